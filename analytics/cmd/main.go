@@ -5,9 +5,11 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/wormhole-foundation/wormhole-explorer/analytics/cmd/dlq"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/cmd/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/cmd/prices"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/cmd/service"
+	common_dlq "github.com/wormhole-foundation/wormhole-explorer/common/dlq"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
@@ -27,6 +29,7 @@ func execute() error {
 
 	addServiceCommand(root)
 	addBackfiller(root)
+	addDlqCommand(root)
 
 	return root.Execute()
 }
@@ -289,3 +292,45 @@ func addVaasPrices(parent *cobra.Command) {
 
 	parent.AddCommand(vaasPricesCmd)
 }
+
+func addDlqCommand(root *cobra.Command) {
+	cfg := &dlq.Settings{}
+	dlqCommand := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspect and redrive the messages of a dead-letter queue",
+	}
+
+	flags := dlqCommand.PersistentFlags()
+	flags.StringVar(&cfg.LogLevel, "log-level", "INFO", "log level")
+	flags.StringVar(&cfg.AwsRegion, "aws-region", "", "AWS region")
+	flags.StringVar(&cfg.AwsEndpoint, "aws-endpoint", "", "AWS endpoint, for local environments")
+	flags.StringVar(&cfg.DlqUrl, "dlq-url", "", "dead-letter queue url")
+	flags.StringVar(&cfg.QueueUrl, "queue-url", "", "url of the queue the messages are replayed to")
+	flags.StringVar(&cfg.Source, "source", "pipeline", "queue the dead-letter queue belongs to: pipeline or notifications")
+	flags.IntVar(&cfg.MaxMessages, "max-messages", 100, "maximum number of messages to receive")
+	flags.Int32Var(&cfg.VisibilityTimeout, "visibility-timeout", 120, "seconds the received messages are hidden from other consumers of the dead-letter queue")
+	flags.Uint16Var(&cfg.ChainID, "chain", 0, "select the messages of a chain id")
+	flags.StringVar(&cfg.Error, "error", "", "select the messages whose error contains the value")
+	flags.StringSliceVar(&cfg.IDs, "id", nil, "select the messages by message id or vaa id")
+	flags.BoolVar(&cfg.DryRun, "dry-run", false, "report the messages without replaying or purging them")
+
+	dlqCommand.MarkPersistentFlagRequired("aws-region")
+	dlqCommand.MarkPersistentFlagRequired("dlq-url")
+
+	actions := map[common_dlq.Action]string{
+		common_dlq.ActionList:   "List the messages grouped by error and chain",
+		common_dlq.ActionReplay: "Send the messages to the queue and delete them from the dead-letter queue",
+		common_dlq.ActionPurge:  "Delete the messages from the dead-letter queue",
+	}
+	for _, action := range []common_dlq.Action{common_dlq.ActionList, common_dlq.ActionReplay, common_dlq.ActionPurge} {
+		action := action
+		dlqCommand.AddCommand(&cobra.Command{
+			Use:   string(action),
+			Short: actions[action],
+			Run: func(_ *cobra.Command, _ []string) {
+				dlq.Run(action, cfg)
+			},
+		})
+	}
+	root.AddCommand(dlqCommand)
+}
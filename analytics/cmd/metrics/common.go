@@ -4,13 +4,13 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
 )
 
 // convertPointToLineProtocol transforms a given data point into the format that InfluxDB uses for dumps.
 //
 // See https://docs.influxdata.com/influxdb/v2.0/reference/syntax/line-protocol/
-func convertPointToLineProtocol(point *write.Point) string {
+func convertPointToLineProtocol(point *timeseries.Point) string {
 
 	// Collect tags
 	var tags string
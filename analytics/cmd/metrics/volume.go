@@ -8,13 +8,13 @@ import (
 	"sync"
 	"time"
 
-	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/shopspring/decimal"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/cmd/token"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/metric"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/prices"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
@@ -42,7 +42,7 @@ func NewVaaConverter(priceCache *prices.CoinPricesCache,
 	}
 }
 
-func (c *VaaConverter) Convert(ctx context.Context, vaaBytes []byte) (*token.TransferredToken, *write.Point, string, error) {
+func (c *VaaConverter) Convert(ctx context.Context, vaaBytes []byte) (*token.TransferredToken, *timeseries.Point, string, error) {
 
 	// Parse the VAA and payload
 	vaa, err := sdk.Unmarshal(vaaBytes)
@@ -75,7 +75,7 @@ func (c *VaaConverter) Convert(ctx context.Context, vaaBytes []byte) (*token.Tra
 	}
 
 	// Generate a data point for the VAA volume metric
-	var point *write.Point
+	var point *timeseries.Point
 	{
 		p := metric.MakePointForVaaVolumeParams{
 			Vaa: vaa,
@@ -25,11 +25,15 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/queue"
 	wormscanNotionalCache "github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
 	sqs_client "github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbutil"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	health "github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
@@ -57,6 +61,19 @@ func Run() {
 
 	// build logger
 	logger := logger.New("wormhole-explorer-analytics", logger.WithLevel(config.LogLevel))
+
+	// set up tracing
+	shutdownTracing, err := telemetry.Setup(rootCtx, telemetry.Config{
+		ServiceName:  "wormhole-explorer-analytics",
+		Environment:  config.Environment,
+		OtlpEndpoint: config.TracingOtlpEndpoint,
+		OtlpInsecure: config.TracingOtlpInsecure,
+		SampleRatio:  config.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+
 	logger.Info("starting analytics service...")
 
 	// setup DB connection
@@ -66,14 +83,21 @@ func Run() {
 		logger.Fatal("failed to connect MongoDB", zap.Error(err))
 	}
 
-	// create influxdb client.
-	logger.Info("initializing InfluxDB client...")
-	influxCli := newInfluxClient(config.InfluxUrl, config.InfluxToken)
-	influxCli.Options().SetBatchSize(100)
+	// create the time-series store.
+	logger.Info("initializing time-series store...", zap.String("backend", config.TimeSeriesBackend))
+	store, storeHealthCheck, err := newTimeSeriesStore(rootCtx, config)
+	if err != nil {
+		logger.Fatal("failed to create time-series store", zap.Error(err))
+	}
+
+	// InfluxDB summarizes the raw measurements with tasks, the other backends are summarized by the service.
+	if s, ok := store.(timeseries.Store); ok && config.TimeSeriesBackend != timeseries.BackendInflux {
+		metric.NewSummarizer(s, repository.NewLockRepository(db.Database), time.Hour, logger).Start(rootCtx)
+	}
 
 	// get health check functions.
 	logger.Info("creating health check functions...")
-	healthChecks, err := newHealthChecks(rootCtx, config, influxCli, db.Database)
+	healthChecks, err := newHealthChecks(rootCtx, config, storeHealthCheck, db.Database)
 	if err != nil {
 		logger.Fatal("failed to create health checks", zap.Error(err))
 	}
@@ -103,21 +127,20 @@ func Run() {
 
 	// create a metrics instance
 	logger.Info("initializing metrics instance...")
-	metric, err := metric.New(rootCtx, db.Database, influxCli, config.InfluxOrganization, config.InfluxBucketInfinite,
-		config.InfluxBucket30Days, config.InfluxBucket24Hours, notionalCache, metrics, tokenResolver.GetTransferredTokenByVaa, tokenProvider, logger)
+	metric, err := metric.New(rootCtx, db.Database, store, notionalCache, metrics, tokenResolver.GetTransferredTokenByVaa, tokenProvider, logger)
 	if err != nil {
 		logger.Fatal("failed to create metrics instance", zap.Error(err))
 	}
 
 	// create and start a vaa consumer.
 	logger.Info("initializing vaa consumer...")
-	vaaConsumeFunc := newVAAConsumeFunc(rootCtx, config, logger)
+	vaaConsumeFunc := newVAAConsumeFunc(rootCtx, config, metrics, logger)
 	vaaConsumer := consumer.New(vaaConsumeFunc, metric.Push, logger, metrics, config.P2pNetwork)
 	vaaConsumer.Start(rootCtx)
 
 	// create and start a notification consumer.
 	logger.Info("initializing notification consumer...")
-	notificationConsumeFunc := newNotificationConsumeFunc(rootCtx, config, logger)
+	notificationConsumeFunc := newNotificationConsumeFunc(rootCtx, config, metrics, logger)
 	notificationConsumer := consumer.New(notificationConsumeFunc, metric.Push, logger, metrics, config.P2pNetwork)
 	notificationConsumer.Start(rootCtx)
 
@@ -152,11 +175,22 @@ func Run() {
 	logger.Info("closing MongoDB connection...")
 	db.DisconnectWithTimeout(10 * time.Second)
 
+	logger.Info("flushing traces...")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", zap.Error(err))
+	}
+	shutdownCancel()
+
 	logger.Info("terminated successfully")
 }
 
 // Creates a callbacks depending on whether the execution is local (memory queue) or not (SQS queue)
-func newVAAConsumeFunc(appCtx context.Context, config *config.Configuration, logger *zap.Logger) queue.ConsumeFunc {
+func newVAAConsumeFunc(appCtx context.Context, config *config.Configuration, metrics metrics.Metrics, logger *zap.Logger) queue.ConsumeFunc {
+	if config.ConsumerQueue == "redis-stream" {
+		return newRedisStreamConsumeFunc(appCtx, config, config.PipelineRedisStream, queue.NewVaaConverter(logger), metrics, logger)
+	}
+
 	sqsConsumer, err := newSQSConsumer(appCtx, config, config.PipelineSQSUrl)
 	if err != nil {
 		logger.Fatal("failed to create sqs consumer", zap.Error(err))
@@ -166,7 +200,10 @@ func newVAAConsumeFunc(appCtx context.Context, config *config.Configuration, log
 	return vaaQueue.Consume
 }
 
-func newNotificationConsumeFunc(ctx context.Context, cfg *config.Configuration, logger *zap.Logger) queue.ConsumeFunc {
+func newNotificationConsumeFunc(ctx context.Context, cfg *config.Configuration, metrics metrics.Metrics, logger *zap.Logger) queue.ConsumeFunc {
+	if cfg.ConsumerQueue == "redis-stream" {
+		return newRedisStreamConsumeFunc(ctx, cfg, cfg.NotificationsRedisStream, queue.NewNotificationEvent(logger), metrics, logger)
+	}
 
 	sqsConsumer, err := newSQSConsumer(ctx, cfg, cfg.NotificationsSQSUrl)
 	if err != nil {
@@ -177,6 +214,21 @@ func newNotificationConsumeFunc(ctx context.Context, cfg *config.Configuration,
 	return vaaQueue.Consume
 }
 
+func newRedisStreamConsumeFunc(ctx context.Context, cfg *config.Configuration, stream string, converter queue.ConverterFunc, metrics metrics.Metrics, logger *zap.Logger) queue.ConsumeFunc {
+	client := redis.NewClient(&redis.Options{Addr: cfg.RedisStreamUri})
+	consumer, err := redisstream.NewConsumer(ctx, client, stream, cfg.RedisStreamGroup,
+		redisstream.WithMaxMessages(10),
+		redisstream.WithVisibilityTimeout(120*time.Second),
+		redisstream.WithMaxDeliveries(cfg.RedisStreamMaxDeliveries),
+		redisstream.WithLogger(logger))
+	if err != nil {
+		logger.Fatal("failed to create redis stream consumer", zap.Error(err))
+	}
+
+	vaaQueue := queue.NewEventRedisStream(consumer, converter, metrics, logger)
+	return vaaQueue.Consume
+}
+
 func newSQSConsumer(appCtx context.Context, config *config.Configuration, sqsUrl string) (*sqs_client.Consumer, error) {
 	awsconfig, err := newAwsConfig(appCtx, config)
 	if err != nil {
@@ -219,13 +271,49 @@ func newInfluxClient(url, token string) influxdb2.Client {
 	return influxdb2.NewClient(url, token)
 }
 
+// newTimeSeriesStore creates the time-series store for the configured backend and its health check.
+func newTimeSeriesStore(ctx context.Context, cfg *config.Configuration) (timeseries.Writer, health.Check, error) {
+	switch cfg.TimeSeriesBackend {
+	case timeseries.BackendInflux:
+		influxCli := newInfluxClient(cfg.InfluxUrl, cfg.InfluxToken)
+		influxCli.Options().SetBatchSize(100)
+		buckets := map[timeseries.Bucket]string{
+			timeseries.BucketInfinite: cfg.InfluxBucketInfinite,
+			timeseries.Bucket30Days:   cfg.InfluxBucket30Days,
+			timeseries.Bucket24Hours:  cfg.InfluxBucket24Hours,
+		}
+		store := timeseries.NewInfluxStore(influxCli, cfg.InfluxOrganization, buckets, timeseries.Bucket24Hours)
+		return store, health.Influx(influxCli), nil
+	case timeseries.BackendClickHouse:
+		store := timeseries.NewClickHouseStore(timeseries.ClickHouseConfig{
+			URL:      cfg.ClickHouseURL,
+			Database: cfg.ClickHouseDatabase,
+			User:     cfg.ClickHouseUser,
+			Password: cfg.ClickHousePassword,
+		})
+		if err := store.EnsureSchema(ctx); err != nil {
+			return nil, nil, fmt.Errorf("failed to create clickhouse schema: %w", err)
+		}
+		return store, store.Ping, nil
+	case timeseries.BackendMemory:
+		return timeseries.NewMemoryStore(), health.Noop(), nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported time-series backend: %s", cfg.TimeSeriesBackend)
+	}
+}
+
 func newHealthChecks(
 	ctx context.Context,
 	config *config.Configuration,
-	influxCli influxdb2.Client,
+	storeHealthCheck health.Check,
 	db *mongo.Database,
 ) ([]health.Check, error) {
 
+	if config.ConsumerQueue == "redis-stream" {
+		client := redis.NewClient(&redis.Options{Addr: config.RedisStreamUri})
+		return []health.Check{health.Redis(client), storeHealthCheck, health.Mongo(db)}, nil
+	}
+
 	awsConfig, err := newAwsConfig(ctx, config)
 	if err != nil {
 		return nil, err
@@ -234,7 +322,7 @@ func newHealthChecks(
 	healthChecks := []health.Check{
 		health.SQS(awsConfig, config.PipelineSQSUrl),
 		health.SQS(awsConfig, config.NotificationsSQSUrl),
-		health.Influx(influxCli),
+		storeHealthCheck,
 		health.Mongo(db),
 	}
 	return healthChecks, nil
@@ -85,7 +85,7 @@ func (r *TokenResolver) GetTransferredTokenByVaa(ctx context.Context, vaa *sdk.V
 	}
 
 	// Parse the VAA with standarized properties
-	result, err := r.client.ParseVaaWithStandarizedProperties(vaa)
+	result, err := r.client.ParseVaaWithStandarizedProperties(ctx, vaa)
 	if err != nil {
 		if errors.Is(err, parser.ErrUnprocessableEntity) {
 			r.logger.Debug("Parsing vaa with standarized properties resulted in unprocessable entity",
@@ -9,31 +9,45 @@ import (
 
 // Configuration represents the application configuration with the default values.
 type Configuration struct {
-	Environment             string `env:"ENVIRONMENT,required"`
-	LogLevel                string `env:"LOG_LEVEL,default=INFO"`
-	Port                    string `env:"PORT,default=8000"`
-	ConsumerMode            string `env:"CONSUMER_MODE,default=QUEUE"`
-	AwsEndpoint             string `env:"AWS_ENDPOINT"`
-	AwsAccessKeyID          string `env:"AWS_ACCESS_KEY_ID"`
-	AwsSecretAccessKey      string `env:"AWS_SECRET_ACCESS_KEY"`
-	AwsRegion               string `env:"AWS_REGION"`
-	PipelineSQSUrl          string `env:"PIPELINE_SQS_URL"`
-	NotificationsSQSUrl     string `env:"NOTIFICATIONS_SQS_URL"`
-	InfluxUrl               string `env:"INFLUX_URL"`
-	InfluxToken             string `env:"INFLUX_TOKEN"`
-	InfluxOrganization      string `env:"INFLUX_ORGANIZATION"`
-	InfluxBucketInfinite    string `env:"INFLUX_BUCKET_INFINITE"`
-	InfluxBucket30Days      string `env:"INFLUX_BUCKET_30_DAYS"`
-	InfluxBucket24Hours     string `env:"INFLUX_BUCKET_24_HOURS"`
-	MongodbURI              string `env:"MONGODB_URI,required"`
-	MongodbDatabase         string `env:"MONGODB_DATABASE,required"`
-	PprofEnabled            bool   `env:"PPROF_ENABLED,default=false"`
-	P2pNetwork              string `env:"P2P_NETWORK,required"`
-	CacheURL                string `env:"CACHE_URL,required"`
-	CachePrefix             string `env:"CACHE_PREFIX,required"`
-	CacheChannel            string `env:"CACHE_CHANNEL,required"`
-	VaaPayloadParserURL     string `env:"VAA_PAYLOAD_PARSER_URL, required"`
-	VaaPayloadParserTimeout int64  `env:"VAA_PAYLOAD_PARSER_TIMEOUT, required"`
+	Environment              string  `env:"ENVIRONMENT,required"`
+	LogLevel                 string  `env:"LOG_LEVEL,default=INFO"`
+	TracingOtlpEndpoint      string  `env:"TRACING_OTLP_ENDPOINT"`
+	TracingOtlpInsecure      bool    `env:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio       float64 `env:"TRACING_SAMPLE_RATIO,default=1"`
+	Port                     string  `env:"PORT,default=8000"`
+	ConsumerMode             string  `env:"CONSUMER_MODE,default=QUEUE"`
+	ConsumerQueue            string  `env:"CONSUMER_QUEUE,default=sqs"`
+	AwsEndpoint              string  `env:"AWS_ENDPOINT"`
+	AwsAccessKeyID           string  `env:"AWS_ACCESS_KEY_ID"`
+	AwsSecretAccessKey       string  `env:"AWS_SECRET_ACCESS_KEY"`
+	AwsRegion                string  `env:"AWS_REGION"`
+	PipelineSQSUrl           string  `env:"PIPELINE_SQS_URL"`
+	NotificationsSQSUrl      string  `env:"NOTIFICATIONS_SQS_URL"`
+	RedisStreamUri           string  `env:"REDIS_STREAM_URI"`
+	PipelineRedisStream      string  `env:"PIPELINE_REDIS_STREAM"`
+	NotificationsRedisStream string  `env:"NOTIFICATIONS_REDIS_STREAM"`
+	RedisStreamGroup         string  `env:"REDIS_STREAM_GROUP,default=analytics"`
+	RedisStreamMaxDeliveries int64   `env:"REDIS_STREAM_MAX_DELIVERIES,default=10"`
+	InfluxUrl                string  `env:"INFLUX_URL"`
+	InfluxToken              string  `env:"INFLUX_TOKEN"`
+	InfluxOrganization       string  `env:"INFLUX_ORGANIZATION"`
+	InfluxBucketInfinite     string  `env:"INFLUX_BUCKET_INFINITE"`
+	InfluxBucket30Days       string  `env:"INFLUX_BUCKET_30_DAYS"`
+	InfluxBucket24Hours      string  `env:"INFLUX_BUCKET_24_HOURS"`
+	TimeSeriesBackend        string  `env:"TIMESERIES_BACKEND,default=influx"`
+	ClickHouseURL            string  `env:"CLICKHOUSE_URL"`
+	ClickHouseDatabase       string  `env:"CLICKHOUSE_DATABASE"`
+	ClickHouseUser           string  `env:"CLICKHOUSE_USER"`
+	ClickHousePassword       string  `env:"CLICKHOUSE_PASSWORD"`
+	MongodbURI               string  `env:"MONGODB_URI,required"`
+	MongodbDatabase          string  `env:"MONGODB_DATABASE,required"`
+	PprofEnabled             bool    `env:"PPROF_ENABLED,default=false"`
+	P2pNetwork               string  `env:"P2P_NETWORK,required"`
+	CacheURL                 string  `env:"CACHE_URL,required"`
+	CachePrefix              string  `env:"CACHE_PREFIX,required"`
+	CacheChannel             string  `env:"CACHE_CHANNEL,required"`
+	VaaPayloadParserURL      string  `env:"VAA_PAYLOAD_PARSER_URL, required"`
+	VaaPayloadParserTimeout  int64   `env:"VAA_PAYLOAD_PARSER_TIMEOUT, required"`
 }
 
 // New creates a configuration with the values from .env file and environment variables.
@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"context"
+	"errors"
 
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/metric"
@@ -10,6 +11,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// errEventExpired is the error of the events whose visibility timeout expired before being processed.
+var errEventExpired = errors.New("event expired")
+
 // Consumer consumer struct definition.
 type Consumer struct {
 	consume    queue.ConsumeFunc
@@ -34,7 +38,7 @@ func (c *Consumer) Start(ctx context.Context) {
 
 			// check id message is expired.
 			if msg.IsExpired() {
-				msg.Failed()
+				msg.Failed(errEventExpired)
 				c.logger.Warn("Message with vaa expired", zap.String("id", event.ID))
 				c.metrics.IncExpiredMessage(chainID, event.Source, msg.Retry())
 				continue
@@ -50,9 +54,9 @@ func (c *Consumer) Start(ctx context.Context) {
 			}
 
 			// push vaa metrics.
-			err = c.pushMetric(ctx, &metric.Params{TrackID: event.TrackID, Vaa: vaa, VaaIsSigned: event.VaaIsSigned})
+			err = c.pushMetric(msg.Context(), &metric.Params{TrackID: event.TrackID, Vaa: vaa, VaaIsSigned: event.VaaIsSigned})
 			if err != nil {
-				msg.Failed()
+				msg.Failed(err)
 				c.metrics.IncUnprocessedMessage(chainID, event.Source, msg.Retry())
 				continue
 			}
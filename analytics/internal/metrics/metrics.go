@@ -16,4 +16,5 @@ type Metrics interface {
 	IncUnprocessedMessage(chain, source string, retry uint8)
 	IncProcessedMessage(chain, source string, retry uint8)
 	VaaProcessingDuration(chain string, start *time.Time)
+	SetStreamLag(stream string, lag time.Duration, deadLetters int64)
 }
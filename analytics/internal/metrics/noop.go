@@ -43,3 +43,6 @@ func (p *NoopMetrics) IncProcessedMessage(chain, source string, retry uint8) {
 
 func (m *NoopMetrics) VaaProcessingDuration(chain string, start *time.Time) {
 }
+
+func (m *NoopMetrics) SetStreamLag(stream string, lag time.Duration, deadLetters int64) {
+}
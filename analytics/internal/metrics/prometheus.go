@@ -14,6 +14,8 @@ type PrometheusMetrics struct {
 	tokenRequestsCount    *prometheus.CounterVec
 	processedMessage      *prometheus.CounterVec
 	vaaProcessingDuration *prometheus.HistogramVec
+	streamLag             *prometheus.GaugeVec
+	streamDeadLetters     *prometheus.GaugeVec
 }
 
 // NewPrometheusMetrics returns a new instance of PrometheusMetrics.
@@ -66,12 +68,30 @@ func NewPrometheusMetrics(environment string) *PrometheusMetrics {
 		},
 		[]string{"chain"},
 	)
+	streamLag := promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "redis_stream_lag_seconds",
+			Help:        "Age of the oldest message of the redis stream not acknowledged by the consumer group",
+			ConstLabels: constLabels,
+		},
+		[]string{"stream"},
+	)
+	streamDeadLetters := promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "redis_stream_dead_letters",
+			Help:        "Number of messages of the dead letter stream of the redis stream",
+			ConstLabels: constLabels,
+		},
+		[]string{"stream"},
+	)
 	return &PrometheusMetrics{
 		measurementCount:      measurementCount,
 		notionalCount:         notionalRequestsCount,
 		tokenRequestsCount:    tokenRequestsCount,
 		processedMessage:      processedMessage,
 		vaaProcessingDuration: vaaProcessingDuration,
+		streamLag:             streamLag,
+		streamDeadLetters:     streamDeadLetters,
 	}
 }
 
@@ -122,3 +142,8 @@ func (p *PrometheusMetrics) VaaProcessingDuration(chain string, start *time.Time
 	elapsed := float64(time.Since(*start).Nanoseconds()) / 1e9
 	p.vaaProcessingDuration.WithLabelValues(chain).Observe(elapsed)
 }
+
+func (m *PrometheusMetrics) SetStreamLag(stream string, lag time.Duration, deadLetters int64) {
+	m.streamLag.WithLabelValues(stream).Set(lag.Seconds())
+	m.streamDeadLetters.WithLabelValues(stream).Set(float64(deadLetters))
+}
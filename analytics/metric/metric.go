@@ -7,14 +7,12 @@ import (
 	"strconv"
 	"time"
 
-	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
-	"github.com/influxdata/influxdb-client-go/v2/api"
-	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/shopspring/decimal"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/cmd/token"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/internal/metrics"
 	wormscanNotionalCache "github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
@@ -31,10 +29,7 @@ type Metric struct {
 	db *mongo.Database
 	// transferPrices contains the notional price for each token bridge transfer.
 	transferPrices           *mongo.Collection
-	influxCli                influxdb2.Client
-	apiBucketInfinite        api.WriteAPIBlocking
-	apiBucket30Days          api.WriteAPIBlocking
-	apiBucket24Hours         api.WriteAPIBlocking
+	store                    timeseries.Writer
 	notionalCache            wormscanNotionalCache.NotionalLocalCacheReadable
 	metrics                  metrics.Metrics
 	getTransferredTokenByVaa token.GetTransferredTokenByVaa
@@ -46,11 +41,7 @@ type Metric struct {
 func New(
 	ctx context.Context,
 	db *mongo.Database,
-	influxCli influxdb2.Client,
-	organization string,
-	bucketInifite string,
-	bucket30Days string,
-	bucket24Hours string,
+	store timeseries.Writer,
 	notionalCache wormscanNotionalCache.NotionalLocalCacheReadable,
 	metrics metrics.Metrics,
 	getTransferredTokenByVaa token.GetTransferredTokenByVaa,
@@ -58,18 +49,10 @@ func New(
 	logger *zap.Logger,
 ) (*Metric, error) {
 
-	apiBucketInfinite := influxCli.WriteAPIBlocking(organization, bucketInifite)
-	apiBucket30Days := influxCli.WriteAPIBlocking(organization, bucket30Days)
-	apiBucket24Hours := influxCli.WriteAPIBlocking(organization, bucket24Hours)
-	apiBucket24Hours.EnableBatching()
-
 	m := Metric{
 		db:                       db,
 		transferPrices:           db.Collection("transferPrices"),
-		influxCli:                influxCli,
-		apiBucketInfinite:        apiBucketInfinite,
-		apiBucket24Hours:         apiBucket24Hours,
-		apiBucket30Days:          apiBucket30Days,
+		store:                    store,
 		logger:                   logger,
 		notionalCache:            notionalCache,
 		metrics:                  metrics,
@@ -152,19 +135,17 @@ func (m *Metric) Push(ctx context.Context, params *Params) error {
 	return nil
 }
 
-// Close influx client.
+// Close the time-series store.
 func (m *Metric) Close() {
 
 	const flushTimeout = 5 * time.Second
 
 	// wait a bounded amount of time for all buckets to flush
 	ctx, cancelFunc := context.WithTimeout(context.Background(), flushTimeout)
-	m.apiBucket24Hours.Flush(ctx)
-	m.apiBucket30Days.Flush(ctx)
-	m.apiBucketInfinite.Flush(ctx)
+	m.store.Flush(ctx)
 	cancelFunc()
 
-	m.influxCli.Close()
+	m.store.Close()
 }
 
 // vaaCountMeasurement creates a new point for the `vaa_count` measurement.
@@ -186,8 +167,8 @@ func (m *Metric) vaaCountMeasurement(ctx context.Context, p *Params) error {
 		return nil
 	}
 
-	// Write the point to influx
-	err = m.apiBucket30Days.WritePoint(ctx, point)
+	// Write the point to the time-series store
+	err = m.store.WritePoints(ctx, timeseries.Bucket30Days, point)
 	if err != nil {
 		m.logger.Error("Failed to write metric",
 			zap.String("measurement", point.Name()),
@@ -206,8 +187,8 @@ func (m *Metric) vaaCountMeasurement(ctx context.Context, p *Params) error {
 func (m *Metric) vaaCountAllMessagesMeasurement(ctx context.Context, params *Params) error {
 
 	// Quite often we get VAAs that are older than 24 hours.
-	// We do not want to generate metrics for those, and moreover influxDB
-	// returns an error when we try to do so.
+	// We do not want to generate metrics for those, and moreover some backends
+	// (e.g.: influxDB) return an error when we try to do so.
 	if time.Since(params.Vaa.Timestamp) > time.Hour*24 {
 		m.logger.Debug("vaa is older than 24 hours, skipping",
 			zap.String("trackId", params.TrackID),
@@ -218,14 +199,14 @@ func (m *Metric) vaaCountAllMessagesMeasurement(ctx context.Context, params *Par
 	}
 
 	// Create a new point
-	point := influxdb2.
-		NewPointWithMeasurement(VaaAllMessagesMeasurement).
+	point := timeseries.
+		NewPoint(VaaAllMessagesMeasurement).
 		AddTag("chain_id", strconv.Itoa(int(params.Vaa.EmitterChain))).
 		AddField("count", 1).
 		SetTime(generateUniqueTimestamp(params.Vaa))
 
-	// Write the point to influx
-	err := m.apiBucket24Hours.WritePoint(ctx, point)
+	// Write the point to the time-series store
+	err := m.store.WritePoints(ctx, timeseries.Bucket24Hours, point)
 	if err != nil {
 		m.logger.Error("Failed to write metric",
 			zap.String("measurement", VaaAllMessagesMeasurement),
@@ -271,8 +252,8 @@ func (m *Metric) volumeMeasurement(ctx context.Context, params *Params, token *t
 
 	vaaVolumeV3point := m.MakePointVaaVolumeV3(point, params, token)
 
-	// Write the point to influx
-	err = m.apiBucketInfinite.WritePoint(ctx, point, vaaVolumeV3point)
+	// Write the points to the time-series store
+	err = m.store.WritePoints(ctx, timeseries.BucketInfinite, point, vaaVolumeV3point)
 	if err != nil {
 		m.metrics.IncFailedMeasurement(VaaVolumeMeasurement)
 		return err
@@ -289,9 +270,9 @@ func (m *Metric) volumeMeasurement(ctx context.Context, params *Params, token *t
 	return nil
 }
 
-func (m *Metric) MakePointVaaVolumeV3(vaaVolumeV2Point *write.Point, params *Params, transferredToken *token.TransferredToken) *write.Point {
+func (m *Metric) MakePointVaaVolumeV3(vaaVolumeV2Point *timeseries.Point, params *Params, transferredToken *token.TransferredToken) *timeseries.Point {
 
-	point := influxdb2.NewPointWithMeasurement("vaa_volume_v3")
+	point := timeseries.NewPoint("vaa_volume_v3")
 
 	point.SetTime(vaaVolumeV2Point.Time())
 
@@ -356,7 +337,7 @@ func (m *Metric) MakePointVaaVolumeV3(vaaVolumeV2Point *write.Point, params *Par
 //
 // Some VAAs will not generate a measurement, so the caller must always check
 // whether the returned point is nil.
-func MakePointForVaaCount(vaa *sdk.VAA) (*write.Point, error) {
+func MakePointForVaaCount(vaa *sdk.VAA) (*timeseries.Point, error) {
 
 	// Do not generate this metric for PythNet VAAs
 	if vaa.EmitterChain == sdk.ChainIDPythNet {
@@ -364,8 +345,8 @@ func MakePointForVaaCount(vaa *sdk.VAA) (*write.Point, error) {
 	}
 
 	// Create a new point
-	point := influxdb2.
-		NewPointWithMeasurement(VaaCountMeasurement).
+	point := timeseries.
+		NewPoint(VaaCountMeasurement).
 		AddTag("chain_id", strconv.Itoa(int(vaa.EmitterChain))).
 		AddField("count", 1).
 		SetTime(generateUniqueTimestamp(vaa))
@@ -395,11 +376,11 @@ type MakePointForVaaVolumeParams struct {
 	TokenProvider *domain.TokenProvider
 }
 
-// MakePointForVaaVolume builds the InfluxDB volume metric for a given VAA
+// MakePointForVaaVolume builds the volume metric for a given VAA
 //
 // Some VAAs will not generate a measurement, so the caller must always check
 // whether the returned point is nil.
-func MakePointForVaaVolume(params *MakePointForVaaVolumeParams) (*write.Point, error) {
+func MakePointForVaaVolume(params *MakePointForVaaVolumeParams) (*timeseries.Point, error) {
 
 	// Do not generate this metric for PythNet VAAs
 	if params.Vaa.EmitterChain == sdk.ChainIDPythNet {
@@ -428,7 +409,7 @@ func MakePointForVaaVolume(params *MakePointForVaaVolumeParams) (*write.Point, e
 	}
 
 	// Create a data point
-	point := influxdb2.NewPointWithMeasurement(VaaVolumeMeasurement).
+	point := timeseries.NewPoint(VaaVolumeMeasurement).
 		// This is always set to the portal token bridge app ID, but we may have other apps in the future
 		AddTag("app_id", params.TransferredToken.AppId).
 		AddTag("emitter_chain", fmt.Sprintf("%d", params.Vaa.EmitterChain)).
@@ -0,0 +1,188 @@
+package metric
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	"go.uber.org/zap"
+)
+
+var corridorTags = []string{"emitter_chain", "destination_chain", "token_chain", "token_address"}
+
+// summarizerLock is the name of the lock that elects the replica running the summarizer.
+const summarizerLock = "analytics-summarizer"
+
+// locker elects the replica that runs the summarizer.
+type locker interface {
+	AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+}
+
+// Summarizer writes the summarized measurements computed on InfluxDB by the tasks of analytics/scripts
+// (total_tx_all_time_24h.flux and top_100_corridors_3h.flux), so that the API reads the same
+// measurements from the backends without tasks instead of scanning the raw points on every request.
+//
+// Only the replica holding the lock of the summarizer runs it, and every run resumes from the last
+// summarized points stored in the backend: the totals add the transfers since the last totals to them,
+// and the top corridors are only written once per hour.
+type Summarizer struct {
+	store    timeseries.Store
+	lock     locker
+	owner    string
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// NewSummarizer creates a new *Summarizer that summarizes the raw measurements every interval.
+func NewSummarizer(store timeseries.Store, lock locker, interval time.Duration, logger *zap.Logger) *Summarizer {
+	// the hostname is the pod name, unique across the replicas.
+	owner, err := os.Hostname()
+	if err != nil {
+		owner = fmt.Sprintf("analytics-%d", time.Now().UnixNano())
+	}
+	return &Summarizer{store: store, lock: lock, owner: owner, interval: interval, logger: logger}
+}
+
+// Start summarizes the raw measurements until the context is cancelled.
+func (s *Summarizer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.summarize(ctx, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *Summarizer) summarize(ctx context.Context, now time.Time) {
+	// the lock outlives a few ticks, so a run that takes longer than the interval keeps it.
+	leader, err := s.lock.AcquireLock(ctx, summarizerLock, s.owner, 3*s.interval)
+	if err != nil {
+		s.logger.Error("failed to acquire summarizer lock", zap.Error(err))
+		return
+	}
+	if !leader {
+		s.logger.Debug("summarizer lock held by another replica")
+		return
+	}
+
+	if err := s.writeTotals(ctx, now.Truncate(24*time.Hour)); err != nil {
+		s.logger.Error("failed to summarize total transactions", zap.Error(err))
+	}
+	for measurement, days := range map[string]int{
+		"top_100_corridors_7_days_3h_v2": 7,
+		"top_100_corridors_2_days_3h_v2": 2,
+	} {
+		if err := s.writeTopCorridors(ctx, now.Truncate(time.Hour), measurement, days); err != nil {
+			s.logger.Error("failed to summarize top corridors", zap.String("measurement", measurement), zap.Error(err))
+		}
+	}
+}
+
+// writeTotals writes the number and the volume of the transfers until stop, computed once per day.
+// The transfers since the last totals are added to them, so the whole history is only read when
+// there are no totals in the retention of the bucket.
+func (s *Summarizer) writeTotals(ctx context.Context, stop time.Time) error {
+	var points []*timeseries.Point
+	for measurement, aggregate := range map[string]timeseries.Aggregate{
+		"total_tx_count_v2":  timeseries.AggregateCount,
+		"total_tx_volume_v2": timeseries.AggregateSum,
+	} {
+		last, err := s.last(ctx, timeseries.Bucket30Days, measurement, "value", stop.AddDate(0, -1, 0), 24*time.Hour)
+		if err != nil {
+			return err
+		}
+		start := time.Unix(0, 0)
+		var value float64
+		if last != nil {
+			if !last.Time.Before(stop) {
+				continue
+			}
+			start, value = last.Time, last.Value
+		}
+
+		rows, err := s.store.Query(ctx, &timeseries.Query{
+			Bucket:      timeseries.BucketInfinite,
+			Measurement: VaaVolumeMeasurement,
+			Field:       "volume",
+			Start:       start,
+			Stop:        stop,
+			Aggregate:   aggregate,
+		})
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			value += rows[0].Value
+		}
+		points = append(points, timeseries.NewPoint(measurement).AddField("value", value).SetTime(stop))
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	return s.store.WritePoints(ctx, timeseries.Bucket30Days, points...)
+}
+
+// last returns the last value of a summarized measurement written since start, with the time of the
+// interval that contains it, or nil if there is none. The summarized points are written at the start
+// of their interval, so the time is the time of the point.
+func (s *Summarizer) last(ctx context.Context, bucket timeseries.Bucket, measurement, field string, start time.Time,
+	interval time.Duration) (*timeseries.Row, error) {
+	rows, err := s.store.Query(ctx, &timeseries.Query{
+		Bucket:      bucket,
+		Measurement: measurement,
+		Field:       field,
+		Start:       start,
+		Aggregate:   timeseries.AggregateLast,
+		Window:      interval,
+	})
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &rows[len(rows)-1], nil
+}
+
+// writeTopCorridors writes the 100 corridors with the highest number of transfers in the last days,
+// unless they were already written for the execution.
+func (s *Summarizer) writeTopCorridors(ctx context.Context, execution time.Time, measurement string, days int) error {
+	last, err := s.last(ctx, timeseries.Bucket24Hours, measurement, "count", execution.Add(-24*time.Hour), time.Hour)
+	if err != nil {
+		return err
+	}
+	if last != nil && !last.Time.Before(execution) {
+		return nil
+	}
+
+	rows, err := s.store.Query(ctx, &timeseries.Query{
+		Bucket:      timeseries.BucketInfinite,
+		Measurement: VaaVolumeMeasurement,
+		Field:       "volume",
+		Start:       execution.AddDate(0, 0, -days),
+		GroupBy:     corridorTags,
+		Aggregate:   timeseries.AggregateCount,
+		Limit:       100,
+	})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	points := make([]*timeseries.Point, 0, len(rows))
+	for _, row := range rows {
+		point := timeseries.NewPoint(measurement).AddField("count", uint64(row.Value)).SetTime(execution)
+		for _, tag := range corridorTags {
+			point.AddTag(tag, row.Tags[tag])
+		}
+		points = append(points, point)
+	}
+	return s.store.WritePoints(ctx, timeseries.Bucket24Hours, points...)
+}
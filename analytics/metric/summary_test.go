@@ -0,0 +1,123 @@
+package metric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	"go.uber.org/zap"
+)
+
+// fakeLock grants the lock of the summarizer when leader is true.
+type fakeLock struct {
+	leader bool
+	names  []string
+}
+
+func (l *fakeLock) AcquireLock(_ context.Context, name, _ string, _ time.Duration) (bool, error) {
+	l.names = append(l.names, name)
+	return l.leader, nil
+}
+
+func newVolumePoint(t time.Time, volume uint64, emitterChain string) *timeseries.Point {
+	return timeseries.NewPoint(VaaVolumeMeasurement).
+		AddTag("emitter_chain", emitterChain).
+		AddTag("destination_chain", "2").
+		AddTag("token_chain", "1").
+		AddTag("token_address", "0x1").
+		AddField("volume", volume).
+		SetTime(t)
+}
+
+// totals returns the values of a total measurement by time.
+func totals(store *timeseries.MemoryStore, measurement string) map[time.Time]float64 {
+	result := make(map[time.Time]float64)
+	for _, p := range store.Points(timeseries.Bucket30Days) {
+		if p.Name() != measurement {
+			continue
+		}
+		value, _ := p.Field("value")
+		result[p.Time()] = value.(float64)
+	}
+	return result
+}
+
+func TestSummarizer_Totals(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	store := timeseries.NewMemoryStore()
+	require.NoError(t, store.WritePoints(ctx, timeseries.BucketInfinite,
+		newVolumePoint(day.Add(-48*time.Hour), 10, "1"),
+		newVolumePoint(day.Add(-time.Hour), 5, "1"),
+	))
+	lock := &fakeLock{leader: true}
+	s := NewSummarizer(store, lock, time.Hour, zap.NewNop())
+
+	s.summarize(ctx, day.Add(3*time.Hour))
+	assert.Equal(t, map[time.Time]float64{day: 2}, totals(store, "total_tx_count_v2"))
+	assert.Equal(t, map[time.Time]float64{day: 15}, totals(store, "total_tx_volume_v2"))
+	assert.Equal(t, []string{summarizerLock}, lock.names)
+
+	// the totals of the day are not written again.
+	s.summarize(ctx, day.Add(4*time.Hour))
+	assert.Len(t, totals(store, "total_tx_count_v2"), 1)
+
+	// the totals of the next day add the transfers of the day to the last totals, so the transfers
+	// before them are not read again.
+	require.NoError(t, store.WritePoints(ctx, timeseries.BucketInfinite,
+		newVolumePoint(day.Add(-72*time.Hour), 100, "1"),
+		newVolumePoint(day.Add(time.Hour), 7, "1"),
+	))
+	next := day.Add(24 * time.Hour)
+	s.summarize(ctx, next.Add(time.Hour))
+	assert.Equal(t, map[time.Time]float64{day: 2, next: 3}, totals(store, "total_tx_count_v2"))
+	assert.Equal(t, map[time.Time]float64{day: 15, next: 22}, totals(store, "total_tx_volume_v2"))
+}
+
+func TestSummarizer_NotLeader(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 10, 3, 0, 0, 0, time.UTC)
+	store := timeseries.NewMemoryStore()
+	require.NoError(t, store.WritePoints(ctx, timeseries.BucketInfinite, newVolumePoint(now.Add(-48*time.Hour), 10, "1")))
+
+	NewSummarizer(store, &fakeLock{}, time.Hour, zap.NewNop()).summarize(ctx, now)
+	assert.Empty(t, store.Points(timeseries.Bucket30Days))
+	assert.Empty(t, store.Points(timeseries.Bucket24Hours))
+}
+
+func TestSummarizer_TopCorridors(t *testing.T) {
+	ctx := context.Background()
+	execution := time.Date(2024, 5, 10, 3, 0, 0, 0, time.UTC)
+	store := timeseries.NewMemoryStore()
+	require.NoError(t, store.WritePoints(ctx, timeseries.BucketInfinite,
+		newVolumePoint(execution.Add(-time.Hour), 10, "1"),
+		newVolumePoint(execution.Add(-2*time.Hour), 10, "1"),
+		newVolumePoint(execution.Add(-72*time.Hour), 10, "5"),
+	))
+	s := NewSummarizer(store, &fakeLock{leader: true}, time.Hour, zap.NewNop())
+
+	corridors := func(measurement string) map[string]uint64 {
+		result := make(map[string]uint64)
+		for _, p := range store.Points(timeseries.Bucket24Hours) {
+			if p.Name() != measurement || !p.Time().Equal(execution) {
+				continue
+			}
+			chain, _ := p.Tag("emitter_chain")
+			count, _ := p.Field("count")
+			result[chain] = count.(uint64)
+		}
+		return result
+	}
+
+	s.summarize(ctx, execution.Add(10*time.Minute))
+	assert.Equal(t, map[string]uint64{"1": 2}, corridors("top_100_corridors_2_days_3h_v2"))
+	assert.Equal(t, map[string]uint64{"1": 2, "5": 1}, corridors("top_100_corridors_7_days_3h_v2"))
+
+	// the corridors of an execution are only written once.
+	written := len(store.Points(timeseries.Bucket24Hours))
+	s.summarize(ctx, execution.Add(20*time.Minute))
+	assert.Len(t, store.Points(timeseries.Bucket24Hours), written)
+}
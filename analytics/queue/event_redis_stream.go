@@ -0,0 +1,196 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-foundation/wormhole-explorer/analytics/internal/metrics"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// lagInterval is the interval to update the lag metric of a stream.
+const lagInterval = 30 * time.Second
+
+// RedisStream represents a VAA queue in a redis stream.
+type RedisStream struct {
+	consumer  *redisstream.Consumer
+	ch        chan ConsumerMessage
+	converter ConverterFunc
+	wg        sync.WaitGroup
+	metrics   metrics.Metrics
+	logger    *zap.Logger
+}
+
+// NewEventRedisStream creates a VAA queue in a redis stream instances.
+func NewEventRedisStream(consumer *redisstream.Consumer, converter ConverterFunc, metrics metrics.Metrics, logger *zap.Logger) *RedisStream {
+	return &RedisStream{
+		consumer:  consumer,
+		ch:        make(chan ConsumerMessage, 10),
+		converter: converter,
+		metrics:   metrics,
+		logger:    logger.With(zap.String("stream", consumer.GetStream()), zap.String("group", consumer.GetGroup())),
+	}
+}
+
+// Consume returns the channel with the received messages from the redis stream.
+func (q *RedisStream) Consume(ctx context.Context) <-chan ConsumerMessage {
+	go q.reportLag(ctx)
+	go func() {
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			messages, err := q.consumer.GetMessages(ctx)
+			if err != nil {
+				q.logger.Error("Error getting messages from redis stream", zap.Error(err))
+				time.Sleep(time.Second)
+				continue
+			}
+			q.logger.Debug("Received messages from redis stream", zap.Int("count", len(messages)))
+			expiredAt := time.Now().Add(q.consumer.GetVisibilityTimeout())
+			for _, msg := range messages {
+				// converts message to event
+				event, err := q.converter(msg.Body)
+				if err != nil {
+					q.logger.Error("Error converting event message", zap.Error(err), zap.String("body", msg.Body))
+					if err = q.consumer.DeleteMessage(ctx, msg.ID); err != nil {
+						q.logger.Error("Error deleting message from redis stream", zap.Error(err))
+					}
+					continue
+				}
+
+				if event == nil {
+					q.logger.Warn("Can not handle message", zap.String("body", msg.Body))
+					if err = q.consumer.DeleteMessage(ctx, msg.ID); err != nil {
+						q.logger.Error("Error deleting message from redis stream", zap.Error(err))
+					}
+					continue
+				}
+
+				// continue the trace of the producer of the message.
+				msgCtx, span := tracer.Start(telemetry.ContextWithTraceContext(ctx, msg.TraceContext), "analytics.vaa.consume",
+					trace.WithSpanKind(trace.SpanKindConsumer),
+					trace.WithAttributes(telemetry.TrackIDKey.String(event.TrackID), telemetry.VaaIDKey.String(event.ID)))
+
+				sentTimestamp := redisstream.GetSentTimestamp(msg.ID)
+				q.wg.Add(1)
+				q.ch <- &redisStreamConsumerMessage{
+					id:            msg.ID,
+					data:          event,
+					wg:            &q.wg,
+					logger:        q.logger,
+					consumer:      q.consumer,
+					retry:         msg.Retry,
+					expiredAt:     expiredAt,
+					sentTimestamp: &sentTimestamp,
+					ctx:           msgCtx,
+					span:          span,
+				}
+			}
+			q.wg.Wait()
+		}
+	}()
+	return q.ch
+}
+
+// reportLag updates the lag metric of the stream periodically.
+func (q *RedisStream) reportLag(ctx context.Context) {
+	ticker := time.NewTicker(lagInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.updateLag(ctx)
+		}
+	}
+}
+
+// updateLag sets the lag and the dead letters of the stream in the metrics.
+func (q *RedisStream) updateLag(ctx context.Context) {
+	lag, err := q.consumer.Lag(ctx)
+	if err != nil {
+		q.logger.Error("Error getting redis stream lag", zap.Error(err))
+		return
+	}
+	deadLetters, err := q.consumer.DeadLetters(ctx)
+	if err != nil {
+		q.logger.Error("Error getting redis stream dead letters", zap.Error(err))
+		return
+	}
+	q.metrics.SetStreamLag(q.consumer.GetStream(), lag, deadLetters)
+}
+
+// Close closes all consumer resources.
+func (q *RedisStream) Close() {
+	close(q.ch)
+}
+
+type redisStreamConsumerMessage struct {
+	data          *Event
+	consumer      *redisstream.Consumer
+	wg            *sync.WaitGroup
+	id            string
+	logger        *zap.Logger
+	retry         uint8
+	expiredAt     time.Time
+	sentTimestamp *time.Time
+	ctx           context.Context
+	span          trace.Span
+}
+
+func (m *redisStreamConsumerMessage) Data() *Event {
+	return m.data
+}
+
+func (m *redisStreamConsumerMessage) Done() {
+	if err := m.consumer.DeleteMessage(m.ctx, m.id); err != nil {
+		m.logger.Error("Error deleting message from redis stream", zap.Error(err))
+	}
+	m.span.End()
+	m.wg.Done()
+}
+
+// Failed leaves the message pending, so it is reclaimed once the visibility timeout expires,
+// or moves it to the dead letter stream once it has been delivered max deliveries times.
+func (m *redisStreamConsumerMessage) Failed(err error) {
+	var lastErr string
+	if err != nil {
+		lastErr = err.Error()
+	}
+	moved, errMove := m.consumer.Failed(m.ctx, m.id, m.retry, lastErr)
+	if errMove != nil {
+		m.logger.Error("Error moving message to the dead letter stream", zap.String("vaaId", m.data.ID), zap.Error(errMove))
+	} else if moved {
+		m.logger.Warn("Message moved to the dead letter stream",
+			zap.String("vaaId", m.data.ID),
+			zap.String("deadLetterStream", m.consumer.GetDeadLetterStream()),
+			zap.Uint8("retry", m.retry))
+	}
+	m.span.SetStatus(codes.Error, "message processing failed")
+	m.span.End()
+	m.wg.Done()
+}
+
+func (m *redisStreamConsumerMessage) IsExpired() bool {
+	return m.expiredAt.Before(time.Now())
+}
+
+func (m *redisStreamConsumerMessage) Retry() uint8 {
+	return m.retry
+}
+
+func (m *redisStreamConsumerMessage) SentTimestamp() *time.Time {
+	return m.sentTimestamp
+}
+
+func (m *redisStreamConsumerMessage) Context() context.Context {
+	return m.ctx
+}
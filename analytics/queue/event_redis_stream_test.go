@@ -0,0 +1,179 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/analytics/internal/metrics"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
+	"go.uber.org/zap"
+)
+
+const (
+	testStream = "vaas"
+	testGroup  = "analytics"
+)
+
+// lagMetrics records the lag of the stream set in the metrics.
+type lagMetrics struct {
+	*metrics.NoopMetrics
+	stream      string
+	lag         time.Duration
+	deadLetters int64
+}
+
+func (m *lagMetrics) SetStreamLag(stream string, lag time.Duration, deadLetters int64) {
+	m.stream, m.lag, m.deadLetters = stream, lag, deadLetters
+}
+
+type redisStreamTest struct {
+	redis    *miniredis.Miniredis
+	client   *redis.Client
+	consumer *redisstream.Consumer
+	producer *redisstream.Producer
+	metrics  *lagMetrics
+	queue    *RedisStream
+}
+
+func newRedisStreamTest(t *testing.T, maxDeliveries int64) *redisStreamTest {
+	m := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	consumer, err := redisstream.NewConsumer(context.Background(), client, testStream, testGroup,
+		redisstream.WithVisibilityTimeout(time.Minute),
+		redisstream.WithWaitTime(10*time.Millisecond),
+		redisstream.WithMaxDeliveries(maxDeliveries))
+	require.NoError(t, err)
+
+	// the body of the messages is the id of the vaa, except for the invalid and unknown messages.
+	converter := func(body string) (*Event, error) {
+		switch body {
+		case "invalid":
+			return nil, errors.New("invalid message")
+		case "unknown":
+			return nil, nil
+		}
+		return &Event{ID: body, ChainID: 2}, nil
+	}
+
+	lag := &lagMetrics{NoopMetrics: metrics.NewNoopMetrics()}
+	return &redisStreamTest{
+		redis:    m,
+		client:   client,
+		consumer: consumer,
+		producer: redisstream.NewProducer(client, testStream, 0),
+		metrics:  lag,
+		queue:    NewEventRedisStream(consumer, converter, lag, zap.NewNop()),
+	}
+}
+
+func (s *redisStreamTest) pending(t *testing.T) int64 {
+	pending, err := s.client.XPending(context.Background(), testStream, testGroup).Result()
+	require.NoError(t, err)
+	return pending.Count
+}
+
+func receive(t *testing.T, ch <-chan ConsumerMessage) ConsumerMessage {
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message received from the redis stream")
+		return nil
+	}
+}
+
+func TestRedisStream_Done(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newRedisStreamTest(t, 10)
+	ch := s.queue.Consume(ctx)
+
+	require.NoError(t, s.producer.SendMessage(ctx, "2/emitter/1"))
+	msg := receive(t, ch)
+	assert.Equal(t, "2/emitter/1", msg.Data().ID)
+	assert.False(t, msg.IsExpired())
+	assert.NotNil(t, msg.SentTimestamp())
+	assert.Equal(t, int64(1), s.pending(t))
+
+	msg.Done()
+	assert.Equal(t, int64(0), s.pending(t))
+}
+
+func TestRedisStream_SkippedMessages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newRedisStreamTest(t, 10)
+	ch := s.queue.Consume(ctx)
+
+	// the messages that can not be converted are acknowledged without being delivered.
+	for _, body := range []string{"invalid", "unknown", "2/emitter/1"} {
+		require.NoError(t, s.producer.SendMessage(ctx, body))
+	}
+	msg := receive(t, ch)
+	assert.Equal(t, "2/emitter/1", msg.Data().ID)
+	assert.Equal(t, int64(1), s.pending(t))
+	msg.Done()
+	assert.Equal(t, int64(0), s.pending(t))
+}
+
+func TestRedisStream_Failed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newRedisStreamTest(t, 2)
+	now := time.Now()
+	s.redis.SetTime(now)
+	ch := s.queue.Consume(ctx)
+
+	require.NoError(t, s.producer.SendMessage(ctx, "2/emitter/1"))
+	msg := receive(t, ch)
+
+	// the failed message is left pending to be reclaimed.
+	msg.Failed(errors.New("first error"))
+	assert.Equal(t, int64(1), s.pending(t))
+
+	// the message is delivered again once the visibility timeout expires.
+	s.redis.SetTime(now.Add(2 * time.Minute))
+	msg = receive(t, ch)
+	assert.Equal(t, "2/emitter/1", msg.Data().ID)
+
+	// the message is moved to the dead letter stream with its error after max deliveries.
+	msg.Failed(errors.New("last error"))
+	assert.Equal(t, int64(0), s.pending(t))
+	entries, err := s.client.XRange(ctx, s.consumer.GetDeadLetterStream(), "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "2/emitter/1", entries[0].Values["message"])
+	assert.Equal(t, "last error", entries[0].Values["deadLetterError"])
+}
+
+func TestRedisStream_UpdateLag(t *testing.T) {
+	ctx := context.Background()
+	s := newRedisStreamTest(t, 1)
+
+	s.redis.SetTime(time.Now().Add(-time.Hour))
+	require.NoError(t, s.producer.SendMessage(ctx, "2/emitter/1"))
+	s.queue.updateLag(ctx)
+	assert.Equal(t, testStream, s.metrics.stream)
+	assert.True(t, s.metrics.lag >= time.Hour, s.metrics.lag)
+	assert.Equal(t, int64(0), s.metrics.deadLetters)
+
+	// a message that failed after max deliveries is counted in the dead letters.
+	messages, err := s.consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	moved, err := s.consumer.Failed(ctx, messages[0].ID, messages[0].Retry, "error")
+	require.NoError(t, err)
+	require.True(t, moved)
+
+	s.queue.updateLag(ctx)
+	assert.Equal(t, time.Duration(0), s.metrics.lag)
+	assert.Equal(t, int64(1), s.metrics.deadLetters)
+}
@@ -3,11 +3,15 @@ package queue
 import (
 	"context"
 	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 )
 
 type sqsEvent struct {
 	MessageID string `json:"MessageId"`
 	Message   string `json:"Message"`
+	// MessageAttributes carries the trace context of the producer.
+	MessageAttributes telemetry.SNSMessageAttributes `json:"MessageAttributes"`
 }
 
 // Event represents a event data to be handle.
@@ -28,9 +32,12 @@ type ConsumerMessage interface {
 	Retry() uint8
 	Data() *Event
 	Done()
-	Failed()
+	// Failed is called when the processing of the message failed with err.
+	Failed(err error)
 	IsExpired() bool
 	SentTimestamp() *time.Time
+	// Context returns the context of the message, it carries the span of its processing.
+	Context() context.Context
 }
 
 // ConsumeFunc is a function to consume VAAEvent.
@@ -0,0 +1,148 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// defaultListSize is the page size assumed for list fields without a pageSize argument.
+const defaultListSize = 50
+
+// ErrFragmentCycle is returned when the fragments of a query spread each other.
+var ErrFragmentCycle = errors.New("fragment cycle")
+
+// listFields are the fields that return a page of elements. The cost of their selections
+// is multiplied by the page size.
+var listFields = map[string]bool{
+	"operations": true,
+	"vaas":       true,
+}
+
+// Complexity returns the cost of an operation of a query: every field costs one point, and the
+// cost of the selections of a list field is multiplied by its page size.
+func Complexity(query, operationName string, variables map[string]any) (int, error) {
+	// graph-gophers/graphql-go does not export its query parser, the query is parsed by gqlparser,
+	// which follows the same specification, before being executed.
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return 0, err
+	}
+
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return 0, fmt.Errorf("unknown operation %q", operationName)
+	}
+
+	c := complexity{fragments: doc.Fragments, variables: variables, visiting: make(map[string]bool)}
+	return c.selectionSet(op.SelectionSet)
+}
+
+type complexity struct {
+	fragments ast.FragmentDefinitionList
+	variables map[string]any
+	visiting  map[string]bool
+}
+
+func (c *complexity) selectionSet(set ast.SelectionSet) (int, error) {
+	total := 0
+	for _, sel := range set {
+		var cost int
+		var err error
+		switch s := sel.(type) {
+		case *ast.Field:
+			cost, err = c.field(s)
+		case *ast.InlineFragment:
+			cost, err = c.selectionSet(s.SelectionSet)
+		case *ast.FragmentSpread:
+			cost, err = c.fragment(s.Name)
+		}
+		if err != nil {
+			return 0, err
+		}
+		total += cost
+	}
+	return total, nil
+}
+
+func (c *complexity) field(f *ast.Field) (int, error) {
+	children, err := c.selectionSet(f.SelectionSet)
+	if err != nil {
+		return 0, err
+	}
+	if listFields[f.Name] {
+		children *= c.listSize(f)
+	}
+	return 1 + children, nil
+}
+
+func (c *complexity) fragment(name string) (int, error) {
+	def := c.fragments.ForName(name)
+	if def == nil {
+		return 0, fmt.Errorf("unknown fragment %q", name)
+	}
+	if c.visiting[name] {
+		return 0, fmt.Errorf("%w: %s", ErrFragmentCycle, name)
+	}
+	c.visiting[name] = true
+	defer delete(c.visiting, name)
+	return c.selectionSet(def.SelectionSet)
+}
+
+// listSize returns the page size requested by a list field.
+func (c *complexity) listSize(f *ast.Field) int {
+	arg := f.Arguments.ForName("pageSize")
+	if arg == nil || arg.Value == nil {
+		return defaultListSize
+	}
+
+	var size float64
+	switch arg.Value.Kind {
+	case ast.IntValue:
+		n, err := strconv.ParseFloat(arg.Value.Raw, 64)
+		if err != nil {
+			return defaultListSize
+		}
+		size = n
+	case ast.Variable:
+		n, ok := number(c.variables[arg.Value.Raw])
+		if !ok {
+			return defaultListSize
+		}
+		size = n
+	default:
+		return defaultListSize
+	}
+
+	if size < 1 {
+		return defaultListSize
+	}
+	// the page sizes that do not fit an Int are rejected by the validation of the query.
+	return int(math.Min(size, math.MaxInt32))
+}
+
+// number returns the value of a numeric variable. The variables decoded from JSON are float64.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
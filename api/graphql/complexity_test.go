@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		opName    string
+		variables map[string]any
+		expected  int
+	}{
+		{
+			name:     "single field",
+			query:    `{ scorecards { tvl volume24h } }`,
+			expected: 3,
+		},
+		{
+			name:     "list field with default page size",
+			query:    `{ operations { id vaa { id } } }`,
+			expected: 1 + 50*3,
+		},
+		{
+			name:     "list field with page size",
+			query:    `{ operations(pageSize: 10) { id relay { status } } }`,
+			expected: 1 + 10*3,
+		},
+		{
+			name:      "page size from variables",
+			query:     `query ops($size: Int) { operations(pageSize: $size) { id } }`,
+			variables: map[string]any{"size": float64(20)},
+			expected:  1 + 20,
+		},
+		{
+			name:      "large page size from variables",
+			query:     `query ops($size: Int) { operations(pageSize: $size) { id } }`,
+			variables: map[string]any{"size": float64(1e6)},
+			expected:  1 + 1e6,
+		},
+		{
+			name:     "page size out of the Int range",
+			query:    `{ operations(pageSize: 99999999999) { id } }`,
+			expected: 1 + math.MaxInt32,
+		},
+		{
+			name:     "fragments",
+			query:    `{ vaas(pageSize: 5) { ...v } } fragment v on Vaa { id ... on Vaa { digest } }`,
+			expected: 1 + 5*2,
+		},
+		{
+			name:     "named operation",
+			query:    `query a { scorecards { tvl } } query b { governorNotional { chainId notionalLimit } }`,
+			opName:   "b",
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, err := Complexity(tt.query, tt.opName, tt.variables)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cost)
+		})
+	}
+}
+
+func TestComplexity_Errors(t *testing.T) {
+	_, err := Complexity(`{ operations { id }`, "", nil)
+	assert.Error(t, err)
+
+	_, err = Complexity(`query a { scorecards { tvl } } query b { scorecards { tvl } }`, "", nil)
+	assert.Error(t, err)
+
+	_, err = Complexity(`{ vaas { ...a } } fragment a on Vaa { ...b } fragment b on Vaa { ...a }`, "", nil)
+	assert.ErrorIs(t, err, ErrFragmentCycle)
+}
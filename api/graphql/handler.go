@@ -0,0 +1,173 @@
+// Package graphql implements a GraphQL endpoint over the operations, VAAs, relays, tokens,
+// scorecards and governor services of the REST API.
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"go.uber.org/zap"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxParallelism is the maximum number of resolvers of a request that run concurrently.
+const maxParallelism = 20
+
+// Config is the configuration of the GraphQL endpoint.
+type Config struct {
+	// MaxDepth is the maximum depth of the selections of a query.
+	MaxDepth int
+	// MaxComplexity is the maximum cost of a query made by a client that is limited by IP.
+	MaxComplexity int
+	// MaxComplexityApiKey is the maximum cost of a query made with an API key or a static api token.
+	MaxComplexityApiKey int
+	// MaxQueryLength is the maximum length of a query in bytes.
+	MaxQueryLength int
+}
+
+// Handler executes the GraphQL requests.
+type Handler struct {
+	schema       *gql.Schema
+	resolver     *Resolver
+	persisted    *PersistedQueries
+	cfg          Config
+	staticTokens map[string]bool
+	logger       *zap.Logger
+}
+
+// NewHandler parses the schema and creates a new handler.
+func NewHandler(resolver *Resolver, persisted *PersistedQueries, cfg Config, staticTokens map[string]bool, logger *zap.Logger) (*Handler, error) {
+	s, err := gql.ParseSchema(schema, resolver,
+		gql.MaxDepth(cfg.MaxDepth),
+		gql.MaxParallelism(maxParallelism),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{
+		schema:       s,
+		resolver:     resolver,
+		persisted:    persisted,
+		cfg:          cfg,
+		staticTokens: staticTokens,
+		logger:       logger.With(zap.String("module", "GraphQLHandler")),
+	}, nil
+}
+
+// Request is the body of a GraphQL request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+	Extensions    struct {
+		PersistedQuery *struct {
+			Version    int    `json:"version"`
+			Sha256Hash string `json:"sha256Hash"`
+		} `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+// Serve godoc
+// @Description Executes a GraphQL query over operations, VAAs, relays, tokens, scorecards and governor data.
+// @Description Queries can be sent by their sha256 hash using persisted queries (extensions.persistedQuery).
+// @Tags wormholescan
+// @ID graphql
+// @Param request body Request true "GraphQL request"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/graphql [post]
+func (h *Handler) Serve(c *fiber.Ctx) error {
+	req, err := h.parseRequest(c)
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "BAD_REQUEST", err.Error())
+	}
+
+	var hash string
+	if req.Extensions.PersistedQuery != nil {
+		hash = req.Extensions.PersistedQuery.Sha256Hash
+	}
+	query, err := h.persisted.Resolve(c.Context(), req.Query, hash)
+	switch {
+	case errors.Is(err, ErrPersistedQueryNotFound):
+		return writeError(c, fiber.StatusOK, "PERSISTED_QUERY_NOT_FOUND", err.Error())
+	case errors.Is(err, ErrPersistedQueryNotSupported):
+		return writeError(c, fiber.StatusOK, "PERSISTED_QUERY_NOT_SUPPORTED", err.Error())
+	case err != nil:
+		return writeError(c, fiber.StatusBadRequest, "BAD_REQUEST", err.Error())
+	}
+	if query == "" {
+		return writeError(c, fiber.StatusBadRequest, "BAD_REQUEST", "query is required")
+	}
+	if h.cfg.MaxQueryLength > 0 && len(query) > h.cfg.MaxQueryLength {
+		return writeError(c, fiber.StatusBadRequest, "QUERY_TOO_LARGE",
+			"query length "+strconv.Itoa(len(query))+" exceeds the limit of "+strconv.Itoa(h.cfg.MaxQueryLength))
+	}
+
+	cost, err := Complexity(query, req.OperationName, req.Variables)
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "GRAPHQL_PARSE_FAILED", err.Error())
+	}
+	c.Set("X-GraphQL-Complexity", strconv.Itoa(cost))
+	if limit := h.maxComplexity(c); cost > limit {
+		return writeError(c, fiber.StatusBadRequest, "QUERY_TOO_COMPLEX",
+			"query complexity "+strconv.Itoa(cost)+" exceeds the limit of "+strconv.Itoa(limit))
+	}
+
+	ctx := withLoaders(c.Context(), newLoaders(h.resolver.vaas, h.resolver.relays))
+	resp := h.schema.Exec(ctx, query, req.OperationName, req.Variables)
+	return c.JSON(resp)
+}
+
+// parseRequest reads a GraphQL request from the body of a POST request or from the query
+// parameters of a GET request.
+func (h *Handler) parseRequest(c *fiber.Ctx) (*Request, error) {
+	var req Request
+	if c.Method() != fiber.MethodGet {
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return nil, errors.New("invalid request body")
+		}
+		return &req, nil
+	}
+
+	req.Query = c.Query("query")
+	req.OperationName = c.Query("operationName")
+	if v := c.Query("variables"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+			return nil, errors.New("invalid variables")
+		}
+	}
+	if v := c.Query("extensions"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Extensions); err != nil {
+			return nil, errors.New("invalid extensions")
+		}
+	}
+	return &req, nil
+}
+
+// maxComplexity returns the complexity limit of a request. The requests that the IP rate limiter
+// skips, because they are made with an API key or a static api token, get the higher limit.
+func (h *Handler) maxComplexity(c *fiber.Ctx) int {
+	if middleware.IsApiKeyAuthenticated(c) || h.staticTokens[c.Get(middleware.ApiKeyHeader)] {
+		return h.cfg.MaxComplexityApiKey
+	}
+	return h.cfg.MaxComplexity
+}
+
+type responseError struct {
+	Message    string            `json:"message"`
+	Extensions map[string]string `json:"extensions"`
+}
+
+func writeError(c *fiber.Ctx, status int, code, message string) error {
+	return c.Status(status).JSON(fiber.Map{
+		"errors": []responseError{{Message: message, Extensions: map[string]string{"code": code}}},
+	})
+}
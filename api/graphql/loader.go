@@ -0,0 +1,139 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/relays"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+)
+
+const (
+	// loaderWait is the time a loader waits for more keys before running a batch.
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch is the maximum number of keys of a batch.
+	loaderMaxBatch = 100
+)
+
+// batchFunc fetches the values of a batch of keys. Keys without a value are left out of the result.
+type batchFunc[V any] func(ctx context.Context, keys []string) (map[string]V, error)
+
+// batch is a set of keys that are fetched together.
+type batch[V any] struct {
+	keys   []string
+	done   chan struct{}
+	values map[string]V
+	err    error
+}
+
+// loader batches and caches the lookups made by the resolvers of a single request, so that
+// resolving the same field for every element of a list runs one query instead of one per element.
+type loader[V any] struct {
+	fetch    batchFunc[V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	current *batch[V]
+	batches map[string]*batch[V]
+}
+
+func newLoader[V any](fetch batchFunc[V], wait time.Duration, maxBatch int) *loader[V] {
+	return &loader[V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		batches:  make(map[string]*batch[V]),
+	}
+}
+
+// Load returns the value of a key, or the zero value if the key has no value.
+func (l *loader[V]) Load(ctx context.Context, key string) (V, error) {
+	l.mu.Lock()
+	b, ok := l.batches[key]
+	if !ok {
+		if l.current == nil {
+			b = &batch[V]{done: make(chan struct{})}
+			l.current = b
+			time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+		}
+		b = l.current
+		b.keys = append(b.keys, key)
+		l.batches[key] = b
+		if len(b.keys) >= l.maxBatch {
+			l.current = nil
+			go l.run(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return b.values[key], b.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch runs a batch when its wait time is over, unless it was already run because it was full.
+func (l *loader[V]) dispatch(ctx context.Context, b *batch[V]) {
+	l.mu.Lock()
+	if l.current != b {
+		l.mu.Unlock()
+		return
+	}
+	l.current = nil
+	l.mu.Unlock()
+	l.run(ctx, b)
+}
+
+func (l *loader[V]) run(ctx context.Context, b *batch[V]) {
+	b.values, b.err = l.fetch(ctx, b.keys)
+	close(b.done)
+}
+
+// loaders holds the loaders of a request.
+type loaders struct {
+	vaas   *loader[*vaa.VaaDoc]
+	relays *loader[*relays.RelayDoc]
+}
+
+func newLoaders(vaaService *vaa.Service, relaysService *relays.Service) *loaders {
+	return &loaders{
+		vaas: newLoader(func(ctx context.Context, ids []string) (map[string]*vaa.VaaDoc, error) {
+			docs, err := vaaService.FindByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[string]*vaa.VaaDoc, len(docs))
+			for _, d := range docs {
+				values[d.ID] = d
+			}
+			return values, nil
+		}, loaderWait, loaderMaxBatch),
+		relays: newLoader(func(ctx context.Context, ids []string) (map[string]*relays.RelayDoc, error) {
+			docs, err := relaysService.FindByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[string]*relays.RelayDoc, len(docs))
+			for _, d := range docs {
+				values[d.ID] = d
+			}
+			return values, nil
+		}, loaderWait, loaderMaxBatch),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func getLoaders(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey{}).(*loaders)
+	return l
+}
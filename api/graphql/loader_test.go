@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader_BatchesConcurrentLoads(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	l := newLoader(func(ctx context.Context, keys []string) (map[string]string, error) {
+		mu.Lock()
+		batches = append(batches, keys)
+		mu.Unlock()
+		values := make(map[string]string)
+		for _, k := range keys {
+			if k != "missing" {
+				values[k] = "value-" + k
+			}
+		}
+		return values, nil
+	}, 50*time.Millisecond, 100)
+
+	keys := []string{"a", "b", "c", "a", "missing"}
+	results := make([]string, len(keys))
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		go func(i int, k string) {
+			defer wg.Done()
+			v, err := l.Load(context.Background(), k)
+			assert.NoError(t, err)
+			results[i] = v
+		}(i, k)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"value-a", "value-b", "value-c", "value-a", ""}, results)
+	assert.Len(t, batches, 1)
+	assert.ElementsMatch(t, []string{"a", "b", "c", "missing"}, batches[0])
+
+	// cached keys are not fetched again.
+	v, err := l.Load(context.Background(), "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "value-b", v)
+	assert.Len(t, batches, 1)
+}
+
+func TestLoader_MaxBatch(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	l := newLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		mu.Lock()
+		sizes = append(sizes, len(keys))
+		mu.Unlock()
+		return map[string]int{}, nil
+	}, 10*time.Millisecond, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := l.Load(context.Background(), fmt.Sprint(i))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, s := range sizes {
+		assert.LessOrEqual(t, s, 2)
+		total += s
+	}
+	assert.Equal(t, 5, total)
+}
+
+func TestLoader_Error(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	l := newLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		return nil, errFetch
+	}, time.Millisecond, 10)
+
+	_, err := l.Load(context.Background(), "a")
+	assert.ErrorIs(t, err, errFetch)
+}
@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
+)
+
+// persistedQueryExpiration is the time an automatic persisted query is kept in the cache.
+const persistedQueryExpiration = 7 * 24 * time.Hour
+
+var (
+	ErrPersistedQueryNotFound     = errors.New("PersistedQueryNotFound")
+	ErrPersistedQueryNotSupported = errors.New("PersistedQueryNotSupported")
+	ErrPersistedQueryRequired     = errors.New("only persisted queries are allowed")
+	ErrPersistedQueryMismatch     = errors.New("provided sha does not match query")
+)
+
+// PersistedQueries resolves the queries sent by hash. It supports the queries registered in a
+// file at startup and automatic persisted queries, which clients register by sending the query
+// along with its sha256 hash and are stored in the cache.
+type PersistedQueries struct {
+	cache  cache.Cache
+	static map[string]string
+	// only rejects the queries that are not registered in the file.
+	only bool
+}
+
+// NewPersistedQueries creates the persisted queries. The file, when set, is a JSON object that
+// maps the sha256 hash of each query to the query.
+func NewPersistedQueries(c cache.Cache, file string, only bool) (*PersistedQueries, error) {
+	static := make(map[string]string)
+	if file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read persisted queries file: %w", err)
+		}
+		if err := json.Unmarshal(b, &static); err != nil {
+			return nil, fmt.Errorf("failed to parse persisted queries file: %w", err)
+		}
+		for hash, query := range static {
+			if hashQuery(query) != hash {
+				return nil, fmt.Errorf("persisted query %s: %w", hash, ErrPersistedQueryMismatch)
+			}
+		}
+	}
+	if only && len(static) == 0 {
+		return nil, errors.New("persisted queries only mode requires a persisted queries file")
+	}
+	return &PersistedQueries{cache: c, static: static, only: only}, nil
+}
+
+// Resolve returns the query to execute for a request with the given query and persisted query hash.
+func (p *PersistedQueries) Resolve(ctx context.Context, query, hash string) (string, error) {
+	if hash == "" {
+		if p.only {
+			return "", ErrPersistedQueryRequired
+		}
+		return query, nil
+	}
+
+	if q, ok := p.static[hash]; ok {
+		return q, nil
+	}
+	if p.only {
+		return "", ErrPersistedQueryNotSupported
+	}
+
+	key := "graphql:apq:" + hash
+	if query == "" {
+		q, err := p.cache.Get(ctx, key)
+		if err != nil || q == "" {
+			return "", ErrPersistedQueryNotFound
+		}
+		return q, nil
+	}
+
+	if hashQuery(query) != hash {
+		return "", ErrPersistedQueryMismatch
+	}
+	// a query that can not be cached is still executed, the client will register it again.
+	_ = p.cache.Set(ctx, key, query, persistedQueryExpiration)
+	return query, nil
+}
+
+func hashQuery(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
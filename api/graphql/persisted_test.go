@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
+)
+
+type mapCache struct {
+	values map[string]string
+}
+
+func (c *mapCache) Get(ctx context.Context, key string) (string, error) {
+	v, ok := c.values[key]
+	if !ok {
+		return "", cache.ErrNotFound
+	}
+	return v, nil
+}
+
+func (c *mapCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.values[key] = value.(string)
+	return nil
+}
+
+func (c *mapCache) Close() error { return nil }
+
+func TestPersistedQueries_Automatic(t *testing.T) {
+	ctx := context.Background()
+	query := `{ scorecards { tvl } }`
+	hash := hashQuery(query)
+
+	p, err := NewPersistedQueries(&mapCache{values: map[string]string{}}, "", false)
+	require.NoError(t, err)
+
+	// plain queries are executed as they are.
+	q, err := p.Resolve(ctx, query, "")
+	assert.NoError(t, err)
+	assert.Equal(t, query, q)
+
+	// the hash is unknown until the client registers the query.
+	_, err = p.Resolve(ctx, "", hash)
+	assert.ErrorIs(t, err, ErrPersistedQueryNotFound)
+
+	_, err = p.Resolve(ctx, query, hashQuery("other"))
+	assert.ErrorIs(t, err, ErrPersistedQueryMismatch)
+
+	q, err = p.Resolve(ctx, query, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, query, q)
+
+	q, err = p.Resolve(ctx, "", hash)
+	assert.NoError(t, err)
+	assert.Equal(t, query, q)
+}
+
+func TestPersistedQueries_Only(t *testing.T) {
+	ctx := context.Background()
+	query := `{ scorecards { tvl } }`
+	hash := hashQuery(query)
+
+	file := filepath.Join(t.TempDir(), "queries.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"`+hash+`": "{ scorecards { tvl } }"}`), 0o600))
+
+	p, err := NewPersistedQueries(&mapCache{values: map[string]string{}}, file, true)
+	require.NoError(t, err)
+
+	q, err := p.Resolve(ctx, "", hash)
+	assert.NoError(t, err)
+	assert.Equal(t, query, q)
+
+	_, err = p.Resolve(ctx, query, "")
+	assert.ErrorIs(t, err, ErrPersistedQueryRequired)
+
+	other := `{ governorNotional { chainId } }`
+	_, err = p.Resolve(ctx, other, hashQuery(other))
+	assert.ErrorIs(t, err, ErrPersistedQueryNotSupported)
+
+	_, err = NewPersistedQueries(&mapCache{values: map[string]string{}}, "", true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,305 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/operations"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/relays"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
+	"github.com/wormhole-foundation/wormhole-explorer/common/types"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// maxPageSize is the maximum number of elements of a list query.
+const maxPageSize = 100
+
+// Resolver is the root resolver of the GraphQL schema. It reuses the services of the REST API.
+type Resolver struct {
+	operations   *operations.Service
+	vaas         *vaa.Service
+	relays       *relays.Service
+	transactions *transactions.Service
+	governor     *governor.Service
+	logger       *zap.Logger
+}
+
+// NewResolver creates a new root resolver.
+func NewResolver(
+	operationsService *operations.Service,
+	vaaService *vaa.Service,
+	relaysService *relays.Service,
+	transactionsService *transactions.Service,
+	governorService *governor.Service,
+	logger *zap.Logger,
+) *Resolver {
+	return &Resolver{
+		operations:   operationsService,
+		vaas:         vaaService,
+		relays:       relaysService,
+		transactions: transactionsService,
+		governor:     governorService,
+		logger:       logger.With(zap.String("module", "GraphQLResolver")),
+	}
+}
+
+type vaaIDArgs struct {
+	Chain   int32
+	Emitter string
+	Seq     string
+}
+
+func (a *vaaIDArgs) parse() (sdk.ChainID, *types.Address, error) {
+	chainID, err := parseChain(a.Chain)
+	if err != nil {
+		return sdk.ChainIDUnset, nil, err
+	}
+	emitter, err := types.StringToAddress(a.Emitter, true)
+	if err != nil {
+		return sdk.ChainIDUnset, nil, fmt.Errorf("invalid emitter: %s", a.Emitter)
+	}
+	return chainID, emitter, nil
+}
+
+// Operation resolves the operation of a VAA.
+func (r *Resolver) Operation(ctx context.Context, args vaaIDArgs) (*operationResolver, error) {
+	chainID, emitter, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+	op, err := r.operations.FindById(ctx, chainID, emitter, args.Seq)
+	if err != nil {
+		return nil, r.notFoundAsNil(ctx, err)
+	}
+	return &operationResolver{root: r, op: op}, nil
+}
+
+// Operations resolves a page of operations.
+func (r *Resolver) Operations(ctx context.Context, args struct {
+	TxHash      *string
+	Address     *string
+	SourceChain *[]int32
+	TargetChain *[]int32
+	AppId       *[]string
+	Page        *int32
+	PageSize    *int32
+}) ([]*operationResolver, error) {
+	p, err := parsePagination(args.Page, args.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	filter := operations.OperationFilter{Pagination: *p}
+	if args.TxHash != nil && *args.TxHash != "" {
+		txHash, err := types.ParseTxHash(*args.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid txHash: %s", *args.TxHash)
+		}
+		filter.TxHash = txHash
+	}
+	if args.Address != nil {
+		filter.Address = *args.Address
+	}
+	if filter.SourceChainIDs, err = parseChains(args.SourceChain); err != nil {
+		return nil, err
+	}
+	if filter.TargetChainIDs, err = parseChains(args.TargetChain); err != nil {
+		return nil, err
+	}
+	if args.AppId != nil {
+		filter.AppIDs = *args.AppId
+	}
+	searchByAddressOrTxHash := filter.Address != "" || filter.TxHash != nil
+	if searchByAddressOrTxHash && (len(filter.SourceChainIDs) > 0 || len(filter.TargetChainIDs) > 0 || len(filter.AppIDs) > 0) {
+		return nil, errors.New("address/txHash cannot be combined with sourceChain/targetChain/appId")
+	}
+
+	ops, err := r.operations.FindAll(ctx, filter)
+	if err != nil {
+		return nil, r.internalError(ctx, err)
+	}
+	result := make([]*operationResolver, 0, len(ops))
+	for _, op := range ops {
+		result = append(result, &operationResolver{root: r, op: op})
+	}
+	return result, nil
+}
+
+// Vaa resolves a VAA.
+func (r *Resolver) Vaa(ctx context.Context, args vaaIDArgs) (*vaaResolver, error) {
+	chainID, emitter, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.vaas.FindById(ctx, chainID, emitter, args.Seq, false)
+	if err != nil {
+		return nil, r.notFoundAsNil(ctx, err)
+	}
+	return &vaaResolver{root: r, doc: res.Data}, nil
+}
+
+// Vaas resolves a page of VAAs.
+func (r *Resolver) Vaas(ctx context.Context, args struct {
+	Chain    *int32
+	Emitter  *string
+	AppId    *string
+	Page     *int32
+	PageSize *int32
+}) ([]*vaaResolver, error) {
+	p, err := parsePagination(args.Page, args.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []*vaa.VaaDoc
+	switch {
+	case args.Emitter != nil:
+		if args.Chain == nil {
+			return nil, errors.New("chain is required when filtering by emitter")
+		}
+		chainID, emitter, err := (&vaaIDArgs{Chain: *args.Chain, Emitter: *args.Emitter}).parse()
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.vaas.FindByEmitter(ctx, &vaa.FindByEmitterParams{
+			EmitterChain:   chainID,
+			EmitterAddress: emitter,
+			Pagination:     p,
+		})
+		if err != nil {
+			return nil, r.internalError(ctx, err)
+		}
+		docs = res.Data
+	case args.Chain != nil:
+		chainID, err := parseChain(*args.Chain)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.vaas.FindByChain(ctx, chainID, p)
+		if err != nil {
+			return nil, r.internalError(ctx, err)
+		}
+		docs = res.Data
+	default:
+		params := &vaa.FindAllParams{Pagination: p}
+		if args.AppId != nil {
+			params.AppId = *args.AppId
+		}
+		res, err := r.vaas.FindAll(ctx, params)
+		if err != nil {
+			return nil, r.internalError(ctx, err)
+		}
+		docs = res.Data
+	}
+
+	result := make([]*vaaResolver, 0, len(docs))
+	for _, d := range docs {
+		result = append(result, &vaaResolver{root: r, doc: d})
+	}
+	return result, nil
+}
+
+// Token resolves the metadata of a token.
+func (r *Resolver) Token(ctx context.Context, args struct {
+	Chain   int32
+	Address string
+}) (*tokenResolver, error) {
+	chainID, err := parseChain(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	address, err := types.StringToAddress(args.Address, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %s", args.Address)
+	}
+	return r.token(ctx, chainID, address)
+}
+
+func (r *Resolver) token(ctx context.Context, chainID sdk.ChainID, address *types.Address) (*tokenResolver, error) {
+	token, err := r.transactions.GetTokenByChainAndAddress(ctx, chainID, address)
+	if err != nil {
+		return nil, r.notFoundAsNil(ctx, err)
+	}
+	return &tokenResolver{token: token}, nil
+}
+
+// Scorecards resolves the network scorecards.
+func (r *Resolver) Scorecards(ctx context.Context) (*scorecardsResolver, error) {
+	scorecards, err := r.transactions.GetScorecards(ctx)
+	if err != nil {
+		return nil, r.internalError(ctx, err)
+	}
+	return &scorecardsResolver{s: scorecards}, nil
+}
+
+// GovernorNotional resolves the governor available notional by chain.
+func (r *Resolver) GovernorNotional(ctx context.Context) ([]*governorNotionalResolver, error) {
+	notionals, err := r.governor.GetAvailNotionByChain(ctx)
+	if err != nil {
+		return nil, r.internalError(ctx, err)
+	}
+	result := make([]*governorNotionalResolver, 0, len(notionals))
+	for _, n := range notionals {
+		result = append(result, &governorNotionalResolver{n: n})
+	}
+	return result, nil
+}
+
+// notFoundAsNil resolves a not found error as a null value.
+func (r *Resolver) notFoundAsNil(ctx context.Context, err error) error {
+	if errors.Is(err, errs.ErrNotFound) {
+		return nil
+	}
+	return r.internalError(ctx, err)
+}
+
+// internalError logs an error and hides its details from the client.
+func (r *Resolver) internalError(ctx context.Context, err error) error {
+	requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+	r.logger.Error("failed to resolve graphql field", zap.Error(err), zap.String("requestID", requestID))
+	return errs.ErrInternalError
+}
+
+func parseChain(chain int32) (sdk.ChainID, error) {
+	if chain <= 0 || chain > int32(^uint16(0)) {
+		return sdk.ChainIDUnset, fmt.Errorf("invalid chain: %d", chain)
+	}
+	return sdk.ChainID(chain), nil
+}
+
+func parseChains(chains *[]int32) ([]sdk.ChainID, error) {
+	if chains == nil {
+		return nil, nil
+	}
+	result := make([]sdk.ChainID, 0, len(*chains))
+	for _, c := range *chains {
+		chainID, err := parseChain(c)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, chainID)
+	}
+	return result, nil
+}
+
+func parsePagination(page, pageSize *int32) (*pagination.Pagination, error) {
+	p := pagination.Default()
+	if pageSize != nil {
+		if *pageSize <= 0 || *pageSize > maxPageSize {
+			return nil, fmt.Errorf("pageSize must be between 1 and %d", maxPageSize)
+		}
+		p.SetLimit(int64(*pageSize))
+	}
+	if page != nil {
+		if *page < 0 {
+			return nil, errors.New("page cannot be negative")
+		}
+		p.SetSkip(int64(*page) * p.Limit)
+	}
+	return p, nil
+}
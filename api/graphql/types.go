@@ -0,0 +1,264 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/operations"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/relays"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+	"github.com/wormhole-foundation/wormhole-explorer/common/types"
+)
+
+type operationResolver struct {
+	root *Resolver
+	op   *operations.OperationDto
+}
+
+func (r *operationResolver) ID() gql.ID { return gql.ID(r.op.ID) }
+
+// idPart returns a part of the operation id (chain/emitter/sequence).
+func (r *operationResolver) idPart(i int) string {
+	parts := strings.Split(r.op.ID, "/")
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[i]
+}
+
+func (r *operationResolver) EmitterChain() int32 {
+	chain, _ := strconv.ParseInt(r.idPart(0), 10, 32)
+	return int32(chain)
+}
+
+func (r *operationResolver) EmitterAddress() string { return r.idPart(1) }
+func (r *operationResolver) Sequence() string       { return r.idPart(2) }
+func (r *operationResolver) TxHash() string         { return r.op.TxHash }
+func (r *operationResolver) Symbol() string         { return r.op.Symbol }
+func (r *operationResolver) UsdAmount() string      { return r.op.UsdAmount }
+func (r *operationResolver) TokenAmount() string    { return r.op.TokenAmount }
+
+func (r *operationResolver) AppIds() []string {
+	if r.op.StandardizedProperties == nil || r.op.StandardizedProperties.AppIds == nil {
+		return []string{}
+	}
+	return r.op.StandardizedProperties.AppIds
+}
+
+func (r *operationResolver) FromChain() *int32 {
+	if r.op.StandardizedProperties == nil {
+		return nil
+	}
+	return int32Ptr(int32(r.op.StandardizedProperties.FromChain))
+}
+
+func (r *operationResolver) FromAddress() *string {
+	if r.op.StandardizedProperties == nil {
+		return nil
+	}
+	return &r.op.StandardizedProperties.FromAddress
+}
+
+func (r *operationResolver) ToChain() *int32 {
+	if r.op.StandardizedProperties == nil {
+		return nil
+	}
+	return int32Ptr(int32(r.op.StandardizedProperties.ToChain))
+}
+
+func (r *operationResolver) ToAddress() *string {
+	if r.op.StandardizedProperties == nil {
+		return nil
+	}
+	return &r.op.StandardizedProperties.ToAddress
+}
+
+func (r *operationResolver) SourceTx() *sourceTxResolver {
+	if r.op.SourceTx == nil {
+		return nil
+	}
+	return &sourceTxResolver{tx: r.op.SourceTx}
+}
+
+func (r *operationResolver) DestinationTx() *destinationTxResolver {
+	if r.op.DestinationTx == nil {
+		return nil
+	}
+	return &destinationTxResolver{tx: r.op.DestinationTx}
+}
+
+func (r *operationResolver) Emitter() *emitterResolver {
+	return newEmitterResolver(r.op.Emitter)
+}
+
+// Vaa resolves the VAA of the operation through the request loader, so that the VAAs of a
+// page of operations are fetched with a single query.
+func (r *operationResolver) Vaa(ctx context.Context) (*vaaResolver, error) {
+	doc, err := getLoaders(ctx).vaas.Load(ctx, r.op.ID)
+	if err != nil {
+		return nil, r.root.internalError(ctx, err)
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	return &vaaResolver{root: r.root, doc: doc}, nil
+}
+
+func (r *operationResolver) Relay(ctx context.Context) (*relayResolver, error) {
+	return loadRelay(ctx, r.root, r.op.ID)
+}
+
+func (r *operationResolver) Token(ctx context.Context) (*tokenResolver, error) {
+	p := r.op.StandardizedProperties
+	if p == nil || p.TokenChain == 0 || p.TokenAddress == "" {
+		return nil, nil
+	}
+	address, err := types.StringToAddress(p.TokenAddress, true)
+	if err != nil {
+		return nil, nil
+	}
+	return r.root.token(ctx, p.TokenChain, address)
+}
+
+type sourceTxResolver struct {
+	tx *operations.OriginTx
+}
+
+func (r *sourceTxResolver) TxHash() string       { return r.tx.TxHash }
+func (r *sourceTxResolver) From() string         { return r.tx.From }
+func (r *sourceTxResolver) Status() string       { return r.tx.Status }
+func (r *sourceTxResolver) Timestamp() *gql.Time { return timePtr(r.tx.Timestamp) }
+
+type destinationTxResolver struct {
+	tx *operations.DestinationTx
+}
+
+func (r *destinationTxResolver) ChainId() int32       { return int32(r.tx.ChainID) }
+func (r *destinationTxResolver) Status() string       { return r.tx.Status }
+func (r *destinationTxResolver) Method() string       { return r.tx.Method }
+func (r *destinationTxResolver) TxHash() string       { return r.tx.TxHash }
+func (r *destinationTxResolver) From() string         { return r.tx.From }
+func (r *destinationTxResolver) To() string           { return r.tx.To }
+func (r *destinationTxResolver) BlockNumber() string  { return r.tx.BlockNumber }
+func (r *destinationTxResolver) Timestamp() *gql.Time { return timePtr(r.tx.Timestamp) }
+
+type vaaResolver struct {
+	root *Resolver
+	doc  *vaa.VaaDoc
+}
+
+func (r *vaaResolver) ID() gql.ID              { return gql.ID(r.doc.ID) }
+func (r *vaaResolver) Version() int32          { return int32(r.doc.Version) }
+func (r *vaaResolver) EmitterChain() int32     { return int32(r.doc.EmitterChain) }
+func (r *vaaResolver) EmitterAddr() string     { return r.doc.EmitterAddr }
+func (r *vaaResolver) Sequence() string        { return r.doc.Sequence }
+func (r *vaaResolver) GuardianSetIndex() int32 { return int32(r.doc.GuardianSetIndex) }
+func (r *vaaResolver) Vaa() string             { return base64.StdEncoding.EncodeToString(r.doc.Vaa) }
+func (r *vaaResolver) Digest() string          { return r.doc.Digest }
+func (r *vaaResolver) Timestamp() *gql.Time    { return timePtr(r.doc.Timestamp) }
+func (r *vaaResolver) TxHash() *string         { return r.doc.TxHash }
+func (r *vaaResolver) AppId() string           { return r.doc.AppId }
+func (r *vaaResolver) IsDuplicated() bool      { return r.doc.IsDuplicated }
+
+func (r *vaaResolver) Emitter() *emitterResolver {
+	return newEmitterResolver(r.doc.Emitter)
+}
+
+func (r *vaaResolver) Relay(ctx context.Context) (*relayResolver, error) {
+	id := fmt.Sprintf("%d/%s/%s", r.doc.EmitterChain, r.doc.EmitterAddr, r.doc.Sequence)
+	return loadRelay(ctx, r.root, id)
+}
+
+// loadRelay resolves the relay of a VAA through the request loader.
+func loadRelay(ctx context.Context, root *Resolver, id string) (*relayResolver, error) {
+	doc, err := getLoaders(ctx).relays.Load(ctx, id)
+	if err != nil {
+		return nil, root.internalError(ctx, err)
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	return &relayResolver{doc: doc}, nil
+}
+
+type emitterResolver struct {
+	info *emitter.Info
+}
+
+func newEmitterResolver(info *emitter.Info) *emitterResolver {
+	if info == nil {
+		return nil
+	}
+	return &emitterResolver{info: info}
+}
+
+func (r *emitterResolver) AppId() string { return r.info.AppID }
+func (r *emitterResolver) Name() string  { return r.info.Name }
+func (r *emitterResolver) Owner() string { return r.info.Owner }
+
+type relayResolver struct {
+	doc *relays.RelayDoc
+}
+
+func (r *relayResolver) ID() gql.ID             { return gql.ID(r.doc.ID) }
+func (r *relayResolver) Status() string         { return r.doc.Data.Status }
+func (r *relayResolver) ReceivedAt() gql.Time   { return gql.Time{Time: r.doc.Data.ReceivedAt} }
+func (r *relayResolver) CompletedAt() *gql.Time { return timePtr(r.doc.Data.CompletedAt) }
+func (r *relayResolver) FailedAt() *gql.Time    { return timePtr(r.doc.Data.FailedAt) }
+func (r *relayResolver) ToTxHash() *string      { return r.doc.Data.ToTxHash }
+
+type tokenResolver struct {
+	token *transactions.Token
+}
+
+func (r *tokenResolver) Symbol() string      { return string(r.token.Symbol) }
+func (r *tokenResolver) CoingeckoId() string { return r.token.CoingeckoID }
+func (r *tokenResolver) Decimals() int32     { return int32(r.token.Decimals) }
+
+type scorecardsResolver struct {
+	s *transactions.Scorecards
+}
+
+func (r *scorecardsResolver) Messages24h() string   { return r.s.Messages24h }
+func (r *scorecardsResolver) TotalMessages() string { return r.s.TotalMessages }
+func (r *scorecardsResolver) TotalTxCount() string  { return r.s.TotalTxCount }
+func (r *scorecardsResolver) TotalTxVolume() string { return r.s.TotalTxVolume }
+func (r *scorecardsResolver) Tvl() string           { return r.s.Tvl }
+func (r *scorecardsResolver) Volume24h() string     { return r.s.Volume24h }
+func (r *scorecardsResolver) Volume7d() string      { return r.s.Volume7d }
+func (r *scorecardsResolver) Volume30d() string     { return r.s.Volume30d }
+
+type governorNotionalResolver struct {
+	n *governor.AvailableNotionalByChain
+}
+
+func (r *governorNotionalResolver) ChainId() int32 { return int32(r.n.ChainID) }
+
+func (r *governorNotionalResolver) AvailableNotional() string {
+	return strconv.FormatUint(uint64(r.n.AvailableNotional), 10)
+}
+
+func (r *governorNotionalResolver) NotionalLimit() string {
+	return strconv.FormatUint(uint64(r.n.NotionalLimit), 10)
+}
+
+func (r *governorNotionalResolver) MaxTransactionSize() string {
+	return strconv.FormatUint(uint64(r.n.MaxTransactionSize), 10)
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func timePtr(t *time.Time) *gql.Time {
+	if t == nil {
+		return nil
+	}
+	return &gql.Time{Time: *t}
+}
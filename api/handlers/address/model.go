@@ -1,7 +1,118 @@
 package address
 
-import "github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+import (
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
 
 type AddressOverview struct {
 	Vaas []*vaa.VaaDoc `json:"vaas"`
 }
+
+// AddressStats contains the aggregated transfer activity of an address.
+//
+// USD amounts are computed from the token prices at the moment of each transfer
+// (transferPrices collection), transfers without a known price only add to the counters.
+type AddressStats struct {
+	Address            string                  `bson:"_id" json:"address"`
+	TotalSentUsd       string                  `bson:"totalSentUsd" json:"totalSentUsd"`
+	TotalReceivedUsd   string                  `bson:"totalReceivedUsd" json:"totalReceivedUsd"`
+	SentCount          uint64                  `bson:"sentCount" json:"sentCount"`
+	ReceivedCount      uint64                  `bson:"receivedCount" json:"receivedCount"`
+	FirstActivity      *time.Time              `bson:"firstActivity" json:"firstActivity"`
+	LastActivity       *time.Time              `bson:"lastActivity" json:"lastActivity"`
+	PendingRedemptions int                     `bson:"-" json:"pendingRedemptions"`
+	Chains             []*ChainActivity        `bson:"chains" json:"chains"`
+	Tokens             []*TokenActivity        `bson:"tokens" json:"tokens"`
+	Protocols          []*ProtocolActivity     `bson:"protocols" json:"protocols"`
+	Counterparties     []*CounterpartyActivity `bson:"counterparties" json:"counterparties"`
+	// CounterpartiesTruncated is true when the least active counterparties were dropped from the rollup.
+	CounterpartiesTruncated bool `bson:"counterpartiesTruncated" json:"counterpartiesTruncated"`
+	// OtherCounterparties aggregates the activity with the counterparties that are not in Counterparties,
+	// either dropped from the rollup or first seen after it was truncated.
+	OtherCounterparties *CounterpartyActivity `bson:"otherCounterparties,omitempty" json:"otherCounterparties,omitempty"`
+
+	// PendingVaaIDs are the transfers of the address that have not been redeemed yet.
+	PendingVaaIDs []string `bson:"pendingVaaIds" json:"-"`
+	// Watermark is the `indexedAt` of the last VAA aggregated in the rollup.
+	Watermark *time.Time `bson:"watermark" json:"-"`
+	UpdatedAt *time.Time `bson:"updatedAt" json:"updatedAt"`
+
+	// representations are the known encodings of the address.
+	representations map[string]bool
+}
+
+// ChainActivity is the volume sent from and received on a chain.
+type ChainActivity struct {
+	ChainID       sdk.ChainID `bson:"chainId" json:"chainId"`
+	SentUsd       string      `bson:"sentUsd" json:"sentUsd"`
+	ReceivedUsd   string      `bson:"receivedUsd" json:"receivedUsd"`
+	SentCount     uint64      `bson:"sentCount" json:"sentCount"`
+	ReceivedCount uint64      `bson:"receivedCount" json:"receivedCount"`
+}
+
+// TokenActivity is the volume sent and received of a token.
+type TokenActivity struct {
+	TokenChain    sdk.ChainID `bson:"tokenChain" json:"tokenChain"`
+	TokenAddress  string      `bson:"tokenAddress" json:"tokenAddress"`
+	Symbol        string      `bson:"symbol" json:"symbol,omitempty"`
+	SentUsd       string      `bson:"sentUsd" json:"sentUsd"`
+	ReceivedUsd   string      `bson:"receivedUsd" json:"receivedUsd"`
+	SentCount     uint64      `bson:"sentCount" json:"sentCount"`
+	ReceivedCount uint64      `bson:"receivedCount" json:"receivedCount"`
+}
+
+// ProtocolActivity is the volume and number of transfers of a protocol (appId).
+type ProtocolActivity struct {
+	AppID     string `bson:"appId" json:"appId"`
+	VolumeUsd string `bson:"volumeUsd" json:"volumeUsd"`
+	Count     uint64 `bson:"count" json:"count"`
+}
+
+// CounterpartyActivity is the volume exchanged with another address.
+type CounterpartyActivity struct {
+	ChainID     sdk.ChainID `bson:"chainId" json:"chainId"`
+	Address     string      `bson:"address" json:"address"`
+	SentUsd     string      `bson:"sentUsd" json:"sentUsd"`
+	ReceivedUsd string      `bson:"receivedUsd" json:"receivedUsd"`
+	Count       uint64      `bson:"count" json:"count"`
+}
+
+// AddressTransfer is a transfer in which an address is the sender or the recipient.
+type AddressTransfer struct {
+	ID           string      `bson:"_id"`
+	IndexedAt    time.Time   `bson:"indexedAt"`
+	Timestamp    time.Time   `bson:"timestamp"`
+	AppIDs       []string    `bson:"appIds"`
+	FromChain    sdk.ChainID `bson:"fromChain"`
+	FromAddress  string      `bson:"fromAddress"`
+	ToChain      sdk.ChainID `bson:"toChain"`
+	ToAddress    string      `bson:"toAddress"`
+	TokenChain   sdk.ChainID `bson:"tokenChain"`
+	TokenAddress string      `bson:"tokenAddress"`
+	Symbol       string      `bson:"symbol"`
+	UsdAmount    string      `bson:"usdAmount"`
+	Redeemed     bool        `bson:"redeemed"`
+}
+
+// AddressGraph is the linked identity of an address: its known representations and the
+// addresses it has sent transfers to or received transfers from.
+type AddressGraph struct {
+	Address            string           `json:"address"`
+	UniversalAddresses []string         `json:"universalAddresses"`
+	Representations    []string         `json:"representations"`
+	Links              []*LinkedAddress `json:"links"`
+}
+
+// LinkedAddress is an address related to another one through transfers.
+type LinkedAddress struct {
+	UniversalAddress string        `bson:"_id" json:"universalAddress"`
+	Representations  []string      `bson:"representations" json:"representations"`
+	Chains           []sdk.ChainID `bson:"chains" json:"chains"`
+	SentCount        uint64        `bson:"sentCount" json:"sentCount"`
+	ReceivedCount    uint64        `bson:"receivedCount" json:"receivedCount"`
+	FirstSeen        *time.Time    `bson:"firstSeen" json:"firstSeen"`
+	LastSeen         *time.Time    `bson:"lastSeen" json:"lastSeen"`
+}
@@ -2,12 +2,16 @@ package address
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/common"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
@@ -16,7 +20,11 @@ type Repository struct {
 	logger *zap.Logger
 
 	collections struct {
-		parsedVaa *mongo.Collection
+		parsedVaa          *mongo.Collection
+		vaas               *mongo.Collection
+		globalTransactions *mongo.Collection
+		addressStats       *mongo.Collection
+		addressGraph       *mongo.Collection
 	}
 }
 
@@ -24,9 +32,17 @@ func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
 	return &Repository{db: db,
 		logger: logger.With(zap.String("module", "AddressRepository")),
 		collections: struct {
-			parsedVaa *mongo.Collection
+			parsedVaa          *mongo.Collection
+			vaas               *mongo.Collection
+			globalTransactions *mongo.Collection
+			addressStats       *mongo.Collection
+			addressGraph       *mongo.Collection
 		}{
-			parsedVaa: db.Collection("parsedVaa"),
+			parsedVaa:          db.Collection("parsedVaa"),
+			vaas:               db.Collection("vaas"),
+			globalTransactions: db.Collection("globalTransactions"),
+			addressStats:       db.Collection("addressStats"),
+			addressGraph:       db.Collection("addressGraph"),
 		},
 	}
 }
@@ -126,3 +142,237 @@ func (r *Repository) GetAddressOverview(ctx context.Context, params *GetAddressO
 	}
 	return &AddressOverview{Vaas: vaas}, nil
 }
+
+// FindAddressStats returns the rollup of an address, or nil if it has not been created yet.
+func (r *Repository) FindAddressStats(ctx context.Context, address string) (*AddressStats, error) {
+
+	var stats AddressStats
+	err := r.collections.addressStats.FindOne(ctx, bson.D{{Key: "_id", Value: address}}).Decode(&stats)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to find address stats",
+			zap.Error(err),
+			zap.String("address", address),
+			zap.String("requestID", requestID),
+		)
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// SaveAddressStats stores the rollup of an address.
+//
+// The write only succeeds if the stored watermark is still the one the rollup was built from,
+// so that concurrent refreshes never aggregate the same VAAs twice. It returns false when
+// another refresh won the race.
+func (r *Repository) SaveAddressStats(ctx context.Context, stats *AddressStats, prevWatermark *time.Time) (bool, error) {
+
+	filter := bson.D{{Key: "_id", Value: stats.Address}, {Key: "watermark", Value: prevWatermark}}
+	_, err := r.collections.addressStats.ReplaceOne(ctx, filter, stats, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to save address stats",
+			zap.Error(err),
+			zap.String("address", stats.Address),
+			zap.String("requestID", requestID),
+		)
+		return false, err
+	}
+	return true, nil
+}
+
+// FindAddressTransfers returns the transfers sent or received by an address whose VAA was
+// indexed in the range (since, until].
+//
+// Only the VAAs updated since the previous refresh are looked up, so that a refresh does not
+// resolve the whole history of the address.
+func (r *Repository) FindAddressTransfers(ctx context.Context, address string, representations []string, since *time.Time, until time.Time) ([]*AddressTransfer, error) {
+
+	ids, err := common.FindVaasIdsByRepresentations(ctx, r.db, representations, since)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to find address vaa ids",
+			zap.Error(err),
+			zap.String("address", address),
+			zap.String("requestID", requestID),
+		)
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	indexedAt := bson.M{"$lte": until}
+	if since != nil {
+		indexedAt["$gt"] = *since
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "_id", Value: bson.M{"$in": ids}},
+			{Key: "indexedAt", Value: indexedAt},
+		}}},
+		{{Key: "$lookup", Value: bson.D{{Key: "from", Value: "parsedVaa"}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "_id"}, {Key: "as", Value: "parsedVaa"}}}},
+		{{Key: "$lookup", Value: bson.D{{Key: "from", Value: "globalTransactions"}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "_id"}, {Key: "as", Value: "globalTransactions"}}}},
+		{{Key: "$lookup", Value: bson.D{{Key: "from", Value: "transferPrices"}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "_id"}, {Key: "as", Value: "transferPrices"}}}},
+		{{Key: "$addFields", Value: bson.D{
+			{Key: "parsedVaa", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$parsedVaa", 0}}}},
+			{Key: "globalTransactions", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$globalTransactions", 0}}}},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "indexedAt", Value: 1},
+			{Key: "timestamp", Value: 1},
+			{Key: "appIds", Value: "$parsedVaa.standardizedProperties.appIds"},
+			{Key: "fromChain", Value: "$parsedVaa.standardizedProperties.fromChain"},
+			{Key: "fromAddress", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$globalTransactions.originTx.from", "$parsedVaa.standardizedProperties.fromAddress"}}}},
+			{Key: "toChain", Value: "$parsedVaa.standardizedProperties.toChain"},
+			{Key: "toAddress", Value: "$parsedVaa.standardizedProperties.toAddress"},
+			{Key: "tokenChain", Value: "$parsedVaa.standardizedProperties.tokenChain"},
+			{Key: "tokenAddress", Value: "$parsedVaa.standardizedProperties.tokenAddress"},
+			{Key: "symbol", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$transferPrices.symbol", 0}}}},
+			{Key: "usdAmount", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$transferPrices.usdAmount", 0}}}},
+			{Key: "redeemed", Value: bson.D{{Key: "$gt", Value: bson.A{"$globalTransactions.destinationTx", nil}}}},
+		}}},
+	}
+
+	cur, err := r.collections.vaas.Aggregate(ctx, pipeline)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Aggregate command to get address transfers",
+			zap.Error(err),
+			zap.String("address", address),
+			zap.String("requestID", requestID),
+		)
+		return nil, err
+	}
+
+	var transfers []*AddressTransfer
+	if err := cur.All(ctx, &transfers); err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to decode cursor for address transfers",
+			zap.Error(err),
+			zap.String("address", address),
+			zap.String("requestID", requestID),
+		)
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// FindRedeemedVaaIDs returns the subset of ids whose transfer has a destination transaction.
+func (r *Repository) FindRedeemedVaaIDs(ctx context.Context, ids []string) ([]string, error) {
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: bson.M{"$in": ids}},
+		{Key: "destinationTx", Value: bson.M{"$ne": nil}},
+	}
+	cur, err := r.collections.globalTransactions.Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to find redeemed transfers",
+			zap.Error(err),
+			zap.String("requestID", requestID),
+		)
+		return nil, err
+	}
+
+	var documents []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cur.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	redeemed := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		redeemed = append(redeemed, doc.ID)
+	}
+	return redeemed, nil
+}
+
+// FindAddressRepresentations returns every known representation of an address.
+func (r *Repository) FindAddressRepresentations(ctx context.Context, address string) ([]string, error) {
+	representations, err := common.FindAddressRepresentations(ctx, r.db, address)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to find address representations",
+			zap.Error(err),
+			zap.String("address", address),
+			zap.String("requestID", requestID),
+		)
+		return nil, err
+	}
+	return representations, nil
+}
+
+type FindLinkedAddressesParams struct {
+	UniversalAddresses []string
+	Skip               int64
+	Limit              int64
+}
+
+// FindLinkedAddresses returns the addresses that sent transfers to, or received transfers from,
+// any of the given universal addresses, sorted by number of transfers.
+func (r *Repository) FindLinkedAddresses(ctx context.Context, params *FindLinkedAddressesParams) ([]*LinkedAddress, error) {
+
+	if len(params.UniversalAddresses) == 0 {
+		return nil, nil
+	}
+
+	isSender := bson.D{{Key: "$in", Value: bson.A{"$from", params.UniversalAddresses}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "from", Value: bson.M{"$in": params.UniversalAddresses}}},
+			bson.D{{Key: "to", Value: bson.M{"$in": params.UniversalAddresses}}},
+		}}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$cond", Value: bson.A{isSender, "$to", "$from"}}}},
+			{Key: "sentCount", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{isSender, 1, 0}}}}}},
+			{Key: "receivedCount", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{isSender, 0, 1}}}}}},
+			{Key: "chains", Value: bson.D{{Key: "$addToSet", Value: bson.D{{Key: "$cond", Value: bson.A{isSender, "$toChain", "$fromChain"}}}}}},
+			{Key: "firstSeen", Value: bson.D{{Key: "$min", Value: "$timestamp"}}},
+			{Key: "lastSeen", Value: bson.D{{Key: "$max", Value: "$timestamp"}}},
+		}}},
+		// a self transfer is not a link
+		{{Key: "$match", Value: bson.D{{Key: "_id", Value: bson.M{"$nin": params.UniversalAddresses}}}}},
+		{{Key: "$addFields", Value: bson.D{{Key: "total", Value: bson.D{{Key: "$add", Value: bson.A{"$sentCount", "$receivedCount"}}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$skip", Value: params.Skip}},
+		{{Key: "$limit", Value: params.Limit}},
+		{{Key: "$lookup", Value: bson.D{{Key: "from", Value: repository.AddressLinks}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "_id"}, {Key: "as", Value: "links"}}}},
+		{{Key: "$addFields", Value: bson.D{{Key: "representations", Value: bson.D{{Key: "$ifNull", Value: bson.A{bson.D{{Key: "$arrayElemAt", Value: bson.A{"$links.representations", 0}}}, bson.A{}}}}}}}},
+		{{Key: "$unset", Value: bson.A{"links", "total"}}},
+	}
+
+	cur, err := r.collections.addressGraph.Aggregate(ctx, pipeline)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Aggregate command to get linked addresses",
+			zap.Error(err),
+			zap.Any("params", params),
+			zap.String("requestID", requestID),
+		)
+		return nil, err
+	}
+
+	var links []*LinkedAddress
+	if err := cur.All(ctx, &links); err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to decode cursor for linked addresses",
+			zap.Error(err),
+			zap.Any("params", params),
+			zap.String("requestID", requestID),
+		)
+		return nil, err
+	}
+	return links, nil
+}
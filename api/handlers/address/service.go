@@ -2,6 +2,7 @@ package address
 
 import (
 	"context"
+	"time"
 
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/api/response"
@@ -44,3 +45,106 @@ func (s *Service) GetAddressOverview(
 	response.Data = overview
 	return response, nil
 }
+
+// addressStatsSettleDelay is the time to wait for a VAA to be parsed and its transactions
+// to be indexed before it is aggregated in the rollup of an address.
+const addressStatsSettleDelay = 5 * time.Minute
+
+// GetAddressStats returns the aggregated transfer activity of an address.
+//
+// The activity is kept in a per-address rollup, each call only aggregates the VAAs indexed
+// since the last refresh and rechecks the transfers that were pending redemption.
+func (s *Service) GetAddressStats(ctx context.Context, address string) (*AddressStats, error) {
+
+	stored, err := s.repo.FindAddressStats(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats *AddressStats
+	var prevWatermark *time.Time
+	if stored == nil {
+		stats = newAddressStats(address)
+	} else {
+		stats = stored.clone()
+		prevWatermark = stored.Watermark
+	}
+
+	representations, err := s.repo.FindAddressRepresentations(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	stats.setRepresentations(representations)
+
+	until := settledUntil(time.Now(), addressStatsSettleDelay)
+	if prevWatermark != nil && !until.After(*prevWatermark) {
+		stats.sort()
+		return stats, nil
+	}
+
+	redeemed, err := s.repo.FindRedeemedVaaIDs(ctx, stats.PendingVaaIDs)
+	if err != nil {
+		return nil, err
+	}
+	stats.removePending(redeemed)
+
+	transfers, err := s.repo.FindAddressTransfers(ctx, address, representations, prevWatermark, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range transfers {
+		stats.apply(t)
+	}
+	stats.sort()
+
+	now := time.Now()
+	stats.Watermark = &until
+	stats.UpdatedAt = &now
+	saved, err := s.repo.SaveAddressStats(ctx, stats, prevWatermark)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		// another request refreshed the rollup concurrently, return its version.
+		s.logger.Debug("address stats refreshed concurrently", zap.String("address", address))
+		latest, err := s.repo.FindAddressStats(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil {
+			latest.sort()
+			return latest, nil
+		}
+	}
+	return stats, nil
+}
+
+// GetAddressGraph returns the linked identity graph of an address.
+func (s *Service) GetAddressGraph(
+	ctx context.Context,
+	address string,
+	pagination *pagination.Pagination,
+) (*AddressGraph, error) {
+
+	representations, err := s.repo.FindAddressRepresentations(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	universalAddresses := universalAddresses(representations)
+	links, err := s.repo.FindLinkedAddresses(ctx, &FindLinkedAddressesParams{
+		UniversalAddresses: universalAddresses,
+		Skip:               pagination.Skip,
+		Limit:              pagination.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddressGraph{
+		Address:            address,
+		UniversalAddresses: universalAddresses,
+		Representations:    representations,
+		Links:              links,
+	}, nil
+}
@@ -0,0 +1,303 @@
+package address
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// the least active ones are aggregated in OtherCounterparties so that the document size stays bounded.
+// the least active ones are dropped so that the document size stays bounded.
+const maxCounterparties = 500
+
+// newAddressStats creates an empty rollup for an address.
+func newAddressStats(address string) *AddressStats {
+	return &AddressStats{
+		Address:          address,
+		TotalSentUsd:     "0",
+		TotalReceivedUsd: "0",
+	}
+}
+
+// apply aggregates a transfer into the rollup.
+func (s *AddressStats) apply(t *AddressTransfer) {
+
+	sent := s.matchAddress(t.FromAddress)
+	received := s.matchAddress(t.ToAddress)
+	if !sent && !received {
+		return
+	}
+
+	usd, err := decimal.NewFromString(t.UsdAmount)
+	if err != nil {
+		usd = decimal.Zero
+	}
+
+	if s.FirstActivity == nil || t.Timestamp.Before(*s.FirstActivity) {
+		ts := t.Timestamp
+		s.FirstActivity = &ts
+	}
+	if s.LastActivity == nil || t.Timestamp.After(*s.LastActivity) {
+		ts := t.Timestamp
+		s.LastActivity = &ts
+	}
+
+	token := s.token(t.TokenChain, t.TokenAddress)
+	if t.Symbol != "" {
+		token.Symbol = t.Symbol
+	}
+
+	if sent {
+		s.SentCount++
+		s.TotalSentUsd = addDecimal(s.TotalSentUsd, usd)
+
+		chain := s.chain(t.FromChain)
+		chain.SentCount++
+		chain.SentUsd = addDecimal(chain.SentUsd, usd)
+
+		token.SentCount++
+		token.SentUsd = addDecimal(token.SentUsd, usd)
+
+		if t.ToAddress != "" {
+			counterparty := s.counterparty(t.ToChain, t.ToAddress)
+			counterparty.Count++
+			counterparty.SentUsd = addDecimal(counterparty.SentUsd, usd)
+		}
+	}
+
+	if received {
+		s.ReceivedCount++
+		s.TotalReceivedUsd = addDecimal(s.TotalReceivedUsd, usd)
+
+		chain := s.chain(t.ToChain)
+		chain.ReceivedCount++
+		chain.ReceivedUsd = addDecimal(chain.ReceivedUsd, usd)
+
+		token.ReceivedCount++
+		token.ReceivedUsd = addDecimal(token.ReceivedUsd, usd)
+
+		if t.FromAddress != "" {
+			counterparty := s.counterparty(t.FromChain, t.FromAddress)
+			counterparty.Count++
+			counterparty.ReceivedUsd = addDecimal(counterparty.ReceivedUsd, usd)
+		}
+	}
+
+	for _, appID := range t.AppIDs {
+		protocol := s.protocol(appID)
+		protocol.Count++
+		protocol.VolumeUsd = addDecimal(protocol.VolumeUsd, usd)
+	}
+
+	if !t.Redeemed && t.ToChain != sdk.ChainIDUnset {
+		s.PendingVaaIDs = append(s.PendingVaaIDs, t.ID)
+	}
+}
+
+// removePending removes the redeemed transfers from the list of pending transfers.
+func (s *AddressStats) removePending(redeemed []string) {
+	if len(redeemed) == 0 {
+		return
+	}
+	ids := make(map[string]struct{}, len(redeemed))
+	for _, id := range redeemed {
+		ids[id] = struct{}{}
+	}
+	pending := s.PendingVaaIDs[:0]
+	for _, id := range s.PendingVaaIDs {
+		if _, ok := ids[id]; !ok {
+			pending = append(pending, id)
+		}
+	}
+	s.PendingVaaIDs = pending
+}
+
+// sort sorts the breakdowns by activity and aggregates the least active counterparties in OtherCounterparties.
+func (s *AddressStats) sort() {
+	sort.SliceStable(s.Chains, func(i, j int) bool {
+		return s.Chains[i].SentCount+s.Chains[i].ReceivedCount > s.Chains[j].SentCount+s.Chains[j].ReceivedCount
+	})
+	sort.SliceStable(s.Tokens, func(i, j int) bool {
+		return totalDecimal(s.Tokens[i].SentUsd, s.Tokens[i].ReceivedUsd).GreaterThan(totalDecimal(s.Tokens[j].SentUsd, s.Tokens[j].ReceivedUsd))
+	})
+	sort.SliceStable(s.Protocols, func(i, j int) bool {
+		return s.Protocols[i].Count > s.Protocols[j].Count
+	})
+	sort.SliceStable(s.Counterparties, func(i, j int) bool {
+		return s.Counterparties[i].Count > s.Counterparties[j].Count
+	})
+	if len(s.Counterparties) > maxCounterparties {
+		other := s.otherCounterparties()
+		for _, c := range s.Counterparties[maxCounterparties:] {
+			other.Count += c.Count
+			other.SentUsd = addDecimal(other.SentUsd, totalDecimal(c.SentUsd))
+			other.ReceivedUsd = addDecimal(other.ReceivedUsd, totalDecimal(c.ReceivedUsd))
+		}
+		s.Counterparties = s.Counterparties[:maxCounterparties]
+		s.CounterpartiesTruncated = true
+	}
+	s.PendingRedemptions = len(s.PendingVaaIDs)
+}
+
+func (s *AddressStats) chain(chainID sdk.ChainID) *ChainActivity {
+	for _, c := range s.Chains {
+		if c.ChainID == chainID {
+			return c
+		}
+	}
+	c := &ChainActivity{ChainID: chainID, SentUsd: "0", ReceivedUsd: "0"}
+	s.Chains = append(s.Chains, c)
+	return c
+}
+
+func (s *AddressStats) token(chainID sdk.ChainID, address string) *TokenActivity {
+	for _, t := range s.Tokens {
+		if t.TokenChain == chainID && t.TokenAddress == address {
+			return t
+		}
+	}
+	t := &TokenActivity{TokenChain: chainID, TokenAddress: address, SentUsd: "0", ReceivedUsd: "0"}
+	s.Tokens = append(s.Tokens, t)
+	return t
+}
+
+func (s *AddressStats) protocol(appID string) *ProtocolActivity {
+	for _, p := range s.Protocols {
+		if p.AppID == appID {
+			return p
+		}
+	}
+	p := &ProtocolActivity{AppID: appID, VolumeUsd: "0"}
+	s.Protocols = append(s.Protocols, p)
+	return p
+}
+
+func (s *AddressStats) counterparty(chainID sdk.ChainID, address string) *CounterpartyActivity {
+	for _, c := range s.Counterparties {
+		if c.ChainID == chainID && c.Address == address {
+			return c
+		}
+	}
+	// once the rollup is truncated, the activity with new counterparties is aggregated with the
+	// dropped ones, otherwise a dropped counterparty would come back with its latest transfers only.
+	if s.CounterpartiesTruncated {
+		return s.otherCounterparties()
+	}
+	c := &CounterpartyActivity{ChainID: chainID, Address: address, SentUsd: "0", ReceivedUsd: "0"}
+	s.Counterparties = append(s.Counterparties, c)
+	return c
+}
+
+func (s *AddressStats) otherCounterparties() *CounterpartyActivity {
+	if s.OtherCounterparties == nil {
+		s.OtherCounterparties = &CounterpartyActivity{SentUsd: "0", ReceivedUsd: "0"}
+	}
+	return s.OtherCounterparties
+}
+
+// clone returns a deep copy of the rollup.
+func (s *AddressStats) clone() *AddressStats {
+	c := *s
+	c.Chains = make([]*ChainActivity, 0, len(s.Chains))
+	for _, v := range s.Chains {
+		v := *v
+		c.Chains = append(c.Chains, &v)
+	}
+	c.Tokens = make([]*TokenActivity, 0, len(s.Tokens))
+	for _, v := range s.Tokens {
+		v := *v
+		c.Tokens = append(c.Tokens, &v)
+	}
+	c.Protocols = make([]*ProtocolActivity, 0, len(s.Protocols))
+	for _, v := range s.Protocols {
+		v := *v
+		c.Protocols = append(c.Protocols, &v)
+	}
+	c.Counterparties = make([]*CounterpartyActivity, 0, len(s.Counterparties))
+	for _, v := range s.Counterparties {
+		v := *v
+		c.Counterparties = append(c.Counterparties, &v)
+	}
+	if s.OtherCounterparties != nil {
+		v := *s.OtherCounterparties
+		c.OtherCounterparties = &v
+	}
+	c.PendingVaaIDs = append([]string(nil), s.PendingVaaIDs...)
+	return &c
+}
+
+// setRepresentations sets the known encodings of the address used to match the transfers.
+func (s *AddressStats) setRepresentations(representations []string) {
+	s.representations = make(map[string]bool, len(representations))
+	for _, r := range representations {
+		s.representations[r] = true
+	}
+}
+
+// matchAddress reports whether candidate is one of the representations of the address,
+// or the same address using the same rules as the address lookup (exact match or
+// lowercase hex with the 0x prefix).
+func (s *AddressStats) matchAddress(candidate string) bool {
+	if candidate == "" {
+		return false
+	}
+	if candidate == s.Address || s.representations[candidate] {
+		return true
+	}
+	return strings.ToLower(candidate) == addressHex(s.Address)
+}
+
+// addressHex returns the lowercase 0x-prefixed form of an address.
+func addressHex(address string) string {
+	addressHexa := strings.ToLower(address)
+	if !utils.StartsWith0x(address) {
+		addressHexa = "0x" + addressHexa
+	}
+	return addressHexa
+}
+
+func addDecimal(value string, amount decimal.Decimal) string {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		d = decimal.Zero
+	}
+	return d.Add(amount).String()
+}
+
+func totalDecimal(values ...string) decimal.Decimal {
+	total := decimal.Zero
+	for _, v := range values {
+		if d, err := decimal.NewFromString(v); err == nil {
+			total = total.Add(d)
+		}
+	}
+	return total
+}
+
+// settledUntil returns the upper bound of the VAAs that can be aggregated in the rollup.
+//
+// VAAs indexed in the last minutes are excluded because their parsedVaa and
+// globalTransactions documents may not have been written yet.
+func settledUntil(now time.Time, delay time.Duration) time.Time {
+	return now.Add(-delay).Truncate(time.Second)
+}
+
+// universalAddresses returns the universal addresses (32-byte lowercase hex without prefix)
+// found in a list of representations.
+func universalAddresses(representations []string) []string {
+	var universal []string
+	for _, r := range representations {
+		if len(r) != 64 || r != strings.ToLower(r) {
+			continue
+		}
+		if _, err := hex.DecodeString(r); err == nil {
+			universal = append(universal, r)
+		}
+	}
+	return universal
+}
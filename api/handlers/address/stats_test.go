@@ -0,0 +1,182 @@
+package address
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestAddressStats_Apply(t *testing.T) {
+	address := "0xF890982f9310df57d00f659cf4fd87e65adEd8d7"
+	now := time.Now().UTC()
+
+	stats := newAddressStats(address)
+	stats.apply(&AddressTransfer{
+		ID:           "2/000000000000000000000000f890982f9310df57d00f659cf4fd87e65aded8d7/1",
+		Timestamp:    now.Add(-time.Hour),
+		AppIDs:       []string{"PORTAL_TOKEN_BRIDGE"},
+		FromChain:    sdk.ChainIDEthereum,
+		FromAddress:  "0xf890982f9310df57d00f659cf4fd87e65aded8d7",
+		ToChain:      sdk.ChainIDSolana,
+		ToAddress:    "HV3AtRAqzRcwD5djYh8y3nwy3SzQgG5BbGEhYnMzfw5v",
+		TokenChain:   sdk.ChainIDEthereum,
+		TokenAddress: "000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		Symbol:       "WETH",
+		UsdAmount:    "1500.5",
+		Redeemed:     true,
+	})
+	stats.apply(&AddressTransfer{
+		ID:           "1/ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5/7",
+		Timestamp:    now,
+		AppIDs:       []string{"PORTAL_TOKEN_BRIDGE"},
+		FromChain:    sdk.ChainIDSolana,
+		FromAddress:  "HV3AtRAqzRcwD5djYh8y3nwy3SzQgG5BbGEhYnMzfw5v",
+		ToChain:      sdk.ChainIDEthereum,
+		ToAddress:    "0xf890982f9310df57d00f659cf4fd87e65aded8d7",
+		TokenChain:   sdk.ChainIDEthereum,
+		TokenAddress: "000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		UsdAmount:    "499.5",
+	})
+	// transfers of other addresses are ignored
+	stats.apply(&AddressTransfer{
+		ID:        "2/000000000000000000000000f890982f9310df57d00f659cf4fd87e65aded8d7/3",
+		Timestamp: now,
+		FromChain: sdk.ChainIDEthereum,
+		ToChain:   sdk.ChainIDSolana,
+		ToAddress: "HV3AtRAqzRcwD5djYh8y3nwy3SzQgG5BbGEhYnMzfw5v",
+		UsdAmount: "10",
+	})
+	stats.sort()
+
+	assert.Equal(t, "1500.5", stats.TotalSentUsd)
+	assert.Equal(t, "499.5", stats.TotalReceivedUsd)
+	assert.Equal(t, uint64(1), stats.SentCount)
+	assert.Equal(t, uint64(1), stats.ReceivedCount)
+	assert.Equal(t, now.Add(-time.Hour), *stats.FirstActivity)
+	assert.Equal(t, now, *stats.LastActivity)
+	assert.Equal(t, 1, stats.PendingRedemptions)
+
+	assert.Equal(t, []*ChainActivity{{
+		ChainID:       sdk.ChainIDEthereum,
+		SentUsd:       "1500.5",
+		ReceivedUsd:   "499.5",
+		SentCount:     1,
+		ReceivedCount: 1,
+	}}, stats.Chains)
+	assert.Len(t, stats.Tokens, 1)
+	assert.Equal(t, "WETH", stats.Tokens[0].Symbol)
+	assert.Equal(t, "1500.5", stats.Tokens[0].SentUsd)
+	assert.Equal(t, "499.5", stats.Tokens[0].ReceivedUsd)
+	assert.Equal(t, []*ProtocolActivity{{AppID: "PORTAL_TOKEN_BRIDGE", VolumeUsd: "2000", Count: 2}}, stats.Protocols)
+	assert.Equal(t, []*CounterpartyActivity{{
+		ChainID:     sdk.ChainIDSolana,
+		Address:     "HV3AtRAqzRcwD5djYh8y3nwy3SzQgG5BbGEhYnMzfw5v",
+		SentUsd:     "1500.5",
+		ReceivedUsd: "499.5",
+		Count:       2,
+	}}, stats.Counterparties)
+
+	stats.removePending([]string{"1/ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5/7"})
+	stats.sort()
+	assert.Equal(t, 0, stats.PendingRedemptions)
+}
+
+func TestAddressStats_Clone(t *testing.T) {
+	stats := newAddressStats("0xf890982f9310df57d00f659cf4fd87e65aded8d7")
+	stats.apply(&AddressTransfer{
+		ID:          "2/000000000000000000000000f890982f9310df57d00f659cf4fd87e65aded8d7/1",
+		AppIDs:      []string{"PORTAL_TOKEN_BRIDGE"},
+		FromChain:   sdk.ChainIDEthereum,
+		FromAddress: "0xf890982f9310df57d00f659cf4fd87e65aded8d7",
+		ToChain:     sdk.ChainIDSolana,
+		ToAddress:   "HV3AtRAqzRcwD5djYh8y3nwy3SzQgG5BbGEhYnMzfw5v",
+		UsdAmount:   "10",
+	})
+
+	c := stats.clone()
+	c.apply(&AddressTransfer{
+		ID:          "2/000000000000000000000000f890982f9310df57d00f659cf4fd87e65aded8d7/2",
+		AppIDs:      []string{"PORTAL_TOKEN_BRIDGE"},
+		FromChain:   sdk.ChainIDEthereum,
+		FromAddress: "0xf890982f9310df57d00f659cf4fd87e65aded8d7",
+		ToChain:     sdk.ChainIDSolana,
+		ToAddress:   "HV3AtRAqzRcwD5djYh8y3nwy3SzQgG5BbGEhYnMzfw5v",
+		UsdAmount:   "5",
+	})
+
+	assert.Equal(t, "10", stats.Chains[0].SentUsd)
+	assert.Equal(t, "15", c.Chains[0].SentUsd)
+	assert.Len(t, stats.PendingVaaIDs, 1)
+	assert.Len(t, c.PendingVaaIDs, 2)
+}
+
+func TestUniversalAddresses(t *testing.T) {
+	representations := []string{
+		"0x45dbea4617971d93188eda21530bc6503d153313",
+		"00000000000000000000000045dbea4617971d93188eda21530bc6503d153313",
+		"0x00000000000000000000000045dbea4617971d93188eda21530bc6503d153313",
+		"inj1ghd753shjuwexxywmgs4xz7x2q732vcnxxynfn",
+	}
+	assert.Equal(t, []string{"00000000000000000000000045dbea4617971d93188eda21530bc6503d153313"}, universalAddresses(representations))
+}
+
+func TestAddressStats_CounterpartiesTruncated(t *testing.T) {
+	stats := newAddressStats("0xf890982f9310df57d00f659cf4fd87e65aded8d7")
+	for i := 0; i <= maxCounterparties; i++ {
+		stats.apply(&AddressTransfer{
+			ID:          fmt.Sprintf("2/000000000000000000000000f890982f9310df57d00f659cf4fd87e65aded8d7/%d", i),
+			FromChain:   sdk.ChainIDEthereum,
+			FromAddress: "0xf890982f9310df57d00f659cf4fd87e65aded8d7",
+			ToChain:     sdk.ChainIDEthereum,
+			ToAddress:   fmt.Sprintf("0x%040x", i),
+			UsdAmount:   "1",
+		})
+	}
+	stats.sort()
+	assert.Len(t, stats.Counterparties, maxCounterparties)
+	assert.True(t, stats.CounterpartiesTruncated)
+	assert.Equal(t, &CounterpartyActivity{SentUsd: "1", ReceivedUsd: "0", Count: 1}, stats.OtherCounterparties)
+
+	// the transfers with counterparties that are not in the rollup are aggregated in the other
+	// counterparties, so a dropped counterparty does not come back with its latest transfers only.
+	dropped := fmt.Sprintf("0x%040x", maxCounterparties)
+	for _, counterparty := range []string{dropped, "0xa1a2a3a4a5a6a7a8a9a0b1b2b3b4b5b6b7b8b9b0"} {
+		stats.apply(&AddressTransfer{
+			ID:          "2/000000000000000000000000f890982f9310df57d00f659cf4fd87e65aded8d7/new",
+			FromChain:   sdk.ChainIDEthereum,
+			FromAddress: counterparty,
+			ToChain:     sdk.ChainIDEthereum,
+			ToAddress:   "0xf890982f9310df57d00f659cf4fd87e65aded8d7",
+			UsdAmount:   "2",
+		})
+	}
+	// the transfers with the counterparties in the rollup are still counted on them.
+	stats.apply(&AddressTransfer{
+		ID:          "2/000000000000000000000000f890982f9310df57d00f659cf4fd87e65aded8d7/kept",
+		FromChain:   sdk.ChainIDEthereum,
+		FromAddress: "0xf890982f9310df57d00f659cf4fd87e65aded8d7",
+		ToChain:     sdk.ChainIDEthereum,
+		ToAddress:   stats.Counterparties[0].Address,
+		UsdAmount:   "1",
+	})
+	stats.sort()
+	assert.Len(t, stats.Counterparties, maxCounterparties)
+	assert.Equal(t, uint64(2), stats.Counterparties[0].Count)
+	assert.Equal(t, &CounterpartyActivity{SentUsd: "1", ReceivedUsd: "4", Count: 3}, stats.OtherCounterparties)
+	for _, c := range stats.Counterparties {
+		assert.NotEqual(t, dropped, c.Address)
+	}
+
+	// the other counterparties are copied with the rollup.
+	clone := stats.clone()
+	clone.OtherCounterparties.Count++
+	assert.Equal(t, uint64(3), stats.OtherCounterparties.Count)
+
+	// the flag is kept after the rollup is refreshed.
+	stats.Counterparties = stats.Counterparties[:1]
+	stats.sort()
+	assert.True(t, stats.CounterpartiesTruncated)
+}
@@ -0,0 +1,87 @@
+package apikey
+
+import (
+	"time"
+)
+
+// Status is the state of an API key.
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusRevoked Status = "revoked"
+)
+
+// AllRouteGroups allows a tier to call every route group.
+const AllRouteGroups = "*"
+
+// Tier defines the quotas and the routes available to the API keys that belong to it.
+type Tier struct {
+	Name string `bson:"_id" json:"name"`
+	// PerMinute is the maximum number of requests per minute.
+	PerMinute int64 `bson:"perMinute" json:"perMinute"`
+	// Daily is the maximum number of requests per day (UTC). Zero means unlimited.
+	Daily int64 `bson:"daily" json:"daily"`
+	// RouteGroups are the route groups (e.g.: operations, vaas, exports) that can be called
+	// with the keys of the tier. Empty or "*" allows every route group.
+	RouteGroups []string  `bson:"routeGroups" json:"routeGroups"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// AllowsRouteGroup returns true if the keys of the tier can call the route group.
+func (t *Tier) AllowsRouteGroup(group string) bool {
+	if len(t.RouteGroups) == 0 {
+		return true
+	}
+	for _, g := range t.RouteGroups {
+		if g == AllRouteGroups || g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey is a named key. Only the SHA-256 hash of the secret is stored.
+type APIKey struct {
+	ID     string `bson:"_id" json:"id"`
+	Name   string `bson:"name" json:"name"`
+	Tier   string `bson:"tier" json:"tier"`
+	Status Status `bson:"status" json:"status"`
+	// Prefix is the beginning of the secret, used to identify the key.
+	Prefix string `bson:"prefix" json:"prefix"`
+	Hash   string `bson:"hash" json:"-"`
+	// PreviousHash is the hash of the secret replaced by the last rotation. It is valid
+	// until PreviousExpiresAt so that clients can switch to the new secret.
+	PreviousHash      string     `bson:"previousHash,omitempty" json:"-"`
+	PreviousExpiresAt *time.Time `bson:"previousExpiresAt,omitempty" json:"previousExpiresAt,omitempty"`
+	CreatedAt         time.Time  `bson:"createdAt" json:"createdAt"`
+	RotatedAt         *time.Time `bson:"rotatedAt,omitempty" json:"rotatedAt,omitempty"`
+	RevokedAt         *time.Time `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+}
+
+// NewAPIKey is the response of the creation or rotation of an API key. It is the
+// only time the secret is returned.
+type NewAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Usage is the number of requests of an API key in the current windows.
+type Usage struct {
+	Minute int64
+	Day    int64
+}
+
+// UsageReport is the usage of an API key.
+type UsageReport struct {
+	ID         string       `json:"id"`
+	Tier       string       `json:"tier"`
+	LastMinute int64        `json:"lastMinute"`
+	Daily      []DailyUsage `json:"daily"`
+}
+
+// DailyUsage is the number of requests of an API key in a day.
+type DailyUsage struct {
+	Date     string `json:"date"`
+	Requests int64  `json:"requests"`
+}
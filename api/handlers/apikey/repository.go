@@ -0,0 +1,142 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	apiKeysCollection     = "apiKeys"
+	apiKeyTiersCollection = "apiKeyTiers"
+)
+
+// Repository stores the API keys and tiers.
+type Repository struct {
+	db     *mongo.Database
+	logger *zap.Logger
+
+	collections struct {
+		apiKeys     *mongo.Collection
+		apiKeyTiers *mongo.Collection
+	}
+}
+
+// NewRepository creates a new API key repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger.With(zap.String("module", "ApiKeyRepository")),
+		collections: struct {
+			apiKeys     *mongo.Collection
+			apiKeyTiers *mongo.Collection
+		}{
+			apiKeys:     db.Collection(apiKeysCollection),
+			apiKeyTiers: db.Collection(apiKeyTiersCollection),
+		},
+	}
+}
+
+// FindTiers returns all the tiers.
+func (r *Repository) FindTiers(ctx context.Context) ([]Tier, error) {
+	cur, err := r.collections.apiKeyTiers.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		r.logger.Error("failed to find api key tiers", zap.Error(err))
+		return nil, err
+	}
+	var tiers []Tier
+	if err := cur.All(ctx, &tiers); err != nil {
+		r.logger.Error("failed to decode api key tiers", zap.Error(err))
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// UpsertTier creates or replaces a tier.
+func (r *Repository) UpsertTier(ctx context.Context, tier *Tier) error {
+	_, err := r.collections.apiKeyTiers.ReplaceOne(ctx, bson.D{{Key: "_id", Value: tier.Name}}, tier, options.Replace().SetUpsert(true))
+	if err != nil {
+		r.logger.Error("failed to upsert api key tier", zap.String("tier", tier.Name), zap.Error(err))
+	}
+	return err
+}
+
+// FindKeys returns all the API keys.
+func (r *Repository) FindKeys(ctx context.Context) ([]APIKey, error) {
+	cur, err := r.collections.apiKeys.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		r.logger.Error("failed to find api keys", zap.Error(err))
+		return nil, err
+	}
+	var keys []APIKey
+	if err := cur.All(ctx, &keys); err != nil {
+		r.logger.Error("failed to decode api keys", zap.Error(err))
+		return nil, err
+	}
+	return keys, nil
+}
+
+// FindKey returns an API key by id.
+func (r *Repository) FindKey(ctx context.Context, id string) (*APIKey, error) {
+	var key APIKey
+	err := r.collections.apiKeys.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errs.ErrNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to find api key", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &key, nil
+}
+
+// InsertKey saves a new API key.
+func (r *Repository) InsertKey(ctx context.Context, key *APIKey) error {
+	_, err := r.collections.apiKeys.InsertOne(ctx, key)
+	if err != nil {
+		r.logger.Error("failed to insert api key", zap.String("id", key.ID), zap.Error(err))
+	}
+	return err
+}
+
+// RotateKey replaces the hash of an active API key. The previous hash stays valid until previousExpiresAt.
+func (r *Repository) RotateKey(ctx context.Context, id, prefix, hash, previousHash string, previousExpiresAt, now time.Time) error {
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "status", Value: StatusActive}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "prefix", Value: prefix},
+		{Key: "hash", Value: hash},
+		{Key: "previousHash", Value: previousHash},
+		{Key: "previousExpiresAt", Value: previousExpiresAt},
+		{Key: "rotatedAt", Value: now},
+	}}}
+	return r.updateKey(ctx, id, filter, update)
+}
+
+// RevokeKey revokes an API key.
+func (r *Repository) RevokeKey(ctx context.Context, id string, now time.Time) error {
+	filter := bson.D{{Key: "_id", Value: id}}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "status", Value: StatusRevoked}, {Key: "revokedAt", Value: now}}},
+		{Key: "$unset", Value: bson.D{{Key: "previousHash", Value: ""}, {Key: "previousExpiresAt", Value: ""}}},
+	}
+	return r.updateKey(ctx, id, filter, update)
+}
+
+func (r *Repository) updateKey(ctx context.Context, id string, filter, update bson.D) error {
+	result, err := r.collections.apiKeys.UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.logger.Error("failed to update api key", zap.String("id", id), zap.Error(err))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("api key %s: %w", id, errs.ErrNotFound)
+	}
+	return nil
+}
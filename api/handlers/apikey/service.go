@@ -0,0 +1,337 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidKey is returned when the API key does not exist or was revoked.
+	ErrInvalidKey = errors.New("INVALID API KEY")
+	// ErrRouteNotAllowed is returned when the tier of the API key cannot call the route group.
+	ErrRouteNotAllowed = errors.New("ROUTE NOT ALLOWED FOR API KEY")
+	// ErrQuotaExceeded is returned when the API key exceeded its per-minute or daily quota.
+	ErrQuotaExceeded = errors.New("API KEY QUOTA EXCEEDED")
+	// ErrInvalidTier is returned when a tier does not exist or is not valid.
+	ErrInvalidTier = errors.New("INVALID API KEY TIER")
+)
+
+const (
+	// keyPrefix is the prefix of the generated secrets, it makes them easy to identify.
+	keyPrefix = "wsk_"
+	// maxUsageDays is the maximum number of days of the usage report.
+	maxUsageDays = 31
+)
+
+// Decision is the result of the authorization of a request.
+type Decision struct {
+	Key   *APIKey
+	Tier  *Tier
+	Usage Usage
+	// Reset is the end of the per-minute window.
+	Reset time.Time
+	// RetryAfter is set when the quota is exceeded.
+	RetryAfter time.Duration
+}
+
+// Service manages the API keys and authorizes the requests made with them.
+//
+// The keys and tiers are cached in memory and reloaded periodically, so rotations and
+// revocations made from any API instance apply to all of them without a restart.
+type Service struct {
+	repo            *Repository
+	usage           UsageStore
+	refreshInterval time.Duration
+	logger          *zap.Logger
+	now             func() time.Time
+
+	mu         sync.RWMutex
+	keysByHash map[string]*APIKey
+	tiers      map[string]*Tier
+}
+
+// NewService creates a new API key service.
+func NewService(repo *Repository, usage UsageStore, refreshInterval time.Duration, logger *zap.Logger) *Service {
+	if refreshInterval <= 0 {
+		refreshInterval = 30 * time.Second
+	}
+	return &Service{
+		repo:            repo,
+		usage:           usage,
+		refreshInterval: refreshInterval,
+		logger:          logger.With(zap.String("module", "ApiKeyService")),
+		now:             time.Now,
+		keysByHash:      make(map[string]*APIKey),
+		tiers:           make(map[string]*Tier),
+	}
+}
+
+// Start loads the keys and reloads them periodically until the context is done.
+func (s *Service) Start(ctx context.Context) error {
+	if err := s.reload(ctx); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(s.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.reload(ctx); err != nil {
+					s.logger.Error("failed to reload api keys", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// reload replaces the cached keys and tiers with the stored ones.
+func (s *Service) reload(ctx context.Context) error {
+	keys, err := s.repo.FindKeys(ctx)
+	if err != nil {
+		return err
+	}
+	tiers, err := s.repo.FindTiers(ctx)
+	if err != nil {
+		return err
+	}
+	s.setCache(keys, tiers)
+	return nil
+}
+
+func (s *Service) setCache(keys []APIKey, tiers []Tier) {
+	keysByHash := make(map[string]*APIKey, len(keys))
+	for i := range keys {
+		k := &keys[i]
+		if k.Status != StatusActive {
+			continue
+		}
+		keysByHash[k.Hash] = k
+		if k.PreviousHash != "" {
+			keysByHash[k.PreviousHash] = k
+		}
+	}
+	tiersByName := make(map[string]*Tier, len(tiers))
+	for i := range tiers {
+		tiersByName[tiers[i].Name] = &tiers[i]
+	}
+
+	s.mu.Lock()
+	s.keysByHash = keysByHash
+	s.tiers = tiersByName
+	s.mu.Unlock()
+}
+
+// Authorize checks that a request to the route group can be made with the secret and counts it.
+//
+// The decision is returned along with ErrQuotaExceeded, so the caller can report the limits.
+// When the usage cannot be counted the request is allowed.
+func (s *Service) Authorize(ctx context.Context, secret, routeGroup string) (*Decision, error) {
+
+	now := s.now()
+	hash := hashSecret(secret)
+
+	s.mu.RLock()
+	key, ok := s.keysByHash[hash]
+	var tier *Tier
+	if ok {
+		tier = s.tiers[key.Tier]
+	}
+	s.mu.RUnlock()
+
+	if !ok || (hash == key.PreviousHash && (key.PreviousExpiresAt == nil || !now.Before(*key.PreviousExpiresAt))) {
+		return nil, ErrInvalidKey
+	}
+	if tier == nil {
+		s.logger.Error("api key tier not found", zap.String("id", key.ID), zap.String("tier", key.Tier))
+		return nil, ErrInvalidTier
+	}
+	if !tier.AllowsRouteGroup(routeGroup) {
+		return nil, ErrRouteNotAllowed
+	}
+
+	decision := &Decision{Key: key, Tier: tier, Reset: now.Truncate(time.Minute).Add(time.Minute)}
+
+	usage, err := s.usage.Incr(ctx, key.ID, now)
+	if err != nil {
+		s.logger.Error("failed to count api key usage", zap.String("id", key.ID), zap.Error(err))
+		return decision, nil
+	}
+	decision.Usage = usage
+
+	if tier.PerMinute > 0 && usage.Minute > tier.PerMinute {
+		decision.RetryAfter = decision.Reset.Sub(now)
+		return decision, ErrQuotaExceeded
+	}
+	if tier.Daily > 0 && usage.Day > tier.Daily {
+		nextDay := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		decision.RetryAfter = nextDay.Sub(now)
+		return decision, ErrQuotaExceeded
+	}
+	return decision, nil
+}
+
+// ListTiers returns all the tiers.
+func (s *Service) ListTiers(ctx context.Context) ([]Tier, error) {
+	return s.repo.FindTiers(ctx)
+}
+
+// UpsertTier creates or replaces a tier.
+func (s *Service) UpsertTier(ctx context.Context, tier *Tier) error {
+	if tier.Name == "" || tier.PerMinute < 0 || tier.Daily < 0 {
+		return fmt.Errorf("%w: name is required and quotas cannot be negative", ErrInvalidTier)
+	}
+	tier.UpdatedAt = s.now()
+	if err := s.repo.UpsertTier(ctx, tier); err != nil {
+		return err
+	}
+	return s.reload(ctx)
+}
+
+// ListKeys returns all the API keys.
+func (s *Service) ListKeys(ctx context.Context) ([]APIKey, error) {
+	return s.repo.FindKeys(ctx)
+}
+
+// GetKey returns an API key.
+func (s *Service) GetKey(ctx context.Context, id string) (*APIKey, error) {
+	return s.repo.FindKey(ctx, id)
+}
+
+// CreateKey creates a new API key in the given tier.
+func (s *Service) CreateKey(ctx context.Context, name, tier string) (*NewAPIKey, error) {
+
+	if err := s.checkTier(ctx, tier); err != nil {
+		return nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	key := APIKey{
+		ID:        primitive.NewObjectID().Hex(),
+		Name:      name,
+		Tier:      tier,
+		Status:    StatusActive,
+		Prefix:    secret[:len(keyPrefix)+6],
+		Hash:      hashSecret(secret),
+		CreatedAt: s.now(),
+	}
+	if err := s.repo.InsertKey(ctx, &key); err != nil {
+		return nil, err
+	}
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+	return &NewAPIKey{APIKey: key, Key: secret}, nil
+}
+
+// RotateKey replaces the secret of an API key. The previous secret is valid during the grace period.
+func (s *Service) RotateKey(ctx context.Context, id string, grace time.Duration) (*NewAPIKey, error) {
+
+	key, err := s.repo.FindKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.Status != StatusActive {
+		return nil, fmt.Errorf("api key %s is %s: %w", id, key.Status, ErrInvalidKey)
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	prefix, hash := secret[:len(keyPrefix)+6], hashSecret(secret)
+	if err := s.repo.RotateKey(ctx, id, prefix, hash, key.Hash, now.Add(grace), now); err != nil {
+		return nil, err
+	}
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	previousExpiresAt := now.Add(grace)
+	key.Prefix, key.Hash = prefix, hash
+	key.PreviousExpiresAt, key.RotatedAt = &previousExpiresAt, &now
+	return &NewAPIKey{APIKey: *key, Key: secret}, nil
+}
+
+// RevokeKey revokes an API key.
+func (s *Service) RevokeKey(ctx context.Context, id string) error {
+	if err := s.repo.RevokeKey(ctx, id, s.now()); err != nil {
+		return err
+	}
+	return s.reload(ctx)
+}
+
+// GetUsage returns the usage of an API key in the current minute and the last days.
+func (s *Service) GetUsage(ctx context.Context, id string, days int) (*UsageReport, error) {
+
+	key, err := s.repo.FindKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if days <= 0 || days > maxUsageDays {
+		days = maxUsageDays
+	}
+
+	now := s.now().UTC()
+	minute, err := s.usage.Minute(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]time.Time, 0, days)
+	for i := 0; i < days; i++ {
+		dates = append(dates, now.AddDate(0, 0, -i))
+	}
+	counts, err := s.usage.Days(ctx, id, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	report := UsageReport{ID: key.ID, Tier: key.Tier, LastMinute: minute}
+	for i, d := range dates {
+		report.Daily = append(report.Daily, DailyUsage{Date: d.Format(time.DateOnly), Requests: counts[i]})
+	}
+	return &report, nil
+}
+
+func (s *Service) checkTier(ctx context.Context, name string) error {
+	tiers, err := s.repo.FindTiers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range tiers {
+		if t.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidTier, name)
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,133 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type memoryUsageStore struct {
+	counts map[string]int64
+	err    error
+}
+
+func (m *memoryUsageStore) Incr(_ context.Context, id string, now time.Time) (Usage, error) {
+	if m.err != nil {
+		return Usage{}, m.err
+	}
+	minuteKey := id + now.UTC().Format(minuteLayout)
+	dayKey := id + now.UTC().Format(dayLayout)
+	m.counts[minuteKey]++
+	m.counts[dayKey]++
+	return Usage{Minute: m.counts[minuteKey], Day: m.counts[dayKey]}, nil
+}
+
+func (m *memoryUsageStore) Minute(_ context.Context, id string, t time.Time) (int64, error) {
+	return m.counts[id+t.UTC().Format(minuteLayout)], nil
+}
+
+func (m *memoryUsageStore) Days(_ context.Context, id string, days []time.Time) ([]int64, error) {
+	result := make([]int64, 0, len(days))
+	for _, d := range days {
+		result = append(result, m.counts[id+d.UTC().Format(dayLayout)])
+	}
+	return result, nil
+}
+
+func newTestService(now time.Time, usage UsageStore) *Service {
+	s := NewService(nil, usage, time.Minute, zap.NewNop())
+	s.now = func() time.Time { return now }
+	previousExpiresAt := now.Add(time.Hour)
+	s.setCache(
+		[]APIKey{
+			{ID: "1", Tier: "basic", Status: StatusActive, Hash: hashSecret("secret-1")},
+			{ID: "2", Tier: "exports", Status: StatusActive, Hash: hashSecret("secret-2"),
+				PreviousHash: hashSecret("old-secret-2"), PreviousExpiresAt: &previousExpiresAt},
+			{ID: "3", Tier: "basic", Status: StatusRevoked, Hash: hashSecret("secret-3")},
+			{ID: "4", Tier: "missing", Status: StatusActive, Hash: hashSecret("secret-4")},
+		},
+		[]Tier{
+			{Name: "basic", PerMinute: 2, Daily: 3},
+			{Name: "exports", PerMinute: 10, RouteGroups: []string{"exports"}},
+		})
+	return s
+}
+
+func TestService_Authorize(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 30, 15, 0, time.UTC)
+	s := newTestService(now, &memoryUsageStore{counts: map[string]int64{}})
+
+	d, err := s.Authorize(ctx, "secret-1", "operations")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", d.Key.ID)
+	assert.Equal(t, Usage{Minute: 1, Day: 1}, d.Usage)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 31, 0, 0, time.UTC), d.Reset)
+
+	_, err = s.Authorize(ctx, "secret-1", "operations")
+	assert.NoError(t, err)
+
+	// per-minute quota
+	d, err = s.Authorize(ctx, "secret-1", "operations")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Equal(t, 45*time.Second, d.RetryAfter)
+
+	// daily quota, in the next minute
+	s.now = func() time.Time { return now.Add(time.Minute) }
+	d, err = s.Authorize(ctx, "secret-1", "operations")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Equal(t, Usage{Minute: 1, Day: 4}, d.Usage)
+	assert.Equal(t, 13*time.Hour+28*time.Minute+45*time.Second, d.RetryAfter)
+}
+
+func TestService_Authorize_Denied(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	s := newTestService(now, &memoryUsageStore{counts: map[string]int64{}})
+
+	_, err := s.Authorize(ctx, "unknown", "operations")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = s.Authorize(ctx, "secret-3", "operations")
+	assert.ErrorIs(t, err, ErrInvalidKey, "revoked key")
+
+	_, err = s.Authorize(ctx, "secret-4", "operations")
+	assert.ErrorIs(t, err, ErrInvalidTier)
+
+	_, err = s.Authorize(ctx, "secret-2", "operations")
+	assert.ErrorIs(t, err, ErrRouteNotAllowed)
+
+	_, err = s.Authorize(ctx, "secret-2", "exports")
+	assert.NoError(t, err)
+}
+
+func TestService_Authorize_Rotation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	s := newTestService(now, &memoryUsageStore{counts: map[string]int64{}})
+
+	d, err := s.Authorize(ctx, "old-secret-2", "exports")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", d.Key.ID)
+
+	// the previous secret expires after the grace period.
+	s.now = func() time.Time { return now.Add(time.Hour) }
+	_, err = s.Authorize(ctx, "old-secret-2", "exports")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = s.Authorize(ctx, "secret-2", "exports")
+	assert.NoError(t, err)
+}
+
+func TestService_Authorize_UsageStoreError(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	s := newTestService(now, &memoryUsageStore{err: errors.New("redis unavailable")})
+
+	d, err := s.Authorize(ctx, "secret-1", "operations")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", d.Key.ID)
+}
@@ -0,0 +1,91 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	minuteLayout = "200601021504"
+	dayLayout    = "20060102"
+	// usageRetention is the time the daily counters are kept.
+	usageRetention = 32 * 24 * time.Hour
+)
+
+// UsageStore counts the requests of the API keys.
+type UsageStore interface {
+	// Incr counts a request of an API key and returns the usage of the current windows.
+	Incr(ctx context.Context, id string, now time.Time) (Usage, error)
+	// Minute returns the number of requests of an API key in the minute of t.
+	Minute(ctx context.Context, id string, t time.Time) (int64, error)
+	// Days returns the number of requests of an API key in each of the given days.
+	Days(ctx context.Context, id string, days []time.Time) ([]int64, error)
+}
+
+// RedisUsageStore keeps the usage counters in redis, so they are shared by all the API instances.
+type RedisUsageStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisUsageStore creates a new usage store.
+func NewRedisUsageStore(client *redis.Client, prefix string) *RedisUsageStore {
+	return &RedisUsageStore{client: client, prefix: prefix}
+}
+
+func (s *RedisUsageStore) minuteKey(id string, t time.Time) string {
+	return fmt.Sprintf("%sapi-key:%s:m:%s", s.prefix, id, t.UTC().Format(minuteLayout))
+}
+
+func (s *RedisUsageStore) dayKey(id string, t time.Time) string {
+	return fmt.Sprintf("%sapi-key:%s:d:%s", s.prefix, id, t.UTC().Format(dayLayout))
+}
+
+// Incr counts a request of an API key and returns the usage of the current windows.
+func (s *RedisUsageStore) Incr(ctx context.Context, id string, now time.Time) (Usage, error) {
+	var minute, day *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		minuteKey, dayKey := s.minuteKey(id, now), s.dayKey(id, now)
+		minute = pipe.Incr(ctx, minuteKey)
+		pipe.Expire(ctx, minuteKey, 2*time.Minute)
+		day = pipe.Incr(ctx, dayKey)
+		pipe.Expire(ctx, dayKey, usageRetention)
+		return nil
+	})
+	if err != nil {
+		return Usage{}, err
+	}
+	return Usage{Minute: minute.Val(), Day: day.Val()}, nil
+}
+
+// Minute returns the number of requests of an API key in the minute of t.
+func (s *RedisUsageStore) Minute(ctx context.Context, id string, t time.Time) (int64, error) {
+	v, err := s.client.Get(ctx, s.minuteKey(id, t)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return v, err
+}
+
+// Days returns the number of requests of an API key in each of the given days.
+func (s *RedisUsageStore) Days(ctx context.Context, id string, days []time.Time) ([]int64, error) {
+	keys := make([]string, 0, len(days))
+	for _, d := range days {
+		keys = append(keys, s.dayKey(id, d))
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int64, len(values))
+	for i, v := range values {
+		if str, ok := v.(string); ok {
+			result[i], _ = strconv.ParseInt(str, 10, 64)
+		}
+	}
+	return result, nil
+}
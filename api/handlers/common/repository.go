@@ -3,7 +3,10 @@ package common
 import (
 	"context"
 	"strings"
+	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
 	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -13,25 +16,78 @@ type mongoID struct {
 	Id string `bson:"_id"`
 }
 
+// FindAddressRepresentations returns every known representation of an address.
+//
+// The representations are read from the address links written by the parser, which relate
+// the native encodings seen in transfers with the universal address. Addresses that have not
+// been linked yet are expanded with the chain encoders.
+func FindAddressRepresentations(ctx context.Context, db *mongo.Database, address string) ([]string, error) {
+	addressHexa := strings.ToLower(address)
+	if !utils.StartsWith0x(address) {
+		addressHexa = "0x" + strings.ToLower(addressHexa)
+	}
+
+	seen := make(map[string]bool)
+	var representations []string
+	add := func(values ...string) {
+		for _, v := range values {
+			if v != "" && !seen[v] {
+				seen[v] = true
+				representations = append(representations, v)
+			}
+		}
+	}
+	add(address, addressHexa)
+	add(domain.AddressRepresentations(address)...)
+
+	filter := bson.D{{Key: "representations", Value: bson.M{"$in": bson.A{address, addressHexa}}}}
+	cur, err := db.Collection(repository.AddressLinks).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var links []struct {
+		Representations []string `bson:"representations"`
+	}
+	if err := cur.All(ctx, &links); err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		add(link.Representations...)
+	}
+	return representations, nil
+}
+
 func FindVaasIdsByFromAddressOrToAddress(
 	ctx context.Context,
 	db *mongo.Database,
 	address string,
 ) ([]string, error) {
-	addressHexa := strings.ToLower(address)
-	if !utils.StartsWith0x(address) {
-		addressHexa = "0x" + strings.ToLower(addressHexa)
+	representations, err := FindAddressRepresentations(ctx, db, address)
+	if err != nil {
+		return nil, err
 	}
+	return FindVaasIdsByRepresentations(ctx, db, representations, nil)
+}
 
-	matchForToAddress := bson.D{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
-		bson.D{{Key: "standardizedProperties.toAddress", Value: bson.M{"$eq": addressHexa}}},
-		bson.D{{Key: "standardizedProperties.toAddress", Value: bson.M{"$eq": address}}},
-	}}}}}
+// FindVaasIdsByRepresentations returns the ids of the VAAs sent from or to any of the representations
+// of an address. When since is not nil, only the VAAs whose parsedVaa or globalTransactions document
+// was updated after it are returned: both are written after the VAA is indexed, so the result holds
+// every VAA indexed after since.
+func FindVaasIdsByRepresentations(
+	ctx context.Context,
+	db *mongo.Database,
+	representations []string,
+	since *time.Time,
+) ([]string, error) {
 
-	matchForFromAddress := bson.D{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
-		bson.D{{Key: "originTx.from", Value: bson.M{"$eq": addressHexa}}},
-		bson.D{{Key: "originTx.from", Value: bson.M{"$eq": address}}},
-	}}}}}
+	toAddress := bson.D{{Key: "standardizedProperties.toAddress", Value: bson.M{"$in": representations}}}
+	fromAddress := bson.D{{Key: "originTx.from", Value: bson.M{"$in": representations}}}
+	if since != nil {
+		toAddress = append(toAddress, bson.E{Key: "updatedAt", Value: bson.M{"$gt": *since}})
+		fromAddress = append(fromAddress, bson.E{Key: "originTx.updatedAt", Value: bson.M{"$gt": *since}})
+	}
+	matchForToAddress := bson.D{{Key: "$match", Value: toAddress}}
+	matchForFromAddress := bson.D{{Key: "$match", Value: fromAddress}}
 
 	toAddressFilter := bson.D{{Key: "$unionWith", Value: bson.D{{Key: "coll", Value: "parsedVaa"}, {Key: "pipeline", Value: bson.A{matchForToAddress}}}}}
 	fromAddressFilter := bson.D{{Key: "$unionWith", Value: bson.D{{Key: "coll", Value: "globalTransactions"}, {Key: "pipeline", Value: bson.A{matchForFromAddress}}}}}
@@ -0,0 +1,50 @@
+package emitter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Source values of an emitter document.
+const (
+	SourceSeed  = "seed"
+	SourceAdmin = "admin"
+)
+
+// EmitterDoc represents an emitter of the registry.
+type EmitterDoc struct {
+	ID            string      `bson:"_id" json:"id"`
+	EmitterChain  vaa.ChainID `bson:"emitterChain" json:"emitterChain"`
+	EmitterAddr   string      `bson:"emitterAddr" json:"emitterAddr"`
+	NativeAddress string      `bson:"nativeAddress" json:"nativeAddress,omitempty"`
+	AppID         string      `bson:"appId" json:"appId,omitempty"`
+	Name          string      `bson:"name" json:"name,omitempty"`
+	Owner         string      `bson:"owner" json:"owner,omitempty"`
+	Source        string      `bson:"source" json:"source"`
+	CreatedAt     *time.Time  `bson:"createdAt" json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time  `bson:"updatedAt" json:"updatedAt,omitempty"`
+	// MessageCount is computed on read, it is not stored.
+	MessageCount *int64 `bson:"-" json:"messageCount,omitempty"`
+}
+
+// Info is the emitter information added to the VAA and operation responses.
+type Info struct {
+	AppID string `json:"appId,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Owner string `json:"owner,omitempty"`
+}
+
+// EmitterQuery contains the filters of an emitter search.
+type EmitterQuery struct {
+	Pagination *pagination.Pagination
+	ChainID    *vaa.ChainID
+	AppID      string
+}
+
+// emitterID returns the document id of an emitter.
+func emitterID(chainID vaa.ChainID, address string) string {
+	return fmt.Sprintf("%d/%s", chainID, address)
+}
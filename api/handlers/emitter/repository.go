@@ -0,0 +1,155 @@
+package emitter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository definition.
+type Repository struct {
+	db          *mongo.Database
+	logger      *zap.Logger
+	collections struct {
+		emitters *mongo.Collection
+		vaas     *mongo.Collection
+	}
+}
+
+// NewRepository create a new Repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{db: db,
+		logger: logger.With(zap.String("module", "EmitterRepository")),
+		collections: struct {
+			emitters *mongo.Collection
+			vaas     *mongo.Collection
+		}{
+			emitters: db.Collection(repository.Emitters),
+			vaas:     db.Collection(repository.Vaas),
+		},
+	}
+}
+
+// FindAll get the emitters matching the query, sorted by chain and address.
+func (r *Repository) FindAll(ctx context.Context, q *EmitterQuery) ([]*EmitterDoc, error) {
+	filter := bson.D{}
+	if q.ChainID != nil {
+		filter = append(filter, bson.E{Key: "emitterChain", Value: *q.ChainID})
+	}
+	if q.AppID != "" {
+		filter = append(filter, bson.E{Key: "appId", Value: q.AppID})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "emitterChain", Value: 1}, {Key: "emitterAddr", Value: 1}})
+	if q.Pagination != nil {
+		opts.SetLimit(q.Pagination.Limit).SetSkip(q.Pagination.Skip)
+	}
+	cur, err := r.collections.emitters.Find(ctx, filter, opts)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Find command to get emitters",
+			zap.Error(err), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	var emitters []*EmitterDoc
+	if err := cur.All(ctx, &emitters); err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed decoding cursor to []*EmitterDoc", zap.Error(err),
+			zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	// If no results were found, return an empty slice instead of nil.
+	if emitters == nil {
+		emitters = make([]*EmitterDoc, 0)
+	}
+	return emitters, nil
+}
+
+// FindOne get an emitter by chain and address.
+func (r *Repository) FindOne(ctx context.Context, chainID vaa.ChainID, address string) (*EmitterDoc, error) {
+	var doc EmitterDoc
+	err := r.collections.emitters.FindOne(ctx, bson.M{"_id": emitterID(chainID, address)}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errs.ErrNotFound
+		}
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute FindOne command to get emitter",
+			zap.Error(err), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+	return &doc, nil
+}
+
+// Upsert creates or replaces the editable fields of an emitter.
+func (r *Repository) Upsert(ctx context.Context, doc *EmitterDoc) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"emitterChain":  doc.EmitterChain,
+			"emitterAddr":   doc.EmitterAddr,
+			"nativeAddress": doc.NativeAddress,
+			"appId":         doc.AppID,
+			"name":          doc.Name,
+			"owner":         doc.Owner,
+			"source":        doc.Source,
+			"updatedAt":     now,
+		},
+		"$setOnInsert": bson.M{"createdAt": now},
+	}
+	_, err := r.collections.emitters.UpdateByID(ctx, doc.ID, update, options.Update().SetUpsert(true))
+	return errors.WithStack(err)
+}
+
+// Insert creates an emitter only if it does not exist, so the changes made through the admin API are kept.
+func (r *Repository) Insert(ctx context.Context, doc *EmitterDoc) error {
+	now := time.Now()
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"emitterChain":  doc.EmitterChain,
+			"emitterAddr":   doc.EmitterAddr,
+			"nativeAddress": doc.NativeAddress,
+			"appId":         doc.AppID,
+			"name":          doc.Name,
+			"owner":         doc.Owner,
+			"source":        doc.Source,
+			"createdAt":     now,
+			"updatedAt":     now,
+		},
+	}
+	_, err := r.collections.emitters.UpdateByID(ctx, doc.ID, update, options.Update().SetUpsert(true))
+	return errors.WithStack(err)
+}
+
+// Delete removes an emitter.
+func (r *Repository) Delete(ctx context.Context, chainID vaa.ChainID, address string) error {
+	res, err := r.collections.emitters.DeleteOne(ctx, bson.M{"_id": emitterID(chainID, address)})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if res.DeletedCount == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+// CountMessages get the number of VAAs emitted by an emitter.
+func (r *Repository) CountMessages(ctx context.Context, chainID vaa.ChainID, address string) (int64, error) {
+	filter := bson.D{
+		{Key: "emitterChain", Value: chainID},
+		{Key: "emitterAddr", Value: address},
+	}
+	count, err := r.collections.vaas.CountDocuments(ctx, filter)
+	return count, errors.WithStack(err)
+}
@@ -0,0 +1,241 @@
+package emitter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrInvalidEmitter is returned when an emitter sent to the admin API is not valid.
+var ErrInvalidEmitter = errors.New("invalid emitter")
+
+// reloadRetryDelay is the time to wait before reloading the emitters again after a failure.
+const reloadRetryDelay = 10 * time.Second
+
+// Service definition.
+type Service struct {
+	repo       *Repository
+	refresh    time.Duration
+	mu         sync.RWMutex
+	emitters   map[string]*Info
+	loaded     bool
+	reloadedAt time.Time
+	counts     map[string]messageCount
+	group      singleflight.Group
+	logger     *zap.Logger
+}
+
+// messageCount is the number of messages of an emitter at a given time.
+type messageCount struct {
+	count     int64
+	countedAt time.Time
+}
+
+// NewService create a new emitter.Service. The emitters used to enrich the responses
+// and the message counts of the listed emitters are kept in memory and reloaded from
+// the repository every refresh interval.
+func NewService(repo *Repository, refresh time.Duration, logger *zap.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		refresh:  refresh,
+		emitters: make(map[string]*Info),
+		counts:   make(map[string]messageCount),
+		logger:   logger.With(zap.String("module", "EmitterService")),
+	}
+}
+
+// Seed stores the known emitters that are not in the registry yet.
+func (s *Service) Seed(ctx context.Context, known []domain.KnownEmitter) error {
+	for _, k := range known {
+		doc := newEmitterDoc(k.ChainID, k.Address, SourceSeed)
+		doc.AppID = k.AppID
+		doc.Name = k.Name
+		doc.Owner = k.Owner
+		if err := s.repo.Insert(ctx, doc); err != nil {
+			return err
+		}
+	}
+	s.invalidate()
+	return nil
+}
+
+// FindAll get the emitters matching the query along with their message counts.
+// The counts are cached for the refresh interval.
+func (s *Service) FindAll(ctx context.Context, q *EmitterQuery) ([]*EmitterDoc, error) {
+	emitters, err := s.repo.FindAll(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range emitters {
+		count, err := s.countMessages(ctx, e)
+		if err != nil {
+			s.logger.Warn("failed to count emitter messages", zap.String("id", e.ID), zap.Error(err))
+			continue
+		}
+		e.MessageCount = &count
+	}
+	return emitters, nil
+}
+
+// FindOne get an emitter along with its message count.
+func (s *Service) FindOne(ctx context.Context, chainID vaa.ChainID, address string) (*EmitterDoc, error) {
+	e, err := s.repo.FindOne(ctx, chainID, normalizeAddress(address))
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.repo.CountMessages(ctx, e.EmitterChain, e.EmitterAddr)
+	if err != nil {
+		return nil, err
+	}
+	e.MessageCount = &count
+	return e, nil
+}
+
+// countMessages returns the cached message count of an emitter, counting the messages
+// once for the concurrent requests when the cached count is stale.
+func (s *Service) countMessages(ctx context.Context, e *EmitterDoc) (int64, error) {
+	s.mu.RLock()
+	c, ok := s.counts[e.ID]
+	s.mu.RUnlock()
+	if ok && time.Since(c.countedAt) <= s.refresh {
+		return c.count, nil
+	}
+
+	v, err, _ := s.group.Do("count:"+e.ID, func() (interface{}, error) {
+		count, err := s.repo.CountMessages(ctx, e.EmitterChain, e.EmitterAddr)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.counts[e.ID] = messageCount{count: count, countedAt: time.Now()}
+		s.mu.Unlock()
+		return count, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int64), nil
+}
+
+// Upsert creates or replaces an emitter through the admin API.
+func (s *Service) Upsert(ctx context.Context, chainID vaa.ChainID, address string, e *EmitterDoc) (*EmitterDoc, error) {
+	address = normalizeAddress(address)
+	if !domain.ChainIdIsValid(chainID) || len(address) != 64 {
+		return nil, ErrInvalidEmitter
+	}
+	doc := newEmitterDoc(chainID, address, SourceAdmin)
+	doc.AppID = e.AppID
+	doc.Name = e.Name
+	doc.Owner = e.Owner
+	if e.NativeAddress != "" {
+		doc.NativeAddress = e.NativeAddress
+	}
+	if err := s.repo.Upsert(ctx, doc); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return s.repo.FindOne(ctx, chainID, address)
+}
+
+// Delete removes an emitter through the admin API.
+func (s *Service) Delete(ctx context.Context, chainID vaa.ChainID, address string) error {
+	if err := s.repo.Delete(ctx, chainID, normalizeAddress(address)); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Lookup returns the registry information of an emitter, or nil if it is not registered.
+//
+// A single reload runs at a time when the emitters are stale. Once the emitters have been
+// loaded, the requests do not wait for the reload and get the previous emitters meanwhile.
+func (s *Service) Lookup(ctx context.Context, chainID vaa.ChainID, address string) *Info {
+	id := emitterID(chainID, normalizeAddress(address))
+	s.mu.RLock()
+	stale := time.Since(s.reloadedAt) > s.refresh
+	loaded := s.loaded
+	info := s.emitters[id]
+	s.mu.RUnlock()
+	if !stale {
+		return info
+	}
+
+	reloaded := s.group.DoChan("emitters", func() (interface{}, error) {
+		err := s.reload(context.WithoutCancel(ctx))
+		if err != nil {
+			s.logger.Error("failed to reload emitters", zap.Error(err))
+			// retry after reloadRetryDelay instead of on every request.
+			s.mu.Lock()
+			if s.loaded {
+				s.reloadedAt = time.Now().Add(reloadRetryDelay - s.refresh)
+			}
+			s.mu.Unlock()
+		}
+		return nil, err
+	})
+	if loaded {
+		return info
+	}
+	select {
+	case <-ctx.Done():
+		return info
+	case r := <-reloaded:
+		if r.Err != nil {
+			return info
+		}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.emitters[id]
+}
+
+func (s *Service) reload(ctx context.Context) error {
+	docs, err := s.repo.FindAll(ctx, &EmitterQuery{})
+	if err != nil {
+		return err
+	}
+	emitters := make(map[string]*Info, len(docs))
+	for _, d := range docs {
+		emitters[d.ID] = &Info{AppID: d.AppID, Name: d.Name, Owner: d.Owner}
+	}
+	s.mu.Lock()
+	s.emitters = emitters
+	s.loaded = true
+	s.reloadedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	s.reloadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func newEmitterDoc(chainID vaa.ChainID, address, source string) *EmitterDoc {
+	address = normalizeAddress(address)
+	nativeAddress, err := domain.TranslateEmitterAddress(chainID, address)
+	if err != nil {
+		nativeAddress = ""
+	}
+	return &EmitterDoc{
+		ID:            emitterID(chainID, address),
+		EmitterChain:  chainID,
+		EmitterAddr:   address,
+		NativeAddress: nativeAddress,
+		Source:        source,
+	}
+}
+
+func normalizeAddress(address string) string {
+	return strings.ToLower(utils.Remove0x(address))
+}
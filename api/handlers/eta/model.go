@@ -0,0 +1,28 @@
+package eta
+
+import (
+	"time"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Quantiles are the p50 and p90 durations of a phase, in seconds.
+type Quantiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+}
+
+// Estimate is the distribution of the phase durations of the transfers of a corridor.
+type Estimate struct {
+	SourceChain sdk.ChainID `json:"sourceChain"`
+	TargetChain sdk.ChainID `json:"targetChain"`
+	// AppID is empty when the estimate includes every protocol of the corridor.
+	AppID  string               `json:"appId,omitempty"`
+	Phases map[string]Quantiles `json:"phases"`
+}
+
+// Arrival is the estimated arrival time of a pending operation.
+type Arrival struct {
+	P50 time.Time `json:"p50"`
+	P90 time.Time `json:"p90"`
+}
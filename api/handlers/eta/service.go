@@ -0,0 +1,207 @@
+package eta
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/common/latency"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// window is the period of the transfers used to compute the estimates.
+const window = 7 * 24 * time.Hour
+
+// reloadRetryDelay is the time to wait before querying the quantiles again after a failure.
+const reloadRetryDelay = 30 * time.Second
+
+// Service estimates the duration of the transfers from the transfer_latency measurement.
+// The quantiles of every corridor are loaded at once and kept in memory for the refresh interval.
+type Service struct {
+	reader     timeseries.Reader
+	refresh    time.Duration
+	mu         sync.RWMutex
+	estimates  map[string]*Estimate
+	loaded     bool
+	reloadedAt time.Time
+	// retryAt and loadErr are set when a reload fails, so that the queries are not repeated on every request.
+	retryAt time.Time
+	loadErr error
+	group   singleflight.Group
+	logger  *zap.Logger
+}
+
+// NewService create a new eta.Service.
+func NewService(reader timeseries.Reader, refresh time.Duration, logger *zap.Logger) *Service {
+	return &Service{
+		reader:    reader,
+		refresh:   refresh,
+		estimates: make(map[string]*Estimate),
+		logger:    logger.With(zap.String("module", "EtaService")),
+	}
+}
+
+// Estimate returns the phase durations of the transfers from one chain to another. When there is no data
+// for the protocol, or the protocol is empty, the estimate of the whole corridor is returned.
+func (s *Service) Estimate(ctx context.Context, from, to sdk.ChainID, appID string) (*Estimate, error) {
+	estimates, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if appID != "" {
+		if e, ok := estimates[estimateKey(from, to, appID)]; ok {
+			return e, nil
+		}
+	}
+	if e, ok := estimates[estimateKey(from, to, "")]; ok {
+		return e, nil
+	}
+	return nil, errs.ErrNotFound
+}
+
+// Arrival returns the estimated arrival time of a pending transfer. When the VAA is already signed
+// only the redemption delay remains, otherwise the total duration is counted from the source transaction.
+// The second value is false if there is no estimate for the corridor.
+func (s *Service) Arrival(ctx context.Context, from, to sdk.ChainID, appID string, sourceAt time.Time, signedAt *time.Time) (*Arrival, bool) {
+	e, err := s.Estimate(ctx, from, to, appID)
+	if err != nil {
+		return nil, false
+	}
+	start, q := sourceAt, e.Phases[string(latency.PhaseTotal)]
+	if signedAt != nil {
+		start, q = *signedAt, e.Phases[string(latency.PhaseRedemptionDelay)]
+	}
+	return &Arrival{
+		P50: start.Add(seconds(q.P50)).UTC(),
+		P90: start.Add(seconds(q.P90)).UTC(),
+	}, true
+}
+
+// load returns the estimates, reloading them once for the concurrent requests when they are older
+// than the refresh interval. Once loaded, the previous estimates are returned while they are reloaded
+// in the background and kept if the reload fails. After a failure the reload waits for reloadRetryDelay.
+func (s *Service) load(ctx context.Context) (map[string]*Estimate, error) {
+	s.mu.RLock()
+	estimates, loaded, loadErr := s.estimates, s.loaded, s.loadErr
+	stale := time.Since(s.reloadedAt) > s.refresh && time.Now().After(s.retryAt)
+	s.mu.RUnlock()
+	if !stale {
+		if !loaded && loadErr != nil {
+			return nil, loadErr
+		}
+		return estimates, nil
+	}
+
+	reloaded := s.group.DoChan("estimates", func() (interface{}, error) {
+		reloaded, err := s.query(context.WithoutCancel(ctx))
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil {
+			s.logger.Error("failed to load transfer latency quantiles", zap.Error(err))
+			s.retryAt = time.Now().Add(reloadRetryDelay)
+			s.loadErr = err
+			return nil, err
+		}
+		s.estimates = reloaded
+		s.loaded = true
+		s.reloadedAt = time.Now()
+		s.loadErr = nil
+		return reloaded, nil
+	})
+	if loaded {
+		return estimates, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-reloaded:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.(map[string]*Estimate), nil
+	}
+}
+
+// query computes the quantiles of every phase grouped by corridor and protocol, and by corridor alone.
+func (s *Service) query(ctx context.Context) (map[string]*Estimate, error) {
+	estimates := make(map[string]*Estimate)
+	groupings := [][]string{
+		{latency.TagSourceChain, latency.TagTargetChain, latency.TagAppID},
+		{latency.TagSourceChain, latency.TagTargetChain},
+	}
+	start := time.Now().Add(-window)
+	for _, groupBy := range groupings {
+		for _, phase := range latency.Phases {
+			for _, quantile := range []float64{0.5, 0.9} {
+				rows, err := s.reader.Query(ctx, &timeseries.Query{
+					Bucket:      latency.Bucket,
+					Measurement: latency.Measurement,
+					Field:       string(phase),
+					Start:       start,
+					GroupBy:     groupBy,
+					Aggregate:   timeseries.AggregateQuantile,
+					Quantile:    quantile,
+				})
+				if err != nil {
+					return nil, err
+				}
+				for _, row := range rows {
+					e, ok := estimateFromRow(estimates, row)
+					if !ok {
+						continue
+					}
+					q := e.Phases[string(phase)]
+					if quantile == 0.5 {
+						q.P50 = row.Value
+					} else {
+						q.P90 = row.Value
+					}
+					e.Phases[string(phase)] = q
+				}
+			}
+		}
+	}
+	return estimates, nil
+}
+
+// estimateFromRow returns the estimate of the group of a row, creating it if needed.
+func estimateFromRow(estimates map[string]*Estimate, row timeseries.Row) (*Estimate, bool) {
+	from, err := strconv.ParseUint(row.Tags[latency.TagSourceChain], 10, 16)
+	if err != nil {
+		return nil, false
+	}
+	to, err := strconv.ParseUint(row.Tags[latency.TagTargetChain], 10, 16)
+	if err != nil {
+		return nil, false
+	}
+	appID := row.Tags[latency.TagAppID]
+	if appID == latency.NoAppID {
+		return nil, false
+	}
+	key := estimateKey(sdk.ChainID(from), sdk.ChainID(to), appID)
+	e, ok := estimates[key]
+	if !ok {
+		e = &Estimate{
+			SourceChain: sdk.ChainID(from),
+			TargetChain: sdk.ChainID(to),
+			AppID:       appID,
+			Phases:      make(map[string]Quantiles, len(latency.Phases)),
+		}
+		estimates[key] = e
+	}
+	return e, true
+}
+
+func estimateKey(from, to sdk.ChainID, appID string) string {
+	return fmt.Sprintf("%d/%d/%s", from, to, appID)
+}
+
+func seconds(v float64) time.Duration {
+	return time.Duration(v * float64(time.Second))
+}
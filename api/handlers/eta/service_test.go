@@ -0,0 +1,103 @@
+package eta
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/common/latency"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+func writeTransfer(t *testing.T, store *timeseries.MemoryStore, appID string, total time.Duration, redeemedAt time.Time) {
+	transfer := latency.Transfer{
+		SourceChain: sdk.ChainIDEthereum,
+		TargetChain: sdk.ChainIDSolana,
+		AppID:       appID,
+		RedeemedAt:  redeemedAt,
+	}
+	durations := latency.Durations{
+		latency.PhaseRedemptionDelay: time.Minute,
+		latency.PhaseTotal:           total,
+	}
+	require.NoError(t, store.WritePoints(context.Background(), latency.Bucket, latency.NewPoint(transfer, durations)))
+}
+
+func TestService_Estimate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := timeseries.NewMemoryStore()
+	for i := 1; i <= 10; i++ {
+		writeTransfer(t, store, "PORTAL_TOKEN_BRIDGE", time.Duration(i)*time.Minute, now.Add(-time.Duration(i)*time.Hour))
+	}
+	writeTransfer(t, store, "", time.Hour, now.Add(-time.Hour))
+	srv := NewService(store, time.Minute, zap.NewNop())
+
+	e, err := srv.Estimate(ctx, sdk.ChainIDEthereum, sdk.ChainIDSolana, "PORTAL_TOKEN_BRIDGE")
+	require.NoError(t, err)
+	assert.Equal(t, "PORTAL_TOKEN_BRIDGE", e.AppID)
+	assert.Equal(t, Quantiles{P50: 300, P90: 540}, e.Phases[string(latency.PhaseTotal)])
+
+	// the corridor estimate includes the transfers without app id.
+	e, err = srv.Estimate(ctx, sdk.ChainIDEthereum, sdk.ChainIDSolana, "UNKNOWN")
+	require.NoError(t, err)
+	assert.Empty(t, e.AppID)
+	assert.Equal(t, Quantiles{P50: 360, P90: 600}, e.Phases[string(latency.PhaseTotal)])
+
+	_, err = srv.Estimate(ctx, sdk.ChainIDSolana, sdk.ChainIDEthereum, "")
+	assert.True(t, errors.Is(err, errs.ErrNotFound))
+}
+
+func TestService_Arrival(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := timeseries.NewMemoryStore()
+	writeTransfer(t, store, "PORTAL_TOKEN_BRIDGE", 20*time.Minute, now.Add(-time.Hour))
+	srv := NewService(store, time.Minute, zap.NewNop())
+
+	sourceAt := now.Add(-5 * time.Minute)
+	arrival, ok := srv.Arrival(ctx, sdk.ChainIDEthereum, sdk.ChainIDSolana, "PORTAL_TOKEN_BRIDGE", sourceAt, nil)
+	require.True(t, ok)
+	assert.Equal(t, sourceAt.Add(20*time.Minute).UTC(), arrival.P50)
+
+	// once the vaa is signed only the redemption delay remains.
+	signedAt := now.Add(-time.Minute)
+	arrival, ok = srv.Arrival(ctx, sdk.ChainIDEthereum, sdk.ChainIDSolana, "PORTAL_TOKEN_BRIDGE", sourceAt, &signedAt)
+	require.True(t, ok)
+	assert.Equal(t, signedAt.Add(time.Minute).UTC(), arrival.P90)
+
+	_, ok = srv.Arrival(ctx, sdk.ChainIDSolana, sdk.ChainIDEthereum, "", sourceAt, nil)
+	assert.False(t, ok)
+}
+
+// failingReader counts the queries and fails all of them.
+type failingReader struct {
+	calls atomic.Int32
+}
+
+func (r *failingReader) Query(context.Context, *timeseries.Query) ([]timeseries.Row, error) {
+	r.calls.Add(1)
+	return nil, errors.New("unavailable")
+}
+
+func TestService_LoadBackoff(t *testing.T) {
+	ctx := context.Background()
+	reader := &failingReader{}
+	srv := NewService(reader, time.Minute, zap.NewNop())
+
+	_, err := srv.Estimate(ctx, sdk.ChainIDEthereum, sdk.ChainIDSolana, "")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), reader.calls.Load())
+
+	// the failure is returned without querying again until the retry delay passes.
+	_, err = srv.Estimate(ctx, sdk.ChainIDEthereum, sdk.ChainIDSolana, "")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), reader.calls.Load())
+}
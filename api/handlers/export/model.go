@@ -0,0 +1,77 @@
+package export
+
+import (
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/export"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Status is the state of an export job.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Filter selects the operations included in an export.
+type Filter struct {
+	Address        string        `bson:"address,omitempty" json:"address,omitempty"`
+	SourceChainIDs []sdk.ChainID `bson:"sourceChainIds,omitempty" json:"sourceChain,omitempty"`
+	TargetChainIDs []sdk.ChainID `bson:"targetChainIds,omitempty" json:"targetChain,omitempty"`
+	AppIDs         []string      `bson:"appIds,omitempty" json:"appId,omitempty"`
+	ExclusiveAppId bool          `bson:"exclusiveAppId" json:"exclusiveAppId"`
+	From           *time.Time    `bson:"from,omitempty" json:"from,omitempty"`
+	To             *time.Time    `bson:"to,omitempty" json:"to,omitempty"`
+}
+
+// Job is an asynchronous export of operations.
+type Job struct {
+	ID         string        `bson:"_id" json:"id"`
+	Status     Status        `bson:"status" json:"status"`
+	Format     export.Format `bson:"format" json:"format"`
+	Filter     Filter        `bson:"filter" json:"filter"`
+	Rows       int64         `bson:"rows" json:"rows"`
+	Size       int64         `bson:"size" json:"size"`
+	Error      string        `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt  time.Time     `bson:"createdAt" json:"createdAt"`
+	StartedAt  *time.Time    `bson:"startedAt,omitempty" json:"startedAt,omitempty"`
+	FinishedAt *time.Time    `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+	ExpiresAt  time.Time     `bson:"expiresAt" json:"expiresAt"`
+
+	// Owner is the API instance that queued the job, it refreshes HeartbeatAt while the job
+	// is pending or running so that the jobs of a stopped instance can be detected.
+	Owner       string     `bson:"owner" json:"-"`
+	HeartbeatAt *time.Time `bson:"heartbeatAt,omitempty" json:"-"`
+}
+
+// Filename returns the name of the export file.
+func (j *Job) Filename() string {
+	return "operations-" + j.ID + "." + j.Format.Extension()
+}
+
+// TransferDoc is an operation read from the database to be exported.
+type TransferDoc struct {
+	ID                string      `bson:"_id"`
+	SourceChain       sdk.ChainID `bson:"sourceChain"`
+	EmitterAddress    string      `bson:"emitterAddress"`
+	Sequence          string      `bson:"sequence"`
+	VaaHash           string      `bson:"vaaHash"`
+	SourceTxHash      string      `bson:"sourceTxHash"`
+	DestinationChain  sdk.ChainID `bson:"destinationChain"`
+	DestinationTxHash string      `bson:"destinationTxHash"`
+	TokenChain        sdk.ChainID `bson:"tokenChain"`
+	TokenAddress      string      `bson:"tokenAddress"`
+	TokenAddressHexa  string      `bson:"tokenAddressHexa"`
+	Amount            string      `bson:"amount"`
+	SourceWallet      string      `bson:"sourceWallet"`
+	DestinationWallet string      `bson:"destinationWallet"`
+	Fee               string      `bson:"fee"`
+	Timestamp         time.Time   `bson:"timestamp"`
+	AppIds            []string    `bson:"appIds"`
+	PortalPayloadType int         `bson:"portalPayloadType"`
+	UsdAmount         string      `bson:"usdAmount"`
+}
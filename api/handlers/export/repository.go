@@ -0,0 +1,271 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/common"
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	exportJobsCollection = "exportJobs"
+	exportFilesBucket    = "exports"
+)
+
+// Repository stores the export jobs and their result files.
+//
+// The files are stored in GridFS, so any API instance can serve the download of an
+// export regardless of the instance that ran the job.
+type Repository struct {
+	db     *mongo.Database
+	files  *gridfs.Bucket
+	logger *zap.Logger
+
+	collections struct {
+		exportJobs *mongo.Collection
+		parsedVaa  *mongo.Collection
+	}
+}
+
+// NewRepository creates a new export repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) (*Repository, error) {
+	files, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(exportFilesBucket))
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:     db,
+		files:  files,
+		logger: logger.With(zap.String("module", "ExportRepository")),
+		collections: struct {
+			exportJobs *mongo.Collection
+			parsedVaa  *mongo.Collection
+		}{
+			exportJobs: db.Collection(exportJobsCollection),
+			parsedVaa:  db.Collection("parsedVaa"),
+		},
+	}, nil
+}
+
+// InsertJob saves a new export job.
+func (r *Repository) InsertJob(ctx context.Context, job *Job) error {
+	_, err := r.collections.exportJobs.InsertOne(ctx, job)
+	return err
+}
+
+// FindJob returns an export job by id.
+func (r *Repository) FindJob(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	err := r.collections.exportJobs.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errs.ErrNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to find export job", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateJob sets the given fields of an export job.
+func (r *Repository) UpdateJob(ctx context.Context, id string, fields bson.D) error {
+	_, err := r.collections.exportJobs.UpdateByID(ctx, id, bson.D{{Key: "$set", Value: fields}})
+	if err != nil {
+		r.logger.Error("failed to update export job", zap.String("id", id), zap.Error(err))
+	}
+	return err
+}
+
+// UpdateHeartbeat refreshes the heartbeat of the pending and running jobs of an owner.
+func (r *Repository) UpdateHeartbeat(ctx context.Context, owner string, now time.Time) error {
+	filter := bson.D{
+		{Key: "owner", Value: owner},
+		{Key: "status", Value: bson.M{"$in": bson.A{StatusPending, StatusRunning}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "heartbeatAt", Value: now}}}}
+	_, err := r.collections.exportJobs.UpdateMany(ctx, filter, update)
+	if err != nil {
+		r.logger.Error("failed to update export jobs heartbeat", zap.String("owner", owner), zap.Error(err))
+	}
+	return err
+}
+
+// FailAbandonedJobs marks as failed the pending and running jobs whose heartbeat is older than
+// the given time: the instance that queued them stopped before finishing them.
+// It returns the number of jobs marked as failed.
+func (r *Repository) FailAbandonedJobs(ctx context.Context, before time.Time, cause string) (int64, error) {
+	filter := bson.D{
+		{Key: "status", Value: bson.M{"$in": bson.A{StatusPending, StatusRunning}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "heartbeatAt", Value: bson.M{"$lt": before}}},
+			bson.D{{Key: "heartbeatAt", Value: bson.M{"$exists": false}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: StatusFailed},
+		{Key: "error", Value: cause},
+		{Key: "finishedAt", Value: time.Now()},
+	}}}
+	res, err := r.collections.exportJobs.UpdateMany(ctx, filter, update)
+	if err != nil {
+		r.logger.Error("failed to fail abandoned export jobs", zap.Error(err))
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// FindExpiredJobs returns the ids of the jobs that expired before the given time.
+func (r *Repository) FindExpiredJobs(ctx context.Context, before time.Time) ([]string, error) {
+	filter := bson.D{{Key: "expiresAt", Value: bson.M{"$lt": before}}}
+	cur, err := r.collections.exportJobs.Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var documents []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cur.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		ids = append(ids, doc.ID)
+	}
+	return ids, nil
+}
+
+// DeleteJob deletes an export job and its result file.
+func (r *Repository) DeleteJob(ctx context.Context, id string) error {
+	if err := r.files.Delete(id); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+		return err
+	}
+	_, err := r.collections.exportJobs.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	return err
+}
+
+// OpenUploadStream opens a stream to write the result file of an export job.
+func (r *Repository) OpenUploadStream(id, filename string) (*gridfs.UploadStream, error) {
+	return r.files.OpenUploadStreamWithID(id, filename)
+}
+
+// OpenDownloadStream opens a stream to read the result file of an export job.
+func (r *Repository) OpenDownloadStream(id string) (io.ReadCloser, error) {
+	stream, err := r.files.OpenDownloadStream(id)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, errs.ErrNotFound
+	}
+	return stream, err
+}
+
+// StreamTransfers calls fn for each operation that matches the filter, sorted by timestamp.
+func (r *Repository) StreamTransfers(ctx context.Context, filter *Filter, fn func(*TransferDoc) error) error {
+
+	var pipeline mongo.Pipeline
+
+	if filter.Address != "" {
+		ids, err := common.FindVaasIdsByFromAddressOrToAddress(ctx, r.db, filter.Address)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{{Key: "_id", Value: bson.M{"$in": ids}}}}})
+	}
+
+	if len(filter.SourceChainIDs) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"rawStandardizedProperties.fromChain": bson.M{"$in": filter.SourceChainIDs}},
+			bson.M{"emitterChain": bson.M{"$in": filter.SourceChainIDs}},
+		}}}})
+	}
+
+	if len(filter.TargetChainIDs) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"rawStandardizedProperties.toChain": bson.M{"$in": filter.TargetChainIDs}}}})
+	}
+
+	if len(filter.AppIDs) > 0 {
+		if filter.ExclusiveAppId {
+			matchAppID := bson.A{}
+			for _, appID := range filter.AppIDs {
+				matchAppID = append(matchAppID, bson.M{"$and": bson.A{
+					bson.M{"rawStandardizedProperties.appIds": bson.M{"$eq": appID}},
+					bson.M{"rawStandardizedProperties.appIds": bson.M{"$size": 1}},
+				}})
+			}
+			pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": matchAppID}}})
+		} else {
+			pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"rawStandardizedProperties.appIds": bson.M{"$in": filter.AppIDs}}}})
+		}
+	}
+
+	if filter.From != nil || filter.To != nil {
+		timestamp := bson.M{}
+		if filter.From != nil {
+			timestamp["$gte"] = filter.From
+		}
+		if filter.To != nil {
+			timestamp["$lte"] = filter.To
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"timestamp": timestamp}}})
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}}})
+
+	for _, from := range []string{"vaas", "globalTransactions", "transferPrices"} {
+		pipeline = append(pipeline, bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: from}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "_id"}, {Key: "as", Value: from}}}})
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.D{
+		{Key: "vaa", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$vaas", 0}}}},
+		{Key: "globalTransactions", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$globalTransactions", 0}}}},
+	}}})
+
+	pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.D{
+		{Key: "appIds", Value: "$rawStandardizedProperties.appIds"},
+		{Key: "sourceChain", Value: "$emitterChain"},
+		{Key: "emitterAddress", Value: "$emitterAddr"},
+		{Key: "sequence", Value: "$sequence"},
+		{Key: "vaaHash", Value: "$vaa.txHash"},
+		{Key: "destinationChain", Value: "$rawStandardizedProperties.toChain"},
+		{Key: "tokenChain", Value: "$rawStandardizedProperties.tokenChain"},
+		{Key: "tokenAddress", Value: "$rawStandardizedProperties.tokenAddress"},
+		{Key: "amount", Value: "$rawStandardizedProperties.amount"},
+		{Key: "sourceWallet", Value: "$globalTransactions.originTx.from"},
+		{Key: "sourceTxHash", Value: "$globalTransactions.originTx.nativeTxHash"},
+		{Key: "destinationTxHash", Value: "$globalTransactions.destinationTx.txHash"},
+		{Key: "destinationWallet", Value: "$rawStandardizedProperties.toAddress"},
+		{Key: "fee", Value: "$rawStandardizedProperties.fee"},
+		{Key: "timestamp", Value: "$timestamp"},
+		{Key: "tokenAddressHexa", Value: "$parsedPayload.tokenAddress"},
+		{Key: "portalPayloadType", Value: "$parsedPayload.payloadType"},
+		{Key: "usdAmount", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$transferPrices.usdAmount", 0}}}},
+	}}})
+
+	cur, err := r.collections.parsedVaa.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		r.logger.Error("failed execute aggregation pipeline", zap.Any("filter", filter), zap.Error(err))
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var doc TransferDoc
+		if err := cur.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode operation: %w", err)
+		}
+		if err := fn(&doc); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
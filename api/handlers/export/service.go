@@ -0,0 +1,416 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/export"
+	"github.com/wormhole-foundation/wormhole-explorer/common/prices"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrQueueFull is returned when there are too many exports waiting to run.
+	ErrQueueFull = errors.New("EXPORT QUEUE FULL")
+	// ErrNotReady is returned when the result of an export is requested before it finished.
+	ErrNotReady = errors.New("EXPORT NOT READY")
+	// ErrInvalidFilter is returned when the filter of an export is not valid.
+	ErrInvalidFilter = errors.New("INVALID EXPORT FILTER")
+)
+
+// maxTimeRange is the maximum time range of an export that is not filtered by address.
+const maxTimeRange = 366 * 24 * time.Hour
+
+const (
+	// heartbeatInterval is the interval at which an instance refreshes the heartbeat of its jobs.
+	heartbeatInterval = time.Minute
+	// abandonedAfter is the time after which a pending or running job without heartbeat
+	// is considered abandoned by a stopped instance.
+	abandonedAfter = 5 * heartbeatInterval
+)
+
+// GetPriceByTimeFn returns the price in USD of a token at the given time.
+type GetPriceByTimeFn func(ctx context.Context, coingeckoID string, dateTime time.Time) (decimal.Decimal, error)
+
+// Config contains the parameters of the export workers.
+type Config struct {
+	// Workers is the number of exports that run concurrently.
+	Workers int
+	// QueueSize is the number of exports that can wait for a worker.
+	QueueSize int
+	// Retention is the time an export is kept after it was created.
+	Retention time.Duration
+}
+
+// Service runs the exports in background workers.
+type Service struct {
+	repo           *Repository
+	tokenProvider  *domain.TokenProvider
+	getPriceByTime GetPriceByTimeFn
+	cfg            Config
+	queue          chan string
+	// id identifies the instance as the owner of the jobs it queued.
+	id     string
+	logger *zap.Logger
+}
+
+// NewService creates a new export service.
+//
+// getPriceByTime is optional, when it is nil the USD amounts computed by the analytics
+// service (transferPrices collection) are used instead.
+func NewService(repo *Repository, tokenProvider *domain.TokenProvider, getPriceByTime GetPriceByTimeFn, cfg Config, logger *zap.Logger) *Service {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+	return &Service{
+		repo:           repo,
+		tokenProvider:  tokenProvider,
+		getPriceByTime: getPriceByTime,
+		cfg:            cfg,
+		queue:          make(chan string, cfg.QueueSize),
+		id:             primitive.NewObjectID().Hex(),
+		logger:         logger.With(zap.String("module", "ExportService")),
+	}
+}
+
+// Start starts the export workers, the heartbeat of the queued jobs and the cleanup of expired exports.
+//
+// The jobs are queued in memory, so the jobs left pending or running by a stopped instance are
+// marked as failed once their heartbeat expires, and the client can submit them again.
+func (s *Service) Start(ctx context.Context) {
+	s.failAbandoned(ctx)
+	for i := 0; i < s.cfg.Workers; i++ {
+		go s.work(ctx)
+	}
+	go s.heartbeat(ctx)
+	go s.cleanup(ctx)
+}
+
+// Submit creates a new export job and queues it.
+func (s *Service) Submit(ctx context.Context, format export.Format, filter Filter) (*Job, error) {
+
+	if err := validateFilter(&filter); err != nil {
+		return nil, err
+	}
+	if len(s.queue) == cap(s.queue) {
+		return nil, ErrQueueFull
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          primitive.NewObjectID().Hex(),
+		Status:      StatusPending,
+		Format:      format,
+		Filter:      filter,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.cfg.Retention),
+		Owner:       s.id,
+		HeartbeatAt: &now,
+	}
+	if err := s.repo.InsertJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.queue <- job.ID:
+		return job, nil
+	default:
+		s.fail(ctx, job.ID, ErrQueueFull)
+		return nil, ErrQueueFull
+	}
+}
+
+// GetJob returns an export job.
+func (s *Service) GetJob(ctx context.Context, id string) (*Job, error) {
+	return s.repo.FindJob(ctx, id)
+}
+
+// OpenResult returns a reader for the result file of a completed export.
+func (s *Service) OpenResult(ctx context.Context, id string) (*Job, io.ReadCloser, error) {
+	job, err := s.repo.FindJob(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != StatusCompleted {
+		return job, nil, ErrNotReady
+	}
+	r, err := s.repo.OpenDownloadStream(id)
+	if err != nil {
+		return job, nil, err
+	}
+	return job, r, nil
+}
+
+func (s *Service) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			if err := s.run(ctx, id); err != nil {
+				s.logger.Error("export failed", zap.String("id", id), zap.Error(err))
+				s.fail(ctx, id, err)
+			}
+		}
+	}
+}
+
+// run executes an export job and stores its result file.
+func (s *Service) run(ctx context.Context, id string) error {
+
+	job, err := s.repo.FindJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	startedAt := time.Now()
+	err = s.repo.UpdateJob(ctx, id, bson.D{
+		{Key: "status", Value: StatusRunning},
+		{Key: "startedAt", Value: startedAt},
+	})
+	if err != nil {
+		return err
+	}
+
+	upload, err := s.repo.OpenUploadStream(id, job.Filename())
+	if err != nil {
+		return err
+	}
+	counter := &countingWriter{w: upload}
+
+	w, err := export.NewWriter(job.Format, counter, export.TransferColumns)
+	if err != nil {
+		_ = upload.Abort()
+		return err
+	}
+
+	p := newPricer(s.tokenProvider, s.getPriceByTime, s.logger)
+	var rows int64
+	err = s.repo.StreamTransfers(ctx, &job.Filter, func(doc *TransferDoc) error {
+		t := p.toTransfer(ctx, doc)
+		rows++
+		return w.Write(t.Record())
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		_ = upload.Abort()
+		return err
+	}
+	if err := upload.Close(); err != nil {
+		return err
+	}
+
+	s.logger.Info("export completed",
+		zap.String("id", id),
+		zap.Int64("rows", rows),
+		zap.Int64("size", counter.n),
+		zap.Duration("duration", time.Since(startedAt)))
+
+	return s.repo.UpdateJob(ctx, id, bson.D{
+		{Key: "status", Value: StatusCompleted},
+		{Key: "rows", Value: rows},
+		{Key: "size", Value: counter.n},
+		{Key: "finishedAt", Value: time.Now()},
+	})
+}
+
+func (s *Service) fail(ctx context.Context, id string, cause error) {
+	_ = s.repo.UpdateJob(ctx, id, bson.D{
+		{Key: "status", Value: StatusFailed},
+		{Key: "error", Value: cause.Error()},
+		{Key: "finishedAt", Value: time.Now()},
+	})
+}
+
+// heartbeat periodically refreshes the heartbeat of the jobs of the instance and fails
+// the jobs abandoned by stopped instances.
+func (s *Service) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.repo.UpdateHeartbeat(ctx, s.id, time.Now())
+			s.failAbandoned(ctx)
+		}
+	}
+}
+
+// failAbandoned marks as failed the jobs whose heartbeat expired.
+func (s *Service) failAbandoned(ctx context.Context) {
+	count, err := s.repo.FailAbandonedJobs(ctx, time.Now().Add(-abandonedAfter), "export interrupted, submit it again")
+	if err != nil {
+		return
+	}
+	if count > 0 {
+		s.logger.Warn("failed abandoned exports", zap.Int64("count", count))
+	}
+}
+
+// cleanup periodically deletes the expired exports.
+func (s *Service) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ids, err := s.repo.FindExpiredJobs(ctx, time.Now())
+			if err != nil {
+				s.logger.Error("failed to find expired exports", zap.Error(err))
+				continue
+			}
+			for _, id := range ids {
+				if err := s.repo.DeleteJob(ctx, id); err != nil {
+					s.logger.Error("failed to delete expired export", zap.String("id", id), zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+// validateFilter checks that an export filter is bounded.
+func validateFilter(filter *Filter) error {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return fmt.Errorf("%w: invalid date range", ErrInvalidFilter)
+	}
+	if filter.Address != "" {
+		return nil
+	}
+	if filter.From == nil {
+		return fmt.Errorf("%w: from is required when the export is not filtered by address", ErrInvalidFilter)
+	}
+	to := time.Now()
+	if filter.To != nil {
+		to = *filter.To
+	}
+	if to.Sub(*filter.From) > maxTimeRange {
+		return fmt.Errorf("%w: the time range cannot be greater than %d days", ErrInvalidFilter, int(maxTimeRange.Hours()/24))
+	}
+	return nil
+}
+
+// pricer computes the USD amount of the exported transfers.
+type pricer struct {
+	tokenProvider  *domain.TokenProvider
+	getPriceByTime GetPriceByTimeFn
+	// prices caches the daily price of each token during an export.
+	prices map[string]decimal.Decimal
+	logger *zap.Logger
+}
+
+func newPricer(tokenProvider *domain.TokenProvider, getPriceByTime GetPriceByTimeFn, logger *zap.Logger) *pricer {
+	return &pricer{
+		tokenProvider:  tokenProvider,
+		getPriceByTime: getPriceByTime,
+		prices:         make(map[string]decimal.Decimal),
+		logger:         logger,
+	}
+}
+
+// toTransfer converts an operation into an export row.
+func (p *pricer) toTransfer(ctx context.Context, doc *TransferDoc) *export.Transfer {
+	t := &export.Transfer{
+		VaaID:               doc.ID,
+		VaaHash:             doc.VaaHash,
+		SourceChain:         doc.SourceChain,
+		EmitterAddress:      doc.EmitterAddress,
+		Sequence:            doc.Sequence,
+		Timestamp:           doc.Timestamp,
+		SourceTxHash:        doc.SourceTxHash,
+		SourceSenderAddress: doc.SourceWallet,
+		DestinationChain:    doc.DestinationChain,
+		DestinationAddress:  doc.DestinationWallet,
+		DestinationTxHash:   doc.DestinationTxHash,
+		PortalPayloadType:   doc.PortalPayloadType,
+		AppIDs:              doc.AppIds,
+		TokenChain:          doc.TokenChain,
+		TokenAddress:        doc.TokenAddress,
+		Amount:              doc.Amount,
+		Fee:                 doc.Fee,
+	}
+
+	if doc.TokenAddressHexa != "" {
+		if tokenAddress, err := sdk.StringToAddress(doc.TokenAddressHexa); err == nil {
+			if m, ok := p.tokenProvider.GetTokenByAddress(doc.TokenChain, tokenAddress.String()); ok {
+				decimals := m.Decimals
+				t.Decimals = &decimals
+				t.Symbol = m.Symbol.String()
+				t.CoingeckoID = m.CoingeckoID
+				if usd, ok := p.notionalUSD(ctx, m.CoingeckoID, doc, decimals); ok {
+					t.NotionalUSD = &usd
+					return t
+				}
+			}
+		}
+	}
+
+	// fallback to the USD amount computed by the analytics service.
+	if usd, err := decimal.NewFromString(doc.UsdAmount); err == nil {
+		f, _ := usd.Float64()
+		t.NotionalUSD = &f
+	}
+	return t
+}
+
+// notionalUSD computes the USD amount of a transfer with the token price at the transfer time.
+func (p *pricer) notionalUSD(ctx context.Context, coingeckoID string, doc *TransferDoc, decimals int64) (float64, bool) {
+	if p.getPriceByTime == nil || coingeckoID == "" || doc.Amount == "" {
+		return 0, false
+	}
+	amount, ok := new(big.Int).SetString(doc.Amount, 10)
+	if !ok {
+		return 0, false
+	}
+
+	day := doc.Timestamp.UTC().Truncate(24 * time.Hour)
+	key := coingeckoID + "/" + day.Format(time.DateOnly)
+	price, ok := p.prices[key]
+	if !ok {
+		var err error
+		price, err = p.getPriceByTime(ctx, coingeckoID, doc.Timestamp)
+		if err != nil {
+			p.logger.Warn("failed to get token price",
+				zap.String("coingeckoId", coingeckoID),
+				zap.Time("timestamp", doc.Timestamp),
+				zap.Error(err))
+			return 0, false
+		}
+		p.prices[key] = price
+	}
+
+	usd, _ := prices.CalculatePriceUSD(price, amount, decimals).Float64()
+	return usd, true
+}
+
+// countingWriter tracks the size of the export file.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
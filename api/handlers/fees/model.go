@@ -0,0 +1,56 @@
+package fees
+
+import (
+	"time"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// GroupBy is the dimension used to aggregate the fees, in addition to the chain.
+type GroupBy string
+
+const (
+	GroupByChain   GroupBy = "chain"
+	GroupByRelayer GroupBy = "relayer"
+	GroupByAppID   GroupBy = "appId"
+)
+
+// ParseGroupBy parses a group by value, the second value is false if it is unknown.
+func ParseGroupBy(s string) (GroupBy, bool) {
+	switch g := GroupBy(s); g {
+	case GroupByChain, GroupByRelayer, GroupByAppID:
+		return g, true
+	default:
+		return "", false
+	}
+}
+
+// Query is the filter of the fee statistics.
+type Query struct {
+	Side    string
+	GroupBy GroupBy
+	ChainID *sdk.ChainID
+	From    time.Time
+	To      time.Time
+	// Interval splits the time range, zero means a single interval.
+	Interval time.Duration
+}
+
+// Stats are the aggregated fees of a group of transactions.
+//
+// Fees are always grouped by chain since the amounts are expressed in the native token of the chain.
+type Stats struct {
+	// Time is the start of the interval, it is only set when the query has an interval.
+	Time    *time.Time  `json:"time,omitempty"`
+	ChainID sdk.ChainID `json:"chainId"`
+	Relayer string      `json:"relayer,omitempty"`
+	AppID   string      `json:"appId,omitempty"`
+	Count   uint64      `json:"count"`
+	// TotalFee and AverageFee are expressed in the native token of the chain.
+	TotalFee   float64 `json:"totalFee"`
+	AverageFee float64 `json:"averageFee"`
+	// CountUSD is the number of transactions with a known USD fee.
+	CountUSD      uint64  `json:"countUsd"`
+	TotalFeeUSD   float64 `json:"totalFeeUsd"`
+	AverageFeeUSD float64 `json:"averageFeeUsd"`
+}
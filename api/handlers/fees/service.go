@@ -0,0 +1,131 @@
+package fees
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/fees"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Service aggregates the transaction_fees measurement.
+type Service struct {
+	reader timeseries.Reader
+	logger *zap.Logger
+}
+
+// NewService create a new fees.Service.
+func NewService(reader timeseries.Reader, logger *zap.Logger) *Service {
+	return &Service{
+		reader: reader,
+		logger: logger.With(zap.String("module", "FeesService")),
+	}
+}
+
+// GetStats returns the count, total and average fee of the transactions of a side grouped by chain,
+// and by relayer or protocol, sorted by time and chain.
+func (s *Service) GetStats(ctx context.Context, q *Query) ([]*Stats, error) {
+	groupBy := []string{fees.TagChainID}
+	switch q.GroupBy {
+	case GroupByRelayer:
+		groupBy = append(groupBy, fees.TagRelayer)
+	case GroupByAppID:
+		groupBy = append(groupBy, fees.TagAppID)
+	}
+	include := map[string][]string{fees.TagSide: {q.Side}}
+	if q.ChainID != nil {
+		include[fees.TagChainID] = []string{strconv.Itoa(int(*q.ChainID))}
+	}
+
+	stats := make(map[string]*Stats)
+	aggregations := []struct {
+		field     string
+		aggregate timeseries.Aggregate
+		set       func(s *Stats, v float64)
+	}{
+		{fees.FieldFee, timeseries.AggregateCount, func(s *Stats, v float64) { s.Count = uint64(v) }},
+		{fees.FieldFee, timeseries.AggregateSum, func(s *Stats, v float64) { s.TotalFee = v }},
+		{fees.FieldFeeUSD, timeseries.AggregateCount, func(s *Stats, v float64) { s.CountUSD = uint64(v) }},
+		{fees.FieldFeeUSD, timeseries.AggregateSum, func(s *Stats, v float64) { s.TotalFeeUSD = v }},
+	}
+	for _, a := range aggregations {
+		rows, err := s.reader.Query(ctx, &timeseries.Query{
+			Bucket:      fees.Bucket,
+			Measurement: fees.Measurement,
+			Field:       a.field,
+			Start:       q.From,
+			Stop:        q.To,
+			Include:     include,
+			GroupBy:     groupBy,
+			Aggregate:   a.aggregate,
+			Window:      q.Interval,
+		})
+		if err != nil {
+			s.logger.Error("failed to query transaction fees", zap.String("field", a.field),
+				zap.String("aggregate", string(a.aggregate)), zap.Error(err))
+			return nil, err
+		}
+		for _, row := range rows {
+			st, ok := statsFromRow(stats, row, q.Interval > 0)
+			if !ok {
+				continue
+			}
+			a.set(st, row.Value)
+		}
+	}
+
+	result := make([]*Stats, 0, len(stats))
+	for _, st := range stats {
+		if st.Count > 0 {
+			st.AverageFee = st.TotalFee / float64(st.Count)
+		}
+		if st.CountUSD > 0 {
+			st.AverageFeeUSD = st.TotalFeeUSD / float64(st.CountUSD)
+		}
+		result = append(result, st)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Time != nil && b.Time != nil && !a.Time.Equal(*b.Time) {
+			return a.Time.Before(*b.Time)
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.Relayer != b.Relayer {
+			return a.Relayer < b.Relayer
+		}
+		return a.AppID < b.AppID
+	})
+	return result, nil
+}
+
+// statsFromRow returns the stats of the group of a row, creating them if needed.
+func statsFromRow(stats map[string]*Stats, row timeseries.Row, windowed bool) (*Stats, bool) {
+	chainID, err := strconv.ParseUint(row.Tags[fees.TagChainID], 10, 16)
+	if err != nil {
+		return nil, false
+	}
+	relayer, appID := row.Tags[fees.TagRelayer], row.Tags[fees.TagAppID]
+	if appID == fees.NoAppID {
+		appID = ""
+	}
+	var t *time.Time
+	if windowed {
+		start := row.Time.UTC()
+		t = &start
+	}
+
+	key := strings.Join([]string{row.Time.String(), row.Tags[fees.TagChainID], relayer, appID}, "/")
+	st, ok := stats[key]
+	if !ok {
+		st = &Stats{Time: t, ChainID: sdk.ChainID(chainID), Relayer: relayer, AppID: appID}
+		stats[key] = st
+	}
+	return st, true
+}
@@ -0,0 +1,66 @@
+package fees
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/fees"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+func writeFee(t *testing.T, store *timeseries.MemoryStore, f fees.Fee) {
+	p, ok := fees.NewPoint(f, fees.NewKnownRelayers([]string{"relayer1", "relayer2"}))
+	require.True(t, ok)
+	require.NoError(t, store.WritePoints(context.Background(), fees.Bucket, p))
+}
+
+func TestService_GetStats(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := timeseries.NewMemoryStore()
+	writeFee(t, store, fees.Fee{ID: "2/emitter/1", Side: fees.SideDestination, ChainID: sdk.ChainIDSolana,
+		AppID: "PORTAL_TOKEN_BRIDGE", Relayer: "relayer1", Timestamp: day.Add(time.Hour), Fee: "0.1", FeeUSD: "10"})
+	writeFee(t, store, fees.Fee{ID: "2/emitter/2", Side: fees.SideDestination, ChainID: sdk.ChainIDSolana,
+		AppID: "PORTAL_TOKEN_BRIDGE", Relayer: "relayer2", Timestamp: day.Add(2 * time.Hour), Fee: "0.3"})
+	writeFee(t, store, fees.Fee{ID: "2/emitter/3", Side: fees.SideDestination, ChainID: sdk.ChainIDSolana,
+		Relayer: "relayer1", Timestamp: day.Add(25 * time.Hour), Fee: "0.2", FeeUSD: "20"})
+	writeFee(t, store, fees.Fee{ID: "1/emitter/4", Side: fees.SideSource, ChainID: sdk.ChainIDSolana,
+		Timestamp: day.Add(time.Hour), Fee: "5"})
+	srv := NewService(store, zap.NewNop())
+
+	stats, err := srv.GetStats(ctx, &Query{Side: string(fees.SideDestination), GroupBy: GroupByChain,
+		From: day, To: day.Add(48 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, sdk.ChainIDSolana, stats[0].ChainID)
+	assert.Nil(t, stats[0].Time)
+	assert.Equal(t, uint64(3), stats[0].Count)
+	assert.InDelta(t, 0.6, stats[0].TotalFee, 1e-9)
+	assert.InDelta(t, 0.2, stats[0].AverageFee, 1e-9)
+	assert.Equal(t, uint64(2), stats[0].CountUSD)
+	assert.InDelta(t, 15, stats[0].AverageFeeUSD, 1e-9)
+
+	stats, err = srv.GetStats(ctx, &Query{Side: string(fees.SideDestination), GroupBy: GroupByRelayer,
+		From: day, To: day.Add(48 * time.Hour), Interval: 24 * time.Hour})
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+	assert.Equal(t, day, *stats[0].Time)
+	assert.Equal(t, "relayer1", stats[0].Relayer)
+	assert.Equal(t, "relayer2", stats[1].Relayer)
+	assert.Equal(t, day.Add(24*time.Hour), *stats[2].Time)
+	assert.Equal(t, "relayer1", stats[2].Relayer)
+	assert.InDelta(t, 0.2, stats[2].AverageFee, 1e-9)
+
+	stats, err = srv.GetStats(ctx, &Query{Side: string(fees.SideDestination), GroupBy: GroupByAppID,
+		From: day, To: day.Add(48 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Empty(t, stats[0].AppID)
+	assert.Equal(t, "PORTAL_TOKEN_BRIDGE", stats[1].AppID)
+	assert.Equal(t, uint64(2), stats[1].Count)
+}
@@ -0,0 +1,43 @@
+package governance
+
+import (
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
+	"github.com/wormhole-foundation/wormhole-explorer/common/governance"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// ActionDoc represents a decoded governance VAA.
+type ActionDoc struct {
+	ID               string                `bson:"_id" json:"id"`
+	Module           governance.Module     `bson:"module" json:"module"`
+	Type             governance.ActionType `bson:"type" json:"type"`
+	TargetChain      vaa.ChainID           `bson:"targetChain" json:"targetChain"`
+	EmitterChain     vaa.ChainID           `bson:"emitterChain" json:"emitterChain"`
+	EmitterAddr      string                `bson:"emitterAddr" json:"emitterAddr"`
+	Sequence         uint64                `bson:"sequence" json:"sequence"`
+	GuardianSetIndex uint32                `bson:"guardianSetIndex" json:"guardianSetIndex"`
+	Timestamp        time.Time             `bson:"timestamp" json:"timestamp"`
+	Details          governance.Details    `bson:"details" json:"details"`
+	IndexedAt        *time.Time            `bson:"indexedAt" json:"indexedAt"`
+}
+
+// RegisteredEmitterDoc represents the emitter of a foreign chain registered in a bridge module.
+type RegisteredEmitterDoc struct {
+	Module          governance.Module `bson:"module" json:"module"`
+	RegisteredChain vaa.ChainID       `bson:"registeredChain" json:"chainId"`
+	EmitterAddress  string            `bson:"registeredEmitter" json:"emitterAddress"`
+	VaaID           string            `bson:"vaaId" json:"vaaId"`
+	Timestamp       time.Time         `bson:"timestamp" json:"timestamp"`
+}
+
+// ActionQuery contains the filters of a governance actions search.
+type ActionQuery struct {
+	Pagination  *pagination.Pagination
+	Type        *governance.ActionType
+	Module      *governance.Module
+	TargetChain *vaa.ChainID
+	From        *time.Time
+	To          *time.Time
+}
@@ -0,0 +1,131 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/common/governance"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository definition.
+type Repository struct {
+	db          *mongo.Database
+	logger      *zap.Logger
+	collections struct {
+		governanceActions *mongo.Collection
+	}
+}
+
+// NewRepository create a new Repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{db: db,
+		logger: logger.With(zap.String("module", "GovernanceRepository")),
+		collections: struct {
+			governanceActions *mongo.Collection
+		}{
+			governanceActions: db.Collection(repository.GovernanceActions),
+		},
+	}
+}
+
+// FindActions get a list of governance actions matching the query, sorted by timestamp.
+func (r *Repository) FindActions(ctx context.Context, q *ActionQuery) ([]*ActionDoc, error) {
+	filter := bson.D{}
+	if q.Type != nil {
+		filter = append(filter, bson.E{Key: "type", Value: *q.Type})
+	}
+	if q.Module != nil {
+		filter = append(filter, bson.E{Key: "module", Value: *q.Module})
+	}
+	if q.TargetChain != nil {
+		filter = append(filter, bson.E{Key: "targetChain", Value: *q.TargetChain})
+	}
+	if q.From != nil || q.To != nil {
+		timestamp := bson.M{}
+		if q.From != nil {
+			timestamp["$gte"] = *q.From
+		}
+		if q.To != nil {
+			timestamp["$lt"] = *q.To
+		}
+		filter = append(filter, bson.E{Key: "timestamp", Value: timestamp})
+	}
+
+	p := q.Pagination
+	sort := bson.D{{Key: "timestamp", Value: p.GetSortInt()}, {Key: "_id", Value: p.GetSortInt()}}
+	cur, err := r.collections.governanceActions.Find(ctx, filter, options.Find().SetLimit(p.Limit).SetSkip(p.Skip).SetSort(sort))
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Find command to get governance actions",
+			zap.Error(err), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	var actions []*ActionDoc
+	if err := cur.All(ctx, &actions); err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed decoding cursor to []*ActionDoc", zap.Error(err),
+			zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	// If no results were found, return an empty slice instead of nil.
+	if actions == nil {
+		actions = make([]*ActionDoc, 0)
+	}
+	return actions, nil
+}
+
+// FindRegisteredEmitters get the emitters registered in the bridge modules of a chain, derived from
+// the chain registration history. Registrations targeting chain 0 apply to every chain.
+// A bridge module rejects the registration of a chain that is already registered, so the earliest
+// registration of a foreign chain is the one in effect and the later ones are ignored.
+func (r *Repository) FindRegisteredEmitters(ctx context.Context, chainID vaa.ChainID) ([]*RegisteredEmitterDoc, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "type", Value: governance.ActionRegisterChain},
+			{Key: "targetChain", Value: bson.M{"$in": []vaa.ChainID{vaa.ChainIDUnset, chainID}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "timestamp", Value: 1}, {Key: "sequence", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "module", Value: "$module"},
+				{Key: "registeredChain", Value: "$details.registeredChain"},
+			}},
+			{Key: "module", Value: bson.M{"$first": "$module"}},
+			{Key: "registeredChain", Value: bson.M{"$first": "$details.registeredChain"}},
+			{Key: "registeredEmitter", Value: bson.M{"$first": "$details.registeredEmitter"}},
+			{Key: "vaaId", Value: bson.M{"$first": "$_id"}},
+			{Key: "timestamp", Value: bson.M{"$first": "$timestamp"}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "module", Value: 1}, {Key: "registeredChain", Value: 1}}}},
+	}
+
+	cur, err := r.collections.governanceActions.Aggregate(ctx, pipeline)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Aggregate command to get registered emitters",
+			zap.Error(err), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	var emitters []*RegisteredEmitterDoc
+	if err := cur.All(ctx, &emitters); err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed decoding cursor to []*RegisteredEmitterDoc", zap.Error(err),
+			zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	if emitters == nil {
+		emitters = make([]*RegisteredEmitterDoc, 0)
+	}
+	return emitters, nil
+}
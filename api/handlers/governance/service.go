@@ -0,0 +1,29 @@
+package governance
+
+import (
+	"context"
+
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Service definition.
+type Service struct {
+	repo   *Repository
+	logger *zap.Logger
+}
+
+// NewService create a new governance.Service.
+func NewService(dao *Repository, logger *zap.Logger) *Service {
+	return &Service{repo: dao, logger: logger.With(zap.String("module", "GovernanceService"))}
+}
+
+// FindActions get the governance actions matching the query.
+func (s *Service) FindActions(ctx context.Context, q *ActionQuery) ([]*ActionDoc, error) {
+	return s.repo.FindActions(ctx, q)
+}
+
+// FindRegisteredEmitters get the emitters registered in the bridge modules of a chain.
+func (s *Service) FindRegisteredEmitters(ctx context.Context, chainID vaa.ChainID) ([]*RegisteredEmitterDoc, error) {
+	return s.repo.FindRegisteredEmitters(ctx, chainID)
+}
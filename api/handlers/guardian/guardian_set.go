@@ -6,6 +6,7 @@ import (
 	"github.com/certusone/wormhole/node/pkg/common"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/config"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/guardianset"
 )
 
 // GuardianSet definition.
@@ -24,12 +25,23 @@ func getByEnv(enviroment string) GuardianSet {
 	}
 }
 
+// fromHistory creates a GuardianSet from a verified guardian set history.
+func fromHistory(h *guardianset.History) GuardianSet {
+	gstByIndex, expirationTimeByIndex := h.All()
+	return GuardianSet{
+		GstByIndex:            gstByIndex,
+		ExpirationTimeByIndex: expirationTimeByIndex,
+	}
+}
+
 // IsValid check if a guardianSet is valid.
 func (gs GuardianSet) IsValid(gsIx uint32, t time.Time) bool {
 	if int(gsIx) > len(gs.GstByIndex) {
 		return false
 	}
-	return gs.ExpirationTimeByIndex[gsIx].After(t)
+	expiration := gs.ExpirationTimeByIndex[gsIx]
+	// the current guardian set has no expiration time.
+	return expiration.IsZero() || expiration.After(t)
 }
 
 // GetLatest get the lastest guardianset.
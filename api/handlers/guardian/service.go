@@ -11,6 +11,7 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/api/cacheable"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
+	"github.com/wormhole-foundation/wormhole-explorer/common/guardianset"
 	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
 	"go.uber.org/zap"
 )
@@ -18,6 +19,7 @@ import (
 type Service struct {
 	repo       *repository.GuardianSetRepository
 	p2pNetwork string
+	bundleFile string
 	cache      cache.Cache
 	metrics    metrics.Metrics
 	logger     *zap.Logger
@@ -25,11 +27,12 @@ type Service struct {
 
 const currentGuardianSetKey = "current-guardian-set"
 
-func NewService(repo *repository.GuardianSetRepository, p2pNetwork string, cache cache.Cache,
+func NewService(repo *repository.GuardianSetRepository, p2pNetwork, bundleFile string, cache cache.Cache,
 	metrics metrics.Metrics, logger *zap.Logger) *Service {
 	return &Service{
 		repo:       repo,
 		p2pNetwork: p2pNetwork,
+		bundleFile: bundleFile,
 		cache:      cache,
 		metrics:    metrics,
 		logger:     logger.With(zap.String("module", "GuardianService")),
@@ -48,14 +51,14 @@ func (s *Service) getGuardianSet(ctx context.Context) (*GuardianSet, error) {
 	docs, err := s.repo.FindAll(ctx)
 	if err != nil {
 		s.logger.Error("failed to get guardian set from repository", zap.Error(err))
-		gs := getByEnv(s.p2pNetwork)
+		gs := s.getFallbackGuardianSet(ctx)
 		return &gs, nil
 	}
 
-	gs := &GuardianSet{}
 	if len(docs) == 0 {
 		s.logger.Error("guardian set not fetched from chain yet")
-		return gs, nil
+		gs := s.getFallbackGuardianSet(ctx)
+		return &gs, nil
 	}
 	sort.Slice(docs, func(i, j int) bool {
 		return docs[i].GuardianSetIndex > docs[j].GuardianSetIndex
@@ -91,3 +94,17 @@ func (s *Service) getGuardianSet(ctx context.Context) (*GuardianSet, error) {
 		ExpirationTimeByIndex: expirationTimeByIndex,
 	}, nil
 }
+
+// getFallbackGuardianSet verifies the guardian sets from the bundle file when it is configured,
+// otherwise returns the guardian sets hardcoded for the network.
+func (s *Service) getFallbackGuardianSet(ctx context.Context) GuardianSet {
+	if s.bundleFile == "" {
+		return getByEnv(s.p2pNetwork)
+	}
+	history, err := guardianset.Load(ctx, s.p2pNetwork, s.bundleFile, nil, s.logger)
+	if err != nil {
+		s.logger.Error("failed to load guardian set bundle", zap.String("file", s.bundleFile), zap.Error(err))
+		return getByEnv(s.p2pNetwork)
+	}
+	return fromHistory(history)
+}
@@ -47,3 +47,24 @@ type FindAllParams struct {
 	Pagination *pagination.Pagination
 	TxHash     *types.TxHash
 }
+
+// ConflictingSignerDoc represents a guardian that signed a hash of a conflicting message.
+type ConflictingSignerDoc struct {
+	GuardianAddr string `bson:"guardianAddr" json:"guardianAddr"`
+	Hash         string `bson:"hash" json:"hash"`
+}
+
+// ConflictingObservationDoc represents a message for which guardians signed different hashes.
+type ConflictingObservationDoc struct {
+	ID           string                 `bson:"_id" json:"id"`
+	EmitterChain vaa.ChainID            `bson:"emitterChain" json:"emitterChain"`
+	EmitterAddr  string                 `bson:"emitterAddr" json:"emitterAddr"`
+	Sequence     string                 `bson:"sequence" json:"sequence"`
+	Hashes       []string               `bson:"hashes" json:"hashes"`
+	Signers      []ConflictingSignerDoc `bson:"signers" json:"signers"`
+	// Status is unconfirmed until a signed hash is compared with the digest of the signed VAA.
+	Status    string     `bson:"status" json:"status"`
+	VaaDigest string     `bson:"vaaDigest,omitempty" json:"vaaDigest,omitempty"`
+	UpdatedAt *time.Time `bson:"updatedAt" json:"updatedAt"`
+	IndexedAt *time.Time `bson:"indexedAt" json:"indexedAt"`
+}
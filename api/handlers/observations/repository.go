@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
 	"github.com/wormhole-foundation/wormhole-explorer/common/types"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.mongodb.org/mongo-driver/bson"
@@ -22,14 +23,21 @@ type Repository struct {
 	logger      *zap.Logger
 	collections struct {
 		observations *mongo.Collection
+		conflicts    *mongo.Collection
 	}
 }
 
 // NewRepository create a new Repository.
 func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
 	return &Repository{db: db,
-		logger:      logger.With(zap.String("module", "ObservationsRepository")),
-		collections: struct{ observations *mongo.Collection }{observations: db.Collection("observations")},
+		logger: logger.With(zap.String("module", "ObservationsRepository")),
+		collections: struct {
+			observations *mongo.Collection
+			conflicts    *mongo.Collection
+		}{
+			observations: db.Collection("observations"),
+			conflicts:    db.Collection(repository.ConflictingObservations),
+		},
 	}
 }
 
@@ -82,6 +90,38 @@ func (r *Repository) FindOne(ctx context.Context, q *ObservationQuery) (*Observa
 	return &obs, err
 }
 
+// FindConflicts get a list of messages for which guardians signed different hashes,
+// sorted by detection time in descending order.
+func (r *Repository) FindConflicts(ctx context.Context, chainID *vaa.ChainID, p *pagination.Pagination) ([]*ConflictingObservationDoc, error) {
+	filter := bson.D{}
+	if chainID != nil {
+		filter = append(filter, bson.E{Key: "emitterChain", Value: *chainID})
+	}
+	sort := bson.D{{Key: "indexedAt", Value: p.GetSortInt()}, {Key: "_id", Value: p.GetSortInt()}}
+
+	cur, err := r.collections.conflicts.Find(ctx, filter, options.Find().SetLimit(p.Limit).SetSkip(p.Skip).SetSort(sort))
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Find command to get conflicting observations",
+			zap.Error(err), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	var conflicts []*ConflictingObservationDoc
+	if err := cur.All(ctx, &conflicts); err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed decoding cursor to []*ConflictingObservationDoc", zap.Error(err),
+			zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	// If no results were found, return an empty slice instead of nil.
+	if conflicts == nil {
+		conflicts = make([]*ConflictingObservationDoc, 0)
+	}
+	return conflicts, nil
+}
+
 // ObservationQuery respresent a query for the observation mongodb document.
 type ObservationQuery struct {
 	pagination.Pagination
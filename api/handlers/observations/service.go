@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
+	"github.com/wormhole-foundation/wormhole-explorer/common/observation"
 	"github.com/wormhole-foundation/wormhole-explorer/common/types"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
@@ -13,12 +14,13 @@ import (
 // Service definition.
 type Service struct {
 	repo   *Repository
+	stuck  *observation.Repository
 	logger *zap.Logger
 }
 
 // NewService create a new Service.
-func NewService(dao *Repository, logger *zap.Logger) *Service {
-	return &Service{repo: dao, logger: logger.With(zap.String("module", "ObservationsService"))}
+func NewService(dao *Repository, stuck *observation.Repository, logger *zap.Logger) *Service {
+	return &Service{repo: dao, stuck: stuck, logger: logger.With(zap.String("module", "ObservationsService"))}
 }
 
 // FindAll get all the observations.
@@ -85,3 +87,22 @@ func (s *Service) FindOne(
 
 	return s.repo.FindOne(ctx, query)
 }
+
+// FindConflicts get the messages for which guardians signed different hashes.
+func (s *Service) FindConflicts(ctx context.Context, chainID *vaa.ChainID, p *pagination.Pagination) ([]*ConflictingObservationDoc, error) {
+	return s.repo.FindConflicts(ctx, chainID, p)
+}
+
+// FindStuck get the messages that were observed but did not become a signed VAA in time,
+// as stored by the stuck observations job.
+func (s *Service) FindStuck(ctx context.Context, q observation.Query) ([]observation.Message, error) {
+	messages, err := s.stuck.Find(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	// If no results were found, return an empty slice instead of nil.
+	if messages == nil {
+		messages = make([]observation.Message, 0)
+	}
+	return messages, nil
+}
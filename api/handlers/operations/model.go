@@ -3,6 +3,8 @@ package operations
 import (
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/eta"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
@@ -18,6 +20,10 @@ type OperationDto struct {
 	DestinationTx          *DestinationTx          `bson:"destinationTx" json:"destinationTx"`
 	Payload                map[string]any          `bson:"payload"`
 	StandardizedProperties *StandardizedProperties `bson:"standardizedProperties"`
+	// Emitter is taken from the emitter registry, it is not stored with the operation.
+	Emitter *emitter.Info `bson:"-"`
+	// ETA is the estimated arrival time of a pending operation, it is not stored with the operation.
+	ETA *eta.Arrival `bson:"-"`
 }
 
 // StandardizedProperties represents the standardized properties of a operation.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/common"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
@@ -162,22 +163,19 @@ func buildQueryOperationsByAppID(appIDs []string, exclusive bool) bson.D {
 
 // findOperationsIdByAddress returns all operations filtered by address.
 func findOperationsIdByAddress(ctx context.Context, db *mongo.Database, address string, pagination *pagination.Pagination) ([]string, error) {
-	addressHex := strings.ToLower(address)
-	if !utils.StartsWith0x(address) {
-		addressHex = "0x" + strings.ToLower(addressHex)
+	representations, err := common.FindAddressRepresentations(ctx, db, address)
+	if err != nil {
+		return nil, err
 	}
 
 	matchGlobalTransactions := bson.D{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
-		bson.D{{Key: "originTx.from", Value: bson.M{"$eq": addressHex}}},
-		bson.D{{Key: "originTx.from", Value: bson.M{"$eq": address}}},
-		bson.D{{Key: "originTx.attribute.value.originAddress", Value: bson.M{"$eq": addressHex}}},
-		bson.D{{Key: "originTx.attribute.value.originAddress", Value: bson.M{"$eq": address}}},
+		bson.D{{Key: "originTx.from", Value: bson.M{"$in": representations}}},
+		bson.D{{Key: "originTx.attribute.value.originAddress", Value: bson.M{"$in": representations}}},
 	}}}}}
 
-	matchParsedVaa := bson.D{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
-		bson.D{{Key: "standardizedProperties.toAddress", Value: bson.M{"$eq": addressHex}}},
-		bson.D{{Key: "standardizedProperties.toAddress", Value: bson.M{"$eq": address}}},
-	}}}}}
+	matchParsedVaa := bson.D{{Key: "$match", Value: bson.D{
+		{Key: "standardizedProperties.toAddress", Value: bson.M{"$in": representations}},
+	}}}
 
 	globalTransactionFilter := bson.D{{Key: "$unionWith", Value: bson.D{{Key: "coll", Value: "globalTransactions"}, {Key: "pipeline", Value: bson.A{matchGlobalTransactions}}}}}
 	parserFilter := bson.D{{Key: "$unionWith", Value: bson.D{{Key: "coll", Value: "parsedVaa"}, {Key: "pipeline", Value: bson.A{matchParsedVaa}}}}}
@@ -3,8 +3,12 @@ package operations
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/eta"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/common/types"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -12,13 +16,15 @@ import (
 )
 
 type Service struct {
-	repo   *Repository
-	logger *zap.Logger
+	repo     *Repository
+	emitters *emitter.Service
+	etas     *eta.Service
+	logger   *zap.Logger
 }
 
 // NewService create a new Service.
-func NewService(repo *Repository, logger *zap.Logger) *Service {
-	return &Service{repo: repo, logger: logger.With(zap.String("module", "OperationService"))}
+func NewService(repo *Repository, emitters *emitter.Service, etas *eta.Service, logger *zap.Logger) *Service {
+	return &Service{repo: repo, emitters: emitters, etas: etas, logger: logger.With(zap.String("module", "OperationService"))}
 }
 
 // FindById returns the operations for the given chainID/emitter/seq.
@@ -29,6 +35,8 @@ func (s *Service) FindById(ctx context.Context, chainID vaa.ChainID,
 	if err != nil {
 		return nil, err
 	}
+	s.addEmitterInfo(ctx, operation)
+	s.addETA(ctx, operation)
 	return operation, nil
 }
 
@@ -65,13 +73,69 @@ func (s *Service) FindAll(ctx context.Context, filter OperationFilter) ([]*Opera
 		To:             filter.To,
 	}
 
+	var operations []*OperationDto
+	var err error
 	if len(operationQuery.AppIDs) != 0 || len(operationQuery.SourceChainIDs) > 0 || len(operationQuery.TargetChainIDs) > 0 || len(operationQuery.PayloadType) > 0 {
-		return s.repo.FindFromParsedVaa(ctx, operationQuery)
+		operations, err = s.repo.FindFromParsedVaa(ctx, operationQuery)
+	} else {
+		operations, err = s.repo.FindAll(ctx, operationQuery)
 	}
-
-	operations, err := s.repo.FindAll(ctx, operationQuery)
 	if err != nil {
 		return nil, err
 	}
+	s.addEmitterInfo(ctx, operations...)
+	s.addETA(ctx, operations...)
 	return operations, nil
 }
+
+// addEmitterInfo sets the emitter registry information of the given operations.
+// The emitter is taken from the operation id, which has the form chain/emitter/sequence.
+func (s *Service) addEmitterInfo(ctx context.Context, operations ...*OperationDto) {
+	if s.emitters == nil {
+		return
+	}
+	for _, op := range operations {
+		parts := strings.Split(op.ID, "/")
+		if len(parts) != 3 {
+			continue
+		}
+		chainID, err := strconv.ParseUint(parts[0], 10, 16)
+		if err != nil {
+			continue
+		}
+		op.Emitter = s.emitters.Lookup(ctx, vaa.ChainID(chainID), parts[1])
+	}
+}
+
+// addETA sets the estimated arrival time of the given operations that were not redeemed yet.
+func (s *Service) addETA(ctx context.Context, operations ...*OperationDto) {
+	if s.etas == nil {
+		return
+	}
+	for _, op := range operations {
+		p := op.StandardizedProperties
+		if op.DestinationTx != nil || p == nil || p.ToChain == vaa.ChainIDUnset {
+			continue
+		}
+		var sourceAt, signedAt *time.Time
+		if op.SourceTx != nil {
+			sourceAt = op.SourceTx.Timestamp
+		}
+		if op.Vaa != nil {
+			if sourceAt == nil {
+				sourceAt = op.Vaa.Timestamp
+			}
+			signedAt = op.Vaa.IndexedAt
+		}
+		if sourceAt == nil {
+			continue
+		}
+		var appID string
+		if len(p.AppIds) > 0 {
+			appID = p.AppIds[0]
+		}
+		if arrival, ok := s.etas.Arrival(ctx, p.FromChain, p.ToChain, appID, *sourceAt, signedAt); ok {
+			op.ETA = arrival
+		}
+	}
+}
@@ -2,157 +2,124 @@ package protocols
 
 import (
 	"context"
-	"fmt"
 	"github.com/influxdata/influxdb-client-go/v2/api"
 	"github.com/influxdata/influxdb-client-go/v2/api/query"
 	"github.com/mitchellh/mapstructure"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbconsts"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 	"time"
 )
 
-const AllProtocolsDeltaLastDay = `
-		import "date"
-		import "types"
-		import "strings"
-
-		ts = date.truncate(t: now(), unit: 1h)
-		yesterday = date.sub(d: 1d, from: ts)
-		
-		data = from(bucket: "%s")
-				|> range(start: yesterday,stop:ts)
-				|> filter(fn: (r) => r._measurement == "protocols_stats_totals_1h")
-				|> drop(columns: ["emitter_chain","destination_chain"])
-		
-		tvt = data
-			|> filter(fn : (r) => r._field == "total_value_transferred")
-			|> group(columns:["app_id"])
-			|> sum()
-			|> set(key:"_field",value:"total_value_transferred")
-			|> map(fn: (r) => ({r with _value: int(v: r._value)}))
-		
-		totalMsgs =	data
-					|> filter(fn : (r) => r._field == "total_messages")
-					|> group(columns:["app_id"])
-					|> sum()
-					|> set(key:"_field",value:"total_messages")
-					|> map(fn: (r) => ({r with _value: int(v: r._value)}))
-		
-		union(tables:[tvt,totalMsgs])
-			|> set(key:"_time",value:string(v:yesterday))
-			|> pivot(rowKey:["_time","app_id"], columnKey: ["_field"], valueColumn: "_value")
-			|> map(fn: (r) => ({r with app_id: strings.trimPrefix(v: r.app_id, prefix: "TOTAL_")}))
-`
-
-const AllProtocolStats24HrAgo = `
-	import "date"
-	import "strings"
-
-	startOfCurrentDay = date.truncate(t: now(), unit: 1d)
-	
-	data =	from(bucket: "%s")
-				|> range(start: 1970-01-01T00:00:00Z,stop:startOfCurrentDay)
-				|> filter(fn: (r) => r._measurement == "protocols_stats_totals_1d" and r.version == "v1")
-				|> drop(columns: ["emitter_chain","destination_chain","version"])
-	
-	tvt = data	
-			|> filter(fn : (r) => r._field == "total_value_transferred")
-			|> group(columns:["app_id"])
-			|> sum()
-			|> set(key:"_field",value:"total_value_transferred")
-			|> map(fn: (r) => ({r with _value: int(v: r._value)}))
-	
-	totalMsgs =	data	
-				|> filter(fn : (r) => r._field == "total_messages")
-				|> group(columns:["app_id"])
-				|> sum()
-				|> set(key:"_field",value:"total_messages")
-				|> map(fn: (r) => ({r with _value: int(v: r._value)}))
-	
-	union(tables:[tvt,totalMsgs])		
-		|> set(key:"_time",value:string(v:startOfCurrentDay))
-		|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-		|> map(fn: (r) => ({r with app_id: strings.trimPrefix(v: r.app_id, prefix: "TOTAL_")}))
-`
-
-const AllProtocolsDeltaSinceStartOfDay = `
-	import "date"
-	import "strings"
-
-	ts = date.truncate(t: now(), unit: 1h)
-	startOfDay = date.truncate(t: now(), unit: 1d)
-	
-	data = from(bucket: "%s")
-			|> range(start: startOfDay,stop:ts)
-			|> filter(fn: (r) => r._measurement == "protocols_stats_totals_1h")
-			|> drop(columns: ["emitter_chain","destination_chain","version"])
-	
-	tvt = data	
-			|> filter(fn : (r) => r._field == "total_value_transferred")
-			|> group(columns:["app_id"])
-			|> sum()
-			|> set(key:"_field",value:"total_value_transferred")
-			|> map(fn: (r) => ({r with _value: int(v: r._value)}))
-	
-	totalMsgs =	data	
-					|> filter(fn : (r) => r._field == "total_messages")
-					|> group(columns:["app_id"])
-					|> sum()	
-					|> set(key:"_field",value:"total_messages")
-					|> map(fn: (r) => ({r with _value: int(v: r._value)}))
-	
-	union(tables:[tvt,totalMsgs])
-		|> set(key:"_time",value:string(v:startOfDay))
-		|> pivot(rowKey:["_time","app_id"], columnKey: ["_field"], valueColumn: "_value")
-		|> map(fn: (r) => ({r with app_id: strings.trimPrefix(v: r.app_id, prefix: "TOTAL_")}))
-`
-
-const QueryTemplateProtocolStats24HrAgo = `
-		from(bucket: "%s")
-  			|> range(start: -1d)
-  			|> filter(fn: (r) => r._measurement == "%s" and r.protocol == "%s")
-			|> first()
-			|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-`
-
-const QueryTemplateProtocolStatsNow = `
-		from(bucket: "%s")
-  			|> range(start: -2d)
-  			|> filter(fn: (r) => r._measurement == "%s" and r.protocol == "%s")
-			|> last()
-			|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-`
-
-const QueryTemplateProtocolActivity = `	
-	data = 
-		from(bucket: "%s")
-		  |> range(start: %s)
-		  |> filter(fn: (r) => r._measurement == "%s" and r.protocol == "%s")
-	
-		tvt = data	
-			|> filter(fn: (r) => r._field == "total_value_transferred") 
-			|> sum()
-
-		txs = data	
-  			|> filter(fn: (r) => r._field == "txs")
-  			|> sum()
-
-		union(tables:[tvt, txs])
-			|> pivot(rowKey:["_start","_stop"], columnKey: ["_field"], valueColumn: "_value")
-			|> rename(columns: {_stop: "_time"})
-`
-
-const QueryLast24HrActivity = `
-	import "date"
-	
-	from(bucket: "%s")
-		|> range(start: -5d)
-		|> filter(fn: (r) => r._measurement == "%s")
-		|> filter(fn: (r) => r.protocol == "%s")
-		|> last()
-		|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-`
+// AllProtocolsDeltaLastDay returns the messages and volume of each protocol in the last 24 hours.
+func AllProtocolsDeltaLastDay(bucket string) flux.Query {
+	s := flux.NewScript("date", "strings")
+	ts := s.Let("ts", flux.Call("date.truncate", flux.Arg("t", flux.Raw("now()")), flux.Arg("unit", flux.Raw("1h"))))
+	yesterday := s.Let("yesterday", flux.Call("date.sub", flux.Arg("d", flux.Raw("1d")), flux.Arg("from", ts)))
+	data := s.Let("data", flux.From(bucket).
+		Range(yesterday, ts).
+		Filter(flux.Eq("_measurement", flux.String("protocols_stats_totals_1h"))).
+		Drop("emitter_chain", "destination_chain"))
+	return buildProtocolsTotals(s, data, yesterday, "_time", "app_id")
+}
+
+// AllProtocolStats24HrAgo returns the messages and volume of each protocol until the start of the current day.
+func AllProtocolStats24HrAgo(bucket string) flux.Query {
+	s := flux.NewScript("date", "strings")
+	startOfCurrentDay := s.Let("startOfCurrentDay", flux.Call("date.truncate", flux.Arg("t", flux.Raw("now()")), flux.Arg("unit", flux.Raw("1d"))))
+	data := s.Let("data", flux.From(bucket).
+		Range(flux.Epoch, startOfCurrentDay).
+		Filter(flux.Eq("_measurement", flux.String("protocols_stats_totals_1d")), flux.Eq("version", flux.String("v1"))).
+		Drop("emitter_chain", "destination_chain", "version"))
+	return buildProtocolsTotals(s, data, startOfCurrentDay, "_time")
+}
+
+// AllProtocolsDeltaSinceStartOfDay returns the messages and volume of each protocol since the start of the current day.
+func AllProtocolsDeltaSinceStartOfDay(bucket string) flux.Query {
+	s := flux.NewScript("date", "strings")
+	ts := s.Let("ts", flux.Call("date.truncate", flux.Arg("t", flux.Raw("now()")), flux.Arg("unit", flux.Raw("1h"))))
+	startOfDay := s.Let("startOfDay", flux.Call("date.truncate", flux.Arg("t", flux.Raw("now()")), flux.Arg("unit", flux.Raw("1d"))))
+	data := s.Let("data", flux.From(bucket).
+		Range(startOfDay, ts).
+		Filter(flux.Eq("_measurement", flux.String("protocols_stats_totals_1h"))).
+		Drop("emitter_chain", "destination_chain", "version"))
+	return buildProtocolsTotals(s, data, startOfDay, "_time", "app_id")
+}
+
+// buildProtocolsTotals sums the messages and volume of the data by protocol, and sets the time of the results to at.
+func buildProtocolsTotals(s *flux.Script, data, at flux.Expr, rowKey ...string) flux.Query {
+	total := func(field string) *flux.Pipeline {
+		return flux.Pipe(data).
+			Filter(flux.Eq("_field", flux.String(field))).
+			Group("app_id").
+			Sum().
+			Set("_field", flux.String(field)).
+			Map(`(r) => ({r with _value: int(v: r._value)})`)
+	}
+	tvt := s.Let("tvt", total("total_value_transferred"))
+	totalMsgs := s.Let("totalMsgs", total("total_messages"))
+	return s.Build(flux.Pipe(flux.Union(tvt, totalMsgs)).
+		Set("_time", flux.Call("string", flux.Arg("v", at))).
+		Pivot(rowKey, []string{"_field"}, "_value").
+		Map(`(r) => ({r with app_id: strings.trimPrefix(v: r.app_id, prefix: "TOTAL_")})`))
+}
+
+// QueryProtocolStats24HrAgo returns the first stats of the protocol in the last day.
+func QueryProtocolStats24HrAgo(bucket, measurement, protocol string) flux.Query {
+	return flux.NewScript().Build(flux.From(bucket).
+		Range(flux.Ago(flux.Raw("1d")), nil).
+		Filter(flux.Eq("_measurement", measurement), flux.Eq("protocol", protocol)).
+		First().
+		Pivot([]string{"_time"}, []string{"_field"}, "_value"))
+}
+
+// QueryProtocolStatsNow returns the last stats of the protocol.
+func QueryProtocolStatsNow(bucket, measurement, protocol string) flux.Query {
+	return flux.NewScript().Build(flux.From(bucket).
+		Range(flux.Ago(flux.Raw("2d")), nil).
+		Filter(flux.Eq("_measurement", measurement), flux.Eq("protocol", protocol)).
+		Last().
+		Pivot([]string{"_time"}, []string{"_field"}, "_value"))
+}
+
+// QueryProtocolActivity returns the volume and transactions of the protocol since start.
+func QueryProtocolActivity(bucket string, start any, measurement, protocol string) flux.Query {
+	s := flux.NewScript()
+	data := s.Let("data", flux.From(bucket).
+		Range(start, nil).
+		Filter(flux.Eq("_measurement", measurement), flux.Eq("protocol", protocol)))
+	tvt := s.Let("tvt", flux.Pipe(data).Filter(flux.Eq("_field", flux.String("total_value_transferred"))).Sum())
+	txs := s.Let("txs", flux.Pipe(data).Filter(flux.Eq("_field", flux.String("txs"))).Sum())
+	return s.Build(flux.Pipe(flux.Union(tvt, txs)).
+		Pivot([]string{"_start", "_stop"}, []string{"_field"}, "_value").
+		Rename("_stop", "_time"))
+}
+
+// QueryLast24HrActivity returns the last activity of the protocol.
+func QueryLast24HrActivity(bucket, measurement, protocol string) flux.Query {
+	return flux.NewScript().Build(flux.From(bucket).
+		Range(flux.Ago(flux.Raw("5d")), nil).
+		Filter(flux.Eq("_measurement", measurement)).
+		Filter(flux.Eq("protocol", protocol)).
+		Last().
+		Pivot([]string{"_time"}, []string{"_field"}, "_value"))
+}
+
+// buildCCTPStats returns the first or last cctp totals of the last day.
+func buildCCTPStats(bucket string, last bool) flux.Query {
+	p := flux.From(bucket).
+		Range(flux.Ago(flux.Raw("1d")), nil).
+		Filter(flux.Eq("_measurement", flux.String("cctp_status_total_v2")))
+	if last {
+		p.Last()
+	} else {
+		p.First()
+	}
+	return flux.NewScript().Build(p.
+		Pivot([]string{"_time"}, []string{"_field"}, "_value").
+		Rename("txs", "total_messages", "volume", "total_value_transferred"))
+}
 
 type Repository struct {
 	queryAPI                QueryDoer
@@ -199,7 +166,7 @@ type stats struct {
 }
 
 type QueryDoer interface {
-	Query(ctx context.Context, query string) (QueryResult, error)
+	Query(ctx context.Context, query flux.Query) (QueryResult, error)
 }
 
 type queryApiWrapper struct {
@@ -234,17 +201,17 @@ func NewRepository(qApi QueryDoer, bucketInfinite, bucket30d, bucket24Hrs string
 	}
 }
 
-func (q *queryApiWrapper) Query(ctx context.Context, query string) (QueryResult, error) {
-	return q.qApi.Query(ctx, query)
+func (q *queryApiWrapper) Query(ctx context.Context, query flux.Query) (QueryResult, error) {
+	return q.qApi.QueryWithParams(ctx, query.Text, query.Params)
 }
 
 func (r *Repository) getProtocolStatsNow(ctx context.Context, protocol string) (rowStat, error) {
-	q := fmt.Sprintf(QueryTemplateProtocolStatsNow, r.bucket30d, dbconsts.ProtocolsStatsMeasurementHourly, protocol)
+	q := QueryProtocolStatsNow(r.bucket30d, dbconsts.ProtocolsStatsMeasurementHourly, protocol)
 	return fetchSingleRecord[rowStat](ctx, r.logger, r.queryAPI, q, protocol)
 }
 
 func (r *Repository) getProtocolStats24hrAgo(ctx context.Context, protocol string) (rowStat, error) {
-	q := fmt.Sprintf(QueryTemplateProtocolStats24HrAgo, r.bucket30d, dbconsts.ProtocolsStatsMeasurementHourly, protocol)
+	q := QueryProtocolStats24HrAgo(r.bucket30d, dbconsts.ProtocolsStatsMeasurementHourly, protocol)
 	return fetchSingleRecord[rowStat](ctx, r.logger, r.queryAPI, q, protocol)
 }
 
@@ -252,21 +219,21 @@ func (r *Repository) getAllbridgeActivity(ctx context.Context) (rowActivity, err
 
 	const allbridge = "allbridge"
 
-	q := fmt.Sprintf(QueryTemplateProtocolActivity, r.bucketInfinite, "1970-01-01T00:00:00Z", dbconsts.ProtocolsActivityMeasurementDaily, allbridge)
+	q := QueryProtocolActivity(r.bucketInfinite, flux.Epoch, dbconsts.ProtocolsActivityMeasurementDaily, allbridge)
 	activityDaily, err := fetchSingleRecord[rowActivity](ctx, r.logger, r.queryAPI, q, allbridge)
 	if err != nil {
 		r.logger.Error("error fetching latest daily activity", zap.Error(err))
 		return rowActivity{}, err
 	}
-	startOfDay := time.Now().UTC().Truncate(24 * time.Hour).Format(time.RFC3339)
-	q = fmt.Sprintf(QueryTemplateProtocolActivity, r.bucket30d, startOfDay, dbconsts.ProtocolsActivityMeasurementHourly, allbridge)
+	startOfDay := time.Now().UTC().Truncate(24 * time.Hour)
+	q = QueryProtocolActivity(r.bucket30d, startOfDay, dbconsts.ProtocolsActivityMeasurementHourly, allbridge)
 	activityHourly, err := fetchSingleRecord[rowActivity](ctx, r.logger, r.queryAPI, q, allbridge)
 	if err != nil {
 		r.logger.Error("error fetching latest hourly activity", zap.Error(err))
 		return rowActivity{}, err
 	}
 
-	q = fmt.Sprintf(QueryLast24HrActivity, r.bucketInfinite, dbconsts.ProtocolsActivityMeasurementDaily, allbridge)
+	q = QueryLast24HrActivity(r.bucketInfinite, dbconsts.ProtocolsActivityMeasurementDaily, allbridge)
 	last24HrActivity, err := fetchSingleRecord[rowActivity](ctx, r.logger, r.queryAPI, q, allbridge)
 	if err != nil {
 		r.logger.Error("error fetching last 24 hr activity", zap.Error(err))
@@ -283,14 +250,14 @@ func (r *Repository) getAllbridgeActivity(ctx context.Context) (rowActivity, err
 
 func (r *Repository) getAllProtocolStats(ctx context.Context) ([]intStats, error) {
 	// calculate total values till the start of current day
-	totalTillCurrentDayQuery := fmt.Sprintf(AllProtocolStats24HrAgo, r.bucketInfinite)
+	totalTillCurrentDayQuery := AllProtocolStats24HrAgo(r.bucketInfinite)
 	recordsTillCurrentDay, err := fetchMultipleRecords[intRowStat](ctx, r.logger, r.queryAPI, totalTillCurrentDayQuery)
 	if err != nil {
 		return nil, err
 	}
 
 	// calculate delta since the beginning of current day
-	currentDayStatsQuery := fmt.Sprintf(AllProtocolsDeltaSinceStartOfDay, r.bucket30d)
+	currentDayStatsQuery := AllProtocolsDeltaSinceStartOfDay(r.bucket30d)
 	recordsCurrentDay, err := fetchMultipleRecords[intRowStat](ctx, r.logger, r.queryAPI, currentDayStatsQuery)
 	if err != nil {
 		return nil, err
@@ -298,7 +265,7 @@ func (r *Repository) getAllProtocolStats(ctx context.Context) ([]intStats, error
 
 	latestTotal := mergeStats(recordsTillCurrentDay, recordsCurrentDay)
 
-	q3 := fmt.Sprintf(AllProtocolsDeltaLastDay, r.bucket30d)
+	q3 := AllProtocolsDeltaLastDay(r.bucket30d)
 	deltaYesterdayStats, errQ3 := fetchMultipleRecords[intRowStat](ctx, r.logger, r.queryAPI, q3)
 	if errQ3 != nil {
 		return nil, errQ3
@@ -347,22 +314,14 @@ func mergeStats(totalTillStartOfToday []intRowStat, currentDay []intRowStat) []i
 
 func (r *Repository) getCCTPStats(ctx context.Context, protocol string) (intStats, error) {
 
-	queryTemplate := `
-		from(bucket: "%s")
-			|> range(start: -1d)
-			|> filter(fn: (r) => r._measurement == "cctp_status_total_v2")
-			%s
-			|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-			|> rename(columns: {txs: "total_messages", volume: "total_value_transferred"})
-	`
-	q := fmt.Sprintf(queryTemplate, r.bucket24Hrs, "|> last()")
+	q := buildCCTPStats(r.bucket24Hrs, true)
 	statsData, err := fetchSingleRecord[intRowStat](ctx, r.logger, r.queryAPI, q, protocol)
 	if err != nil {
 		r.logger.Error("error fetching cctp totals stats", zap.Error(err))
 		return intStats{}, err
 	}
 
-	q = fmt.Sprintf(queryTemplate, r.bucket24Hrs, "|> first()")
+	q = buildCCTPStats(r.bucket24Hrs, false)
 	totals24HrAgo, err := fetchSingleRecord[intRowStat](ctx, r.logger, r.queryAPI, q, protocol)
 	if err != nil {
 		r.logger.Error("error fetching cctp totals stats", zap.Error(err))
@@ -384,25 +343,25 @@ func (r *Repository) getCCTPStats(ctx context.Context, protocol string) (intStat
 
 }
 
-func fetchMultipleRecords[T any](ctx context.Context, logger *zap.Logger, queryAPI QueryDoer, query string) ([]T, error) {
+func fetchMultipleRecords[T any](ctx context.Context, logger *zap.Logger, queryAPI QueryDoer, query flux.Query) ([]T, error) {
 
 	result := make([]T, 0)
 	resp, err := queryAPI.Query(ctx, query)
 	if err != nil {
-		logger.Error("error executing query to fetch data", zap.Error(err), zap.String("query", query))
+		logger.Error("error executing query to fetch data", zap.Error(err), zap.String("query", query.Text))
 		return result, err
 	}
 	defer resp.Close()
 
 	for resp.Next() {
 		if resp.Err() != nil {
-			logger.Error("error reading query response", zap.Error(resp.Err()), zap.String("query", query))
+			logger.Error("error reading query response", zap.Error(resp.Err()), zap.String("query", query.Text))
 			return result, resp.Err()
 		}
 		var res T
 		err = mapstructure.Decode(resp.Record().Values(), &res)
 		if err != nil {
-			logger.Error("error decoding query response", zap.Error(err), zap.String("query", query))
+			logger.Error("error decoding query response", zap.Error(err), zap.String("query", query.Text))
 			return result, err
 		}
 		result = append(result, res)
@@ -410,21 +369,21 @@ func fetchMultipleRecords[T any](ctx context.Context, logger *zap.Logger, queryA
 	return result, nil
 }
 
-func fetchSingleRecord[T any](ctx context.Context, logger *zap.Logger, queryAPI QueryDoer, query, protocol string) (T, error) {
+func fetchSingleRecord[T any](ctx context.Context, logger *zap.Logger, queryAPI QueryDoer, query flux.Query, protocol string) (T, error) {
 	var res T
 	result, err := queryAPI.Query(ctx, query)
 	if err != nil {
-		logger.Error("error executing query to fetch data", zap.Error(err), zap.String("protocol", protocol), zap.String("query", query))
+		logger.Error("error executing query to fetch data", zap.Error(err), zap.String("protocol", protocol), zap.String("query", query.Text))
 		return res, err
 	}
 	defer result.Close()
 
 	if !result.Next() {
 		if result.Err() != nil {
-			logger.Error("error reading query response", zap.Error(result.Err()), zap.String("protocol", protocol), zap.String("query", query))
+			logger.Error("error reading query response", zap.Error(result.Err()), zap.String("protocol", protocol), zap.String("query", query.Text))
 			return res, result.Err()
 		}
-		logger.Info("empty query response", zap.String("protocol", protocol), zap.String("query", query))
+		logger.Info("empty query response", zap.String("protocol", protocol), zap.String("query", query.Text))
 		return res, err
 	}
 
@@ -23,14 +23,16 @@ const ALLBRIDGE = "ALLBRIDGE"
 const UNKNOWN = "UNKNOWN"
 
 type Service struct {
-	protocols      []string
-	repo           *Repository
-	logger         *zap.Logger
-	cache          cache.Cache
-	cacheKeyPrefix string
-	cacheTTL       int
-	metrics        metrics.Metrics
-	tvl            tvlProvider
+	protocols []string
+	// adapterProtocols are the protocols defined with an adapter in the protocols stats job.
+	adapterProtocols []string
+	repo             *Repository
+	logger           *zap.Logger
+	cache            cache.Cache
+	cacheKeyPrefix   string
+	cacheTTL         int
+	metrics          metrics.Metrics
+	tvl              tvlProvider
 }
 
 type ProtocolTotalValuesDTO struct {
@@ -56,16 +58,17 @@ type tvlProvider interface {
 
 type fetchProtocolTotalValues func(context.Context, string) (ProtocolStats, error)
 
-func NewService(protocols []string, repo *Repository, logger *zap.Logger, cache cache.Cache, cacheKeyPrefix string, cacheTTL int, metrics metrics.Metrics, tvlProvider tvlProvider) *Service {
+func NewService(protocols, adapterProtocols []string, repo *Repository, logger *zap.Logger, cache cache.Cache, cacheKeyPrefix string, cacheTTL int, metrics metrics.Metrics, tvlProvider tvlProvider) *Service {
 	return &Service{
-		protocols:      protocols,
-		repo:           repo,
-		logger:         logger,
-		cache:          cache,
-		cacheKeyPrefix: cacheKeyPrefix,
-		cacheTTL:       cacheTTL,
-		metrics:        metrics,
-		tvl:            tvlProvider,
+		protocols:        protocols,
+		adapterProtocols: adapterProtocols,
+		repo:             repo,
+		logger:           logger,
+		cache:            cache,
+		cacheKeyPrefix:   cacheKeyPrefix,
+		cacheTTL:         cacheTTL,
+		metrics:          metrics,
+		tvl:              tvlProvider,
 	}
 }
 
@@ -127,13 +130,17 @@ func (s *Service) fetchProtocolValues(ctx context.Context, wg *sync.WaitGroup, p
 
 func (s *Service) getProtocolTotalValuesFn(protocol string) fetchProtocolTotalValues {
 	switch protocol {
-	case MAYAN:
-		return s.getMayanStats
 	case ALLBRIDGE:
 		return s.getAllbridgeStats
 	case CCTP:
 		return s.getCCTPStats
+	case MAYAN:
+		return s.getJobProtocolStats
 	default:
+		// only the adapter protocols are stored by the job, any other name would return empty stats.
+		if slices.Contains(s.adapterProtocols, protocol) {
+			return s.getJobProtocolStats
+		}
 		return func(_ context.Context, _ string) (ProtocolStats, error) {
 			return ProtocolStats{Protocol: protocol}, fmt.Errorf("unsupported protocol %s", protocol)
 		}
@@ -271,31 +278,40 @@ func (s *Service) getCCTPStats(ctx context.Context, protocol string) (ProtocolSt
 	return val, nil
 }
 
-func (s *Service) getMayanStats(ctx context.Context, _ string) (ProtocolStats, error) {
-	const mayan = "mayan"
-	mayanNow, errStats := s.repo.getProtocolStatsNow(ctx, mayan)
+// getJobProtocolStats returns the stats stored by the protocols stats job, which is the source of MAYAN
+// and of the protocols defined with an adapter in the job configuration.
+func (s *Service) getJobProtocolStats(ctx context.Context, protocol string) (ProtocolStats, error) {
+	name := strings.ToLower(protocol)
+	statsNow, errStats := s.repo.getProtocolStatsNow(ctx, name)
 	if errStats != nil {
-		s.logger.Error("error fetching Mayan stats", zap.Error(errStats), zap.String("protocol", mayan))
-		return ProtocolStats{Protocol: mayan}, errStats
+		s.logger.Error("error fetching protocol stats", zap.Error(errStats), zap.String("protocol", name))
+		return ProtocolStats{Protocol: name}, errStats
 	}
-	mayan24hrAgo, errStats := s.repo.getProtocolStats24hrAgo(ctx, mayan)
+	stats24hrAgo, errStats := s.repo.getProtocolStats24hrAgo(ctx, name)
 	if errStats != nil {
-		s.logger.Error("error fetching Mayan stats 24hr ago", zap.Error(errStats), zap.String("protocol", mayan))
-		return ProtocolStats{Protocol: mayan}, errStats
+		s.logger.Error("error fetching protocol stats 24hr ago", zap.Error(errStats), zap.String("protocol", name))
+		return ProtocolStats{Protocol: name}, errStats
 	}
 
-	last24HrMessages := mayanNow.TotalMessages - mayan24hrAgo.TotalMessages
-	last24HrVolume := mayanNow.Volume - mayan24hrAgo.Volume
-	return ProtocolStats{
-		Protocol:                    mayan,
-		TotalValueLocked:            mayanNow.TotalValueLocked,
-		TotalMessages:               mayanNow.TotalMessages,
-		TotalValueTransferred:       mayanNow.Volume,
+	last24HrMessages := statsNow.TotalMessages - stats24hrAgo.TotalMessages
+	last24HrVolume := statsNow.Volume - stats24hrAgo.Volume
+	dto := ProtocolStats{
+		Protocol:                    name,
+		TotalValueLocked:            statsNow.TotalValueLocked,
+		TotalMessages:               statsNow.TotalMessages,
+		TotalValueTransferred:       statsNow.Volume,
 		LastDayMessages:             last24HrMessages,
-		Last24HourVolume:            mayanNow.Volume - mayan24hrAgo.Volume,
-		LastDayDiffPercentage:       strconv.FormatFloat(float64(last24HrMessages)/float64(mayan24hrAgo.TotalMessages)*100, 'f', 2, 64) + "%",
-		LastDayVolumeDiffPercentage: strconv.FormatFloat(last24HrVolume/mayan24hrAgo.Volume*100, 'f', 2, 64) + "%",
-	}, nil
+		Last24HourVolume:            last24HrVolume,
+		LastDayDiffPercentage:       "0.00%",
+		LastDayVolumeDiffPercentage: "0.00%",
+	}
+	if stats24hrAgo.TotalMessages != 0 {
+		dto.LastDayDiffPercentage = strconv.FormatFloat(float64(last24HrMessages)/float64(stats24hrAgo.TotalMessages)*100, 'f', 2, 64) + "%"
+	}
+	if stats24hrAgo.Volume != 0 {
+		dto.LastDayVolumeDiffPercentage = strconv.FormatFloat(last24HrVolume/stats24hrAgo.Volume*100, 'f', 2, 64) + "%"
+	}
+	return dto, nil
 }
 
 func (s *Service) getAllbridgeStats(ctx context.Context, _ string) (ProtocolStats, error) {
@@ -12,6 +12,7 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
 	cacheMock "github.com/wormhole-foundation/wormhole-explorer/common/client/cache/mock"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbconsts"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
 	"go.uber.org/zap"
 	"testing"
 	"time"
@@ -73,19 +74,19 @@ func TestService_GetProtocolsTotalValues_Allbridge(t *testing.T) {
 
 	ctx := context.Background()
 	queryAPI := &mockQueryAPI{}
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolStatsNow, "bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLatest, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolStats24HrAgo, "bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLastDay, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolActivity, "bucketInfinite", "1970-01-01T00:00:00Z", dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(respActivityLast, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolActivity, "bucket30d", ts.Truncate(24*time.Hour).Format(time.RFC3339), dbconsts.ProtocolsActivityMeasurementHourly, allbridge)).Return(respActivity2, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryLast24HrActivity, "bucketInfinite", dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(last24respActivity, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStatsNow("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLatest, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStats24HrAgo("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLastDay, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolActivity("bucketInfinite", flux.Epoch, dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(respActivityLast, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolActivity("bucket30d", ts.Truncate(24*time.Hour), dbconsts.ProtocolsActivityMeasurementHourly, allbridge)).Return(respActivity2, nil)
+	queryAPI.On("Query", ctx, protocols.QueryLast24HrActivity("bucketInfinite", dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(last24respActivity, nil)
 
 	// core protocols influx calls
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolStats24HrAgo, "bucketInfinite")).Return(emptyQueryTableResult(), nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaSinceStartOfDay, "bucket30d")).Return(emptyQueryTableResult(), nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaLastDay, "bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolStats24HrAgo("bucketInfinite")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaSinceStartOfDay("bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaLastDay("bucket30d")).Return(emptyQueryTableResult(), nil)
 
 	repository := protocols.NewRepository(queryAPI, "bucketInfinite", "bucket30d", "bucket24hr", zap.NewNop())
-	service := protocols.NewService([]string{protocols.ALLBRIDGE}, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
+	service := protocols.NewService([]string{protocols.ALLBRIDGE}, nil, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
 
 	values := service.GetProtocolsTotalValues(ctx)
 	assert.Equal(t, 1, len(values))
@@ -124,17 +125,17 @@ func TestService_GetProtocolsTotalValues_Allbridge_FailedFetchingActivity(t *tes
 	ctx := context.Background()
 	queryAPI := &mockQueryAPI{}
 	// Allbridge influx calls
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolStatsNow, "bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLatest, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolStats24HrAgo, "bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLastDay, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolActivity, "bucketInfinite", "1970-01-01T00:00:00Z", dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(&mockQueryTableResult{}, errors.New("mocked_error"))
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStatsNow("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLatest, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStats24HrAgo("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLastDay, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolActivity("bucketInfinite", flux.Epoch, dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(&mockQueryTableResult{}, errors.New("mocked_error"))
 
 	// core protocols influx calls
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolStats24HrAgo, "bucketInfinite")).Return(emptyQueryTableResult(), nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaSinceStartOfDay, "bucket30d")).Return(emptyQueryTableResult(), nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaLastDay, "bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolStats24HrAgo("bucketInfinite")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaSinceStartOfDay("bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaLastDay("bucket30d")).Return(emptyQueryTableResult(), nil)
 
 	repository := protocols.NewRepository(queryAPI, "bucketInfinite", "bucket30d", "bucket24hr", zap.NewNop())
-	service := protocols.NewService([]string{protocols.ALLBRIDGE}, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
+	service := protocols.NewService([]string{protocols.ALLBRIDGE}, nil, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
 
 	values := service.GetProtocolsTotalValues(ctx)
 	assert.Equal(t, 1, len(values))
@@ -183,19 +184,19 @@ func TestService_GetProtocolsTotalValues_Allbridge_FailedFetchingStats(t *testin
 
 	ctx := context.Background()
 	queryAPI := &mockQueryAPI{}
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolStatsNow, "bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(&mockQueryTableResult{}, errors.New("mocked_error"))
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolStats24HrAgo, "bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLastDay, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolActivity, "bucketInfinite", "1970-01-01T00:00:00Z", dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(respActivityLast, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolActivity, "bucket30d", ts.Truncate(24*time.Hour).Format(time.RFC3339), dbconsts.ProtocolsActivityMeasurementHourly, allbridge)).Return(respActivity2, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryLast24HrActivity, "bucketInfinite", dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(respActivity2, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStatsNow("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(&mockQueryTableResult{}, errors.New("mocked_error"))
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStats24HrAgo("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, allbridge)).Return(respStatsLastDay, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolActivity("bucketInfinite", flux.Epoch, dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(respActivityLast, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolActivity("bucket30d", ts.Truncate(24*time.Hour), dbconsts.ProtocolsActivityMeasurementHourly, allbridge)).Return(respActivity2, nil)
+	queryAPI.On("Query", ctx, protocols.QueryLast24HrActivity("bucketInfinite", dbconsts.ProtocolsActivityMeasurementDaily, allbridge)).Return(respActivity2, nil)
 
 	// core protocols influx calls
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolStats24HrAgo, "bucketInfinite")).Return(emptyQueryTableResult(), nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaSinceStartOfDay, "bucket30d")).Return(emptyQueryTableResult(), nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaLastDay, "bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolStats24HrAgo("bucketInfinite")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaSinceStartOfDay("bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaLastDay("bucket30d")).Return(emptyQueryTableResult(), nil)
 
 	repository := protocols.NewRepository(queryAPI, "bucketInfinite", "bucket30d", "bucket24hr", zap.NewNop())
-	service := protocols.NewService([]string{protocols.ALLBRIDGE}, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
+	service := protocols.NewService([]string{protocols.ALLBRIDGE}, nil, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
 
 	values := service.GetProtocolsTotalValues(ctx)
 	assert.Equal(t, 1, len(values))
@@ -229,16 +230,16 @@ func TestService_GetProtocolsTotalValues_Mayan(t *testing.T) {
 
 	ctx := context.Background()
 	queryAPI := &mockQueryAPI{}
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolStatsNow, "bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, mayan)).Return(respStatsLatest, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.QueryTemplateProtocolStats24HrAgo, "bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, mayan)).Return(respStatsLastDay, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStatsNow("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, mayan)).Return(respStatsLatest, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStats24HrAgo("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, mayan)).Return(respStatsLastDay, nil)
 
 	// core protocols influx calls
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolStats24HrAgo, "bucketInfinite")).Return(emptyQueryTableResult(), nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaSinceStartOfDay, "bucket30d")).Return(emptyQueryTableResult(), nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaLastDay, "bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolStats24HrAgo("bucketInfinite")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaSinceStartOfDay("bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaLastDay("bucket30d")).Return(emptyQueryTableResult(), nil)
 
 	repository := protocols.NewRepository(queryAPI, "bucketInfinite", "bucket30d", "bucket24hr", zap.NewNop())
-	service := protocols.NewService([]string{protocols.MAYAN}, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
+	service := protocols.NewService([]string{protocols.MAYAN}, nil, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
 
 	values := service.GetProtocolsTotalValues(ctx)
 	assert.Equal(t, 1, len(values))
@@ -251,6 +252,74 @@ func TestService_GetProtocolsTotalValues_Mayan(t *testing.T) {
 
 }
 
+func TestService_GetProtocolsTotalValues_AdapterProtocol(t *testing.T) {
+	const acme = "acme"
+	var errNil error
+	respStatsLatest := &mockQueryTableResult{}
+	respStatsLatest.On("Next").Return(true)
+	respStatsLatest.On("Err").Return(errNil)
+	respStatsLatest.On("Close").Return(errNil)
+	respStatsLatest.On("Record").Return(query.NewFluxRecord(1, map[string]interface{}{
+		"protocol":           acme,
+		"total_messages":     uint64(7),
+		"total_value_locked": float64(20),
+		"volume":             float64(10),
+	}))
+
+	// the protocol had no activity before the last day.
+	respStatsLastDay := &mockQueryTableResult{}
+	respStatsLastDay.On("Next").Return(true)
+	respStatsLastDay.On("Err").Return(errNil)
+	respStatsLastDay.On("Close").Return(errNil)
+	respStatsLastDay.On("Record").Return(query.NewFluxRecord(1, map[string]interface{}{
+		"protocol":       acme,
+		"total_messages": uint64(0),
+		"volume":         float64(0),
+	}))
+
+	ctx := context.Background()
+	queryAPI := &mockQueryAPI{}
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStatsNow("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, acme)).Return(respStatsLatest, nil)
+	queryAPI.On("Query", ctx, protocols.QueryProtocolStats24HrAgo("bucket30d", dbconsts.ProtocolsStatsMeasurementHourly, acme)).Return(respStatsLastDay, nil)
+
+	// core protocols influx calls
+	queryAPI.On("Query", ctx, protocols.AllProtocolStats24HrAgo("bucketInfinite")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaSinceStartOfDay("bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaLastDay("bucket30d")).Return(emptyQueryTableResult(), nil)
+
+	repository := protocols.NewRepository(queryAPI, "bucketInfinite", "bucket30d", "bucket24hr", zap.NewNop())
+	service := protocols.NewService([]string{"ACME"}, []string{"ACME"}, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
+
+	values := service.GetProtocolsTotalValues(ctx)
+	assert.Equal(t, 1, len(values))
+	assert.Empty(t, values[0].Error)
+	assert.Equal(t, acme, values[0].Protocol)
+	assert.Equal(t, uint64(7), values[0].TotalMessages)
+	assert.Equal(t, float64(20), values[0].TotalValueLocked)
+	assert.Equal(t, uint64(7), values[0].LastDayMessages)
+	assert.Equal(t, "0.00%", values[0].LastDayDiffPercentage)
+	assert.Equal(t, "0.00%", values[0].LastDayVolumeDiffPercentage)
+}
+
+func TestService_GetProtocolsTotalValues_UnsupportedProtocol(t *testing.T) {
+
+	ctx := context.Background()
+	queryAPI := &mockQueryAPI{}
+
+	// core protocols influx calls
+	queryAPI.On("Query", ctx, protocols.AllProtocolStats24HrAgo("bucketInfinite")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaSinceStartOfDay("bucket30d")).Return(emptyQueryTableResult(), nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaLastDay("bucket30d")).Return(emptyQueryTableResult(), nil)
+
+	repository := protocols.NewRepository(queryAPI, "bucketInfinite", "bucket30d", "bucket24hr", zap.NewNop())
+	service := protocols.NewService([]string{"ACME"}, nil, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
+
+	values := service.GetProtocolsTotalValues(ctx)
+	assert.Equal(t, 1, len(values))
+	assert.Equal(t, "acme", values[0].Protocol)
+	assert.Equal(t, "unsupported protocol ACME", values[0].Error)
+}
+
 func TestService_GetProtocolsTotalValues_CacheHit(t *testing.T) {
 	ctx := context.Background()
 	mockCache := &cacheMock.CacheMock{}
@@ -258,7 +327,7 @@ func TestService_GetProtocolsTotalValues_CacheHit(t *testing.T) {
 	cacheErr = nil
 	cachedValue := fmt.Sprintf(`{"result": [{"protocol":"protocol1","total_messages":7,"total_value_locked":5,"total_value_secured":9,"total_value_transferred":7,"last_day_messages":4,"last_day_diff_percentage":"75.00%%"}],"timestamp":"%s"}`, time.Now().Format(time.RFC3339))
 	mockCache.On("Get", ctx, "WORMSCAN:PROTOCOLS:ALL_PROTOCOLS").Return(cachedValue, cacheErr)
-	service := protocols.NewService([]string{}, nil, zap.NewNop(), mockCache, "WORMSCAN:PROTOCOLS", 60, metrics.NewNoOpMetrics(), &mockTvl{})
+	service := protocols.NewService([]string{}, nil, nil, zap.NewNop(), mockCache, "WORMSCAN:PROTOCOLS", 60, metrics.NewNoOpMetrics(), &mockTvl{})
 	values := service.GetProtocolsTotalValues(ctx)
 	assert.Equal(t, 1, len(values))
 	assert.Equal(t, "protocol1", values[0].Protocol)
@@ -307,12 +376,12 @@ func TestService_GetPortalTokenBridge_Stats(t *testing.T) {
 		},
 	}
 
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolStats24HrAgo, "bucketInfinite")).Return(totalStartOfCurrentDay, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaSinceStartOfDay, "bucket30d")).Return(deltaSinceStartOfDay, nil)
-	queryAPI.On("Query", ctx, fmt.Sprintf(protocols.AllProtocolsDeltaLastDay, "bucket30d")).Return(deltaLastDay, nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolStats24HrAgo("bucketInfinite")).Return(totalStartOfCurrentDay, nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaSinceStartOfDay("bucket30d")).Return(deltaSinceStartOfDay, nil)
+	queryAPI.On("Query", ctx, protocols.AllProtocolsDeltaLastDay("bucket30d")).Return(deltaLastDay, nil)
 
 	repository := protocols.NewRepository(queryAPI, "bucketInfinite", "bucket30d", "bucket24hr", zap.NewNop())
-	service := protocols.NewService([]string{}, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
+	service := protocols.NewService([]string{}, nil, repository, zap.NewNop(), cache.NewDummyCacheClient(), "WORMSCAN:PROTOCOLS", 0, metrics.NewNoOpMetrics(), &mockTvl{})
 	values := service.GetProtocolsTotalValues(ctx)
 	assert.NotNil(t, values)
 	assert.Equal(t, 1, len(values))
@@ -334,7 +403,7 @@ type mockQueryAPI struct {
 	mock.Mock
 }
 
-func (m *mockQueryAPI) Query(ctx context.Context, q string) (protocols.QueryResult, error) {
+func (m *mockQueryAPI) Query(ctx context.Context, q flux.Query) (protocols.QueryResult, error) {
 	args := m.Called(ctx, q)
 	return args.Get(0).(protocols.QueryResult), args.Error(1)
 }
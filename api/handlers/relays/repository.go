@@ -48,6 +48,25 @@ func (r *Repository) FindOne(ctx context.Context, q *RelaysQuery) (*RelayDoc, er
 	return &response, nil
 }
 
+// FindByIDs returns the relays matching the given ids ("chain/emitter/sequence").
+func (r *Repository) FindByIDs(ctx context.Context, ids []string) ([]*RelayDoc, error) {
+	cur, err := r.collections.relays.Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Find command to get relays",
+			zap.Error(err), zap.Strings("ids", ids), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+	relays := []*RelayDoc{}
+	if err := cur.All(ctx, &relays); err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed decoding cursor to []*RelayDoc",
+			zap.Error(err), zap.Strings("ids", ids), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+	return relays, nil
+}
+
 type RelaysQuery struct {
 	chainId  vaa.ChainID
 	emitter  string
@@ -33,3 +33,11 @@ func (s *Service) FindByVAA(
 
 	return s.repo.FindOne(ctx, query)
 }
+
+// FindByIDs get the relays of the given VAA ids ("chain/emitter/sequence").
+func (s *Service) FindByIDs(ctx context.Context, ids []string) ([]*RelayDoc, error) {
+	if len(ids) == 0 {
+		return []*RelayDoc{}, nil
+	}
+	return s.repo.FindByIDs(ctx, ids)
+}
@@ -1,173 +1,87 @@
 package stats
 
 import (
-	"fmt"
 	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+	"github.com/wormhole-foundation/wormhole-explorer/common/stats"
 )
 
-const queryTemplateSymbolWithAssets = `
-from(bucket: "%s")
-    |> range(start: %s)
-    |> filter(fn: (r) => r._measurement == "%s" and r._field == "txs_volume")
-    |> last()
-    |> group()
-`
-
-func buildSymbolWithAssets(bucket string, t time.Time, measurement string) string {
-	start := t.Truncate(time.Hour * 24).Format(time.RFC3339Nano)
-	return fmt.Sprintf(queryTemplateSymbolWithAssets, bucket, start, measurement)
+// buildLastValues returns the last value of a field of the measurement, updated since the start of the day.
+func buildLastValues(bucket string, t time.Time, measurement, field string) flux.Query {
+	return flux.NewScript().Build(flux.From(bucket).
+		Range(t.Truncate(time.Hour*24), nil).
+		Filter(flux.Eq("_measurement", measurement), flux.Eq("_field", flux.String(field))).
+		Last().
+		Group())
+}
+
+func buildSymbolWithAssets(bucket string, t time.Time, measurement string) flux.Query {
+	return buildLastValues(bucket, t, measurement, "txs_volume")
+}
+
+func buildTopCorridors(bucket string, t time.Time, measurement string) flux.Query {
+	return buildLastValues(bucket, t, measurement, "count")
 }
 
-const queryTemplateTopCorridors = `
-from(bucket: "%s")
-    |> range(start: %s)
-    |> filter(fn: (r) => r._measurement == "%s" and r._field == "count")
-    |> last()
-    |> group()
-`
-
-func buildTopCorridors(bucket string, t time.Time, measurement string) string {
-	start := t.Truncate(time.Hour * 24).Format(time.RFC3339Nano)
-	return fmt.Sprintf(queryTemplateTopCorridors, bucket, start, measurement)
+// buildNTTTotal returns the total of the native token transfers of the symbol with a different
+// source and destination chain: the sum of the daily totals until today plus the transfers of today.
+func buildNTTTotal(bucket string, t time.Time, symbol string, isNotional bool) flux.Query {
+	today := flux.Param("today", t.Truncate(time.Hour*24))
+	field := "total_transferred"
+	aggregation := "count"
+	if isNotional {
+		field = "total_volume_transferred"
+		aggregation = "sum"
+	}
+
+	s := flux.NewScript("influxdata/influxdb/schema")
+	last := flux.From(bucket).
+		Range(flux.Epoch, today).
+		Filter(flux.Eq("_measurement", flux.String("ntt_symbol_chain_1d")), flux.Eq("_field", flux.String(field))).
+		Filter(flux.Eq("symbol", symbol), flux.Ne("emitter_chain", flux.Column("destination_chain"))).
+		Group().
+		Sum()
+	current := stats.NTTTransfers(bucket, today, nil).
+		Filter(flux.Eq("symbol", symbol)).
+		Map(`(r) => ({r with _value: r.volume})`).
+		Group().
+		Then(aggregation)
+	if isNotional {
+		last.ToFloat().Map(`(r) => ({r with _value: r._value / 100000000.0})`)
+		current.ToFloat().Map(`(r) => ({r with _value: r._value / 100000000.0})`)
+	}
+
+	return s.Build(flux.Pipe(flux.Union(s.Let("current", current), s.Let("last", last))).
+		Group().
+		Sum())
 }
 
-const queryTemplateNTTTotalValueTokenTransferred = `
-import "influxdata/influxdb/schema"
-import "date"
-
-bucket = "%s"
-today =  %s
-
-last = from(bucket: bucket)
-  |> range(start: 1970-01-01T00:00:00Z, stop: today)
-  |> filter(fn: (r) => r._measurement == "ntt_symbol_chain_1d" and r._field == "total_volume_transferred" )
-	|> filter(fn: (r) => r.symbol == "W" and r.emitter_chain != r.destination_chain)
-	|> group()
-	|> sum()
-	|> toFloat()
-	|> map(fn: (r) => ({r with _value: r._value / 100000000.0}))
-
-current = from(bucket: bucket)
-  |> range(start: today)
-	|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-	|> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-	|> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-	|> schema.fieldsAsCols()
-	|> filter(fn: (r) => r.symbol == "%s")
-	|> map(fn: (r) => ({r with _value: r.volume}))
-	|> group()
-	|> sum()
-	|> toFloat()
-	|> map(fn: (r) => ({r with _value: r._value / 100000000.0}))
-	
-	
-union(tables: [current, last])
-  |> group()
-  |> sum()
-`
-
-func buildNTTTotalValueTokenTransferred(bucket string, t time.Time, symbol string) string {
-	start := t.Truncate(time.Hour * 24).Format(time.RFC3339Nano)
-	return fmt.Sprintf(queryTemplateNTTTotalValueTokenTransferred, bucket, start, symbol)
+func buildNTTTotalValueTokenTransferred(bucket string, t time.Time, symbol string) flux.Query {
+	return buildNTTTotal(bucket, t, symbol, true)
 }
 
-const queryTemplateNTTTotalTokenTransferred = `
-import "influxdata/influxdb/schema"
-import "date"
-
-bucket = "%s"
-today =  %s
-
-last = from(bucket: bucket)
-  |> range(start: 1970-01-01T00:00:00Z, stop: today)
-  |> filter(fn: (r) => r._measurement == "ntt_symbol_chain_1d" and r._field == "total_transferred" )
-	|> filter(fn: (r) => r.symbol == "W" and r.emitter_chain != r.destination_chain)
-	|> group()
-	|> sum()
-
-current = from(bucket: bucket)
-  |> range(start: today)
-	|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-	|> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-	|> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-	|> schema.fieldsAsCols()
-	|> filter(fn: (r) => r.symbol == "%s")
-	|> map(fn: (r) => ({r with _value: r.volume}))
-	|> group()
-	|> count()
-	
-	
-union(tables: [current, last])
-  |> group()
-  |> sum()
-`
-
-func buildNTTTotalTokenTransferred(bucket string, t time.Time, symbol string) string {
-	start := t.Truncate(time.Hour * 24).Format(time.RFC3339Nano)
-	return fmt.Sprintf(queryTemplateNTTTotalTokenTransferred, bucket, start, symbol)
+func buildNTTTotalTokenTransferred(bucket string, t time.Time, symbol string) flux.Query {
+	return buildNTTTotal(bucket, t, symbol, false)
 }
 
-const queryTemplateNTTAverageTransferSize = `
-import "influxdata/influxdb/schema"
-import "date"
-
-bucket = "%s"
-
-from(bucket: bucket)
-  |> range(start: 2021-01-01T00:00:00Z)
-	|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-	|> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-	|> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-	|> schema.fieldsAsCols()
-	|> filter(fn: (r) => r.symbol == "%s")
-	|> map(fn: (r) => ({r with _value: r.volume}))
-	|> group()
-	|> mean()
-	|> toFloat()
-	|> map(fn: (r) => ({r with _value: r._value / 100000000.0}))
-`
-
-func buildNTTAverageTransferSize(bucket string, symbol string) string {
-	return fmt.Sprintf(queryTemplateNTTAverageTransferSize, bucket, symbol)
+func buildNTTAverageTransferSize(bucket string, symbol string) flux.Query {
+	s := flux.NewScript("influxdata/influxdb/schema")
+	return s.Build(stats.NTTTransfers(bucket, flux.Raw("2021-01-01T00:00:00Z"), nil).
+		Filter(flux.Eq("symbol", symbol)).
+		Map(`(r) => ({r with _value: r.volume})`).
+		Group().
+		Then("mean").
+		ToFloat().
+		Map(`(r) => ({r with _value: r._value / 100000000.0})`))
 }
 
-const queryTemplateNTTChainActivity = `
-import "influxdata/influxdb/schema"
-import "strings"
-
-bucket = "%s"
-today = %s
-field = "%s"
-
-last = from(bucket: bucket)
-    |> range(start: 1970-01-01T00:00:00Z, stop: today)
-    |> filter(fn: (r) => r._measurement == "ntt_symbol_chain_1d" and r._field == field)
-    |> filter(fn: (r) => %s)
-	|> group(columns:["symbol","emitter_chain","destination_chain"])
-	|> sum()
-
-current = from(bucket: bucket)
-    |> range(start: today)
-    |> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-    |> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-    |> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-    |> schema.fieldsAsCols()
-    |> filter(fn: (r) => r.symbol != "")
-    |> map(fn: (r) => ({r with symbol: strings.toUpper(v: r.symbol)}))
-    |> filter(fn: (r) => %s)
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> map(fn: (r) => ({r with _value: r.volume}))
-    |> %s()
-	
-union(tables: [current, last])
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> sum()
-`
-
-func buildNTTChainActivity(bucket string, t time.Time, symbol string, isNotional bool) string {
-	filterCondition := fmt.Sprintf(`r.symbol == "%s"`, symbol)
-	if symbol == "" {
-		filterCondition = "true"
+// buildNTTChainActivity returns the native token transfers by symbol and pair of chains.
+// An empty symbol returns the transfers of all the symbols.
+func buildNTTChainActivity(bucket string, t time.Time, symbol string, isNotional bool) flux.Query {
+	var filterSymbol flux.Expr
+	if symbol != "" {
+		filterSymbol = flux.Eq("symbol", symbol)
 	}
 	field := "total_transferred"
 	aggregation := "count"
@@ -175,29 +89,53 @@ func buildNTTChainActivity(bucket string, t time.Time, symbol string, isNotional
 		field = "total_volume_transferred"
 		aggregation = "sum"
 	}
-	start := t.Truncate(time.Hour * 24).Format(time.RFC3339Nano)
-	return fmt.Sprintf(queryTemplateNTTChainActivity, bucket, start, field, filterCondition, filterCondition, aggregation)
+	today := flux.Param("today", t.Truncate(time.Hour*24))
+	groupBy := []string{"symbol", "emitter_chain", "destination_chain"}
+
+	s := flux.NewScript("influxdata/influxdb/schema", "strings")
+	last := s.Let("last", flux.From(bucket).
+		Range(flux.Epoch, today).
+		Filter(flux.Eq("_measurement", flux.String("ntt_symbol_chain_1d")), flux.Eq("_field", flux.String(field))).
+		Filter(filterSymbol).
+		Group(groupBy...).
+		Sum())
+	current := s.Let("current", stats.NTTTransfers(bucket, today, nil).
+		Filter(flux.Ne("symbol", flux.String(""))).
+		Map(`(r) => ({r with symbol: strings.toUpper(v: r.symbol)})`).
+		Filter(filterSymbol).
+		Group(groupBy...).
+		Map(`(r) => ({r with _value: r.volume})`).
+		Then(aggregation))
+
+	return s.Build(flux.Pipe(flux.Union(current, last)).
+		Group(groupBy...).
+		Sum())
 }
 
-const queryTemplateNTTChainActivityByTime = `
-start = %s
-stop =  %s
-bucket = "%s"
-symbol = "%s"
-
-from(bucket: bucket)
-		|> range(start: start, stop: stop)
-		|> filter(fn: (r) => r._measurement == "ntt_symbol_chain_1d" and r._field == "%s" )
-		|> filter(fn: (r) => r.symbol == symbol)
-		|> group(columns: ["symbol"])
-		|> aggregateWindow(every: %s, fn: %s, createEmpty: true)`
-
-func buildNTTChainActivityByTime(bucket string, start, stop, symbol string, isNotional bool, every string) string {
+// buildNTTChainActivityByTime returns the native token transfers of the symbol aggregated in
+// windows of the given duration. Hourly windows are computed from the transfers, while
+// longer windows are computed from the daily totals.
+func buildNTTChainActivityByTime(bucket string, start, stop time.Time, symbol string, isNotional bool, every flux.Duration) flux.Query {
 	aggregation := "count"
 	field := "total_transferred"
 	if isNotional {
 		aggregation = "sum"
 		field = "total_volume_transferred"
 	}
-	return fmt.Sprintf(queryTemplateNTTChainActivityByTime, start, stop, bucket, symbol, field, every, aggregation)
+
+	if every == flux.Duration(HourNttTimespan) {
+		s := flux.NewScript("influxdata/influxdb/schema")
+		return s.Build(stats.NTTTransfers(bucket, start, stop).
+			Filter(flux.Eq("symbol", symbol)).
+			Group().
+			Map(`(r) => ({r with _value: r.volume})`).
+			AggregateWindow(every, aggregation, true))
+	}
+
+	return flux.NewScript().Build(flux.From(bucket).
+		Range(start, stop).
+		Filter(flux.Eq("_measurement", flux.String("ntt_symbol_chain_1d")), flux.Eq("_field", flux.String(field))).
+		Filter(flux.Eq("symbol", symbol)).
+		Group("symbol").
+		AggregateWindow(every, aggregation, true))
 }
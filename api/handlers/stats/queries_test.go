@@ -1,168 +1,59 @@
 package stats
 
 import (
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux/fluxtest"
 )
 
-func TestQueries_buildNTTTransferChainActivityWithSymbol(t *testing.T) {
-
-	expected := `
-import "influxdata/influxdb/schema"
-import "strings"
-
-bucket = "wormscan"
-today = 2024-08-23T00:00:00Z
-field = "total_transferred"
-
-last = from(bucket: bucket)
-    |> range(start: 1970-01-01T00:00:00Z, stop: today)
-    |> filter(fn: (r) => r._measurement == "ntt_symbol_chain_1d" and r._field == field)
-    |> filter(fn: (r) => r.symbol == "W")
-	|> group(columns:["symbol","emitter_chain","destination_chain"])
-	|> sum()
-
-current = from(bucket: bucket)
-    |> range(start: today)
-    |> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-    |> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-    |> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-    |> schema.fieldsAsCols()
-    |> filter(fn: (r) => r.symbol != "")
-    |> map(fn: (r) => ({r with symbol: strings.toUpper(v: r.symbol)}))
-    |> filter(fn: (r) => r.symbol == "W")
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> map(fn: (r) => ({r with _value: r.volume}))
-    |> count()
-	
-union(tables: [current, last])
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> sum()
-`
+func TestQueries_buildNTTChainActivity(t *testing.T) {
 	//2023-08-23T18:39:10.985Z
 	tm := time.Date(2024, 8, 23, 18, 39, 10, 985, time.UTC)
-	actual := buildNTTChainActivity("wormscan", tm, "W", false)
-	assert.Equal(t, expected, actual)
-}
-
-func TestQueries_buildNTTTransferChainActivityWithoutSymbol(t *testing.T) {
 
-	expected := `
-import "influxdata/influxdb/schema"
-import "strings"
-
-bucket = "wormscan"
-today = 2024-08-23T00:00:00Z
-field = "total_transferred"
-
-last = from(bucket: bucket)
-    |> range(start: 1970-01-01T00:00:00Z, stop: today)
-    |> filter(fn: (r) => r._measurement == "ntt_symbol_chain_1d" and r._field == field)
-    |> filter(fn: (r) => true)
-	|> group(columns:["symbol","emitter_chain","destination_chain"])
-	|> sum()
-
-current = from(bucket: bucket)
-    |> range(start: today)
-    |> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-    |> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-    |> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-    |> schema.fieldsAsCols()
-    |> filter(fn: (r) => r.symbol != "")
-    |> map(fn: (r) => ({r with symbol: strings.toUpper(v: r.symbol)}))
-    |> filter(fn: (r) => true)
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> map(fn: (r) => ({r with _value: r.volume}))
-    |> count()
-	
-union(tables: [current, last])
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> sum()
-`
-	//2023-08-23T18:39:10.985Z
-	tm := time.Date(2024, 8, 23, 18, 39, 10, 985, time.UTC)
-	actual := buildNTTChainActivity("wormscan", tm, "", false)
-	assert.Equal(t, expected, actual)
+	fluxtest.AssertGolden(t, "ntt_transfer_chain_activity_with_symbol", buildNTTChainActivity("wormscan", tm, "W", false))
+	fluxtest.AssertGolden(t, "ntt_transfer_chain_activity_without_symbol", buildNTTChainActivity("wormscan", tm, "", false))
+	fluxtest.AssertGolden(t, "ntt_volume_chain_activity_with_symbol", buildNTTChainActivity("wormscan", tm, "W", true))
+	fluxtest.AssertGolden(t, "ntt_volume_chain_activity_without_symbol", buildNTTChainActivity("wormscan", tm, "", true))
 }
 
-func TestQueries_buildNTTVolumeChainActivityWithSymbol(t *testing.T) {
+func TestQueries_buildNTTChainActivityByTime(t *testing.T) {
+	start := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	stop := time.Date(2024, 8, 23, 0, 0, 0, 0, time.UTC)
 
-	expected := `
-import "influxdata/influxdb/schema"
-import "strings"
-
-bucket = "wormscan"
-today = 2024-08-23T00:00:00Z
-field = "total_volume_transferred"
-
-last = from(bucket: bucket)
-    |> range(start: 1970-01-01T00:00:00Z, stop: today)
-    |> filter(fn: (r) => r._measurement == "ntt_symbol_chain_1d" and r._field == field)
-    |> filter(fn: (r) => r.symbol == "W")
-	|> group(columns:["symbol","emitter_chain","destination_chain"])
-	|> sum()
-
-current = from(bucket: bucket)
-    |> range(start: today)
-    |> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-    |> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-    |> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-    |> schema.fieldsAsCols()
-    |> filter(fn: (r) => r.symbol != "")
-    |> map(fn: (r) => ({r with symbol: strings.toUpper(v: r.symbol)}))
-    |> filter(fn: (r) => r.symbol == "W")
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> map(fn: (r) => ({r with _value: r.volume}))
-    |> sum()
-	
-union(tables: [current, last])
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> sum()
-`
-	//2023-08-23T18:39:10.985Z
-	tm := time.Date(2024, 8, 23, 18, 39, 10, 985, time.UTC)
-	actual := buildNTTChainActivity("wormscan", tm, "W", true)
-	assert.Equal(t, expected, actual)
+	fluxtest.AssertGolden(t, "ntt_chain_activity_by_hour", buildNTTChainActivityByTime("wormscan", start, stop, "W", true, "1h"))
+	fluxtest.AssertGolden(t, "ntt_chain_activity_by_month", buildNTTChainActivityByTime("wormscan", start, stop, "W", false, "1mo"))
 }
 
-func TestQueries_buildNTTVolumeChainActivityWithoutSymbol(t *testing.T) {
+func TestQueries_buildNTTSummary(t *testing.T) {
+	tm := time.Date(2024, 8, 23, 18, 39, 10, 985, time.UTC)
 
-	expected := `
-import "influxdata/influxdb/schema"
-import "strings"
+	fluxtest.AssertGolden(t, "ntt_total_value_token_transferred", buildNTTTotalValueTokenTransferred("wormscan", tm, "W"))
+	fluxtest.AssertGolden(t, "ntt_total_token_transferred", buildNTTTotalTokenTransferred("wormscan", tm, "W"))
+	fluxtest.AssertGolden(t, "ntt_average_transfer_size", buildNTTAverageTransferSize("wormscan", "W"))
+}
 
-bucket = "wormscan"
-today = 2024-08-23T00:00:00Z
-field = "total_volume_transferred"
+func TestQueries_buildNTTTotalFiltersBySymbol(t *testing.T) {
+	tm := time.Date(2024, 8, 23, 18, 39, 10, 985, time.UTC)
 
-last = from(bucket: bucket)
-    |> range(start: 1970-01-01T00:00:00Z, stop: today)
-    |> filter(fn: (r) => r._measurement == "ntt_symbol_chain_1d" and r._field == field)
-    |> filter(fn: (r) => true)
-	|> group(columns:["symbol","emitter_chain","destination_chain"])
-	|> sum()
+	for _, q := range []flux.Query{
+		buildNTTTotalValueTokenTransferred("wormscan", tm, "ONDO"),
+		buildNTTTotalTokenTransferred("wormscan", tm, "ONDO"),
+	} {
+		if strings.Contains(q.Text, `"W"`) {
+			t.Errorf("query filters by a hardcoded symbol:\n%s", q.Text)
+		}
+		if q.Params["symbol"] != "ONDO" {
+			t.Errorf("expected symbol param ONDO, got %v", q.Params["symbol"])
+		}
+	}
+}
 
-current = from(bucket: bucket)
-    |> range(start: today)
-    |> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-    |> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-    |> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-    |> schema.fieldsAsCols()
-    |> filter(fn: (r) => r.symbol != "")
-    |> map(fn: (r) => ({r with symbol: strings.toUpper(v: r.symbol)}))
-    |> filter(fn: (r) => true)
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> map(fn: (r) => ({r with _value: r.volume}))
-    |> sum()
-	
-union(tables: [current, last])
-    |> group(columns:["symbol","emitter_chain","destination_chain"])
-    |> sum()
-`
-	//2023-08-23T18:39:10.985Z
+func TestQueries_buildTopCorridors(t *testing.T) {
 	tm := time.Date(2024, 8, 23, 18, 39, 10, 985, time.UTC)
-	actual := buildNTTChainActivity("wormscan", tm, "", true)
-	assert.Equal(t, expected, actual)
+
+	fluxtest.AssertGolden(t, "top_corridors", buildTopCorridors("wormscan-24hours", tm, "top_100_corridors_7_days_3h_v2"))
+	fluxtest.AssertGolden(t, "symbol_with_assets", buildSymbolWithAssets("wormscan-24hours", tm, "assets_by_symbol_7_days_3h_v2"))
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/wormhole-foundation/wormhole-explorer/common/coingecko"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
 	"github.com/wormhole-foundation/wormhole-explorer/common/stats"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
@@ -70,7 +71,7 @@ func (r *Repository) GetSymbolWithAssets(ctx context.Context, timeSpan SymbolWit
 	}
 
 	query := buildSymbolWithAssets(r.bucket24HoursRetention, time.Now(), measurement)
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +159,7 @@ func (r *Repository) GetTopCorridores(ctx context.Context, timeSpan TopCorridors
 	}
 
 	query := buildTopCorridors(r.bucket24HoursRetention, time.Now(), measurement)
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		return nil, err
 	}
@@ -318,7 +319,7 @@ func (r *Repository) GetNativeTokenTransferSummary(ctx context.Context, symbol s
 
 func (r *Repository) getNTTTotalValueTokenTransferred(ctx context.Context, symbol string) (*decimal.Decimal, error) {
 	query := buildNTTTotalValueTokenTransferred(r.bucketInfiniteRetention, time.Now(), symbol)
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		r.logger.Error("failed to query ntt total value tokend transferred",
 			zap.String("symbol", symbol), zap.Error(err))
@@ -348,7 +349,7 @@ func (r *Repository) getNTTTotalValueTokenTransferred(ctx context.Context, symbo
 
 func (r *Repository) getNTTTotalTokenTransferred(ctx context.Context, symbol string) (*decimal.Decimal, error) {
 	query := buildNTTTotalTokenTransferred(r.bucketInfiniteRetention, time.Now(), symbol)
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		r.logger.Error("failed to query ntt total token transferred",
 			zap.String("symbol", symbol), zap.Error(err))
@@ -379,7 +380,7 @@ func (r *Repository) getNTTTotalTokenTransferred(ctx context.Context, symbol str
 
 func (r *Repository) getNTTAverageTransferSize(ctx context.Context, symbol string) (*decimal.Decimal, error) {
 	query := buildNTTAverageTransferSize(r.bucketInfiniteRetention, symbol)
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		r.logger.Error("failed to query ntt average transfer size",
 			zap.String("symbol", symbol), zap.Error(err))
@@ -409,7 +410,7 @@ func (r *Repository) getNTTAverageTransferSize(ctx context.Context, symbol strin
 
 func (r *Repository) GetNativeTokenTransferActivity(ctx context.Context, isNotional bool, symbol string) ([]NativeTokenTransferActivity, error) {
 	query := buildNTTChainActivity(r.bucketInfiniteRetention, time.Now(), symbol, isNotional)
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		r.logger.Error("failed to query native token transfer activity", zap.Error(err))
 		return nil, err
@@ -482,27 +483,26 @@ func (r *Repository) GetNativeTokenTransferActivity(ctx context.Context, isNotio
 }
 
 func (r *Repository) GetNativeTokenTransferByTime(ctx context.Context, timespan NttTimespan, symbol string, isNotional bool, from, to time.Time) ([]NativeTokenTransferByTime, error) {
-	var query string
+	var start, stop time.Time
+	every := flux.Duration(timespan)
 	switch timespan {
 	case HourNttTimespan:
-		start := from.Truncate(1 * time.Hour).UTC().Format(time.RFC3339)
-		stop := to.Truncate(1 * time.Hour).UTC().Format(time.RFC3339)
-		query = r.buildQueryGetNativeTokenTransferByTimeHourly(start, stop, symbol, isNotional)
+		start = from.Truncate(1 * time.Hour)
+		stop = to.Truncate(1 * time.Hour)
 	case DayNttTimespan:
-		start := from.Truncate(24 * time.Hour).UTC().Format(time.RFC3339)
-		stop := to.Truncate(24 * time.Hour).UTC().Format(time.RFC3339)
-		query = r.buildQueryGetNativeTokenTransferByTimeDaily(start, stop, symbol, isNotional)
+		start = from.Truncate(24 * time.Hour)
+		stop = to.Truncate(24 * time.Hour)
 	case MonthNttTimespan:
-		start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location()).UTC().Format(time.RFC3339)
-		stop := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location()).UTC().Format(time.RFC3339)
-		query = r.buildQueryGetNativeTokenTransferByTimeMonthly(start, stop, symbol, isNotional)
+		start = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+		stop = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location())
 	default:
-		start := time.Date(from.Year(), 1, 1, 0, 0, 0, 0, from.Location()).UTC().Format(time.RFC3339)
-		stop := time.Date(to.Year(), 1, 1, 0, 0, 0, 0, to.Location()).UTC().Format(time.RFC3339)
-		query = r.buildQueryGetNativeTokenTransferByTimeYearly(start, stop, symbol, isNotional)
+		every = flux.Duration(YearNttTimespan)
+		start = time.Date(from.Year(), 1, 1, 0, 0, 0, 0, from.Location())
+		stop = time.Date(to.Year(), 1, 1, 0, 0, 0, 0, to.Location())
 	}
+	query := buildNTTChainActivityByTime(r.bucketInfiniteRetention, start, stop, strings.ToUpper(symbol), isNotional, every)
 
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		r.logger.Error("failed to query native token transfer activity", zap.Error(err))
 		return nil, err
@@ -552,45 +552,6 @@ func (r *Repository) GetNativeTokenTransferByTime(ctx context.Context, timespan
 	return values, nil
 }
 
-func (r *Repository) buildQueryGetNativeTokenTransferByTimeHourly(start, stop, symbol string, isNotional bool) string {
-	function := "count"
-	if isNotional {
-		function = "sum"
-	}
-	query := `
-	import "influxdata/influxdb/schema"
-	import "strings"
-
-	start = %s
-	stop =  %s
-	bucket = "%s"
-	symbol = "%s"
-
-	from(bucket: bucket)
-	|> range(start: start, stop: stop)
-	|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-	|> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-	|> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-	|> schema.fieldsAsCols()
-	|> filter(fn: (r) => r.symbol == symbol)
-	|> group()
-	|> map(fn: (r) => ({r with _value: r.volume}))
-	|> aggregateWindow(every: 1h, fn: %s, createEmpty: true)`
-	return fmt.Sprintf(query, start, stop, r.bucketInfiniteRetention, strings.ToUpper(symbol), function)
-}
-
-func (r *Repository) buildQueryGetNativeTokenTransferByTimeDaily(start, stop, symbol string, isNotional bool) string {
-	return buildNTTChainActivityByTime(r.bucketInfiniteRetention, start, stop, strings.ToUpper(symbol), isNotional, "1d")
-}
-
-func (r *Repository) buildQueryGetNativeTokenTransferByTimeMonthly(start, stop, symbol string, isNotional bool) string {
-	return buildNTTChainActivityByTime(r.bucketInfiniteRetention, start, stop, strings.ToUpper(symbol), isNotional, "1mo")
-}
-
-func (r *Repository) buildQueryGetNativeTokenTransferByTimeYearly(start, stop, symbol string, isNotional bool) string {
-	return buildNTTChainActivityByTime(r.bucketInfiniteRetention, start, stop, strings.ToUpper(symbol), isNotional, "1y")
-}
-
 func buildTimeForNativeTokenTransferByTime(timestamp time.Time, timespan NttTimespan) time.Time {
 	switch timespan {
 	case HourNttTimespan:
@@ -15,7 +15,7 @@ import (
 )
 
 type Service struct {
-	repo               *Repository
+	repo               repository
 	addressRepositorty *stats.AddressRepository
 	holderRepository   *stats.HolderRepositoryReadable
 	cache              cache.Cache
@@ -24,6 +24,15 @@ type Service struct {
 	logger             *zap.Logger
 }
 
+// decouple service from repository
+type repository interface {
+	GetSymbolWithAssets(ctx context.Context, timeSpan SymbolWithAssetsTimeSpan) ([]SymbolWithAssetDTO, error)
+	GetTopCorridores(ctx context.Context, timeSpan TopCorridorsTimeSpan) ([]TopCorridorsDTO, error)
+	GetNativeTokenTransferSummary(ctx context.Context, symbol string) (*NativeTokenTransferSummary, error)
+	GetNativeTokenTransferActivity(ctx context.Context, isNotional bool, symbol string) ([]NativeTokenTransferActivity, error)
+	GetNativeTokenTransferByTime(ctx context.Context, timespan NttTimespan, symbol string, isNotional bool, from, to time.Time) ([]NativeTokenTransferByTime, error)
+}
+
 const (
 	topSymbolsByVolumeKey  = "wormscan:top-assets-symbol-by-volume"
 	topCorridorsByCountKey = "wormscan:top-corridors-by-count"
@@ -33,7 +42,7 @@ const (
 )
 
 // NewService create a new Service.
-func NewService(repo *Repository, statsRepository *stats.AddressRepository,
+func NewService(repo repository, statsRepository *stats.AddressRepository,
 	holderRepository *stats.HolderRepositoryReadable, cache cache.Cache,
 	expiration time.Duration, metrics metrics.Metrics, logger *zap.Logger) *Service {
 	return &Service{
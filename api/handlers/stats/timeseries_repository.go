@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// TimeSeriesRepository serves the top corridors from a pluggable time-series backend,
+// reading the `top_100_corridors_*` measurements summarized by the analytics service.
+// Every other query is delegated to the embedded Repository.
+type TimeSeriesRepository struct {
+	*Repository
+	reader timeseries.Reader
+	logger *zap.Logger
+}
+
+// NewTimeSeriesRepository creates a new *TimeSeriesRepository.
+func NewTimeSeriesRepository(repo *Repository, reader timeseries.Reader, logger *zap.Logger) *TimeSeriesRepository {
+	return &TimeSeriesRepository{Repository: repo, reader: reader, logger: logger}
+}
+
+// GetTopCorridores returns the 100 corridors with the highest number of transfers in the time span.
+func (r *TimeSeriesRepository) GetTopCorridores(ctx context.Context, timeSpan TopCorridorsTimeSpan) ([]TopCorridorsDTO, error) {
+
+	measurement := "top_100_corridors_2_days_3h_v2"
+	if timeSpan == TimeSpan7DaysTopCorridors {
+		measurement = "top_100_corridors_7_days_3h_v2"
+	}
+
+	// the corridors of every summarizer run of the last day, grouped by the hour of the run. Only
+	// the corridors of the latest run are returned, so the corridors that left the top 100 since an
+	// older run are not.
+	rows, err := r.reader.Query(ctx, &timeseries.Query{
+		Bucket:      timeseries.Bucket24Hours,
+		Measurement: measurement,
+		Field:       "count",
+		Start:       time.Now().Add(-24 * time.Hour).Truncate(time.Hour),
+		GroupBy:     []string{"emitter_chain", "destination_chain", "token_chain", "token_address"},
+		Aggregate:   timeseries.AggregateLast,
+		Window:      time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows = latestRun(rows)
+
+	var values []TopCorridorsDTO
+	for _, row := range rows {
+		emitterChain, err := strconv.ParseUint(row.Tags["emitter_chain"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert emitter chain field to uint16. %v", err)
+		}
+		destinationChain, err := strconv.ParseUint(row.Tags["destination_chain"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert destination chain field to uint16. %v", err)
+		}
+		tokenChain, err := strconv.ParseUint(row.Tags["token_chain"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert token chain field to uint16. %v", err)
+		}
+
+		value := TopCorridorsDTO{
+			EmitterChainID:     sdk.ChainID(emitterChain),
+			DestinationChainID: sdk.ChainID(destinationChain),
+			TokenChainID:       sdk.ChainID(tokenChain),
+			TokenAddress:       row.Tags["token_address"],
+			Txs:                uint64(row.Value),
+		}
+
+		// do not include invalid chain IDs in the response
+		if !domain.ChainIdIsValid(value.EmitterChainID) || !domain.ChainIdIsValid(value.DestinationChainID) {
+			r.logger.Warn("Invalid chain ID in top corridors",
+				zap.Uint16("emitter_chain", uint16(value.EmitterChainID)),
+				zap.Uint16("destination_chain", uint16(value.DestinationChainID)))
+			continue
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// latestRun returns the rows of the latest window, at most 100. The rows are sorted by time, and
+// the rows of a window by value in descending order.
+func latestRun(rows []timeseries.Row) []timeseries.Row {
+	if len(rows) == 0 {
+		return nil
+	}
+	last := rows[len(rows)-1].Time
+	i := len(rows)
+	for i > 0 && rows[i-1].Time.Equal(last) {
+		i--
+	}
+	rows = rows[i:]
+	if len(rows) > 100 {
+		rows = rows[:100]
+	}
+	return rows
+}
@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+func newCorridorPoint(measurement, emitterChain string, count uint64, execution time.Time) *timeseries.Point {
+	return timeseries.NewPoint(measurement).
+		AddTag("emitter_chain", emitterChain).
+		AddTag("destination_chain", "2").
+		AddTag("token_chain", "2").
+		AddTag("token_address", "0x1").
+		AddField("count", count).
+		SetTime(execution)
+}
+
+func TestTimeSeriesRepository_GetTopCorridores(t *testing.T) {
+	ctx := context.Background()
+	measurement := "top_100_corridors_2_days_3h_v2"
+	// the latest run may be before the start of the day.
+	latest := time.Now().Truncate(time.Hour).Add(-3 * time.Hour)
+	store := timeseries.NewMemoryStore()
+	require.NoError(t, store.WritePoints(ctx, timeseries.Bucket24Hours,
+		newCorridorPoint(measurement, "1", 10, latest),
+		newCorridorPoint(measurement, "5", 20, latest),
+		// a corridor that left the top corridors since an older run.
+		newCorridorPoint(measurement, "1", 8, latest.Add(-3*time.Hour)),
+		newCorridorPoint(measurement, "4", 50, latest.Add(-3*time.Hour)),
+		newCorridorPoint("top_100_corridors_7_days_3h_v2", "6", 70, latest),
+	))
+	repo := NewTimeSeriesRepository(&Repository{}, store, zap.NewNop())
+
+	corridors, err := repo.GetTopCorridores(ctx, TimeSpan2DaysTopCorridors)
+	require.NoError(t, err)
+	assert.Equal(t, []TopCorridorsDTO{
+		{EmitterChainID: sdk.ChainIDPolygon, DestinationChainID: sdk.ChainIDEthereum, TokenChainID: sdk.ChainIDEthereum, TokenAddress: "0x1", Txs: 20},
+		{EmitterChainID: sdk.ChainIDSolana, DestinationChainID: sdk.ChainIDEthereum, TokenChainID: sdk.ChainIDEthereum, TokenAddress: "0x1", Txs: 10},
+	}, corridors)
+}
+
+func TestTimeSeriesRepository_GetTopCorridores_NoRuns(t *testing.T) {
+	ctx := context.Background()
+	store := timeseries.NewMemoryStore()
+	require.NoError(t, store.WritePoints(ctx, timeseries.Bucket24Hours,
+		newCorridorPoint("top_100_corridors_7_days_3h_v2", "1", 10, time.Now().Add(-48*time.Hour)),
+	))
+	repo := NewTimeSeriesRepository(&Repository{}, store, zap.NewNop())
+
+	corridors, err := repo.GetTopCorridores(ctx, TimeSpan7DaysTopCorridors)
+	require.NoError(t, err)
+	assert.Empty(t, corridors)
+}
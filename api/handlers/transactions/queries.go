@@ -2,51 +2,43 @@ package transactions
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
-// queryTemplateVaaCount1d1h is the query used to get the last VAA count and the aggregated VAA count for the last 24 hours by hour.
-const queryTemplateVaaCount1d1h = `
-lastVaaCount = from(bucket: "%s")
-  |> range(start: %s)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count")
-  |> group()
-  |> aggregateWindow(every: %s, fn: count, createEmpty: true)
-aggregatesVaaCount = from(bucket: "%s")
-  |> range(start: %s)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count_1h")
-union(tables: [aggregatesVaaCount, lastVaaCount])
-  |> group()
-  |> sort(columns: ["_time"], desc: true)
-`
-
-// queryTemplateVaaCount1d1h is the query used to get the last VAA count and the aggregated VAA count for 1 week or month by day.
-const queryTemplateVaaCount = `
-lastVaaCount = from(bucket: "%s")
-  |> range(start: %s)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count")
-  |> group()
-aggregatesVaaCount = from(bucket: "%s")
-  |> range(start: %s)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count_1h")
-  |> aggregateWindow(every: 1h, fn: sum, createEmpty: true)
-union(tables: [aggregatesVaaCount, lastVaaCount])
-  |> group()
-  |> aggregateWindow(every: 1d, fn: sum, createEmpty: true)
-  |> sort(columns: ["_time"], desc: true)
-`
-
-func buildLastTrxQuery(bucket string, tm time.Time, q *TransactionCountQuery) string {
+// buildLastTrxQuery returns the last VAA count and the aggregated VAA count, for the last
+// 24 hours by hour or for 1 week or month by day.
+func buildLastTrxQuery(bucket string, tm time.Time, q *TransactionCountQuery) flux.Query {
 	startLastVaa, startAggregatesVaa := createRangeQuery(tm, q.TimeSpan)
-	if q.TimeSpan == "1d" && q.SampleRate == "1h" {
-		return fmt.Sprintf(queryTemplateVaaCount1d1h, bucket, startLastVaa, q.SampleRate, bucket, startAggregatesVaa)
+	byHour := q.TimeSpan == "1d" && q.SampleRate == "1h"
+
+	lastVaaCount := flux.From(bucket).
+		Range(flux.Param("startLastVaa", startLastVaa), nil).
+		Filter(flux.Eq("_measurement", flux.String("vaa_count"))).
+		Group()
+	aggregatesVaaCount := flux.From(bucket).
+		Range(flux.Param("startAggregatesVaa", startAggregatesVaa), nil).
+		Filter(flux.Eq("_measurement", flux.String("vaa_count_1h")))
+	if byHour {
+		lastVaaCount.AggregateWindow(flux.Duration(q.SampleRate), "count", true)
+	} else {
+		aggregatesVaaCount.AggregateWindow(flux.Raw("1h"), "sum", true)
 	}
-	return fmt.Sprintf(queryTemplateVaaCount, bucket, startLastVaa, bucket, startAggregatesVaa)
-}
 
-func createRangeQuery(t time.Time, timeSpan string) (string, string) {
+	s := flux.NewScript()
+	result := flux.Pipe(flux.Union(s.Let("aggregatesVaaCount", aggregatesVaaCount), s.Let("lastVaaCount", lastVaaCount))).
+		Group()
+	if !byHour {
+		result.AggregateWindow(flux.Raw("1d"), "sum", true)
+	}
+	return s.Build(result.Sort(true, "_time"))
+}
 
-	const format = time.RFC3339Nano
+func createRangeQuery(t time.Time, timeSpan string) (time.Time, time.Time) {
 
 	var startLastVaa, startAggregatesVaa time.Time
 
@@ -62,47 +54,496 @@ func createRangeQuery(t time.Time, timeSpan string) (string, string) {
 		startAggregatesVaa = startLastVaa.Add(-time.Hour * 24)
 	}
 
-	return startLastVaa.Format(format), startAggregatesVaa.Format(format)
-}
-
-const queryTemplateTotalTrxCount = `
-current = from(bucket: "%s")
-  |> range(start: %s)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_volume_v2")
-  |> filter(fn: (r) => r["_field"] == "volume")
-  |> group()
-  |> count()
-last = from(bucket: "%s")
-  |> range(start: -1mo)
-  |> filter(fn: (r) => r["_measurement"] == "total_tx_count_v2")
-  |> last()
-union(tables: [current, last])
-  |> group()
-  |> sum()
-`
-
-func buildTotalTrxCountQuery(bucketForever, bucket30Days string, t time.Time) string {
-	start := t.Truncate(time.Hour * 24).Format(time.RFC3339Nano)
-	return fmt.Sprintf(queryTemplateTotalTrxCount, bucketForever, start, bucket30Days)
-}
-
-const queryTemplateTotalTrxVolume = `
-current = from(bucket: "%s")
-  |> range(start: %s)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_volume_v2")
-  |> filter(fn: (r) => r["_field"] == "volume")
-  |> group()
-  |> sum()
-last = from(bucket: "%s")
-  |> range(start: -1mo)
-  |> filter(fn: (r) => r["_measurement"] == "total_tx_volume_v2")
-  |> last()
-union(tables: [current, last])
-  |> group()
-  |> sum()
-`
-
-func buildTotalTrxVolumeQuery(bucketForever, bucket30Days string, t time.Time) string {
-	start := t.Truncate(time.Hour * 24).Format(time.RFC3339Nano)
-	return fmt.Sprintf(queryTemplateTotalTrxVolume, bucketForever, start, bucket30Days)
+	return startLastVaa, startAggregatesVaa
+}
+
+// buildTotalTrxQuery returns the aggregation of the transactions of the current day
+// plus the last total stored in the given measurement.
+func buildTotalTrxQuery(bucketForever, bucket30Days string, t time.Time, aggregation, totalMeasurement string) flux.Query {
+	s := flux.NewScript()
+	current := s.Let("current", flux.From(bucketForever).
+		Range(t.Truncate(time.Hour*24), nil).
+		Filter(flux.Eq("_measurement", flux.String("vaa_volume_v2"))).
+		Filter(flux.Eq("_field", flux.String("volume"))).
+		Group().
+		Then(aggregation))
+	last := s.Let("last", flux.From(bucket30Days).
+		Range(flux.Ago(flux.Raw("1mo")), nil).
+		Filter(flux.Eq("_measurement", flux.String(totalMeasurement))).
+		Last())
+	return s.Build(flux.Pipe(flux.Union(current, last)).
+		Group().
+		Sum())
+}
+
+func buildTotalTrxCountQuery(bucketForever, bucket30Days string, t time.Time) flux.Query {
+	return buildTotalTrxQuery(bucketForever, bucket30Days, t, "count", "total_tx_count_v2")
+}
+
+func buildTotalTrxVolumeQuery(bucketForever, bucket30Days string, t time.Time) flux.Query {
+	return buildTotalTrxQuery(bucketForever, bucket30Days, t, "sum", "total_tx_volume_v2")
+}
+
+// buildMessages24HrQuery returns the count of messages of the last 24 hours: the historic count
+// from the summarized metric plus the current count from the unsummarized metric.
+func buildMessages24HrQuery(bucket24Hr string) flux.Query {
+	s := flux.NewScript("date")
+	summarized := s.Let("summarized", flux.From(bucket24Hr).
+		Range(flux.Ago(flux.Raw("24h")), nil).
+		Filter(flux.Eq("_measurement", flux.String("vaa_count_all_messages_5m"))).
+		Group().
+		Sum())
+	// This assumes that the summarization task runs exactly every 5 minutes
+	startOfInterval := s.Let("startOfInterval", flux.Call("date.truncate", flux.Arg("t", flux.Raw("now()")), flux.Arg("unit", flux.Raw("5m"))))
+	raw := s.Let("raw", flux.From(bucket24Hr).
+		Range(startOfInterval, nil).
+		Filter(flux.Eq("_measurement", flux.String("vaa_count_all_messages"))).
+		Filter(flux.Eq("_field", flux.String("count"))).
+		Group().
+		Count())
+	return s.Build(flux.Pipe(flux.Union(summarized, raw)).
+		Group().
+		Sum())
+}
+
+// buildTopAssetsQuery returns the 7 assets with the highest volume: the historic volumes
+// from the summarized metric plus the current day's volume from the unsummarized metric.
+func buildTopAssetsQuery(bucket30Days, bucketInfinite string, timeSpan TopStatisticsTimeSpan) flux.Query {
+	groupBy := []string{"emitter_chain", "token_address", "token_chain"}
+
+	s := flux.NewScript("date")
+	summarized := s.Let("summarized", flux.From(bucket30Days).
+		Range(flux.Ago(flux.Duration(timeSpan)), nil).
+		Filter(flux.Eq("_measurement", flux.String("asset_volumes_24h_v2"))).
+		Group(groupBy...))
+	// This assumes that the summarization task runs exactly once per day at 00:00hs
+	startOfDay := s.Let("startOfDay", flux.Call("date.truncate", flux.Arg("t", flux.Raw("now()")), flux.Arg("unit", flux.Raw("1d"))))
+	raw := s.Let("raw", flux.From(bucketInfinite).
+		Range(startOfDay, nil).
+		Filter(flux.Eq("_measurement", flux.String("vaa_volume_v2"))).
+		Filter(flux.Eq("_field", flux.String("volume"))).
+		Group(groupBy...))
+	return s.Build(flux.Pipe(flux.Union(summarized, raw)).
+		Group(groupBy...).
+		Sum().
+		Group().
+		Top(7, "_value"))
+}
+
+// buildTopChainPairsQuery returns the 100 pairs of chains with the most transfers.
+func buildTopChainPairsQuery(bucket24Hr string, timeSpan TopStatisticsTimeSpan, measurement string) flux.Query {
+	return flux.NewScript().Build(flux.From(bucket24Hr).
+		Range(flux.Ago(flux.Duration(timeSpan)), nil).
+		Filter(flux.Eq("_measurement", flux.String(measurement)), flux.Eq("_field", flux.String("count"))).
+		Last().
+		Group("emitter_chain", "destination_chain").
+		Sum().
+		Group().
+		Top(100, "_value"))
+}
+
+func (r *Repository) buildChainActivityQuery(q *ChainActivityQuery) flux.Query {
+
+	var field string
+	if q.IsNotional {
+		field = "notional"
+	} else {
+		field = "count"
+	}
+
+	if q.TimeSpan == ChainActivityTs1Year || q.TimeSpan == ChainActivityTsAllTime {
+
+		if field == "notional" {
+			field = "volume"
+		}
+
+		var start any = flux.Epoch
+		if q.TimeSpan == ChainActivityTs1Year {
+			start = time.Now().AddDate(-1, 0, 0).Truncate(time.Second)
+		}
+
+		return flux.NewScript().Build(flux.From(r.bucketInfiniteRetention).
+			Range(start, nil).
+			Filter(flux.Eq("_measurement", flux.String("chain_activity_1d"))).
+			Filter(flux.Eq("_field", flux.String(field))).
+			Drop("_time", "to", "_measurement", "app_id").
+			Group("emitter_chain", "destination_chain").
+			Sum())
+	}
+
+	var measurement string
+	switch q.TimeSpan {
+	case ChainActivityTs7Days:
+		measurement = "chain_activity_7_days_3h_v2"
+	case ChainActivityTs30Days:
+		measurement = "chain_activity_30_days_3h_v2"
+	case ChainActivityTs90Days:
+		measurement = "chain_activity_90_days_3h_v2"
+	default:
+		measurement = "chain_activity_7_days_3h_v2"
+	}
+	//today without hours
+	start := time.Now().Truncate(24 * time.Hour)
+	return flux.NewScript().Build(flux.From(r.bucket24HoursRetention).
+		Range(start, nil).
+		Filter(flux.Eq("_measurement", flux.String(measurement)), flux.Eq("_field", flux.String(field))).
+		Filter(flux.In("app_id", q.GetAppIDs()...)).
+		Last().
+		Group("emitter_chain", "destination_chain").
+		Sum())
+}
+
+// buildMayanQuery returns the volume of mayan since from, as the difference between the
+// first and the last volume of the protocol stats.
+func buildMayanQuery(bucket string, from offset) flux.Query {
+	s := flux.NewScript()
+	mayanData := s.Let("mayanData", flux.From(bucket).
+		Range(flux.Ago(flux.Duration(from)), nil).
+		Filter(flux.Eq("_measurement", flux.String("protocols_stats_1d"))).
+		Filter(flux.Exists("protocol"), flux.Eq("protocol", flux.String("mayan"))).
+		Filter(flux.Eq("_field", flux.String("volume"))))
+	volumeStart := s.Let("volumeStart", flux.Pipe(mayanData).
+		First().
+		Map(`(r) => ({r with _field: "volume_start"})`).
+		Keep("protocol", "_field", "_value"))
+	volumeNow := s.Let("volumeNow", flux.Pipe(mayanData).
+		Last().
+		Map(`(r) => ({r with _field: "volume_now"})`).
+		Keep("protocol", "_field", "_value"))
+	return s.Build(flux.Pipe(flux.Union(volumeNow, volumeStart)).
+		Pivot([]string{"protocol"}, []string{"_field"}, "_value").
+		Map(`(r) => ({r with _value: r.volume_now - r.volume_start})`))
+}
+
+// buildVolumeQuery returns the volume since from, excluding the volume of the given apps.
+func buildVolumeQuery(bucketInfinite string, from offset, excludeAppIDs []string) flux.Query {
+	var excludeApps []flux.Expr
+	for _, appID := range excludeAppIDs {
+		excludeApps = append(excludeApps, flux.Ne("app_id", appID))
+	}
+
+	return flux.NewScript().Build(flux.From(bucketInfinite).
+		Range(flux.Ago(flux.Duration(from)), nil).
+		Filter(flux.Eq("_measurement", flux.String("vaa_volume_v2"))).
+		Filter(excludeApps...).
+		Filter(flux.Eq("_field", flux.String("volume"))).
+		Group().
+		Then("sum", flux.Arg("column", flux.String("_value"))))
+}
+
+// buildTokensVolumeQuery returns the 100 token symbols with the highest volume of all time.
+func buildTokensVolumeQuery(bucket24Hr string) flux.Query {
+	return flux.NewScript().Build(flux.From(bucket24Hr).
+		Range(flux.Ago(flux.Raw("1d")), nil).
+		Filter(flux.Eq("_measurement", flux.String("tokens_symbol_volume_all_time"))).
+		Group("symbol").
+		Last().
+		Pivot([]string{"_time"}, []string{"_field"}, "_value").
+		Group().
+		Sort(true, "volume").
+		Limit(100).
+		Map(`(r) => ({r with volume: float(v: r.volume) / 100000000.0})`))
+}
+
+// timespanRange returns the start and the stop of a range truncated to the timespan.
+func timespanRange(from, to time.Time, timespan Timespan) (time.Time, time.Time) {
+	switch timespan {
+	case Hour:
+		return from.Truncate(1 * time.Hour), to.Truncate(1 * time.Hour)
+	case Day:
+		return from.Truncate(24 * time.Hour), to.Truncate(24 * time.Hour)
+	case Month:
+		return time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location()),
+			time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location())
+	default:
+		return time.Date(from.Year(), 1, 1, 0, 0, 0, 0, from.Location()),
+			time.Date(to.Year(), 1, 1, 0, 0, 0, 0, to.Location())
+	}
+}
+
+// chainIDs returns the chain ids as they are stored in the tags.
+func chainIDs(ids []sdk.ChainID) []string {
+	tags := make([]string, 0, len(ids))
+	for _, id := range ids {
+		tags = append(tags, strconv.Itoa(int(id)))
+	}
+	return tags
+}
+
+// sumAndCount aggregates the volume and the number of transactions of a window.
+const sumAndCount = `sumAndCount = (tables=<-, column) => {
+  return tables
+    |> reduce(
+      identity: {_value: uint(v: 0), txs: uint(v: 0)},
+      fn: (r, accumulator) => ({_value: accumulator._value + r._value, txs: accumulator.txs + uint(v: 1)}),
+    )
+}`
+
+func (r *Repository) buildTokenSymbolActivityQuery(q TokenSymbolActivityQuery) flux.Query {
+	start, stop := timespanRange(q.From, q.To, q.Timespan)
+
+	s := flux.NewScript("date")
+	s.Define(sumAndCount)
+	every := s.Let("every", flux.Param("every", flux.Duration(q.Timespan)))
+	return s.Build(flux.From(r.bucketInfiniteRetention).
+		Range(start, stop).
+		Filter(flux.Eq("_measurement", flux.String("vaa_volume_v3")), flux.Eq("version", flux.String("v5"))).
+		Filter(flux.Or(flux.Eq("_field", flux.String("volume")), flux.Eq("_field", flux.String("symbol")))).
+		Pivot([]string{"_time"}, []string{"_field"}, "_value").
+		Keep("_start", "_stop", "_time", "emitter_chain", "destination_chain", "symbol", "volume").
+		Filter(flux.Gt("volume", 0)).
+		Filter(flux.In("symbol", q.TokenSymbols...)).
+		Filter(flux.In("emitter_chain", chainIDs(q.SourceChains)...)).
+		Filter(flux.In("destination_chain", chainIDs(q.TargetChains)...)).
+		Rename("volume", "_value").
+		Set("_field", flux.String("volume")).
+		Group("symbol", "emitter_chain", "destination_chain", "_field").
+		AggregateWindow(every, "sumAndCount", true).
+		Map(`(r) => ({r with
+    volume: if exists r._value then float(v: r._value) / 100000000.0 else float(v: 0),
+    to: r._time,
+    _time: date.sub(d: every, from: r._time),
+  })`).
+		Drop("_value", "_start", "_stop", "_field"))
+}
+
+func (r *Repository) buildChainActivityQueryTops(q ChainActivityTopsQuery) flux.Query {
+	start, stop := timespanRange(q.From, q.To, q.Timespan)
+	filterSourceChain := flux.In("emitter_chain", chainIDs(q.SourceChains)...)
+
+	if len(q.TargetChains) == 0 && q.AppId == "" {
+		return r.buildQueryChainActivityTopsByEmitter(q, start, stop, filterSourceChain)
+	}
+
+	var filterAppID flux.Expr
+	if q.AppId != "" {
+		filterAppID = flux.Eq("app_id", q.AppId)
+	}
+	measurement := "chain_activity_1d"
+	if q.Timespan == Hour {
+		measurement = "chain_activity_1h"
+	}
+
+	data := flux.From(r.bucketInfiniteRetention).
+		Range(start, stop).
+		Filter(flux.Eq("_measurement", flux.String(measurement))).
+		Filter(filterSourceChain).
+		Filter(flux.In("destination_chain", chainIDs(q.TargetChains)...)).
+		Filter(filterAppID)
+
+	s := flux.NewScript("join")
+	switch q.Timespan {
+	case Hour, Day:
+		data.Drop("destination_chain")
+		return buildChainActivityTotals(s, data, flux.And(flux.Eq("_field", flux.String("volume")), flux.Gt("_value", 0)),
+			`(l, r) => l._time == r._time and l.to == r.to and l.emitter_chain == r.emitter_chain`)
+	case Month:
+		windowByTimespan(data.Drop("destination_chain", "to", "app_id"), flux.Raw("1mo"))
+	default:
+		windowByTimespan(data.Drop("destination_chain", "to", "app_id"), flux.Raw("1y"))
+	}
+	return buildChainActivityTotals(s, data, flux.And(flux.Eq("_field", flux.String("volume")), flux.Gt("_value", 0)),
+		`(l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain`)
+}
+
+func (r *Repository) buildQueryChainActivityTopsByEmitter(q ChainActivityTopsQuery, start, stop time.Time, filterSourceChain flux.Expr) flux.Query {
+
+	measurement := ""
+	switch q.Timespan {
+	case Hour:
+		measurement = "emitter_chain_activity_1h"
+	default:
+		measurement = "emitter_chain_activity_1d"
+	}
+
+	data := flux.From(r.bucketInfiniteRetention).
+		Range(start, stop).
+		Filter(flux.Eq("_measurement", flux.String(measurement))).
+		Filter(filterSourceChain)
+
+	if q.Timespan == Hour || q.Timespan == Day {
+		return flux.NewScript().Build(data.
+			Pivot([]string{"_time", "emitter_chain"}, []string{"_field"}, "_value").
+			Sort(false, "emitter_chain", "_time"))
+	}
+
+	s := flux.NewScript("join")
+	on := `(l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain`
+	if q.Timespan == Month {
+		windowByTimespan(data.Drop("to"), flux.Raw("1mo"))
+		return buildChainActivityTotals(s, data, flux.And(flux.Eq("_field", flux.String("volume")), flux.Gt("_value", 0)), on)
+	}
+	windowByTimespan(data.Drop("to"), flux.Raw("1y"))
+	return buildChainActivityTotals(s, data, nil, on)
+}
+
+// windowByTimespan groups the records in windows of every, and sets the bounds of
+// each window as the _time and to columns of its records.
+func windowByTimespan(p *flux.Pipeline, every flux.Expr) *flux.Pipeline {
+	return p.Then("window", flux.Arg("every", every), flux.Arg("period", every)).
+		Drop("_time").
+		Rename("_start", "_time").
+		Map(`(r) => ({r with to: string(v: r._stop)})`)
+}
+
+// buildChainActivityTotals joins the volume and the count of the data by time and emitter chain.
+// The volume is the sum of the records that match filterVolume, or of all the records if it is nil.
+func buildChainActivityTotals(s *flux.Script, data *flux.Pipeline, filterVolume flux.Expr, on string) flux.Query {
+	groupBy := []string{"_time", "to", "emitter_chain"}
+	d := s.Let("data", data)
+	vols := s.Let("vols", flux.Pipe(d).
+		Filter(filterVolume).
+		Group(groupBy...).
+		ToUInt().
+		Sum().
+		Rename("_value", "volume"))
+	counts := s.Let("counts", flux.Pipe(d).
+		Filter(flux.Eq("_field", flux.String("count"))).
+		Group(groupBy...).
+		ToUInt().
+		Sum().
+		Rename("_value", "count"))
+	return s.Build(flux.Pipe(flux.Call("join.inner",
+		flux.Arg("left", vols),
+		flux.Arg("right", counts),
+		flux.Arg("on", flux.Raw(on)),
+		flux.Arg("as", flux.Raw(`(l, r) => ({l with count: r.count})`)))).
+		Group().
+		Sort(false, "emitter_chain", "_time"))
+}
+
+// appActivityRange returns the bucket, the measurement and the range of the stats of the applications by timespan.
+func (r *Repository) appActivityRange(q ApplicationActivityQuery, measurementPrefix string) (string, string, time.Time, time.Time) {
+	switch q.Timespan {
+	case "1h":
+		return r.bucket30DaysRetention, measurementPrefix + "_1h", q.From.Truncate(1 * time.Hour), q.To.Truncate(1 * time.Hour)
+	case Month:
+		from, to := timespanRange(q.From, q.To, Month)
+		return r.bucketInfiniteRetention, measurementPrefix + "_1d", from, to
+	default: // default is 1d
+		return r.bucketInfiniteRetention, measurementPrefix + "_1d", q.From.Truncate(24 * time.Hour), q.To.Truncate(24 * time.Hour)
+	}
+}
+
+func (r *Repository) buildTotalsAppActivityQuery(q ApplicationActivityQuery) flux.Query {
+	bucket, measurement, from, to := r.appActivityRange(q, "protocols_stats_totals")
+
+	filterMeasurement := flux.Eq("_measurement", flux.String(measurement))
+	if q.Timespan != "1h" {
+		filterMeasurement = flux.And(filterMeasurement, flux.Eq("version", flux.String("v1")))
+	}
+	var filterByAppID flux.Expr
+	if q.AppId != "" && !q.ExclusiveAppID {
+		filterByAppID = flux.Eq("app_id", "TOTAL_"+strings.ToUpper(q.AppId))
+	}
+
+	allData := flux.From(bucket).
+		Range(from, to).
+		Filter(filterMeasurement).
+		Filter(filterByAppID)
+
+	if q.Timespan == Month {
+		s := flux.NewScript("date", "join")
+		data := s.Let("allData", allData.Drop("emitter_chain", "destination_chain", "version", "_measurement"))
+		return s.Build(flux.Call("join.inner",
+			flux.Arg("left", s.Let("totalMsgs", monthlyTotal(data, "total_messages").Group())),
+			flux.Arg("right", s.Let("tvt", monthlyTotal(data, "total_value_transferred").Group())),
+			flux.Arg("on", flux.Raw(`(l, r) => l.app_id == r.app_id and l._time == r._time`)),
+			flux.Arg("as", flux.Raw(`(l, r) => ({
+    to: l._time,
+    _time: date.sub(d: 1mo, from: l._time),
+    app_id: l.app_id,
+    total_messages: l.total_messages,
+    total_value_transferred: float(v: r.total_value_transferred) / 100000000.0,
+  })`))))
+	}
+
+	s := flux.NewScript("date")
+	data := s.Let("allData", allData.Drop("emitter_chain", "destination_chain"))
+	return buildActivityByTimespan(s, data, q.Timespan, "uint(v: r._value)", "app_id")
+}
+
+func (r *Repository) buildAppActivityQuery(q ApplicationActivityQuery) flux.Query {
+	bucket, measurement, from, to := r.appActivityRange(q, "protocols_stats")
+
+	var filterByAppID flux.Expr
+	if q.AppId != "" {
+		appID := flux.Param("app_id", q.AppId)
+		if !q.ExclusiveAppID {
+			filterByAppID = flux.Or(flux.Eq("app_id_1", appID), flux.Eq("app_id_2", appID), flux.Eq("app_id_3", appID))
+		} else {
+			filterByAppID = flux.And(flux.Eq("app_id_1", appID), flux.Eq("app_id_2", flux.String("none")), flux.Eq("app_id_3", flux.String("none")))
+		}
+	}
+
+	allData := flux.From(bucket).
+		Range(from, to).
+		Filter(flux.Eq("_measurement", flux.String(measurement))).
+		Filter(flux.Not(flux.Exists("protocol"))).
+		Filter(filterByAppID).
+		Drop("emitter_chain", "destination_chain", "_measurement")
+
+	if q.Timespan == Month {
+		s := flux.NewScript("date", "join")
+		data := s.Let("allData", allData)
+		total := func(field string) *flux.Pipeline {
+			return monthlyTotal(data, field).
+				Map(fmt.Sprintf(`(r) => ({r with
+    _time: date.sub(d: 1mo, from: r._time),
+    %[1]s: if not exists r.%[1]s then uint(v: 0) else r.%[1]s,
+  })`, field)).
+				Drop("_start", "_stop").
+				Group()
+		}
+		return s.Build(flux.Call("join.inner",
+			flux.Arg("left", s.Let("totalMsgs", total("total_messages"))),
+			flux.Arg("right", s.Let("tvt", total("total_value_transferred"))),
+			flux.Arg("on", flux.Raw(`(l, r) => l.app_id_1 == r.app_id_1 and l.app_id_2 == r.app_id_2 and l.app_id_3 == r.app_id_3 and l._time == r._time`)),
+			flux.Arg("as", flux.Raw(`(l, r) => ({
+    _time: l._time,
+    to: date.add(d: 1mo, to: l._time),
+    app_id_1: l.app_id_1,
+    app_id_2: l.app_id_2,
+    app_id_3: l.app_id_3,
+    total_messages: l.total_messages,
+    total_value_transferred: float(v: r.total_value_transferred) / 100000000.0,
+  })`))))
+	}
+
+	s := flux.NewScript("date")
+	data := s.Let("allData", allData)
+	return buildActivityByTimespan(s, data, q.Timespan, "r._value", "app_id_1", "app_id_2", "app_id_3")
+}
+
+// monthlyTotal returns the monthly sum of a field of the data, in a column named after the field.
+func monthlyTotal(data flux.Expr, field string) *flux.Pipeline {
+	return flux.Pipe(data).
+		Filter(flux.Eq("_field", flux.String(field))).
+		AggregateWindow(flux.Raw("1mo"), "sum", true).
+		Rename("_value", field)
+}
+
+// buildActivityByTimespan returns the messages and the volume of the data by the given columns,
+// in windows of the timespan. Each record spans from _time to the to column.
+// The messages of each window are the result of msgsValue.
+func buildActivityByTimespan(s *flux.Script, data flux.Expr, timespan Timespan, msgsValue string, cols ...string) flux.Query {
+	every := s.Let("every", flux.Param("every", flux.Duration(timespan)))
+	groupBy := append([]string{"_time", "_field"}, cols...)
+	total := func(field, value string) *flux.Pipeline {
+		return flux.Pipe(data).
+			Filter(flux.Eq("_field", flux.String(field))).
+			AggregateWindow(every, "sum", true).
+			Map(`(r) => ({r with _value: if not exists r._value then uint(v: 0) else ` + value + `})`).
+			Group(groupBy...).
+			Sum()
+	}
+	totalMsgs := s.Let("totalMsgs", total("total_messages", msgsValue))
+	tvt := s.Let("tvt", total("total_value_transferred", "r._value"))
+	return s.Build(flux.Pipe(flux.Union(totalMsgs, tvt)).
+		Pivot(append([]string{"_time"}, cols...), []string{"_field"}, "_value").
+		Map(`(r) => ({r with
+    total_value_transferred: float(v: r.total_value_transferred) / 100000000.0,
+    to: r._time,
+    _time: date.sub(d: every, from: r._time),
+  })`))
 }
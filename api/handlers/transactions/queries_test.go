@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux/fluxtest"
 )
 
 func TestQueries_createRangeQuery(t *testing.T) {
@@ -38,96 +39,39 @@ func TestQueries_createRangeQuery(t *testing.T) {
 
 	for _, tt := range tests {
 		startLastVaa, startAggregatesVaa := createRangeQuery(tt.tm, tt.ts)
-		assert.Equal(t, tt.wantStartLastVaa, startLastVaa)
-		assert.Equal(t, tt.wantStartAggregatesVaa, startAggregatesVaa)
+		assert.Equal(t, tt.wantStartLastVaa, startLastVaa.Format(time.RFC3339Nano))
+		assert.Equal(t, tt.wantStartAggregatesVaa, startAggregatesVaa.Format(time.RFC3339Nano))
 	}
 }
 
 func TestQueries_buildLastTrxQuery1d1h(t *testing.T) {
-
-	expected := `
-lastVaaCount = from(bucket: "wormscan-1month")
-  |> range(start: 2023-05-04T18:00:00Z)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count")
-  |> group()
-  |> aggregateWindow(every: 1h, fn: count, createEmpty: true)
-aggregatesVaaCount = from(bucket: "wormscan-1month")
-  |> range(start: 2023-05-03T18:00:00Z)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count_1h")
-union(tables: [aggregatesVaaCount, lastVaaCount])
-  |> group()
-  |> sort(columns: ["_time"], desc: true)
-`
 	//2023-05-04T18:39:10.985Z
 	tm := time.Date(2023, 5, 4, 18, 39, 10, 985, time.UTC)
 	actual := buildLastTrxQuery("wormscan-1month", tm, &TransactionCountQuery{TimeSpan: "1d", SampleRate: "1h"})
-	assert.Equal(t, expected, actual)
+	fluxtest.AssertGolden(t, "last_trx_1d_1h", actual)
 }
 
 func TestQueries_buildLastTrxQuery1w1d(t *testing.T) {
-
-	expected := `
-lastVaaCount = from(bucket: "wormscan-1month")
-  |> range(start: 2023-05-04T00:00:00Z)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count")
-  |> group()
-aggregatesVaaCount = from(bucket: "wormscan-1month")
-  |> range(start: 2023-04-27T00:00:00Z)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count_1h")
-  |> aggregateWindow(every: 1h, fn: sum, createEmpty: true)
-union(tables: [aggregatesVaaCount, lastVaaCount])
-  |> group()
-  |> aggregateWindow(every: 1d, fn: sum, createEmpty: true)
-  |> sort(columns: ["_time"], desc: true)
-`
 	//2023-05-04T18:39:10.985Z
 	tm := time.Date(2023, 5, 4, 18, 39, 10, 985, time.UTC)
 	actual := buildLastTrxQuery("wormscan-1month", tm, &TransactionCountQuery{TimeSpan: "1w", SampleRate: "1d"})
-	assert.Equal(t, expected, actual)
+	fluxtest.AssertGolden(t, "last_trx_1w_1d", actual)
 }
 
 func TestQueries_buildTotalTrxCountQuery(t *testing.T) {
-
-	expected := `
-current = from(bucket: "bucket-forever")
-  |> range(start: 2023-05-12T00:00:00Z)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_volume_v2")
-  |> filter(fn: (r) => r["_field"] == "volume")
-  |> group()
-  |> count()
-last = from(bucket: "bucket-30days")
-  |> range(start: -1mo)
-  |> filter(fn: (r) => r["_measurement"] == "total_tx_count_v2")
-  |> last()
-union(tables: [current, last])
-  |> group()
-  |> sum()
-`
-	//2023-05-04T18:39:10.985Z
 	tm := time.Date(2023, 5, 12, 16, 53, 10, 985, time.UTC)
 	actual := buildTotalTrxCountQuery("bucket-forever", "bucket-30days", tm)
-	assert.Equal(t, expected, actual)
+	fluxtest.AssertGolden(t, "total_trx_count", actual)
 }
 
 func TestQueries_buildTotalTrxVolumeQuery(t *testing.T) {
-
-	expected := `
-current = from(bucket: "bucket-forever")
-  |> range(start: 2023-05-10T00:00:00Z)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_volume_v2")
-  |> filter(fn: (r) => r["_field"] == "volume")
-  |> group()
-  |> sum()
-last = from(bucket: "bucket-30days")
-  |> range(start: -1mo)
-  |> filter(fn: (r) => r["_measurement"] == "total_tx_volume_v2")
-  |> last()
-union(tables: [current, last])
-  |> group()
-  |> sum()
-`
-	//2023-05-04T18:39:10.985Z
 	tm := time.Date(2023, 5, 10, 16, 53, 10, 985, time.UTC)
 	actual := buildTotalTrxVolumeQuery("bucket-forever", "bucket-30days", tm)
-	assert.Equal(t, expected, actual)
+	fluxtest.AssertGolden(t, "total_trx_volume", actual)
+}
+
+func TestQueries_buildVolumeQuery(t *testing.T) {
+	fluxtest.AssertGolden(t, "volume_24h", buildVolumeQuery("wormscan", _24h, nil))
+	fluxtest.AssertGolden(t, "volume_7d_without_mayan", buildVolumeQuery("wormscan", _7d, []string{"MAYAN"}))
+	fluxtest.AssertGolden(t, "mayan_7d", buildMayanQuery("wormscan", _7d))
 }
@@ -12,7 +12,6 @@ import (
 	"io"
 	"net/http"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +23,7 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/tvl"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -31,100 +31,6 @@ import (
 	"go.uber.org/zap"
 )
 
-const queryTemplateChainActivity = `
-from(bucket: "%s")
-  |> range(start: %s)
-  |> filter(fn: (r) => r._measurement == "%s" and r._field == "%s")
-  |> last()
-  |> group(columns: ["emitter_chain", "destination_chain"])
-  |> sum()
-`
-
-const queryTemplateChainActivityWithApps = `
-from(bucket: "%s")
-  |> range(start: %s)
-  |> filter(fn: (r) => r._measurement == "%s" and r._field == "%s")
-  |> filter(fn: (r) => contains(value: r.app_id, set: %s))
-  |> last()
-  |> group(columns: ["emitter_chain", "destination_chain"])
-  |> sum()
-`
-
-const queryTemplateVolume = `
-from(bucket: "%s")
-  |> range(start: -%s)
-  |> filter(fn: (r) => r._measurement == "vaa_volume_v2")
-  %s
-  |> filter(fn:(r) => r._field == "volume")
-  |> group()
-  |> sum(column: "_value")
-`
-
-const queryTemplateMessages24h = `
-import "date"
-
-// Get historic count from the summarized metric.
-summarized = from(bucket: "%s")
-  |> range(start: -24h)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count_all_messages_5m")
-  |> group()
-  |> sum()
-
-// Get the current count from the unsummarized metric.
-// This assumes that the summarization task runs exactly every 5 minutes
-startOfInterval = date.truncate(t: now(), unit: 5m)
-raw = from(bucket: "%s")
-  |> range(start: startOfInterval)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_count_all_messages")
-  |> filter(fn: (r) => r["_field"] == "count")
-  |> group()
-  |> count()
-
-// Merge all results, compute the sum, return the top 7 volumes.
-union(tables: [summarized, raw])
-  |> group()
-  |> sum()
-`
-
-const queryTemplateTopAssets = `
-import "date"
-
-// Get historic volumes from the summarized metric.
-summarized = from(bucket: "%s")
-  |> range(start: -%s)
-  |> filter(fn: (r) => r["_measurement"] == "asset_volumes_24h_v2")
-  |> group(columns: ["emitter_chain", "token_address", "token_chain"])
-
-// Get the current day's volume from the unsummarized metric.
-// This assumes that the summarization task runs exactly once per day at 00:00hs
-startOfDay = date.truncate(t: now(), unit: 1d)
-raw = from(bucket: "%s")
-  |> range(start: startOfDay)
-  |> filter(fn: (r) => r["_measurement"] == "vaa_volume_v2")
-  |> filter(fn: (r) => r["_field"] == "volume")
-  |> group(columns: ["emitter_chain", "token_address", "token_chain"])
-
-// Merge all results, compute the sum, return the top 7 volumes.
-union(tables: [summarized, raw])
-  |> group(columns: ["emitter_chain", "token_address", "token_chain"])
-  |> sum()
-  |> group()
-  |> top(columns: ["_value"], n: 7)
-`
-
-const queryTemplateTopChainPairs = `
-import "date"
-
-from(bucket: "%s")
-  |> range(start: -%s)
-  |> filter(fn: (r) => r._measurement == "%s" and r._field == "count")
-  |> last()
-  |> group(columns: ["emitter_chain", "destination_chain"])
-  |> sum()
-  |> group()
-  |> top(columns: ["_value"], n: 100)
-`
-
 type repositoryCollections struct {
 	vaas               *mongo.Collection
 	vaasPythnet        *mongo.Collection
@@ -151,7 +57,7 @@ type Repository struct {
 type httpDoEr func(req *http.Request) (*http.Response, error)
 
 type influxQueryAPI interface {
-	Query(ctx context.Context, query string) (influxQueryResult, error)
+	Query(ctx context.Context, query flux.Query) (influxQueryResult, error)
 }
 
 type influxQueryResult interface {
@@ -164,8 +70,8 @@ type influxAdapter struct {
 	influxAPI api.QueryAPI
 }
 
-func (i *influxAdapter) Query(ctx context.Context, query string) (influxQueryResult, error) {
-	result, err := i.influxAPI.Query(ctx, query)
+func (i *influxAdapter) Query(ctx context.Context, query flux.Query) (influxQueryResult, error) {
+	result, err := i.influxAPI.QueryWithParams(ctx, query.Text, query.Params)
 	return &influxResult{result}, err
 }
 
@@ -233,7 +139,7 @@ func NewRepository(
 func (r *Repository) GetTopAssets(ctx context.Context, timeSpan *TopStatisticsTimeSpan) ([]AssetDTO, error) {
 
 	// Submit the query to InfluxDB
-	query := fmt.Sprintf(queryTemplateTopAssets, r.bucket30DaysRetention, *timeSpan, r.bucketInfiniteRetention)
+	query := buildTopAssetsQuery(r.bucket30DaysRetention, r.bucketInfiniteRetention, *timeSpan)
 	result, err := r.queryAPI.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -304,7 +210,7 @@ func (r *Repository) GetTopChainPairs(ctx context.Context, timeSpan *TopStatisti
 	}
 
 	// Submit the query to InfluxDB
-	query := fmt.Sprintf(queryTemplateTopChainPairs, r.bucket24HoursRetention, *timeSpan, measurement)
+	query := buildTopChainPairsQuery(r.bucket24HoursRetention, *timeSpan, measurement)
 	result, err := r.queryAPI.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -425,68 +331,6 @@ func (r *Repository) FindChainActivity(ctx context.Context, q *ChainActivityQuer
 	return responseWithoutWrongChainId, nil
 }
 
-func (r *Repository) buildChainActivityQuery(q *ChainActivityQuery) string {
-
-	var field string
-	if q.IsNotional {
-		field = "notional"
-	} else {
-		field = "count"
-	}
-
-	if q.TimeSpan == ChainActivityTs1Year || q.TimeSpan == ChainActivityTsAllTime {
-
-		if field == "notional" {
-			field = "volume"
-		}
-
-		var start string
-		measurement := "chain_activity_1d"
-		switch q.TimeSpan {
-		case ChainActivityTs1Year:
-			start = time.Now().AddDate(-1, 0, 0).Format(time.RFC3339)
-		case ChainActivityTsAllTime:
-			start = "1970-01-01T00:00:00Z"
-		default:
-			start = "1970-01-01T00:00:00Z"
-		}
-
-		hotfixQuery := `
-			import "date"
-
-			from(bucket: "%s")
-				|> range(start: %s)
-				|> filter(fn: (r) => r._measurement == "%s")
-				|> filter(fn: (r) => r._field == "%s")
-				|> drop(columns:["_time","to","_measurement","app_id"])
-				|> group(columns:["emitter_chain","destination_chain"])
-				|> sum()
-`
-		return fmt.Sprintf(hotfixQuery, r.bucketInfiniteRetention, start, measurement, field)
-
-	}
-
-	var measurement string
-	switch q.TimeSpan {
-	case ChainActivityTs7Days:
-		measurement = "chain_activity_7_days_3h_v2"
-	case ChainActivityTs30Days:
-		measurement = "chain_activity_30_days_3h_v2"
-	case ChainActivityTs90Days:
-		measurement = "chain_activity_90_days_3h_v2"
-	default:
-		measurement = "chain_activity_7_days_3h_v2"
-	}
-	//today without hours
-	start := time.Now().Truncate(24 * time.Hour).UTC().Format(time.RFC3339)
-	if q.HasAppIDS() {
-		apps := `["` + strings.Join(q.GetAppIDs(), `","`) + `"]`
-		return fmt.Sprintf(queryTemplateChainActivityWithApps, r.bucket24HoursRetention, start, measurement, field, apps)
-	} else {
-		return fmt.Sprintf(queryTemplateChainActivity, r.bucket24HoursRetention, start, measurement, field)
-	}
-}
-
 func (r *Repository) GetScorecards(ctx context.Context) (*Scorecards, error) {
 
 	// This function launches one goroutine for each scorecard.
@@ -700,10 +544,6 @@ func (r *Repository) getMessages24h(ctx context.Context) (string, error) {
 	return fmt.Sprint(row.Value), nil
 }
 
-func buildMessages24HrQuery(bucket24Hr string) string {
-	return fmt.Sprintf(queryTemplateMessages24h, bucket24Hr, bucket24Hr)
-}
-
 func (r *Repository) getVolume(ctx context.Context, from offset, excludeAppIDs []string) (uint64, error) {
 
 	// query volume
@@ -824,51 +664,6 @@ func (r *Repository) getMayanVolume(ctx context.Context, from offset) (uint64, e
 	return row.Volume, nil
 }
 
-func buildMayanQuery(bucket string, from offset) string {
-	queryTemplate := `
-		mayanData = from(bucket: "%s")
-						|> range(start: -%s)
-						|> filter(fn: (r) => r._measurement == "protocols_stats_1d")
-						|> filter(fn: (r) => exists r.protocol  and r.protocol == "mayan")
-						|> filter(fn: (r) => r._field == "volume")
-	
-	
-		volume30daysAgo = mayanData
-							|> first()
-							|> map(fn: (r) => ({r with _field : "volume_start"}))
-							|> keep(columns:["protocol","_field","_value"])
-		
-		volumeNow = mayanData
-						|> last()
-						|> map(fn: (r) => ({r with _field : "volume_now"}))
-						|> keep(columns:["protocol","_field","_value"])
-		
-		union(tables:[volumeNow,volume30daysAgo])
-			|> pivot(rowKey:["protocol"], columnKey: ["_field"], valueColumn: "_value")
-			|> map(fn: (r) => ({
-					r with 
-						_value : r.volume_now - r.volume_start
-			}))
-	`
-	queryMayan := fmt.Sprintf(queryTemplate, bucket, from)
-	return queryMayan
-}
-
-func buildVolumeQuery(bucketInfinite string, from offset, excludeAppIDs []string) string {
-
-	var filterAppIDs string
-	if len(excludeAppIDs) > 0 {
-		val := fmt.Sprintf("r.app_id != \"%s\"", excludeAppIDs[0])
-		buff := ""
-		for _, tc := range excludeAppIDs[1:] {
-			buff += fmt.Sprintf(" and r.app_id != \"%s\"", tc)
-		}
-		filterAppIDs = fmt.Sprintf("|> filter(fn: (r) => %s%s)", val, buff)
-	}
-
-	return fmt.Sprintf(queryTemplateVolume, bucketInfinite, from, filterAppIDs)
-}
-
 // GetTransactionCount get the last transactions.
 func (r *Repository) GetTransactionCount(ctx context.Context, q *TransactionCountQuery) ([]TransactionCountResult, error) {
 	query := buildLastTrxQuery(r.bucket30DaysRetention, time.Now(), q)
@@ -1350,21 +1145,7 @@ func (r *Repository) FindChainActivityTops(ctx *fasthttp.RequestCtx, q ChainActi
 }
 
 func (r *Repository) FindTokensVolume(ctx context.Context) ([]TokenVolume, error) {
-	query := `
-		import "date"
-
-		from(bucket: "%s")
-			|> range(start: -1d)
-			|> filter(fn: (r) => r._measurement == "tokens_symbol_volume_all_time")
-			|> group(columns:["symbol"])
-			|> last()
-			|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
-			|> group()
-			|> sort(columns:["volume"],desc:true)
-			|> limit(n:100)
-			|> map(fn: (r) => ({r with volume: float(v:r.volume) / 100000000.0 }))
-	`
-	query = fmt.Sprintf(query, r.bucket24HoursRetention)
+	query := buildTokensVolumeQuery(r.bucket24HoursRetention)
 	result, err := r.queryAPI.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -1416,666 +1197,6 @@ func (r *Repository) FindTokenSymbolActivity(ctx context.Context, payload TokenS
 	return response, nil
 }
 
-func (r *Repository) buildTokenSymbolActivityQuery(q TokenSymbolActivityQuery) string {
-
-	var start, stop string
-
-	switch q.Timespan {
-	case Hour:
-		stop = q.To.Truncate(1 * time.Hour).UTC().Format(time.RFC3339)
-		start = q.From.Truncate(1 * time.Hour).UTC().Format(time.RFC3339)
-	case Day:
-		start = q.From.Truncate(24 * time.Hour).UTC().Format(time.RFC3339)
-		stop = q.To.Truncate(24 * time.Hour).UTC().Format(time.RFC3339)
-	case Month:
-		start = time.Date(q.From.Year(), q.From.Month(), 1, 0, 0, 0, 0, q.From.Location()).UTC().Format(time.RFC3339)
-		stop = time.Date(q.To.Year(), q.To.Month(), 1, 0, 0, 0, 0, q.To.Location()).UTC().Format(time.RFC3339)
-	default:
-		start = time.Date(q.From.Year(), 1, 1, 0, 0, 0, 0, q.From.Location()).UTC().Format(time.RFC3339)
-		stop = time.Date(q.To.Year(), 1, 1, 0, 0, 0, 0, q.To.Location()).UTC().Format(time.RFC3339)
-	}
-
-	filterTargetChain := ""
-	if len(q.TargetChains) > 0 {
-		val := fmt.Sprintf("r.destination_chain == \"%d\"", q.TargetChains[0])
-		buff := ""
-		for _, tc := range q.TargetChains[1:] {
-			buff += fmt.Sprintf(" or r.destination_chain == \"%d\"", tc)
-		}
-		filterTargetChain = fmt.Sprintf("|> filter(fn: (r) => %s%s)", val, buff)
-	}
-
-	filterSourceChain := ""
-	if len(q.SourceChains) > 0 {
-		val := fmt.Sprintf("r.emitter_chain == \"%d\"", q.SourceChains[0])
-		buff := ""
-		for _, tc := range q.SourceChains[1:] {
-			buff += fmt.Sprintf(" or r.emitter_chain == \"%d\"", tc)
-		}
-		filterSourceChain = fmt.Sprintf("|> filter(fn: (r) => %s%s)", val, buff)
-	}
-
-	filterTokenSymbol := ""
-	if len(q.TokenSymbols) > 0 {
-		val := fmt.Sprintf("r.symbol == \"%s\"", q.TokenSymbols[0])
-		buff := ""
-		for _, tc := range q.TokenSymbols[1:] {
-			buff += fmt.Sprintf(" or r.symbol == \"%s\"", tc)
-		}
-		filterTokenSymbol = fmt.Sprintf("|> filter(fn: (r) => %s%s)", val, buff)
-	}
-
-	query := `
-	import "date"
-
-	sumAndCount = (tables=<-, column) => {
-		return tables
-				|> reduce(
-					identity: {
-						_value: uint(v:0),
-						txs: uint(v:0)
-					},
-					fn: (r, accumulator) => ({
-						_value: accumulator._value + r._value,
-						txs: accumulator.txs + uint(v:1)
-					})
-				)
-	}
-	
-	from(bucket: "%s")
-		|> range(start: %s, stop: %s)
-		|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-		|> filter(fn: (r) => r._field == "volume" or r._field == "symbol")
-		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
-		|> keep(columns:["_start","_stop","_time","emitter_chain","destination_chain","symbol","volume"])
-		|> filter(fn: (r) => r.volume > 0)
-		%s //filter by symbol
-		%s //filter by source_chain
-		%s //filter by target_chain
-		|> rename(columns: {volume: "_value"})
-		|> set(key: "_field", value: "volume")
-		|> group(columns:["symbol","emitter_chain","destination_chain","_field"])
-		|> aggregateWindow(every: %s, fn: sumAndCount, createEmpty: true)
-		|> map(fn: (r) => ({
-				r with 
-				volume: if exists r._value then float(v:r._value) / 100000000.0 else float(v:0),
-				to: r._time,
-				_time: date.sub(d: %s, from: r._time),
-		}))
-		|> drop(columns:["_value","_start","_stop","_field"])	
-	`
-
-	return fmt.Sprintf(query, r.bucketInfiniteRetention, start, stop, filterTokenSymbol, filterSourceChain, filterTargetChain, q.Timespan, q.Timespan)
-}
-
-func (r *Repository) buildChainActivityQueryTops(q ChainActivityTopsQuery) string {
-
-	var start, stop string
-
-	switch q.Timespan {
-	case Hour:
-		start = q.From.Truncate(1 * time.Hour).UTC().Format(time.RFC3339)
-		stop = q.To.Truncate(1 * time.Hour).UTC().Format(time.RFC3339)
-	case Day:
-		start = q.From.Truncate(24 * time.Hour).UTC().Format(time.RFC3339)
-		stop = q.To.Truncate(24 * time.Hour).UTC().Format(time.RFC3339)
-	case Month:
-		start = time.Date(q.From.Year(), q.From.Month(), 1, 0, 0, 0, 0, q.From.Location()).UTC().Format(time.RFC3339)
-		stop = time.Date(q.To.Year(), q.To.Month(), 1, 0, 0, 0, 0, q.To.Location()).UTC().Format(time.RFC3339)
-	default:
-		start = time.Date(q.From.Year(), 1, 1, 0, 0, 0, 0, q.From.Location()).UTC().Format(time.RFC3339)
-		stop = time.Date(q.To.Year(), 1, 1, 0, 0, 0, 0, q.To.Location()).UTC().Format(time.RFC3339)
-	}
-
-	filterTargetChain := ""
-	if len(q.TargetChains) > 0 {
-		val := fmt.Sprintf("r.destination_chain == \"%d\"", q.TargetChains[0])
-		buff := ""
-		for _, tc := range q.TargetChains[1:] {
-			buff += fmt.Sprintf(" or r.destination_chain == \"%d\"", tc)
-		}
-		filterTargetChain = fmt.Sprintf("|> filter(fn: (r) => %s%s)", val, buff)
-	}
-
-	filterSourceChain := ""
-	if len(q.SourceChains) > 0 {
-		val := fmt.Sprintf("r.emitter_chain == \"%d\"", q.SourceChains[0])
-		buff := ""
-		for _, tc := range q.SourceChains[1:] {
-			buff += fmt.Sprintf(" or r.emitter_chain == \"%d\"", tc)
-		}
-		filterSourceChain = fmt.Sprintf("|> filter(fn: (r) => %s%s)", val, buff)
-	}
-
-	filterAppId := ""
-	if q.AppId != "" {
-		filterAppId = "|> filter(fn: (r) => r.app_id == \"" + q.AppId + "\")"
-	}
-
-	if len(q.TargetChains) == 0 && q.AppId == "" {
-		return r.buildQueryChainActivityTopsByEmitter(q, start, stop, filterSourceChain)
-	}
-
-	var query string
-	switch q.Timespan {
-	case Hour:
-		query = r.buildQueryChainActivityHourly(start, stop, filterSourceChain, filterTargetChain, filterAppId)
-	case Day:
-		query = r.buildQueryChainActivityDaily(start, stop, filterSourceChain, filterTargetChain, filterAppId)
-	case Month:
-		query = r.buildQueryChainActivityMonthly(start, stop, filterSourceChain, filterTargetChain, filterAppId)
-	default:
-		query = r.buildQueryChainActivityYearly(start, stop, filterSourceChain, filterTargetChain, filterAppId)
-	}
-	return query
-}
-
-func (r *Repository) buildQueryChainActivityTopsByEmitter(q ChainActivityTopsQuery, start, stop, filterSourceChain string) string {
-
-	measurement := ""
-	switch q.Timespan {
-	case Hour:
-		measurement = "emitter_chain_activity_1h"
-	default:
-		measurement = "emitter_chain_activity_1d"
-	}
-
-	if q.Timespan == Hour || q.Timespan == Day {
-		query := `
-					import "date"
-
-					from(bucket: "%s")
-					|> range(start: %s,stop: %s)
-					|> filter(fn: (r) => r._measurement == "%s")
-					%s
-					|> pivot(rowKey:["_time","emitter_chain"], columnKey: ["_field"], valueColumn: "_value")
-					|> sort(columns:["emitter_chain","_time"],desc:false)`
-		return fmt.Sprintf(query, r.bucketInfiniteRetention, start, stop, measurement, filterSourceChain)
-	}
-
-	if q.Timespan == Month {
-		query := `
-				import "date"
-				import "join"
-
-				data = from(bucket: "%s")
-						|> range(start: %s,stop: %s)
-						|> filter(fn: (r) => r._measurement == "%s")
-						%s
-						|> drop(columns:["to"])
-						|> window(every: 1mo, period:1mo)
-						|> drop(columns:["_time"])
-						|> rename(columns: {_start: "_time"})
-						|> map(fn: (r) => ({r with to: string(v: r._stop)}))
-
-				vols = data
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-				counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-				join.inner(
-						left: vols,
-						right: counts,
-						on: (l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain,
-						as: (l, r) => ({l with count: r.count}),
-				)
-				|> group()
-				|> sort(columns:["emitter_chain","_time"],desc:false)`
-		return fmt.Sprintf(query, r.bucketInfiniteRetention, start, stop, measurement, filterSourceChain)
-	}
-
-	query := `
-				import "date"
-				import "join"
-
-				data = from(bucket: "%s")
-						|> range(start: %s,stop: %s)
-						|> filter(fn: (r) => r._measurement == "%s")
-						%s
-						|> drop(columns:["to"])
-						|> window(every: 1y, period:1y)
-						|> drop(columns:["_time"])
-						|> rename(columns: {_start: "_time"})
-						|> map(fn: (r) => ({r with to: string(v: r._stop)}))
-
-				vols = data
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-				counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-				join.inner(
-						left: vols,
-						right: counts,
-						on: (l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain,
-						as: (l, r) => ({l with count: r.count}),
-				)
-				|> group()
-				|> sort(columns:["emitter_chain","_time"],desc:false)`
-	return fmt.Sprintf(query, r.bucketInfiniteRetention, start, stop, measurement, filterSourceChain)
-
-}
-
-func (r *Repository) buildQueryChainActivityHourly(start, stop, filterSourceChain, filterTargetChain, filterAppId string) string {
-	query := `
-					import "date"
-					import "join"
-
-					data = from(bucket: "%s")
-		  			|> range(start: %s,stop: %s)
-		  			|> filter(fn: (r) => r._measurement == "chain_activity_1h")
-					%s
-					%s
-					%s
-					|> drop(columns:["destination_chain"])
-
-					vols = data		
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-					counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-					join.inner(
-					    left: vols,
-					    right: counts,
-					    on: (l, r) => l._time == r._time and l.to == r.to and l.emitter_chain == r.emitter_chain,
-					    as: (l, r) => ({l with count: r.count}),
-					)
-					|> group()
-					|> sort(columns:["emitter_chain","_time"],desc:false)`
-	return fmt.Sprintf(query, r.bucketInfiniteRetention, start, stop, filterSourceChain, filterTargetChain, filterAppId)
-}
-
-func (r *Repository) buildQueryChainActivityDaily(start, stop, filterSourceChain, filterTargetChain, filterAppId string) string {
-
-	query := `
-					import "date"
-					import "join"
-
-					data = from(bucket: "%s")
-		  			|> range(start: %s,stop: %s)
-		  			|> filter(fn: (r) => r._measurement == "chain_activity_1d")
-					%s
-					%s
-					%s
-					|> drop(columns:["destination_chain"])
-
-					vols = data		
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-					counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-					join.inner(
-					    left: vols,
-					    right: counts,
-					    on: (l, r) => l._time == r._time and l.to == r.to and l.emitter_chain == r.emitter_chain,
-					    as: (l, r) => ({l with count: r.count}),
-					)
-					|> group()
-					|> sort(columns:["emitter_chain","_time"],desc:false)`
-	return fmt.Sprintf(query, r.bucketInfiniteRetention, start, stop, filterSourceChain, filterTargetChain, filterAppId)
-}
-
-func (r *Repository) buildQueryChainActivityMonthly(start, stop, filterSourceChain, filterTargetChain, filterAppId string) string {
-	query := `
-				import "date"
-				import "join"
-
-				data = from(bucket: "%s")
-						|> range(start: %s,stop: %s)
-						|> filter(fn: (r) => r._measurement == "chain_activity_1d")
-						%s
-						%s
-						%s
-						|> drop(columns:["destination_chain","to","app_id"])
-						|> window(every: 1mo, period:1mo)
-						|> drop(columns:["_time"])
-						|> rename(columns: {_start: "_time"})
-						|> map(fn: (r) => ({r with to: string(v: r._stop)}))
-
-				vols = data
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-				counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-				join.inner(
-						left: vols,
-						right: counts,
-						on: (l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain,
-						as: (l, r) => ({l with count: r.count}),
-				)
-				|> group()
-				|> sort(columns:["emitter_chain","_time"],desc:false)`
-	return fmt.Sprintf(query, r.bucketInfiniteRetention, start, stop, filterSourceChain, filterTargetChain, filterAppId)
-}
-
-func (r *Repository) buildQueryChainActivityYearly(start, stop, filterSourceChain, filterTargetChain, filterAppId string) string {
-	query := `
-				import "date"
-				import "join"
-
-				data = from(bucket: "%s")
-						|> range(start: %s,stop: %s)
-						|> filter(fn: (r) => r._measurement == "chain_activity_1d")
-						%s
-						%s
-						%s
-						|> drop(columns:["destination_chain","to","app_id"])
-						|> window(every: 1y, period:1y)
-						|> drop(columns:["_time"])
-						|> rename(columns: {_start: "_time"})
-						|> map(fn: (r) => ({r with to: string(v: r._stop)}))
-
-				vols = data
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-				counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-				join.inner(
-						left: vols,
-						right: counts,
-						on: (l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain,
-						as: (l, r) => ({l with count: r.count}),
-				)
-				|> group()
-				|> sort(columns:["emitter_chain","_time"],desc:false)`
-	return fmt.Sprintf(query, r.bucketInfiniteRetention, start, stop, filterSourceChain, filterTargetChain, filterAppId)
-}
-
-func (r *Repository) buildTotalsAppActivityQuery(q ApplicationActivityQuery) string {
-
-	var measurement string
-	var bucket string
-	var from, to time.Time
-
-	switch q.Timespan {
-	case "1h":
-		measurement = "|> filter(fn: (r) => r._measurement == \"protocols_stats_totals_1h\")"
-		bucket = r.bucket30DaysRetention
-		from = q.From.Truncate(1 * time.Hour)
-		to = q.To.Truncate(1 * time.Hour)
-	default: // default is 1d
-		measurement = "|> filter(fn: (r) => r._measurement == \"protocols_stats_totals_1d\" and r.version == \"v1\")"
-		bucket = r.bucketInfiniteRetention
-		from = q.From.Truncate(24 * time.Hour)
-		to = q.To.Truncate(24 * time.Hour)
-	}
-
-	filterByAppId := ""
-	if q.AppId != "" && !q.ExclusiveAppID {
-		filterByAppId = fmt.Sprintf("|> filter(fn: (r) => r.app_id == \"TOTAL_%s\")", strings.ToUpper(q.AppId))
-	}
-
-	if q.Timespan == Month {
-		return r.buildTotalsAppActivityQueryMonthly(q, measurement, bucket, filterByAppId)
-	}
-
-	query := `
-			import "date"
-
-			allData = from(bucket: "%s")
-						|> range(start: %s,stop: %s)
-						%s
-						%s
-						|> drop(columns:["emitter_chain","destination_chain"])
-			
-			totalMsgs = allData
-						|> filter(fn: (r) => r._field == "total_messages")
-						|> aggregateWindow(every: %s, fn: sum,createEmpty:true)
-						|> map(fn: (r) => ({
-								r with
-								_value: if not exists r._value then uint(v:0) else uint(v:r._value)
-     						}))
-						|> group(columns:["_time","app_id","_field"])
-						|> sum()
-						
-			tvt = allData
-						|> filter(fn: (r) => r._field == "total_value_transferred")
-						|> aggregateWindow(every: %s, fn: sum, createEmpty:true)
-						|> map(fn: (r) => ({
-								r with
-								_value: if not exists r._value then uint(v:0) else r._value
-     						}))
-						|> group(columns:["_time","app_id","_field"])
-						|> sum()
-
-			union(tables: [totalMsgs, tvt])
-				|> pivot(rowKey:["_time","app_id"], columnKey: ["_field"], valueColumn: "_value")
-				|> map(fn: (r) => ({
-						r with
-						"total_value_transferred": float(v:r.total_value_transferred) / 100000000.0,
-						"to": r._time,
-						"_time": date.sub(d: %s, from: r._time)
-     			}))
-			`
-
-	return fmt.Sprintf(query, bucket, from.Format(time.RFC3339), to.Format(time.RFC3339), measurement, filterByAppId, q.Timespan, q.Timespan, q.Timespan)
-}
-
-func (r *Repository) buildAppActivityQuery(q ApplicationActivityQuery) string {
-
-	var measurement string
-	var bucket string
-	var from, to time.Time
-
-	switch q.Timespan {
-	case "1h":
-		measurement = "protocols_stats_1h"
-		bucket = r.bucket30DaysRetention
-		from = q.From.Truncate(1 * time.Hour)
-		to = q.To.Truncate(1 * time.Hour)
-	default: // default is 1d
-		measurement = "protocols_stats_1d"
-		bucket = r.bucketInfiniteRetention
-		from = q.From.Truncate(24 * time.Hour)
-		to = q.To.Truncate(24 * time.Hour)
-	}
-
-	filterByAppId := ""
-	if q.AppId != "" {
-		if !q.ExclusiveAppID {
-			filterByAppId = fmt.Sprintf("|> filter(fn: (r) => r.app_id_1 == \"%s\" or r.app_id_2 == \"%s\" or r.app_id_3 == \"%s\")", q.AppId, q.AppId, q.AppId)
-		} else {
-			filterByAppId = fmt.Sprintf("|> filter(fn: (r) => r.app_id_1 == \"%s\" and r.app_id_2 == \"none\" and r.app_id_3 == \"none\")", q.AppId)
-		}
-	}
-
-	if q.Timespan == Month {
-		return r.buildAppActivityQueryMonthly(q, measurement, bucket, filterByAppId)
-	}
-
-	query := `
-			import "date"
-
-				allData = from(bucket: "%s")
-							|> range(start: %s,stop: %s)
-							|> filter(fn: (r) => r._measurement == "%s")
-							|> filter(fn: (r) => not exists r.protocol )
-							%s
-							|> drop(columns:["emitter_chain","destination_chain","_measurement"])
-
-				totalMsgs = allData
-							|> filter(fn: (r) => r._field == "total_messages")
-							|> aggregateWindow(every: %s, fn: sum, createEmpty:true)
-							|> map(fn: (r) => ({
-										r with
-										_value: if not exists r._value then uint(v:0) else r._value
-								}))
-							|> group(columns:["_time","_field","app_id_1","app_id_2","app_id_3"])
-							|> sum()
-						
-				tvt = allData
-						|> filter(fn: (r) => r._field == "total_value_transferred")
-						|> aggregateWindow(every: %s, fn: sum, createEmpty:true)
-						|> map(fn: (r) => ({
-								r with
-								_value: if not exists r._value then uint(v:0) else r._value
-     						}))
-						|> group(columns:["_time","_field","app_id_1","app_id_2","app_id_3"])
-						|> sum()
-						
-				union(tables: [totalMsgs, tvt])
-				|> pivot(rowKey:["_time","app_id_1","app_id_2","app_id_3"], columnKey: ["_field"], valueColumn: "_value")
-				|> map(fn: (r) => ({
-						r with
-						"total_value_transferred": float(v:r.total_value_transferred) / 100000000.0,
-						"to": r._time,
-						"_time": date.sub(d: %s, from: r._time)
-				}))`
-
-	return fmt.Sprintf(query, bucket, from.Format(time.RFC3339), to.Format(time.RFC3339), measurement, filterByAppId, q.Timespan, q.Timespan, q.Timespan)
-}
-
-func (r *Repository) buildAppActivityQueryMonthly(q ApplicationActivityQuery, measurement string, bucket string, filterByAppId string) string {
-	query := `
-			import "date"
-			import "join"
-
-			allData = from(bucket: "%s")
-						|> range(start: %s,stop: %s)
-						|> filter(fn: (r) => r._measurement == "%s")
-						|> filter(fn: (r) => not exists r.protocol )
-						%s
-						|> drop(columns:["emitter_chain","destination_chain","_measurement"])
-
-			totalMsgs = allData
-						|> filter(fn: (r) => r._field == "total_messages")
-						|> aggregateWindow(every: 1mo, fn: sum)
-						|> rename(columns: {_value: "total_messages"})
-						|> map(fn: (r) => ({
-								r with
-								_time: date.sub(d: 1mo, from: r._time),
-								total_messages: if not exists r.total_messages then uint(v:0) else r.total_messages
-     						}))
-						|> drop(columns:["_start","_stop"])
-						|> group()
-			
-			
-			tvt = allData
-					|> filter(fn: (r) => r._field == "total_value_transferred")
-					|> aggregateWindow(every: 1mo, fn: sum)
-					|> rename(columns: {_value: "total_value_transferred"})		
-					|> map(fn: (r) => ({
-						r with
-						_time: date.sub(d: 1mo, from: r._time),
-						total_value_transferred: if not exists r.total_value_transferred then uint(v:0) else r.total_value_transferred
-					}))
-					|> drop(columns:["_start","_stop"])
-					|> group()
-						
-			join.inner(
-			    left: totalMsgs,
-			    right: tvt,
-			    on: (l, r) => l.app_id_1 == r.app_id_1 and l.app_id_2 == r.app_id_2 and l.app_id_3 == r.app_id_3 and l._time == r._time,
-			    as: (l, r) => ({
-					"_time":l._time,
-					"to":date.add(d: 1mo, to: l._time),
-					"app_id_1": l.app_id_1,
-					"app_id_2": l.app_id_2,
-					"app_id_3": l.app_id_3,
-					"total_messages":l.total_messages,
-					"total_value_transferred": float(v:r.total_value_transferred) / 100000000.0
-					})
-			)
-		`
-
-	from := time.Date(q.From.Year(), q.From.Month(), 1, 0, 0, 0, 0, q.From.Location())
-	to := time.Date(q.To.Year(), q.To.Month(), 1, 0, 0, 0, 0, q.To.Location())
-	return fmt.Sprintf(query, bucket, from.Format(time.RFC3339), to.Format(time.RFC3339), measurement, filterByAppId)
-}
-
-func (r *Repository) buildTotalsAppActivityQueryMonthly(q ApplicationActivityQuery, filterMeasurement string, bucket string, filterByAppID string) string {
-	query := `
-			import "date"
-			import "join"
-
-			allData = from(bucket: "%s")
-						|> range(start: %s,stop: %s)
-						%s
-						%s
-						|> drop(columns:["emitter_chain","destination_chain","version","_measurement"])
-			
-			totalMsgs = allData
-						|> filter(fn: (r) => r._field == "total_messages")
-						|> aggregateWindow(every: 1mo, fn: sum)
-						|> rename(columns: {_value: "total_messages"})
-						|> group()
-						
-			tvt = allData
-						|> filter(fn: (r) => r._field == "total_value_transferred")
-						|> aggregateWindow(every: 1mo, fn: sum)
-						|> rename(columns: {_value: "total_value_transferred"})
-						|> group()
-
-			join.inner(
-			    left: totalMsgs,
-			    right: tvt,
-			    on: (l, r) => l.app_id == r.app_id and l._time == r._time,
-			    as: (l, r) => ({
-					"to":l._time,
-					"_time": date.sub(d: 1mo, from: l._time),
-					"app_id": l.app_id,
-					"total_messages":l.total_messages,
-					"total_value_transferred": float(v:r.total_value_transferred) / 100000000.0
-					}),
-			)
-	`
-	from := time.Date(q.From.Year(), q.From.Month(), 1, 0, 0, 0, 0, q.From.Location())
-	to := time.Date(q.To.Year(), q.To.Month(), 1, 0, 0, 0, 0, q.To.Location())
-	return fmt.Sprintf(query, bucket, from.Format(time.RFC3339), to.Format(time.RFC3339), filterMeasurement, filterByAppID)
-}
-
 func (r *Repository) getMayanOverview(ctx context.Context) (*StatsOverview, error) {
 	url := r.mayanBaseURL + "/v3/stats/overview"
 
@@ -8,13 +8,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/config"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux/fluxtest"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 	"go.uber.org/zap"
 	"io"
 	"net/http"
-	"strings"
 	"testing"
 	"time"
 )
@@ -61,12 +62,13 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 	}
 
 	tcs := []struct {
-		name     string
-		input    ChainActivityTopsQuery
-		expected string
+		name   string
+		golden string
+		input  ChainActivityTopsQuery
 	}{
 		{
-			name: "Search only by time range hourly",
+			name:   "Search only by time range hourly",
+			golden: "chain_activity_tops_search_only_by_time_range_hourly",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{},
 				TargetChains: []sdk.ChainID{},
@@ -74,18 +76,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				To:           time.Date(2024, 1, 3, 5, 30, 5, 0, time.UTC),
 				Timespan:     Hour,
 			},
-			expected: `
-					import "date"
-
-					from(bucket: "wormscan-testenv")
-					|> range(start: 2024-01-01T00:00:00Z,stop: 2024-01-03T05:00:00Z)
-					|> filter(fn: (r) => r._measurement == "emitter_chain_activity_1h")
-					
-					|> pivot(rowKey:["_time","emitter_chain"], columnKey: ["_field"], valueColumn: "_value")
-					|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search only by time range daily",
+			name:   "Search only by time range daily",
+			golden: "chain_activity_tops_search_only_by_time_range_daily",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{},
 				TargetChains: []sdk.ChainID{},
@@ -93,18 +87,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				To:           time.Date(2024, 1, 3, 5, 30, 5, 0, time.UTC),
 				Timespan:     Day,
 			},
-			expected: `
-					import "date"
-
-					from(bucket: "wormscan-testenv")
-					|> range(start: 2024-01-01T00:00:00Z,stop: 2024-01-03T00:00:00Z)
-					|> filter(fn: (r) => r._measurement == "emitter_chain_activity_1d")
-					
-					|> pivot(rowKey:["_time","emitter_chain"], columnKey: ["_field"], valueColumn: "_value")
-					|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search only by time range monthly",
+			name:   "Search only by time range monthly",
+			golden: "chain_activity_tops_search_only_by_time_range_monthly",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{},
 				TargetChains: []sdk.ChainID{},
@@ -112,45 +98,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				To:           time.Date(2024, 1, 3, 5, 30, 5, 0, time.UTC),
 				Timespan:     Month,
 			},
-			expected: `
-				import "date"
-				import "join"
-
-				data = from(bucket: "wormscan-testenv")
-						|> range(start: 2023-10-01T00:00:00Z,stop: 2024-01-01T00:00:00Z)
-						|> filter(fn: (r) => r._measurement == "emitter_chain_activity_1d")
-						
-						|> drop(columns:["to"])
-						|> window(every: 1mo, period:1mo)
-						|> drop(columns:["_time"])
-						|> rename(columns: {_start: "_time"})
-						|> map(fn: (r) => ({r with to: string(v: r._stop)}))
-
-				vols = data
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-				counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-				join.inner(
-						left: vols,
-						right: counts,
-						on: (l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain,
-						as: (l, r) => ({l with count: r.count}),
-				)
-				|> group()
-				|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search only by time range yearly",
+			name:   "Search only by time range yearly",
+			golden: "chain_activity_tops_search_only_by_time_range_yearly",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{},
 				TargetChains: []sdk.ChainID{},
@@ -158,44 +109,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				To:           time.Date(2024, 1, 3, 5, 30, 5, 0, time.UTC),
 				Timespan:     Year,
 			},
-			expected: `
-				import "date"
-				import "join"
-
-				data = from(bucket: "wormscan-testenv")
-						|> range(start: 2020-01-01T00:00:00Z,stop: 2024-01-01T00:00:00Z)
-						|> filter(fn: (r) => r._measurement == "emitter_chain_activity_1d")
-						
-						|> drop(columns:["to"])
-						|> window(every: 1y, period:1y)
-						|> drop(columns:["_time"])
-						|> rename(columns: {_start: "_time"})
-						|> map(fn: (r) => ({r with to: string(v: r._stop)}))
-
-				vols = data
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-				counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-				join.inner(
-						left: vols,
-						right: counts,
-						on: (l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain,
-						as: (l, r) => ({l with count: r.count}),
-				)
-				|> group()
-				|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search by emitter_chain daily",
+			name:   "Search by emitter_chain daily",
+			golden: "chain_activity_tops_search_by_emitter_chain_daily",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{1},
 				TargetChains: []sdk.ChainID{},
@@ -203,18 +120,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				To:           time.Date(2024, 1, 3, 5, 30, 5, 0, time.UTC),
 				Timespan:     Day,
 			},
-			expected: `
-					import "date"
-
-					from(bucket: "wormscan-testenv")
-					|> range(start: 2024-01-01T00:00:00Z,stop: 2024-01-03T00:00:00Z)
-					|> filter(fn: (r) => r._measurement == "emitter_chain_activity_1d")
-					|> filter(fn: (r) => r.emitter_chain == "1")
-					|> pivot(rowKey:["_time","emitter_chain"], columnKey: ["_field"], valueColumn: "_value")
-					|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search by multiple emitter_chain daily",
+			name:   "Search by multiple emitter_chain daily",
+			golden: "chain_activity_tops_search_by_multiple_emitter_chain_daily",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{1, 2},
 				TargetChains: []sdk.ChainID{},
@@ -222,18 +131,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				To:           time.Date(2024, 1, 3, 5, 30, 5, 0, time.UTC),
 				Timespan:     Day,
 			},
-			expected: `
-					import "date"
-
-					from(bucket: "wormscan-testenv")
-					|> range(start: 2024-01-01T00:00:00Z,stop: 2024-01-03T00:00:00Z)
-					|> filter(fn: (r) => r._measurement == "emitter_chain_activity_1d")
-					|> filter(fn: (r) => r.emitter_chain == "1" or r.emitter_chain == "2")
-					|> pivot(rowKey:["_time","emitter_chain"], columnKey: ["_field"], valueColumn: "_value")
-					|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search by emitter_chain and target_chain hourly",
+			name:   "Search by emitter_chain and target_chain hourly",
+			golden: "chain_activity_tops_search_by_emitter_chain_and_target_chain_hourly",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{1},
 				TargetChains: []sdk.ChainID{2},
@@ -241,43 +142,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				To:           time.Date(2024, 1, 3, 5, 30, 5, 0, time.UTC),
 				Timespan:     Hour,
 			},
-			expected: `
-					import "date"
-					import "join"
-
-					data = from(bucket: "wormscan-testenv")
-		  			|> range(start: 2024-01-01T00:00:00Z,stop: 2024-01-03T05:00:00Z)
-		  			|> filter(fn: (r) => r._measurement == "chain_activity_1h")
-					|> filter(fn: (r) => r.emitter_chain == "1")
-					|> filter(fn: (r) => r.destination_chain == "2")
-					
-					|> drop(columns:["destination_chain"])
-
-					vols = data		
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-					counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-					join.inner(
-					    left: vols,
-					    right: counts,
-					    on: (l, r) => l._time == r._time and l.to == r.to and l.emitter_chain == r.emitter_chain,
-					    as: (l, r) => ({l with count: r.count}),
-					)
-					|> group()
-					|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search by multiple emitter_chain and multiple target_chain daily",
+			name:   "Search by multiple emitter_chain and multiple target_chain daily",
+			golden: "chain_activity_tops_search_by_multiple_emitter_chain_and_multiple_target_chain_daily",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{1, 3},
 				TargetChains: []sdk.ChainID{2, 4},
@@ -285,43 +153,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				To:           time.Date(2024, 1, 3, 5, 30, 5, 0, time.UTC),
 				Timespan:     Day,
 			},
-			expected: `
-					import "date"
-					import "join"
-
-					data = from(bucket: "wormscan-testenv")
-		  			|> range(start: 2024-01-01T00:00:00Z,stop: 2024-01-03T00:00:00Z)
-		  			|> filter(fn: (r) => r._measurement == "chain_activity_1d")
-					|> filter(fn: (r) => r.emitter_chain == "1" or r.emitter_chain == "3")
-					|> filter(fn: (r) => r.destination_chain == "2" or r.destination_chain == "4")
-					
-					|> drop(columns:["destination_chain"])
-
-					vols = data		
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-					counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-					join.inner(
-					    left: vols,
-					    right: counts,
-					    on: (l, r) => l._time == r._time and l.to == r.to and l.emitter_chain == r.emitter_chain,
-					    as: (l, r) => ({l with count: r.count}),
-					)
-					|> group()
-					|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search by app_id daily",
+			name:   "Search by app_id daily",
+			golden: "chain_activity_tops_search_by_app_id_daily",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{},
 				TargetChains: []sdk.ChainID{},
@@ -330,43 +165,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				Timespan:     Day,
 				AppId:        "CCTP_WORMHOLE_INTEGRATION",
 			},
-			expected: `
-					import "date"
-					import "join"
-
-					data = from(bucket: "wormscan-testenv")
-		  			|> range(start: 2024-01-01T00:00:00Z,stop: 2024-01-03T00:00:00Z)
-		  			|> filter(fn: (r) => r._measurement == "chain_activity_1d")
-					
-					
-					|> filter(fn: (r) => r.app_id == "CCTP_WORMHOLE_INTEGRATION")
-					|> drop(columns:["destination_chain"])
-
-					vols = data		
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-					counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-					join.inner(
-					    left: vols,
-					    right: counts,
-					    on: (l, r) => l._time == r._time and l.to == r.to and l.emitter_chain == r.emitter_chain,
-					    as: (l, r) => ({l with count: r.count}),
-					)
-					|> group()
-					|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search by multiple emitter_chain, destination_chain and app_id daily",
+			name:   "Search by multiple emitter_chain, destination_chain and app_id daily",
+			golden: "chain_activity_tops_search_by_multiple_emitter_chain_destination_chain_and_app_id_daily",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{1, 2},
 				TargetChains: []sdk.ChainID{3, 4},
@@ -375,43 +177,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				Timespan:     Day,
 				AppId:        "CCTP_WORMHOLE_INTEGRATION",
 			},
-			expected: `
-					import "date"
-					import "join"
-
-					data = from(bucket: "wormscan-testenv")
-		  			|> range(start: 2024-01-01T00:00:00Z,stop: 2024-01-03T00:00:00Z)
-		  			|> filter(fn: (r) => r._measurement == "chain_activity_1d")
-					|> filter(fn: (r) => r.emitter_chain == "1" or r.emitter_chain == "2")
-					|> filter(fn: (r) => r.destination_chain == "3" or r.destination_chain == "4")
-					|> filter(fn: (r) => r.app_id == "CCTP_WORMHOLE_INTEGRATION")
-					|> drop(columns:["destination_chain"])
-
-					vols = data		
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-					counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-					join.inner(
-					    left: vols,
-					    right: counts,
-					    on: (l, r) => l._time == r._time and l.to == r.to and l.emitter_chain == r.emitter_chain,
-					    as: (l, r) => ({l with count: r.count}),
-					)
-					|> group()
-					|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search by multiple emitter_chain, destination_chain and app_id monthly",
+			name:   "Search by multiple emitter_chain, destination_chain and app_id monthly",
+			golden: "chain_activity_tops_search_by_multiple_emitter_chain_destination_chain_and_app_id_monthly",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{1, 2},
 				TargetChains: []sdk.ChainID{3, 4},
@@ -420,47 +189,10 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				Timespan:     Month,
 				AppId:        "CCTP_WORMHOLE_INTEGRATION",
 			},
-			expected: `
-				import "date"
-				import "join"
-
-				data = from(bucket: "wormscan-testenv")
-						|> range(start: 2023-09-01T00:00:00Z,stop: 2024-03-01T00:00:00Z)
-						|> filter(fn: (r) => r._measurement == "chain_activity_1d")
-						|> filter(fn: (r) => r.emitter_chain == "1" or r.emitter_chain == "2")
-						|> filter(fn: (r) => r.destination_chain == "3" or r.destination_chain == "4")
-						|> filter(fn: (r) => r.app_id == "CCTP_WORMHOLE_INTEGRATION")
-						|> drop(columns:["destination_chain","to","app_id"])
-						|> window(every: 1mo, period:1mo)
-						|> drop(columns:["_time"])
-						|> rename(columns: {_start: "_time"})
-						|> map(fn: (r) => ({r with to: string(v: r._stop)}))
-
-				vols = data
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-				counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-				join.inner(
-						left: vols,
-						right: counts,
-						on: (l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain,
-						as: (l, r) => ({l with count: r.count}),
-				)
-				|> group()
-				|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 		{
-			name: "Search by multiple emitter_chain, destination_chain and app_id yearly",
+			name:   "Search by multiple emitter_chain, destination_chain and app_id yearly",
+			golden: "chain_activity_tops_search_by_multiple_emitter_chain_destination_chain_and_app_id_yearly",
 			input: ChainActivityTopsQuery{
 				SourceChains: []sdk.ChainID{1, 2},
 				TargetChains: []sdk.ChainID{3, 4},
@@ -469,51 +201,12 @@ func Test_buildChainActivityQueryTops(t *testing.T) {
 				Timespan:     Year,
 				AppId:        "CCTP_WORMHOLE_INTEGRATION",
 			},
-			expected: `
-				import "date"
-				import "join"
-
-				data = from(bucket: "wormscan-testenv")
-						|> range(start: 2020-01-01T00:00:00Z,stop: 2024-01-01T00:00:00Z)
-						|> filter(fn: (r) => r._measurement == "chain_activity_1d")
-						|> filter(fn: (r) => r.emitter_chain == "1" or r.emitter_chain == "2")
-						|> filter(fn: (r) => r.destination_chain == "3" or r.destination_chain == "4")
-						|> filter(fn: (r) => r.app_id == "CCTP_WORMHOLE_INTEGRATION")
-						|> drop(columns:["destination_chain","to","app_id"])
-						|> window(every: 1y, period:1y)
-						|> drop(columns:["_time"])
-						|> rename(columns: {_start: "_time"})
-						|> map(fn: (r) => ({r with to: string(v: r._stop)}))
-
-				vols = data
-						|> filter(fn: (r) => (r._field == "volume" and r._value > 0))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "volume"})
-
-				counts = data
-						|> filter(fn: (r) => (r._field == "count"))
-						|> group(columns:["_time","to","emitter_chain"])
-						|> toUInt()
-						|> sum()
-						|> rename(columns: {_value: "count"})
-
-				join.inner(
-						left: vols,
-						right: counts,
-						on: (l, r) => l._time == r._time and l.emitter_chain == r.emitter_chain,
-						as: (l, r) => ({l with count: r.count}),
-				)
-				|> group()
-				|> sort(columns:["emitter_chain","_time"],desc:false)`,
 		},
 	}
 
 	for _, testCase := range tcs {
 		t.Run(testCase.name, func(t *testing.T) {
-			got := repository.buildChainActivityQueryTops(testCase.input)
-			assert.Equal(t, testCase.expected, got, "Expected query did not match actual one.")
+			fluxtest.AssertGolden(t, testCase.golden, repository.buildChainActivityQueryTops(testCase.input))
 		})
 	}
 }
@@ -525,44 +218,38 @@ func Test_buildAppActivityQuery(t *testing.T) {
 	}
 
 	tcs := []struct {
-		name                string
-		input               ApplicationActivityQuery
-		expectedAppQuery    string
-		expectedTotalsQuery string
+		name   string
+		golden string
+		input  ApplicationActivityQuery
 	}{
 		{
-			name: "Search by timespan monthly",
+			name:   "Search by timespan monthly",
+			golden: "app_activity_search_by_timespan_monthly",
 			input: ApplicationActivityQuery{
 				AppId:    "CCTP_WORMHOLE_INTEGRATION",
 				From:     time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC),
 				To:       time.Date(2024, 3, 3, 5, 30, 5, 0, time.UTC),
 				Timespan: Month,
 			},
-			expectedAppQuery:    "\n\t\t\timport \"date\"\n\t\t\timport \"join\"\n\n\t\t\tallData = from(bucket: \"wormscan-testenv\")\n\t\t\t\t\t\t|> range(start: 2023-10-01T00:00:00Z,stop: 2024-03-01T00:00:00Z)\n\t\t\t\t\t\t|> filter(fn: (r) => r._measurement == \"protocols_stats_1d\")\n\t\t\t\t\t\t|> filter(fn: (r) => not exists r.protocol )\n\t\t\t\t\t\t|> filter(fn: (r) => r.app_id_1 == \"CCTP_WORMHOLE_INTEGRATION\" or r.app_id_2 == \"CCTP_WORMHOLE_INTEGRATION\" or r.app_id_3 == \"CCTP_WORMHOLE_INTEGRATION\")\n\t\t\t\t\t\t|> drop(columns:[\"emitter_chain\",\"destination_chain\",\"_measurement\"])\n\n\t\t\ttotalMsgs = allData\n\t\t\t\t\t\t|> filter(fn: (r) => r._field == \"total_messages\")\n\t\t\t\t\t\t|> aggregateWindow(every: 1mo, fn: sum)\n\t\t\t\t\t\t|> rename(columns: {_value: \"total_messages\"})\n\t\t\t\t\t\t|> map(fn: (r) => ({\n\t\t\t\t\t\t\t\tr with\n\t\t\t\t\t\t\t\t_time: date.sub(d: 1mo, from: r._time),\n\t\t\t\t\t\t\t\ttotal_messages: if not exists r.total_messages then uint(v:0) else r.total_messages\n     \t\t\t\t\t\t}))\n\t\t\t\t\t\t|> drop(columns:[\"_start\",\"_stop\"])\n\t\t\t\t\t\t|> group()\n\t\t\t\n\t\t\t\n\t\t\ttvt = allData\n\t\t\t\t\t|> filter(fn: (r) => r._field == \"total_value_transferred\")\n\t\t\t\t\t|> aggregateWindow(every: 1mo, fn: sum)\n\t\t\t\t\t|> rename(columns: {_value: \"total_value_transferred\"})\t\t\n\t\t\t\t\t|> map(fn: (r) => ({\n\t\t\t\t\t\tr with\n\t\t\t\t\t\t_time: date.sub(d: 1mo, from: r._time),\n\t\t\t\t\t\ttotal_value_transferred: if not exists r.total_value_transferred then uint(v:0) else r.total_value_transferred\n\t\t\t\t\t}))\n\t\t\t\t\t|> drop(columns:[\"_start\",\"_stop\"])\n\t\t\t\t\t|> group()\n\t\t\t\t\t\t\n\t\t\tjoin.inner(\n\t\t\t    left: totalMsgs,\n\t\t\t    right: tvt,\n\t\t\t    on: (l, r) => l.app_id_1 == r.app_id_1 and l.app_id_2 == r.app_id_2 and l.app_id_3 == r.app_id_3 and l._time == r._time,\n\t\t\t    as: (l, r) => ({\n\t\t\t\t\t\"_time\":l._time,\n\t\t\t\t\t\"to\":date.add(d: 1mo, to: l._time),\n\t\t\t\t\t\"app_id_1\": l.app_id_1,\n\t\t\t\t\t\"app_id_2\": l.app_id_2,\n\t\t\t\t\t\"app_id_3\": l.app_id_3,\n\t\t\t\t\t\"total_messages\":l.total_messages,\n\t\t\t\t\t\"total_value_transferred\": float(v:r.total_value_transferred) / 100000000.0\n\t\t\t\t\t})\n\t\t\t)\n\t\t",
-			expectedTotalsQuery: "\n\t\t\timport \"date\"\n\t\t\timport \"join\"\n\n\t\t\tallData = from(bucket: \"wormscan-testenv\")\n\t\t\t\t\t\t|> range(start: 2023-10-01T00:00:00Z,stop: 2024-03-01T00:00:00Z)\n\t\t\t\t\t\t|> filter(fn: (r) => r._measurement == \"protocols_stats_totals_1d\" and r.version == \"v1\")\n\t\t\t\t\t\t|> filter(fn: (r) => r.app_id == \"TOTAL_CCTP_WORMHOLE_INTEGRATION\")\n\t\t\t\t\t\t|> drop(columns:[\"emitter_chain\",\"destination_chain\",\"version\",\"_measurement\"])\n\t\t\t\n\t\t\ttotalMsgs = allData\n\t\t\t\t\t\t|> filter(fn: (r) => r._field == \"total_messages\")\n\t\t\t\t\t\t|> aggregateWindow(every: 1mo, fn: sum)\n\t\t\t\t\t\t|> rename(columns: {_value: \"total_messages\"})\n\t\t\t\t\t\t|> group()\n\t\t\t\t\t\t\n\t\t\ttvt = allData\n\t\t\t\t\t\t|> filter(fn: (r) => r._field == \"total_value_transferred\")\n\t\t\t\t\t\t|> aggregateWindow(every: 1mo, fn: sum)\n\t\t\t\t\t\t|> rename(columns: {_value: \"total_value_transferred\"})\n\t\t\t\t\t\t|> group()\n\n\t\t\tjoin.inner(\n\t\t\t    left: totalMsgs,\n\t\t\t    right: tvt,\n\t\t\t    on: (l, r) => l.app_id == r.app_id and l._time == r._time,\n\t\t\t    as: (l, r) => ({\n\t\t\t\t\t\"to\":l._time,\n\t\t\t\t\t\"_time\": date.sub(d: 1mo, from: l._time),\n\t\t\t\t\t\"app_id\": l.app_id,\n\t\t\t\t\t\"total_messages\":l.total_messages,\n\t\t\t\t\t\"total_value_transferred\": float(v:r.total_value_transferred) / 100000000.0\n\t\t\t\t\t}),\n\t\t\t)\n\t",
 		},
 		{
-			name: "Search by timespan hourly",
+			name:   "Search by timespan hourly",
+			golden: "app_activity_search_by_timespan_hourly",
 			input: ApplicationActivityQuery{
 				AppId:    "CCTP_WORMHOLE_INTEGRATION",
 				From:     time.Date(2023, 10, 7, 11, 13, 55, 0, time.UTC),
 				To:       time.Date(2024, 3, 3, 5, 30, 5, 0, time.UTC),
 				Timespan: Hour,
 			},
-			expectedAppQuery:    "\n\t\t\timport \"date\"\n\n\t\t\t\tallData = from(bucket: \"wormscan-30days-testenv\")\n\t\t\t\t\t\t\t|> range(start: 2023-10-07T11:00:00Z,stop: 2024-03-03T05:00:00Z)\n\t\t\t\t\t\t\t|> filter(fn: (r) => r._measurement == \"protocols_stats_1h\")\n\t\t\t\t\t\t\t|> filter(fn: (r) => not exists r.protocol )\n\t\t\t\t\t\t\t|> filter(fn: (r) => r.app_id_1 == \"CCTP_WORMHOLE_INTEGRATION\" or r.app_id_2 == \"CCTP_WORMHOLE_INTEGRATION\" or r.app_id_3 == \"CCTP_WORMHOLE_INTEGRATION\")\n\t\t\t\t\t\t\t|> drop(columns:[\"emitter_chain\",\"destination_chain\",\"_measurement\"])\n\n\t\t\t\ttotalMsgs = allData\n\t\t\t\t\t\t\t|> filter(fn: (r) => r._field == \"total_messages\")\n\t\t\t\t\t\t\t|> aggregateWindow(every: 1h, fn: sum, createEmpty:true)\n\t\t\t\t\t\t\t|> map(fn: (r) => ({\n\t\t\t\t\t\t\t\t\t\tr with\n\t\t\t\t\t\t\t\t\t\t_value: if not exists r._value then uint(v:0) else r._value\n\t\t\t\t\t\t\t\t}))\n\t\t\t\t\t\t\t|> group(columns:[\"_time\",\"_field\",\"app_id_1\",\"app_id_2\",\"app_id_3\"])\n\t\t\t\t\t\t\t|> sum()\n\t\t\t\t\t\t\n\t\t\t\ttvt = allData\n\t\t\t\t\t\t|> filter(fn: (r) => r._field == \"total_value_transferred\")\n\t\t\t\t\t\t|> aggregateWindow(every: 1h, fn: sum, createEmpty:true)\n\t\t\t\t\t\t|> map(fn: (r) => ({\n\t\t\t\t\t\t\t\tr with\n\t\t\t\t\t\t\t\t_value: if not exists r._value then uint(v:0) else r._value\n     \t\t\t\t\t\t}))\n\t\t\t\t\t\t|> group(columns:[\"_time\",\"_field\",\"app_id_1\",\"app_id_2\",\"app_id_3\"])\n\t\t\t\t\t\t|> sum()\n\t\t\t\t\t\t\n\t\t\t\tunion(tables: [totalMsgs, tvt])\n\t\t\t\t|> pivot(rowKey:[\"_time\",\"app_id_1\",\"app_id_2\",\"app_id_3\"], columnKey: [\"_field\"], valueColumn: \"_value\")\n\t\t\t\t|> map(fn: (r) => ({\n\t\t\t\t\t\tr with\n\t\t\t\t\t\t\"total_value_transferred\": float(v:r.total_value_transferred) / 100000000.0,\n\t\t\t\t\t\t\"to\": r._time,\n\t\t\t\t\t\t\"_time\": date.sub(d: 1h, from: r._time)\n\t\t\t\t}))",
-			expectedTotalsQuery: "\n\t\t\timport \"date\"\n\n\t\t\tallData = from(bucket: \"wormscan-30days-testenv\")\n\t\t\t\t\t\t|> range(start: 2023-10-07T11:00:00Z,stop: 2024-03-03T05:00:00Z)\n\t\t\t\t\t\t|> filter(fn: (r) => r._measurement == \"protocols_stats_totals_1h\")\n\t\t\t\t\t\t|> filter(fn: (r) => r.app_id == \"TOTAL_CCTP_WORMHOLE_INTEGRATION\")\n\t\t\t\t\t\t|> drop(columns:[\"emitter_chain\",\"destination_chain\"])\n\t\t\t\n\t\t\ttotalMsgs = allData\n\t\t\t\t\t\t|> filter(fn: (r) => r._field == \"total_messages\")\n\t\t\t\t\t\t|> aggregateWindow(every: 1h, fn: sum,createEmpty:true)\n\t\t\t\t\t\t|> map(fn: (r) => ({\n\t\t\t\t\t\t\t\tr with\n\t\t\t\t\t\t\t\t_value: if not exists r._value then uint(v:0) else uint(v:r._value)\n     \t\t\t\t\t\t}))\n\t\t\t\t\t\t|> group(columns:[\"_time\",\"app_id\",\"_field\"])\n\t\t\t\t\t\t|> sum()\n\t\t\t\t\t\t\n\t\t\ttvt = allData\n\t\t\t\t\t\t|> filter(fn: (r) => r._field == \"total_value_transferred\")\n\t\t\t\t\t\t|> aggregateWindow(every: 1h, fn: sum, createEmpty:true)\n\t\t\t\t\t\t|> map(fn: (r) => ({\n\t\t\t\t\t\t\t\tr with\n\t\t\t\t\t\t\t\t_value: if not exists r._value then uint(v:0) else r._value\n     \t\t\t\t\t\t}))\n\t\t\t\t\t\t|> group(columns:[\"_time\",\"app_id\",\"_field\"])\n\t\t\t\t\t\t|> sum()\n\n\t\t\tunion(tables: [totalMsgs, tvt])\n\t\t\t\t|> pivot(rowKey:[\"_time\",\"app_id\"], columnKey: [\"_field\"], valueColumn: \"_value\")\n\t\t\t\t|> map(fn: (r) => ({\n\t\t\t\t\t\tr with\n\t\t\t\t\t\t\"total_value_transferred\": float(v:r.total_value_transferred) / 100000000.0,\n\t\t\t\t\t\t\"to\": r._time,\n\t\t\t\t\t\t\"_time\": date.sub(d: 1h, from: r._time)\n     \t\t\t}))\n\t\t\t",
 		},
 	}
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			query := repository.buildAppActivityQuery(tc.input)
-			assert.Equal(t, tc.expectedAppQuery, query)
-			totalsQuery := repository.buildTotalsAppActivityQuery(tc.input)
-			assert.Equal(t, tc.expectedTotalsQuery, totalsQuery)
+			fluxtest.AssertGolden(t, tc.golden, repository.buildAppActivityQuery(tc.input))
+			fluxtest.AssertGolden(t, tc.golden+"_totals", repository.buildTotalsAppActivityQuery(tc.input))
 		})
 	}
-
 }
 
 func Test_buildTokenSymbolActivityQuery(t *testing.T) {
@@ -572,12 +259,13 @@ func Test_buildTokenSymbolActivityQuery(t *testing.T) {
 	}
 
 	tcs := []struct {
-		name          string
-		input         TokenSymbolActivityQuery
-		expectedQuery string
+		name   string
+		golden string
+		input  TokenSymbolActivityQuery
 	}{
 		{
-			name: "Hourly timespan with single token symbol and single source/target chain",
+			name:   "Hourly timespan with single token symbol and single source/target chain",
+			golden: "token_symbol_activity_hourly_timespan_with_single_token_symbol_and_single_source_target_chain",
 			input: TokenSymbolActivityQuery{
 				From:         time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC),
 				To:           time.Date(2023, 8, 1, 13, 0, 0, 0, time.UTC),
@@ -586,48 +274,10 @@ func Test_buildTokenSymbolActivityQuery(t *testing.T) {
 				TargetChains: []sdk.ChainID{2},
 				Timespan:     Hour,
 			},
-			expectedQuery: `
-	import "date"
-
-	sumAndCount = (tables=<-, column) => {
-		return tables
-				|> reduce(
-					identity: {
-						_value: uint(v:0),
-						txs: uint(v:0)
-					},
-					fn: (r, accumulator) => ({
-						_value: accumulator._value + r._value,
-						txs: accumulator.txs + uint(v:1)
-					})
-				)
-	}
-	
-	from(bucket: "wormscan-testenv")
-		|> range(start: 2023-08-01T12:00:00Z, stop: 2023-08-01T13:00:00Z)
-		|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-		|> filter(fn: (r) => r._field == "volume" or r._field == "symbol")
-		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
-		|> keep(columns:["_start","_stop","_time","emitter_chain","destination_chain","symbol","volume"])
-		|> filter(fn: (r) => r.volume > 0)
-		|> filter(fn: (r) => r.symbol == "BTC") //filter by symbol
-		|> filter(fn: (r) => r.emitter_chain == "1") //filter by source_chain
-		|> filter(fn: (r) => r.destination_chain == "2") //filter by target_chain
-		|> rename(columns: {volume: "_value"})
-		|> set(key: "_field", value: "volume")
-		|> group(columns:["symbol","emitter_chain","destination_chain","_field"])
-		|> aggregateWindow(every: 1h, fn: sumAndCount, createEmpty: true)
-		|> map(fn: (r) => ({
-				r with 
-				volume: if exists r._value then float(v:r._value) / 100000000.0 else float(v:0),
-				to: r._time,
-				_time: date.sub(d: 1h, from: r._time),
-		}))
-		|> drop(columns:["_value","_start","_stop","_field"])	
-	`,
 		},
 		{
-			name: "Daily timespan with multiple token symbols and multiple source/target chains",
+			name:   "Daily timespan with multiple token symbols and multiple source/target chains",
+			golden: "token_symbol_activity_daily_timespan_with_multiple_token_symbols_and_multiple_source_target_chains",
 			input: TokenSymbolActivityQuery{
 				From:         time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
 				To:           time.Date(2023, 8, 2, 0, 0, 0, 0, time.UTC),
@@ -636,99 +286,21 @@ func Test_buildTokenSymbolActivityQuery(t *testing.T) {
 				TargetChains: []sdk.ChainID{3, 4},
 				Timespan:     Day,
 			},
-			expectedQuery: `
-	import "date"
-
-	sumAndCount = (tables=<-, column) => {
-		return tables
-				|> reduce(
-					identity: {
-						_value: uint(v:0),
-						txs: uint(v:0)
-					},
-					fn: (r, accumulator) => ({
-						_value: accumulator._value + r._value,
-						txs: accumulator.txs + uint(v:1)
-					})
-				)
-	}
-	
-	from(bucket: "wormscan-testenv")
-		|> range(start: 2023-08-01T00:00:00Z, stop: 2023-08-02T00:00:00Z)
-		|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-		|> filter(fn: (r) => r._field == "volume" or r._field == "symbol")
-		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
-		|> keep(columns:["_start","_stop","_time","emitter_chain","destination_chain","symbol","volume"])
-		|> filter(fn: (r) => r.volume > 0)
-		|> filter(fn: (r) => r.symbol == "BTC" or r.symbol == "ETH") //filter by symbol
-		|> filter(fn: (r) => r.emitter_chain == "1" or r.emitter_chain == "2") //filter by source_chain
-		|> filter(fn: (r) => r.destination_chain == "3" or r.destination_chain == "4") //filter by target_chain
-		|> rename(columns: {volume: "_value"})
-		|> set(key: "_field", value: "volume")
-		|> group(columns:["symbol","emitter_chain","destination_chain","_field"])
-		|> aggregateWindow(every: 1d, fn: sumAndCount, createEmpty: true)
-		|> map(fn: (r) => ({
-				r with 
-				volume: if exists r._value then float(v:r._value) / 100000000.0 else float(v:0),
-				to: r._time,
-				_time: date.sub(d: 1d, from: r._time),
-		}))
-		|> drop(columns:["_value","_start","_stop","_field"])	
-	`,
 		},
 		{
-			name: "Monthly timespan with no token symbols and no chains",
+			name:   "Monthly timespan with no token symbols and no chains",
+			golden: "token_symbol_activity_monthly_timespan_with_no_token_symbols_and_no_chains",
 			input: TokenSymbolActivityQuery{
 				From:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
 				To:       time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
 				Timespan: Month,
 			},
-			expectedQuery: `
-	import "date"
-
-	sumAndCount = (tables=<-, column) => {
-		return tables
-				|> reduce(
-					identity: {
-						_value: uint(v:0),
-						txs: uint(v:0)
-					},
-					fn: (r, accumulator) => ({
-						_value: accumulator._value + r._value,
-						txs: accumulator.txs + uint(v:1)
-					})
-				)
-	}
-	
-	from(bucket: "wormscan-testenv")
-		|> range(start: 2023-01-01T00:00:00Z, stop: 2023-06-01T00:00:00Z)
-		|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-		|> filter(fn: (r) => r._field == "volume" or r._field == "symbol")
-		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
-		|> keep(columns:["_start","_stop","_time","emitter_chain","destination_chain","symbol","volume"])
-		|> filter(fn: (r) => r.volume > 0)
-		 //filter by symbol
-		 //filter by source_chain
-		 //filter by target_chain
-		|> rename(columns: {volume: "_value"})
-		|> set(key: "_field", value: "volume")
-		|> group(columns:["symbol","emitter_chain","destination_chain","_field"])
-		|> aggregateWindow(every: 1mo, fn: sumAndCount, createEmpty: true)
-		|> map(fn: (r) => ({
-				r with 
-				volume: if exists r._value then float(v:r._value) / 100000000.0 else float(v:0),
-				to: r._time,
-				_time: date.sub(d: 1mo, from: r._time),
-		}))
-		|> drop(columns:["_value","_start","_stop","_field"])	
-	`,
 		},
 	}
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			query := repository.buildTokenSymbolActivityQuery(tc.input)
-			assert.Equal(t, strings.TrimSpace(tc.expectedQuery), strings.TrimSpace(query))
+			fluxtest.AssertGolden(t, tc.golden, repository.buildTokenSymbolActivityQuery(tc.input))
 		})
 	}
 }
@@ -745,7 +317,7 @@ func TestGetScorecards(t *testing.T) {
 		mockTvlErr       error
 		mockQueryResults map[string]struct {
 			res           *mockInfluxQueryResult
-			expectedQuery string
+			expectedQuery flux.Query
 		}
 		mockPythResponse   bson.D
 		expectedErr        bool
@@ -757,7 +329,7 @@ func TestGetScorecards(t *testing.T) {
 			mockTvlErr:    nil,
 			mockQueryResults: map[string]struct {
 				res           *mockInfluxQueryResult
-				expectedQuery string
+				expectedQuery flux.Query
 			}{
 				"messages24h":   {mockInfluxResult(100), buildMessages24HrQuery("wormscan-24hours")},
 				"totalTxCount":  {mockInfluxResult(100), buildTotalTrxCountQuery("wormscan", "wormscan-30days", time.Now())},
@@ -788,7 +360,7 @@ func TestGetScorecards(t *testing.T) {
 			mockTvlErr:    errors.New("mock_tvl_error"),
 			mockQueryResults: map[string]struct {
 				res           *mockInfluxQueryResult
-				expectedQuery string
+				expectedQuery flux.Query
 			}{
 				"messages24h":   {mockInfluxResult(100), buildMessages24HrQuery("wormscan-24hours")},
 				"totalTxCount":  {mockInfluxResult(100), buildTotalTrxCountQuery("wormscan", "wormscan-30days", time.Now())},
@@ -810,7 +382,7 @@ func TestGetScorecards(t *testing.T) {
 			mockTvlErr:    nil,
 			mockQueryResults: map[string]struct {
 				res           *mockInfluxQueryResult
-				expectedQuery string
+				expectedQuery flux.Query
 			}{
 				"messages24h":   {mockInfluxError("failed_query"), buildMessages24HrQuery("wormscan-24hours")},
 				"totalTxCount":  {mockInfluxResult(100), buildTotalTrxCountQuery("wormscan", "wormscan-30days", time.Now())},
@@ -898,7 +470,7 @@ type mockQueryAPI struct {
 	mock.Mock
 }
 
-func (m *mockQueryAPI) Query(ctx context.Context, query string) (influxQueryResult, error) {
+func (m *mockQueryAPI) Query(ctx context.Context, query flux.Query) (influxQueryResult, error) {
 	args := m.Called(ctx, query)
 	return args.Get(0).(influxQueryResult), args.Error(1)
 }
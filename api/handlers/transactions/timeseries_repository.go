@@ -0,0 +1,313 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+const (
+	vaaVolumeMeasurement      = "vaa_volume_v2"
+	vaaAllMessagesMeasurement = "vaa_count_all_messages"
+)
+
+// TimeSeriesRepository serves the analytics queries (scorecards, chain activity, top assets
+// and top chain pairs) from a pluggable time-series backend.
+//
+// The totals are read from the measurements summarized by the analytics service, the same
+// measurements the InfluxDB tasks write for Repository, and the rest of the values are
+// aggregated from the raw measurements over bounded ranges. Every other query is delegated
+// to the embedded Repository.
+type TimeSeriesRepository struct {
+	*Repository
+	reader            timeseries.Reader
+	supportedChainIDs map[sdk.ChainID]string
+	logger            *zap.Logger
+}
+
+// NewTimeSeriesRepository creates a new *TimeSeriesRepository.
+func NewTimeSeriesRepository(repo *Repository, reader timeseries.Reader, logger *zap.Logger) *TimeSeriesRepository {
+	return &TimeSeriesRepository{
+		Repository:        repo,
+		reader:            reader,
+		supportedChainIDs: domain.GetSupportedChainIDs(),
+		logger:            logger,
+	}
+}
+
+// GetTopAssets returns the 7 assets with the highest volume in the time span.
+func (r *TimeSeriesRepository) GetTopAssets(ctx context.Context, timeSpan *TopStatisticsTimeSpan) ([]AssetDTO, error) {
+
+	start, err := topStatisticsStart(timeSpan)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.reader.Query(ctx, &timeseries.Query{
+		Bucket:      timeseries.BucketInfinite,
+		Measurement: vaaVolumeMeasurement,
+		Field:       "volume",
+		Start:       start,
+		GroupBy:     []string{"emitter_chain", "token_address", "token_chain"},
+		Aggregate:   timeseries.AggregateSum,
+		Limit:       7,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var assets []AssetDTO
+	for _, row := range rows {
+		emitterChain, err := strconv.ParseUint(row.Tags["emitter_chain"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert emitter chain field to uint16")
+		}
+		tokenChain, err := strconv.ParseUint(row.Tags["token_chain"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert token chain field to uint16")
+		}
+		assets = append(assets, AssetDTO{
+			EmitterChain: sdk.ChainID(emitterChain),
+			TokenChain:   sdk.ChainID(tokenChain),
+			TokenAddress: row.Tags["token_address"],
+			Volume:       convertToDecimal(uint64(row.Value)),
+		})
+	}
+	return assets, nil
+}
+
+// GetTopChainPairs returns the 7 chain pairs with the highest number of transfers in the time span.
+func (r *TimeSeriesRepository) GetTopChainPairs(ctx context.Context, timeSpan *TopStatisticsTimeSpan) ([]ChainPairDTO, error) {
+
+	start, err := topStatisticsStart(timeSpan)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.reader.Query(ctx, &timeseries.Query{
+		Bucket:      timeseries.BucketInfinite,
+		Measurement: vaaVolumeMeasurement,
+		Field:       "volume",
+		Start:       start,
+		GroupBy:     []string{"emitter_chain", "destination_chain"},
+		Aggregate:   timeseries.AggregateCount,
+		Limit:       100,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []ChainPairDTO
+	for _, row := range rows {
+		emitterChain, err := strconv.ParseUint(row.Tags["emitter_chain"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert emitter chain field to uint16")
+		}
+		destinationChain, err := strconv.ParseUint(row.Tags["destination_chain"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert destination chain field to uint16")
+		}
+		pair := ChainPairDTO{
+			EmitterChain:      sdk.ChainID(emitterChain),
+			DestinationChain:  sdk.ChainID(destinationChain),
+			NumberOfTransfers: fmt.Sprintf("%d", uint64(row.Value)),
+		}
+
+		// do not include invalid chain IDs in the response
+		if !domain.ChainIdIsValid(pair.EmitterChain) || !domain.ChainIdIsValid(pair.DestinationChain) {
+			continue
+		}
+
+		pairs = append(pairs, pair)
+		if len(pairs) == 7 {
+			break
+		}
+	}
+	return pairs, nil
+}
+
+// FindChainActivity returns the number of transfers (or the volume) between each pair of chains.
+func (r *TimeSeriesRepository) FindChainActivity(ctx context.Context, q *ChainActivityQuery) ([]ChainActivityResult, error) {
+
+	query := &timeseries.Query{
+		Bucket:      timeseries.BucketInfinite,
+		Measurement: vaaVolumeMeasurement,
+		Field:       "volume",
+		Start:       chainActivityStart(q.TimeSpan),
+		GroupBy:     []string{"emitter_chain", "destination_chain"},
+		Aggregate:   timeseries.AggregateCount,
+	}
+	if q.IsNotional {
+		query.Aggregate = timeseries.AggregateSum
+	}
+	if q.HasAppIDS() {
+		query.Include = map[string][]string{"app_id": q.GetAppIDs()}
+	}
+
+	rows, err := r.reader.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []ChainActivityResult
+	for _, row := range rows {
+		res := ChainActivityResult{
+			ChainSourceID:      row.Tags["emitter_chain"],
+			ChainDestinationID: row.Tags["destination_chain"],
+			Volume:             uint64(row.Value),
+		}
+		if !r.isSupportedChain(res.ChainSourceID) || !r.isSupportedChain(res.ChainDestinationID) {
+			continue
+		}
+		response = append(response, res)
+	}
+	return response, nil
+}
+
+// GetScorecards returns the scorecards.
+//
+// The totals are the last daily totals summarized until the start of the day plus the transfers
+// of the current day, so that the whole history is not scanned on every request.
+func (r *TimeSeriesRepository) GetScorecards(ctx context.Context) (*Scorecards, error) {
+
+	now := time.Now()
+
+	messages24h, err := r.aggregate(ctx, timeseries.Bucket24Hours, vaaAllMessagesMeasurement, "count", now.Add(-24*time.Hour), timeseries.AggregateCount)
+	if err != nil {
+		r.logger.Error("failed to get 24h messages", zap.Error(err))
+		return nil, err
+	}
+
+	totalTxCount, err := r.total(ctx, now, "total_tx_count_v2", timeseries.AggregateCount)
+	if err != nil {
+		r.logger.Error("failed to get total tx count", zap.Error(err))
+		return nil, err
+	}
+
+	totalTxVolume, err := r.total(ctx, now, "total_tx_volume_v2", timeseries.AggregateSum)
+	if err != nil {
+		r.logger.Error("failed to get total tx volume", zap.Error(err))
+		return nil, err
+	}
+
+	var volumes [3]float64
+	for i, days := range []int{1, 7, 30} {
+		volumes[i], err = r.aggregate(ctx, timeseries.BucketInfinite, vaaVolumeMeasurement, "volume", now.AddDate(0, 0, -days), timeseries.AggregateSum)
+		if err != nil {
+			r.logger.Error("failed to get volume", zap.Int("days", days), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	tvl, err := r.tvl.Get(ctx)
+	if err != nil {
+		r.logger.Error("failed to get tvl", zap.Error(err))
+		return nil, err
+	}
+
+	totalPythMessage, err := r.getTotalPythMessage(ctx)
+	if err != nil {
+		r.logger.Error("failed to get total pyth message", zap.Error(err))
+		return nil, err
+	}
+
+	txCount := strconv.FormatUint(uint64(totalTxCount), 10)
+	return &Scorecards{
+		Messages24h:   strconv.FormatUint(uint64(messages24h), 10),
+		TotalMessages: calculateTotalMessage(r.p2pNetwork, txCount, totalPythMessage),
+		TotalTxCount:  txCount,
+		TotalTxVolume: convertToDecimal(uint64(totalTxVolume)),
+		Tvl:           tvl,
+		Volume24h:     fmt.Sprintf("%.8f", volumes[0]/1e8),
+		Volume7d:      fmt.Sprintf("%.8f", volumes[1]/1e8),
+		Volume30d:     fmt.Sprintf("%.8f", volumes[2]/1e8),
+	}, nil
+}
+
+// total returns the last total stored in the summarized measurement plus the aggregation
+// of the transfers of the current day.
+func (r *TimeSeriesRepository) total(ctx context.Context, now time.Time, measurement string, aggregate timeseries.Aggregate) (float64, error) {
+
+	last, err := r.aggregate(ctx, timeseries.Bucket30Days, measurement, "value", now.AddDate(0, -1, 0), timeseries.AggregateLast)
+	if err != nil {
+		return 0, err
+	}
+	current, err := r.aggregate(ctx, timeseries.BucketInfinite, vaaVolumeMeasurement, "volume", now.Truncate(24*time.Hour), aggregate)
+	if err != nil {
+		return 0, err
+	}
+	return last + current, nil
+}
+
+// aggregate returns the aggregated value of a field since the given time.
+func (r *TimeSeriesRepository) aggregate(
+	ctx context.Context,
+	bucket timeseries.Bucket,
+	measurement, field string,
+	start time.Time,
+	aggregate timeseries.Aggregate,
+) (float64, error) {
+
+	rows, err := r.reader.Query(ctx, &timeseries.Query{
+		Bucket:      bucket,
+		Measurement: measurement,
+		Field:       field,
+		Start:       start,
+		Aggregate:   aggregate,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Value, nil
+}
+
+func (r *TimeSeriesRepository) isSupportedChain(s string) bool {
+	chainID, err := strconv.Atoi(s)
+	if err != nil {
+		return false
+	}
+	_, ok := r.supportedChainIDs[sdk.ChainID(chainID)]
+	return ok
+}
+
+// topStatisticsStart returns the start of the time range for a TopStatisticsTimeSpan.
+func topStatisticsStart(timeSpan *TopStatisticsTimeSpan) (time.Time, error) {
+	if timeSpan == nil {
+		return time.Time{}, fmt.Errorf("invalid nil timeSpan")
+	}
+	switch *timeSpan {
+	case TimeSpan7Days:
+		return time.Now().AddDate(0, 0, -7), nil
+	case TimeSpan15Days:
+		return time.Now().AddDate(0, 0, -15), nil
+	case TimeSpan30Days:
+		return time.Now().AddDate(0, 0, -30), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid time span: %s", *timeSpan)
+	}
+}
+
+// chainActivityStart returns the start of the time range for a ChainActivityTimeSpan.
+func chainActivityStart(timeSpan ChainActivityTimeSpan) time.Time {
+	switch timeSpan {
+	case ChainActivityTs30Days:
+		return time.Now().AddDate(0, 0, -30)
+	case ChainActivityTs90Days:
+		return time.Now().AddDate(0, 0, -90)
+	case ChainActivityTs1Year:
+		return time.Now().AddDate(-1, 0, 0)
+	case ChainActivityTsAllTime:
+		return time.Unix(0, 0)
+	default:
+		return time.Now().AddDate(0, 0, -7)
+	}
+}
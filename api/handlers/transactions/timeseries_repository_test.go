@@ -0,0 +1,70 @@
+package transactions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+func newVolumePoint(emitterChain, destinationChain, appID string, volume uint64, ts time.Time) *timeseries.Point {
+	return timeseries.NewPoint(vaaVolumeMeasurement).
+		AddTag("app_id", appID).
+		AddTag("emitter_chain", emitterChain).
+		AddTag("destination_chain", destinationChain).
+		AddTag("token_address", "000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2").
+		AddTag("token_chain", "2").
+		AddField("volume", volume).
+		SetTime(ts)
+}
+
+func TestTimeSeriesRepository_GetTopAssets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := timeseries.NewMemoryStore()
+	_ = store.WritePoints(ctx, timeseries.BucketInfinite,
+		newVolumePoint("2", "1", "PORTAL_TOKEN_BRIDGE", 1_5000_0000, now.Add(-time.Hour)),
+		newVolumePoint("2", "4", "PORTAL_TOKEN_BRIDGE", 2_5000_0000, now.Add(-2*time.Hour)),
+		newVolumePoint("2", "4", "PORTAL_TOKEN_BRIDGE", 9_0000_0000, now.AddDate(0, 0, -10)),
+	)
+	repo := NewTimeSeriesRepository(&Repository{}, store, zap.NewNop())
+
+	timeSpan := TimeSpan7Days
+	assets, err := repo.GetTopAssets(ctx, &timeSpan)
+	assert.NoError(t, err)
+	assert.Equal(t, []AssetDTO{{
+		EmitterChain: sdk.ChainIDEthereum,
+		TokenChain:   sdk.ChainIDEthereum,
+		TokenAddress: "000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		Volume:       "4.00000000",
+	}}, assets)
+}
+
+func TestTimeSeriesRepository_FindChainActivity(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := timeseries.NewMemoryStore()
+	_ = store.WritePoints(ctx, timeseries.BucketInfinite,
+		newVolumePoint("2", "1", "PORTAL_TOKEN_BRIDGE", 100, now.Add(-time.Hour)),
+		newVolumePoint("2", "1", "PORTAL_TOKEN_BRIDGE", 200, now.Add(-time.Hour)),
+		newVolumePoint("2", "1", "CCTP_WORMHOLE_INTEGRATION", 300, now.Add(-time.Hour)),
+		newVolumePoint("2", "65000", "PORTAL_TOKEN_BRIDGE", 400, now.Add(-time.Hour)),
+	)
+	repo := NewTimeSeriesRepository(&Repository{}, store, zap.NewNop())
+
+	result, err := repo.FindChainActivity(ctx, &ChainActivityQuery{TimeSpan: ChainActivityTs7Days})
+	assert.NoError(t, err)
+	assert.Equal(t, []ChainActivityResult{{ChainSourceID: "2", ChainDestinationID: "1", Volume: 3}}, result)
+
+	result, err = repo.FindChainActivity(ctx, &ChainActivityQuery{
+		TimeSpan:   ChainActivityTs7Days,
+		IsNotional: true,
+		AppIDs:     []string{"PORTAL_TOKEN_BRIDGE"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ChainActivityResult{{ChainSourceID: "2", ChainDestinationID: "1", Volume: 300}}, result)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
 	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/api/response"
@@ -21,16 +22,18 @@ type Service struct {
 	repo         *Repository
 	getCacheFunc cache.CacheGetFunc
 	parseVaaFunc vaaPayloadParser.ParseVaaFunc
+	emitters     *emitter.Service
 	logger       *zap.Logger
 }
 
 // NewService creates a new VAA Service.
-func NewService(r *Repository, getCacheFunc cache.CacheGetFunc, parseVaaFunc vaaPayloadParser.ParseVaaFunc, logger *zap.Logger) *Service {
+func NewService(r *Repository, getCacheFunc cache.CacheGetFunc, parseVaaFunc vaaPayloadParser.ParseVaaFunc, emitters *emitter.Service, logger *zap.Logger) *Service {
 
 	s := Service{
 		repo:         r,
 		getCacheFunc: getCacheFunc,
 		parseVaaFunc: parseVaaFunc,
+		emitters:     emitters,
 		logger:       logger.With(zap.String("module", "VaaService")),
 	}
 
@@ -80,6 +83,7 @@ func (s *Service) FindAll(
 	if err != nil {
 		return nil, err
 	}
+	s.addEmitterInfo(ctx, vaas...)
 
 	// Return the matching documents
 	res := response.Response[[]*VaaDoc]{Data: vaas}
@@ -99,11 +103,31 @@ func (s *Service) FindByChain(
 		IncludeParsedPayload(false)
 
 	vaas, err := s.repo.FindVaas(ctx, query)
+	s.addEmitterInfo(ctx, vaas...)
 
 	res := response.Response[[]*VaaDoc]{Data: vaas}
 	return &res, err
 }
 
+// FindByIDs get the VAAs matching the given ids ("chain/emitter/sequence").
+func (s *Service) FindByIDs(ctx context.Context, ids []string) ([]*VaaDoc, error) {
+	if len(ids) == 0 {
+		return []*VaaDoc{}, nil
+	}
+	p := pagination.Default().SetLimit(int64(len(ids)))
+	query := Query().
+		SetIDs(ids).
+		SetPagination(p).
+		IncludeParsedPayload(false)
+
+	vaas, err := s.repo.FindVaas(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.addEmitterInfo(ctx, vaas...)
+	return vaas, nil
+}
+
 // FindByEmitterParams contains the input parameters for the function `FindByEmitter`.
 type FindByEmitterParams struct {
 	EmitterChain         sdk.ChainID
@@ -136,6 +160,7 @@ func (s *Service) FindByEmitter(
 	} else {
 		vaas, err = s.repo.FindVaas(ctx, query)
 	}
+	s.addEmitterInfo(ctx, vaas...)
 
 	res := response.Response[[]*VaaDoc]{Data: vaas}
 	return &res, err
@@ -161,6 +186,7 @@ func (s *Service) FindById(
 	if err != nil {
 		return &response.Response[*VaaDoc]{}, err
 	}
+	s.addEmitterInfo(ctx, vaa)
 
 	// return matching documents
 	resp := response.Response[*VaaDoc]{Data: vaa}
@@ -300,3 +326,13 @@ func (s *Service) FindDuplicatedById(
 	resp := response.Response[[]*VaaDoc]{Data: vaas}
 	return &resp, err
 }
+
+// addEmitterInfo sets the emitter registry information of the given VAAs.
+func (s *Service) addEmitterInfo(ctx context.Context, vaas ...*VaaDoc) {
+	if s.emitters == nil {
+		return
+	}
+	for _, v := range vaas {
+		v.Emitter = s.emitters.Lookup(ctx, v.EmitterChain, v.EmitterAddr)
+	}
+}
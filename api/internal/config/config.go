@@ -55,6 +55,24 @@ type AppConfig struct {
 		Bucket30Days   string
 		BucketInfinite string
 	}
+	TimeSeries struct {
+		// Backend used to serve the analytics queries: influx (default) or clickhouse.
+		Backend    string
+		ClickHouse struct {
+			URL      string
+			Database string
+			User     string
+			Password string
+		}
+	}
+	Chains struct {
+		// OverridesFile is a JSON file with chain metadata that replaces or extends the embedded chain registry.
+		OverridesFile string
+	}
+	GuardianSet struct {
+		// Bundle file with the signed guardian set upgrade VAAs, used when the guardian sets are not stored yet.
+		BundleFile string
+	}
 	Coingecko struct {
 		URL       string
 		HeaderKey string
@@ -74,8 +92,43 @@ type AppConfig struct {
 		//Api Tokens
 		Tokens string
 	}
-	Protocols    []string
-	MayanBaseURL string
+	ApiKeys struct {
+		Enabled bool
+		// Token required by the admin endpoints. The admin endpoints are disabled when it is empty.
+		AdminToken string
+		// Seconds between reloads of the API keys from the database.
+		RefreshSeconds int
+	}
+	Export struct {
+		// Number of exports that run concurrently.
+		Workers int
+		// Number of exports that can wait for a worker.
+		QueueSize int
+		// Hours an export file is kept before it is deleted.
+		RetentionHours int
+		// URL of the prices API used to price the exported transfers.
+		// When it is empty the USD amounts of the analytics service are used.
+		PricesURL string
+	}
+	GraphQL struct {
+		Enabled bool
+		// Maximum depth of the selections of a query.
+		MaxDepth int
+		// Maximum cost of a query made by a client limited by IP.
+		MaxComplexity int
+		// Maximum cost of a query made with an API key or a static api token.
+		MaxComplexityApiKey int
+		// Maximum length of a query in bytes.
+		MaxQueryLength int
+		// JSON file that maps the sha256 hash of each persisted query to the query.
+		PersistedQueriesFile string
+		// Reject the queries that are not in the persisted queries file.
+		PersistedQueriesOnly bool
+	}
+	Protocols []string
+	// AdapterProtocols are the protocols of Protocols defined with an adapter in the protocols stats job.
+	AdapterProtocols []string
+	MayanBaseURL     string
 }
 
 // GetLogLevel get zapcore.Level define in the configuraion.
@@ -106,7 +159,19 @@ func init() {
 	viper.SetDefault("runmode", "PRODUCTION")
 	viper.SetDefault("p2pnetwork", P2pMainNet)
 	viper.SetDefault("PprofEnabled", false)
+	viper.SetDefault("Chains_OverridesFile", "")
+	viper.SetDefault("GuardianSet_BundleFile", "")
 	viper.SetDefault("RateLimit_Enabled", true)
+	viper.SetDefault("Export_Workers", 2)
+	viper.SetDefault("Export_QueueSize", 20)
+	viper.SetDefault("Export_RetentionHours", 24)
+	viper.SetDefault("GraphQL_Enabled", true)
+	viper.SetDefault("GraphQL_MaxDepth", 8)
+	viper.SetDefault("GraphQL_MaxComplexity", 1000)
+	viper.SetDefault("GraphQL_MaxComplexityApiKey", 10000)
+	viper.SetDefault("GraphQL_MaxQueryLength", 10000)
+	viper.SetDefault("GraphQL_PersistedQueriesFile", "")
+	viper.SetDefault("GraphQL_PersistedQueriesOnly", false)
 
 	// Consider environment variables in unmarshall doesn't work unless doing this: https://github.com/spf13/viper/issues/188#issuecomment-1168898503
 	b, err := json.Marshal(defaulConfig())
@@ -141,3 +206,14 @@ func Get() (*AppConfig, error) {
 func (c *AppConfig) GetApiTokens() []string {
 	return strings.Split(c.RateLimit.Tokens, ",")
 }
+
+// GetApiTokensMap returns the static api tokens as a set. Empty tokens are ignored.
+func (c *AppConfig) GetApiTokensMap() map[string]bool {
+	tokens := make(map[string]bool)
+	for _, token := range c.GetApiTokens() {
+		if token != "" {
+			tokens[token] = true
+		}
+	}
+	return tokens
+}
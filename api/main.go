@@ -27,7 +27,14 @@ import (
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	gqlapi "github.com/wormhole-foundation/wormhole-explorer/api/graphql"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/address"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/apikey"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/eta"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/export"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/fees"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governance"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
 	guardianHandlers "github.com/wormhole-foundation/wormhole-explorer/api/handlers/guardian"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
@@ -43,6 +50,8 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/tvl"
 	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
 	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/admin"
+	graphqlRoutes "github.com/wormhole-foundation/wormhole-explorer/api/routes/graphql"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/guardian"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan"
 	rpcApi "github.com/wormhole-foundation/wormhole-explorer/api/rpc"
@@ -52,10 +61,14 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbutil"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	xlogger "github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/observation"
+	"github.com/wormhole-foundation/wormhole-explorer/common/prices"
 	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
 	stats2 "github.com/wormhole-foundation/wormhole-explorer/common/stats"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
 	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
 
@@ -115,6 +128,11 @@ func main() {
 	rootLogger := xlogger.New("wormhole-api", xlogger.WithLevel(cfg.LogLevel))
 	defer rootLogger.Sync()
 
+	// Apply the chain registry overrides
+	if err := domain.Chains.LoadOverrides(cfg.Chains.OverridesFile); err != nil {
+		rootLogger.Fatal("failed to load chain overrides", zap.Error(err))
+	}
+
 	// Setup DB
 	rootLogger.Info("connecting to MongoDB")
 	db, err := dbutil.Connect(appCtx, rootLogger, cfg.DB.URL, cfg.DB.Name, false)
@@ -141,6 +159,12 @@ func main() {
 	rootLogger.Info("initializing InfluxDB client")
 	influxCli := newInfluxClient(cfg.Influx.URL, cfg.Influx.Token)
 
+	// Time-series reader for the analytics queries
+	tsReader, err := newTimeSeriesReader(cfg)
+	if err != nil {
+		rootLogger.Fatal("failed to initialize time-series reader", zap.Error(err))
+	}
+
 	//VaaPayloadParser client
 	vaaParserFunc, err := NewVaaParserFunc(cfg, rootLogger)
 	if err != nil {
@@ -171,7 +195,9 @@ func main() {
 		cfg.MayanBaseURL,
 	)
 	relaysRepo := relays.NewRepository(db.Database, rootLogger)
+	governanceRepo := governance.NewRepository(db.Database, rootLogger)
 	operationsRepo := operations.NewRepository(db.Database, rootLogger)
+	emitterRepo := emitter.NewRepository(db.Database, rootLogger)
 	nttRepo := stats2.NewNTTRepository(
 		influxCli,
 		cfg.Influx.Organization,
@@ -202,6 +228,10 @@ func main() {
 		cfg.Influx.Bucket24Hours,
 		rootLogger)
 	guardianSetRepository := repository.NewGuardianSetRepository(db.Database, rootLogger)
+	exportRepo, err := export.NewRepository(db.Database, rootLogger)
+	if err != nil {
+		rootLogger.Fatal("failed to initialize export repository", zap.Error(err))
+	}
 
 	metrics := metrics.NewPrometheusMetrics(cfg.Environment)
 
@@ -209,18 +239,48 @@ func main() {
 	rootLogger.Info("initializing services")
 	expirationTime := time.Duration(cfg.Cache.MetricExpiration) * time.Minute
 	addressService := address.NewService(addressRepo, rootLogger)
-	vaaService := vaa.NewService(vaaRepo, cache.Get, vaaParserFunc, rootLogger)
-	obsService := observations.NewService(obsRepo, rootLogger)
+	emitterService := emitter.NewService(emitterRepo, 5*time.Minute, rootLogger)
+	if err := emitterService.Seed(appCtx, domain.GetKnownEmitters(cfg.P2pNetwork)); err != nil {
+		rootLogger.Error("failed to seed emitter registry", zap.Error(err))
+	}
+	vaaService := vaa.NewService(vaaRepo, cache.Get, vaaParserFunc, emitterService, rootLogger)
+	obsService := observations.NewService(obsRepo, observation.NewRepository(db.Database, rootLogger), rootLogger)
 	governorService := governor.NewService(governorRepo, cache, metrics, rootLogger)
 	infrastructureService := infrastructure.NewService(infrastructureRepo, rootLogger)
 	heartbeatsService := heartbeats.NewService(heartbeatsRepo, rootLogger)
-	transactionsService := transactions.NewService(transactionsRepo, cache, expirationTime, tokenProvider, metrics, rootLogger)
 	relaysService := relays.NewService(relaysRepo, rootLogger)
-	operationsService := operations.NewService(operationsRepo, rootLogger)
-	statsService := stats.NewService(statsRepo, statsAddressRepo, statsHolderRepo, cache, expirationTime, metrics, rootLogger)
-	protocolsService := protocols.NewService(cfg.Protocols, protocolsRepo, rootLogger, cache, cfg.Cache.ProtocolsStatsKey, cfg.Cache.ProtocolsStatsExpiration, metrics, tvl)
-	guardianService := guardianHandlers.NewService(guardianSetRepository, cfg.P2pNetwork, cache, metrics, rootLogger)
+	governanceService := governance.NewService(governanceRepo, rootLogger)
+	measurementReader := newMeasurementReader(tsReader, influxCli, cfg)
+	etaService := eta.NewService(measurementReader, 10*time.Minute, rootLogger)
+	feesService := fees.NewService(measurementReader, rootLogger)
+	operationsService := operations.NewService(operationsRepo, emitterService, etaService, rootLogger)
+
+	// The analytics queries are served by the InfluxDB repositories unless another
+	// time-series backend is configured.
+	var transactionsService *transactions.Service
+	var statsService *stats.Service
+	if tsReader == nil {
+		transactionsService = transactions.NewService(transactionsRepo, cache, expirationTime, tokenProvider, metrics, rootLogger)
+		statsService = stats.NewService(statsRepo, statsAddressRepo, statsHolderRepo, cache, expirationTime, metrics, rootLogger)
+	} else {
+		tsTransactionsRepo := transactions.NewTimeSeriesRepository(transactionsRepo, tsReader, rootLogger)
+		transactionsService = transactions.NewService(tsTransactionsRepo, cache, expirationTime, tokenProvider, metrics, rootLogger)
+		tsStatsRepo := stats.NewTimeSeriesRepository(statsRepo, tsReader, rootLogger)
+		statsService = stats.NewService(tsStatsRepo, statsAddressRepo, statsHolderRepo, cache, expirationTime, metrics, rootLogger)
+	}
+	protocolsService := protocols.NewService(cfg.Protocols, cfg.AdapterProtocols, protocolsRepo, rootLogger, cache, cfg.Cache.ProtocolsStatsKey, cfg.Cache.ProtocolsStatsExpiration, metrics, tvl)
+	guardianService := guardianHandlers.NewService(guardianSetRepository, cfg.P2pNetwork, cfg.GuardianSet.BundleFile, cache, metrics, rootLogger)
 	supplyService := supply.NewService(rootLogger)
+	var getPriceByTime export.GetPriceByTimeFn
+	if cfg.Export.PricesURL != "" {
+		getPriceByTime = prices.NewPricesApi(cfg.Export.PricesURL, rootLogger).GetPriceByTime
+	}
+	exportService := export.NewService(exportRepo, tokenProvider, getPriceByTime, export.Config{
+		Workers:   cfg.Export.Workers,
+		QueueSize: cfg.Export.QueueSize,
+		Retention: time.Duration(cfg.Export.RetentionHours) * time.Hour,
+	}, rootLogger)
+	exportService.Start(appCtx)
 
 	// Set up a custom error handler
 	response.SetEnableStackTrace(*cfg)
@@ -249,6 +309,16 @@ func main() {
 	}
 	app.Use(cors.New())
 
+	// Configure api keys, they must be checked before the rate limiter.
+	var apiKeyService *apikey.Service
+	if cfg.ApiKeys.Enabled {
+		apiKeyService, err = NewApiKeyService(appCtx, cfg, db.Database, rootLogger)
+		if err != nil {
+			rootLogger.Fatal("failed to initialize api keys", zap.Error(err))
+		}
+		app.Use(middleware.ApiKeyLimiter(apiKeyService, cfg.GetApiTokensMap(), rootLogger))
+	}
+
 	// Configure rate limiter
 	if cfg.RateLimit.Enabled {
 		rl, err := NewRateLimiter(appCtx, cfg, rootLogger)
@@ -261,8 +331,19 @@ func main() {
 	notSupportedByEnv := middleware.NotSupportedByTestnetEnv(cfg.P2pNetwork)
 	// Set up route handlers
 	app.Get("/swagger.json", GetSwagger)
-	wormscan.RegisterRoutes(notSupportedByEnv, app, rootLogger, addressService, vaaService, obsService, governorService, infrastructureService, transactionsService, relaysService, operationsService, statsService, protocolsService, supplyService)
+	wormscan.RegisterRoutes(notSupportedByEnv, app, rootLogger, addressService, vaaService, obsService, governorService, infrastructureService, transactionsService, relaysService, operationsService, statsService, protocolsService, supplyService, exportService, governanceService, emitterService, etaService, feesService, cfg.P2pNetwork)
 	guardian.RegisterRoutes(cfg, app, rootLogger, vaaService, governorService, heartbeatsService, guardianService)
+	if cfg.GraphQL.Enabled {
+		resolver := gqlapi.NewResolver(operationsService, vaaService, relaysService, transactionsService, governorService, rootLogger)
+		gqlHandler, err := NewGraphQLHandler(cfg, resolver, cache, rootLogger)
+		if err != nil {
+			rootLogger.Fatal("failed to initialize graphql handler", zap.Error(err))
+		}
+		graphqlRoutes.RegisterRoutes(app, gqlHandler)
+	}
+	if cfg.ApiKeys.AdminToken != "" {
+		admin.RegisterRoutes(app, cfg.ApiKeys.AdminToken, rootLogger, apiKeyService, emitterService)
+	}
 
 	// Set up gRPC handlers
 	handler := rpcApi.NewHandler(vaaService, heartbeatsService, governorService, guardianService, rootLogger)
@@ -332,10 +413,61 @@ func NewCache(ctx context.Context, cfg *config.AppConfig, logger *zap.Logger) (w
 	return cacheClient, nil
 }
 
+// NewApiKeyService creates the api key service and loads the keys.
+func NewApiKeyService(ctx context.Context, cfg *config.AppConfig, db *mongo.Database, logger *zap.Logger) (*apikey.Service, error) {
+
+	prefix := cfg.RateLimit.Prefix
+	if prefix != "" {
+		prefix += ":"
+	}
+	redisClient := redis.NewClient(&redis.Options{Addr: cfg.Cache.URL})
+	usage := apikey.NewRedisUsageStore(redisClient, prefix)
+
+	repo := apikey.NewRepository(db, logger)
+	refreshInterval := time.Duration(cfg.ApiKeys.RefreshSeconds) * time.Second
+	srv := apikey.NewService(repo, usage, refreshInterval, logger)
+	if err := srv.Start(ctx); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
 func newInfluxClient(url, token string) influxdb2.Client {
 	return influxdb2.NewClient(url, token)
 }
 
+// newTimeSeriesReader returns the reader for the configured time-series backend,
+// or nil when the analytics queries are served by the InfluxDB repositories.
+func newTimeSeriesReader(cfg *config.AppConfig) (timeseries.Reader, error) {
+	switch cfg.TimeSeries.Backend {
+	case "", timeseries.BackendInflux:
+		return nil, nil
+	case timeseries.BackendClickHouse:
+		return timeseries.NewClickHouseStore(timeseries.ClickHouseConfig{
+			URL:      cfg.TimeSeries.ClickHouse.URL,
+			Database: cfg.TimeSeries.ClickHouse.Database,
+			User:     cfg.TimeSeries.ClickHouse.User,
+			Password: cfg.TimeSeries.ClickHouse.Password,
+		}), nil
+	case timeseries.BackendMemory:
+		return nil, fmt.Errorf("unsupported time-series backend: %s, the memory store only serves the points written by the same process", cfg.TimeSeries.Backend)
+	default:
+		return nil, fmt.Errorf("unsupported time-series backend: %s", cfg.TimeSeries.Backend)
+	}
+}
+
+// newMeasurementReader returns the reader of the transfer latency and transaction fees measurements.
+// Unlike the other analytics queries, they are always served through a time-series store.
+func newMeasurementReader(tsReader timeseries.Reader, influxCli influxdb2.Client, cfg *config.AppConfig) timeseries.Reader {
+	if tsReader != nil {
+		return tsReader
+	}
+	return timeseries.NewInfluxStore(influxCli, cfg.Influx.Organization, map[timeseries.Bucket]string{
+		timeseries.Bucket30Days:   cfg.Influx.Bucket30Days,
+		timeseries.BucketInfinite: cfg.Influx.BucketInfinite,
+	})
+}
+
 func NewRateLimiter(ctx context.Context, cfg *config.AppConfig, logger *zap.Logger) (func(*fiber.Ctx) error, error) {
 
 	if cfg.RateLimit.Prefix != "" {
@@ -344,13 +476,8 @@ func NewRateLimiter(ctx context.Context, cfg *config.AppConfig, logger *zap.Logg
 		cfg.RateLimit.Prefix = "rate-limiter:"
 	}
 
-	enableApiTokens := len(cfg.GetApiTokens()) > 0
-	enableByApiToken := make(map[string]bool)
-	if enableApiTokens {
-		for _, token := range cfg.GetApiTokens() {
-			enableByApiToken[token] = true
-		}
-	}
+	enableByApiToken := cfg.GetApiTokensMap()
+	enableApiTokens := len(enableByApiToken) > 0
 
 	// initialize rate limiter
 	store, err := frs.New(
@@ -372,6 +499,10 @@ func NewRateLimiter(ctx context.Context, cfg *config.AppConfig, logger *zap.Logg
 
 	router := limiter.New(limiter.Config{
 		Next: func(c *fiber.Ctx) bool {
+			// requests made with an api key are limited by the quotas of its tier.
+			if middleware.IsApiKeyAuthenticated(c) {
+				return true
+			}
 			if enableApiTokens {
 				apiKey := c.Get("X-API-KEY")
 				if apiKey != "" {
@@ -397,6 +528,21 @@ func NewRateLimiter(ctx context.Context, cfg *config.AppConfig, logger *zap.Logg
 
 }
 
+// NewGraphQLHandler creates the handler of the GraphQL endpoint.
+func NewGraphQLHandler(cfg *config.AppConfig, resolver *gqlapi.Resolver, cache wormscanCache.Cache, logger *zap.Logger) (*gqlapi.Handler, error) {
+	persisted, err := gqlapi.NewPersistedQueries(cache, cfg.GraphQL.PersistedQueriesFile, cfg.GraphQL.PersistedQueriesOnly)
+	if err != nil {
+		return nil, err
+	}
+	gqlCfg := gqlapi.Config{
+		MaxDepth:            cfg.GraphQL.MaxDepth,
+		MaxComplexity:       cfg.GraphQL.MaxComplexity,
+		MaxComplexityApiKey: cfg.GraphQL.MaxComplexityApiKey,
+		MaxQueryLength:      cfg.GraphQL.MaxQueryLength,
+	}
+	return gqlapi.NewHandler(resolver, persisted, gqlCfg, cfg.GetApiTokensMap(), logger)
+}
+
 // NewVaaParserFunc returns a function to parse VAA payload.
 func NewVaaParserFunc(cfg *config.AppConfig, logger *zap.Logger) (vaaPayloadParser.ParseVaaFunc, error) {
 	if cfg.RunMode == config.RunModeDevelopmernt && !cfg.VaaPayloadParser.Enabled {
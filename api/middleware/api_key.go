@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/apikey"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"go.uber.org/zap"
+)
+
+const (
+	// ApiKeyHeader is the header that carries the API key.
+	ApiKeyHeader = "X-API-KEY"
+	// AdminTokenHeader is the header that carries the token of the admin endpoints.
+	AdminTokenHeader = "X-ADMIN-TOKEN"
+
+	apiKeyLocal = "apiKeyId"
+)
+
+// ApiKeyLimiter authorizes the requests made with an API key and enforces the quotas of its tier.
+//
+// Requests without a key, or with one of the static tokens, are left to the IP rate limiter.
+func ApiKeyLimiter(srv *apikey.Service, staticTokens map[string]bool, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		secret := c.Get(ApiKeyHeader)
+		if secret == "" || staticTokens[secret] || IsK8sPath(c.Path()) {
+			return c.Next()
+		}
+
+		decision, err := srv.Authorize(c.Context(), secret, RouteGroup(c.Path()))
+		if decision != nil {
+			setRateLimitHeaders(c, decision)
+		}
+		switch {
+		case errors.Is(err, apikey.ErrInvalidKey):
+			return response.NewApiError(c, fiber.StatusUnauthorized, response.Unauthenticated, "INVALID API KEY", err)
+		case errors.Is(err, apikey.ErrRouteNotAllowed), errors.Is(err, apikey.ErrInvalidTier):
+			return response.NewApiError(c, fiber.StatusForbidden, response.PermissionDenied, "ROUTE NOT ALLOWED FOR API KEY", err)
+		case errors.Is(err, apikey.ErrQuotaExceeded):
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(decision.RetryAfter.Seconds())))
+			return response.NewApiError(c, fiber.StatusTooManyRequests, response.ResourceExhausted, "API KEY QUOTA EXCEEDED", err)
+		case err != nil:
+			logger.Error("failed to authorize api key", zap.Error(err))
+			return err
+		}
+
+		c.Locals(apiKeyLocal, decision.Key.ID)
+		return c.Next()
+	}
+}
+
+// IsApiKeyAuthenticated returns true if the request was authorized by ApiKeyLimiter.
+func IsApiKeyAuthenticated(c *fiber.Ctx) bool {
+	return c.Locals(apiKeyLocal) != nil
+}
+
+// RouteGroup returns the route group of a path: the first segment after the API version
+// (e.g.: /api/v1/operations/:id -> operations). The guardian API routes belong to the
+// "guardian" group.
+func RouteGroup(path string) string {
+	if rest, ok := strings.CutPrefix(path, "/api/v1/"); ok {
+		group, _, _ := strings.Cut(rest, "/")
+		return group
+	}
+	if strings.HasPrefix(path, "/v1/") {
+		return "guardian"
+	}
+	return ""
+}
+
+// setRateLimitHeaders sets the X-RateLimit-* headers of the per-minute window and,
+// when the tier has a daily quota, of the daily window.
+func setRateLimitHeaders(c *fiber.Ctx, d *apikey.Decision) {
+	if d.Tier.PerMinute > 0 {
+		c.Set("X-RateLimit-Limit", strconv.FormatInt(d.Tier.PerMinute, 10))
+		c.Set("X-RateLimit-Remaining", strconv.FormatInt(max(d.Tier.PerMinute-d.Usage.Minute, 0), 10))
+		c.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(time.Until(d.Reset).Seconds())))
+	}
+	if d.Tier.Daily > 0 {
+		c.Set("X-RateLimit-Limit-Day", strconv.FormatInt(d.Tier.Daily, 10))
+		c.Set("X-RateLimit-Remaining-Day", strconv.FormatInt(max(d.Tier.Daily-d.Usage.Day, 0), 10))
+	}
+}
+
+func ceilSeconds(s float64) int {
+	n := int(s)
+	if float64(n) < s {
+		n++
+	}
+	return n
+}
+
+// AdminAuth allows the request only when it carries the admin token.
+func AdminAuth(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		got := c.Get(AdminTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return response.NewApiError(c, fiber.StatusUnauthorized, response.Unauthenticated, "UNAUTHORIZED", nil)
+		}
+		return c.Next()
+	}
+}
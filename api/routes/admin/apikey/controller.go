@@ -0,0 +1,206 @@
+package apikey
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/apikey"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"go.uber.org/zap"
+)
+
+// Controller is the controller for the API key admin resources.
+type Controller struct {
+	srv    *apikey.Service
+	logger *zap.Logger
+}
+
+// NewController creates a new controller.
+func NewController(srv *apikey.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "ApiKeyController")),
+	}
+}
+
+// CreateKeyRequest is the body of a new API key.
+type CreateKeyRequest struct {
+	Name string `json:"name"`
+	Tier string `json:"tier"`
+}
+
+// ListTiers godoc
+// @Description Returns the API key tiers.
+// @Tags admin
+// @ID admin-list-api-key-tiers
+// @Success 200 {object} []apikey.Tier
+// @Failure 401
+// @Failure 500
+// @Router /api/v1/admin/api-key-tiers [get]
+func (c *Controller) ListTiers(ctx *fiber.Ctx) error {
+	tiers, err := c.srv.ListTiers(ctx.Context())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(tiers)
+}
+
+// UpsertTier godoc
+// @Description Creates or replaces an API key tier. The changes apply to the existing keys of the tier.
+// @Tags admin
+// @ID admin-upsert-api-key-tier
+// @Param name path string true "name of the tier"
+// @Param request body apikey.Tier true "tier"
+// @Success 200 {object} apikey.Tier
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Router /api/v1/admin/api-key-tiers/:name [put]
+func (c *Controller) UpsertTier(ctx *fiber.Ctx) error {
+	var tier apikey.Tier
+	if err := ctx.BodyParser(&tier); err != nil {
+		return response.NewRequestBodyError(ctx, "invalid tier, unable to parse", err)
+	}
+	tier.Name = ctx.Params("name")
+	err := c.srv.UpsertTier(ctx.Context(), &tier)
+	if errors.Is(err, apikey.ErrInvalidTier) {
+		return response.NewRequestBodyError(ctx, err.Error(), err)
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(tier)
+}
+
+// ListKeys godoc
+// @Description Returns the API keys.
+// @Tags admin
+// @ID admin-list-api-keys
+// @Success 200 {object} []apikey.APIKey
+// @Failure 401
+// @Failure 500
+// @Router /api/v1/admin/api-keys [get]
+func (c *Controller) ListKeys(ctx *fiber.Ctx) error {
+	keys, err := c.srv.ListKeys(ctx.Context())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(keys)
+}
+
+// FindKeyById godoc
+// @Description Returns an API key.
+// @Tags admin
+// @ID admin-find-api-key-by-id
+// @Param id path string true "id of the API key"
+// @Success 200 {object} apikey.APIKey
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/admin/api-keys/:id [get]
+func (c *Controller) FindKeyById(ctx *fiber.Ctx) error {
+	key, err := c.srv.GetKey(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(key)
+}
+
+// CreateKey godoc
+// @Description Creates a new API key. The key is only returned in this response.
+// @Tags admin
+// @ID admin-create-api-key
+// @Param request body CreateKeyRequest true "API key"
+// @Success 201 {object} apikey.NewAPIKey
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Router /api/v1/admin/api-keys [post]
+func (c *Controller) CreateKey(ctx *fiber.Ctx) error {
+	var body CreateKeyRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		return response.NewRequestBodyError(ctx, "invalid api key request, unable to parse", err)
+	}
+	if body.Name == "" {
+		return response.NewRequestBodyError(ctx, "invalid api key request, name is empty", nil)
+	}
+	key, err := c.srv.CreateKey(ctx.Context(), body.Name, body.Tier)
+	if errors.Is(err, apikey.ErrInvalidTier) {
+		return response.NewRequestBodyError(ctx, err.Error(), err)
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(key)
+}
+
+// RotateKey godoc
+// @Description Replaces the secret of an API key. The previous secret is still accepted during the grace period.
+// @Tags admin
+// @ID admin-rotate-api-key
+// @Param id path string true "id of the API key"
+// @Param graceMinutes query integer false "Minutes the previous secret is still accepted. Default: 0."
+// @Success 200 {object} apikey.NewAPIKey
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 409
+// @Failure 500
+// @Router /api/v1/admin/api-keys/:id/rotate [post]
+func (c *Controller) RotateKey(ctx *fiber.Ctx) error {
+	graceMinutes, err := strconv.Atoi(ctx.Query("graceMinutes", "0"))
+	if err != nil || graceMinutes < 0 {
+		return response.NewInvalidQueryParamError(ctx, "INVALID GRACE_MINUTES VALUE", err)
+	}
+	key, err := c.srv.RotateKey(ctx.Context(), ctx.Params("id"), time.Duration(graceMinutes)*time.Minute)
+	if errors.Is(err, apikey.ErrInvalidKey) {
+		return response.NewApiError(ctx, fiber.StatusConflict, response.FailedPrecondition, "API KEY IS REVOKED", err)
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(key)
+}
+
+// RevokeKey godoc
+// @Description Revokes an API key.
+// @Tags admin
+// @ID admin-revoke-api-key
+// @Param id path string true "id of the API key"
+// @Success 204
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/admin/api-keys/:id [delete]
+func (c *Controller) RevokeKey(ctx *fiber.Ctx) error {
+	if err := c.srv.RevokeKey(ctx.Context(), ctx.Params("id")); err != nil {
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// GetUsage godoc
+// @Description Returns the number of requests of an API key in the current minute and the last days.
+// @Tags admin
+// @ID admin-get-api-key-usage
+// @Param id path string true "id of the API key"
+// @Param days query integer false "Number of days. Default and maximum: 31."
+// @Success 200 {object} apikey.UsageReport
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/admin/api-keys/:id/usage [get]
+func (c *Controller) GetUsage(ctx *fiber.Ctx) error {
+	days, err := strconv.Atoi(ctx.Query("days", "0"))
+	if err != nil {
+		return response.NewInvalidQueryParamError(ctx, "INVALID DAYS VALUE", err)
+	}
+	usage, err := c.srv.GetUsage(ctx.Context(), ctx.Params("id"), days)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(usage)
+}
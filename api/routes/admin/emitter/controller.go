@@ -0,0 +1,92 @@
+package emitter
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"go.uber.org/zap"
+)
+
+// Controller is the controller for the emitter registry admin resources.
+type Controller struct {
+	srv    *emitter.Service
+	logger *zap.Logger
+}
+
+// NewController creates a new controller.
+func NewController(srv *emitter.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "EmitterAdminController")),
+	}
+}
+
+// UpsertEmitterRequest is the body of an emitter of the registry.
+type UpsertEmitterRequest struct {
+	AppID         string `json:"appId"`
+	Name          string `json:"name"`
+	Owner         string `json:"owner"`
+	NativeAddress string `json:"nativeAddress"`
+}
+
+// UpsertEmitter godoc
+// @Description Creates or replaces an emitter of the registry.
+// @Tags admin
+// @ID admin-upsert-emitter
+// @Param chain path integer true "Emitter chain."
+// @Param emitter path string true "Emitter address."
+// @Param request body UpsertEmitterRequest true "emitter"
+// @Success 200 {object} emitter.EmitterDoc
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Router /api/v1/admin/emitters/{chain}/{emitter} [put]
+func (c *Controller) UpsertEmitter(ctx *fiber.Ctx) error {
+	chainID, addr, err := middleware.ExtractVAAChainIDEmitter(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	var req UpsertEmitterRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.NewRequestBodyError(ctx, "invalid emitter, unable to parse", err)
+	}
+	e, err := c.srv.Upsert(ctx.Context(), chainID, addr.Hex(), &emitter.EmitterDoc{
+		AppID:         req.AppID,
+		Name:          req.Name,
+		Owner:         req.Owner,
+		NativeAddress: req.NativeAddress,
+	})
+	if errors.Is(err, emitter.ErrInvalidEmitter) {
+		return response.NewRequestBodyError(ctx, err.Error(), err)
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(e)
+}
+
+// DeleteEmitter godoc
+// @Description Removes an emitter from the registry.
+// @Tags admin
+// @ID admin-delete-emitter
+// @Param chain path integer true "Emitter chain."
+// @Param emitter path string true "Emitter address."
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/admin/emitters/{chain}/{emitter} [delete]
+func (c *Controller) DeleteEmitter(ctx *fiber.Ctx) error {
+	chainID, addr, err := middleware.ExtractVAAChainIDEmitter(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	if err := c.srv.Delete(ctx.Context(), chainID, addr.Hex()); err != nil {
+		return err
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"github.com/gofiber/fiber/v2"
+	apikeysvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/apikey"
+	emittersvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/admin/apikey"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/admin/emitter"
+	"go.uber.org/zap"
+)
+
+// RegisterRoutes sets up the handlers for the admin API. Every route requires the admin token.
+func RegisterRoutes(
+	app *fiber.App,
+	adminToken string,
+	rootLogger *zap.Logger,
+	apiKeyService *apikeysvc.Service,
+	emitterService *emittersvc.Service,
+) {
+
+	// Set up route handlers
+	api := app.Group("/api/v1/admin", middleware.AdminAuth(adminToken))
+
+	// emitter registry
+	emitterCtrl := emitter.NewController(emitterService, rootLogger)
+	api.Put("/emitters/:chain/:emitter", emitterCtrl.UpsertEmitter)
+	api.Delete("/emitters/:chain/:emitter", emitterCtrl.DeleteEmitter)
+
+	// the api key resources are only available when api keys are enabled
+	if apiKeyService == nil {
+		return
+	}
+	apiKeyCtrl := apikey.NewController(apiKeyService, rootLogger)
+
+	// api key tiers
+	api.Get("/api-key-tiers", apiKeyCtrl.ListTiers)
+	api.Put("/api-key-tiers/:name", apiKeyCtrl.UpsertTier)
+
+	// api keys
+	api.Get("/api-keys", apiKeyCtrl.ListKeys)
+	api.Post("/api-keys", apiKeyCtrl.CreateKey)
+	api.Get("/api-keys/:id", apiKeyCtrl.FindKeyById)
+	api.Delete("/api-keys/:id", apiKeyCtrl.RevokeKey)
+	api.Post("/api-keys/:id/rotate", apiKeyCtrl.RotateKey)
+	api.Get("/api-keys/:id/usage", apiKeyCtrl.GetUsage)
+}
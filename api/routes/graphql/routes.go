@@ -0,0 +1,12 @@
+package graphql
+
+import (
+	"github.com/gofiber/fiber/v2"
+	gqlapi "github.com/wormhole-foundation/wormhole-explorer/api/graphql"
+)
+
+// RegisterRoutes sets up the GraphQL endpoint. GET requests allow persisted queries to be cached by CDNs.
+func RegisterRoutes(app *fiber.App, handler *gqlapi.Handler) {
+	app.Get("/api/v1/graphql", handler.Serve)
+	app.Post("/api/v1/graphql", handler.Serve)
+}
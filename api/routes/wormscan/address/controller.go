@@ -61,3 +61,64 @@ func (c *Controller) FindById(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(response)
 }
+
+// GetStats godoc
+// @Description Returns the aggregated transfer activity of an address: volume sent and received
+// @Description by chain and token, counterparties, protocols, first and last activity and
+// @Description the number of transfers pending redemption.
+// @Tags wormholescan
+// @ID get-address-stats
+// @Param id path string true "address"
+// @Success 200 {object} address.AddressStats
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/address/:id/stats [get]
+func (c *Controller) GetStats(ctx *fiber.Ctx) error {
+
+	address := middleware.ExtractAddressFromPath(ctx, c.logger)
+
+	stats, err := c.srv.GetAddressStats(ctx.Context(), address)
+	if err != nil {
+		return err
+	}
+	if stats.SentCount == 0 && stats.ReceivedCount == 0 {
+		return errors.ErrNotFound
+	}
+
+	return ctx.JSON(stats)
+}
+
+// GetLinks godoc
+// @Description Returns the linked identity of an address: every known representation of the address
+// @Description across chain encodings and the addresses it has sent transfers to or received transfers from.
+// @Tags wormholescan
+// @ID get-address-links
+// @Param id path string true "address"
+// @Param page query integer false "Page number. Starts at 0."
+// @Param pageSize query integer false "Number of elements per page."
+// @Success 200 {object} address.AddressGraph
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/address/:id/links [get]
+func (c *Controller) GetLinks(ctx *fiber.Ctx) error {
+
+	address := middleware.ExtractAddressFromPath(ctx, c.logger)
+
+	pagination, err := middleware.ExtractPagination(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Check pagination max limit
+	if pagination.Limit > 1000 {
+		return response.NewInvalidParamError(ctx, "pageSize cannot be greater than 1000", nil)
+	}
+
+	graph, err := c.srv.GetAddressGraph(ctx.Context(), address, pagination)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(graph)
+}
@@ -0,0 +1,88 @@
+package chains
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	p2pNetwork string
+	logger     *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(p2pNetwork string, logger *zap.Logger) *Controller {
+	return &Controller{
+		p2pNetwork: p2pNetwork,
+		logger:     logger.With(zap.String("module", "ChainsController")),
+	}
+}
+
+// ChainResponse is the metadata of a chain in the configured network.
+type ChainResponse struct {
+	ID           uint16               `json:"id"`
+	Name         string               `json:"name"`
+	Family       domain.ChainFamily   `json:"family"`
+	FinalityTime int64                `json:"finalityTime"`
+	NativeToken  *domain.NativeToken  `json:"nativeToken,omitempty"`
+	AddressCodec domain.AddressCodec  `json:"addressCodec"`
+	TxHashCodec  string               `json:"txHashCodec"`
+	Explorer     *domain.ExplorerURLs `json:"explorer,omitempty"`
+}
+
+func (c *Controller) toResponse(chain *domain.ChainInfo) ChainResponse {
+	r := ChainResponse{
+		ID:           uint16(chain.ID),
+		Name:         chain.Name,
+		Family:       chain.Family,
+		FinalityTime: int64(chain.Finality().Seconds()),
+		NativeToken:  chain.NativeToken,
+		AddressCodec: chain.AddressCodec,
+		TxHashCodec:  chain.TxHashCodec,
+	}
+	if e, ok := chain.Explorer[c.p2pNetwork]; ok {
+		r.Explorer = &e
+	}
+	return r
+}
+
+// FindAll godoc
+// @Description Returns the metadata of the chains: name, family, finality time in seconds, native token,
+// @Description address and tx hash encoding, and the explorer URL templates of the network.
+// @Tags wormholescan
+// @ID find-chains
+// @Success 200 {object} []ChainResponse
+// @Router /api/v1/chains [get]
+func (c *Controller) FindAll(ctx *fiber.Ctx) error {
+	all := domain.Chains.All()
+	chains := make([]ChainResponse, 0, len(all))
+	for _, chain := range all {
+		chains = append(chains, c.toResponse(chain))
+	}
+	return ctx.JSON(chains)
+}
+
+// FindOne godoc
+// @Description Returns the metadata of a chain.
+// @Tags wormholescan
+// @ID find-chain
+// @Param chain path integer true "id of the chain"
+// @Success 200 {object} ChainResponse
+// @Failure 400
+// @Failure 404
+// @Router /api/v1/chains/{chain} [get]
+func (c *Controller) FindOne(ctx *fiber.Ctx) error {
+	chainID, err := middleware.ExtractChainID(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	chain, ok := domain.Chains.Get(chainID)
+	if !ok {
+		return response.NewNotFoundError(ctx)
+	}
+	return ctx.JSON(c.toResponse(chain))
+}
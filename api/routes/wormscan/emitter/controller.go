@@ -0,0 +1,87 @@
+package emitter
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *emitter.Service
+	logger *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(srv *emitter.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "EmitterController")),
+	}
+}
+
+// FindAll godoc
+// @Description Returns the emitters of the registry with their protocol, contract name, native address, owner and message count.
+// @Tags wormholescan
+// @ID find-emitters
+// @Param chain query integer false "Emitter chain."
+// @Param appId query string false "Protocol of the emitter."
+// @Param page query integer false "Page number."
+// @Param pageSize query integer false "Number of elements per page."
+// @Success 200 {object} []emitter.EmitterDoc
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/emitters [get]
+func (c *Controller) FindAll(ctx *fiber.Ctx) error {
+	p, err := middleware.ExtractPagination(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Limit > 1000 {
+		return response.NewInvalidParamError(ctx, "pageSize cannot be greater than 1000", nil)
+	}
+
+	q := &emitter.EmitterQuery{Pagination: p, AppID: ctx.Query("appId")}
+	if v := ctx.Query("chain"); v != "" {
+		chain, err := strconv.ParseUint(v, 10, 16)
+		if err != nil {
+			return response.NewInvalidQueryParamError(ctx, "INVALID <chain> QUERY PARAMETER", nil)
+		}
+		chainID := sdk.ChainID(chain)
+		q.ChainID = &chainID
+	}
+
+	emitters, err := c.srv.FindAll(ctx.Context(), q)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(emitters)
+}
+
+// FindOne godoc
+// @Description Returns an emitter of the registry.
+// @Tags wormholescan
+// @ID find-emitter
+// @Param chain path integer true "Emitter chain."
+// @Param emitter path string true "Emitter address."
+// @Success 200 {object} emitter.EmitterDoc
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/emitters/{chain}/{emitter} [get]
+func (c *Controller) FindOne(ctx *fiber.Ctx) error {
+	chainID, addr, err := middleware.ExtractVAAChainIDEmitter(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	e, err := c.srv.FindOne(ctx.Context(), chainID, addr.Hex())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(e)
+}
@@ -0,0 +1,56 @@
+package eta
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/eta"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *eta.Service
+	logger *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(srv *eta.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "EtaController")),
+	}
+}
+
+// GetEstimate godoc
+// @Description Returns the p50 and p90 duration, in seconds, of each phase of the transfers between two chains
+// @Description over the last 7 days: finality_wait, governor_hold, guardian_signing, redemption_delay and total.
+// @Description When there is no data for the protocol, the estimate of the whole corridor is returned.
+// @Tags wormholescan
+// @ID get-eta
+// @Param from query integer true "Source chain."
+// @Param to query integer true "Target chain."
+// @Param appId query string false "Protocol of the transfers."
+// @Success 200 {object} eta.Estimate
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/eta [get]
+func (c *Controller) GetEstimate(ctx *fiber.Ctx) error {
+	from, err := strconv.ParseUint(ctx.Query("from"), 10, 16)
+	if err != nil {
+		return response.NewInvalidQueryParamError(ctx, "INVALID <from> QUERY PARAMETER", nil)
+	}
+	to, err := strconv.ParseUint(ctx.Query("to"), 10, 16)
+	if err != nil {
+		return response.NewInvalidQueryParamError(ctx, "INVALID <to> QUERY PARAMETER", nil)
+	}
+
+	e, err := c.srv.Estimate(ctx.Context(), sdk.ChainID(from), sdk.ChainID(to), ctx.Query("appId"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(e)
+}
@@ -0,0 +1,137 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/export"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	_ "github.com/wormhole-foundation/wormhole-explorer/api/response" // required by swaggo
+	commonExport "github.com/wormhole-foundation/wormhole-explorer/common/export"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Controller is the controller for the export resource.
+type Controller struct {
+	srv    *export.Service
+	logger *zap.Logger
+}
+
+// NewController creates a new controller.
+func NewController(srv *export.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "ExportController")),
+	}
+}
+
+// CreateExportRequest is the body of a new export.
+type CreateExportRequest struct {
+	// Format of the export file: csv (default), jsonl or parquet.
+	Format         string        `json:"format"`
+	Address        string        `json:"address"`
+	SourceChain    []sdk.ChainID `json:"sourceChain"`
+	TargetChain    []sdk.ChainID `json:"targetChain"`
+	AppID          []string      `json:"appId"`
+	ExclusiveAppId bool          `json:"exclusiveAppId"`
+	From           *time.Time    `json:"from"`
+	To             *time.Time    `json:"to"`
+}
+
+// Create godoc
+// @Description Creates an asynchronous export of operations in CSV, JSON lines or Parquet format.
+// @Description The export runs in background, use the returned id to check its status and download the file.
+// @Tags wormholescan
+// @ID create-export
+// @Param request body CreateExportRequest true "export request"
+// @Success 202 {object} export.Job
+// @Failure 400
+// @Failure 429
+// @Failure 500
+// @Router /api/v1/exports [post]
+func (c *Controller) Create(ctx *fiber.Ctx) error {
+
+	var body CreateExportRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		return response.NewRequestBodyError(ctx, "invalid export request, unable to parse", err)
+	}
+
+	if body.Format == "" {
+		body.Format = string(commonExport.FormatCSV)
+	}
+	format, err := commonExport.ParseFormat(body.Format)
+	if err != nil {
+		return response.NewRequestBodyError(ctx, err.Error(), err)
+	}
+
+	filter := export.Filter{
+		Address:        body.Address,
+		SourceChainIDs: body.SourceChain,
+		TargetChainIDs: body.TargetChain,
+		AppIDs:         body.AppID,
+		ExclusiveAppId: body.ExclusiveAppId,
+		From:           body.From,
+		To:             body.To,
+	}
+
+	job, err := c.srv.Submit(ctx.Context(), format, filter)
+	switch {
+	case errors.Is(err, export.ErrInvalidFilter):
+		return response.NewRequestBodyError(ctx, err.Error(), err)
+	case errors.Is(err, export.ErrQueueFull):
+		return response.NewApiError(ctx, fiber.StatusTooManyRequests, response.ResourceExhausted,
+			"too many exports in progress, try again later", err)
+	case err != nil:
+		return err
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// FindById godoc
+// @Description Returns the status of an export.
+// @Tags wormholescan
+// @ID find-export-by-id
+// @Param id path string true "id of the export"
+// @Success 200 {object} export.Job
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/exports/:id [get]
+func (c *Controller) FindById(ctx *fiber.Ctx) error {
+
+	job, err := c.srv.GetJob(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(job)
+}
+
+// Download godoc
+// @Description Downloads the file of a completed export.
+// @Tags wormholescan
+// @ID download-export
+// @Param id path string true "id of the export"
+// @Success 200 {file} file
+// @Failure 404
+// @Failure 409
+// @Failure 500
+// @Router /api/v1/exports/:id/download [get]
+func (c *Controller) Download(ctx *fiber.Ctx) error {
+
+	job, r, err := c.srv.OpenResult(ctx.Context(), ctx.Params("id"))
+	if errors.Is(err, export.ErrNotReady) {
+		return response.NewApiError(ctx, fiber.StatusConflict, response.FailedPrecondition,
+			fmt.Sprintf("export is %s", job.Status), err)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, job.Format.ContentType())
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", job.Filename()))
+	return ctx.SendStream(r, int(job.Size))
+}
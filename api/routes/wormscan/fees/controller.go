@@ -0,0 +1,121 @@
+package fees
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/fees"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	commonFees "github.com/wormhole-foundation/wormhole-explorer/common/fees"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// defaultRange is the time range of the query when from is not set.
+const defaultRange = 30 * 24 * time.Hour
+
+// maxIntervals is the maximum number of intervals of the time range.
+const maxIntervals = 1000
+
+// intervals are the supported values of the interval query parameter.
+var intervals = map[string]time.Duration{
+	"1h": time.Hour,
+	"1d": 24 * time.Hour,
+	"1w": 7 * 24 * time.Hour,
+}
+
+// Controller definition.
+type Controller struct {
+	srv    *fees.Service
+	logger *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(srv *fees.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "FeesController")),
+	}
+}
+
+// GetStats godoc
+// @Description Returns the number of transactions and the total and average fee, in the native token
+// @Description and in USD, of the source or destination transactions grouped by chain, relayer or protocol.
+// @Description Only the known relayers are returned, the transactions of the other senders are grouped under the relayer "other".
+// @Tags wormholescan
+// @ID get-fees
+// @Param side query string false "Transactions to aggregate, default: destination, supported values: [source, destination]."
+// @Param groupBy query string false "Grouping of the fees besides the chain, default: chain, supported values: [chain, relayer, appId]."
+// @Param chain query integer false "Chain of the transactions."
+// @Param from query string false "Start of the time range in RFC3339 format, default: 30 days ago."
+// @Param to query string false "End of the time range in RFC3339 format, default: now."
+// @Param interval query string false "Split the time range in up to 1000 intervals, supported values: [1h, 1d, 1w]."
+// @Success 200 {object} []fees.Stats
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/fees [get]
+func (c *Controller) GetStats(ctx *fiber.Ctx) error {
+	side, ok := commonFees.ParseSide(ctx.Query("side", string(commonFees.SideDestination)))
+	if !ok {
+		return response.NewInvalidQueryParamError(ctx, "INVALID <side> QUERY PARAMETER", nil)
+	}
+	groupBy, ok := fees.ParseGroupBy(ctx.Query("groupBy", string(fees.GroupByChain)))
+	if !ok {
+		return response.NewInvalidQueryParamError(ctx, "INVALID <groupBy> QUERY PARAMETER", nil)
+	}
+
+	var chainID *sdk.ChainID
+	if chain := ctx.Query("chain"); chain != "" {
+		id, err := strconv.ParseUint(chain, 10, 16)
+		if err != nil {
+			return response.NewInvalidQueryParamError(ctx, "INVALID <chain> QUERY PARAMETER", nil)
+		}
+		chainID = new(sdk.ChainID)
+		*chainID = sdk.ChainID(id)
+	}
+
+	to, err := middleware.ExtractTime(ctx, time.RFC3339, "to")
+	if err != nil {
+		return err
+	}
+	if to == nil {
+		now := time.Now()
+		to = &now
+	}
+	from, err := middleware.ExtractTime(ctx, time.RFC3339, "from")
+	if err != nil {
+		return err
+	}
+	if from == nil {
+		start := to.Add(-defaultRange)
+		from = &start
+	}
+	if !from.Before(*to) {
+		return response.NewInvalidQueryParamError(ctx, "INVALID <from> QUERY PARAMETER, <from> MUST BE BEFORE <to>", nil)
+	}
+
+	var interval time.Duration
+	if v := ctx.Query("interval"); v != "" {
+		if interval, ok = intervals[v]; !ok {
+			return response.NewInvalidQueryParamError(ctx, "INVALID <interval> QUERY PARAMETER", nil)
+		}
+		if to.Sub(*from)/interval > maxIntervals {
+			return response.NewInvalidQueryParamError(ctx, "INVALID <interval> QUERY PARAMETER, THE TIME RANGE EXCEEDS 1000 INTERVALS", nil)
+		}
+	}
+
+	stats, err := c.srv.GetStats(ctx.Context(), &fees.Query{
+		Side:     string(side),
+		GroupBy:  groupBy,
+		ChainID:  chainID,
+		From:     *from,
+		To:       *to,
+		Interval: interval,
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(stats)
+}
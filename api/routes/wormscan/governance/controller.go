@@ -0,0 +1,114 @@
+package governance
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governance"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	commonGovernance "github.com/wormhole-foundation/wormhole-explorer/common/governance"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *governance.Service
+	logger *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(srv *governance.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "GovernanceController")),
+	}
+}
+
+// FindAll godoc
+// @Description Returns the decoded core, token bridge and NFT bridge governance VAAs.
+// @Tags wormholescan
+// @ID find-governance-actions
+// @Param type query string false "Action type." Enums(contractUpgrade, guardianSetUpgrade, setMessageFee, transferFees, recoverChainId, registerChain)
+// @Param module query string false "Governance module." Enums(Core, TokenBridge, NFTBridge)
+// @Param chain query integer false "Target chain, 0 means every chain."
+// @Param from query string false "From date, supported format 2006-01-02T15:04:05Z07:00."
+// @Param to query string false "To date, supported format 2006-01-02T15:04:05Z07:00."
+// @Param page query integer false "Page number."
+// @Param pageSize query integer false "Number of elements per page."
+// @Param sortOrder query string false "Sort results in ascending or descending order." Enums(ASC, DESC)
+// @Success 200 {object} []governance.ActionDoc
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/governance [get]
+func (c *Controller) FindAll(ctx *fiber.Ctx) error {
+	p, err := middleware.ExtractPagination(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Limit > 1000 {
+		return response.NewInvalidParamError(ctx, "pageSize cannot be greater than 1000", nil)
+	}
+
+	q := &governance.ActionQuery{Pagination: p}
+	if v := ctx.Query("type"); v != "" {
+		t, ok := commonGovernance.ParseActionType(v)
+		if !ok {
+			return response.NewInvalidQueryParamError(ctx, "INVALID <type> QUERY PARAMETER", nil)
+		}
+		q.Type = &t
+	}
+	if v := ctx.Query("module"); v != "" {
+		m, ok := commonGovernance.ParseModule(v)
+		if !ok {
+			return response.NewInvalidQueryParamError(ctx, "INVALID <module> QUERY PARAMETER", nil)
+		}
+		q.Module = &m
+	}
+	if v := ctx.Query("chain"); v != "" {
+		chain, err := strconv.ParseUint(v, 10, 16)
+		if err != nil {
+			return response.NewInvalidQueryParamError(ctx, "INVALID <chain> QUERY PARAMETER", nil)
+		}
+		chainID := sdk.ChainID(chain)
+		q.TargetChain = &chainID
+	}
+	q.From, err = middleware.ExtractTime(ctx, time.RFC3339, "from")
+	if err != nil {
+		return err
+	}
+	q.To, err = middleware.ExtractTime(ctx, time.RFC3339, "to")
+	if err != nil {
+		return err
+	}
+
+	actions, err := c.srv.FindActions(ctx.Context(), q)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(actions)
+}
+
+// FindRegisteredEmitters godoc
+// @Description Returns the emitters registered in the token bridge and NFT bridge of a chain,
+// @Description derived from the chain registration governance history.
+// @Tags wormholescan
+// @ID find-governance-registered-emitters
+// @Param chain path integer true "Chain on which the registrations apply."
+// @Success 200 {object} []governance.RegisteredEmitterDoc
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/governance/registered-emitters/{chain} [get]
+func (c *Controller) FindRegisteredEmitters(ctx *fiber.Ctx) error {
+	chainID, err := middleware.ExtractChainID(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	emitters, err := c.srv.FindRegisteredEmitters(ctx.Context(), chainID)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(emitters)
+}
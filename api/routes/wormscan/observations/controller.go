@@ -3,14 +3,23 @@ package observations
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/observations"
 	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
 	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"github.com/wormhole-foundation/wormhole-explorer/common/observation"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 )
 
+const (
+	// maxStuckTimeRange is the maximum time range to look for stuck messages.
+	maxStuckTimeRange = 7 * 24 * time.Hour
+)
+
 // Controller definition.
 type Controller struct {
 	srv    *observations.Service
@@ -205,3 +214,117 @@ func (c *Controller) FindOne(ctx *fiber.Ctx) error {
 	}
 	return ctx.JSON(obs)
 }
+
+// FindConflicts godoc
+// @Description Returns the messages for which guardians signed different hashes, with the signers of each hash.
+// @Tags wormholescan
+// @ID find-conflicting-observations
+// @Param chain query integer false "Emitter chain."
+// @Param page query integer false "Page number."
+// @Param pageSize query integer false "Number of elements per page."
+// @Param sortOrder query string false "Sort results in ascending or descending order." Enums(ASC, DESC)
+// @Success 200 {object} []observations.ConflictingObservationDoc
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/observations/conflicts [get]
+func (c *Controller) FindConflicts(ctx *fiber.Ctx) error {
+	p, err := middleware.ExtractPagination(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Limit > 1000 {
+		return response.NewInvalidParamError(ctx, "pageSize cannot be greater than 1000", nil)
+	}
+
+	chainID, err := extractChainQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := c.srv.FindConflicts(ctx.Context(), chainID, p)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(conflicts)
+}
+
+// FindStuck godoc
+// @Description Returns the messages that were observed by the guardians but did not become a signed VAA in time,
+// @Description classified as stuck (quorum reached without VAA), partial (quorum not reached) or late (VAA indexed after the threshold).
+// @Description The messages are detected by the stuck observations job, using its threshold; the messages enqueued by the governor are not included.
+// @Tags wormholescan
+// @ID find-stuck-observations
+// @Param from query string false "From date of the first observation, supported format 2006-01-02T15:04:05Z07:00. Defaults to 24 hours before to."
+// @Param to query string false "To date of the first observation, supported format 2006-01-02T15:04:05Z07:00. Defaults to now."
+// @Param chain query integer false "Emitter chain."
+// @Param status query string false "Comma-separated list of statuses." Enums(stuck, partial, late)
+// @Param pageSize query integer false "Maximum number of messages."
+// @Success 200 {object} []observation.Message
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/observations/stuck [get]
+func (c *Controller) FindStuck(ctx *fiber.Ctx) error {
+	p, err := middleware.ExtractPagination(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Limit > 1000 {
+		return response.NewInvalidParamError(ctx, "pageSize cannot be greater than 1000", nil)
+	}
+
+	to, err := middleware.ExtractTime(ctx, time.RFC3339, "to")
+	if err != nil {
+		return err
+	}
+	from, err := middleware.ExtractTime(ctx, time.RFC3339, "from")
+	if err != nil {
+		return err
+	}
+	q := observation.Query{Limit: p.Limit}
+	q.To = time.Now().UTC()
+	if to != nil {
+		q.To = *to
+	}
+	q.From = q.To.Add(-24 * time.Hour)
+	if from != nil {
+		q.From = *from
+	}
+	if !q.From.Before(q.To) || q.To.Sub(q.From) > maxStuckTimeRange {
+		return response.NewInvalidParamError(ctx, "invalid time range, it cannot be greater than 7 days", nil)
+	}
+
+	q.ChainID, err = extractChainQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	if v := ctx.Query("status"); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			status, ok := observation.ParseStatus(strings.TrimSpace(s))
+			if !ok {
+				return response.NewInvalidQueryParamError(ctx, "INVALID <status> QUERY PARAMETER", nil)
+			}
+			q.Statuses = append(q.Statuses, status)
+		}
+	}
+
+	messages, err := c.srv.FindStuck(ctx.Context(), q)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(messages)
+}
+
+// extractChainQuery parses the optional chain query parameter.
+func extractChainQuery(ctx *fiber.Ctx) (*sdk.ChainID, error) {
+	v := ctx.Query("chain")
+	if v == "" {
+		return nil, nil
+	}
+	chain, err := strconv.ParseUint(v, 10, 16)
+	if err != nil {
+		return nil, response.NewInvalidQueryParamError(ctx, "INVALID <chain> QUERY PARAMETER", nil)
+	}
+	chainID := sdk.ChainID(chain)
+	return &chainID, nil
+}
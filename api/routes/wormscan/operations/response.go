@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/eta"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/operations"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
@@ -18,12 +20,14 @@ type OperationResponse struct {
 	ID             string         `json:"id"`
 	EmitterChain   sdk.ChainID    `json:"emitterChain"`
 	EmitterAddress EmitterAddress `json:"emitterAddress"`
+	Emitter        *emitter.Info  `json:"emitter,omitempty"`
 	Sequence       string         `json:"sequence"`
 	Vaa            *Vaa           `json:"vaa,omitempty"`
 	Content        *Content       `json:"content,omitempty"`
 	SourceChain    *SourceChain   `json:"sourceChain,omitempty"`
 	TargetChain    *TargetChain   `json:"targetChain,omitempty"`
 	Data           map[string]any `json:"data,omitempty"`
+	ETA            *eta.Arrival   `json:"eta,omitempty"`
 }
 
 // EmitterAddress definition.
@@ -135,12 +139,14 @@ func toOperationResponse(operation *operations.OperationDto, log *zap.Logger) (*
 			Hex:    address,
 			Native: emitterNativeAddress,
 		},
+		Emitter:     operation.Emitter,
 		Sequence:    sequence,
 		Vaa:         vaa,
 		Content:     &content,
 		Data:        getAdditionalData(operation),
 		SourceChain: sourceChain,
 		TargetChain: targetChain,
+		ETA:         operation.ETA,
 	}
 
 	return &r, nil
@@ -7,6 +7,11 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	addrsvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/address"
+	emittersvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/emitter"
+	etasvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/eta"
+	exportsvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/export"
+	feessvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/fees"
+	governancesvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/governance"
 	govsvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
 	infrasvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/infrastructure"
 	obssvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/observations"
@@ -18,6 +23,12 @@ import (
 	trxsvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
 	vaasvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/address"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/chains"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/emitter"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/eta"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/export"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/fees"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/governance"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/governor"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/infrastructure"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/observations"
@@ -49,6 +60,12 @@ func RegisterRoutes(
 	statsService *statssvc.Service,
 	protocolsService *protocolssvc.Service,
 	supplyService *supplySvc.Service,
+	exportService *exportsvc.Service,
+	governanceService *governancesvc.Service,
+	emitterService *emittersvc.Service,
+	etaService *etasvc.Service,
+	feesService *feessvc.Service,
+	p2pNetwork string,
 ) {
 
 	// Set up controllers
@@ -63,6 +80,12 @@ func RegisterRoutes(
 	statsCtrl := stats.NewController(statsService, rootLogger)
 	contributorsCtrl := protocols.NewController(rootLogger, protocolsService)
 	supplyCtrl := supply.NewController(supplyService, rootLogger)
+	exportCtrl := export.NewController(exportService, rootLogger)
+	governanceCtrl := governance.NewController(governanceService, rootLogger)
+	emitterCtrl := emitter.NewController(emitterService, rootLogger)
+	chainsCtrl := chains.NewController(p2pNetwork, rootLogger)
+	etaCtrl := eta.NewController(etaService, rootLogger)
+	feesCtrl := fees.NewController(feesService, rootLogger)
 
 	// Set up route handlers
 	api := app.Group("/api/v1")
@@ -93,6 +116,13 @@ func RegisterRoutes(
 
 	// accounts resource
 	api.Get("/address/:id", addressCtrl.FindById)
+	api.Get("/address/:id/stats", addressCtrl.GetStats)
+	api.Get("/address/:id/links", addressCtrl.GetLinks)
+
+	// bulk exports
+	api.Post("/exports", exportCtrl.Create)
+	api.Get("/exports/:id", exportCtrl.FindById)
+	api.Get("/exports/:id/download", exportCtrl.Download)
 
 	// analytics, transactions, custom endpoints
 	api.Get("/global-tx/:chain/:emitter/:sequence", transactionCtrl.FindGlobalTransactionByID)
@@ -112,6 +142,8 @@ func RegisterRoutes(
 	// stats custom endpoints
 	api.Get("/top-symbols-by-volume", statsCtrl.GetTopSymbolsByVolume)
 	api.Get("/top-100-corridors", statsCtrl.GetTopCorridors)
+	api.Get("/eta", etaCtrl.GetEstimate)
+	api.Get("/fees", feesCtrl.GetStats)
 	api.Get("/protocols/stats", contributorsCtrl.GetProtocolsTotalValues)
 	api.Get("/native-token-transfer/summary", notSupportedByEnv, statsCtrl.GetNativeTokenTransferSummary)
 	api.Get("/native-token-transfer/activity", notSupportedByEnv, statsCtrl.GetNativeTokenTransferActivity)
@@ -137,6 +169,8 @@ func RegisterRoutes(
 	// oservations resource
 	observations := api.Group("/observations")
 	observations.Get("/", observationsCtrl.FindAll)
+	observations.Get("/stuck", observationsCtrl.FindStuck)
+	observations.Get("/conflicts", observationsCtrl.FindConflicts)
 	observations.Get("/:chain", observationsCtrl.FindAllByChain)
 	observations.Get("/:chain/:emitter", observationsCtrl.FindAllByEmitter)
 	observations.Get("/:chain/:emitter/:sequence", observationsCtrl.FindAllByVAA)
@@ -167,6 +201,21 @@ func RegisterRoutes(
 	enqueueVaas.Get("/:chain", governorCtrl.GetEnqueuedVaasByChainID)
 	governor.Get("/vaas", governorCtrl.GetGovernorVaas)
 
+	// governance resources
+	governance := api.Group("/governance")
+	governance.Get("/", governanceCtrl.FindAll)
+	governance.Get("/registered-emitters/:chain", governanceCtrl.FindRegisteredEmitters)
+
+	// emitter registry resources
+	emitters := api.Group("/emitters")
+	emitters.Get("/", emitterCtrl.FindAll)
+	emitters.Get("/:chain/:emitter", emitterCtrl.FindOne)
+
+	// chains resource
+	chainsGroup := api.Group("/chains")
+	chainsGroup.Get("/", chainsCtrl.FindAll)
+	chainsGroup.Get("/:chain", chainsCtrl.FindOne)
+
 	relays := api.Group("/relays")
 	relays.Get("/:chain/:emitter/:sequence", relaysCtrl.FindOne)
 }
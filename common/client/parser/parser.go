@@ -2,6 +2,7 @@ package parser
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -9,6 +10,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 )
@@ -53,7 +55,8 @@ func NewParserVAAAPIClient(timeout int64, baseURL string, logger *zap.Logger) (P
 
 	return ParserVAAAPIClient{
 		Client: http.Client{
-			Timeout: time.Duration(timeout) * time.Second,
+			Timeout:   time.Duration(timeout) * time.Second,
+			Transport: telemetry.NewTransport(nil),
 		},
 		BaseURL: baseURL,
 		Logger:  logger,
@@ -130,7 +133,7 @@ type ParseVaaWithStandarizedPropertiesdResponse struct {
 }
 
 // ParseVaaWithStandarizedProperties invoke the endpoint to parse a VAA from the VAAParserAPI.
-func (c *ParserVAAAPIClient) ParseVaaWithStandarizedProperties(vaa *sdk.VAA) (*ParseVaaWithStandarizedPropertiesdResponse, error) {
+func (c *ParserVAAAPIClient) ParseVaaWithStandarizedProperties(ctx context.Context, vaa *sdk.VAA) (*ParseVaaWithStandarizedPropertiesdResponse, error) {
 	endpointUrl := fmt.Sprintf("%s/vaas/parse", c.BaseURL)
 
 	vaaBytes, err := vaa.Marshal()
@@ -139,7 +142,12 @@ func (c *ParserVAAAPIClient) ParseVaaWithStandarizedProperties(vaa *sdk.VAA) (*P
 	}
 
 	body := base64.StdEncoding.EncodeToString(vaaBytes)
-	response, err := c.Client.Post(endpointUrl, "text/plain", bytes.NewBuffer([]byte(body)))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointUrl, bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "text/plain")
+	response, err := c.Client.Do(request)
 	if err != nil {
 		c.Logger.Error("error call parse vaa endpoint", zap.Error(err), zap.Uint16("chainID", uint16(vaa.EmitterChain)),
 			zap.String("address", vaa.EmitterAddress.String()), zap.Uint64("sequence", vaa.Sequence))
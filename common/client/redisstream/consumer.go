@@ -0,0 +1,352 @@
+package redisstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	// deadLetterSuffix is appended to the name of a stream to get the name of its dead letter stream.
+	deadLetterSuffix = ":dlq"
+	// deadLetterIDField, deadLetterGroupField and deadLetterErrorField are the fields of a dead letter entry
+	// that hold the id of the failed entry, the group that failed to process it and the error of its last processing.
+	deadLetterIDField    = "deadLetterId"
+	deadLetterGroupField = "deadLetterGroup"
+	deadLetterErrorField = "deadLetterError"
+)
+
+// ConsumerOption represents a consumer option function.
+type ConsumerOption func(*Consumer)
+
+// Consumer represents a redis stream consumer that belongs to a consumer group.
+//
+// A message read by the consumer stays pending until it is acknowledged. When the consumer crashes
+// or fails to process it, the message is reclaimed by any consumer of the group once it has been
+// idle for longer than the visibility timeout, as the visibility timeout of SQS. A message that failed
+// after max deliveries is moved to the dead letter stream, as the redrive policy of SQS.
+type Consumer struct {
+	client            *redis.Client
+	stream            string
+	group             string
+	name              string
+	maxMessages       int64
+	visibilityTimeout time.Duration
+	waitTime          time.Duration
+	maxDeliveries     int64
+	logger            *zap.Logger
+}
+
+// Message represents a message read from a stream.
+type Message struct {
+	ID   string
+	Body string
+	// TraceContext is the trace context of the producer of the message.
+	TraceContext map[string]string
+	// Retry is the number of times the message has been delivered.
+	Retry uint8
+}
+
+// NewConsumer creates a consumer of a stream, creating the stream and the consumer group when they do not exist.
+func NewConsumer(ctx context.Context, client *redis.Client, stream, group string, opts ...ConsumerOption) (*Consumer, error) {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = fmt.Sprintf("consumer-%d", os.Getpid())
+	}
+	consumer := &Consumer{
+		client:            client,
+		stream:            stream,
+		group:             group,
+		name:              name,
+		maxMessages:       10,
+		visibilityTimeout: 60 * time.Second,
+		waitTime:          20 * time.Second,
+		maxDeliveries:     10,
+		logger:            zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(consumer)
+	}
+
+	// the group starts with the messages added after its creation.
+	err = client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("failed to create consumer group %s of stream %s: %w", group, stream, err)
+	}
+
+	return consumer, nil
+}
+
+// WithConsumerName allows to specify the name of the consumer in the group. It defaults to the hostname.
+func WithConsumerName(name string) ConsumerOption {
+	return func(c *Consumer) {
+		c.name = name
+	}
+}
+
+// WithMaxMessages allows to specify an maximum number of messages to return when setting a value.
+func WithMaxMessages(v int64) ConsumerOption {
+	return func(c *Consumer) {
+		c.maxMessages = v
+	}
+}
+
+// WithVisibilityTimeout allows to specify the time a message stays pending before being reclaimed.
+func WithVisibilityTimeout(v time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		c.visibilityTimeout = v
+	}
+}
+
+// WithWaitTime allows to specify the time to block waiting for new messages.
+func WithWaitTime(v time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		c.waitTime = v
+	}
+}
+
+// WithMaxDeliveries allows to specify the number of deliveries of a failed message before it is
+// moved to the dead letter stream. A value of 0 keeps the failed messages in the stream forever.
+func WithMaxDeliveries(v int64) ConsumerOption {
+	return func(c *Consumer) {
+		c.maxDeliveries = v
+	}
+}
+
+// WithLogger allows to specify the logger of the errors that do not stop the consumer.
+func WithLogger(logger *zap.Logger) ConsumerOption {
+	return func(c *Consumer) {
+		c.logger = logger
+	}
+}
+
+// GetMessages retrieves messages from the stream.
+// The pending messages of crashed consumers are reclaimed first, then new messages are read.
+// A failure to reclaim is logged and does not stop the new messages from being read.
+func (c *Consumer) GetMessages(ctx context.Context) ([]Message, error) {
+	messages, err := c.reclaim(ctx)
+	if err != nil {
+		c.logger.Error("Error reclaiming pending messages from redis stream",
+			zap.String("stream", c.stream), zap.String("group", c.group), zap.Error(err))
+	}
+	if len(messages) > 0 {
+		return messages, nil
+	}
+
+	streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.group,
+		Consumer: c.name,
+		Streams:  []string{c.stream, ">"},
+		Count:    c.maxMessages,
+		Block:    c.waitTime,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			messages = append(messages, newMessage(msg, 1))
+		}
+	}
+	return messages, nil
+}
+
+// reclaim claims the messages that have been pending for longer than the visibility timeout.
+// XAUTOCLAIM is not used because its reply changed in redis 7 and the client can not parse it.
+func (c *Consumer) reclaim(ctx context.Context) ([]Message, error) {
+	pending, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: c.stream,
+		Group:  c.group,
+		Idle:   c.visibilityTimeout,
+		Start:  "-",
+		End:    "+",
+		Count:  c.maxMessages,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending messages: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(pending))
+	retries := make(map[string]int64, len(pending))
+	for _, p := range pending {
+		ids = append(ids, p.ID)
+		retries[p.ID] = p.RetryCount
+	}
+
+	// the claim checks the idle time again, so a message claimed meanwhile by another consumer is skipped.
+	claimed, err := c.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   c.stream,
+		Group:    c.group,
+		Consumer: c.name,
+		MinIdle:  c.visibilityTimeout,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending messages: %w", err)
+	}
+
+	messages := make([]Message, 0, len(claimed))
+	for _, msg := range claimed {
+		// the claim increments the delivery count kept in the pending entries list.
+		messages = append(messages, newMessage(msg, retries[msg.ID]+1))
+	}
+	return messages, nil
+}
+
+func newMessage(msg redis.XMessage, retry int64) Message {
+	m := Message{
+		ID:           msg.ID,
+		TraceContext: make(map[string]string, len(msg.Values)),
+		Retry:        uint8(min(retry, 255)),
+	}
+	for key, value := range msg.Values {
+		s, _ := value.(string)
+		if key == messageField {
+			m.Body = s
+		} else {
+			m.TraceContext[key] = s
+		}
+	}
+	return m
+}
+
+// DeleteMessage acknowledges a message, so that it is not delivered again to the group.
+// The entry is kept in the stream for the other groups, and removed when the stream is trimmed.
+func (c *Consumer) DeleteMessage(ctx context.Context, id string) error {
+	return c.client.XAck(ctx, c.stream, c.group, id).Err()
+}
+
+// Failed handles a message whose processing failed. The message is left pending to be delivered again,
+// unless it has been delivered max deliveries times: then it is added to the dead letter stream and
+// acknowledged, atomically, with lastErr. It returns true when the message was moved to the dead letter stream.
+func (c *Consumer) Failed(ctx context.Context, id string, retry uint8, lastErr string) (bool, error) {
+	if c.maxDeliveries <= 0 || int64(retry) < c.maxDeliveries {
+		return false, nil
+	}
+
+	entries, err := c.client.XRangeN(ctx, c.stream, id, id, 1).Result()
+	if err != nil {
+		return false, err
+	}
+	values := map[string]interface{}{
+		deadLetterIDField:    id,
+		deadLetterGroupField: c.group,
+		deadLetterErrorField: lastErr,
+	}
+	if len(entries) > 0 {
+		for key, value := range entries[0].Values {
+			values[key] = value
+		}
+	}
+
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{Stream: c.GetDeadLetterStream(), Values: values})
+		pipe.XAck(ctx, c.stream, c.group, id)
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to move message %s to the dead letter stream: %w", id, err)
+	}
+	return true, nil
+}
+
+// GetVisibilityTimeout returns visibility timeout.
+func (c *Consumer) GetVisibilityTimeout() time.Duration {
+	return c.visibilityTimeout
+}
+
+// GetStream returns the stream name.
+func (c *Consumer) GetStream() string {
+	return c.stream
+}
+
+// GetDeadLetterStream returns the name of the stream holding the messages that failed after max deliveries.
+func (c *Consumer) GetDeadLetterStream() string {
+	return c.stream + deadLetterSuffix
+}
+
+// GetGroup returns the consumer group name.
+func (c *Consumer) GetGroup() string {
+	return c.group
+}
+
+// Lag returns the age of the oldest message that the group has not acknowledged yet,
+// either pending or not delivered. It returns 0 when the group is up to date.
+func (c *Consumer) Lag(ctx context.Context) (time.Duration, error) {
+	pending, err := c.client.XPending(ctx, c.stream, c.group).Result()
+	if err != nil {
+		return 0, err
+	}
+	oldest := pending.Lower
+	if pending.Count == 0 {
+		lastDeliveredID, err := c.lastDeliveredID(ctx)
+		if err != nil {
+			return 0, err
+		}
+		next, err := c.client.XRangeN(ctx, c.stream, "("+lastDeliveredID, "+", 1).Result()
+		if err != nil {
+			return 0, err
+		}
+		if len(next) == 0 {
+			return 0, nil
+		}
+		oldest = next[0].ID
+	}
+	return time.Since(GetSentTimestamp(oldest)), nil
+}
+
+// lastDeliveredID returns the id of the last message delivered to the group.
+// The reply of XINFO GROUPS is read field by field because redis 7 added fields
+// that the client does not expect.
+func (c *Consumer) lastDeliveredID(ctx context.Context) (string, error) {
+	reply, err := c.client.Do(ctx, "XINFO", "GROUPS", c.stream).Slice()
+	if err != nil {
+		return "", err
+	}
+	for _, g := range reply {
+		fields, ok := g.([]interface{})
+		if !ok {
+			continue
+		}
+		info := make(map[string]string, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			key, _ := fields[i].(string)
+			value, _ := fields[i+1].(string)
+			info[key] = value
+		}
+		if info["name"] == c.group {
+			return info["last-delivered-id"], nil
+		}
+	}
+	return "0-0", nil
+}
+
+// DeadLetters returns the number of messages in the dead letter stream.
+func (c *Consumer) DeadLetters(ctx context.Context) (int64, error) {
+	return c.client.XLen(ctx, c.GetDeadLetterStream()).Result()
+}
+
+// GetSentTimestamp returns the time a message was added to the stream, which is encoded in its id.
+func GetSentTimestamp(id string) time.Time {
+	ms, _, _ := strings.Cut(id, "-")
+	millis, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
@@ -0,0 +1,259 @@
+package redisstream
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/go-redis/redis/v8"
+	"github.com/test-go/testify/assert"
+	"github.com/test-go/testify/require"
+)
+
+const (
+	testStream = "vaas"
+	testGroup  = "parser"
+)
+
+func newTestClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	m := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return m, client
+}
+
+func newTestConsumer(t *testing.T, client *redis.Client, name string, opts ...ConsumerOption) *Consumer {
+	opts = append([]ConsumerOption{
+		WithConsumerName(name),
+		WithVisibilityTimeout(time.Minute),
+		WithWaitTime(10 * time.Millisecond),
+	}, opts...)
+	c, err := NewConsumer(context.Background(), client, testStream, testGroup, opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestGetMessages(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestClient(t)
+	consumer := newTestConsumer(t, client, "consumer-1")
+	producer := NewProducer(client, testStream, 0)
+
+	messages, err := consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	require.NoError(t, producer.SendMessage(ctx, "vaa-1"))
+	messages, err = consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "vaa-1", messages[0].Body)
+	assert.Equal(t, uint8(1), messages[0].Retry)
+
+	// a message is delivered once to the group.
+	messages, err = consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestGetMessages_Reclaim(t *testing.T) {
+	ctx := context.Background()
+	m, client := newTestClient(t)
+	now := time.Now()
+	m.SetTime(now)
+	crashed := newTestConsumer(t, client, "consumer-1")
+	consumer := newTestConsumer(t, client, "consumer-2")
+	producer := NewProducer(client, testStream, 0)
+
+	require.NoError(t, producer.SendMessage(ctx, "vaa-1"))
+	messages, err := crashed.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	// the message is not reclaimed before the visibility timeout.
+	m.SetTime(now.Add(30 * time.Second))
+	messages, err = consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	// the message is reclaimed with its delivery count.
+	m.SetTime(now.Add(2 * time.Minute))
+	messages, err = consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "vaa-1", messages[0].Body)
+	assert.Equal(t, uint8(2), messages[0].Retry)
+
+	// the reclaimed message belongs to the new consumer until it is idle again.
+	messages, err = crashed.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	m.SetTime(now.Add(4 * time.Minute))
+	messages, err = crashed.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, uint8(3), messages[0].Retry)
+
+	// an acknowledged message is not reclaimed.
+	require.NoError(t, crashed.DeleteMessage(ctx, messages[0].ID))
+	m.SetTime(now.Add(6 * time.Minute))
+	messages, err = consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestGetMessages_ReclaimFailure(t *testing.T) {
+	ctx := context.Background()
+	m, client := newTestClient(t)
+	consumer := newTestConsumer(t, client, "consumer-1")
+	producer := NewProducer(client, testStream, 0)
+
+	// the pending messages can not be listed, as when the reply of the command can not be parsed.
+	m.Server().SetPreHook(func(c *server.Peer, cmd string, args ...string) bool {
+		if strings.EqualFold(cmd, "XPENDING") {
+			c.WriteError("ERR unknown command 'XPENDING'")
+			return true
+		}
+		return false
+	})
+	_, err := consumer.reclaim(ctx)
+	require.Error(t, err)
+
+	// a failure to reclaim does not stop the new messages from being read.
+	require.NoError(t, producer.SendMessage(ctx, "vaa-1"))
+	messages, err := consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "vaa-1", messages[0].Body)
+}
+
+func TestFailed(t *testing.T) {
+	ctx := context.Background()
+	m, client := newTestClient(t)
+	now := time.Now()
+	m.SetTime(now)
+	consumer := newTestConsumer(t, client, "consumer-1", WithMaxDeliveries(2))
+	producer := NewProducer(client, testStream, 0)
+
+	require.NoError(t, producer.SendMessage(ctx, "vaa-1"))
+	messages, err := consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	// the message is left pending before max deliveries.
+	moved, err := consumer.Failed(ctx, messages[0].ID, messages[0].Retry, "first error")
+	require.NoError(t, err)
+	assert.False(t, moved)
+	deadLetters, err := consumer.DeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deadLetters)
+
+	m.SetTime(now.Add(2 * time.Minute))
+	messages, err = consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, uint8(2), messages[0].Retry)
+
+	// the message is moved to the dead letter stream with the last error once delivered max deliveries times.
+	moved, err = consumer.Failed(ctx, messages[0].ID, messages[0].Retry, "last error")
+	require.NoError(t, err)
+	assert.True(t, moved)
+
+	deadLetters, err = consumer.DeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deadLetters)
+	entries, err := client.XRange(ctx, consumer.GetDeadLetterStream(), "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "vaa-1", entries[0].Values[messageField])
+	assert.Equal(t, messages[0].ID, entries[0].Values[deadLetterIDField])
+	assert.Equal(t, testGroup, entries[0].Values[deadLetterGroupField])
+	assert.Equal(t, "last error", entries[0].Values[deadLetterErrorField])
+
+	// the moved message is acknowledged, so it is not reclaimed.
+	pending, err := client.XPending(ctx, testStream, testGroup).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), pending.Count)
+	m.SetTime(now.Add(4 * time.Minute))
+	messages, err = consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestFailed_NoMaxDeliveries(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestClient(t)
+	consumer := newTestConsumer(t, client, "consumer-1", WithMaxDeliveries(0))
+
+	moved, err := consumer.Failed(ctx, "1-0", 255, "error")
+	require.NoError(t, err)
+	assert.False(t, moved)
+}
+
+func TestLag(t *testing.T) {
+	ctx := context.Background()
+	m, client := newTestClient(t)
+	consumer := newTestConsumer(t, client, "consumer-1")
+	producer := NewProducer(client, testStream, 0)
+
+	lag, err := consumer.Lag(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), lag)
+
+	// the message ids are the time they were added, so the lag is the age of the oldest unacknowledged one.
+	m.SetTime(time.Now().Add(-10 * time.Minute))
+	require.NoError(t, producer.SendMessage(ctx, "vaa-1"))
+	m.SetTime(time.Now().Add(-5 * time.Minute))
+	require.NoError(t, producer.SendMessage(ctx, "vaa-2"))
+
+	// not delivered messages.
+	lag, err = consumer.Lag(ctx)
+	require.NoError(t, err)
+	assert.True(t, lag >= 10*time.Minute, lag)
+
+	// pending messages.
+	messages, err := consumer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	lag, err = consumer.Lag(ctx)
+	require.NoError(t, err)
+	assert.True(t, lag >= 10*time.Minute, lag)
+
+	require.NoError(t, consumer.DeleteMessage(ctx, messages[0].ID))
+	lag, err = consumer.Lag(ctx)
+	require.NoError(t, err)
+	assert.True(t, lag >= 5*time.Minute && lag < 10*time.Minute, lag)
+
+	require.NoError(t, consumer.DeleteMessage(ctx, messages[1].ID))
+	lag, err = consumer.Lag(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), lag)
+}
+
+func TestSendMessage_Trim(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestClient(t)
+	producer := NewProducer(client, testStream, 3)
+
+	for _, body := range []string{"vaa-1", "vaa-2", "vaa-3", "vaa-4", "vaa-5"} {
+		require.NoError(t, producer.SendMessage(ctx, body))
+	}
+	entries, err := client.XRange(ctx, testStream, "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "vaa-3", entries[0].Values[messageField])
+	assert.Equal(t, "vaa-5", entries[2].Values[messageField])
+
+	// a producer without max length does not trim the stream.
+	untrimmed := NewProducer(client, "notifications", 0)
+	for _, body := range []string{"vaa-1", "vaa-2", "vaa-3", "vaa-4", "vaa-5"} {
+		require.NoError(t, untrimmed.SendMessage(ctx, body))
+	}
+	length, err := client.XLen(ctx, "notifications").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), length)
+}
@@ -0,0 +1,48 @@
+package redisstream
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
+)
+
+// messageField is the field of a stream entry that holds the message body.
+// The other fields of the entry carry the trace context of the producer.
+const messageField = "message"
+
+// Producer represents a redis stream producer.
+type Producer struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewProducer creates a producer that appends messages to a stream.
+// The stream is trimmed to approximately maxLen entries, or never trimmed when maxLen is 0.
+func NewProducer(client *redis.Client, stream string, maxLen int64) *Producer {
+	return &Producer{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+// SendMessage appends a message to the stream.
+func (p *Producer) SendMessage(ctx context.Context, body string) error {
+	values := map[string]interface{}{messageField: body}
+	for key, value := range telemetry.TraceContext(ctx) {
+		values[key] = value
+	}
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
+
+// GetStream returns the stream name.
+func (p *Producer) GetStream() string {
+	return p.stream
+}
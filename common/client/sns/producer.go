@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_sns "github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 )
 
 // Producer represents SNS producer.
@@ -28,6 +29,7 @@ func (p *Producer) SendMessage(ctx context.Context, groupID, deduplicationID, bo
 			MessageDeduplicationId: aws.String(deduplicationID),
 			Message:                aws.String(body),
 			TopicArn:               aws.String(p.url),
+			MessageAttributes:      telemetry.InjectSNSAttributes(ctx, nil),
 		})
 	return err
 }
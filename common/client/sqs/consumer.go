@@ -2,7 +2,11 @@ package sqs
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -10,6 +14,14 @@ import (
 	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
+const (
+	// LastErrorAttribute is the message attribute that holds the error of the last processing of a message
+	// moved to the dead-letter queue by the consumer.
+	LastErrorAttribute = "LastError"
+	// maxLastErrorLength bounds the length of the LastError attribute.
+	maxLastErrorLength = 1024
+)
+
 // ConsumerOption represents a consumer option function.
 type ConsumerOption func(*Consumer)
 
@@ -20,6 +32,15 @@ type Consumer struct {
 	maxMessages       int32
 	visibilityTimeout int32
 	waitTimeSeconds   int32
+	// deadLetter is the redrive policy of the queue, loaded by the first failed message.
+	deadLetter   *deadLetterQueue
+	deadLetterMu sync.Mutex
+}
+
+// deadLetterQueue is the target of the redrive policy of a queue.
+type deadLetterQueue struct {
+	url             string
+	maxReceiveCount int
 }
 
 // New instances of a Consumer to consume SQS messages.
@@ -94,6 +115,114 @@ func (c *Consumer) DeleteMessage(ctx context.Context, id *string) error {
 	return err
 }
 
+// Failed handles a message whose processing failed with lastErr. The message is left in the queue to be
+// received again, unless this is its last receive allowed by the redrive policy of the queue: then it is
+// sent to the dead-letter queue with lastErr in the LastError attribute, since SQS does not keep the error
+// when it moves a message, and deleted from the queue. It returns true when the message was moved.
+func (c *Consumer) Failed(ctx context.Context, msg aws_sqs_types.Message, lastErr string) (bool, error) {
+	receiveCount, _ := strconv.Atoi(msg.Attributes[string(aws_sqs_types.MessageSystemAttributeNameApproximateReceiveCount)])
+	dlq, err := c.getDeadLetterQueue(ctx)
+	if err != nil {
+		return false, err
+	}
+	if dlq.url == "" || receiveCount < dlq.maxReceiveCount {
+		return false, nil
+	}
+
+	attributes := make(map[string]aws_sqs_types.MessageAttributeValue, len(msg.MessageAttributes)+1)
+	for key, value := range msg.MessageAttributes {
+		attributes[key] = value
+	}
+	if len(lastErr) > maxLastErrorLength {
+		lastErr = strings.ToValidUTF8(lastErr[:maxLastErrorLength], "")
+	}
+	if lastErr != "" {
+		attributes[LastErrorAttribute] = aws_sqs_types.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(lastErr),
+		}
+	}
+
+	input := &aws_sqs.SendMessageInput{
+		QueueUrl:          aws.String(dlq.url),
+		MessageBody:       msg.Body,
+		MessageAttributes: attributes,
+	}
+	// the messages of a fifo queue keep their group, and are deduplicated by their id.
+	if strings.HasSuffix(dlq.url, ".fifo") {
+		input.MessageGroupId = aws.String(msg.Attributes[string(aws_sqs_types.MessageSystemAttributeNameMessageGroupId)])
+		input.MessageDeduplicationId = msg.MessageId
+	}
+	_, err = c.api.SendMessage(ctx, input)
+	if err != nil {
+		return false, fmt.Errorf("failed to send message %s to %s: %w", aws.ToString(msg.MessageId), dlq.url, err)
+	}
+	if err := c.DeleteMessage(ctx, msg.ReceiptHandle); err != nil {
+		return false, fmt.Errorf("failed to delete message %s: %w", aws.ToString(msg.MessageId), err)
+	}
+	return true, nil
+}
+
+// getDeadLetterQueue returns the dead-letter queue of the redrive policy of the queue.
+// The url is empty when the queue has no redrive policy.
+func (c *Consumer) getDeadLetterQueue(ctx context.Context) (*deadLetterQueue, error) {
+	c.deadLetterMu.Lock()
+	defer c.deadLetterMu.Unlock()
+	if c.deadLetter != nil {
+		return c.deadLetter, nil
+	}
+
+	out, err := c.api.GetQueueAttributes(ctx, &aws_sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(c.url),
+		AttributeNames: []aws_sqs_types.QueueAttributeName{aws_sqs_types.QueueAttributeNameRedrivePolicy},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the redrive policy of %s: %w", c.url, err)
+	}
+	policy := out.Attributes[string(aws_sqs_types.QueueAttributeNameRedrivePolicy)]
+	if policy == "" {
+		c.deadLetter = &deadLetterQueue{}
+		return c.deadLetter, nil
+	}
+
+	name, accountID, maxReceiveCount, err := parseRedrivePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.api.GetQueueUrl(ctx, &aws_sqs.GetQueueUrlInput{
+		QueueName:              aws.String(name),
+		QueueOwnerAWSAccountId: aws.String(accountID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the url of the dead-letter queue %s: %w", name, err)
+	}
+	c.deadLetter = &deadLetterQueue{url: aws.ToString(res.QueueUrl), maxReceiveCount: maxReceiveCount}
+	return c.deadLetter, nil
+}
+
+// parseRedrivePolicy returns the name and the account of the dead-letter queue of a redrive policy,
+// and the number of receives of a message before it is moved to it.
+func parseRedrivePolicy(policy string) (name, accountID string, maxReceiveCount int, err error) {
+	var p struct {
+		DeadLetterTargetArn string          `json:"deadLetterTargetArn"`
+		MaxReceiveCount     json.RawMessage `json:"maxReceiveCount"`
+	}
+	if err := json.Unmarshal([]byte(policy), &p); err != nil {
+		return "", "", 0, fmt.Errorf("invalid redrive policy %s: %w", policy, err)
+	}
+	// the max receive count is a number or a string depending on how the policy was set.
+	maxReceiveCount, err = strconv.Atoi(strings.Trim(string(p.MaxReceiveCount), `"`))
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid max receive count in redrive policy %s: %w", policy, err)
+	}
+	// arn:aws:sqs:<region>:<account>:<name>
+	parts := strings.Split(p.DeadLetterTargetArn, ":")
+	if len(parts) != 6 || parts[2] != "sqs" {
+		return "", "", 0, fmt.Errorf("invalid dead-letter target arn %s", p.DeadLetterTargetArn)
+	}
+	return parts[5], parts[4], maxReceiveCount, nil
+}
+
 // GetVisibilityTimeout returns visibility timeout.
 func (c *Consumer) GetVisibilityTimeout() time.Duration {
 	return time.Duration(int64(c.visibilityTimeout) * int64(time.Second))
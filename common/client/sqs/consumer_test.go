@@ -0,0 +1,29 @@
+package sqs
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/assert"
+	"github.com/test-go/testify/require"
+)
+
+func TestParseRedrivePolicy(t *testing.T) {
+	name, accountID, maxReceiveCount, err := parseRedrivePolicy(
+		`{"deadLetterTargetArn":"arn:aws:sqs:us-east-2:123456789012:parser-dlq","maxReceiveCount":4}`)
+	require.NoError(t, err)
+	assert.Equal(t, "parser-dlq", name)
+	assert.Equal(t, "123456789012", accountID)
+	assert.Equal(t, 4, maxReceiveCount)
+
+	// the max receive count set as a string.
+	_, _, maxReceiveCount, err = parseRedrivePolicy(
+		`{"deadLetterTargetArn":"arn:aws:sqs:us-east-2:123456789012:parser-dlq","maxReceiveCount":"10"}`)
+	require.NoError(t, err)
+	assert.Equal(t, 10, maxReceiveCount)
+
+	_, _, _, err = parseRedrivePolicy(`{"deadLetterTargetArn":"parser-dlq","maxReceiveCount":4}`)
+	assert.Error(t, err)
+
+	_, _, _, err = parseRedrivePolicy(`not json`)
+	assert.Error(t, err)
+}
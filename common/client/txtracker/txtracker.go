@@ -17,6 +17,7 @@ var (
 	ErrCallEndpoint  = errors.New("ERROR CALL ENPOINT")
 	ErrBadRequest    = errors.New("BAD REQUEST")
 	ErrInternalError = errors.New("INTERNAL ERROR")
+	ErrNotFound      = errors.New("NOT FOUND")
 )
 
 // TxTrackerAPIClient tx tracker api client.
@@ -137,3 +138,52 @@ func (c *TxTrackerAPIClient) CreateTxHash(vaaID, txHash string) (*TxHashResponse
 	}
 
 }
+
+// SourceTxFunc represent a source tx function.
+type SourceTxFunc func(vaaID, txHash string, timestamp *time.Time) (*TxHashResponse, error)
+
+// SourceTx fetches the source transaction of a vaa by tx hash from its chain, without storing it.
+// It returns ErrNotFound when the transaction does not exist in the chain.
+func (c *TxTrackerAPIClient) SourceTx(vaaID, txHash string, timestamp *time.Time) (*TxHashResponse, error) {
+	endpoint := fmt.Sprintf("%s/vaa/source-tx", c.BaseURL)
+
+	// create request body.
+	payload := struct {
+		VaaID     string     `json:"id"`
+		TxHash    string     `json:"txHash"`
+		Timestamp *time.Time `json:"timestamp,omitempty"`
+	}{
+		VaaID:     vaaID,
+		TxHash:    txHash,
+		Timestamp: timestamp,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		c.Logger.Error("error marshalling payload", zap.Error(err), zap.String("vaaID", vaaID), zap.String("txHash", txHash))
+		return nil, err
+	}
+
+	response, err := c.Client.Post(endpoint, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		c.Logger.Error("error call source tx endpoint",
+			zap.Error(err),
+			zap.String("vaaID", vaaID),
+			zap.String("txHash", txHash))
+		return nil, ErrCallEndpoint
+	}
+
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		var txHashResponse TxHashResponse
+		json.NewDecoder(response.Body).Decode(&txHashResponse)
+		return &txHashResponse, nil
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	case http.StatusBadRequest:
+		return nil, ErrBadRequest
+	default:
+		return nil, ErrInternalError
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"go.uber.org/zap"
 )
 
@@ -35,13 +36,15 @@ func Connect(
 
 	// build mongo options
 	options := options.Client().ApplyURI(uri)
+	cmdMonitor := otelmongo.NewMonitor()
 	if enableQueryLog {
-		cmdMonitor := &event.CommandMonitor{
-			Started: func(_ context.Context, evt *event.CommandStartedEvent) {
-				logger.Info(evt.Command.String())
-			}}
-		options.SetMonitor(cmdMonitor)
+		tracingStarted := cmdMonitor.Started
+		cmdMonitor.Started = func(ctx context.Context, evt *event.CommandStartedEvent) {
+			logger.Info(evt.Command.String())
+			tracingStarted(ctx, evt)
+		}
 	}
+	options.SetMonitor(cmdMonitor)
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(subContext, options)
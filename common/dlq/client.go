@@ -0,0 +1,139 @@
+package dlq
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_sqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	sqs_client "github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
+)
+
+// Client represents a dead-letter queue and the main queue its messages are replayed to.
+type Client struct {
+	api               *aws_sqs.Client
+	dlqUrl            string
+	queueUrl          string
+	visibilityTimeout int32
+}
+
+// NewClient creates a client of a dead-letter queue.
+// The received messages are hidden from other consumers of the dead-letter queue for visibilityTimeout seconds,
+// so that they are received only once while inspecting the queue.
+func NewClient(awsConfig aws.Config, dlqUrl, queueUrl string, visibilityTimeout int32) *Client {
+	return &Client{
+		api:               aws_sqs.NewFromConfig(awsConfig),
+		dlqUrl:            dlqUrl,
+		queueUrl:          queueUrl,
+		visibilityTimeout: visibilityTimeout,
+	}
+}
+
+// Receive receives up to max messages of the dead-letter queue and decodes them with the converter of the service.
+// The messages stay in the queue until they are replayed or deleted.
+func (c *Client) Receive(ctx context.Context, max int, decode DecodeFunc) ([]Message, error) {
+	var messages []Message
+	seen := make(map[string]bool)
+	for len(messages) < max {
+		res, err := c.api.ReceiveMessage(ctx, &aws_sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(c.dlqUrl),
+			MaxNumberOfMessages: int32(min(max-len(messages), 10)),
+			AttributeNames: []aws_sqs_types.QueueAttributeName{
+				aws_sqs_types.QueueAttributeNameAll,
+			},
+			MessageAttributeNames: []string{
+				string(aws_sqs_types.QueueAttributeNameAll),
+			},
+			WaitTimeSeconds:   1,
+			VisibilityTimeout: c.visibilityTimeout,
+		})
+		if err != nil {
+			return messages, fmt.Errorf("failed to receive messages from %s: %w", c.dlqUrl, err)
+		}
+		if len(res.Messages) == 0 {
+			break
+		}
+		for _, msg := range res.Messages {
+			id := aws.ToString(msg.MessageId)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			receiveCount, _ := strconv.Atoi(msg.Attributes[string(aws_sqs_types.MessageSystemAttributeNameApproximateReceiveCount)])
+			message := Message{
+				MessageID:     id,
+				ReceiptHandle: msg.ReceiptHandle,
+				Body:          aws.ToString(msg.Body),
+				Attributes:    msg.MessageAttributes,
+				ReceiveCount:  receiveCount,
+				SentTimestamp: sqs_client.GetSentTimestamp(msg),
+			}
+			message.Event, message.Error = Decode(message.Body, message.Attributes, decode)
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// Replay sends the messages to the main queue and deletes them from the dead-letter queue.
+// The error of the last processing is not sent. It returns the number of messages replayed.
+func (c *Client) Replay(ctx context.Context, messages []Message) (int, error) {
+	for i, msg := range messages {
+		attributes := make(map[string]aws_sqs_types.MessageAttributeValue, len(msg.Attributes))
+		for key, value := range msg.Attributes {
+			if key != sqs_client.LastErrorAttribute {
+				attributes[key] = value
+			}
+		}
+		_, err := c.api.SendMessage(ctx, &aws_sqs.SendMessageInput{
+			QueueUrl:          aws.String(c.queueUrl),
+			MessageBody:       aws.String(msg.Body),
+			MessageAttributes: attributes,
+		})
+		if err != nil {
+			return i, fmt.Errorf("failed to send message %s to %s: %w", msg.MessageID, c.queueUrl, err)
+		}
+		if err := c.delete(ctx, msg); err != nil {
+			return i, err
+		}
+	}
+	return len(messages), nil
+}
+
+// Delete deletes the messages from the dead-letter queue. It returns the number of messages deleted.
+func (c *Client) Delete(ctx context.Context, messages []Message) (int, error) {
+	for i, msg := range messages {
+		if err := c.delete(ctx, msg); err != nil {
+			return i, err
+		}
+	}
+	return len(messages), nil
+}
+
+// Release makes the messages visible again in the dead-letter queue.
+func (c *Client) Release(ctx context.Context, messages []Message) error {
+	for _, msg := range messages {
+		_, err := c.api.ChangeMessageVisibility(ctx, &aws_sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          aws.String(c.dlqUrl),
+			ReceiptHandle:     msg.ReceiptHandle,
+			VisibilityTimeout: 0,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to release message %s: %w", msg.MessageID, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) delete(ctx context.Context, msg Message) error {
+	_, err := c.api.DeleteMessage(ctx, &aws_sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(c.dlqUrl),
+		ReceiptHandle: msg.ReceiptHandle,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete message %s from %s: %w", msg.MessageID, c.dlqUrl, err)
+	}
+	return nil
+}
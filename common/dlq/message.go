@@ -0,0 +1,184 @@
+package dlq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	sqs_client "github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Errors of the messages of a dead-letter queue. SQS does not keep the error of the consumer, so the consumers
+// move the messages on their last receive with the error in the LastError attribute. A message decoded by the
+// converter of the service without that attribute is a message whose processing failed more times than the
+// redrive limit, moved by SQS.
+const (
+	ErrProcessingFailed = "processing failed"
+	ErrUnhandledEvent   = "unhandled event"
+)
+
+// Event is the data of a message decoded by the converter of a service.
+type Event struct {
+	ID      string
+	ChainID sdk.ChainID
+}
+
+// DecodeFunc decodes a message published to the topic of a service.
+// It returns a nil event for the messages the service does not handle.
+type DecodeFunc func(message string) (*Event, error)
+
+// Message represents a message of a dead-letter queue.
+type Message struct {
+	MessageID     string
+	ReceiptHandle *string
+	Body          string
+	Attributes    map[string]aws_sqs_types.MessageAttributeValue
+	ReceiveCount  int
+	SentTimestamp *time.Time
+	// Event is nil when the message can not be decoded.
+	Event *Event
+	Error string
+}
+
+// Decode decodes the body of a message of a dead-letter queue and returns its error, the LastError attribute
+// when the consumer set it. The messages published to SNS topics are wrapped in an envelope, the raw messages
+// are decoded as they are.
+func Decode(body string, attributes map[string]aws_sqs_types.MessageAttributeValue, decode DecodeFunc) (*Event, string) {
+	var envelope struct {
+		Message string `json:"Message"`
+	}
+	message := body
+	if err := json.Unmarshal([]byte(body), &envelope); err == nil && envelope.Message != "" {
+		message = envelope.Message
+	}
+	event, err := decode(message)
+	if err != nil {
+		return nil, err.Error()
+	}
+	if event == nil {
+		return nil, ErrUnhandledEvent
+	}
+	if lastErr, ok := attributes[sqs_client.LastErrorAttribute]; ok && lastErr.StringValue != nil && *lastErr.StringValue != "" {
+		return event, *lastErr.StringValue
+	}
+	return event, ErrProcessingFailed
+}
+
+// Filter selects the messages of a dead-letter queue.
+type Filter struct {
+	ChainID *sdk.ChainID
+	// Error selects the messages whose error contains it.
+	Error string
+	// IDs selects the messages by message id or event id.
+	IDs []string
+}
+
+// Match returns true when the message is selected by the filter.
+func (f Filter) Match(msg Message) bool {
+	if f.ChainID != nil && (msg.Event == nil || msg.Event.ChainID != *f.ChainID) {
+		return false
+	}
+	if f.Error != "" && !strings.Contains(msg.Error, f.Error) {
+		return false
+	}
+	if len(f.IDs) == 0 {
+		return true
+	}
+	for _, id := range f.IDs {
+		if id == msg.MessageID || (msg.Event != nil && id == msg.Event.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Split returns the messages selected by the filter and the rest.
+func (f Filter) Split(messages []Message) (selected, rest []Message) {
+	for _, msg := range messages {
+		if f.Match(msg) {
+			selected = append(selected, msg)
+		} else {
+			rest = append(rest, msg)
+		}
+	}
+	return selected, rest
+}
+
+// Group represents the messages of a dead-letter queue with the same error and chain.
+type Group struct {
+	Error  string
+	Chain  string
+	Count  int
+	Oldest *time.Time
+}
+
+// GroupBy groups the messages by error, the LastError attribute set by the consumer, and chain,
+// sorted by the number of messages.
+func GroupBy(messages []Message) []Group {
+	index := make(map[[2]string]*Group)
+	var groups []*Group
+	for _, msg := range messages {
+		chain := "unknown"
+		if msg.Event != nil {
+			chain = msg.Event.ChainID.String()
+		}
+		key := [2]string{msg.Error, chain}
+		g, ok := index[key]
+		if !ok {
+			g = &Group{Error: msg.Error, Chain: chain}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.Count++
+		if msg.SentTimestamp != nil && (g.Oldest == nil || msg.SentTimestamp.Before(*g.Oldest)) {
+			g.Oldest = msg.SentTimestamp
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	result := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	return result
+}
+
+// PrintGroups writes the groups as a table.
+func PrintGroups(w io.Writer, groups []Group) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COUNT\tCHAIN\tERROR\tOLDEST")
+	for _, g := range groups {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", g.Count, g.Chain, g.Error, formatTime(g.Oldest))
+	}
+	return tw.Flush()
+}
+
+// PrintMessages writes the messages as a table.
+func PrintMessages(w io.Writer, messages []Message) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MESSAGE ID\tEVENT ID\tCHAIN\tRECEIVES\tSENT\tERROR")
+	for _, msg := range messages {
+		eventID, chain := "", "unknown"
+		if msg.Event != nil {
+			eventID, chain = msg.Event.ID, msg.Event.ChainID.String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", msg.MessageID, eventID, chain, msg.ReceiveCount,
+			formatTime(msg.SentTimestamp), msg.Error)
+	}
+	return tw.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
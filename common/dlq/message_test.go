@@ -0,0 +1,108 @@
+package dlq
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/test-go/testify/assert"
+	"github.com/test-go/testify/require"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func decodeVaa(message string) (*Event, error) {
+	var vaa struct {
+		ID      string `json:"id"`
+		ChainID uint16 `json:"emitterChain"`
+	}
+	if err := json.Unmarshal([]byte(message), &vaa); err != nil {
+		return nil, errors.New("invalid vaa")
+	}
+	if vaa.ID == "" {
+		return nil, nil
+	}
+	return &Event{ID: vaa.ID, ChainID: sdk.ChainID(vaa.ChainID)}, nil
+}
+
+func TestDecode(t *testing.T) {
+	// a message published to a SNS topic.
+	event, errMsg := Decode(`{"MessageId":"1","Message":"{\"id\":\"2/abc/1\",\"emitterChain\":2}"}`, nil, decodeVaa)
+	require.NotNil(t, event)
+	assert.Equal(t, "2/abc/1", event.ID)
+	assert.Equal(t, sdk.ChainIDEthereum, event.ChainID)
+	assert.Equal(t, ErrProcessingFailed, errMsg)
+
+	// a raw message.
+	event, errMsg = Decode(`{"id":"1/abc/1","emitterChain":1}`, nil, decodeVaa)
+	require.NotNil(t, event)
+	assert.Equal(t, sdk.ChainIDSolana, event.ChainID)
+	assert.Equal(t, ErrProcessingFailed, errMsg)
+
+	// a message moved by the consumer with the error of its last processing.
+	attributes := map[string]aws_sqs_types.MessageAttributeValue{
+		"LastError": {DataType: aws.String("String"), StringValue: aws.String("failed to fetch tx")},
+	}
+	event, errMsg = Decode(`{"id":"1/abc/1","emitterChain":1}`, attributes, decodeVaa)
+	require.NotNil(t, event)
+	assert.Equal(t, "failed to fetch tx", errMsg)
+
+	// the attribute is ignored when the message can not be decoded.
+	event, errMsg = Decode(`not json`, attributes, decodeVaa)
+	assert.Nil(t, event)
+	assert.Equal(t, "invalid vaa", errMsg)
+
+	event, errMsg = Decode(`{"emitterChain":1}`, nil, decodeVaa)
+	assert.Nil(t, event)
+	assert.Equal(t, ErrUnhandledEvent, errMsg)
+
+	event, errMsg = Decode(`not json`, nil, decodeVaa)
+	assert.Nil(t, event)
+	assert.Equal(t, "invalid vaa", errMsg)
+}
+
+func TestFilter(t *testing.T) {
+	ethereum := sdk.ChainIDEthereum
+	messages := []Message{
+		{MessageID: "m1", Event: &Event{ID: "2/abc/1", ChainID: sdk.ChainIDEthereum}, Error: ErrProcessingFailed},
+		{MessageID: "m2", Event: &Event{ID: "1/abc/1", ChainID: sdk.ChainIDSolana}, Error: ErrProcessingFailed},
+		{MessageID: "m3", Error: "invalid vaa"},
+	}
+
+	selected, rest := Filter{}.Split(messages)
+	assert.Len(t, selected, 3)
+	assert.Empty(t, rest)
+
+	selected, rest = Filter{ChainID: &ethereum}.Split(messages)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "m1", selected[0].MessageID)
+	assert.Len(t, rest, 2)
+
+	selected, _ = Filter{Error: "invalid"}.Split(messages)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "m3", selected[0].MessageID)
+
+	selected, _ = Filter{IDs: []string{"1/abc/1", "m3"}}.Split(messages)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "m2", selected[0].MessageID)
+	assert.Equal(t, "m3", selected[1].MessageID)
+}
+
+func TestGroupBy(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	messages := []Message{
+		{MessageID: "m1", Error: "invalid vaa", SentTimestamp: &t2},
+		{MessageID: "m2", Event: &Event{ChainID: sdk.ChainIDEthereum}, Error: ErrProcessingFailed, SentTimestamp: &t2},
+		{MessageID: "m3", Event: &Event{ChainID: sdk.ChainIDEthereum}, Error: ErrProcessingFailed, SentTimestamp: &t1},
+		{MessageID: "m4", Event: &Event{ChainID: sdk.ChainIDEthereum}, Error: "failed to fetch tx", SentTimestamp: &t1},
+	}
+
+	groups := GroupBy(messages)
+	require.Len(t, groups, 3)
+	assert.Equal(t, Group{Error: ErrProcessingFailed, Chain: "ethereum", Count: 2, Oldest: &t1}, groups[0])
+	assert.Equal(t, Group{Error: "invalid vaa", Chain: "unknown", Count: 1, Oldest: &t2}, groups[1])
+	assert.Equal(t, Group{Error: "failed to fetch tx", Chain: "ethereum", Count: 1, Oldest: &t1}, groups[2])
+}
@@ -0,0 +1,90 @@
+package dlq
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Action is the action to apply to the messages of a dead-letter queue.
+type Action string
+
+const (
+	// ActionList lists the messages, leaving them in the dead-letter queue.
+	ActionList Action = "list"
+	// ActionReplay sends the messages to the main queue and deletes them from the dead-letter queue.
+	ActionReplay Action = "replay"
+	// ActionPurge deletes the messages from the dead-letter queue.
+	ActionPurge Action = "purge"
+)
+
+// Options represents the options of a run over a dead-letter queue.
+type Options struct {
+	Action      Action
+	MaxMessages int
+	Filter      Filter
+	// DryRun reports the messages the action would apply to without applying it.
+	DryRun bool
+}
+
+// Run receives the messages of the dead-letter queue, writes a report of the messages selected by the filter
+// and applies the action to them. The messages the action is not applied to are released back to the queue.
+func Run(ctx context.Context, client *Client, decode DecodeFunc, opts Options, w io.Writer) error {
+	messages, err := client.Receive(ctx, opts.MaxMessages, decode)
+	if err != nil && len(messages) == 0 {
+		return err
+	}
+	selected, rest := opts.Filter.Split(messages)
+
+	// the received messages are hidden until the visibility timeout expires, so release them
+	// back to the queue as soon as we are done with them.
+	pending := messages
+	defer func() {
+		if err := client.Release(context.WithoutCancel(ctx), pending); err != nil {
+			fmt.Fprintf(w, "failed to release messages: %v\n", err)
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "received %d messages, %d selected\n\n", len(messages), len(selected))
+	if err := PrintGroups(w, GroupBy(selected)); err != nil {
+		return err
+	}
+
+	switch opts.Action {
+	case ActionList:
+		fmt.Fprintln(w)
+		return PrintMessages(w, selected)
+	case ActionReplay, ActionPurge:
+	default:
+		return fmt.Errorf("unsupported action: %s", opts.Action)
+	}
+
+	if opts.DryRun {
+		fmt.Fprintf(w, "\ndry run: %d messages would be %s\n\n", len(selected), opts.Action.done())
+		return PrintMessages(w, selected)
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+
+	var count int
+	if opts.Action == ActionReplay {
+		count, err = client.Replay(ctx, selected)
+	} else {
+		count, err = client.Delete(ctx, selected)
+	}
+	// the messages replayed or deleted are not in the queue anymore.
+	pending = append(rest, selected[count:]...)
+	fmt.Fprintf(w, "\n%d of %d messages %s\n", count, len(selected), opts.Action.done())
+	return err
+}
+
+func (a Action) done() string {
+	if a == ActionReplay {
+		return "replayed"
+	}
+	return "purged"
+}
@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	algorand_types "github.com/algorand/go-algorand-sdk/types"
+	"github.com/cosmos/btcutil/bech32"
+	"github.com/mr-tron/base58"
+	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// bech32Prefixes are the human-readable parts of the bech32 chains supported by the explorer.
+var bech32Prefixes = []string{"terra", "inj", "xpla", "sei", "wormhole"}
+
+// ToUniversalAddress converts a native address of a chain into its Wormhole universal address,
+// the 32-byte left-padded representation used in the VAA payloads, encoded as lowercase hex.
+func ToUniversalAddress(chainID sdk.ChainID, address string) (string, error) {
+
+	var nativeHex string
+	var err error
+	switch chainID {
+	// Wormchain addresses use bech32 encoding
+	case sdk.ChainIDWormchain:
+		nativeHex, err = decodeBech32Bytes("wormhole", address)
+	case sdk.ChainIDTerra, sdk.ChainIDTerra2, sdk.ChainIDInjective, sdk.ChainIDXpla, sdk.ChainIDSei:
+		nativeHex, err = decodeBech32Bytes("", address)
+	default:
+		nativeHex, err = DecodeNativeAddressToHex(chainID, address)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	addr, err := sdk.StringToAddress(utils.Remove0x(strings.ToLower(nativeHex)))
+	if err != nil {
+		return "", fmt.Errorf("invalid address %s for chain %d: %w", address, chainID, err)
+	}
+	return hex.EncodeToString(addr[:]), nil
+}
+
+// AddressRepresentations returns every known encoding of an address.
+//
+// The encoding of the input is detected from its format (EVM or universal hex, base58,
+// bech32 or Algorand base32), and the resulting 32-byte universal address is encoded with
+// each of the chain encoders. The input itself is always the first element of the result.
+// An address that cannot be decoded only returns its trivial variations.
+func AddressRepresentations(address string) []string {
+
+	representations := newAddressSet(address)
+
+	universal, ok := decodeAnyAddress(address)
+	if !ok {
+		lower := strings.ToLower(address)
+		representations.add(lower)
+		if !utils.StartsWith0x(lower) {
+			representations.add("0x" + lower)
+		}
+		return representations.values
+	}
+
+	for _, r := range UniversalAddressRepresentations(universal) {
+		representations.add(r)
+	}
+	return representations.values
+}
+
+// UniversalAddressRepresentations returns every known encoding of a 32-byte universal address.
+//
+// The address is only encoded for the chains whose address format matches its length:
+// a left-padded 20-byte address is not a valid address of the 32-byte chains and vice versa.
+func UniversalAddressRepresentations(universal [32]byte) []string {
+
+	representations := newAddressSet(hex.EncodeToString(universal[:]))
+	representations.add("0x" + hex.EncodeToString(universal[:]))
+
+	// 20-byte addresses (EVM, Terra classic, Injective) are left-padded with zeros.
+	if isPadded20(universal) {
+		representations.add("0x" + hex.EncodeToString(universal[12:]))
+		for _, hrp := range []string{"terra", "inj"} {
+			if r, err := encodeBech32(hrp, universal[12:]); err == nil {
+				representations.add(r)
+			}
+		}
+		return representations.values
+	}
+
+	// 32-byte addresses (Solana, Terra2, Xpla, Sei, Wormchain, Algorand, Sui, Aptos).
+	representations.add(base58.Encode(universal[:]))
+	for _, hrp := range []string{"terra", "xpla", "sei", "wormhole"} {
+		if r, err := encodeBech32(hrp, universal[:]); err == nil {
+			representations.add(r)
+		}
+	}
+	var algorandAddr algorand_types.Address
+	copy(algorandAddr[:], universal[:])
+	representations.add(algorandAddr.String())
+
+	return representations.values
+}
+
+// decodeAnyAddress detects the encoding of an address and returns its universal address.
+func decodeAnyAddress(address string) ([32]byte, bool) {
+
+	var universal [32]byte
+	if address == "" {
+		return universal, false
+	}
+
+	// EVM and universal addresses are hex encoded.
+	if raw := utils.Remove0x(address); len(raw) == 40 || len(raw) == 64 {
+		if b, err := hex.DecodeString(raw); err == nil {
+			copy(universal[32-len(b):], b)
+			return universal, true
+		}
+	}
+
+	// bech32 addresses of cosmos-based chains.
+	for _, hrp := range bech32Prefixes {
+		if !strings.HasPrefix(strings.ToLower(address), hrp+"1") {
+			continue
+		}
+		if h, err := decodeBech32Bytes(hrp, address); err == nil {
+			if b, err := hex.DecodeString(h); err == nil && len(b) <= 32 {
+				copy(universal[32-len(b):], b)
+				return universal, true
+			}
+		}
+	}
+
+	// Algorand addresses use base32 encoding with a trailing checksum.
+	if len(address) == 58 {
+		if addr, err := algorand_types.DecodeAddress(address); err == nil {
+			copy(universal[:], addr[:])
+			return universal, true
+		}
+	}
+
+	// Solana addresses use base58 encoding.
+	if b, err := base58.Decode(address); err == nil && len(b) == 32 {
+		copy(universal[:], b)
+		return universal, true
+	}
+
+	return universal, false
+}
+
+// decodeBech32Bytes decodes a bech32 address into the hex encoding of its 8-bit data.
+// An empty prefix accepts any human-readable part.
+func decodeBech32Bytes(prefix, address string) (string, error) {
+
+	hrp, decoded, err := bech32.Decode(address, bech32.MaxLengthBIP173)
+	if err != nil {
+		// cosmwasm contract addresses are longer than the BIP-173 limit.
+		hrp, decoded, err = bech32.DecodeNoLimit(address)
+		if err != nil {
+			return "", fmt.Errorf("bech32 decoding failed: %w", err)
+		}
+	}
+	if prefix != "" && hrp != prefix {
+		return "", fmt.Errorf("bech32 decoding failed, invalid prefix: %s", hrp)
+	}
+
+	data, err := bech32.ConvertBits(decoded, 5, 8, false)
+	if err != nil {
+		return "", fmt.Errorf("bech32 decoding failed: %w", err)
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// isPadded20 reports whether a universal address is a left-padded 20-byte address.
+func isPadded20(universal [32]byte) bool {
+	for _, b := range universal[:12] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// addressSet is an insertion-ordered set of address representations.
+type addressSet struct {
+	seen   map[string]bool
+	values []string
+}
+
+func newAddressSet(first string) *addressSet {
+	s := &addressSet{seen: make(map[string]bool)}
+	s.add(first)
+	return s
+}
+
+func (s *addressSet) add(v string) {
+	if v == "" || s.seen[v] {
+		return
+	}
+	s.seen[v] = true
+	s.values = append(s.values, v)
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/mr-tron/base58"
+	"github.com/test-go/testify/assert"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// TestAddressRepresentations checks that every representation of an address resolves to the same set.
+func TestAddressRepresentations(t *testing.T) {
+
+	tcs := []struct {
+		name    string
+		address string
+		want    []string
+	}{
+		{
+			name:    "evm",
+			address: "0x45dbea4617971d93188eda21530bc6503d153313",
+			want: []string{
+				"00000000000000000000000045dbea4617971d93188eda21530bc6503d153313",
+				"inj1ghd753shjuwexxywmgs4xz7x2q732vcnxxynfn",
+			},
+		},
+		{
+			name:    "injective",
+			address: "inj1ghd753shjuwexxywmgs4xz7x2q732vcnxxynfn",
+			want: []string{
+				"0x45dbea4617971d93188eda21530bc6503d153313",
+				"00000000000000000000000045dbea4617971d93188eda21530bc6503d153313",
+			},
+		},
+		{
+			name:    "solana",
+			address: "Gv1KWf8DT1jKv5pKBmGaTmVszqa56Xn8YGx2Pg7i7qAk",
+			want: []string{
+				"ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5",
+				"0xec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5",
+			},
+		},
+		{
+			name:    "universal",
+			address: "a463ad028fb79679cfc8ce1efba35ac0e77b35080a1abe9bebe83461f176b0a3",
+			want: []string{
+				"terra153366q50k7t8nn7gec00hg66crnhkdggpgdtaxltaq6xrutkkz3s992fw9",
+			},
+		},
+		{
+			name:    "sei",
+			address: "sei1smzlm9t79kur392nu9egl8p8je9j92q4gzguewj56a05kyxxra0qy0nuf3",
+			want: []string{
+				"86c5fd957e2db8389553e1728f9c27964b22a8154091ccba54d75f4b10c61f5e",
+			},
+		},
+		{
+			name:    "algorand",
+			address: "M7UT7JWIVROIDGMQVJZUBQGBNNIIVOYRPC7JWMGQES4KYJIZHVCRZEGFRQ",
+			want: []string{
+				"67e93fa6c8ac5c819990aa7340c0c16b508abb1178be9b30d024b8ac25193d45",
+			},
+		},
+		{
+			name:    "unknown",
+			address: "contract.portalbridge.near",
+			want: []string{
+				"contract.portalbridge.near",
+				"0xcontract.portalbridge.near",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := AddressRepresentations(tc.address)
+			assert.Equal(t, tc.address, got[0])
+			for _, w := range tc.want {
+				assert.Contains(t, got, w)
+			}
+
+		})
+	}
+}
+
+// TestAddressRepresentations_PaddedAddress checks that a 20-byte address is not encoded for the 32-byte chains.
+func TestAddressRepresentations_PaddedAddress(t *testing.T) {
+	universal, err := hex.DecodeString("00000000000000000000000045dbea4617971d93188eda21530bc6503d153313")
+	assert.NoError(t, err)
+
+	got := AddressRepresentations("0x45dbea4617971d93188eda21530bc6503d153313")
+	assert.NotContains(t, got, base58.Encode(universal))
+	for _, r := range got {
+		assert.False(t, strings.HasPrefix(r, "sei1") || strings.HasPrefix(r, "wormhole1") || strings.HasPrefix(r, "xpla1"), r)
+	}
+}
+
+// TestToUniversalAddress contains a test harness for the `ToUniversalAddress` function.
+func TestToUniversalAddress(t *testing.T) {
+
+	tcs := []struct {
+		chainID sdk.ChainID
+		address string
+		want    string
+	}{
+		{
+			chainID: sdk.ChainIDEthereum,
+			address: "0x45DBEA4617971D93188EDA21530BC6503D153313",
+			want:    "00000000000000000000000045dbea4617971d93188eda21530bc6503d153313",
+		},
+		{
+			chainID: sdk.ChainIDSolana,
+			address: "Gv1KWf8DT1jKv5pKBmGaTmVszqa56Xn8YGx2Pg7i7qAk",
+			want:    "ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5",
+		},
+		{
+			chainID: sdk.ChainIDInjective,
+			address: "inj1ghd753shjuwexxywmgs4xz7x2q732vcnxxynfn",
+			want:    "00000000000000000000000045dbea4617971d93188eda21530bc6503d153313",
+		},
+		{
+			chainID: sdk.ChainIDTerra2,
+			address: "terra153366q50k7t8nn7gec00hg66crnhkdggpgdtaxltaq6xrutkkz3s992fw9",
+			want:    "a463ad028fb79679cfc8ce1efba35ac0e77b35080a1abe9bebe83461f176b0a3",
+		},
+	}
+
+	for _, tc := range tcs {
+		got, err := ToUniversalAddress(tc.chainID, tc.address)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, got, "ToUniversalAddress(%s,%s)", tc.chainID.String(), tc.address)
+	}
+}
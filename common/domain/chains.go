@@ -0,0 +1,365 @@
+package domain
+
+import (
+	_ "embed"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	algorand_types "github.com/algorand/go-algorand-sdk/types"
+	"github.com/mr-tron/base58"
+	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+//go:embed chains.json
+var chainsJSON []byte
+
+// DefaultFinalityTime is the finality time of the chains without a finality time in the registry.
+const DefaultFinalityTime = 1066 * time.Second
+
+// ChainFamily groups the chains that share a virtual machine or an address format.
+type ChainFamily string
+
+const (
+	ChainFamilyEVM      ChainFamily = "evm"
+	ChainFamilySolana   ChainFamily = "solana"
+	ChainFamilyCosmos   ChainFamily = "cosmos"
+	ChainFamilyMove     ChainFamily = "move"
+	ChainFamilyAlgorand ChainFamily = "algorand"
+	ChainFamilyNear     ChainFamily = "near"
+	ChainFamilyBitcoin  ChainFamily = "bitcoin"
+)
+
+// Address codecs.
+const (
+	// AddressCodecEVM addresses are the last 20 bytes of the wormhole address, 0x-prefixed hex encoded.
+	AddressCodecEVM = "evm"
+	// AddressCodecBase58 addresses are the wormhole address base58 encoded.
+	AddressCodecBase58 = "base58"
+	// AddressCodecBech32 addresses are the last Length bytes of the wormhole address bech32 encoded with Prefix.
+	AddressCodecBech32 = "bech32"
+	// AddressCodecAlgorand addresses are the wormhole address base32 encoded with a trailing checksum.
+	AddressCodecAlgorand = "algorand"
+	// AddressCodecHex addresses are hex encoded. Emitters are translated with the known emitter accounts.
+	AddressCodecHex = "hex"
+	// AddressCodecAccount addresses are account names. Emitters are translated with the known emitter accounts.
+	AddressCodecAccount = "account"
+)
+
+// Tx hash codecs.
+const (
+	TxHashCodecHex    = "hex"
+	TxHashCodecBase58 = "base58"
+	TxHashCodecBase32 = "base32"
+)
+
+// NativeToken is the token used to pay the fees of a chain.
+type NativeToken struct {
+	Symbol   string `json:"symbol"`
+	Decimals int32  `json:"decimals"`
+}
+
+// AddressCodec describes how the addresses of a chain are encoded.
+type AddressCodec struct {
+	Type   string `json:"type"`
+	Prefix string `json:"prefix,omitempty"`
+	Length int    `json:"length,omitempty"`
+}
+
+// ExplorerURLs are the URL templates of a block explorer. The tx template contains {hash} and
+// the address template contains {address}.
+type ExplorerURLs struct {
+	Tx      string `json:"tx"`
+	Address string `json:"address"`
+}
+
+// ChainInfo is the metadata of a chain.
+type ChainInfo struct {
+	ID     sdk.ChainID `json:"id"`
+	Name   string      `json:"name"`
+	Family ChainFamily `json:"family"`
+	// FinalityTime is the time to finalize a transaction in seconds.
+	// ref: https://docs.wormhole.com/wormhole/reference/constants
+	FinalityTime int64        `json:"finalityTime,omitempty"`
+	NativeToken  *NativeToken `json:"nativeToken,omitempty"`
+	// L1DataFee is true for the rollups whose fee includes an L1 data fee that the gas price does not cover.
+	L1DataFee    bool         `json:"l1DataFee,omitempty"`
+	AddressCodec AddressCodec `json:"addressCodec"`
+	TxHashCodec  string       `json:"txHashCodec"`
+	// Explorer maps the p2p network (mainnet, testnet) to the explorer URL templates.
+	Explorer map[string]ExplorerURLs `json:"explorer,omitempty"`
+}
+
+// Finality returns the time to finalize a transaction in the chain.
+func (c *ChainInfo) Finality() time.Duration {
+	if c.FinalityTime <= 0 {
+		return DefaultFinalityTime
+	}
+	return time.Duration(c.FinalityTime) * time.Second
+}
+
+// TxURL returns the explorer URL of a transaction, or an empty string if the chain has no explorer in the network.
+func (c *ChainInfo) TxURL(p2pNetwork, txHash string) string {
+	e, ok := c.Explorer[p2pNetwork]
+	if !ok || e.Tx == "" {
+		return ""
+	}
+	return strings.ReplaceAll(e.Tx, "{hash}", txHash)
+}
+
+// AddressURL returns the explorer URL of an address, or an empty string if the chain has no explorer in the network.
+func (c *ChainInfo) AddressURL(p2pNetwork, address string) string {
+	e, ok := c.Explorer[p2pNetwork]
+	if !ok || e.Address == "" {
+		return ""
+	}
+	return strings.ReplaceAll(e.Address, "{address}", address)
+}
+
+// clone returns a deep copy of the chain, so that an override does not modify the chains already returned.
+func (c *ChainInfo) clone() *ChainInfo {
+	copied := *c
+	if c.NativeToken != nil {
+		token := *c.NativeToken
+		copied.NativeToken = &token
+	}
+	if c.Explorer != nil {
+		copied.Explorer = make(map[string]ExplorerURLs, len(c.Explorer))
+		for network, urls := range c.Explorer {
+			copied.Explorer[network] = urls
+		}
+	}
+	return &copied
+}
+
+// ChainRegistry holds the metadata of the chains.
+type ChainRegistry struct {
+	mu     sync.RWMutex
+	chains map[sdk.ChainID]*ChainInfo
+}
+
+// Chains is the registry of the chains, loaded from the embedded chains.json file.
+var Chains = mustLoadChainRegistry()
+
+func mustLoadChainRegistry() *ChainRegistry {
+	r, err := NewChainRegistry(chainsJSON)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load chain registry: %v", err))
+	}
+	return r
+}
+
+// NewChainRegistry creates a registry from a JSON array of chains.
+func NewChainRegistry(data []byte) (*ChainRegistry, error) {
+	var chains []*ChainInfo
+	if err := json.Unmarshal(data, &chains); err != nil {
+		return nil, err
+	}
+	r := &ChainRegistry{chains: make(map[sdk.ChainID]*ChainInfo, len(chains))}
+	for _, c := range chains {
+		if _, ok := r.chains[c.ID]; ok {
+			return nil, fmt.Errorf("duplicated chain %d", c.ID)
+		}
+		r.chains[c.ID] = c
+	}
+	return r, nil
+}
+
+// Override merges a JSON array of chains into the registry. Each element must have an id, the
+// fields present replace the ones of the chain and the chains not in the registry are added.
+func (r *ChainRegistry) Override(data []byte) error {
+	var overrides []json.RawMessage
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, raw := range overrides {
+		var id struct {
+			ID *sdk.ChainID `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &id); err != nil {
+			return err
+		}
+		if id.ID == nil {
+			return fmt.Errorf("chain override without id: %s", raw)
+		}
+		c := &ChainInfo{}
+		if current, ok := r.chains[*id.ID]; ok {
+			c = current.clone()
+		}
+		if err := json.Unmarshal(raw, c); err != nil {
+			return err
+		}
+		r.chains[c.ID] = c
+	}
+	return nil
+}
+
+// LoadOverrides merges the chains of a JSON file into the registry. An empty file name is ignored.
+func (r *ChainRegistry) LoadOverrides(file string) error {
+	if file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read chain overrides: %w", err)
+	}
+	if err := r.Override(data); err != nil {
+		return fmt.Errorf("failed to parse chain overrides: %w", err)
+	}
+	return nil
+}
+
+// Get returns the metadata of a chain.
+func (r *ChainRegistry) Get(chainID sdk.ChainID) (*ChainInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.chains[chainID]
+	return c, ok
+}
+
+// All returns the metadata of all the chains ordered by id.
+func (r *ChainRegistry) All() []*ChainInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	chains := make([]*ChainInfo, 0, len(r.chains))
+	for _, c := range r.chains {
+		chains = append(chains, c)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].ID < chains[j].ID })
+	return chains
+}
+
+// IsEVM returns true if the chain belongs to the EVM family.
+func (r *ChainRegistry) IsEVM(chainID sdk.ChainID) bool {
+	c, ok := r.Get(chainID)
+	return ok && c.Family == ChainFamilyEVM
+}
+
+// FinalityTime returns the time to finalize a transaction in the chain.
+func (r *ChainRegistry) FinalityTime(chainID sdk.ChainID) time.Duration {
+	c, ok := r.Get(chainID)
+	if !ok {
+		return DefaultFinalityTime
+	}
+	return c.Finality()
+}
+
+// EncodeTxHash encodes a transaction hash with the codec of the chain.
+func (r *ChainRegistry) EncodeTxHash(chainID sdk.ChainID, txHash []byte) (string, error) {
+	c, ok := r.Get(chainID)
+	if !ok {
+		return hex.EncodeToString(txHash), fmt.Errorf("unknown chain id: %d", chainID)
+	}
+	switch c.TxHashCodec {
+	case TxHashCodecBase58:
+		return base58.Encode(txHash), nil
+	case TxHashCodecBase32:
+		return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(txHash), nil
+	default:
+		return hex.EncodeToString(txHash), nil
+	}
+}
+
+// NormalizeTxHash returns the transaction hashes of the EVM chains in lowercase and without
+// the 0x prefix. The other transaction hashes are returned unchanged.
+func (r *ChainRegistry) NormalizeTxHash(chainID sdk.ChainID, txHash string) string {
+	if !r.IsEVM(chainID) {
+		return txHash
+	}
+	return utils.Remove0x(strings.ToLower(txHash))
+}
+
+// TranslateEmitterAddress converts an emitter address into the native address of the chain.
+func (r *ChainRegistry) TranslateEmitterAddress(chainID sdk.ChainID, address string) (string, error) {
+
+	// Decode the address from hex
+	addressBytes, err := hex.DecodeString(address)
+	if err != nil {
+		return "", fmt.Errorf(`failed to decode emitter address "%s" from hex: %w`, address, err)
+	}
+	if len(addressBytes) != 32 {
+		return "", fmt.Errorf("expected emitter address length to be 32: %s", address)
+	}
+
+	c, ok := r.Get(chainID)
+	if !ok {
+		return "", fmt.Errorf("can't translate emitter address: ChainID=%d not supported", chainID)
+	}
+
+	switch c.AddressCodec.Type {
+
+	case AddressCodecBase58:
+		return base58.Encode(addressBytes), nil
+
+	case AddressCodecEVM:
+		return "0x" + hex.EncodeToString(addressBytes[12:]), nil
+
+	case AddressCodecBech32:
+		data := addressBytes
+		if l := c.AddressCodec.Length; l > 0 && l < len(addressBytes) {
+			data = addressBytes[len(addressBytes)-l:]
+		}
+		return encodeBech32(c.AddressCodec.Prefix, data)
+
+	// We're using the SDK to handle the checksum logic.
+	case AddressCodecAlgorand:
+		var addr algorand_types.Address
+		copy(addr[:], addressBytes[:])
+		return addr.String(), nil
+
+	// The emitters of these chains are derived from the program address or are capabilities of the
+	// core bridge, so we're using a hashmap of known emitters to avoid querying external APIs.
+	case AddressCodecHex, AddressCodecAccount:
+		if nativeAddress, ok := knownEmitterAccounts[chainID][address]; ok {
+			return nativeAddress, nil
+		}
+		return "", fmt.Errorf(`no mapping found for %s emitter address "%s"`, c.Name, address)
+
+	default:
+		return "", fmt.Errorf("can't translate emitter address: ChainID=%d not supported", chainID)
+	}
+}
+
+// DecodeNativeAddressToHex decodes a native address of the chain to hex.
+func (r *ChainRegistry) DecodeNativeAddressToHex(chainID sdk.ChainID, address string) (string, error) {
+	c, ok := r.Get(chainID)
+	if !ok {
+		return "", fmt.Errorf("can't translate emitter address: ChainID=%d not supported", chainID)
+	}
+
+	switch c.AddressCodec.Type {
+
+	case AddressCodecBase58:
+		addr, err := base58.Decode(address)
+		if err != nil {
+			return "", fmt.Errorf("base58 decoding failed: %w", err)
+		}
+		return hex.EncodeToString(addr), nil
+
+	case AddressCodecEVM, AddressCodecHex:
+		return address, nil
+
+	case AddressCodecBech32:
+		return decodeBech32(c.AddressCodec.Prefix, address)
+
+	case AddressCodecAlgorand:
+		addr, err := algorand_types.DecodeAddress(address)
+		if err != nil {
+			return "", fmt.Errorf("algorand decoding failed: %w", err)
+		}
+		return hex.EncodeToString(addr[:]), nil
+
+	default:
+		return "", fmt.Errorf("can't translate emitter address: ChainID=%d not supported", chainID)
+	}
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/assert"
+	"github.com/test-go/testify/require"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// chainIDUnichain is not defined by the pinned version of the sdk.
+const chainIDUnichain = sdk.ChainID(44)
+
+func TestChainRegistry(t *testing.T) {
+	r, err := NewChainRegistry(chainsJSON)
+	require.NoError(t, err)
+
+	eth, ok := r.Get(sdk.ChainIDEthereum)
+	require.True(t, ok)
+	assert.Equal(t, ChainFamilyEVM, eth.Family)
+	assert.Equal(t, int32(18), eth.NativeToken.Decimals)
+	assert.Equal(t, "https://etherscan.io/tx/0xabc", eth.TxURL("mainnet", "0xabc"))
+	assert.Equal(t, "", eth.TxURL("devnet", "0xabc"))
+
+	assert.Equal(t, 975*time.Second, r.FinalityTime(sdk.ChainIDEthereum))
+	assert.Equal(t, DefaultFinalityTime, r.FinalityTime(sdk.ChainIDAurora))
+	assert.Equal(t, DefaultFinalityTime, r.FinalityTime(sdk.ChainIDUnset))
+
+	assert.True(t, r.IsEVM(sdk.ChainIDBase))
+	assert.False(t, r.IsEVM(sdk.ChainIDWormchain))
+	assert.Equal(t, "abcd", r.NormalizeTxHash(sdk.ChainIDEthereum, "0xABCD"))
+	assert.Equal(t, "ABCD", r.NormalizeTxHash(sdk.ChainIDSolana, "ABCD"))
+
+	all := r.All()
+	for i := 1; i < len(all); i++ {
+		assert.True(t, all[i-1].ID < all[i].ID)
+	}
+}
+
+func TestChainRegistryOverride(t *testing.T) {
+	r, err := NewChainRegistry(chainsJSON)
+	require.NoError(t, err)
+
+	err = r.Override([]byte(`[
+		{"id": 2, "finalityTime": 60},
+		{"id": 60000, "name": "Devnet", "family": "evm", "addressCodec": {"type": "evm"}, "txHashCodec": "hex"}
+	]`))
+	require.NoError(t, err)
+
+	eth, ok := r.Get(sdk.ChainIDEthereum)
+	require.True(t, ok)
+	assert.Equal(t, time.Minute, eth.Finality())
+	// the fields not present in the override are kept.
+	assert.Equal(t, "Ethereum", eth.Name)
+	assert.Equal(t, "ETH", eth.NativeToken.Symbol)
+
+	devnet, ok := r.Get(sdk.ChainID(60000))
+	require.True(t, ok)
+	assert.Equal(t, "Devnet", devnet.Name)
+	address, err := r.TranslateEmitterAddress(devnet.ID, "0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585")
+	require.NoError(t, err)
+	assert.Equal(t, "0x3ee18b2214aff97000d974cf647e7c347e8fa585", address)
+
+	assert.Error(t, r.Override([]byte(`[{"name": "no id"}]`)))
+}
+
+func TestChainRegistryNormalizeTxHash(t *testing.T) {
+	r, err := NewChainRegistry(chainsJSON)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		chainID sdk.ChainID
+		txHash  string
+		want    string
+	}{
+		{chainID: sdk.ChainIDEthereum, txHash: "0xB911CBFB0E42C504", want: "b911cbfb0e42c504"},
+		{chainID: sdk.ChainIDEthereum, txHash: "b911cbfb0e42c504", want: "b911cbfb0e42c504"},
+		{chainID: sdk.ChainIDAurora, txHash: "0xB911CBFB0E42C504", want: "b911cbfb0e42c504"},
+		{chainID: sdk.ChainIDHolesky, txHash: "0xB911CBFB0E42C504", want: "b911cbfb0e42c504"},
+		// berachain and unichain are evm chains in the registry, their hashes were kept unchanged before.
+		{chainID: sdk.ChainIDBerachain, txHash: "0xB911CBFB0E42C504", want: "b911cbfb0e42c504"},
+		{chainID: chainIDUnichain, txHash: "0xB911CBFB0E42C504", want: "b911cbfb0e42c504"},
+		// wormchain uses evm emitter addresses but it is a cosmos chain.
+		{chainID: sdk.ChainIDWormchain, txHash: "0xB911CBFB0E42C504", want: "0xB911CBFB0E42C504"},
+		{chainID: sdk.ChainIDSolana, txHash: "3QFeCHsG9WDXzMozWyck8RUxmw59jyj7MPnQd4w2mbDL", want: "3QFeCHsG9WDXzMozWyck8RUxmw59jyj7MPnQd4w2mbDL"},
+		{chainID: sdk.ChainIDSui, txHash: "3pnJrxdjJeDUSvAquDiidApuRLXp5jATdLPyLhjrJsv5", want: "3pnJrxdjJeDUSvAquDiidApuRLXp5jATdLPyLhjrJsv5"},
+		{chainID: sdk.ChainIDAptos, txHash: "0xB911CBFB0E42C504", want: "0xB911CBFB0E42C504"},
+		{chainID: sdk.ChainIDTerra2, txHash: "B911CBFB0E42C504", want: "B911CBFB0E42C504"},
+		{chainID: sdk.ChainID(60001), txHash: "0xB911CBFB0E42C504", want: "0xB911CBFB0E42C504"},
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.want, r.NormalizeTxHash(tc.chainID, tc.txHash), "chain %d", tc.chainID)
+	}
+}
+
+func TestChainRegistryEncodeTxHash(t *testing.T) {
+	r, err := NewChainRegistry(chainsJSON)
+	require.NoError(t, err)
+
+	solanaTxHash := []byte{0x23, 0xac, 0x49, 0x94, 0x37, 0xa8, 0xe6, 0x53, 0x3b, 0x79, 0x0d, 0x55, 0x78, 0xaf, 0x5d, 0x39, 0xb3, 0x49, 0x88, 0x31, 0x88, 0xec, 0xa5, 0x35, 0xb9, 0x57, 0xd8, 0x2a, 0x0e, 0x77, 0xeb, 0x03}
+	algorandTxHash := []byte{0xd3, 0x45, 0x59, 0x5e, 0x2a, 0x0f, 0xab, 0x5c, 0xde, 0x71, 0x20, 0xb6, 0xbe, 0xb6, 0xee, 0x0b, 0xb9, 0x4b, 0x57, 0x8a, 0xa5, 0x69, 0x95, 0x2d, 0x00, 0x0c, 0xe8, 0xbf, 0xef, 0x03, 0x2d, 0x22}
+	nearTxHash := []byte{0x02, 0xde, 0x67, 0xd0, 0x15, 0x34, 0x02, 0x1c, 0x0e, 0x5b, 0x17, 0x68, 0x6e, 0x1e, 0x70, 0xd4, 0x79, 0x39, 0x6d, 0xa2, 0x9d, 0x1e, 0xbc, 0xe4, 0x9a, 0x4c, 0xad, 0xda, 0x4b, 0xca, 0xa3, 0x2b}
+	suiTxHash := []byte{0x29, 0xf4, 0xe6, 0xd8, 0xe0, 0xbf, 0x65, 0x21, 0xe5, 0xf3, 0x30, 0x28, 0x73, 0xa1, 0xf0, 0x08, 0x65, 0xb7, 0xcf, 0xe0, 0x48, 0x36, 0x73, 0x4d, 0x74, 0xed, 0x8c, 0x99, 0x6e, 0x7a, 0x07, 0x86}
+	evmTxHash := []byte{0xb9, 0x11, 0xcb, 0xfb, 0x0e, 0x42, 0xc5, 0x04, 0x77, 0x2b, 0xe9, 0x16, 0xbb, 0xeb, 0x8a, 0x46, 0xfc, 0xe7, 0x2b, 0xe5, 0xc6, 0x11, 0x28, 0xe7, 0x12, 0x93, 0x68, 0x26, 0x32, 0x88, 0xcc, 0x7d}
+	evmWant := "b911cbfb0e42c504772be916bbeb8a46fce72be5c61128e7129368263288cc7d"
+
+	tcs := []struct {
+		chainID sdk.ChainID
+		txHash  []byte
+		want    string
+		err     bool
+	}{
+		{chainID: sdk.ChainIDSolana, txHash: solanaTxHash, want: "3QFeCHsG9WDXzMozWyck8RUxmw59jyj7MPnQd4w2mbDL"},
+		{chainID: sdk.ChainIDAlgorand, txHash: algorandTxHash, want: "2NCVSXRKB6VVZXTREC3L5NXOBO4UWV4KUVUZKLIABTUL73YDFURA"},
+		{chainID: sdk.ChainIDNear, txHash: nearTxHash, want: "CCWhFHoDg5eycFJC7EHbYXnNdXW1ed8tjdNHCLbYZEa"},
+		{chainID: sdk.ChainIDSui, txHash: suiTxHash, want: "3pnJrxdjJeDUSvAquDiidApuRLXp5jATdLPyLhjrJsv5"},
+		{chainID: sdk.ChainIDEthereum, txHash: evmTxHash, want: evmWant},
+		{chainID: sdk.ChainIDAurora, txHash: evmTxHash, want: evmWant},
+		{chainID: sdk.ChainIDHolesky, txHash: evmTxHash, want: evmWant},
+		{chainID: sdk.ChainIDPolygonSepolia, txHash: evmTxHash, want: evmWant},
+		{chainID: sdk.ChainIDBerachain, txHash: evmTxHash, want: evmWant},
+		{chainID: chainIDUnichain, txHash: evmTxHash, want: evmWant},
+		{chainID: sdk.ChainIDAptos, txHash: evmTxHash, want: evmWant},
+		{chainID: sdk.ChainIDTerra2, txHash: evmTxHash, want: evmWant},
+		{chainID: sdk.ChainIDWormchain, txHash: evmTxHash, want: evmWant},
+		{chainID: sdk.ChainIDBtc, txHash: evmTxHash, want: evmWant},
+		// the unknown chains are encoded in hex with an error.
+		{chainID: sdk.ChainID(60001), txHash: evmTxHash, want: evmWant, err: true},
+	}
+
+	for _, tc := range tcs {
+		got, err := r.EncodeTxHash(tc.chainID, tc.txHash)
+		assert.Equal(t, tc.want, got, "chain %d", tc.chainID)
+		assert.Equal(t, tc.err, err != nil, "chain %d: %v", tc.chainID, err)
+	}
+}
+
+func TestChainRegistryTranslateEmitterAddress(t *testing.T) {
+	r, err := NewChainRegistry(chainsJSON)
+	require.NoError(t, err)
+
+	evmEmitter := "0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585"
+	evmWant := "0x3ee18b2214aff97000d974cf647e7c347e8fa585"
+
+	tcs := []struct {
+		chainID sdk.ChainID
+		address string
+		want    string
+		err     bool
+	}{
+		{chainID: sdk.ChainIDSolana, address: "ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5", want: "Gv1KWf8DT1jKv5pKBmGaTmVszqa56Xn8YGx2Pg7i7qAk"},
+		{chainID: sdk.ChainIDEthereum, address: evmEmitter, want: evmWant},
+		{chainID: sdk.ChainIDAurora, address: evmEmitter, want: evmWant},
+		{chainID: sdk.ChainIDHolesky, address: evmEmitter, want: evmWant},
+		// berachain and unichain emitters could not be translated before.
+		{chainID: sdk.ChainIDBerachain, address: evmEmitter, want: evmWant},
+		{chainID: chainIDUnichain, address: evmEmitter, want: evmWant},
+		{chainID: sdk.ChainIDWormchain, address: evmEmitter, want: evmWant},
+		{chainID: sdk.ChainIDTerra, address: "0000000000000000000000007cf7b764e38a0a5e967972c1df77d432510564e2", want: "terra10nmmwe8r3g99a9newtqa7a75xfgs2e8z87r2sf"},
+		{chainID: sdk.ChainIDTerra2, address: "a463ad028fb79679cfc8ce1efba35ac0e77b35080a1abe9bebe83461f176b0a3", want: "terra153366q50k7t8nn7gec00hg66crnhkdggpgdtaxltaq6xrutkkz3s992fw9"},
+		{chainID: sdk.ChainIDInjective, address: "00000000000000000000000045dbea4617971d93188eda21530bc6503d153313", want: "inj1ghd753shjuwexxywmgs4xz7x2q732vcnxxynfn"},
+		{chainID: sdk.ChainIDSei, address: "86c5fd957e2db8389553e1728f9c27964b22a8154091ccba54d75f4b10c61f5e", want: "sei1smzlm9t79kur392nu9egl8p8je9j92q4gzguewj56a05kyxxra0qy0nuf3"},
+		{chainID: sdk.ChainIDAlgorand, address: "67e93fa6c8ac5c819990aa7340c0c16b508abb1178be9b30d024b8ac25193d45", want: "M7UT7JWIVROIDGMQVJZUBQGBNNIIVOYRPC7JWMGQES4KYJIZHVCRZEGFRQ"},
+		{chainID: sdk.ChainIDNear, address: "148410499d3fcda4dcfd68a1ebfcdddda16ab28326448d4aae4d2f0465cdfcb7", want: "contract.portalbridge.near"},
+		{chainID: sdk.ChainIDSui, address: "ccceeb29348f71bdd22ffef43a2a19c1f5b5e17c5cca5411529120182672ade5", want: "0xc57508ee0d4595e5a8728974a4a93a787d38f339757230d441e895422c07aba9"},
+		{chainID: sdk.ChainIDAptos, address: "0000000000000000000000000000000000000000000000000000000000000001", want: "0x576410486a2da45eee6c949c995670112ddf2fbeedab20350d506328eefc9d4f"},
+		// the emitters without a known account can't be translated.
+		{chainID: sdk.ChainIDAptos, address: "00000000000000000000000000000000000000000000000000000000000000ff", err: true},
+		// bitcoin has no address codec.
+		{chainID: sdk.ChainIDBtc, address: evmEmitter, err: true},
+		{chainID: sdk.ChainID(60001), address: evmEmitter, err: true},
+		{chainID: sdk.ChainIDEthereum, address: "3ee18b2214aff97000d974cf647e7c347e8fa585", err: true},
+		{chainID: sdk.ChainIDEthereum, address: "not hex", err: true},
+	}
+
+	for _, tc := range tcs {
+		got, err := r.TranslateEmitterAddress(tc.chainID, tc.address)
+		assert.Equal(t, tc.want, got, "chain %d", tc.chainID)
+		assert.Equal(t, tc.err, err != nil, "chain %d: %v", tc.chainID, err)
+	}
+}
@@ -10,6 +10,7 @@ const (
 const (
 	AppIdUnkonwn           = "UNKONWN"
 	AppIdPortalTokenBridge = "PORTAL_TOKEN_BRIDGE"
+	AppIdPortalNFTBridge   = "PORTAL_NFT_BRIDGE"
 )
 
 // SourceTxStatus is meant to be a user-facing enum that describes the status of the source transaction.
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// KnownEmitter is a well-known emitter used to seed the emitter registry.
+type KnownEmitter struct {
+	ChainID sdk.ChainID
+	// Address is the 32-byte emitter address, hex encoded without the 0x prefix.
+	Address string
+	AppID   string
+	Name    string
+	Owner   string
+}
+
+// mainnetKnownEmitters contains the Portal emitters of mainnet.
+var mainnetKnownEmitters = []KnownEmitter{
+	{ChainID: sdk.ChainIDSolana, Address: "ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDEthereum, Address: "0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDBSC, Address: "000000000000000000000000b6f6d86a8f9879a9c87f643768d9efc38c1da6e7", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDPolygon, Address: "0000000000000000000000005a58505a96d1dbf8df91cb21b54419fc36e93fde", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDAvalanche, Address: "0000000000000000000000000e082f06ff657d94310cb8ce8b0d9a04541d8052", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDNear, Address: "148410499d3fcda4dcfd68a1ebfcdddda16ab28326448d4aae4d2f0465cdfcb7", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDSui, Address: "ccceeb29348f71bdd22ffef43a2a19c1f5b5e17c5cca5411529120182672ade5", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDAptos, Address: "0000000000000000000000000000000000000000000000000000000000000001", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDAptos, Address: "0000000000000000000000000000000000000000000000000000000000000005", AppID: AppIdPortalNFTBridge, Name: "Portal NFT Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.GovernanceChain, Address: sdk.GovernanceEmitter.String(), AppID: AppIdUnkonwn, Name: "Guardian Governance", Owner: "Wormhole"},
+}
+
+// testnetKnownEmitters contains the Portal emitters of testnet.
+var testnetKnownEmitters = []KnownEmitter{
+	{ChainID: sdk.ChainIDAptos, Address: "0000000000000000000000000000000000000000000000000000000000000001", AppID: AppIdPortalTokenBridge, Name: "Portal Token Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.ChainIDAptos, Address: "0000000000000000000000000000000000000000000000000000000000000005", AppID: AppIdPortalNFTBridge, Name: "Portal NFT Bridge", Owner: "Wormhole"},
+	{ChainID: sdk.GovernanceChain, Address: sdk.GovernanceEmitter.String(), AppID: AppIdUnkonwn, Name: "Guardian Governance", Owner: "Wormhole"},
+}
+
+// GetKnownEmitters returns the well-known emitters of the given p2p network.
+func GetKnownEmitters(p2pNetwork string) []KnownEmitter {
+	switch p2pNetwork {
+	case P2pMainNet:
+		return mainnetKnownEmitters
+	case P2pTestNet:
+		return testnetKnownEmitters
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetKnownEmitters(t *testing.T) {
+	for _, network := range []string{P2pMainNet, P2pTestNet} {
+		seen := make(map[string]bool)
+		for _, e := range GetKnownEmitters(network) {
+			key := e.ChainID.String() + "/" + e.Address
+			assert.False(t, seen[key], "duplicated emitter %s in %s", key, network)
+			seen[key] = true
+
+			assert.Len(t, e.Address, 64, "emitter %s in %s", key, network)
+			_, err := TranslateEmitterAddress(e.ChainID, e.Address)
+			assert.NoError(t, err, "emitter %s in %s", key, network)
+		}
+	}
+	assert.Empty(t, GetKnownEmitters(P2pDevNet))
+}
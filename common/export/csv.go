@@ -0,0 +1,60 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvWriter writes records as CSV with a header row. Nil values are written as empty strings.
+type csvWriter struct {
+	columns []Column
+	w       *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	cw := &csvWriter{columns: columns, w: csv.NewWriter(w)}
+	header := make([]string, 0, len(columns))
+	for _, c := range columns {
+		header = append(header, c.Name)
+	}
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(record []any) error {
+	if err := checkRecord(cw.columns, record); err != nil {
+		return err
+	}
+	row := make([]string, 0, len(record))
+	for _, v := range record {
+		row = append(row, formatValue(v))
+	}
+	return cw.w.Write(row)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// formatValue formats a value as text.
+func formatValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case time.Time:
+		return t.UTC().Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
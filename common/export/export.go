@@ -0,0 +1,127 @@
+// Package export writes tabular records (e.g.: transfers) in the bulk export formats
+// supported by the explorer: CSV, JSON lines and Parquet.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSONL   Format = "jsonl"
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat parses a format name. The comparison is case-insensitive.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatJSONL, FormatParquet:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid export format: %s", s)
+	}
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Extension returns the file extension of the format, without the leading dot.
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// ColumnType is the type of the values of a column.
+type ColumnType int
+
+const (
+	// ColumnString columns hold string values.
+	ColumnString ColumnType = iota
+	// ColumnInt64 columns hold int64 values.
+	ColumnInt64
+	// ColumnFloat64 columns hold float64 values.
+	ColumnFloat64
+	// ColumnTimestamp columns hold time.Time values.
+	ColumnTimestamp
+)
+
+// Column describes a column of the records.
+type Column struct {
+	Name string
+	Type ColumnType
+	// Optional columns accept nil values.
+	Optional bool
+}
+
+// Writer writes records into an export file.
+//
+// Each record has one value per column, in the same order as the columns. The value
+// must match the type of the column, or be nil if the column is optional.
+type Writer interface {
+	Write(record []any) error
+	// Close flushes the buffered records and writes the file trailer, if any.
+	// It does not close the underlying io.Writer.
+	Close() error
+}
+
+// NewWriter creates a Writer for the given format.
+func NewWriter(format Format, w io.Writer, columns []Column) (Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("at least one column is required")
+	}
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatJSONL:
+		return newJSONLWriter(w, columns), nil
+	case FormatParquet:
+		return newParquetWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("invalid export format: %s", format)
+	}
+}
+
+// checkRecord validates the values of a record against the columns.
+func checkRecord(columns []Column, record []any) error {
+	if len(record) != len(columns) {
+		return fmt.Errorf("expected %d values, got %d", len(columns), len(record))
+	}
+	for i, c := range columns {
+		v := record[i]
+		if v == nil {
+			if !c.Optional {
+				return fmt.Errorf("column %s is not optional", c.Name)
+			}
+			continue
+		}
+		var ok bool
+		switch c.Type {
+		case ColumnString:
+			_, ok = v.(string)
+		case ColumnInt64:
+			_, ok = v.(int64)
+		case ColumnFloat64:
+			_, ok = v.(float64)
+		case ColumnTimestamp:
+			_, ok = v.(time.Time)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value type %T for column %s", v, c.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var testColumns = []Column{
+	{Name: "id", Type: ColumnString},
+	{Name: "chain", Type: ColumnInt64, Optional: true},
+	{Name: "usd", Type: ColumnFloat64, Optional: true},
+	{Name: "timestamp", Type: ColumnTimestamp},
+}
+
+var testTimestamp = time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+func writeTestRecords(t *testing.T, format Format) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, testColumns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records := [][]any{
+		{"2/0001/1", int64(2), 10.5, testTimestamp},
+		{"1/0002/7", nil, nil, testTimestamp},
+		{"4/0003/9", int64(4), nil, testTimestamp},
+	}
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	got := string(writeTestRecords(t, FormatCSV))
+	want := "id,chain,usd,timestamp\n" +
+		"2/0001/1,2,10.5,2024-05-01T10:30:00Z\n" +
+		"1/0002/7,,,2024-05-01T10:30:00Z\n" +
+		"4/0003/9,4,,2024-05-01T10:30:00Z\n"
+	if got != want {
+		t.Errorf("unexpected csv:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	got := string(writeTestRecords(t, FormatJSONL))
+	want := `{"id":"2/0001/1","chain":2,"usd":10.5,"timestamp":"2024-05-01T10:30:00Z"}` + "\n" +
+		`{"id":"1/0002/7","chain":null,"usd":null,"timestamp":"2024-05-01T10:30:00Z"}` + "\n" +
+		`{"id":"4/0003/9","chain":4,"usd":null,"timestamp":"2024-05-01T10:30:00Z"}` + "\n"
+	if got != want {
+		t.Errorf("unexpected jsonl:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriter_InvalidRecord(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, testColumns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Write([]any{"id"}); err == nil {
+		t.Error("expected error for a record with missing values")
+	}
+	if err := w.Write([]any{nil, nil, nil, testTimestamp}); err == nil {
+		t.Error("expected error for a nil value in a required column")
+	}
+	if err := w.Write([]any{"id", 2, nil, testTimestamp}); err == nil {
+		t.Error("expected error for a value of the wrong type")
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	data := writeTestRecords(t, FormatParquet)
+
+	if string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		t.Fatalf("missing parquet magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4]))
+	footer := data[len(data)-8-footerLen : len(data)-8]
+
+	r := &thriftReader{data: footer}
+	metadata, err := r.readStruct()
+	if err != nil {
+		t.Fatalf("failed to decode footer: %v", err)
+	}
+
+	if numRows := metadata[3].(int64); numRows != 3 {
+		t.Errorf("expected 3 rows, got %d", numRows)
+	}
+	schema := metadata[2].([]any)
+	if len(schema) != len(testColumns)+1 {
+		t.Fatalf("expected %d schema elements, got %d", len(testColumns)+1, len(schema))
+	}
+	for i, c := range testColumns {
+		element := schema[i+1].(map[int16]any)
+		if name := string(element[4].([]byte)); name != c.Name {
+			t.Errorf("expected column %s, got %s", c.Name, name)
+		}
+	}
+
+	// decode the values of the first column.
+	rowGroups := metadata[4].([]any)
+	chunk := rowGroups[0].(map[int16]any)[1].([]any)[0].(map[int16]any)
+	offset := chunk[3].(map[int16]any)[9].(int64)
+	page := &thriftReader{data: data[offset:]}
+	header, err := page.readStruct()
+	if err != nil {
+		t.Fatalf("failed to decode page header: %v", err)
+	}
+	body := page.data[page.pos : page.pos+int(header[3].(int32))]
+	var ids []string
+	for len(body) > 0 {
+		n := int(binary.LittleEndian.Uint32(body[:4]))
+		ids = append(ids, string(body[4:4+n]))
+		body = body[4+n:]
+	}
+	if fmt.Sprint(ids) != "[2/0001/1 1/0002/7 4/0003/9]" {
+		t.Errorf("unexpected values: %v", ids)
+	}
+}
+
+func TestEncodeRLE(t *testing.T) {
+	got := encodeRLE([]byte{1, 1, 0, 1})
+	want := []byte{2 << 1, 1, 1 << 1, 0, 1 << 1, 1}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeRLE() = %v, want %v", got, want)
+	}
+}
+
+// thriftReader is a minimal Thrift compact protocol decoder used to check the footer.
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) readValue(typ byte) (any, error) {
+	switch typ {
+	case thriftI32:
+		return int32(r.zigzag()), nil
+	case thriftI64:
+		return r.zigzag(), nil
+	case thriftBinary:
+		n := int(r.uvarint())
+		v := r.data[r.pos : r.pos+n]
+		r.pos += n
+		return v, nil
+	case thriftList:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		values := make([]any, 0, size)
+		for i := 0; i < size; i++ {
+			v, err := r.readValue(header & 0x0f)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		return nil, fmt.Errorf("unsupported type %d", typ)
+	}
+}
+
+func (r *thriftReader) readStruct() (map[int16]any, error) {
+	fields := make(map[int16]any)
+	var last int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields, nil
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		v, err := r.readValue(header & 0x0f)
+		if err != nil {
+			return nil, err
+		}
+		fields[id] = v
+		last = id
+	}
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// jsonlWriter writes one JSON object per line, with the keys in column order.
+type jsonlWriter struct {
+	columns []Column
+	keys    [][]byte
+	w       *bufio.Writer
+}
+
+func newJSONLWriter(w io.Writer, columns []Column) *jsonlWriter {
+	keys := make([][]byte, 0, len(columns))
+	for _, c := range columns {
+		key, _ := json.Marshal(c.Name)
+		keys = append(keys, key)
+	}
+	return &jsonlWriter{columns: columns, keys: keys, w: bufio.NewWriter(w)}
+}
+
+func (jw *jsonlWriter) Write(record []any) error {
+	if err := checkRecord(jw.columns, record); err != nil {
+		return err
+	}
+	jw.w.WriteByte('{')
+	for i, v := range record {
+		if i > 0 {
+			jw.w.WriteByte(',')
+		}
+		jw.w.Write(jw.keys[i])
+		jw.w.WriteByte(':')
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC().Format(time.RFC3339)
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		jw.w.Write(value)
+	}
+	jw.w.WriteByte('}')
+	return jw.w.WriteByte('\n')
+}
+
+func (jw *jsonlWriter) Close() error {
+	return jw.w.Flush()
+}
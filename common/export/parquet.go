@@ -0,0 +1,387 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// parquetRowGroupSize is the number of records buffered in memory before a row group is written.
+const parquetRowGroupSize = 10000
+
+const parquetMagic = "PAR1"
+
+// parquet physical types, repetition types, converted types and encodings.
+const (
+	parquetTypeInt64     int32 = 2
+	parquetTypeDouble    int32 = 5
+	parquetTypeByteArray int32 = 6
+
+	parquetRequired int32 = 0
+	parquetOptional int32 = 1
+
+	parquetConvertedUTF8            int32 = 0
+	parquetConvertedTimestampMillis int32 = 9
+
+	parquetEncodingPlain int32 = 0
+	parquetEncodingRLE   int32 = 3
+)
+
+// parquetWriter is a minimal Parquet writer.
+//
+// It writes a flat schema with one uncompressed, PLAIN encoded data page per column
+// and row group, which is readable by any Parquet implementation. Records are buffered
+// in memory and written in row groups of parquetRowGroupSize records.
+type parquetWriter struct {
+	columns   []Column
+	w         *countingWriter
+	buffer    [][]any
+	rowGroups []parquetRowGroup
+	numRows   int64
+	started   bool
+}
+
+type parquetRowGroup struct {
+	columns       []parquetColumnChunk
+	numRows       int64
+	totalByteSize int64
+}
+
+type parquetColumnChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+func newParquetWriter(w io.Writer, columns []Column) *parquetWriter {
+	return &parquetWriter{
+		columns: columns,
+		w:       &countingWriter{w: w},
+		buffer:  make([][]any, len(columns)),
+	}
+}
+
+func (pw *parquetWriter) Write(record []any) error {
+	if err := checkRecord(pw.columns, record); err != nil {
+		return err
+	}
+	for i, v := range record {
+		pw.buffer[i] = append(pw.buffer[i], v)
+	}
+	if len(pw.buffer[0]) >= parquetRowGroupSize {
+		return pw.flushRowGroup()
+	}
+	return nil
+}
+
+func (pw *parquetWriter) Close() error {
+	if err := pw.start(); err != nil {
+		return err
+	}
+	if err := pw.flushRowGroup(); err != nil {
+		return err
+	}
+
+	footer := pw.fileMetadata()
+	if _, err := pw.w.Write(footer); err != nil {
+		return err
+	}
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	if _, err := pw.w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := pw.w.Write([]byte(parquetMagic))
+	return err
+}
+
+// start writes the file header.
+func (pw *parquetWriter) start() error {
+	if pw.started {
+		return nil
+	}
+	pw.started = true
+	_, err := pw.w.Write([]byte(parquetMagic))
+	return err
+}
+
+// flushRowGroup writes the buffered records as a row group.
+func (pw *parquetWriter) flushRowGroup() error {
+	numRows := len(pw.buffer[0])
+	if numRows == 0 {
+		return nil
+	}
+	if err := pw.start(); err != nil {
+		return err
+	}
+
+	rowGroup := parquetRowGroup{numRows: int64(numRows)}
+	for i, c := range pw.columns {
+		offset := pw.w.n
+		page := encodeParquetPage(c, pw.buffer[i])
+		if _, err := pw.w.Write(page); err != nil {
+			return err
+		}
+		size := pw.w.n - offset
+		rowGroup.columns = append(rowGroup.columns, parquetColumnChunk{offset: offset, size: size, numValues: int64(numRows)})
+		rowGroup.totalByteSize += size
+		pw.buffer[i] = pw.buffer[i][:0]
+	}
+	pw.rowGroups = append(pw.rowGroups, rowGroup)
+	pw.numRows += int64(numRows)
+	return nil
+}
+
+// encodeParquetPage encodes the values of a column as a data page, including its header.
+func encodeParquetPage(c Column, values []any) []byte {
+	var body bytes.Buffer
+
+	// definition levels, only for optional columns.
+	if c.Optional {
+		levels := make([]byte, 0, len(values))
+		for _, v := range values {
+			if v == nil {
+				levels = append(levels, 0)
+			} else {
+				levels = append(levels, 1)
+			}
+		}
+		encoded := encodeRLE(levels)
+		var length [4]byte
+		binary.LittleEndian.PutUint32(length[:], uint32(len(encoded)))
+		body.Write(length[:])
+		body.Write(encoded)
+	}
+
+	// PLAIN encoded values, nil values are not stored.
+	var scratch [8]byte
+	for _, v := range values {
+		switch t := v.(type) {
+		case string:
+			binary.LittleEndian.PutUint32(scratch[:4], uint32(len(t)))
+			body.Write(scratch[:4])
+			body.WriteString(t)
+		case int64:
+			binary.LittleEndian.PutUint64(scratch[:], uint64(t))
+			body.Write(scratch[:])
+		case float64:
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(t))
+			body.Write(scratch[:])
+		case time.Time:
+			binary.LittleEndian.PutUint64(scratch[:], uint64(t.UnixMilli()))
+			body.Write(scratch[:])
+		}
+	}
+
+	// PageHeader
+	var h thriftWriter
+	h.i32(1, 0) // type: DATA_PAGE
+	h.i32(2, int32(body.Len()))
+	h.i32(3, int32(body.Len()))
+	h.structBegin(5) // data_page_header
+	h.i32(1, int32(len(values)))
+	h.i32(2, parquetEncodingPlain)
+	h.i32(3, parquetEncodingRLE)
+	h.i32(4, parquetEncodingRLE)
+	h.structEnd()
+	h.stop()
+
+	return append(h.buf.Bytes(), body.Bytes()...)
+}
+
+// encodeRLE encodes levels with a bit width of 1 using the RLE/bit-packing hybrid encoding.
+// Only RLE runs are used.
+func encodeRLE(levels []byte) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		out = append(out, levels[i])
+		i = j
+	}
+	return out
+}
+
+// fileMetadata encodes the FileMetaData structure of the footer.
+func (pw *parquetWriter) fileMetadata() []byte {
+	var t thriftWriter
+	t.i32(1, 1) // version
+
+	// schema: a root element followed by one element per column.
+	t.listBegin(2, thriftStruct, len(pw.columns)+1)
+	t.elemBegin()
+	t.binary(4, []byte("schema"))
+	t.i32(5, int32(len(pw.columns)))
+	t.elemEnd()
+	for _, c := range pw.columns {
+		t.elemBegin()
+		t.i32(1, parquetPhysicalType(c.Type))
+		if c.Optional {
+			t.i32(3, parquetOptional)
+		} else {
+			t.i32(3, parquetRequired)
+		}
+		t.binary(4, []byte(c.Name))
+		switch c.Type {
+		case ColumnString:
+			t.i32(6, parquetConvertedUTF8)
+		case ColumnTimestamp:
+			t.i32(6, parquetConvertedTimestampMillis)
+		}
+		t.elemEnd()
+	}
+
+	t.i64(3, pw.numRows)
+
+	t.listBegin(4, thriftStruct, len(pw.rowGroups))
+	for _, rg := range pw.rowGroups {
+		t.elemBegin()
+		t.listBegin(1, thriftStruct, len(rg.columns))
+		for i, chunk := range rg.columns {
+			c := pw.columns[i]
+			t.elemBegin()
+			t.i64(2, chunk.offset) // file_offset
+			t.structBegin(3)       // meta_data
+			t.i32(1, parquetPhysicalType(c.Type))
+			t.listBegin(2, thriftI32, 2)
+			t.elemI32(parquetEncodingPlain)
+			t.elemI32(parquetEncodingRLE)
+			t.listBegin(3, thriftBinary, 1)
+			t.elemBinary([]byte(c.Name))
+			t.i32(4, 0) // codec: UNCOMPRESSED
+			t.i64(5, chunk.numValues)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset) // data_page_offset
+			t.structEnd()
+			t.elemEnd()
+		}
+		t.i64(2, rg.totalByteSize)
+		t.i64(3, rg.numRows)
+		t.elemEnd()
+	}
+
+	t.binary(6, []byte("wormhole-explorer"))
+	t.stop()
+	return t.buf.Bytes()
+}
+
+func parquetPhysicalType(t ColumnType) int32 {
+	switch t {
+	case ColumnInt64, ColumnTimestamp:
+		return parquetTypeInt64
+	case ColumnFloat64:
+		return parquetTypeDouble
+	default:
+		return parquetTypeByteArray
+	}
+}
+
+// countingWriter tracks the number of bytes written.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// thrift compact protocol types.
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+// thriftWriter encodes structures with the Thrift compact protocol.
+type thriftWriter struct {
+	buf   bytes.Buffer
+	last  int16
+	stack []int16
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	t.last = id
+}
+
+func (t *thriftWriter) varint(v int64) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binary(id int16, v []byte) {
+	t.fieldHeader(id, thriftBinary)
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(v))))
+	t.buf.Write(v)
+}
+
+func (t *thriftWriter) structBegin(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.elemBegin()
+}
+
+func (t *thriftWriter) structEnd() {
+	t.elemEnd()
+}
+
+func (t *thriftWriter) listBegin(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		t.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+	}
+}
+
+// elemBegin starts a nested structure (a struct field or a list element).
+func (t *thriftWriter) elemBegin() {
+	t.stack = append(t.stack, t.last)
+	t.last = 0
+}
+
+// elemEnd ends a nested structure.
+func (t *thriftWriter) elemEnd() {
+	t.stop()
+	t.last = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+func (t *thriftWriter) elemI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) elemBinary(v []byte) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(v))))
+	t.buf.Write(v)
+}
+
+// stop writes the end of a structure.
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// TestParquetWriter_Interop reads the files written by the Parquet writer with an independent
+// Parquet implementation.
+func TestParquetWriter_Interop(t *testing.T) {
+	pr := newInteropReader(t, writeTestRecords(t, FormatParquet))
+	defer pr.ReadStop()
+
+	if n := pr.GetNumRows(); n != 3 {
+		t.Fatalf("expected 3 rows, got %d", n)
+	}
+	want := [][]any{
+		{"2/0001/1", "1/0002/7", "4/0003/9"},
+		{int64(2), nil, int64(4)},
+		{10.5, nil, nil},
+		{testTimestamp.UnixMilli(), testTimestamp.UnixMilli(), testTimestamp.UnixMilli()},
+	}
+	for i, c := range testColumns {
+		values, _, _, err := pr.ReadColumnByIndex(int64(i), 3)
+		if err != nil {
+			t.Fatalf("failed to read column %s: %v", c.Name, err)
+		}
+		if !reflect.DeepEqual(values, want[i]) {
+			t.Errorf("unexpected values of column %s: %v, want %v", c.Name, values, want[i])
+		}
+	}
+}
+
+// TestParquetWriter_InteropRowGroups reads a file with several row groups with an independent
+// Parquet implementation.
+func TestParquetWriter_InteropRowGroups(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatParquet, &buf, testColumns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	numRows := parquetRowGroupSize + 10
+	for i := 0; i < numRows; i++ {
+		var usd any
+		if i%2 == 0 {
+			usd = float64(i)
+		}
+		if err := w.Write([]any{fmt.Sprintf("2/0001/%d", i), int64(i), usd, testTimestamp}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pr := newInteropReader(t, buf.Bytes())
+	defer pr.ReadStop()
+
+	if n := pr.GetNumRows(); n != int64(numRows) {
+		t.Fatalf("expected %d rows, got %d", numRows, n)
+	}
+	ids, _, _, err := pr.ReadColumnByIndex(0, int64(numRows))
+	if err != nil {
+		t.Fatalf("failed to read column id: %v", err)
+	}
+	usd, _, _, err := pr.ReadColumnByIndex(2, int64(numRows))
+	if err != nil {
+		t.Fatalf("failed to read column usd: %v", err)
+	}
+	if len(ids) != numRows || len(usd) != numRows {
+		t.Fatalf("expected %d values, got %d ids and %d usd", numRows, len(ids), len(usd))
+	}
+	last := numRows - 1
+	if ids[last] != fmt.Sprintf("2/0001/%d", last) {
+		t.Errorf("unexpected last id: %v", ids[last])
+	}
+	if usd[last-1] != float64(last-1) || usd[last] != nil {
+		t.Errorf("unexpected last usd values: %v, %v", usd[last-1], usd[last])
+	}
+}
+
+func newInteropReader(t *testing.T, data []byte) *reader.ParquetReader {
+	file, err := buffer.NewBufferFile(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pr, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		t.Fatalf("failed to read parquet file: %v", err)
+	}
+	return pr
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"strings"
+	"time"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// TransferColumns are the columns of a transfer export.
+var TransferColumns = []Column{
+	{Name: "vaaId", Type: ColumnString},
+	{Name: "vaaHash", Type: ColumnString},
+	{Name: "sourceChain", Type: ColumnInt64, Optional: true},
+	{Name: "emitterAddress", Type: ColumnString},
+	{Name: "sequence", Type: ColumnString},
+	{Name: "timestamp", Type: ColumnTimestamp},
+	{Name: "sourceTxHash", Type: ColumnString},
+	{Name: "sourceSenderAddress", Type: ColumnString},
+	{Name: "destinationChain", Type: ColumnInt64, Optional: true},
+	{Name: "destinationAddress", Type: ColumnString},
+	{Name: "destinationTxHash", Type: ColumnString},
+	{Name: "portalPayloadType", Type: ColumnInt64, Optional: true},
+	{Name: "appIds", Type: ColumnString},
+	{Name: "tokenChain", Type: ColumnInt64, Optional: true},
+	{Name: "tokenAddress", Type: ColumnString},
+	{Name: "amount", Type: ColumnString},
+	{Name: "decimals", Type: ColumnInt64, Optional: true},
+	{Name: "notionalUSD", Type: ColumnFloat64, Optional: true},
+	{Name: "fee", Type: ColumnString},
+	{Name: "coinGeckoId", Type: ColumnString},
+	{Name: "symbol", Type: ColumnString},
+}
+
+// Transfer is a row of a transfer export.
+type Transfer struct {
+	VaaID               string
+	VaaHash             string
+	SourceChain         sdk.ChainID
+	EmitterAddress      string
+	Sequence            string
+	Timestamp           time.Time
+	SourceTxHash        string
+	SourceSenderAddress string
+	DestinationChain    sdk.ChainID
+	DestinationAddress  string
+	DestinationTxHash   string
+	PortalPayloadType   int
+	AppIDs              []string
+	TokenChain          sdk.ChainID
+	TokenAddress        string
+	Amount              string
+	// Decimals is nil when the token metadata is unknown.
+	Decimals *int64
+	// NotionalUSD is nil when the token price is unknown.
+	NotionalUSD *float64
+	Fee         string
+	CoingeckoID string
+	Symbol      string
+}
+
+// Record returns the values of the transfer in the order of TransferColumns.
+func (t *Transfer) Record() []any {
+	return []any{
+		t.VaaID,
+		t.VaaHash,
+		chainIDValue(t.SourceChain),
+		t.EmitterAddress,
+		t.Sequence,
+		t.Timestamp,
+		t.SourceTxHash,
+		t.SourceSenderAddress,
+		chainIDValue(t.DestinationChain),
+		t.DestinationAddress,
+		t.DestinationTxHash,
+		intValue(t.PortalPayloadType),
+		strings.Join(t.AppIDs, "|"),
+		chainIDValue(t.TokenChain),
+		t.TokenAddress,
+		t.Amount,
+		int64PtrValue(t.Decimals),
+		float64PtrValue(t.NotionalUSD),
+		t.Fee,
+		t.CoingeckoID,
+		t.Symbol,
+	}
+}
+
+func chainIDValue(chainID sdk.ChainID) any {
+	if chainID == sdk.ChainIDUnset {
+		return nil
+	}
+	return int64(chainID)
+}
+
+func intValue(v int) any {
+	if v == 0 {
+		return nil
+	}
+	return int64(v)
+}
+
+func int64PtrValue(v *int64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func float64PtrValue(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
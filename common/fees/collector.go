@@ -0,0 +1,55 @@
+package fees
+
+import (
+	"context"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	"go.uber.org/zap"
+)
+
+// Collector writes the fees of the transactions into the time-series storage.
+type Collector struct {
+	repository *Repository
+	writer     timeseries.Writer
+	relayers   KnownRelayers
+	logger     *zap.Logger
+}
+
+// NewCollector creates a new collector. The senders of the destination transactions that are not
+// in relayers are tagged as OtherRelayer.
+func NewCollector(repository *Repository, writer timeseries.Writer, relayers KnownRelayers, logger *zap.Logger) *Collector {
+	return &Collector{
+		repository: repository,
+		writer:     writer,
+		relayers:   relayers,
+		logger:     logger.With(zap.String("module", "FeesCollector")),
+	}
+}
+
+// Collect writes the fees of the transactions updated in the range [from, to) and returns the number of points written.
+// The points of a transaction are always written with the same tags and time, so a transaction can be collected again.
+func (c *Collector) Collect(ctx context.Context, from, to time.Time) (int, error) {
+	fees, err := c.repository.FindUpdated(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	points := make([]*timeseries.Point, 0, len(fees))
+	for _, f := range fees {
+		p, ok := NewPoint(f, c.relayers)
+		if !ok {
+			c.logger.Warn("invalid transaction fee", zap.String("id", f.ID), zap.String("side", string(f.Side)), zap.String("fee", f.Fee))
+			continue
+		}
+		points = append(points, p)
+	}
+	if len(points) == 0 {
+		return 0, nil
+	}
+	if err := c.writer.WritePoints(ctx, Bucket, points...); err != nil {
+		c.logger.Error("failed to write transaction fee points", zap.Error(err))
+		return 0, err
+	}
+	return len(points), nil
+}
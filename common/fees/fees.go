@@ -0,0 +1,128 @@
+// Package fees records the fees paid by the source and destination transactions of the transfers.
+//
+// tx-tracker stores the fee of each transaction in the feeDetail of the globalTransactions documents.
+// The collector copies them into the transaction_fees measurement so they can be aggregated by chain,
+// by relayer and by protocol over time.
+package fees
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Measurement is the name of the time-series measurement with the transaction fees.
+const Measurement = "transaction_fees"
+
+// Bucket is the time-series bucket where the transaction fees are stored.
+const Bucket = timeseries.BucketInfinite
+
+// Tags of the transaction_fees measurement.
+const (
+	TagChainID = "chain_id"
+	TagSide    = "side"
+	TagAppID   = "app_id"
+	// TagRelayer is the address that sent the destination transaction when it is a known relayer, or
+	// OtherRelayer. It is not set for source transactions.
+	TagRelayer = "relayer"
+)
+
+// Fields of the transaction_fees measurement.
+const (
+	// FieldFee is the fee in the native token of the chain.
+	FieldFee = "fee"
+	// FieldFeeUSD is the fee in USD, it is only set when the price of the native token was known.
+	FieldFeeUSD = "fee_usd"
+)
+
+// NoAppID is the app_id tag value of the transfers without app id.
+const NoAppID = "none"
+
+// OtherRelayer is the relayer tag value of the destination transactions not sent by a known relayer.
+// Only the known relayers are tagged so that the number of series of the measurement stays bounded.
+const OtherRelayer = "other"
+
+// KnownRelayers is the set of relayer addresses tagged in the measurement.
+type KnownRelayers map[string]bool
+
+// NewKnownRelayers creates the set of known relayers, the addresses are compared in lowercase.
+func NewKnownRelayers(addresses []string) KnownRelayers {
+	k := make(KnownRelayers, len(addresses))
+	for _, a := range addresses {
+		if a = strings.TrimSpace(a); a != "" {
+			k[strings.ToLower(a)] = true
+		}
+	}
+	return k
+}
+
+// Tag returns the relayer tag value of the sender of a destination transaction.
+func (k KnownRelayers) Tag(address string) string {
+	address = strings.ToLower(address)
+	if k[address] {
+		return address
+	}
+	return OtherRelayer
+}
+
+// Side is the transaction of a transfer that paid the fee.
+type Side string
+
+const (
+	SideSource      Side = "source"
+	SideDestination Side = "destination"
+)
+
+// ParseSide parses a side, the second value is false if the side is unknown.
+func ParseSide(s string) (Side, bool) {
+	switch side := Side(strings.ToLower(s)); side {
+	case SideSource, SideDestination:
+		return side, true
+	default:
+		return "", false
+	}
+}
+
+// Fee is the fee paid by a transaction of a transfer.
+type Fee struct {
+	// ID is the id of the transfer, which has the form chain/emitter/sequence.
+	ID        string
+	Side      Side
+	ChainID   sdk.ChainID
+	AppID     string
+	Relayer   string
+	Timestamp time.Time
+	Fee       string
+	FeeUSD    string
+}
+
+// NewPoint returns the transaction_fees point of a fee. The second value is false if the fee is not a number.
+// The time of the point is the time of the transaction, offset by a hash of the transfer id.
+func NewPoint(f Fee, relayers KnownRelayers) (*timeseries.Point, bool) {
+	fee, err := decimal.NewFromString(f.Fee)
+	if err != nil {
+		return nil, false
+	}
+	appID := f.AppID
+	if appID == "" {
+		appID = NoAppID
+	}
+
+	p := timeseries.NewPoint(Measurement).
+		AddTag(TagChainID, strconv.Itoa(int(f.ChainID))).
+		AddTag(TagSide, string(f.Side)).
+		AddTag(TagAppID, appID).
+		AddField(FieldFee, fee.InexactFloat64()).
+		SetTime(timeseries.UniqueTime(f.Timestamp, f.ID))
+	if f.Side == SideDestination && f.Relayer != "" {
+		p.AddTag(TagRelayer, relayers.Tag(f.Relayer))
+	}
+	if feeUSD, err := decimal.NewFromString(f.FeeUSD); err == nil {
+		p.AddField(FieldFeeUSD, feeUSD.InexactFloat64())
+	}
+	return p, true
+}
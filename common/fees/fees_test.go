@@ -0,0 +1,59 @@
+package fees
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/assert"
+	"github.com/test-go/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestNewPoint(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := Fee{
+		ID:        "2/000000000000000000000000000000000000000000000000000000000000beef/7",
+		Side:      SideDestination,
+		ChainID:   sdk.ChainIDEthereum,
+		Relayer:   "0xrelayer",
+		Timestamp: ts,
+		Fee:       "0.0021",
+		FeeUSD:    "4.2",
+	}
+
+	relayers := NewKnownRelayers([]string{"0xRelayer"})
+	p, ok := NewPoint(f, relayers)
+	require.True(t, ok)
+	assert.Equal(t, Measurement, p.Name())
+	assert.Equal(t, timeseries.UniqueTime(ts, f.ID), p.Time())
+	relayer, _ := p.Tag(TagRelayer)
+	assert.Equal(t, "0xrelayer", relayer)
+
+	// the unknown relayers share the same tag value.
+	f.Relayer = "0xunknown"
+	p, ok = NewPoint(f, relayers)
+	require.True(t, ok)
+	relayer, _ = p.Tag(TagRelayer)
+	assert.Equal(t, OtherRelayer, relayer)
+	appID, _ := p.Tag(TagAppID)
+	assert.Equal(t, NoAppID, appID)
+	fee, _ := p.Field(FieldFee)
+	assert.Equal(t, 0.0021, fee)
+	feeUSD, _ := p.Field(FieldFeeUSD)
+	assert.Equal(t, 4.2, feeUSD)
+
+	// the sender of a source transaction is not a relayer and the usd fee is optional.
+	f.Side = SideSource
+	f.FeeUSD = ""
+	p, ok = NewPoint(f, relayers)
+	require.True(t, ok)
+	_, ok = p.Tag(TagRelayer)
+	assert.False(t, ok)
+	_, ok = p.Field(FieldFeeUSD)
+	assert.False(t, ok)
+
+	f.Fee = "unknown"
+	_, ok = NewPoint(f, relayers)
+	assert.False(t, ok)
+}
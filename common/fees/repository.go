@@ -0,0 +1,119 @@
+package fees
+
+import (
+	"context"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// feeDetailDoc is the fee of a transaction as stored by tx-tracker.
+type feeDetailDoc struct {
+	Fee    string `bson:"fee"`
+	FeeUSD string `bson:"feeUSD"`
+}
+
+// txDoc is a source or destination transaction of a globalTransactions document.
+type txDoc struct {
+	ChainID   sdk.ChainID   `bson:"chainId"`
+	From      string        `bson:"from"`
+	Timestamp *time.Time    `bson:"timestamp"`
+	UpdatedAt *time.Time    `bson:"updatedAt"`
+	FeeDetail *feeDetailDoc `bson:"feeDetail"`
+}
+
+// globalTransactionDoc is a globalTransactions document joined with the app ids of its parsed VAA.
+type globalTransactionDoc struct {
+	ID            string   `bson:"_id"`
+	OriginTx      *txDoc   `bson:"originTx"`
+	DestinationTx *txDoc   `bson:"destinationTx"`
+	AppIDs        []string `bson:"appIds"`
+}
+
+// Repository reads the fees of the transactions from the globalTransactions collection.
+type Repository struct {
+	globalTransactions *mongo.Collection
+	logger             *zap.Logger
+}
+
+// NewRepository creates a new fees repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{
+		globalTransactions: db.Collection(repository.GlobalTransactions),
+		logger:             logger.With(zap.String("module", "FeesRepository")),
+	}
+}
+
+// FindUpdated returns the fees of the source and destination transactions updated in the range [from, to).
+//
+// The transfers whose VAA is not parsed yet are skipped: their app id is unknown, and a point written
+// without it would be counted again under the app id once the VAA is parsed. They are collected by the
+// next runs whose range includes the update, so the parser has the lookback window to catch up.
+func (r *Repository) FindUpdated(ctx context.Context, from, to time.Time) ([]Fee, error) {
+	updated := bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "originTx.updatedAt", Value: updated}},
+			bson.D{{Key: "destinationTx.updatedAt", Value: updated}},
+		}}}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: repository.ParsedVaa},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "parsedVaa"},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "parsedVaa", Value: bson.D{{Key: "$ne", Value: bson.A{}}}}}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "originTx", Value: 1},
+			{Key: "destinationTx", Value: 1},
+			{Key: "appIds", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$parsedVaa.standardizedProperties.appIds", 0}}}},
+		}}},
+	}
+
+	cur, err := r.globalTransactions.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.logger.Error("failed to aggregate transaction fees", zap.Error(err))
+		return nil, err
+	}
+	var docs []globalTransactionDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		r.logger.Error("failed to decode transaction fees", zap.Error(err))
+		return nil, err
+	}
+
+	var fees []Fee
+	for _, doc := range docs {
+		var appID string
+		if len(doc.AppIDs) > 0 {
+			appID = doc.AppIDs[0]
+		}
+		if f, ok := doc.OriginTx.toFee(doc.ID, SideSource, appID); ok {
+			fees = append(fees, f)
+		}
+		if f, ok := doc.DestinationTx.toFee(doc.ID, SideDestination, appID); ok {
+			fees = append(fees, f)
+		}
+	}
+	return fees, nil
+}
+
+// toFee converts the transaction into a fee, the second value is false if the transaction has no fee.
+func (tx *txDoc) toFee(id string, side Side, appID string) (Fee, bool) {
+	if tx == nil || tx.FeeDetail == nil || tx.FeeDetail.Fee == "" || tx.Timestamp == nil {
+		return Fee{}, false
+	}
+	return Fee{
+		ID:        id,
+		Side:      side,
+		ChainID:   tx.ChainID,
+		AppID:     appID,
+		Relayer:   tx.From,
+		Timestamp: *tx.Timestamp,
+		Fee:       tx.FeeDetail.Fee,
+		FeeUSD:    tx.FeeDetail.FeeUSD,
+	}, true
+}
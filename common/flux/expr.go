@@ -0,0 +1,285 @@
+package flux
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expr is a Flux expression.
+type Expr interface {
+	// render writes the expression, binding its values to the params of the scope.
+	// The hint names the params of values that were not given a name.
+	render(s *scope, hint string) string
+}
+
+// Duration is a Flux duration such as "1h", "1d" or "1mo", passed to the query as a param.
+type Duration string
+
+// Raw returns an expression written as is. It must only be used for code that is
+// part of the query itself, never for values coming from the callers.
+func Raw(code string) Expr {
+	return raw(code)
+}
+
+type raw string
+
+func (r raw) render(*scope, string) string { return string(r) }
+
+// Epoch is the start of unix time, used as the start of ranges over all the data.
+var Epoch = Raw("1970-01-01T00:00:00Z")
+
+// Var returns a reference to a variable of the script.
+func Var(name string) Expr {
+	return raw(mustIdentifier(name))
+}
+
+// String returns a string literal. Use it for constants of the query, such as
+// measurement or field names, that are clearer written in the script.
+func String(s string) Expr {
+	return raw(quote(s))
+}
+
+// Param returns a named param. Strings are referenced as is, [time.Time] values are
+// converted with time() and [Duration] values with duration(). Integers, floats and
+// booleans are written as literals.
+func Param(name string, value any) Expr {
+	return param{name: name, value: value}
+}
+
+type param struct {
+	name  string
+	value any
+}
+
+func (p param) render(s *scope, hint string) string {
+	name := p.name
+	if name == "" {
+		name = hint
+	}
+	switch v := p.value.(type) {
+	case string:
+		return s.bind(name, v)
+	case time.Time:
+		return fmt.Sprintf("time(v: %s)", s.bind(name, v.UTC()))
+	case Duration:
+		return fmt.Sprintf("duration(v: %s)", s.bind(name, string(v)))
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		f := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(f, ".") {
+			f += ".0"
+		}
+		return f
+	default:
+		panic(fmt.Sprintf("flux: unsupported param type %T", p.value))
+	}
+}
+
+// toExpr returns the value as an expression, binding it as a param unless it is an Expr.
+func toExpr(value any) Expr {
+	if e, ok := value.(Expr); ok {
+		return e
+	}
+	return param{value: value}
+}
+
+// Ago returns the negative of a duration, to express a start relative to now.
+func Ago(d any) Expr {
+	return prefix{op: "-", expr: toExpr(d)}
+}
+
+type prefix struct {
+	op   string
+	expr Expr
+}
+
+func (p prefix) render(s *scope, hint string) string {
+	return p.op + p.expr.render(s, hint)
+}
+
+// Column returns a reference to a column of the record r of a predicate.
+func Column(name string) Expr {
+	return column(name)
+}
+
+type column string
+
+func (c column) render(*scope, string) string {
+	if identifier.MatchString(string(c)) {
+		return "r." + string(c)
+	}
+	return "r[" + quote(string(c)) + "]"
+}
+
+type binary struct {
+	op    string
+	left  Expr
+	right Expr
+	hint  string
+}
+
+func (b binary) render(s *scope, hint string) string {
+	if b.hint != "" {
+		hint = b.hint
+	}
+	return b.left.render(s, hint) + " " + b.op + " " + b.right.render(s, hint)
+}
+
+func compare(op, col string, value any) Expr {
+	return binary{op: op, left: column(col), right: toExpr(value), hint: col}
+}
+
+// Eq returns the predicate r.col == value.
+func Eq(col string, value any) Expr { return compare("==", col, value) }
+
+// Ne returns the predicate r.col != value.
+func Ne(col string, value any) Expr { return compare("!=", col, value) }
+
+// Gt returns the predicate r.col > value.
+func Gt(col string, value any) Expr { return compare(">", col, value) }
+
+// In returns a predicate that matches the records whose column is one of values,
+// or nil when there are no values, so the filter is skipped.
+func In(col string, values ...string) Expr {
+	if len(values) == 0 {
+		return nil
+	}
+	return in{col: col, values: values}
+}
+
+type in struct {
+	col    string
+	values []string
+}
+
+func (i in) render(s *scope, _ string) string {
+	refs := make([]string, 0, len(i.values))
+	for idx, v := range i.values {
+		refs = append(refs, s.bind(fmt.Sprintf("%s_%d", paramName(i.col), idx), v))
+	}
+	return fmt.Sprintf("contains(value: %s, set: [%s])", column(i.col).render(s, ""), strings.Join(refs, ", "))
+}
+
+// Exists returns the predicate exists r.col.
+func Exists(col string) Expr {
+	return prefix{op: "exists ", expr: column(col)}
+}
+
+// Not negates a predicate.
+func Not(e Expr) Expr {
+	return prefix{op: "not ", expr: group{e}}
+}
+
+// And joins the predicates that are not nil with and. It returns nil if there are none.
+func And(preds ...Expr) Expr { return join("and", preds) }
+
+// Or joins the predicates that are not nil with or. It returns nil if there are none.
+func Or(preds ...Expr) Expr { return join("or", preds) }
+
+type logical struct {
+	op    string
+	preds []Expr
+}
+
+func join(op string, preds []Expr) Expr {
+	var operands []Expr
+	for _, p := range preds {
+		if p != nil {
+			operands = append(operands, p)
+		}
+	}
+	switch len(operands) {
+	case 0:
+		return nil
+	case 1:
+		return operands[0]
+	}
+	return logical{op: op, preds: operands}
+}
+
+func (l logical) render(s *scope, hint string) string {
+	parts := make([]string, 0, len(l.preds))
+	for _, p := range l.preds {
+		if other, ok := p.(logical); ok && other.op != l.op {
+			p = group{other}
+		}
+		parts = append(parts, p.render(s, hint))
+	}
+	return strings.Join(parts, " "+l.op+" ")
+}
+
+// group wraps an expression in parentheses when it is a compound predicate.
+type group struct {
+	expr Expr
+}
+
+func (g group) render(s *scope, hint string) string {
+	switch g.expr.(type) {
+	case logical, binary:
+		return "(" + g.expr.render(s, hint) + ")"
+	}
+	return g.expr.render(s, hint)
+}
+
+// Argument is a named argument of a function call.
+type Argument struct {
+	name  string
+	value Expr
+}
+
+// Arg returns a named argument. Values that are not an Expr are passed as params
+// named after the argument.
+func Arg(name string, value any) Argument {
+	return Argument{name: mustIdentifier(name), value: toExpr(value)}
+}
+
+// Call returns a call to a function with named arguments.
+func Call(fn string, args ...Argument) Expr {
+	return call{fn: fn, args: args}
+}
+
+type call struct {
+	fn   string
+	args []Argument
+}
+
+func (c call) render(s *scope, _ string) string {
+	args := make([]string, 0, len(c.args))
+	for _, a := range c.args {
+		args = append(args, a.name+": "+a.value.render(s, a.name))
+	}
+	return c.fn + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Columns returns an array of column names.
+func Columns(cols ...string) Expr {
+	quoted := make([]string, 0, len(cols))
+	for _, c := range cols {
+		quoted = append(quoted, quote(c))
+	}
+	return raw("[" + strings.Join(quoted, ", ") + "]")
+}
+
+// Union returns the union of tables.
+func Union(tables ...Expr) Expr {
+	return unionExpr(tables)
+}
+
+type unionExpr []Expr
+
+func (u unionExpr) render(s *scope, hint string) string {
+	tables := make([]string, 0, len(u))
+	for _, t := range u {
+		tables = append(tables, t.render(s, hint))
+	}
+	return "union(tables: [" + strings.Join(tables, ", ") + "])"
+}
@@ -0,0 +1,154 @@
+// Package flux builds parameterized Flux queries.
+//
+// Values such as bucket names, tags, symbols or times are never written into the
+// query text: they are collected in the params record of the [Query] and referenced
+// as `params.<name>` by the script, so the InfluxDB query API receives them apart
+// from the code that uses them.
+//
+//	s := flux.NewScript()
+//	q := s.Build(flux.From(bucket).
+//		Range(start, nil).
+//		Filter(flux.Eq("_measurement", flux.String("vaa_volume_v2")), flux.Eq("symbol", symbol)).
+//		Sum())
+//
+// renders
+//
+//	from(bucket: params.bucket)
+//	  |> range(start: time(v: params.start))
+//	  |> filter(fn: (r) => r._measurement == "vaa_volume_v2" and r.symbol == params.symbol)
+//	  |> sum()
+//
+// The params record is only accepted by the query API of InfluxDB Cloud: InfluxDB OSS 2.x
+// does not support parameterized queries and fails with an undefined identifier params.
+// The services that read Influx with these queries require an InfluxDB Cloud instance.
+package flux
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Query is a Flux script and the values it references through the params record.
+// Text and Params are the arguments of the QueryWithParams method of the InfluxDB query API.
+type Query struct {
+	Text   string
+	Params map[string]any
+}
+
+// Script is a Flux script made of imports, variable assignments and a result expression.
+type Script struct {
+	imports    []string
+	statements []statement
+}
+
+type statement struct {
+	name  string
+	value Expr
+}
+
+// NewScript creates a new script with the given imports.
+func NewScript(imports ...string) *Script {
+	s := &Script{}
+	return s.Import(imports...)
+}
+
+// Import adds packages to the imports of the script.
+func (s *Script) Import(pkgs ...string) *Script {
+	for _, pkg := range pkgs {
+		if !slices.Contains(s.imports, pkg) {
+			s.imports = append(s.imports, pkg)
+		}
+	}
+	return s
+}
+
+// Define adds a function definition or any other statement to the script.
+// The definition is written as is, so it must not contain values coming from the callers.
+func (s *Script) Define(definition string) *Script {
+	s.statements = append(s.statements, statement{value: Raw(definition)})
+	return s
+}
+
+// Let assigns the expression to a variable and returns a reference to it.
+func (s *Script) Let(name string, value Expr) Expr {
+	v := Var(name)
+	s.statements = append(s.statements, statement{name: name, value: value})
+	return v
+}
+
+// Build renders the script with result as its last expression.
+func (s *Script) Build(result Expr) Query {
+	sc := &scope{params: map[string]any{}}
+	var b strings.Builder
+	for _, pkg := range s.imports {
+		fmt.Fprintf(&b, "import %s\n", quote(pkg))
+	}
+	if len(s.imports) > 0 {
+		b.WriteString("\n")
+	}
+	for _, st := range s.statements {
+		if st.name != "" {
+			fmt.Fprintf(&b, "%s = ", st.name)
+		}
+		b.WriteString(st.value.render(sc, st.name))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(result.render(sc, ""))
+	b.WriteString("\n")
+	return Query{Text: b.String(), Params: sc.params}
+}
+
+// scope collects the params of a script while it is rendered.
+type scope struct {
+	params map[string]any
+}
+
+// bind adds a value to the params record and returns the reference to it.
+// Values bound twice under the same name share the param, while different
+// values get a numeric suffix.
+func (s *scope) bind(name string, value any) string {
+	name = paramName(name)
+	candidate := name
+	for i := 2; ; i++ {
+		current, ok := s.params[candidate]
+		if !ok {
+			s.params[candidate] = value
+			return "params." + candidate
+		}
+		if reflect.DeepEqual(current, value) {
+			return "params." + candidate
+		}
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func paramName(hint string) string {
+	name := strings.TrimLeft(strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, hint), "_0123456789")
+	if name == "" {
+		return "value"
+	}
+	return name
+}
+
+func mustIdentifier(name string) string {
+	if !identifier.MatchString(name) {
+		panic(fmt.Sprintf("flux: invalid identifier %q", name))
+	}
+	return name
+}
+
+// quote returns a Flux string literal.
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,112 @@
+package flux_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux/fluxtest"
+)
+
+func TestScript_Pipeline(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("UTC-3", -3*60*60))
+	q := flux.NewScript().Build(flux.From("wormscan").
+		Range(start, flux.Raw("now()")).
+		Filter(flux.Eq("_measurement", flux.String("vaa_volume_v2")), flux.Eq("_field", "volume")).
+		Filter(flux.In("app_id", "PORTAL_TOKEN_BRIDGE", "CCTP_WORMHOLE_INTEGRATION")).
+		Filter(flux.In("emitter_chain")).
+		Group("emitter_chain", "destination_chain").
+		AggregateWindow(flux.Duration("1d"), "sum", true).
+		Sort(true, "_value").
+		Limit(10))
+
+	fluxtest.AssertGolden(t, "pipeline", q)
+	assert.Equal(t, map[string]any{
+		"bucket":   "wormscan",
+		"start":    start.UTC(),
+		"field":    "volume",
+		"app_id_0": "PORTAL_TOKEN_BRIDGE",
+		"app_id_1": "CCTP_WORMHOLE_INTEGRATION",
+		"every":    "1d",
+	}, q.Params)
+}
+
+func TestScript_Variables(t *testing.T) {
+	s := flux.NewScript("date", "join")
+	s.Define(`sumAndCount = (tables=<-) => tables |> count()`)
+	every := s.Let("every", flux.Param("every", flux.Duration("1mo")))
+	data := s.Let("data", flux.From("wormscan").
+		Range(flux.Ago(flux.Duration("30d")), nil).
+		Filter(flux.Or(flux.Eq("app_id_1", "MAYAN"), flux.Eq("app_id_2", "MAYAN")), flux.Not(flux.Exists("protocol"))).
+		Drop("_start", "_stop").
+		Window(every))
+	volume := s.Let("volume", flux.Pipe(data).
+		Filter(flux.And(flux.Eq("_field", flux.String("volume")), flux.Gt("_value", 0))).
+		Rename("_value", "volume"))
+	q := s.Build(flux.Pipe(flux.Union(volume, data)).
+		Pivot([]string{"_time"}, []string{"_field"}, "_value").
+		Map(`(r) => ({r with to: date.add(d: every, to: r._time)})`))
+
+	fluxtest.AssertGolden(t, "variables", q)
+}
+
+func TestScript_ParamNames(t *testing.T) {
+	q := flux.NewScript().Build(flux.From("wormscan").
+		Filter(flux.Eq("symbol", "W"), flux.Ne("symbol", "WETH"), flux.Eq("symbol", "W")).
+		Filter(flux.Eq("from_address", flux.Param("address", "0x01"))).
+		Filter(flux.Ne("emitter_chain", flux.Column("destination_chain"))))
+
+	assert.Equal(t, `from(bucket: params.bucket)
+  |> filter(fn: (r) => r.symbol == params.symbol and r.symbol != params.symbol_2 and r.symbol == params.symbol)
+  |> filter(fn: (r) => r.from_address == params.address)
+  |> filter(fn: (r) => r.emitter_chain != r.destination_chain)
+`, q.Text)
+	assert.Equal(t, map[string]any{"bucket": "wormscan", "symbol": "W", "symbol_2": "WETH", "address": "0x01"}, q.Params)
+}
+
+func TestScript_ValuesAreNotWrittenInTheQuery(t *testing.T) {
+	symbol := `W") or true or (r.symbol == "`
+	q := flux.NewScript().Build(flux.From("wormscan").Filter(flux.Eq("symbol", symbol), flux.Eq("_measurement", flux.String(`a"b${c}`))))
+
+	assert.Equal(t, `from(bucket: params.bucket)
+  |> filter(fn: (r) => r.symbol == params.symbol and r._measurement == "a\"b\${c}")
+`, q.Text)
+	assert.Equal(t, symbol, q.Params["symbol"])
+}
+
+func TestScript_InvalidIdentifier(t *testing.T) {
+	assert.Panics(t, func() { flux.Var("data) |> drop(") })
+	assert.Panics(t, func() { flux.Arg("every:", "1d") })
+}
+
+func TestFluxtest_Canonical(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := flux.NewScript("date")
+	every := s.Let("every", flux.Param("every", flux.Duration("1h")))
+	q := s.Build(flux.From("wormscan").
+		Range(start, nil).
+		Filter(flux.In("emitter_chain", "1", "2")).
+		Group("emitter_chain", "_time").
+		AggregateWindow(every, "sum", true))
+
+	sprintf := `
+		import "date"
+		import "join"
+
+		from(bucket: "wormscan")
+			|> range(start: 2024-01-01T00:00:00Z)
+			|> filter(fn: (r) => (r.emitter_chain == "1" or r.emitter_chain == "2"))
+			|> group(columns:["_time","emitter_chain"])
+			|> aggregateWindow(every: 1h, fn: sum,)`
+	fluxtest.AssertEquivalent(t, sprintf, q)
+
+	assert.NotEqual(t, fluxtest.Canonical(sprintf), fluxtest.Canonical(strings.Replace(sprintf, `"2"`, `"3"`, 1)))
+	assert.Equal(t, `from(bucket: "wormscan")
+  |> range(start: 2024-01-01T00:00:00Z)
+  |> filter(fn: (r) => contains(value: r.emitter_chain, set: ["1", "2"]))
+  |> group(columns: ["emitter_chain", "_time"])
+  |> aggregateWindow(every: every, fn: sum, createEmpty: true)
+`, strings.SplitN(fluxtest.Inline(q), "\n\n", 3)[2])
+}
@@ -0,0 +1,267 @@
+package fluxtest
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+)
+
+var (
+	conversionRef = regexp.MustCompile(`(time|duration)\(v: params\.([A-Za-z_][A-Za-z0-9_]*)\)`)
+	paramRef      = regexp.MustCompile(`params\.([A-Za-z_][A-Za-z0-9_]*)`)
+	literalVar    = regexp.MustCompile(`(?m)^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=\s*("[^"]*"|[0-9][0-9A-Za-z:.+-]*)\s*$`)
+)
+
+// Inline returns the text of the query with its params written in place as literals,
+// the way the queries were written before they were parameterized.
+func Inline(q flux.Query) string {
+	text := conversionRef.ReplaceAllStringFunc(q.Text, func(ref string) string {
+		m := conversionRef.FindStringSubmatch(ref)
+		switch v := q.Params[m[2]].(type) {
+		case time.Time:
+			return v.Format(time.RFC3339Nano)
+		case string:
+			if m[1] == "duration" {
+				return v
+			}
+			return fmt.Sprintf("time(v: %q)", v)
+		}
+		return ref
+	})
+	return paramRef.ReplaceAllStringFunc(text, func(ref string) string {
+		switch v := q.Params[paramRef.FindStringSubmatch(ref)[1]].(type) {
+		case string:
+			return quote(v)
+		case time.Time:
+			return v.Format(time.RFC3339Nano)
+		}
+		return ref
+	})
+}
+
+// AssertEquivalent compares the query with a query written as text, such as one built
+// with fmt.Sprintf, once its params are inlined and both are in their [Canonical] form.
+func AssertEquivalent(t *testing.T, expected string, q flux.Query) {
+	t.Helper()
+	want, got := Canonical(expected), Canonical(Inline(q))
+	if want != got {
+		t.Errorf("query is not equivalent to the expected one\n--- expected\n%s\n--- actual\n%s", want, got)
+	}
+}
+
+// Canonical returns the tokens of the query separated by a single space, after rewriting
+// the constructs that are written differently but mean the same:
+//   - variables assigned a literal are replaced by the literal,
+//   - unused imports, trailing commas and createEmpty: true, its default, are removed,
+//   - quoted keys of records are unquoted,
+//   - parentheses around the body of a function are removed,
+//   - contains(value: c, set: [a, b]) is written as c == a or c == b,
+//   - the columns of group() are sorted.
+func Canonical(text string) string {
+	vars := map[string]string{}
+	text = literalVar.ReplaceAllStringFunc(text, func(line string) string {
+		m := literalVar.FindStringSubmatch(line)
+		vars[m[1]] = m[2]
+		return ""
+	})
+
+	tokens := tokenize(text)
+	for i, tok := range tokens {
+		if v, ok := vars[tok]; ok && !(i > 0 && tokens[i-1] == ".") && !(i+1 < len(tokens) && tokens[i+1] == ":") {
+			tokens[i] = v
+		}
+	}
+	tokens = removeUnusedImports(tokens)
+	tokens = removeDefaults(tokens)
+	tokens = unquoteKeys(tokens)
+	tokens = unwrapBodies(tokens)
+	tokens = expandContains(tokens)
+	tokens = sortGroupColumns(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// tokenize splits a query in string literals, identifiers, literals and operators.
+func tokenize(text string) []string {
+	var tokens []string
+	r := []rune(text)
+	for i := 0; i < len(r); {
+		c := r[i]
+		start := i
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c == '"':
+			for i++; i < len(r) && r[i] != '"'; i++ {
+				if r[i] == '\\' {
+					i++
+				}
+			}
+			i++
+		case c == '_' || unicode.IsLetter(c):
+			for i < len(r) && (r[i] == '_' || unicode.IsLetter(r[i]) || unicode.IsDigit(r[i])) {
+				i++
+			}
+		case unicode.IsDigit(c):
+			for i < len(r) && (unicode.IsLetter(r[i]) || unicode.IsDigit(r[i]) || strings.ContainsRune(":.+-", r[i])) {
+				i++
+			}
+		case i+1 < len(r) && slices.Contains([]string{"|>", "=>", "==", "!=", "<=", ">=", "<-", "=~", "!~"}, string(r[i:i+2])):
+			i += 2
+		default:
+			i++
+		}
+		if i > len(r) {
+			i = len(r)
+		}
+		tokens = append(tokens, string(r[start:i]))
+	}
+	return tokens
+}
+
+func removeUnusedImports(tokens []string) []string {
+	var out []string
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] == "import" && i+1 < len(tokens) {
+			pkg := strings.Trim(tokens[i+1], `"`)
+			used := false
+			for j := i + 2; j+1 < len(tokens); j++ {
+				if tokens[j] == pkg && tokens[j+1] == "." {
+					used = true
+					break
+				}
+			}
+			if !used {
+				i++
+				continue
+			}
+		}
+		out = append(out, tokens[i])
+	}
+	return out
+}
+
+func removeDefaults(tokens []string) []string {
+	var out []string
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] == "," && i+3 < len(tokens) && tokens[i+1] == "createEmpty" && tokens[i+2] == ":" && tokens[i+3] == "true" {
+			i += 3
+			continue
+		}
+		if tokens[i] == "," && i+1 < len(tokens) && (tokens[i+1] == ")" || tokens[i+1] == "}" || tokens[i+1] == "]") {
+			continue
+		}
+		out = append(out, tokens[i])
+	}
+	return out
+}
+
+var keyIdentifier = regexp.MustCompile(`^"[A-Za-z_][A-Za-z0-9_]*"$`)
+
+func unquoteKeys(tokens []string) []string {
+	for i := 1; i+1 < len(tokens); i++ {
+		prev := tokens[i-1]
+		if (prev == "{" || prev == "," || prev == "with") && tokens[i+1] == ":" && keyIdentifier.MatchString(tokens[i]) {
+			tokens[i] = strings.Trim(tokens[i], `"`)
+		}
+	}
+	return tokens
+}
+
+// closing returns the index of the bracket that closes the one at open.
+func closing(tokens []string, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func unwrapBodies(tokens []string) []string {
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i] != "=>" || tokens[i+1] != "(" || tokens[i+2] == "{" {
+			continue
+		}
+		end := closing(tokens, i+1)
+		if end < 0 || end+1 >= len(tokens) || (tokens[end+1] != ")" && tokens[end+1] != ",") {
+			continue
+		}
+		tokens = append(tokens[:end], tokens[end+1:]...)
+		tokens = append(tokens[:i+1], tokens[i+2:]...)
+	}
+	return tokens
+}
+
+func expandContains(tokens []string) []string {
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i] != "contains" || tokens[i+1] != "(" {
+			continue
+		}
+		end := closing(tokens, i+1)
+		if end < 0 {
+			continue
+		}
+		args := tokens[i+2 : end]
+		sep := slices.Index(args, ",")
+		if len(args) < 6 || args[0] != "value" || args[1] != ":" || sep < 0 || sep+3 >= len(args) || args[sep+1] != "set" || args[sep+3] != "[" {
+			continue
+		}
+		value := args[2:sep]
+		var expanded []string
+		for _, item := range args[sep+4 : len(args)-1] {
+			if item == "," {
+				continue
+			}
+			if len(expanded) > 0 {
+				expanded = append(expanded, "or")
+			}
+			expanded = append(expanded, value...)
+			expanded = append(expanded, "==", item)
+		}
+		if !(i > 0 && tokens[i-1] == "=>" && end+1 < len(tokens) && tokens[end+1] == ")") {
+			expanded = append(append([]string{"("}, expanded...), ")")
+		}
+		tokens = append(tokens[:i], append(expanded, tokens[end+1:]...)...)
+	}
+	return tokens
+}
+
+func sortGroupColumns(tokens []string) []string {
+	for i := 0; i+4 < len(tokens); i++ {
+		if tokens[i] != "group" || tokens[i+1] != "(" || tokens[i+2] != "columns" || tokens[i+3] != ":" || tokens[i+4] != "[" {
+			continue
+		}
+		end := closing(tokens, i+4)
+		var cols []string
+		for _, tok := range tokens[i+5 : end] {
+			if tok != "," {
+				cols = append(cols, tok)
+			}
+		}
+		slices.Sort(cols)
+		for j, col := range cols {
+			tokens[i+5+2*j] = col
+		}
+	}
+	return tokens
+}
+
+// quote returns a Flux string literal.
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
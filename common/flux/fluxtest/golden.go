@@ -0,0 +1,56 @@
+// Package fluxtest provides golden file assertions for Flux queries.
+package fluxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the flux queries")
+
+// Format returns the text of the query followed by its params.
+func Format(q flux.Query) ([]byte, error) {
+	params, err := json.MarshalIndent(q.Params, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString(q.Text)
+	b.WriteString("\n// params\n")
+	b.Write(params)
+	b.WriteString("\n")
+	return b.Bytes(), nil
+}
+
+// AssertGolden compares the query with the file testdata/<name>.golden.
+// Run the tests with -update to rewrite the golden files.
+func AssertGolden(t *testing.T, name string, q flux.Query) {
+	t.Helper()
+	actual, err := Format(q)
+	if err != nil {
+		t.Fatalf("failed formatting query %s: %v", name, err)
+	}
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed reading golden file %s: %v", path, err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("query %s does not match %s\n--- expected\n%s\n--- actual\n%s", name, path, expected, actual)
+	}
+}
@@ -0,0 +1,155 @@
+package flux
+
+import (
+	"strings"
+)
+
+// Pipeline is a chain of functions applied to a stream of tables with the pipe-forward operator.
+type Pipeline struct {
+	source Expr
+	steps  []Expr
+}
+
+// From returns a pipeline that reads from a bucket.
+func From(bucket any) *Pipeline {
+	return Pipe(Call("from", Arg("bucket", bucket)))
+}
+
+// Pipe returns a pipeline that starts with the given tables, e.g. a variable or a union.
+func Pipe(source Expr) *Pipeline {
+	return &Pipeline{source: source}
+}
+
+// Then appends a function call to the pipeline.
+func (p *Pipeline) Then(fn string, args ...Argument) *Pipeline {
+	p.steps = append(p.steps, Call(fn, args...))
+	return p
+}
+
+// Range filters the records by time. The stop is omitted when it is nil.
+// Start and stop may be a [time.Time], an [Expr] such as [Ago] or a variable.
+func (p *Pipeline) Range(start, stop any) *Pipeline {
+	args := []Argument{Arg("start", start)}
+	if stop != nil {
+		args = append(args, Arg("stop", stop))
+	}
+	return p.Then("range", args...)
+}
+
+// Filter keeps the records that match all the predicates that are not nil.
+// The step is skipped when all the predicates are nil.
+func (p *Pipeline) Filter(preds ...Expr) *Pipeline {
+	pred := And(preds...)
+	if pred == nil {
+		return p
+	}
+	return p.Then("filter", Argument{name: "fn", value: predicate{pred}})
+}
+
+type predicate struct {
+	expr Expr
+}
+
+func (f predicate) render(s *scope, _ string) string {
+	return "(r) => " + f.expr.render(s, "")
+}
+
+// Map applies a function to each record. The function is written as is.
+func (p *Pipeline) Map(fn string) *Pipeline {
+	return p.Then("map", Arg("fn", Raw(fn)))
+}
+
+// Group regroups the tables by the given columns, or ungroups them without columns.
+func (p *Pipeline) Group(cols ...string) *Pipeline {
+	if len(cols) == 0 {
+		return p.Then("group")
+	}
+	return p.Then("group", Arg("columns", Columns(cols...)))
+}
+
+// AggregateWindow aggregates the records in windows of the given duration.
+func (p *Pipeline) AggregateWindow(every any, fn string, createEmpty bool) *Pipeline {
+	return p.Then("aggregateWindow", Arg("every", every), Arg("fn", Var(fn)), Arg("createEmpty", createEmpty))
+}
+
+// Window groups the records in windows of the given duration.
+func (p *Pipeline) Window(every any) *Pipeline {
+	return p.Then("window", Arg("every", every))
+}
+
+// Pivot turns the values of the column keys into columns.
+func (p *Pipeline) Pivot(rowKey []string, columnKey []string, valueColumn string) *Pipeline {
+	return p.Then("pivot", Arg("rowKey", Columns(rowKey...)), Arg("columnKey", Columns(columnKey...)), Arg("valueColumn", String(valueColumn)))
+}
+
+// Keep keeps only the given columns.
+func (p *Pipeline) Keep(cols ...string) *Pipeline {
+	return p.Then("keep", Arg("columns", Columns(cols...)))
+}
+
+// Drop removes the given columns.
+func (p *Pipeline) Drop(cols ...string) *Pipeline {
+	return p.Then("drop", Arg("columns", Columns(cols...)))
+}
+
+// Rename renames columns, given as pairs of old and new names.
+func (p *Pipeline) Rename(pairs ...string) *Pipeline {
+	if len(pairs)%2 != 0 {
+		panic("flux: rename requires pairs of column names")
+	}
+	fields := make([]string, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key := pairs[i]
+		if !identifier.MatchString(key) {
+			key = quote(key)
+		}
+		fields = append(fields, key+": "+quote(pairs[i+1]))
+	}
+	return p.Then("rename", Arg("columns", Raw("{"+strings.Join(fields, ", ")+"}")))
+}
+
+// Set sets a column to the same value in every record.
+func (p *Pipeline) Set(key string, value Expr) *Pipeline {
+	return p.Then("set", Arg("key", String(key)), Arg("value", value))
+}
+
+// Sort sorts the records by the given columns.
+func (p *Pipeline) Sort(desc bool, cols ...string) *Pipeline {
+	return p.Then("sort", Arg("columns", Columns(cols...)), Arg("desc", desc))
+}
+
+// Top keeps the n records with the highest values of the given columns.
+func (p *Pipeline) Top(n int, cols ...string) *Pipeline {
+	return p.Then("top", Arg("columns", Columns(cols...)), Arg("n", n))
+}
+
+// Limit keeps the first n records.
+func (p *Pipeline) Limit(n int) *Pipeline { return p.Then("limit", Arg("n", n)) }
+
+// First keeps the first record of each table.
+func (p *Pipeline) First() *Pipeline { return p.Then("first") }
+
+// Last keeps the last record of each table.
+func (p *Pipeline) Last() *Pipeline { return p.Then("last") }
+
+// Sum sums the values of each table.
+func (p *Pipeline) Sum() *Pipeline { return p.Then("sum") }
+
+// Count counts the records of each table.
+func (p *Pipeline) Count() *Pipeline { return p.Then("count") }
+
+// ToUInt converts the values to unsigned integers.
+func (p *Pipeline) ToUInt() *Pipeline { return p.Then("toUInt") }
+
+// ToFloat converts the values to floats.
+func (p *Pipeline) ToFloat() *Pipeline { return p.Then("toFloat") }
+
+func (p *Pipeline) render(s *scope, hint string) string {
+	var b strings.Builder
+	b.WriteString(p.source.render(s, hint))
+	for _, step := range p.steps {
+		b.WriteString("\n  |> ")
+		b.WriteString(step.render(s, hint))
+	}
+	return b.String()
+}
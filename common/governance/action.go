@@ -0,0 +1,241 @@
+// Package governance decodes core, token bridge and NFT bridge governance VAAs.
+package governance
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Module is the governance module a VAA is addressed to.
+type Module string
+
+const (
+	ModuleCore        Module = "Core"
+	ModuleTokenBridge Module = "TokenBridge"
+	ModuleNFTBridge   Module = "NFTBridge"
+)
+
+// ActionType is the kind of governance action.
+type ActionType string
+
+const (
+	ActionContractUpgrade    ActionType = "contractUpgrade"
+	ActionGuardianSetUpgrade ActionType = "guardianSetUpgrade"
+	ActionSetMessageFee      ActionType = "setMessageFee"
+	ActionTransferFees       ActionType = "transferFees"
+	ActionRecoverChainID     ActionType = "recoverChainId"
+	ActionRegisterChain      ActionType = "registerChain"
+)
+
+var (
+	ErrNotGovernance     = errors.New("vaa is not a governance message")
+	ErrUnknownAction     = errors.New("unknown governance action")
+	ErrInvalidPayloadLen = errors.New("invalid governance payload length")
+)
+
+// moduleLength is the length of the left-padded module name at the start of every governance payload.
+const moduleLength = 32
+
+var modules = []Module{ModuleCore, ModuleTokenBridge, ModuleNFTBridge}
+
+// actions maps the action byte of each module to its type.
+var actions = map[Module]map[byte]ActionType{
+	ModuleCore: {
+		1: ActionContractUpgrade,
+		2: ActionGuardianSetUpgrade,
+		3: ActionSetMessageFee,
+		4: ActionTransferFees,
+		5: ActionRecoverChainID,
+	},
+	ModuleTokenBridge: {
+		1: ActionRegisterChain,
+		2: ActionContractUpgrade,
+		3: ActionRecoverChainID,
+	},
+	ModuleNFTBridge: {
+		1: ActionRegisterChain,
+		2: ActionContractUpgrade,
+		3: ActionRecoverChainID,
+	},
+}
+
+// Action is a decoded governance action.
+type Action struct {
+	ID               string
+	Module           Module
+	Type             ActionType
+	TargetChain      sdk.ChainID
+	EmitterChain     sdk.ChainID
+	EmitterAddress   string
+	Sequence         uint64
+	GuardianSetIndex uint32
+	Timestamp        time.Time
+	Details          Details
+}
+
+// Details holds the action specific fields; only the ones that apply to the action type are set.
+type Details struct {
+	NewContract         string   `bson:"newContract,omitempty" json:"newContract,omitempty"`
+	NewGuardianSetIndex *uint32  `bson:"newGuardianSetIndex,omitempty" json:"newGuardianSetIndex,omitempty"`
+	Guardians           []string `bson:"guardians,omitempty" json:"guardians,omitempty"`
+	Fee                 string   `bson:"fee,omitempty" json:"fee,omitempty"`
+	Amount              string   `bson:"amount,omitempty" json:"amount,omitempty"`
+	Recipient           string   `bson:"recipient,omitempty" json:"recipient,omitempty"`
+	EvmChainID          string   `bson:"evmChainId,omitempty" json:"evmChainId,omitempty"`
+	NewChainID          *uint16  `bson:"newChainId,omitempty" json:"newChainId,omitempty"`
+	RegisteredChain     *uint16  `bson:"registeredChain,omitempty" json:"registeredChain,omitempty"`
+	RegisteredEmitter   string   `bson:"registeredEmitter,omitempty" json:"registeredEmitter,omitempty"`
+}
+
+// IsGovernance returns true if the VAA was emitted by the governance emitter.
+func IsGovernance(v *sdk.VAA) bool {
+	return v.EmitterChain == sdk.GovernanceChain && v.EmitterAddress == sdk.GovernanceEmitter
+}
+
+// Decode decodes a governance VAA into an action.
+func Decode(v *sdk.VAA) (*Action, error) {
+	if !IsGovernance(v) {
+		return nil, ErrNotGovernance
+	}
+	payload := v.Payload
+	if len(payload) < moduleLength+1 {
+		return nil, ErrInvalidPayloadLen
+	}
+
+	module, ok := parseModule(payload[:moduleLength])
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown module %x", ErrUnknownAction, payload[:moduleLength])
+	}
+	actionType, ok := actions[module][payload[moduleLength]]
+	if !ok {
+		return nil, fmt.Errorf("%w: module %s action %d", ErrUnknownAction, module, payload[moduleLength])
+	}
+
+	a := &Action{
+		ID:               v.MessageID(),
+		Module:           module,
+		Type:             actionType,
+		EmitterChain:     v.EmitterChain,
+		EmitterAddress:   v.EmitterAddress.String(),
+		Sequence:         v.Sequence,
+		GuardianSetIndex: v.GuardianSetIndex,
+		Timestamp:        v.Timestamp,
+	}
+
+	body := payload[moduleLength+1:]
+	var err error
+	if actionType == ActionRecoverChainID {
+		err = a.decodeRecoverChainID(body)
+	} else {
+		err = a.decode(body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", module, actionType, err)
+	}
+	return a, nil
+}
+
+func parseModule(b []byte) (Module, bool) {
+	for _, m := range modules {
+		if bytes.Equal(b, moduleBytes(m)) {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+func moduleBytes(m Module) []byte {
+	b := make([]byte, moduleLength)
+	copy(b[moduleLength-len(m):], m)
+	return b
+}
+
+// decode decodes the actions that carry a target chain after the action byte.
+func (a *Action) decode(body []byte) error {
+	if len(body) < 2 {
+		return ErrInvalidPayloadLen
+	}
+	a.TargetChain = sdk.ChainID(binary.BigEndian.Uint16(body[:2]))
+	body = body[2:]
+
+	switch a.Type {
+	case ActionContractUpgrade:
+		if len(body) != 32 {
+			return ErrInvalidPayloadLen
+		}
+		a.Details.NewContract = hex.EncodeToString(body)
+	case ActionGuardianSetUpgrade:
+		if len(body) < 5 {
+			return ErrInvalidPayloadLen
+		}
+		index := binary.BigEndian.Uint32(body[:4])
+		numKeys := int(body[4])
+		keys := body[5:]
+		if len(keys) != numKeys*20 {
+			return ErrInvalidPayloadLen
+		}
+		a.Details.NewGuardianSetIndex = &index
+		for i := 0; i < numKeys; i++ {
+			a.Details.Guardians = append(a.Details.Guardians, "0x"+hex.EncodeToString(keys[i*20:(i+1)*20]))
+		}
+	case ActionSetMessageFee:
+		if len(body) != 32 {
+			return ErrInvalidPayloadLen
+		}
+		a.Details.Fee = new(big.Int).SetBytes(body).String()
+	case ActionTransferFees:
+		if len(body) != 64 {
+			return ErrInvalidPayloadLen
+		}
+		a.Details.Amount = new(big.Int).SetBytes(body[:32]).String()
+		a.Details.Recipient = hex.EncodeToString(body[32:])
+	case ActionRegisterChain:
+		if len(body) != 34 {
+			return ErrInvalidPayloadLen
+		}
+		chain := binary.BigEndian.Uint16(body[:2])
+		a.Details.RegisteredChain = &chain
+		a.Details.RegisteredEmitter = hex.EncodeToString(body[2:])
+	}
+	return nil
+}
+
+// decodeRecoverChainID decodes a recover chain id action, which has no target chain.
+func (a *Action) decodeRecoverChainID(body []byte) error {
+	if len(body) != 34 {
+		return ErrInvalidPayloadLen
+	}
+	a.Details.EvmChainID = new(big.Int).SetBytes(body[:32]).String()
+	newChainID := binary.BigEndian.Uint16(body[32:])
+	a.Details.NewChainID = &newChainID
+	return nil
+}
+
+// ParseModule returns the module matching the given name.
+func ParseModule(s string) (Module, bool) {
+	for _, m := range modules {
+		if string(m) == s {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// ParseActionType returns the action type matching the given name.
+func ParseActionType(s string) (ActionType, bool) {
+	for _, moduleActions := range actions {
+		for _, t := range moduleActions {
+			if string(t) == s {
+				return t, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,132 @@
+package governance
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func governanceVaa(payload []byte) *sdk.VAA {
+	return &sdk.VAA{
+		Version:          sdk.SupportedVAAVersion,
+		GuardianSetIndex: 4,
+		Timestamp:        time.Unix(1700000000, 0),
+		Sequence:         10,
+		EmitterChain:     sdk.GovernanceChain,
+		EmitterAddress:   sdk.GovernanceEmitter,
+		Payload:          payload,
+	}
+}
+
+func header(m Module, action byte) []byte {
+	return append(moduleBytes(m), action)
+}
+
+func uint16Bytes(v uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, v)
+}
+
+func bytes32(last byte) []byte {
+	b := make([]byte, 32)
+	b[31] = last
+	return b
+}
+
+func TestDecode(t *testing.T) {
+	emitter := bytes32(0xaa)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		module  Module
+		action  ActionType
+		target  sdk.ChainID
+		check   func(t *testing.T, d Details)
+	}{
+		{
+			name:    "core contract upgrade",
+			payload: append(append(header(ModuleCore, 1), uint16Bytes(2)...), bytes32(0x01)...),
+			module:  ModuleCore,
+			action:  ActionContractUpgrade,
+			target:  2,
+			check: func(t *testing.T, d Details) {
+				assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001", d.NewContract)
+			},
+		},
+		{
+			name:    "guardian set upgrade",
+			payload: append(append(append(header(ModuleCore, 2), uint16Bytes(0)...), 0, 0, 0, 5, 1), make([]byte, 20)...),
+			module:  ModuleCore,
+			action:  ActionGuardianSetUpgrade,
+			check: func(t *testing.T, d Details) {
+				require.NotNil(t, d.NewGuardianSetIndex)
+				assert.Equal(t, uint32(5), *d.NewGuardianSetIndex)
+				assert.Equal(t, []string{"0x0000000000000000000000000000000000000000"}, d.Guardians)
+			},
+		},
+		{
+			name:    "set message fee",
+			payload: append(append(header(ModuleCore, 3), uint16Bytes(1)...), bytes32(100)...),
+			module:  ModuleCore,
+			action:  ActionSetMessageFee,
+			target:  1,
+			check: func(t *testing.T, d Details) {
+				assert.Equal(t, "100", d.Fee)
+			},
+		},
+		{
+			name:    "token bridge register chain",
+			payload: append(append(append(header(ModuleTokenBridge, 1), uint16Bytes(0)...), uint16Bytes(23)...), emitter...),
+			module:  ModuleTokenBridge,
+			action:  ActionRegisterChain,
+			check: func(t *testing.T, d Details) {
+				require.NotNil(t, d.RegisteredChain)
+				assert.Equal(t, uint16(23), *d.RegisteredChain)
+				assert.Equal(t, "00000000000000000000000000000000000000000000000000000000000000aa", d.RegisteredEmitter)
+			},
+		},
+		{
+			name:    "nft bridge recover chain id",
+			payload: append(append(header(ModuleNFTBridge, 3), bytes32(1)...), uint16Bytes(2)...),
+			module:  ModuleNFTBridge,
+			action:  ActionRecoverChainID,
+			check: func(t *testing.T, d Details) {
+				assert.Equal(t, "1", d.EvmChainID)
+				require.NotNil(t, d.NewChainID)
+				assert.Equal(t, uint16(2), *d.NewChainID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Decode(governanceVaa(tt.payload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.module, a.Module)
+			assert.Equal(t, tt.action, a.Type)
+			assert.Equal(t, tt.target, a.TargetChain)
+			assert.Equal(t, uint64(10), a.Sequence)
+			tt.check(t, a.Details)
+		})
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	v := governanceVaa(append(header(ModuleCore, 1), uint16Bytes(2)...))
+	v.EmitterChain = sdk.ChainIDEthereum
+	_, err := Decode(v)
+	assert.ErrorIs(t, err, ErrNotGovernance)
+
+	_, err = Decode(governanceVaa(append(header(ModuleCore, 9), uint16Bytes(2)...)))
+	assert.ErrorIs(t, err, ErrUnknownAction)
+
+	_, err = Decode(governanceVaa(append(header("Unknown", 1), uint16Bytes(2)...)))
+	assert.ErrorIs(t, err, ErrUnknownAction)
+
+	_, err = Decode(governanceVaa(append(header(ModuleCore, 1), uint16Bytes(2)...)))
+	assert.ErrorIs(t, err, ErrInvalidPayloadLen)
+}
@@ -0,0 +1,60 @@
+package governance
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Indexer decodes the governance VAAs stored in the vaas collection into governance actions.
+type Indexer struct {
+	repository *Repository
+	logger     *zap.Logger
+}
+
+// NewIndexer creates a new governance indexer.
+func NewIndexer(repository *Repository, logger *zap.Logger) *Indexer {
+	return &Indexer{
+		repository: repository,
+		logger:     logger.With(zap.String("module", "GovernanceIndexer")),
+	}
+}
+
+// Index decodes the governance VAAs indexed since the VAA of the last indexed action, or all of them when fullScan
+// is set, and returns the number of actions stored.
+func (i *Indexer) Index(ctx context.Context, fullScan bool) (int, error) {
+	var from *time.Time
+	var err error
+	if !fullScan {
+		from, err = i.repository.LastVaaIndexedAt(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	docs, err := i.repository.FindGovernanceVaas(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+
+	indexed := 0
+	for _, doc := range docs {
+		v, err := sdk.Unmarshal(doc.Vaa)
+		if err != nil {
+			i.logger.Warn("failed to unmarshal governance vaa", zap.String("id", doc.ID), zap.Error(err))
+			continue
+		}
+		action, err := Decode(v)
+		if err != nil {
+			i.logger.Warn("failed to decode governance vaa", zap.String("id", doc.ID), zap.Error(err))
+			continue
+		}
+		if err := i.repository.Upsert(ctx, action, doc.IndexedAt); err != nil {
+			return indexed, err
+		}
+		indexed++
+	}
+	return indexed, nil
+}
@@ -0,0 +1,116 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ActionDoc is the governanceActions document of a decoded governance VAA.
+type ActionDoc struct {
+	ID               string     `bson:"_id"`
+	Module           Module     `bson:"module"`
+	Type             ActionType `bson:"type"`
+	TargetChain      uint16     `bson:"targetChain"`
+	EmitterChain     uint16     `bson:"emitterChain"`
+	EmitterAddress   string     `bson:"emitterAddr"`
+	Sequence         uint64     `bson:"sequence"`
+	GuardianSetIndex uint32     `bson:"guardianSetIndex"`
+	Timestamp        time.Time  `bson:"timestamp"`
+	Details          Details    `bson:"details"`
+	// VaaIndexedAt is the indexedAt of the governance VAA, the indexer resumes from the most recent one.
+	VaaIndexedAt time.Time `bson:"vaaIndexedAt"`
+	UpdatedAt    time.Time `bson:"updatedAt"`
+}
+
+// Repository reads governance VAAs and stores the decoded actions.
+type Repository struct {
+	vaas    *mongo.Collection
+	actions *mongo.Collection
+	logger  *zap.Logger
+}
+
+// NewRepository creates a new governance repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{
+		vaas:    db.Collection(repository.Vaas),
+		actions: db.Collection(repository.GovernanceActions),
+		logger:  logger.With(zap.String("module", "GovernanceRepository")),
+	}
+}
+
+// FindGovernanceVaas returns the VAAs emitted by the governance emitter indexed at or after from, in the order
+// they were indexed.
+//
+// The VAAs are selected by indexedAt rather than by timestamp, so that a VAA stored late (e.g. by a backfill)
+// is not skipped because it is older than the last indexed action.
+func (r *Repository) FindGovernanceVaas(ctx context.Context, from *time.Time) ([]repository.VaaDoc, error) {
+	filter := bson.M{
+		"emitterChain": sdk.GovernanceChain,
+		"emitterAddr":  sdk.GovernanceEmitter.String(),
+	}
+	if from != nil {
+		filter["indexedAt"] = bson.M{"$gte": *from}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "indexedAt", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := r.vaas.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []repository.VaaDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// LastVaaIndexedAt returns the indexedAt of the governance VAA of the most recent indexed action,
+// or nil if none was indexed yet.
+func (r *Repository) LastVaaIndexedAt(ctx context.Context) (*time.Time, error) {
+	var doc ActionDoc
+	opts := options.FindOne().SetSort(bson.D{{Key: "vaaIndexedAt", Value: -1}})
+	err := r.actions.FindOne(ctx, bson.M{}, opts).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// the actions indexed before the watermark was stored are indexed again.
+	if doc.VaaIndexedAt.IsZero() {
+		return nil, nil
+	}
+	return &doc.VaaIndexedAt, nil
+}
+
+// Upsert stores a decoded governance action with the indexedAt of its VAA.
+func (r *Repository) Upsert(ctx context.Context, a *Action, vaaIndexedAt time.Time) error {
+	now := time.Now()
+	doc := ActionDoc{
+		ID:               a.ID,
+		Module:           a.Module,
+		Type:             a.Type,
+		TargetChain:      uint16(a.TargetChain),
+		EmitterChain:     uint16(a.EmitterChain),
+		EmitterAddress:   a.EmitterAddress,
+		Sequence:         a.Sequence,
+		GuardianSetIndex: a.GuardianSetIndex,
+		Timestamp:        a.Timestamp,
+		Details:          a.Details,
+		VaaIndexedAt:     vaaIndexedAt,
+		UpdatedAt:        now,
+	}
+	update := bson.M{
+		"$set":         doc,
+		"$setOnInsert": repository.IndexedAt(now),
+	}
+	_, err := r.actions.UpdateByID(ctx, a.ID, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,226 @@
+package guardianset
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+type testGuardianSet struct {
+	set  common.GuardianSet
+	keys []*ecdsa.PrivateKey
+}
+
+func newTestGuardianSet(t *testing.T, index uint32, size int) testGuardianSet {
+	gs := testGuardianSet{set: common.GuardianSet{Index: index}}
+	for i := 0; i < size; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		gs.keys = append(gs.keys, key)
+		gs.set.Keys = append(gs.set.Keys, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	return gs
+}
+
+func upgradePayload(newIndex uint32, keys []eth_common.Address) []byte {
+	payload := make([]byte, 0, upgradeHeaderLength+len(keys)*addressLength)
+	payload = append(payload, sdk.CoreModule...)
+	payload = append(payload, byte(sdk.ActionGuardianSetUpdate))
+	payload = binary.BigEndian.AppendUint16(payload, 0)
+	payload = binary.BigEndian.AppendUint32(payload, newIndex)
+	payload = append(payload, byte(len(keys)))
+	for _, k := range keys {
+		payload = append(payload, k.Bytes()...)
+	}
+	return payload
+}
+
+func newUpgrade(signer testGuardianSet, signers int, next testGuardianSet, ts time.Time) *sdk.VAA {
+	v := &sdk.VAA{
+		Version:          sdk.SupportedVAAVersion,
+		GuardianSetIndex: signer.set.Index,
+		Timestamp:        ts,
+		Nonce:            1,
+		Sequence:         uint64(next.set.Index),
+		EmitterChain:     sdk.GovernanceChain,
+		EmitterAddress:   sdk.GovernanceEmitter,
+		Payload:          upgradePayload(next.set.Index, next.set.Keys),
+	}
+	for i := 0; i < signers; i++ {
+		v.AddSignature(signer.keys[i], uint8(i))
+	}
+	return v
+}
+
+func TestBuild(t *testing.T) {
+	gs0 := newTestGuardianSet(t, 0, 1)
+	gs1 := newTestGuardianSet(t, 1, 4)
+	gs2 := newTestGuardianSet(t, 2, 4)
+	ts1 := time.Unix(1650000000, 0)
+	ts2 := time.Unix(1660000000, 0)
+
+	upgrade1 := newUpgrade(gs0, 1, gs1, ts1)
+	upgrade2 := newUpgrade(gs1, 3, gs2, ts2)
+
+	// unordered and duplicated upgrades are accepted.
+	h, err := Build(gs0.set, []*sdk.VAA{upgrade2, upgrade1, upgrade2}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 3, h.Len())
+	assert.Equal(t, uint32(2), h.GetLatest().Index)
+	assert.Equal(t, gs2.set.Keys, h.GetLatest().Keys)
+
+	_, expiration, err := h.Get(0)
+	require.NoError(t, err)
+	assert.Equal(t, ts1.Add(ExpirationPeriod), expiration)
+	_, expiration, err = h.Get(2)
+	require.NoError(t, err)
+	assert.True(t, expiration.IsZero())
+
+	assert.True(t, h.IsValid(1, ts2))
+	assert.False(t, h.IsValid(1, ts2.Add(ExpirationPeriod+time.Second)))
+	assert.True(t, h.IsValid(2, time.Now()))
+	assert.False(t, h.IsValid(3, time.Now()))
+
+	_, _, err = h.Get(3)
+	assert.ErrorIs(t, err, ErrGuardianSetNotFound)
+}
+
+func TestBuild_NoQuorum(t *testing.T) {
+	gs0 := newTestGuardianSet(t, 0, 1)
+	gs1 := newTestGuardianSet(t, 1, 4)
+	gs2 := newTestGuardianSet(t, 2, 4)
+
+	upgrade1 := newUpgrade(gs0, 1, gs1, time.Now())
+	upgrade2 := newUpgrade(gs1, 2, gs2, time.Now())
+
+	_, err := Build(gs0.set, []*sdk.VAA{upgrade1, upgrade2}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestBuild_ForgedUpgrade(t *testing.T) {
+	gs0 := newTestGuardianSet(t, 0, 1)
+	gs1 := newTestGuardianSet(t, 1, 4)
+	forger := newTestGuardianSet(t, 0, 1)
+
+	forged := newUpgrade(forger, 1, gs1, time.Now())
+	_, err := Build(gs0.set, []*sdk.VAA{forged}, zap.NewNop())
+	assert.Error(t, err)
+
+	// a valid candidate for the same index wins over the forged one.
+	h, err := Build(gs0.set, []*sdk.VAA{forged, newUpgrade(gs0, 1, gs1, time.Now())}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), h.GetLatest().Index)
+}
+
+func TestBuild_Gap(t *testing.T) {
+	gs0 := newTestGuardianSet(t, 0, 1)
+	gs1 := newTestGuardianSet(t, 1, 4)
+	gs2 := newTestGuardianSet(t, 2, 4)
+
+	_, err := Build(gs0.set, []*sdk.VAA{newUpgrade(gs1, 3, gs2, time.Now())}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestBuild_MalformedUpgrade(t *testing.T) {
+	gs0 := newTestGuardianSet(t, 0, 1)
+	gs1 := newTestGuardianSet(t, 1, 4)
+
+	malformed := newUpgrade(gs0, 1, gs1, time.Now())
+	malformed.Payload = malformed.Payload[:len(malformed.Payload)-1]
+	h, err := Build(gs0.set, []*sdk.VAA{malformed, newUpgrade(gs0, 1, gs1, time.Now())}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), h.GetLatest().Index)
+}
+
+func TestHistoryVerify(t *testing.T) {
+	gs0 := newTestGuardianSet(t, 0, 1)
+	gs1 := newTestGuardianSet(t, 1, 4)
+	h, err := Build(gs0.set, []*sdk.VAA{newUpgrade(gs0, 1, gs1, time.Now())}, zap.NewNop())
+	require.NoError(t, err)
+
+	v := &sdk.VAA{
+		Version:          sdk.SupportedVAAVersion,
+		GuardianSetIndex: 1,
+		Timestamp:        time.Now(),
+		EmitterChain:     sdk.ChainIDEthereum,
+		Payload:          []byte{1, 2, 3},
+	}
+	for i := 0; i < 3; i++ {
+		v.AddSignature(gs1.keys[i], uint8(i))
+	}
+	assert.NoError(t, h.Verify(v))
+
+	v.GuardianSetIndex = 2
+	assert.Error(t, h.Verify(v))
+}
+
+func TestParseUpgrade_NotUpgrade(t *testing.T) {
+	v := &sdk.VAA{EmitterChain: sdk.ChainIDEthereum, Payload: upgradePayload(1, []eth_common.Address{{}})}
+	_, err := ParseUpgrade(v)
+	assert.ErrorIs(t, err, ErrNotGuardianSetUpgrade)
+
+	v = &sdk.VAA{EmitterChain: sdk.GovernanceChain, EmitterAddress: sdk.GovernanceEmitter, Payload: upgradePayload(1, []eth_common.Address{{}})}
+	v.Payload = v.Payload[:len(v.Payload)-1]
+	_, err = ParseUpgrade(v)
+	assert.ErrorIs(t, err, ErrInvalidUpgradePayload)
+}
+
+func TestLoadBundle(t *testing.T) {
+	gs0 := newTestGuardianSet(t, 0, 1)
+	gs1 := newTestGuardianSet(t, 1, 4)
+	raw, err := newUpgrade(gs0, 1, gs1, time.Unix(1650000000, 0)).Marshal()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "bundle.txt")
+	content := "# guardian set upgrades\n\n" + hex.EncodeToString(raw) + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	vaas, err := LoadBundle(path)
+	require.NoError(t, err)
+	require.Len(t, vaas, 1)
+
+	h, err := Build(gs0.set, vaas, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), h.GetLatest().Index)
+}
+
+func TestProviderGet(t *testing.T) {
+	ctx := context.Background()
+	p, err := NewProvider(ctx, domain.P2pTestNet, "", nil, zap.NewNop())
+	require.NoError(t, err)
+
+	gs, err := p.Get(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), gs.Index)
+
+	// a reload would fail with the missing bundle, so the unknown index is answered from the last load.
+	p.bundlePath = filepath.Join(t.TempDir(), "missing.txt")
+	_, err = p.Get(ctx, 5)
+	assert.ErrorIs(t, err, ErrGuardianSetNotFound)
+
+	// the history is loaded again once the reload interval expires.
+	p.loadedAt = time.Now().Add(-2 * reloadInterval)
+	_, err = p.Get(ctx, 5)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// a failed load is not retried before the reload interval either.
+	_, err = p.Get(ctx, 5)
+	assert.ErrorIs(t, err, ErrGuardianSetNotFound)
+	gs, err = p.Get(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), gs.Index)
+}
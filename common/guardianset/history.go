@@ -0,0 +1,151 @@
+package guardianset
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// ExpirationPeriod is the time a guardian set remains valid after it has been replaced.
+// It mirrors the guardianSetExpiry of the core bridge contracts.
+const ExpirationPeriod = 24 * time.Hour
+
+var ErrGuardianSetNotFound = errors.New("guardian set not found")
+
+// History contains every guardian set of a network along with its expiration time.
+// The current guardian set has a zero expiration time.
+type History struct {
+	sync.RWMutex
+	sets        []common.GuardianSet
+	expirations []time.Time
+}
+
+// NewHistory creates a history starting at the given genesis guardian set.
+func NewHistory(genesis common.GuardianSet) *History {
+	return &History{
+		sets:        []common.GuardianSet{genesis},
+		expirations: []time.Time{{}},
+	}
+}
+
+// Build creates a history from the genesis guardian set and a list of guardian set upgrade VAAs.
+// The upgrades may be unordered and contain duplicates; each one must be signed by a quorum of
+// the guardian set it replaces, otherwise the chain is rejected. The malformed upgrades are skipped.
+func Build(genesis common.GuardianSet, upgrades []*sdk.VAA, logger *zap.Logger) (*History, error) {
+	if genesis.Index != 0 {
+		return nil, fmt.Errorf("genesis guardian set must have index 0, got %d", genesis.Index)
+	}
+
+	parsed := make([]*Upgrade, 0, len(upgrades))
+	for _, v := range upgrades {
+		u, err := ParseUpgrade(v)
+		if err != nil {
+			logger.Warn("skipping malformed guardian set upgrade", zap.String("id", v.MessageID()), zap.Error(err))
+			continue
+		}
+		parsed = append(parsed, u)
+	}
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return parsed[i].NewIndex < parsed[j].NewIndex
+	})
+
+	h := NewHistory(genesis)
+	for i, u := range parsed {
+		latest := h.GetLatest()
+		if u.NewIndex <= latest.Index {
+			// duplicated upgrade, already applied.
+			continue
+		}
+		if err := h.Apply(u); err != nil {
+			// another candidate for the same index may still be valid.
+			if i+1 < len(parsed) && parsed[i+1].NewIndex == u.NewIndex {
+				continue
+			}
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// Apply verifies an upgrade against the current guardian set and appends the new set to the history.
+func (h *History) Apply(u *Upgrade) error {
+	h.Lock()
+	defer h.Unlock()
+
+	current := h.sets[len(h.sets)-1]
+	if u.NewIndex != current.Index+1 {
+		return fmt.Errorf("guardian set upgrade to index %d does not follow current index %d", u.NewIndex, current.Index)
+	}
+	if u.VAA.GuardianSetIndex != current.Index {
+		return fmt.Errorf("guardian set upgrade to index %d signed by guardian set %d, expected %d",
+			u.NewIndex, u.VAA.GuardianSetIndex, current.Index)
+	}
+	if err := u.VAA.Verify(current.Keys); err != nil {
+		return fmt.Errorf("guardian set upgrade to index %d: %w", u.NewIndex, err)
+	}
+
+	h.expirations[len(h.expirations)-1] = u.Timestamp.Add(ExpirationPeriod)
+	h.sets = append(h.sets, common.GuardianSet{Index: u.NewIndex, Keys: u.Keys})
+	h.expirations = append(h.expirations, time.Time{})
+	return nil
+}
+
+// Len returns the number of guardian sets in the history.
+func (h *History) Len() int {
+	h.RLock()
+	defer h.RUnlock()
+	return len(h.sets)
+}
+
+// Get returns the guardian set and its expiration time for the given index.
+func (h *History) Get(index uint32) (*common.GuardianSet, time.Time, error) {
+	h.RLock()
+	defer h.RUnlock()
+	if int(index) >= len(h.sets) {
+		return nil, time.Time{}, ErrGuardianSetNotFound
+	}
+	gs := h.sets[index]
+	return &gs, h.expirations[index], nil
+}
+
+// GetLatest returns the current guardian set.
+func (h *History) GetLatest() common.GuardianSet {
+	h.RLock()
+	defer h.RUnlock()
+	return h.sets[len(h.sets)-1]
+}
+
+// All returns a copy of every guardian set and expiration time, ordered by index.
+func (h *History) All() ([]common.GuardianSet, []time.Time) {
+	h.RLock()
+	defer h.RUnlock()
+	sets := make([]common.GuardianSet, len(h.sets))
+	copy(sets, h.sets)
+	expirations := make([]time.Time, len(h.expirations))
+	copy(expirations, h.expirations)
+	return sets, expirations
+}
+
+// IsValid returns true if the guardian set exists and had not expired at the given time.
+func (h *History) IsValid(index uint32, t time.Time) bool {
+	_, expiration, err := h.Get(index)
+	if err != nil {
+		return false
+	}
+	return expiration.IsZero() || expiration.After(t)
+}
+
+// Verify validates the VAA signatures against the guardian set it claims to be signed by.
+func (h *History) Verify(v *sdk.VAA) error {
+	gs, _, err := h.Get(v.GuardianSetIndex)
+	if err != nil {
+		return fmt.Errorf("guardian set index %d: %w", v.GuardianSetIndex, err)
+	}
+	return v.Verify(gs.Keys)
+}
@@ -0,0 +1,138 @@
+package guardianset
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Genesis returns the genesis guardian set (index 0) for the given p2p network.
+func Genesis(p2pNetwork string) (common.GuardianSet, error) {
+	var sets []common.GuardianSet
+	switch p2pNetwork {
+	case domain.P2pMainNet:
+		sets, _ = domain.GetMainnetGuardianSet()
+	case domain.P2pTestNet:
+		sets, _ = domain.GetTestnetGuardianSet()
+	default:
+		return common.GuardianSet{}, fmt.Errorf("unknown p2p network %s", p2pNetwork)
+	}
+	return sets[0], nil
+}
+
+// LoadBundle reads guardian set upgrade VAAs from a bundle file.
+// The bundle contains one hex or base64 encoded VAA per line; empty lines and lines starting with # are ignored.
+func LoadBundle(path string) ([]*sdk.VAA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var vaas []*sdk.VAA
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		raw, err := decodeVaa(text)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %s line %d: %w", path, line, err)
+		}
+		v, err := sdk.Unmarshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %s line %d: %w", path, line, err)
+		}
+		vaas = append(vaas, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vaas, nil
+}
+
+func decodeVaa(s string) ([]byte, error) {
+	if raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x")); err == nil {
+		return raw, nil
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// LoadFromDatabase reads the guardian set upgrade VAAs stored in the vaas collection.
+func LoadFromDatabase(ctx context.Context, db *mongo.Database, logger *zap.Logger) ([]*sdk.VAA, error) {
+	filter := bson.M{
+		"emitterChain": sdk.GovernanceChain,
+		"emitterAddr":  sdk.GovernanceEmitter.String(),
+	}
+	cur, err := db.Collection(repository.Vaas).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var docs []repository.VaaDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	var vaas []*sdk.VAA
+	for _, doc := range docs {
+		v, err := sdk.Unmarshal(doc.Vaa)
+		if err != nil {
+			logger.Warn("failed to unmarshal governance vaa", zap.String("id", doc.ID), zap.Error(err))
+			continue
+		}
+		if IsUpgrade(v) {
+			vaas = append(vaas, v)
+		}
+	}
+	return vaas, nil
+}
+
+// Load builds the guardian set history of a network from the upgrades found in the bundle file
+// and, when db is not nil, in the vaas collection.
+func Load(ctx context.Context, p2pNetwork, bundlePath string, db *mongo.Database, logger *zap.Logger) (*History, error) {
+	genesis, err := Genesis(p2pNetwork)
+	if err != nil {
+		return nil, err
+	}
+
+	var upgrades []*sdk.VAA
+	if bundlePath != "" {
+		vaas, err := LoadBundle(bundlePath)
+		if err != nil {
+			return nil, err
+		}
+		upgrades = append(upgrades, vaas...)
+	}
+	if db != nil {
+		vaas, err := LoadFromDatabase(ctx, db, logger)
+		if err != nil {
+			return nil, err
+		}
+		upgrades = append(upgrades, vaas...)
+	}
+
+	history, err := Build(genesis, upgrades, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("guardian set history loaded",
+		zap.Int("upgrades", len(upgrades)),
+		zap.Uint32("currentIndex", history.GetLatest().Index))
+	return history, nil
+}
@@ -0,0 +1,68 @@
+package guardianset
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// reloadInterval is the minimum time between two loads of the history, so that the requests
+// of an unknown guardian set index are answered from the last load meanwhile.
+const reloadInterval = time.Minute
+
+// Provider serves the guardian sets of a network from the history verified from the upgrade VAAs.
+// The history is loaded again when a guardian set newer than the loaded ones is requested,
+// at most once per reload interval.
+type Provider struct {
+	mu         sync.Mutex
+	history    *History
+	loadedAt   time.Time
+	p2pNetwork string
+	bundlePath string
+	db         *mongo.Database
+	logger     *zap.Logger
+}
+
+// NewProvider creates a new provider, loading the history from the bundle file and the vaas collection.
+func NewProvider(ctx context.Context, p2pNetwork, bundlePath string, db *mongo.Database, logger *zap.Logger) (*Provider, error) {
+	history, err := Load(ctx, p2pNetwork, bundlePath, db, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{
+		history:    history,
+		loadedAt:   time.Now(),
+		p2pNetwork: p2pNetwork,
+		bundlePath: bundlePath,
+		db:         db,
+		logger:     logger,
+	}, nil
+}
+
+// Get returns the guardian set for the given index.
+func (p *Provider) Get(ctx context.Context, index uint32) (*common.GuardianSet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	gs, _, err := p.history.Get(index)
+	if !errors.Is(err, ErrGuardianSetNotFound) {
+		return gs, err
+	}
+	if time.Since(p.loadedAt) < reloadInterval {
+		return nil, err
+	}
+
+	p.loadedAt = time.Now()
+	history, err := Load(ctx, p.p2pNetwork, p.bundlePath, p.db, p.logger)
+	if err != nil {
+		return nil, err
+	}
+	p.history = history
+	gs, _, err = p.history.Get(index)
+	return gs, err
+}
@@ -0,0 +1,77 @@
+package guardianset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	eth_common "github.com/ethereum/go-ethereum/common"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+const (
+	// upgradeHeaderLength is the length of module (32), action (1), chain (2), new index (4) and number of keys (1).
+	upgradeHeaderLength = 40
+	addressLength       = 20
+)
+
+var (
+	ErrNotGuardianSetUpgrade = errors.New("vaa is not a guardian set upgrade")
+	ErrInvalidUpgradePayload = errors.New("invalid guardian set upgrade payload")
+)
+
+// Upgrade is a guardian set upgrade parsed from a core governance VAA.
+type Upgrade struct {
+	NewIndex  uint32
+	Keys      []eth_common.Address
+	Timestamp time.Time
+	VAA       *sdk.VAA
+}
+
+// IsUpgrade returns true if the VAA was emitted by the governance emitter and carries a core guardian set upgrade.
+func IsUpgrade(v *sdk.VAA) bool {
+	if v.EmitterChain != sdk.GovernanceChain || v.EmitterAddress != sdk.GovernanceEmitter {
+		return false
+	}
+	if len(v.Payload) < upgradeHeaderLength {
+		return false
+	}
+	return bytes.Equal(v.Payload[:32], sdk.CoreModule) && sdk.GovernanceAction(v.Payload[32]) == sdk.ActionGuardianSetUpdate
+}
+
+// ParseUpgrade parses a guardian set upgrade VAA.
+func ParseUpgrade(v *sdk.VAA) (*Upgrade, error) {
+	if !IsUpgrade(v) {
+		return nil, ErrNotGuardianSetUpgrade
+	}
+
+	payload := v.Payload
+	chainID := binary.BigEndian.Uint16(payload[33:35])
+	if chainID != 0 {
+		return nil, fmt.Errorf("%w: unexpected target chain %d", ErrInvalidUpgradePayload, chainID)
+	}
+
+	newIndex := binary.BigEndian.Uint32(payload[35:39])
+	numKeys := int(payload[39])
+	if numKeys == 0 {
+		return nil, fmt.Errorf("%w: empty key list", ErrInvalidUpgradePayload)
+	}
+	if len(payload) != upgradeHeaderLength+numKeys*addressLength {
+		return nil, fmt.Errorf("%w: expected %d keys, got %d bytes", ErrInvalidUpgradePayload, numKeys, len(payload)-upgradeHeaderLength)
+	}
+
+	keys := make([]eth_common.Address, numKeys)
+	for i := 0; i < numKeys; i++ {
+		offset := upgradeHeaderLength + i*addressLength
+		keys[i] = eth_common.BytesToAddress(payload[offset : offset+addressLength])
+	}
+
+	return &Upgrade{
+		NewIndex:  newIndex,
+		Keys:      keys,
+		Timestamp: v.Timestamp,
+		VAA:       v,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package latency
+
+import (
+	"context"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	"go.uber.org/zap"
+)
+
+// Collector computes the phase durations of the completed transfers and writes them into the time-series storage.
+type Collector struct {
+	repository *Repository
+	writer     timeseries.Writer
+	logger     *zap.Logger
+}
+
+// NewCollector creates a new collector.
+func NewCollector(repository *Repository, writer timeseries.Writer, logger *zap.Logger) *Collector {
+	return &Collector{
+		repository: repository,
+		writer:     writer,
+		logger:     logger.With(zap.String("module", "LatencyCollector")),
+	}
+}
+
+// Collect writes the phase durations of the transfers completed in the range [from, to) and returns the number of points written.
+func (c *Collector) Collect(ctx context.Context, from, to time.Time) (int, error) {
+	transfers, err := c.repository.FindCompleted(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	points := make([]*timeseries.Point, 0, len(transfers))
+	for _, t := range transfers {
+		d := Compute(t, domain.Chains.FinalityTime(t.SourceChain))
+		points = append(points, NewPoint(t, d))
+	}
+	if len(points) == 0 {
+		return 0, nil
+	}
+	if err := c.writer.WritePoints(ctx, Bucket, points...); err != nil {
+		c.logger.Error("failed to write transfer latency points", zap.Error(err))
+		return 0, err
+	}
+	return len(points), nil
+}
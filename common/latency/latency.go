@@ -0,0 +1,118 @@
+// Package latency measures how long each phase of a cross-chain transfer takes.
+//
+// A transfer goes through the following phases:
+//   - finality wait: from the source transaction until the guardians can observe the message.
+//   - governor hold: the time the message was delayed by the governor, if any.
+//   - guardian signing: from the first observation until the signed VAA is indexed.
+//   - redemption delay: from the signed VAA until the transfer is redeemed on the target chain.
+//
+// The durations are stored in the transfer_latency measurement and aggregated into quantiles
+// to estimate the arrival time of the pending transfers.
+package latency
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Measurement is the name of the time-series measurement with the phase durations.
+const Measurement = "transfer_latency"
+
+// Bucket is the time-series bucket where the phase durations are stored.
+const Bucket = timeseries.Bucket30Days
+
+// Tags of the transfer_latency measurement.
+const (
+	TagSourceChain = "source_chain"
+	TagTargetChain = "target_chain"
+	TagAppID       = "app_id"
+)
+
+// NoAppID is the app_id tag value of the transfers without app id.
+const NoAppID = "none"
+
+// GovernorHoldThreshold is the minimum delay after the chain finality that is attributed to the governor.
+// Shorter delays are considered part of the finality wait.
+const GovernorHoldThreshold = 30 * time.Minute
+
+// Phase is a phase of a transfer, its value is the field name in the measurement.
+type Phase string
+
+const (
+	PhaseFinalityWait    Phase = "finality_wait"
+	PhaseGovernorHold    Phase = "governor_hold"
+	PhaseGuardianSigning Phase = "guardian_signing"
+	PhaseRedemptionDelay Phase = "redemption_delay"
+	PhaseTotal           Phase = "total"
+)
+
+// Phases is the list of phases in the order they happen, followed by the total.
+var Phases = []Phase{PhaseFinalityWait, PhaseGovernorHold, PhaseGuardianSigning, PhaseRedemptionDelay, PhaseTotal}
+
+// Transfer holds the timestamps of a completed transfer.
+type Transfer struct {
+	ID          string
+	SourceChain sdk.ChainID
+	TargetChain sdk.ChainID
+	AppID       string
+	// SourceAt is the time of the source transaction.
+	SourceAt time.Time
+	// ObservedAt is the time of the first guardian observation.
+	ObservedAt time.Time
+	// SignedAt is the time the signed VAA was indexed.
+	SignedAt time.Time
+	// RedeemedAt is the time of the transaction on the target chain.
+	RedeemedAt time.Time
+}
+
+// Durations are the durations of the phases of a transfer.
+type Durations map[Phase]time.Duration
+
+// Compute returns the phase durations of a transfer. The finality is the expected finality
+// time of the source chain. Negative durations, caused by clock skew between sources, are zero.
+func Compute(t Transfer, finality time.Duration) Durations {
+	untilObserved := nonNegative(t.ObservedAt.Sub(t.SourceAt))
+	finalityWait, governorHold := untilObserved, time.Duration(0)
+	if excess := untilObserved - finality; excess > GovernorHoldThreshold {
+		finalityWait, governorHold = finality, excess
+	}
+	return Durations{
+		PhaseFinalityWait:    finalityWait,
+		PhaseGovernorHold:    governorHold,
+		PhaseGuardianSigning: nonNegative(t.SignedAt.Sub(t.ObservedAt)),
+		PhaseRedemptionDelay: nonNegative(t.RedeemedAt.Sub(t.SignedAt)),
+		PhaseTotal:           nonNegative(t.RedeemedAt.Sub(t.SourceAt)),
+	}
+}
+
+// NewPoint returns the transfer_latency point of a transfer, the durations are stored in seconds.
+// The time of the point is the redemption time, offset by a hash of the transfer id.
+func NewPoint(t Transfer, d Durations) *timeseries.Point {
+	p := timeseries.NewPoint(Measurement).
+		AddTag(TagSourceChain, strconv.Itoa(int(t.SourceChain))).
+		AddTag(TagTargetChain, strconv.Itoa(int(t.TargetChain))).
+		AddTag(TagAppID, AppIDTag(t.AppID)).
+		SetTime(timeseries.UniqueTime(t.RedeemedAt, t.ID))
+	for _, phase := range Phases {
+		p.AddField(string(phase), d[phase].Seconds())
+	}
+	return p
+}
+
+// AppIDTag returns the app_id tag value of an app id.
+func AppIDTag(appID string) string {
+	if appID == "" {
+		return NoAppID
+	}
+	return appID
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
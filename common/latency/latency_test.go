@@ -0,0 +1,60 @@
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestCompute(t *testing.T) {
+	source := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transfer := Transfer{
+		SourceChain: sdk.ChainIDEthereum,
+		TargetChain: sdk.ChainIDSolana,
+		SourceAt:    source,
+		ObservedAt:  source.Add(16 * time.Minute),
+		SignedAt:    source.Add(17 * time.Minute),
+		RedeemedAt:  source.Add(20 * time.Minute),
+	}
+
+	d := Compute(transfer, 15*time.Minute)
+	assert.Equal(t, 16*time.Minute, d[PhaseFinalityWait])
+	assert.Zero(t, d[PhaseGovernorHold])
+	assert.Equal(t, time.Minute, d[PhaseGuardianSigning])
+	assert.Equal(t, 3*time.Minute, d[PhaseRedemptionDelay])
+	assert.Equal(t, 20*time.Minute, d[PhaseTotal])
+
+	// a delay longer than the threshold after the finality is a governor hold.
+	transfer.ObservedAt = source.Add(24 * time.Hour)
+	transfer.SignedAt = source.Add(24*time.Hour + time.Minute)
+	transfer.RedeemedAt = source.Add(25 * time.Hour)
+	d = Compute(transfer, 15*time.Minute)
+	assert.Equal(t, 15*time.Minute, d[PhaseFinalityWait])
+	assert.Equal(t, 24*time.Hour-15*time.Minute, d[PhaseGovernorHold])
+	assert.Equal(t, 25*time.Hour, d[PhaseTotal])
+
+	// clock skew between sources does not produce negative durations.
+	transfer.RedeemedAt = transfer.SignedAt.Add(-time.Second)
+	d = Compute(transfer, 15*time.Minute)
+	assert.Zero(t, d[PhaseRedemptionDelay])
+}
+
+func TestNewPoint(t *testing.T) {
+	redeemed := time.Date(2024, 1, 1, 0, 20, 0, 0, time.UTC)
+	transfer := Transfer{ID: "2/000000000000000000000000000000000000000000000000000000000000beef/42", SourceChain: sdk.ChainIDEthereum, TargetChain: sdk.ChainIDSolana, RedeemedAt: redeemed}
+	p := NewPoint(transfer, Durations{PhaseTotal: 20 * time.Minute})
+
+	assert.Equal(t, Measurement, p.Name())
+	assert.Equal(t, timeseries.UniqueTime(redeemed, transfer.ID), p.Time())
+	appID, _ := p.Tag(TagAppID)
+	assert.Equal(t, NoAppID, appID)
+	source, _ := p.Tag(TagSourceChain)
+	assert.Equal(t, "2", source)
+	total, _ := p.Field(string(PhaseTotal))
+	assert.Equal(t, 1200.0, total)
+	finality, _ := p.Field(string(PhaseFinalityWait))
+	assert.Equal(t, 0.0, finality)
+}
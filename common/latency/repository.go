@@ -0,0 +1,141 @@
+package latency
+
+import (
+	"context"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// transferDoc is the result of joining a global transaction with its VAA, parsed VAA and observations.
+type transferDoc struct {
+	ID           string      `bson:"_id"`
+	SourceAt     *time.Time  `bson:"sourceAt"`
+	RedeemedAt   *time.Time  `bson:"redeemedAt"`
+	TargetChain  sdk.ChainID `bson:"targetChain"`
+	VaaTime      *time.Time  `bson:"vaaTime"`
+	SignedAt     *time.Time  `bson:"signedAt"`
+	ObservedAt   *time.Time  `bson:"observedAt"`
+	EmitterChain sdk.ChainID `bson:"emitterChain"`
+	FromChain    sdk.ChainID `bson:"fromChain"`
+	ToChain      sdk.ChainID `bson:"toChain"`
+	AppIDs       []string    `bson:"appIds"`
+}
+
+// Repository reads the timestamps of the completed transfers.
+type Repository struct {
+	globalTransactions *mongo.Collection
+	logger             *zap.Logger
+}
+
+// NewRepository creates a new latency repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{
+		globalTransactions: db.Collection(repository.GlobalTransactions),
+		logger:             logger.With(zap.String("module", "LatencyRepository")),
+	}
+}
+
+// FindCompleted returns the transfers whose destination transaction was updated in the range [from, to).
+// Transfers without signed VAA or without destination timestamp are skipped.
+func (r *Repository) FindCompleted(ctx context.Context, from, to time.Time) ([]Transfer, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "destinationTx.updatedAt", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}},
+			{Key: "destinationTx.timestamp", Value: bson.D{{Key: "$ne", Value: nil}}},
+		}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: repository.Vaas},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "vaas"},
+		}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: repository.ParsedVaa},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "parsedVaa"},
+		}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: repository.Observations},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "messageId"},
+			{Key: "as", Value: "observations"},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "sourceAt", Value: "$originTx.timestamp"},
+			{Key: "redeemedAt", Value: "$destinationTx.timestamp"},
+			{Key: "targetChain", Value: "$destinationTx.chainId"},
+			{Key: "vaaTime", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$vaas.timestamp", 0}}}},
+			{Key: "signedAt", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$vaas.indexedAt", 0}}}},
+			{Key: "emitterChain", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$vaas.emitterChain", 0}}}},
+			{Key: "observedAt", Value: bson.D{{Key: "$min", Value: "$observations.indexedAt"}}},
+			{Key: "fromChain", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$parsedVaa.standardizedProperties.fromChain", 0}}}},
+			{Key: "toChain", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$parsedVaa.standardizedProperties.toChain", 0}}}},
+			{Key: "appIds", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$parsedVaa.standardizedProperties.appIds", 0}}}},
+		}}},
+	}
+
+	cur, err := r.globalTransactions.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.logger.Error("failed to aggregate completed transfers", zap.Error(err))
+		return nil, err
+	}
+	var docs []transferDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		r.logger.Error("failed to decode completed transfers", zap.Error(err))
+		return nil, err
+	}
+
+	transfers := make([]Transfer, 0, len(docs))
+	for _, doc := range docs {
+		if t, ok := doc.toTransfer(); ok {
+			transfers = append(transfers, t)
+		}
+	}
+	return transfers, nil
+}
+
+// toTransfer converts the document into a transfer, the second value is false if a required timestamp is missing.
+func (d transferDoc) toTransfer() (Transfer, bool) {
+	if d.SignedAt == nil || d.RedeemedAt == nil {
+		return Transfer{}, false
+	}
+	t := Transfer{
+		ID:          d.ID,
+		SourceChain: d.FromChain,
+		TargetChain: d.ToChain,
+		SignedAt:    *d.SignedAt,
+		RedeemedAt:  *d.RedeemedAt,
+	}
+	if t.SourceChain == sdk.ChainIDUnset {
+		t.SourceChain = d.EmitterChain
+	}
+	if t.TargetChain == sdk.ChainIDUnset {
+		t.TargetChain = d.TargetChain
+	}
+	if len(d.AppIDs) > 0 {
+		t.AppID = d.AppIDs[0]
+	}
+
+	// the source transaction timestamp is missing until the tx-tracker processes it.
+	switch {
+	case d.SourceAt != nil:
+		t.SourceAt = *d.SourceAt
+	case d.VaaTime != nil:
+		t.SourceAt = *d.VaaTime
+	default:
+		return Transfer{}, false
+	}
+	// when the observations are not available the signing phase is attributed to the finality wait.
+	if d.ObservedAt != nil {
+		t.ObservedAt = *d.ObservedAt
+	} else {
+		t.ObservedAt = t.SignedAt
+	}
+	return t, true
+}
@@ -0,0 +1,289 @@
+// Package observation detects messages that were observed by the guardians but never became a signed VAA.
+package observation
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Status is the classification of an observed message.
+type Status string
+
+const (
+	// StatusStuck is a message that reached the guardian set quorum but has no signed VAA.
+	StatusStuck Status = "stuck"
+	// StatusPartial is a message that did not reach the guardian set quorum and has no signed VAA.
+	StatusPartial Status = "partial"
+	// StatusLate is a message whose signed VAA was indexed after the threshold.
+	StatusLate Status = "late"
+)
+
+// ParseStatus parses a status, the second value is false if the status is unknown.
+func ParseStatus(s string) (Status, bool) {
+	switch status := Status(strings.ToLower(s)); status {
+	case StatusStuck, StatusPartial, StatusLate:
+		return status, true
+	default:
+		return "", false
+	}
+}
+
+// maxObservedMessages is the maximum number of messages analyzed by a query.
+const maxObservedMessages = 5000
+
+// Message is an observed message that did not become a signed VAA in time.
+type Message struct {
+	MessageID        string      `bson:"_id" json:"messageId"`
+	EmitterChain     sdk.ChainID `bson:"emitterChain" json:"emitterChain"`
+	EmitterAddr      string      `bson:"emitterAddr" json:"emitterAddr"`
+	Sequence         string      `bson:"sequence" json:"sequence"`
+	Status           Status      `bson:"status" json:"status"`
+	Signers          int         `bson:"signers" json:"signers"`
+	Quorum           int         `bson:"quorum" json:"quorum"`
+	Hashes           int         `bson:"hashes" json:"hashes"`
+	GuardianSetIndex uint32      `bson:"guardianSetIndex" json:"guardianSetIndex"`
+	FirstObservedAt  time.Time   `bson:"firstObservedAt" json:"firstObservedAt"`
+	LastObservedAt   time.Time   `bson:"lastObservedAt" json:"lastObservedAt"`
+	VaaIndexedAt     *time.Time  `bson:"vaaIndexedAt,omitempty" json:"vaaIndexedAt,omitempty"`
+}
+
+// Query defines the messages to be analyzed.
+type Query struct {
+	// From and To filter the messages by the time of their first observation.
+	From time.Time
+	To   time.Time
+	// Threshold is the time a message has to become a signed VAA.
+	Threshold time.Duration
+	ChainID   *sdk.ChainID
+	Statuses  []Status
+	Limit     int64
+}
+
+// hashSigners is the set of guardians that signed a hash of a message.
+type hashSigners struct {
+	Hash    []byte   `bson:"hash"`
+	Signers []string `bson:"signers"`
+}
+
+// observedMessage is the result of grouping the observations of a message.
+type observedMessage struct {
+	MessageID       string        `bson:"_id"`
+	EmitterChain    sdk.ChainID   `bson:"emitterChain"`
+	EmitterAddr     string        `bson:"emitterAddr"`
+	Sequence        string        `bson:"sequence"`
+	Hashes          []hashSigners `bson:"hashes"`
+	FirstObservedAt time.Time     `bson:"firstObservedAt"`
+	LastObservedAt  time.Time     `bson:"lastObservedAt"`
+	VaaIndexedAt    []time.Time   `bson:"vaaIndexedAt"`
+}
+
+// Detector groups observations by message and compares their signers with the guardian set quorum.
+type Detector struct {
+	observations *mongo.Collection
+	guardianSets *repository.GuardianSetRepository
+	logger       *zap.Logger
+}
+
+// NewDetector creates a new detector.
+func NewDetector(db *mongo.Database, logger *zap.Logger) *Detector {
+	return &Detector{
+		observations: db.Collection(repository.Observations),
+		guardianSets: repository.NewGuardianSetRepository(db, logger),
+		logger:       logger.With(zap.String("module", "ObservationDetector")),
+	}
+}
+
+// Find returns the messages first observed in the query range that did not become a signed VAA in time.
+// The messages are sorted by first observation time, oldest first. The messages enqueued by the governor
+// are skipped, and at most maxObservedMessages messages are analyzed.
+func (d *Detector) Find(ctx context.Context, q Query) ([]Message, error) {
+	guardianSets, err := d.guardianSets.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	observed, err := d.findObservedMessages(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var messages []Message
+	for _, o := range observed {
+		m, ok := classify(o, guardianSets, q.Threshold, now)
+		if !ok || !matchStatus(m.Status, q.Statuses) {
+			continue
+		}
+		messages = append(messages, m)
+		if q.Limit > 0 && int64(len(messages)) >= q.Limit {
+			break
+		}
+	}
+	return messages, nil
+}
+
+func (d *Detector) findObservedMessages(ctx context.Context, q Query) ([]observedMessage, error) {
+	match := bson.D{{Key: "indexedAt", Value: bson.D{{Key: "$gte", Value: q.From}, {Key: "$lt", Value: q.To}}}}
+	if q.ChainID != nil {
+		match = append(match, bson.E{Key: "emitterChain", Value: *q.ChainID})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		// group the signers of each message by the observed hash.
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "messageId", Value: "$messageId"}, {Key: "hash", Value: "$hash"}}},
+			{Key: "emitterChain", Value: bson.D{{Key: "$first", Value: "$emitterChain"}}},
+			{Key: "emitterAddr", Value: bson.D{{Key: "$first", Value: "$emitterAddr"}}},
+			{Key: "sequence", Value: bson.D{{Key: "$first", Value: "$sequence"}}},
+			{Key: "signers", Value: bson.D{{Key: "$addToSet", Value: "$guardianAddr"}}},
+			{Key: "firstObservedAt", Value: bson.D{{Key: "$min", Value: "$indexedAt"}}},
+			{Key: "lastObservedAt", Value: bson.D{{Key: "$max", Value: "$indexedAt"}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$_id.messageId"},
+			{Key: "emitterChain", Value: bson.D{{Key: "$first", Value: "$emitterChain"}}},
+			{Key: "emitterAddr", Value: bson.D{{Key: "$first", Value: "$emitterAddr"}}},
+			{Key: "sequence", Value: bson.D{{Key: "$first", Value: "$sequence"}}},
+			{Key: "hashes", Value: bson.D{{Key: "$push", Value: bson.D{{Key: "hash", Value: "$_id.hash"}, {Key: "signers", Value: "$signers"}}}}},
+			{Key: "firstObservedAt", Value: bson.D{{Key: "$min", Value: "$firstObservedAt"}}},
+			{Key: "lastObservedAt", Value: bson.D{{Key: "$max", Value: "$lastObservedAt"}}},
+		}}},
+		// the messages enqueued by the governor are signed when they are released.
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: repository.GovernorVaas},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "governorVaas"},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "governorVaas", Value: bson.D{{Key: "$size", Value: 0}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "firstObservedAt", Value: 1}}}},
+		{{Key: "$limit", Value: maxObservedMessages}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: repository.Vaas},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "vaas"},
+		}}},
+		{{Key: "$addFields", Value: bson.D{{Key: "vaaIndexedAt", Value: "$vaas.indexedAt"}}}},
+		{{Key: "$project", Value: bson.D{{Key: "vaas", Value: 0}, {Key: "governorVaas", Value: 0}}}},
+	}
+
+	cur, err := d.observations.Aggregate(ctx, pipeline)
+	if err != nil {
+		d.logger.Error("failed to aggregate observations", zap.Error(err))
+		return nil, err
+	}
+	var observed []observedMessage
+	if err := cur.All(ctx, &observed); err != nil {
+		d.logger.Error("failed to decode observed messages", zap.Error(err))
+		return nil, err
+	}
+	return observed, nil
+}
+
+// classify returns the message with its status, the second value is false if the message
+// became a signed VAA in time or is still within the threshold.
+func classify(o observedMessage, guardianSets []*repository.GuardianSetDoc, threshold time.Duration, now time.Time) (Message, bool) {
+	m := Message{
+		MessageID:       o.MessageID,
+		EmitterChain:    o.EmitterChain,
+		EmitterAddr:     o.EmitterAddr,
+		Sequence:        o.Sequence,
+		Hashes:          len(o.Hashes),
+		FirstObservedAt: o.FirstObservedAt,
+		LastObservedAt:  o.LastObservedAt,
+	}
+
+	// the signers of the hash with more signatures are the ones that can produce a VAA.
+	var signers []string
+	for _, h := range o.Hashes {
+		if len(h.Signers) > len(signers) {
+			signers = h.Signers
+		}
+	}
+	if gs := findGuardianSet(signers, guardianSets); gs != nil {
+		m.GuardianSetIndex = gs.GuardianSetIndex
+		m.Signers = countSigners(signers, gs)
+		m.Quorum = quorum(len(gs.Keys))
+	}
+
+	if len(o.VaaIndexedAt) > 0 {
+		vaaIndexedAt := o.VaaIndexedAt[0]
+		if vaaIndexedAt.Sub(o.FirstObservedAt) <= threshold {
+			return Message{}, false
+		}
+		m.VaaIndexedAt = &vaaIndexedAt
+		m.Status = StatusLate
+		return m, true
+	}
+
+	if now.Sub(o.FirstObservedAt) <= threshold {
+		return Message{}, false
+	}
+	if m.Quorum > 0 && m.Signers >= m.Quorum {
+		m.Status = StatusStuck
+	} else {
+		m.Status = StatusPartial
+	}
+	return m, true
+}
+
+// findGuardianSet returns the guardian set that contains more signers, the newest one if there is a tie.
+func findGuardianSet(signers []string, guardianSets []*repository.GuardianSetDoc) *repository.GuardianSetDoc {
+	sorted := make([]*repository.GuardianSetDoc, len(guardianSets))
+	copy(sorted, guardianSets)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GuardianSetIndex > sorted[j].GuardianSetIndex
+	})
+
+	var result *repository.GuardianSetDoc
+	max := -1
+	for _, gs := range sorted {
+		if count := countSigners(signers, gs); count > max {
+			result, max = gs, count
+		}
+	}
+	return result
+}
+
+// countSigners returns the number of signers that belong to the guardian set.
+func countSigners(signers []string, gs *repository.GuardianSetDoc) int {
+	keys := make(map[string]bool, len(gs.Keys))
+	for _, k := range gs.Keys {
+		keys[hex.EncodeToString(k.Address)] = true
+	}
+	var count int
+	for _, s := range signers {
+		if keys[strings.ToLower(strings.TrimPrefix(s, "0x"))] {
+			count++
+		}
+	}
+	return count
+}
+
+// quorum returns the minimum number of signatures required for a guardian set of the given size.
+func quorum(numGuardians int) int {
+	return (numGuardians*2)/3 + 1
+}
+
+func matchStatus(status Status, statuses []Status) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package observation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+)
+
+func newGuardianSet(index uint32, size int) *repository.GuardianSetDoc {
+	gs := &repository.GuardianSetDoc{GuardianSetIndex: index}
+	for i := 0; i < size; i++ {
+		gs.Keys = append(gs.Keys, repository.GuardianSetKeyDoc{Index: uint32(i), Address: []byte{byte(index), byte(i)}})
+	}
+	return gs
+}
+
+func signers(index uint32, n int) []string {
+	var s []string
+	for i := 0; i < n; i++ {
+		s = append(s, fmt.Sprintf("0x%02X%02X", index, i))
+	}
+	return s
+}
+
+func TestClassify(t *testing.T) {
+	now := time.Now()
+	threshold := 30 * time.Minute
+	guardianSets := []*repository.GuardianSetDoc{newGuardianSet(3, 19), newGuardianSet(4, 19)}
+
+	tests := []struct {
+		name     string
+		observed observedMessage
+		ok       bool
+		status   Status
+		signers  int
+	}{
+		{
+			name: "stuck",
+			observed: observedMessage{
+				Hashes:          []hashSigners{{Hash: []byte{1}, Signers: signers(4, 13)}},
+				FirstObservedAt: now.Add(-time.Hour),
+			},
+			ok:      true,
+			status:  StatusStuck,
+			signers: 13,
+		},
+		{
+			name: "partial",
+			observed: observedMessage{
+				Hashes:          []hashSigners{{Hash: []byte{1}, Signers: signers(4, 12)}},
+				FirstObservedAt: now.Add(-time.Hour),
+			},
+			ok:      true,
+			status:  StatusPartial,
+			signers: 12,
+		},
+		{
+			name: "partial with guardian disagreement",
+			observed: observedMessage{
+				Hashes: []hashSigners{
+					{Hash: []byte{1}, Signers: signers(4, 10)},
+					{Hash: []byte{2}, Signers: signers(4, 19)[10:]},
+				},
+				FirstObservedAt: now.Add(-time.Hour),
+			},
+			ok:      true,
+			status:  StatusPartial,
+			signers: 10,
+		},
+		{
+			name: "pending within threshold",
+			observed: observedMessage{
+				Hashes:          []hashSigners{{Hash: []byte{1}, Signers: signers(4, 5)}},
+				FirstObservedAt: now.Add(-time.Minute),
+			},
+		},
+		{
+			name: "late",
+			observed: observedMessage{
+				Hashes:          []hashSigners{{Hash: []byte{1}, Signers: signers(3, 19)}},
+				FirstObservedAt: now.Add(-2 * time.Hour),
+				VaaIndexedAt:    []time.Time{now.Add(-time.Hour)},
+			},
+			ok:      true,
+			status:  StatusLate,
+			signers: 19,
+		},
+		{
+			name: "signed in time",
+			observed: observedMessage{
+				Hashes:          []hashSigners{{Hash: []byte{1}, Signers: signers(4, 13)}},
+				FirstObservedAt: now.Add(-2 * time.Hour),
+				VaaIndexedAt:    []time.Time{now.Add(-2 * time.Hour).Add(time.Minute)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ok := classify(tt.observed, guardianSets, threshold, now)
+			assert.Equal(t, tt.ok, ok)
+			if !tt.ok {
+				return
+			}
+			assert.Equal(t, tt.status, m.Status)
+			assert.Equal(t, tt.signers, m.Signers)
+			assert.Equal(t, 13, m.Quorum)
+		})
+	}
+}
+
+func TestFindGuardianSet(t *testing.T) {
+	guardianSets := []*repository.GuardianSetDoc{newGuardianSet(3, 19), newGuardianSet(4, 19)}
+	assert.Equal(t, uint32(3), findGuardianSet(signers(3, 5), guardianSets).GuardianSetIndex)
+	assert.Equal(t, uint32(4), findGuardianSet(signers(4, 5), guardianSets).GuardianSetIndex)
+	assert.Equal(t, uint32(4), findGuardianSet(nil, guardianSets).GuardianSetIndex)
+	assert.Nil(t, findGuardianSet(signers(4, 5), nil))
+}
+
+func TestParseStatus(t *testing.T) {
+	status, ok := ParseStatus("STUCK")
+	assert.True(t, ok)
+	assert.Equal(t, StatusStuck, status)
+	_, ok = ParseStatus("unknown")
+	assert.False(t, ok)
+}
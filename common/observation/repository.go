@@ -0,0 +1,75 @@
+package observation
+
+import (
+	"context"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository stores the messages found by the detector, so that they are read without
+// grouping the observations on every request.
+type Repository struct {
+	messages *mongo.Collection
+	logger   *zap.Logger
+}
+
+// NewRepository creates a new stuck messages repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{
+		messages: db.Collection(repository.StuckMessages),
+		logger:   logger.With(zap.String("module", "StuckMessagesRepository")),
+	}
+}
+
+// Upsert stores the messages, replacing the previous status of the messages already stored.
+func (r *Repository) Upsert(ctx context.Context, messages []Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(messages))
+	for _, m := range messages {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": m.MessageID}).
+			SetUpdate(bson.M{"$set": m, "$currentDate": bson.M{"updatedAt": true}, "$setOnInsert": repository.IndexedAt(now)}).
+			SetUpsert(true))
+	}
+	_, err := r.messages.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		r.logger.Error("failed to upsert stuck messages", zap.Int("count", len(messages)), zap.Error(err))
+	}
+	return err
+}
+
+// Find returns the stored messages first observed in the query range, oldest first.
+// The threshold of the query is ignored, it is the one of the job that stored the messages.
+func (r *Repository) Find(ctx context.Context, q Query) ([]Message, error) {
+	filter := bson.D{{Key: "firstObservedAt", Value: bson.D{{Key: "$gte", Value: q.From}, {Key: "$lt", Value: q.To}}}}
+	if q.ChainID != nil {
+		filter = append(filter, bson.E{Key: "emitterChain", Value: *q.ChainID})
+	}
+	if len(q.Statuses) > 0 {
+		filter = append(filter, bson.E{Key: "status", Value: bson.D{{Key: "$in", Value: q.Statuses}}})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "firstObservedAt", Value: 1}})
+	if q.Limit > 0 {
+		opts.SetLimit(q.Limit)
+	}
+
+	cur, err := r.messages.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("failed to find stuck messages", zap.Error(err))
+		return nil, err
+	}
+	var messages []Message
+	if err := cur.All(ctx, &messages); err != nil {
+		r.logger.Error("failed to decode stuck messages", zap.Error(err))
+		return nil, err
+	}
+	return messages, nil
+}
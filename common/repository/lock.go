@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockRepository stores the locks that elect the replica running a periodic task.
+type LockRepository struct {
+	locks *mongo.Collection
+}
+
+// NewLockRepository creates a new lock repository.
+func NewLockRepository(db *mongo.Database) *LockRepository {
+	return &LockRepository{locks: db.Collection(Locks)}
+}
+
+// AcquireLock takes or renews the lock of the given name for the owner until ttl elapses.
+// It returns false when the lock is held by another owner.
+func (r *LockRepository) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.D{
+		{Key: "_id", Value: name},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "owner", Value: owner}},
+			bson.D{{Key: "expiresAt", Value: bson.D{{Key: "$lte", Value: now}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "owner", Value: owner},
+		{Key: "expiresAt", Value: now.Add(ttl)},
+	}}}
+
+	_, err := r.locks.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// the lock exists and neither belongs to the owner nor is expired.
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	return true, nil
+}
@@ -9,4 +9,15 @@ const (
 	NodeGovernorVaas = "nodeGovernorVaas"
 	GovernorVaas     = "governorVaas"
 	Observations     = "observations"
+
+	ConflictingObservations = "conflictingObservations"
+	StuckMessages           = "stuckMessages"
+	GovernanceActions       = "governanceActions"
+	Emitters                = "emitters"
+	TxHashQueue             = "txHashQueue"
+	GlobalTransactions      = "globalTransactions"
+	ParsedVaa               = "parsedVaa"
+	AddressLinks            = "addressLinks"
+	AddressGraph            = "addressGraph"
+	Locks                   = "locks"
 )
@@ -62,7 +62,7 @@ func (r *AddressRepository) GetNativeTokenTransferTopAddress(ctx context.Context
 
 func (r *AddressRepository) getNativeTokenTransferTopAddress(ctx context.Context, symbol string, isNotional bool) ([]NativeTokenTransferTopAddress, error) {
 	query := buildNTTTopAddress(r.bucketInfiniteRetention, symbol, isNotional, time.Now())
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		r.logger.Error("failed to query ntt top address", zap.Error(err))
 		return nil, err
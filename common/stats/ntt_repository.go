@@ -62,7 +62,7 @@ func (r *NTTRepository) GetNativeTokenTransferMedian(ctx context.Context, symbol
 
 func (r *NTTRepository) getNativeTokenTransferMedian(ctx context.Context, symbol string) (decimal.Decimal, error) {
 	query := buildNTTMedianTransferSize(r.bucketInfiniteRetention, symbol)
-	result, err := r.queryAPI.Query(ctx, query)
+	result, err := r.queryAPI.QueryWithParams(ctx, query.Text, query.Params)
 	if err != nil {
 		r.logger.Error("failed to query ntt median transfer size",
 			zap.String("symbol", symbol), zap.Error(err))
@@ -1,46 +1,36 @@
 package stats
 
 import (
-	"fmt"
 	"time"
-)
-
-const queryTemplateNTTTopAddress = `
-import "influxdata/influxdb/schema"
-import "date"
-import "strings"
-
-bucket = "%s"
-start = %s
-symbol = "%s"
 
-last = from(bucket: bucket)
-  |> range(start: 1970-01-01T00:00:00Z, stop: start)
-  |> filter(fn: (r) => r._measurement == "ntt_symbol_address_1d" and r._field == "%s" )
-  |> filter(fn: (r) => r.symbol == symbol)
-  |> group(columns:["from_address"])
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+)
 
-current = from(bucket: bucket)
-	|> range(start: start)
-	|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-	|> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-	|> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume" or r._field == "from_address")
-	|> schema.fieldsAsCols()
-	|> filter(fn: (r) => r.symbol != "" and r.from_address != "")
-	|> map(fn: (r) => ({r with symbol: strings.toUpper(v: r.symbol), _value: r.volume}))
-	|> filter(fn: (r) => r.symbol == symbol)
-	|> group(columns:["from_address"])
-	|> %s()
+const nttAppID = "NATIVE_TOKEN_TRANSFER"
 
-union(tables: [current, last])
-	|> sum()
-  	|> group()
-	|> sort(columns:["_value"], desc:true)
-	|> limit(n:10)
-`
+// NTTTransfers returns the native token transfers stored in the vaa_volume_v3 measurement,
+// with the symbol, the volume and the extra fields as columns. The stop is omitted when it is nil.
+func NTTTransfers(bucket string, start, stop any, fields ...string) *flux.Pipeline {
+	fieldFilters := []flux.Expr{
+		flux.And(flux.Eq("_field", flux.String("symbol")), flux.Ne("_value", flux.String(""))),
+		flux.Eq("_field", flux.String("volume")),
+	}
+	for _, f := range fields {
+		fieldFilters = append(fieldFilters, flux.Eq("_field", flux.String(f)))
+	}
+	return flux.From(bucket).
+		Range(start, stop).
+		Filter(flux.Eq("_measurement", flux.String("vaa_volume_v3")), flux.Eq("version", flux.String("v5"))).
+		Filter(flux.Or(
+			flux.Eq("app_id_1", flux.String(nttAppID)),
+			flux.Eq("app_id_2", flux.String(nttAppID)),
+			flux.Eq("app_id_3", flux.String(nttAppID)))).
+		Filter(flux.Or(fieldFilters...)).
+		Then("schema.fieldsAsCols")
+}
 
-func buildNTTTopAddress(bucket string, symbol string, isNotional bool, t time.Time) string {
-	start := t.Truncate(time.Hour * 24).Format(time.RFC3339Nano)
+func buildNTTTopAddress(bucket string, symbol string, isNotional bool, t time.Time) flux.Query {
+	today := flux.Param("today", t.Truncate(time.Hour*24))
 	field := "total_transferred"
 	aggregation := "count"
 	if isNotional {
@@ -48,28 +38,34 @@ func buildNTTTopAddress(bucket string, symbol string, isNotional bool, t time.Ti
 		field = "total_volume_transferred"
 	}
 
-	return fmt.Sprintf(queryTemplateNTTTopAddress, bucket, start, symbol, field, aggregation)
-}
+	s := flux.NewScript("influxdata/influxdb/schema", "strings")
+	last := s.Let("last", flux.From(bucket).
+		Range(flux.Epoch, today).
+		Filter(flux.Eq("_measurement", flux.String("ntt_symbol_address_1d")), flux.Eq("_field", flux.String(field))).
+		Filter(flux.Eq("symbol", symbol)).
+		Group("from_address"))
 
-const queryTemplateNTTMedian = `
-import "influxdata/influxdb/schema"
-import "date"
+	current := s.Let("current", NTTTransfers(bucket, today, nil, "from_address").
+		Filter(flux.Ne("symbol", flux.String("")), flux.Ne("from_address", flux.String(""))).
+		Map(`(r) => ({r with symbol: strings.toUpper(v: r.symbol), _value: r.volume})`).
+		Filter(flux.Eq("symbol", symbol)).
+		Group("from_address").
+		Then(aggregation))
 
-bucket = "%s"
-from(bucket: bucket)
-  |> range(start: 2021-01-01T00:00:00Z)
-	|> filter(fn: (r) => r._measurement == "vaa_volume_v3" and r.version == "v5")
-	|> filter(fn: (r) => r.app_id_1 == "NATIVE_TOKEN_TRANSFER" or r.app_id_2 == "NATIVE_TOKEN_TRANSFER" or r.app_id_3 == "NATIVE_TOKEN_TRANSFER")
-	|> filter(fn: (r) => (r._field == "symbol" and r._value != "") or r._field == "volume")
-	|> schema.fieldsAsCols()
-	|> filter(fn: (r) => r.symbol == "%s")
-	|> map(fn: (r) => ({r with _value: r.volume}))
-	|> group()
-	|> median()
-	|> toFloat()
-	|> map(fn: (r) => ({r with _value: r._value / 100000000.0}))
-`
+	return s.Build(flux.Pipe(flux.Union(current, last)).
+		Sum().
+		Group().
+		Sort(true, "_value").
+		Limit(10))
+}
 
-func buildNTTMedianTransferSize(bucket string, symbol string) string {
-	return fmt.Sprintf(queryTemplateNTTMedian, bucket, symbol)
+func buildNTTMedianTransferSize(bucket string, symbol string) flux.Query {
+	s := flux.NewScript("influxdata/influxdb/schema")
+	return s.Build(NTTTransfers(bucket, flux.Raw("2021-01-01T00:00:00Z"), nil).
+		Filter(flux.Eq("symbol", symbol)).
+		Map(`(r) => ({r with _value: r.volume})`).
+		Group().
+		Then("median").
+		ToFloat().
+		Map(`(r) => ({r with _value: r._value / 100000000.0})`))
 }
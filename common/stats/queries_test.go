@@ -0,0 +1,18 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux/fluxtest"
+)
+
+func TestQueries_buildNTTTopAddress(t *testing.T) {
+	tm := time.Date(2024, 5, 4, 17, 9, 33, 0, time.UTC)
+	fluxtest.AssertGolden(t, "ntt_top_address_count", buildNTTTopAddress("wormscan", "W", false, tm))
+	fluxtest.AssertGolden(t, "ntt_top_address_volume", buildNTTTopAddress("wormscan", "W", true, tm))
+}
+
+func TestQueries_buildNTTMedianTransferSize(t *testing.T) {
+	fluxtest.AssertGolden(t, "ntt_median_transfer_size", buildNTTMedianTransferSize("wormscan", "W"))
+}
@@ -0,0 +1,140 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InjectSNSAttributes adds the trace context of ctx to the message attributes of a SNS message.
+// It returns the attributes, allocating them if they are nil.
+func InjectSNSAttributes(ctx context.Context, attrs map[string]types.MessageAttributeValue) map[string]types.MessageAttributeValue {
+	if attrs == nil {
+		attrs = make(map[string]types.MessageAttributeValue)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, snsAttributesCarrier(attrs))
+	return attrs
+}
+
+type snsAttributesCarrier map[string]types.MessageAttributeValue
+
+func (c snsAttributesCarrier) Get(key string) string {
+	if v, ok := c[key]; ok && v.StringValue != nil {
+		return *v.StringValue
+	}
+	return ""
+}
+
+func (c snsAttributesCarrier) Set(key, value string) {
+	c[key] = types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c snsAttributesCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// InjectSQSAttributes adds the trace context of ctx to the message attributes of a SQS message.
+// It returns the attributes, allocating them if they are nil.
+func InjectSQSAttributes(ctx context.Context, attrs map[string]sqs_types.MessageAttributeValue) map[string]sqs_types.MessageAttributeValue {
+	if attrs == nil {
+		attrs = make(map[string]sqs_types.MessageAttributeValue)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, sqsAttributesCarrier(attrs))
+	return attrs
+}
+
+// ExtractSQSAttributes returns a copy of ctx with the trace context carried by the message attributes of a SQS message.
+func ExtractSQSAttributes(ctx context.Context, attrs map[string]sqs_types.MessageAttributeValue) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, sqsAttributesCarrier(attrs))
+}
+
+type sqsAttributesCarrier map[string]sqs_types.MessageAttributeValue
+
+func (c sqsAttributesCarrier) Get(key string) string {
+	if v, ok := c[key]; ok && v.StringValue != nil {
+		return *v.StringValue
+	}
+	return ""
+}
+
+func (c sqsAttributesCarrier) Set(key, value string) {
+	c[key] = sqs_types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c sqsAttributesCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SNSMessageAttribute is a message attribute of a SNS notification delivered to a SQS queue.
+type SNSMessageAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// SNSMessageAttributes are the message attributes of the body of a SQS message sent by SNS.
+type SNSMessageAttributes map[string]SNSMessageAttribute
+
+func (c SNSMessageAttributes) Get(key string) string {
+	return c[key].Value
+}
+
+func (c SNSMessageAttributes) Set(key, value string) {
+	c[key] = SNSMessageAttribute{Type: "String", Value: value}
+}
+
+func (c SNSMessageAttributes) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Extract returns a copy of ctx with the trace context carried by the attributes.
+func (c SNSMessageAttributes) Extract(ctx context.Context) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, c)
+}
+
+// TraceContext returns the trace context of ctx, to be stored along with a document.
+// It returns nil when ctx does not carry a span.
+func TraceContext(ctx context.Context) map[string]string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// ContextWithTraceContext returns a copy of ctx with a trace context returned by TraceContext.
+func ContextWithTraceContext(ctx context.Context, traceContext map[string]string) context.Context {
+	if len(traceContext) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(traceContext))
+}
+
+var (
+	_ propagation.TextMapCarrier = snsAttributesCarrier(nil)
+	_ propagation.TextMapCarrier = sqsAttributesCarrier(nil)
+	_ propagation.TextMapCarrier = SNSMessageAttributes(nil)
+)
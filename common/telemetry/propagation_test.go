@@ -0,0 +1,44 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSNSAttributes_RoundTrip(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	attrs := InjectSNSAttributes(ctx, nil)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", *attrs["traceparent"].StringValue)
+
+	// SNS delivers the attributes to SQS in the body of the message.
+	delivered := SNSMessageAttributes{}
+	for k, v := range attrs {
+		delivered[k] = SNSMessageAttribute{Type: *v.DataType, Value: *v.StringValue}
+	}
+	got := trace.SpanContextFromContext(delivered.Extract(context.Background()))
+	assert.Equal(t, traceID, got.TraceID())
+	assert.Equal(t, spanID, got.SpanID())
+	assert.True(t, got.IsRemote())
+}
+
+func TestSNSMessageAttributes_ExtractWithoutTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctx := SNSMessageAttributes(nil).Extract(context.Background())
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
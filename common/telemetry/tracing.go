@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TrackIDKey is the span attribute that carries the track id of a message.
+const TrackIDKey = attribute.Key("wormscan.track_id")
+
+// VaaIDKey is the span attribute that carries the id of a VAA.
+const VaaIDKey = attribute.Key("wormscan.vaa_id")
+
+// Config is the tracing configuration of a service.
+type Config struct {
+	// ServiceName is the name of the service reported in the spans.
+	ServiceName string
+	// Environment is the deployment environment reported in the spans.
+	Environment string
+	// OtlpEndpoint is the host:port of the OTLP/HTTP collector. Tracing is disabled when it is empty.
+	OtlpEndpoint string
+	// OtlpInsecure disables TLS on the connection to the collector.
+	OtlpInsecure bool
+	// SampleRatio is the ratio of new traces that are sampled. Traces started
+	// by another service follow the sampling decision of their parent.
+	SampleRatio float64
+}
+
+// ShutdownFunc flushes the pending spans and releases the exporter.
+type ShutdownFunc func(context.Context) error
+
+// Setup registers the global tracer provider and the W3C trace context propagator.
+//
+// The propagator is always registered, so services without an exporter still forward
+// the trace context they receive. When no endpoint is configured the global tracer
+// provider is left as a no-op.
+func Setup(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.OtlpEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OtlpEndpoint)}
+	if cfg.OtlpInsecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("deployment.environment", cfg.Environment),
+	)
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(5*time.Second)),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns a tracer of the global tracer provider.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// End records the error, if any, in the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,306 @@
+package timeseries
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	clickHouseDefaultTable   = "points"
+	clickHouseDefaultTimeout = 30 * time.Second
+	clickHouseTimeLayout     = "2006-01-02 15:04:05.000000000"
+)
+
+// ClickHouseConfig contains the parameters to connect to a ClickHouse server.
+type ClickHouseConfig struct {
+	// URL is the address of the ClickHouse HTTP interface (e.g.: http://localhost:8123).
+	URL      string
+	Database string
+	// Table is the name of the points table. Defaults to "points".
+	Table    string
+	User     string
+	Password string
+	Timeout  time.Duration
+}
+
+// ClickHouseStore is a ClickHouse implementation of [Store].
+//
+// It talks to the ClickHouse HTTP interface and stores all the measurements in a single
+// table, with tags and fields as maps. Numeric fields are stored as Float64, any other
+// field is stored as a string.
+type ClickHouseStore struct {
+	cfg    ClickHouseConfig
+	client *http.Client
+}
+
+// NewClickHouseStore creates a new *ClickHouseStore.
+func NewClickHouseStore(cfg ClickHouseConfig) *ClickHouseStore {
+	if cfg.Table == "" {
+		cfg.Table = clickHouseDefaultTable
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = clickHouseDefaultTimeout
+	}
+	return &ClickHouseStore{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// EnsureSchema creates the points table if it does not exist.
+func (s *ClickHouseStore) EnsureSchema(ctx context.Context) error {
+	ddl := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	bucket LowCardinality(String),
+	measurement LowCardinality(String),
+	time DateTime64(9, 'UTC'),
+	tags Map(String, String),
+	fields Map(String, Float64),
+	string_fields Map(String, String)
+) ENGINE = MergeTree
+PARTITION BY toYYYYMM(time)
+ORDER BY (bucket, measurement, time)`, s.table())
+	_, err := s.do(ctx, ddl, nil, nil)
+	return err
+}
+
+// Ping checks that the ClickHouse server is reachable.
+func (s *ClickHouseStore) Ping(ctx context.Context) error {
+	_, err := s.do(ctx, "SELECT 1", nil, nil)
+	return err
+}
+
+type clickHouseRow struct {
+	Bucket       string             `json:"bucket"`
+	Measurement  string             `json:"measurement"`
+	Time         string             `json:"time"`
+	Tags         map[string]string  `json:"tags"`
+	Fields       map[string]float64 `json:"fields"`
+	StringFields map[string]string  `json:"string_fields"`
+}
+
+// WritePoints implements the Writer interface.
+func (s *ClickHouseStore) WritePoints(ctx context.Context, bucket Bucket, points ...*Point) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	count := 0
+	for _, p := range points {
+		if p == nil {
+			continue
+		}
+		row := clickHouseRow{
+			Bucket:       string(bucket),
+			Measurement:  p.measurement,
+			Time:         p.time.UTC().Format(clickHouseTimeLayout),
+			Tags:         p.tags,
+			Fields:       make(map[string]float64),
+			StringFields: make(map[string]string),
+		}
+		for k, v := range p.fields {
+			if f, ok := toFloat64(v); ok {
+				row.Fields[k] = f
+			} else {
+				row.StringFields[k] = fmt.Sprint(v)
+			}
+		}
+		if err := enc.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode point: %w", err)
+		}
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", s.table())
+	_, err := s.do(ctx, query, nil, &body)
+	return err
+}
+
+// Flush implements the Writer interface. Points are written synchronously, so there is nothing to flush.
+func (s *ClickHouseStore) Flush(_ context.Context) {}
+
+// Close implements the Writer interface.
+func (s *ClickHouseStore) Close() {
+	s.client.CloseIdleConnections()
+}
+
+// Query implements the Reader interface.
+func (s *ClickHouseStore) Query(ctx context.Context, q *Query) ([]Row, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	sql, params := BuildClickHouseQuery(s.table(), q)
+	body, err := s.do(ctx, sql, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var values map[string]interface{}
+		if err := json.Unmarshal(line, &values); err != nil {
+			return nil, fmt.Errorf("failed to decode clickhouse row: %w", err)
+		}
+		value, ok := values["value"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value type %T", values["value"])
+		}
+		row := Row{Tags: make(map[string]string, len(q.GroupBy)), Value: value}
+		for i, k := range q.GroupBy {
+			if v, ok := values[fmt.Sprintf("g%d", i)].(string); ok {
+				row.Tags[k] = v
+			}
+		}
+		if t, ok := values["t"].(float64); ok {
+			row.Time = time.Unix(int64(t), 0).UTC()
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// BuildClickHouseQuery translates a Query into a parameterized ClickHouse SQL statement.
+//
+// Every user-provided value is sent as a query parameter, never as part of the SQL text.
+func BuildClickHouseQuery(table string, q *Query) (string, map[string]string) {
+	params := map[string]string{
+		"bucket":      string(q.Bucket),
+		"measurement": q.Measurement,
+		"field":       q.Field,
+		"start":       q.Start.UTC().Format(clickHouseTimeLayout),
+		"stop":        q.stop().UTC().Format(clickHouseTimeLayout),
+	}
+
+	var selects, groups []string
+	if q.Window > 0 {
+		params["window"] = strconv.FormatInt(int64(q.Window/time.Second), 10)
+		selects = append(selects, "toUnixTimestamp(toStartOfInterval(time, INTERVAL {window:UInt32} SECOND)) AS t")
+		groups = append(groups, "t")
+	}
+	for i, k := range q.GroupBy {
+		params[fmt.Sprintf("gk%d", i)] = k
+		selects = append(selects, fmt.Sprintf("tags[{gk%d:String}] AS g%d", i, i))
+		groups = append(groups, fmt.Sprintf("g%d", i))
+	}
+	switch q.Aggregate {
+	case AggregateCount:
+		selects = append(selects, "toFloat64(count()) AS value")
+	case AggregateQuantile:
+		params["quantile"] = strconv.FormatFloat(q.Quantile, 'f', -1, 64)
+		selects = append(selects, "quantile({quantile:Float64})(fields[{field:String}]) AS value")
+	case AggregateLast:
+		selects = append(selects, "argMax(fields[{field:String}], time) AS value")
+	default:
+		selects = append(selects, "sum(fields[{field:String}]) AS value")
+	}
+
+	where := []string{
+		"bucket = {bucket:String}",
+		"measurement = {measurement:String}",
+		"time >= {start:DateTime64(9, 'UTC')}",
+		"time < {stop:DateTime64(9, 'UTC')}",
+		"(mapContains(fields, {field:String}) OR mapContains(string_fields, {field:String}))",
+	}
+	for i, k := range sortedKeys(q.Include) {
+		params[fmt.Sprintf("ik%d", i)] = k
+		params[fmt.Sprintf("iv%d", i)] = clickHouseArray(q.Include[k])
+		where = append(where, fmt.Sprintf("tags[{ik%d:String}] IN {iv%d:Array(String)}", i, i))
+	}
+	for i, k := range sortedKeys(q.Exclude) {
+		params[fmt.Sprintf("ek%d", i)] = k
+		params[fmt.Sprintf("ev%d", i)] = clickHouseArray(q.Exclude[k])
+		where = append(where, fmt.Sprintf("tags[{ek%d:String}] NOT IN {ev%d:Array(String)}", i, i))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "SELECT %s\nFROM %s\nWHERE %s\n", strings.Join(selects, ", "), table, strings.Join(where, "\n  AND "))
+	if len(groups) > 0 {
+		fmt.Fprintf(&b, "GROUP BY %s\n", strings.Join(groups, ", "))
+	}
+	if q.Window > 0 {
+		b.WriteString("ORDER BY t, value DESC\n")
+	} else {
+		b.WriteString("ORDER BY value DESC\n")
+	}
+	if q.Limit > 0 {
+		fmt.Fprintf(&b, "LIMIT %d\n", q.Limit)
+	}
+	b.WriteString("FORMAT JSONEachRow")
+	return b.String(), params
+}
+
+func (s *ClickHouseStore) table() string {
+	if s.cfg.Database == "" {
+		return s.cfg.Table
+	}
+	return s.cfg.Database + "." + s.cfg.Table
+}
+
+// do sends a statement to the ClickHouse HTTP interface and returns the response body.
+func (s *ClickHouseStore) do(ctx context.Context, query string, params map[string]string, body io.Reader) ([]byte, error) {
+	values := url.Values{}
+	for k, v := range params {
+		values.Set("param_"+k, v)
+	}
+
+	// statements are sent in the request body, except for inserts that send the data instead.
+	if body == nil {
+		body = strings.NewReader(query)
+	} else {
+		values.Set("query", query)
+	}
+
+	endpoint := strings.TrimSuffix(s.cfg.URL, "/") + "/?" + values.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.User != "" {
+		req.Header.Set("X-ClickHouse-User", s.cfg.User)
+		req.Header.Set("X-ClickHouse-Key", s.cfg.Password)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("clickhouse request failed with status %d: %s", res.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// clickHouseArray formats a list of strings as a ClickHouse array literal.
+func clickHouseArray(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ReplaceAll(v, `\`, `\\`)
+		v = strings.ReplaceAll(v, `'`, `\'`)
+		quoted = append(quoted, "'"+v+"'")
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
@@ -0,0 +1,161 @@
+package timeseries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux"
+)
+
+// InfluxStore is an InfluxDB v2 implementation of [Store].
+type InfluxStore struct {
+	client   influxdb2.Client
+	queryAPI api.QueryAPI
+	buckets  map[Bucket]string
+	writers  map[Bucket]api.WriteAPIBlocking
+}
+
+// NewInfluxStore creates a new *InfluxStore.
+//
+// The buckets parameter maps each Bucket to the name of the InfluxDB bucket, and
+// batched lists the buckets whose writes are buffered until the next flush.
+func NewInfluxStore(client influxdb2.Client, org string, buckets map[Bucket]string, batched ...Bucket) *InfluxStore {
+	writers := make(map[Bucket]api.WriteAPIBlocking, len(buckets))
+	for b, name := range buckets {
+		writers[b] = client.WriteAPIBlocking(org, name)
+	}
+	for _, b := range batched {
+		if w, ok := writers[b]; ok {
+			w.EnableBatching()
+		}
+	}
+	return &InfluxStore{
+		client:   client,
+		queryAPI: client.QueryAPI(org),
+		buckets:  buckets,
+		writers:  writers,
+	}
+}
+
+// WritePoints implements the Writer interface.
+func (s *InfluxStore) WritePoints(ctx context.Context, bucket Bucket, points ...*Point) error {
+	w, ok := s.writers[bucket]
+	if !ok {
+		return fmt.Errorf("unknown bucket: %s", bucket)
+	}
+	influxPoints := make([]*write.Point, 0, len(points))
+	for _, p := range points {
+		if p != nil {
+			influxPoints = append(influxPoints, ToInfluxPoint(p))
+		}
+	}
+	return w.WritePoint(ctx, influxPoints...)
+}
+
+// Flush implements the Writer interface.
+func (s *InfluxStore) Flush(ctx context.Context) {
+	for _, w := range s.writers {
+		w.Flush(ctx)
+	}
+}
+
+// Close implements the Writer interface.
+func (s *InfluxStore) Close() {
+	s.client.Close()
+}
+
+// Query implements the Reader interface.
+func (s *InfluxStore) Query(ctx context.Context, q *Query) ([]Row, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	bucket, ok := s.buckets[q.Bucket]
+	if !ok {
+		return nil, fmt.Errorf("unknown bucket: %s", q.Bucket)
+	}
+
+	fq := BuildFluxQuery(bucket, q)
+	result, err := s.queryAPI.QueryWithParams(ctx, fq.Text, fq.Params)
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var rows []Row
+	for result.Next() {
+		record := result.Record()
+		value, ok := toFloat64(record.Value())
+		if !ok {
+			return nil, fmt.Errorf("unexpected value type %T", record.Value())
+		}
+		row := Row{Tags: make(map[string]string, len(q.GroupBy)), Value: value}
+		for _, k := range q.GroupBy {
+			if v, ok := record.ValueByKey(k).(string); ok {
+				row.Tags[k] = v
+			}
+		}
+		if q.Window > 0 {
+			row.Time = record.Start().UTC()
+		}
+		rows = append(rows, row)
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	return sortRows(rows, q.Limit), nil
+}
+
+// BuildFluxQuery translates a Query into a Flux script.
+func BuildFluxQuery(bucket string, q *Query) flux.Query {
+	p := flux.From(bucket).
+		Range(q.Start, q.stop()).
+		Filter(flux.Eq("_measurement", q.Measurement), flux.Eq("_field", q.Field))
+	for _, k := range sortedKeys(q.Include) {
+		if len(q.Include[k]) == 0 {
+			p.Filter(flux.Raw("false"))
+			continue
+		}
+		p.Filter(flux.In(k, q.Include[k]...))
+	}
+	for _, k := range sortedKeys(q.Exclude) {
+		if len(q.Exclude[k]) > 0 {
+			p.Filter(flux.Not(flux.In(k, q.Exclude[k]...)))
+		}
+	}
+	p.Group(q.GroupBy...)
+	if q.Window > 0 {
+		p.Window(flux.Duration(fmt.Sprintf("%ds", int64(q.Window/time.Second))))
+	}
+	switch q.Aggregate {
+	case AggregateCount:
+		p.Count()
+	case AggregateQuantile:
+		p.ToFloat().Then("quantile", flux.Arg("q", q.Quantile), flux.Arg("method", flux.String("estimate_tdigest")))
+	case AggregateLast:
+		p.ToFloat().Last()
+	default:
+		p.ToFloat().Sum()
+	}
+	if q.Limit > 0 {
+		p.Group().Top(q.Limit, "_value")
+	}
+	return flux.NewScript().Build(p)
+}
+
+// ToInfluxPoint converts a Point into an InfluxDB point.
+func ToInfluxPoint(p *Point) *write.Point {
+	point := influxdb2.NewPointWithMeasurement(p.measurement)
+	for _, t := range p.TagList() {
+		point.AddTag(t.Key, t.Value)
+	}
+	for _, f := range p.FieldList() {
+		point.AddField(f.Key, f.Value)
+	}
+	return point.SetTime(p.time)
+}
@@ -0,0 +1,32 @@
+package timeseries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/flux/fluxtest"
+)
+
+func TestBuildFluxQuery(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	q := &Query{
+		Bucket:      BucketInfinite,
+		Measurement: "vaa_volume_v2",
+		Field:       "volume",
+		Start:       start,
+		Stop:        start.Add(7 * 24 * time.Hour),
+		Include:     map[string][]string{"emitter_chain": {"2", "30"}},
+		Exclude:     map[string][]string{"app_id": {"MAYAN"}},
+		GroupBy:     []string{"emitter_chain"},
+		Aggregate:   AggregateSum,
+		Window:      24 * time.Hour,
+	}
+	fluxtest.AssertGolden(t, "volume_by_chain", BuildFluxQuery("wormscan", q))
+
+	q.Aggregate = AggregateQuantile
+	q.Quantile = 0.95
+	q.Window = 0
+	q.Limit = 5
+	q.Exclude = nil
+	fluxtest.AssertGolden(t, "quantile", BuildFluxQuery("wormscan", q))
+}
@@ -0,0 +1,150 @@
+package timeseries
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory implementation of [Store].
+//
+// It is intended for tests and local development, points are never evicted.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	points map[Bucket][]*Point
+}
+
+// NewMemoryStore creates a new empty *MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{points: make(map[Bucket][]*Point)}
+}
+
+// WritePoints implements the Writer interface.
+func (s *MemoryStore) WritePoints(_ context.Context, bucket Bucket, points ...*Point) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range points {
+		if p == nil {
+			continue
+		}
+		s.points[bucket] = append(s.points[bucket], p)
+	}
+	return nil
+}
+
+// Flush implements the Writer interface.
+func (s *MemoryStore) Flush(_ context.Context) {}
+
+// Close implements the Writer interface.
+func (s *MemoryStore) Close() {}
+
+// Points returns the points written into a bucket.
+func (s *MemoryStore) Points(bucket Bucket) []*Point {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]*Point, len(s.points[bucket]))
+	copy(result, s.points[bucket])
+	return result
+}
+
+// Query implements the Reader interface.
+func (s *MemoryStore) Query(_ context.Context, q *Query) ([]Row, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stop := q.stop()
+	groups := make(map[string]*Row)
+	values := make(map[string][]float64)
+	lastTimes := make(map[string]time.Time)
+	var keys []string
+	for _, p := range s.points[q.Bucket] {
+		if p.measurement != q.Measurement {
+			continue
+		}
+		if p.time.Before(q.Start) || !p.time.Before(stop) {
+			continue
+		}
+		value, ok := p.fields[q.Field]
+		if !ok {
+			continue
+		}
+		if !matchTags(p, q.Include, true) || !matchTags(p, q.Exclude, false) {
+			continue
+		}
+
+		tags := make(map[string]string, len(q.GroupBy))
+		tagValues := make([]string, 0, len(q.GroupBy))
+		for _, k := range q.GroupBy {
+			tags[k] = p.tags[k]
+			tagValues = append(tagValues, p.tags[k])
+		}
+		var start time.Time
+		if q.Window > 0 {
+			start = time.Unix(0, 0).UTC().Add(p.time.Sub(time.Unix(0, 0)) / q.Window * q.Window)
+			tagValues = append(tagValues, start.String())
+		}
+		key := strings.Join(tagValues, "\x00")
+		row, ok := groups[key]
+		if !ok {
+			row = &Row{Tags: tags, Time: start}
+			groups[key] = row
+			keys = append(keys, key)
+		}
+
+		switch q.Aggregate {
+		case AggregateCount:
+			row.Value++
+		case AggregateSum:
+			if f, ok := toFloat64(value); ok {
+				row.Value += f
+			}
+		case AggregateQuantile:
+			if f, ok := toFloat64(value); ok {
+				values[key] = append(values[key], f)
+			}
+		case AggregateLast:
+			if f, ok := toFloat64(value); ok && !p.time.Before(lastTimes[key]) {
+				row.Value = f
+				lastTimes[key] = p.time
+			}
+		}
+	}
+
+	rows := make([]Row, 0, len(keys))
+	for _, k := range keys {
+		row := *groups[k]
+		if q.Aggregate == AggregateQuantile {
+			row.Value = quantile(values[k], q.Quantile)
+		}
+		rows = append(rows, row)
+	}
+	return sortRows(rows, q.Limit), nil
+}
+
+// matchTags checks the tag filters of a query.
+//
+// When include is true, the point must have one of the listed values for every tag.
+// Otherwise, the point must not have any of the listed values.
+func matchTags(p *Point, filters map[string][]string, include bool) bool {
+	for key, values := range filters {
+		found := false
+		tag, ok := p.tags[key]
+		if ok {
+			for _, v := range values {
+				if tag == v {
+					found = true
+					break
+				}
+			}
+		}
+		if found != include {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+package timeseries
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Query(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore()
+
+	volume := func(emitterChain, appID string, value uint64, ts time.Time) *Point {
+		return NewPoint("vaa_volume_v2").
+			AddTag("emitter_chain", emitterChain).
+			AddTag("app_id", appID).
+			AddField("volume", value).
+			SetTime(ts)
+	}
+	err := store.WritePoints(ctx, BucketInfinite,
+		volume("2", "PORTAL_TOKEN_BRIDGE", 100, now.Add(-time.Hour)),
+		volume("2", "PORTAL_TOKEN_BRIDGE", 50, now.Add(-2*time.Hour)),
+		volume("1", "PORTAL_TOKEN_BRIDGE", 120, now.Add(-time.Hour)),
+		volume("5", "MAYAN", 500, now.Add(-time.Hour)),
+		volume("4", "PORTAL_TOKEN_BRIDGE", 900, now.Add(-48*time.Hour)),
+		NewPoint("vaa_count").AddTag("chain_id", "2").AddField("count", 1).SetTime(now.Add(-time.Hour)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	q := &Query{
+		Bucket:      BucketInfinite,
+		Measurement: "vaa_volume_v2",
+		Field:       "volume",
+		Start:       now.Add(-24 * time.Hour),
+		Exclude:     map[string][]string{"app_id": {"MAYAN"}},
+		GroupBy:     []string{"emitter_chain"},
+		Aggregate:   AggregateSum,
+	}
+	rows, err := store.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].Tags["emitter_chain"] != "2" || rows[0].Value != 150 {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Tags["emitter_chain"] != "1" || rows[1].Value != 120 {
+		t.Errorf("unexpected second row: %+v", rows[1])
+	}
+
+	q.Exclude = nil
+	q.Include = map[string][]string{"app_id": {"MAYAN"}}
+	q.Aggregate = AggregateCount
+	q.GroupBy = nil
+	rows, err = store.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Value != 1 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+
+	q.Include = nil
+	q.GroupBy = []string{"emitter_chain"}
+	q.Start = time.Time{}
+	q.Limit = 1
+	rows, err = store.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Tags["emitter_chain"] != "2" || rows[0].Value != 2 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+
+	q.GroupBy = nil
+	q.Limit = 0
+	q.Aggregate = AggregateLast
+	rows, err = store.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || (rows[0].Value != 100 && rows[0].Value != 120 && rows[0].Value != 500) {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+}
+
+func TestQuery_Validate(t *testing.T) {
+	q := &Query{Measurement: "vaa_count", Field: "count", Aggregate: "median"}
+	if err := q.Validate(); err == nil {
+		t.Error("expected error for an unsupported aggregate")
+	}
+	q.Aggregate = AggregateCount
+	if err := q.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	q.Aggregate = AggregateQuantile
+	q.Quantile = 1.5
+	if err := q.Validate(); err == nil {
+		t.Error("expected error for a quantile out of range")
+	}
+	q = &Query{Measurement: "vaa_count", Field: "count", Aggregate: AggregateCount, Window: time.Hour, Limit: 10}
+	if err := q.Validate(); err == nil {
+		t.Error("expected error for a limit with window")
+	}
+}
+
+func TestMemoryStore_QueryQuantile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore()
+
+	for i := 1; i <= 10; i++ {
+		err := store.WritePoints(ctx, Bucket30Days, NewPoint("transfer_latency").
+			AddTag("source_chain", "2").
+			AddField("total", float64(i*60)).
+			SetTime(now.Add(-time.Duration(i)*time.Minute)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	q := &Query{
+		Bucket:      Bucket30Days,
+		Measurement: "transfer_latency",
+		Field:       "total",
+		Start:       now.Add(-time.Hour),
+		GroupBy:     []string{"source_chain"},
+		Aggregate:   AggregateQuantile,
+		Quantile:    0.5,
+	}
+	rows, err := store.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Value != 300 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+
+	q.Quantile = 0.9
+	rows, err = store.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Value != 540 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+}
+
+func TestMemoryStore_QueryWindow(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+
+	fee := func(chainID string, value float64, ts time.Time) *Point {
+		return NewPoint("transaction_fees").AddTag("chain_id", chainID).AddField("fee_usd", value).SetTime(ts)
+	}
+	err := store.WritePoints(ctx, BucketInfinite,
+		fee("2", 1, day.Add(time.Hour)),
+		fee("2", 2, day.Add(2*time.Hour)),
+		fee("2", 4, day.Add(25*time.Hour)),
+		fee("1", 8, day.Add(3*time.Hour)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := store.Query(ctx, &Query{
+		Bucket:      BucketInfinite,
+		Measurement: "transaction_fees",
+		Field:       "fee_usd",
+		Start:       day,
+		Stop:        day.Add(48 * time.Hour),
+		GroupBy:     []string{"chain_id"},
+		Aggregate:   AggregateSum,
+		Window:      24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Row{
+		{Tags: map[string]string{"chain_id": "1"}, Value: 8, Time: day},
+		{Tags: map[string]string{"chain_id": "2"}, Value: 3, Time: day},
+		{Tags: map[string]string{"chain_id": "2"}, Value: 4, Time: day.Add(24 * time.Hour)},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %+v", len(expected), rows)
+	}
+	for i := range expected {
+		if rows[i].Tags["chain_id"] != expected[i].Tags["chain_id"] || rows[i].Value != expected[i].Value || !rows[i].Time.Equal(expected[i].Time) {
+			t.Errorf("unexpected row %d: %+v", i, rows[i])
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// Package timeseries defines a storage abstraction for the analytics time-series data
+// (vaa_count, vaa_volume_v2, vaa_volume_v3, vaa_count_all_messages, ...).
+//
+// The analytics service writes points through a [Writer] and the API reads aggregated
+// values through a [Reader], so neither of them is tied to a specific database.
+// The available backends are InfluxDB v2, ClickHouse and an in-memory store for tests.
+package timeseries
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"time"
+)
+
+// Backend names used in the configuration of the services.
+const (
+	BackendInflux     = "influx"
+	BackendClickHouse = "clickhouse"
+	BackendMemory     = "memory"
+)
+
+// Bucket identifies the retention policy of the points.
+//
+// Each backend maps a bucket to its own storage unit (e.g.: an InfluxDB bucket).
+type Bucket string
+
+const (
+	BucketInfinite Bucket = "infinite"
+	Bucket30Days   Bucket = "30days"
+	Bucket24Hours  Bucket = "24hours"
+)
+
+// Tag is a key/value pair used to index a point.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Field is a key/value pair with the measured values of a point.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Point is a backend-agnostic time-series data point.
+type Point struct {
+	measurement string
+	tags        map[string]string
+	fields      map[string]interface{}
+	time        time.Time
+}
+
+// NewPoint creates a new point for the given measurement.
+func NewPoint(measurement string) *Point {
+	return &Point{
+		measurement: measurement,
+		tags:        make(map[string]string),
+		fields:      make(map[string]interface{}),
+	}
+}
+
+// AddTag adds a tag to the point, overwriting any previous value for the same key.
+func (p *Point) AddTag(key, value string) *Point {
+	p.tags[key] = value
+	return p
+}
+
+// AddField adds a field to the point, overwriting any previous value for the same key.
+func (p *Point) AddField(key string, value interface{}) *Point {
+	p.fields[key] = value
+	return p
+}
+
+// SetTime sets the timestamp of the point.
+func (p *Point) SetTime(t time.Time) *Point {
+	p.time = t
+	return p
+}
+
+// Name returns the measurement of the point.
+func (p *Point) Name() string {
+	return p.measurement
+}
+
+// Time returns the timestamp of the point.
+func (p *Point) Time() time.Time {
+	return p.time
+}
+
+// Tag returns the value of a tag.
+func (p *Point) Tag(key string) (string, bool) {
+	v, ok := p.tags[key]
+	return v, ok
+}
+
+// Field returns the value of a field.
+func (p *Point) Field(key string) (interface{}, bool) {
+	v, ok := p.fields[key]
+	return v, ok
+}
+
+// TagList returns the tags of the point sorted by key.
+func (p *Point) TagList() []Tag {
+	tags := make([]Tag, 0, len(p.tags))
+	for k, v := range p.tags {
+		tags = append(tags, Tag{Key: k, Value: v})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Key < tags[j].Key })
+	return tags
+}
+
+// FieldList returns the fields of the point sorted by key.
+func (p *Point) FieldList() []Field {
+	fields := make([]Field, 0, len(p.fields))
+	for k, v := range p.fields {
+		fields = append(fields, Field{Key: k, Value: v})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields
+}
+
+// Writer writes points into a time-series backend.
+type Writer interface {
+	// WritePoints writes the points into the given bucket.
+	WritePoints(ctx context.Context, bucket Bucket, points ...*Point) error
+	// Flush writes any buffered point.
+	Flush(ctx context.Context)
+	// Close releases the resources used by the writer.
+	Close()
+}
+
+// Aggregate is the function used to reduce the values of a group of points.
+type Aggregate string
+
+const (
+	AggregateSum   Aggregate = "sum"
+	AggregateCount Aggregate = "count"
+	// AggregateQuantile returns the Query.Quantile quantile of the values of each group.
+	AggregateQuantile Aggregate = "quantile"
+	// AggregateLast returns the value of the most recent point of each group.
+	AggregateLast Aggregate = "last"
+)
+
+// Query is a backend-agnostic aggregation over the points of a measurement.
+type Query struct {
+	Bucket      Bucket
+	Measurement string
+	// Field is the field to aggregate. Points without this field are ignored.
+	Field string
+	// Start is the inclusive lower bound of the time range.
+	Start time.Time
+	// Stop is the exclusive upper bound of the time range. Zero means now.
+	Stop time.Time
+	// Include keeps the points whose tag value is one of the listed values.
+	Include map[string][]string
+	// Exclude drops the points whose tag value is one of the listed values.
+	Exclude map[string][]string
+	// GroupBy is the list of tags used to group the points.
+	GroupBy   []string
+	Aggregate Aggregate
+	// Quantile is the quantile computed by AggregateQuantile, between 0 and 1.
+	Quantile float64
+	// Limit keeps the first N groups sorted by value in descending order. Zero means no limit.
+	Limit int
+	// Window splits the time range into intervals of this duration, aligned to the Unix epoch,
+	// and aggregates each interval separately. Zero means a single interval.
+	Window time.Duration
+}
+
+// Validate checks that the query can be executed by a backend.
+func (q *Query) Validate() error {
+	if q.Measurement == "" {
+		return fmt.Errorf("measurement is required")
+	}
+	if q.Field == "" {
+		return fmt.Errorf("field is required")
+	}
+	switch q.Aggregate {
+	case AggregateSum, AggregateCount, AggregateLast:
+	case AggregateQuantile:
+		if q.Quantile < 0 || q.Quantile > 1 {
+			return fmt.Errorf("invalid quantile: %v", q.Quantile)
+		}
+	default:
+		return fmt.Errorf("invalid aggregate: %s", q.Aggregate)
+	}
+	if q.Limit < 0 {
+		return fmt.Errorf("invalid limit: %d", q.Limit)
+	}
+	if q.Window < 0 || q.Window%time.Second != 0 {
+		return fmt.Errorf("invalid window: %s", q.Window)
+	}
+	if q.Window > 0 && q.Limit > 0 {
+		return fmt.Errorf("limit is not supported with window")
+	}
+	return nil
+}
+
+// stop returns the upper bound of the time range.
+func (q *Query) stop() time.Time {
+	if q.Stop.IsZero() {
+		return time.Now()
+	}
+	return q.Stop
+}
+
+// UniqueTime adds an offset below one millisecond, derived from a hash of the id, to the time of a point.
+//
+// Two points of a series with the same time overwrite each other in InfluxDB, and the time of most
+// events only has second or millisecond resolution, so the offset keeps them apart deterministically.
+// The whole id is hashed because the ids of different emitters often share the same sequence.
+func UniqueTime(t time.Time, id string) time.Time {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return t.Add(time.Duration(h.Sum64()%1_000_000) * time.Nanosecond)
+}
+
+// Row is a group returned by a query.
+type Row struct {
+	// Tags contains the value of each tag in Query.GroupBy.
+	Tags map[string]string
+	// Value is the aggregated value.
+	Value float64
+	// Time is the start of the interval when Query.Window is set.
+	Time time.Time
+}
+
+// Reader runs aggregation queries against a time-series backend.
+type Reader interface {
+	Query(ctx context.Context, q *Query) ([]Row, error)
+}
+
+// Store is a backend that supports both reads and writes.
+type Store interface {
+	Writer
+	Reader
+}
+
+// sortRows sorts the rows by time, oldest first, and by value in descending order and applies the limit.
+func sortRows(rows []Row, limit int) []Row {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].Time.Equal(rows[j].Time) {
+			return rows[i].Time.Before(rows[j].Time)
+		}
+		return rows[i].Value > rows[j].Value
+	})
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// quantile returns the q quantile of the values using the nearest-rank method. The values are sorted in place.
+func quantile(values []float64, q float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	rank := int(math.Ceil(q*float64(len(values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return values[rank]
+}
+
+// toFloat64 converts a numeric field value into a float64.
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// sortedKeys returns the keys of a tag filter in a deterministic order.
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
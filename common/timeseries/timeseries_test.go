@@ -0,0 +1,29 @@
+package timeseries
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUniqueTime(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := "2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1000001"
+
+	got := UniqueTime(ts, id)
+	if got != UniqueTime(ts, id) {
+		t.Errorf("expected the same time for the same id")
+	}
+	if got.Before(ts) || got.Sub(ts) >= time.Millisecond {
+		t.Errorf("expected an offset below one millisecond, got %s", got.Sub(ts))
+	}
+
+	// the same sequence of different emitters, or sequences one million apart, get different offsets.
+	for _, other := range []string{
+		"30/0000000000000000000000008d2de8d2f73f1f4cab472ac9a881c9b123c79627/1000001",
+		"2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1",
+	} {
+		if UniqueTime(ts, other) == got {
+			t.Errorf("expected a different time for %s", other)
+		}
+	}
+}
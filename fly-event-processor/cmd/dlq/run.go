@@ -0,0 +1,80 @@
+package dlq
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	common_dlq "github.com/wormhole-foundation/wormhole-explorer/common/dlq"
+	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/queue"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Settings represents the options of the dead-letter queue commands.
+type Settings struct {
+	LogLevel          string
+	AwsRegion         string
+	AwsEndpoint       string
+	DlqUrl            string
+	QueueUrl          string
+	MaxMessages       int
+	VisibilityTimeout int32
+	ChainID           uint16
+	Error             string
+	IDs               []string
+	DryRun            bool
+}
+
+// Run applies an action to the messages of a dead-letter queue and writes the report to the standard output.
+func Run(action common_dlq.Action, cfg *Settings) {
+
+	ctx := context.Background()
+
+	logger := logger.New("wormholescan-fly-event-processor", logger.WithLevel(cfg.LogLevel))
+
+	if action == common_dlq.ActionReplay && cfg.QueueUrl == "" {
+		logger.Fatal("queue url is required to replay messages")
+	}
+
+	awsConfig, err := newAwsConfig(ctx, cfg)
+	if err != nil {
+		logger.Fatal("failed to create aws config", zap.Error(err))
+	}
+	client := common_dlq.NewClient(awsConfig, cfg.DlqUrl, cfg.QueueUrl, cfg.VisibilityTimeout)
+
+	opts := common_dlq.Options{
+		Action:      action,
+		MaxMessages: cfg.MaxMessages,
+		Filter:      common_dlq.Filter{Error: cfg.Error, IDs: cfg.IDs},
+		DryRun:      cfg.DryRun,
+	}
+	if cfg.ChainID != 0 {
+		chainID := sdk.ChainID(cfg.ChainID)
+		opts.Filter.ChainID = &chainID
+	}
+
+	err = common_dlq.Run(ctx, client, queue.DecodeDlqEvent, opts, os.Stdout)
+	if err != nil {
+		logger.Fatal("failed to run dlq command", zap.String("action", string(action)), zap.Error(err))
+	}
+}
+
+func newAwsConfig(ctx context.Context, cfg *Settings) (aws.Config, error) {
+	if cfg.AwsEndpoint == "" {
+		return awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AwsRegion))
+	}
+	customResolver := aws.EndpointResolverFunc(func(service, region string) (aws.Endpoint, error) {
+		return aws.Endpoint{
+			PartitionID:   "aws",
+			URL:           cfg.AwsEndpoint,
+			SigningRegion: region,
+		}, nil
+	})
+	return awsconfig.LoadDefaultConfig(ctx,
+		awsconfig.WithRegion(cfg.AwsRegion),
+		awsconfig.WithEndpointResolver(customResolver),
+	)
+}
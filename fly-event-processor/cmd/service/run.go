@@ -15,9 +15,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbutil"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/guardianset"
 	"github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
 	"github.com/wormhole-foundation/wormhole-explorer/common/pool"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 
 	governorConsumer "github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/consumer/governor"
 	vaaConsumer "github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/consumer/vaa"
@@ -53,6 +56,23 @@ func Run() {
 	logger := logger.New("wormholescan-fly-event-processor", logger.WithLevel(cfg.LogLevel))
 	logger.Info("Starting wormholescan-fly-event-processor ...")
 
+	// set up tracing
+	shutdownTracing, err := telemetry.Setup(rootCtx, telemetry.Config{
+		ServiceName:  "wormholescan-fly-event-processor",
+		Environment:  cfg.Environment,
+		OtlpEndpoint: cfg.TracingOtlpEndpoint,
+		OtlpInsecure: cfg.TracingOtlpInsecure,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+
+	// apply the chain registry overrides
+	if err := domain.Chains.LoadOverrides(cfg.ChainsOverridesFile); err != nil {
+		logger.Fatal("failed to load chain overrides", zap.Error(err))
+	}
+
 	// create guardian provider pool
 	guardianApiProviderPool, err := newGuardianProviderPool(cfg)
 	if err != nil {
@@ -74,8 +94,20 @@ func Run() {
 		logger.Fatal("failed to initialize VAA parser", zap.Error(err))
 	}
 
+	//TxTracker sourceTx client
+	sourceTxFunc, err := newSourceTxFunc(cfg, logger)
+	if err != nil {
+		logger.Fatal("failed to initialize TxTracker client", zap.Error(err))
+	}
+
+	// load the guardian sets verified from the guardian set upgrade vaas
+	guardianSets, err := guardianset.NewProvider(rootCtx, cfg.P2pNetwork, cfg.GuardianSetBundleFile, db.Database, logger)
+	if err != nil {
+		logger.Fatal("failed to load guardian sets", zap.Error(err))
+	}
+
 	// create a new processor
-	dupVaaProcessor := vaaprocessor.NewProcessor(guardianApiProviderPool, repository, logger, metrics)
+	dupVaaProcessor := vaaprocessor.NewProcessor(guardianApiProviderPool, guardianSets, repository, sourceTxFunc, logger, metrics)
 	governorProcessor := governorProcessor.NewProcessor(repository, createTxHashFunc, logger, metrics)
 
 	// start serving /health and /ready endpoints
@@ -119,6 +151,13 @@ func Run() {
 	logger.Info("Closing MongoDB connection...")
 	db.DisconnectWithTimeout(10 * time.Second)
 
+	logger.Info("flushing traces...")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", zap.Error(err))
+	}
+	shutdownCancel()
+
 	logger.Info("Terminated wormholescan-fly-event-processor")
 
 }
@@ -268,3 +307,21 @@ func newCreateTxHashFunc(
 	}
 	return createTxHashClient.CreateTxHash, nil
 }
+
+func newSourceTxFunc(
+	cfg *config.ServiceConfiguration,
+	logger *zap.Logger,
+) (txTracker.SourceTxFunc, error) {
+	if cfg.Environment == config.EnvironmentLocal {
+		return func(vaaID, txHash string, timestamp *time.Time) (*txTracker.TxHashResponse, error) {
+			return &txTracker.TxHashResponse{
+				NativeTxHash: txHash,
+			}, nil
+		}, nil
+	}
+	sourceTxClient, err := txTracker.NewTxTrackerAPIClient(cfg.TxTrackerTimeout, cfg.TxTrackerUrl, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize TxTracker client: %w", err)
+	}
+	return sourceTxClient.SourceTx, nil
+}
@@ -24,14 +24,18 @@ const (
 // ServiceConfiguration represents the application configuration when running as service with default values.
 type ServiceConfiguration struct {
 	// Global configuration
-	Environment    string `env:"ENVIRONMENT,required"`
-	LogLevel       string `env:"LOG_LEVEL,default=INFO"`
-	Port           string `env:"PORT,default=8000"`
-	PprofEnabled   bool   `env:"PPROF_ENABLED,default=false"`
-	P2pNetwork     string `env:"P2P_NETWORK,required"`
-	AlertEnabled   bool   `env:"ALERT_ENABLED,default=false"`
-	AlertApiKey    string `env:"ALERT_API_KEY"`
-	MetricsEnabled bool   `env:"METRICS_ENABLED,default=false"`
+	Environment         string  `env:"ENVIRONMENT,required"`
+	LogLevel            string  `env:"LOG_LEVEL,default=INFO"`
+	TracingOtlpEndpoint string  `env:"TRACING_OTLP_ENDPOINT"`
+	TracingOtlpInsecure bool    `env:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio  float64 `env:"TRACING_SAMPLE_RATIO,default=1"`
+	Port                string  `env:"PORT,default=8000"`
+	PprofEnabled        bool    `env:"PPROF_ENABLED,default=false"`
+	P2pNetwork          string  `env:"P2P_NETWORK,required"`
+	ChainsOverridesFile string  `env:"CHAINS_OVERRIDES_FILE"`
+	AlertEnabled        bool    `env:"ALERT_ENABLED,default=false"`
+	AlertApiKey         string  `env:"ALERT_API_KEY"`
+	MetricsEnabled      bool    `env:"METRICS_ENABLED,default=false"`
 	// Fly event consumer configuration
 	ConsumerWorkerSize         int `env:"CONSUMER_WORKER_SIZE,default=1"`
 	GovernorConsumerWorkerSize int `env:"GOVERNOR_CONSUMER_WORKER_SIZE,default=1"`
@@ -46,6 +50,8 @@ type ServiceConfiguration struct {
 	AwsRegion          string `env:"AWS_REGION"`
 	DuplicateVaaSQSUrl string `env:"DUPLICATE_VAA_SQS_URL"`
 	GovernorSQSUrl     string `env:"GOVERNOR_SQS_URL"`
+	// Guardian set configuration, the bundle of guardian set upgrade VAAs (see common/guardianset)
+	GuardianSetBundleFile string `env:"GUARDIAN_SET_BUNDLE_FILE"`
 	// Tx-tracker client configuration
 	TxTrackerUrl     string `env:"TX_TRACKER_URL,required"`
 	TxTrackerTimeout int64  `env:"TX_TRACKER_TIMEOUT,default=10"`
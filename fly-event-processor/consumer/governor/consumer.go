@@ -2,6 +2,7 @@ package governor
 
 import (
 	"context"
+	"errors"
 
 	"github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/domain"
 	"github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/internal/metrics"
@@ -10,6 +11,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// errEventExpired is the error of the events whose visibility timeout expired before being processed.
+var errEventExpired = errors.New("event expired")
+
 // Consumer consumer struct definition.
 type Consumer struct {
 	consumeFunc queue.ConsumeFunc[queue.EventGovernorStatus]
@@ -56,7 +60,7 @@ func (c *Consumer) producerLoop(ctx context.Context, ch <-chan queue.ConsumerMes
 		case <-ctx.Done():
 			return
 		case msg := <-ch:
-			c.processEvent(ctx, msg)
+			c.processEvent(msg.Context(), msg)
 		}
 	}
 }
@@ -78,7 +82,7 @@ func (c *Consumer) processEvent(ctx context.Context, msg queue.ConsumerMessage[q
 		zap.String("node", event.Data.NodeName))
 
 	if msg.IsExpired() {
-		msg.Failed()
+		msg.Failed(errEventExpired)
 		logger.Debug("event is expired")
 		c.metrics.IncGovernorStatusExpired(event.Data.NodeName, event.Data.NodeAddress)
 		return
@@ -91,7 +95,7 @@ func (c *Consumer) processEvent(ctx context.Context, msg queue.ConsumerMessage[q
 
 	err := c.processor(ctx, params)
 	if err != nil {
-		msg.Failed()
+		msg.Failed(err)
 		logger.Error("failed to process governor-status event", zap.Error(err))
 		c.metrics.IncGovernorStatusFailed(params.NodeGovernorVaa.Name, params.NodeGovernorVaa.Address)
 		return
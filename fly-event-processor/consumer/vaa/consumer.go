@@ -2,6 +2,7 @@ package vaa
 
 import (
 	"context"
+	"errors"
 
 	"github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/internal/metrics"
 	processor "github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/processor/vaa"
@@ -10,6 +11,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// errEventExpired is the error of the events whose visibility timeout expired before being processed.
+var errEventExpired = errors.New("event expired")
+
 // Consumer consumer struct definition.
 type Consumer struct {
 	consumeFunc queue.ConsumeFunc[queue.EventDuplicateVaa]
@@ -56,7 +60,7 @@ func (c *Consumer) producerLoop(ctx context.Context, ch <-chan queue.ConsumerMes
 		case <-ctx.Done():
 			return
 		case msg := <-ch:
-			c.processEvent(ctx, msg)
+			c.processEvent(msg.Context(), msg)
 		}
 	}
 }
@@ -81,7 +85,7 @@ func (c *Consumer) processEvent(ctx context.Context, msg queue.ConsumerMessage[q
 		zap.String("vaaId", vaaID))
 
 	if msg.IsExpired() {
-		msg.Failed()
+		msg.Failed(errEventExpired)
 		logger.Debug("event is expired")
 		c.metrics.IncDuplicatedVaaExpired(chainID)
 		return
@@ -95,7 +99,7 @@ func (c *Consumer) processEvent(ctx context.Context, msg queue.ConsumerMessage[q
 
 	err := c.processor(ctx, params)
 	if err != nil {
-		msg.Failed()
+		msg.Failed(err)
 		logger.Error("error processing event", zap.Error(err))
 		c.metrics.IncDuplicatedVaaFailed(chainID)
 		return
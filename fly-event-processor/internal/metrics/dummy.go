@@ -25,6 +25,9 @@ func (d *DummyMetrics) IncDuplicatedVaaExpired(chainID sdk.ChainID) {}
 // IncDuplicatedVaaCanNotFixed dummy implementation.
 func (d *DummyMetrics) IncDuplicatedVaaCanNotFixed(chainID sdk.ChainID) {}
 
+// IncDuplicatedVaaResolved dummy implementation.
+func (d *DummyMetrics) IncDuplicatedVaaResolved(chainID sdk.ChainID, method string) {}
+
 // IncGovernorStatusConsumedQueue dummy implementation.
 func (d *DummyMetrics) IncGovernorStatusConsumedQueue() {}
 
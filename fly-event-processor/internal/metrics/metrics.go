@@ -10,6 +10,7 @@ type Metrics interface {
 	IncDuplicatedVaaFailed(chainID sdk.ChainID)
 	IncDuplicatedVaaExpired(chainID sdk.ChainID)
 	IncDuplicatedVaaCanNotFixed(chainID sdk.ChainID)
+	IncDuplicatedVaaResolved(chainID sdk.ChainID, method string)
 	IncGovernorStatusConsumedQueue()
 	IncGovernorStatusProcessed(node string, address string)
 	IncGovernorStatusFailed(node string, address string)
@@ -75,6 +75,12 @@ func (m *PrometheusMetrics) IncDuplicatedVaaCanNotFixed(chainID sdk.ChainID) {
 	m.duplicatedVaaCount.WithLabelValues(chain, "can_not_fixed").Inc()
 }
 
+// IncDuplicatedVaaResolved increments the total number of duplicated VAA resolved by the given method.
+func (m *PrometheusMetrics) IncDuplicatedVaaResolved(chainID sdk.ChainID, method string) {
+	chain := chainID.String()
+	m.duplicatedVaaCount.WithLabelValues(chain, "resolved_by_"+method).Inc()
+}
+
 // IncGovernorStatusConsumedQueue increments the total number of governor status consumed queue.
 func (m *PrometheusMetrics) IncGovernorStatusConsumedQueue() {
 	m.governorStatusCount.WithLabelValues("all", "", "consumed_queue").Inc()
@@ -0,0 +1,259 @@
+package vaa
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/guardian"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/txtracker"
+	"github.com/wormhole-foundation/wormhole-explorer/common/guardianset"
+	"github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/storage"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+// Methods used to resolve a duplicate vaa, ordered by preference.
+const (
+	MethodObservationQuorum = "observation_quorum"
+	MethodSourceTx          = "source_tx"
+	MethodGuardianAPI       = "guardian_api"
+)
+
+// guardianSetProvider returns the guardian set of an index.
+type guardianSetProvider interface {
+	Get(ctx context.Context, index uint32) (*common.GuardianSet, error)
+}
+
+// signedVaaFunc returns the signed vaa of a vaa id.
+type signedVaaFunc func(ctx context.Context, vaaID string, logger *zap.Logger) *guardian.SignedVaa
+
+// candidate is one of the vaas stored for a vaa id.
+type candidate struct {
+	digest    string
+	txHash    string
+	timestamp time.Time
+	// duplicate is the duplicate vaa document, nil for the vaa stored in the vaas collection.
+	duplicate *storage.DuplicateVaaDoc
+}
+
+// newCandidates returns the vaa stored in the vaas collection followed by its duplicates.
+func newCandidates(vaaDoc *storage.VaaDoc, duplicateVaaDocs []storage.DuplicateVaaDoc) ([]candidate, error) {
+	vaa, err := sdk.Unmarshal(vaaDoc.Vaa)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []candidate{{digest: vaa.HexDigest(), txHash: vaaDoc.TxHash, timestamp: vaa.Timestamp}}
+	for i := range duplicateVaaDocs {
+		duplicateVaa, err := sdk.Unmarshal(duplicateVaaDocs[i].Vaa)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate{
+			digest:    duplicateVaa.HexDigest(),
+			txHash:    duplicateVaaDocs[i].TxHash,
+			timestamp: duplicateVaa.Timestamp,
+			duplicate: &duplicateVaaDocs[i],
+		})
+	}
+	return candidates, nil
+}
+
+// resolve picks the correct vaa among the candidates. It returns nil when there is no conclusive evidence.
+// The evidence gathered is recorded in the resolution.
+func (p *Processor) resolve(
+	ctx context.Context,
+	vaaID string,
+	guardianSetIndex uint32,
+	candidates []candidate,
+	observations []storage.ObservationDoc,
+	resolution *storage.DuplicateVaaResolution,
+	logger *zap.Logger,
+) *candidate {
+
+	// 1. the digest signed by a quorum of the guardian set.
+	if c := p.resolveByObservationQuorum(ctx, guardianSetIndex, candidates, observations, resolution, logger); c != nil {
+		resolution.Method = MethodObservationQuorum
+		return c
+	}
+
+	// 2. the only digest whose transaction exists in the source chain.
+	if c := p.resolveBySourceTx(vaaID, candidates, observations, resolution, logger); c != nil {
+		resolution.Method = MethodSourceTx
+		return c
+	}
+
+	// 3. the digest of the vaa signed by the guardian api.
+	if c := p.resolveByGuardianAPI(ctx, vaaID, candidates, logger); c != nil {
+		resolution.Method = MethodGuardianAPI
+		return c
+	}
+	return nil
+}
+
+// resolveByObservationQuorum returns the candidate whose digest has been signed by a quorum of the guardian set.
+func (p *Processor) resolveByObservationQuorum(
+	ctx context.Context,
+	guardianSetIndex uint32,
+	candidates []candidate,
+	observations []storage.ObservationDoc,
+	resolution *storage.DuplicateVaaResolution,
+	logger *zap.Logger,
+) *candidate {
+
+	guardianSet, err := p.guardianSets.Get(ctx, guardianSetIndex)
+	if errors.Is(err, guardianset.ErrGuardianSetNotFound) {
+		logger.Warn("guardian set not found", zap.Uint32("guardianSetIndex", guardianSetIndex))
+		return nil
+	}
+	if err != nil {
+		logger.Error("error getting guardian set", zap.Error(err), zap.Uint32("guardianSetIndex", guardianSetIndex))
+		return nil
+	}
+
+	guardians := make(map[string]bool, len(guardianSet.Keys))
+	for _, key := range guardianSet.Keys {
+		guardians[strings.ToLower(key.Hex())] = true
+	}
+
+	// count the distinct guardians of the set that signed each digest.
+	signers := make(map[string]map[string]bool)
+	for _, o := range observations {
+		addr := strings.ToLower(o.GuardianAddr)
+		if !guardians[addr] {
+			continue
+		}
+		digest := hex.EncodeToString(o.Hash)
+		if signers[digest] == nil {
+			signers[digest] = make(map[string]bool)
+		}
+		signers[digest][addr] = true
+	}
+
+	resolution.Quorum = sdk.CalculateQuorum(len(guardianSet.Keys))
+	resolution.Signatures = make(map[string]int, len(candidates))
+	var winner *candidate
+	for i := range candidates {
+		count := len(signers[candidates[i].digest])
+		resolution.Signatures[candidates[i].digest] = count
+		if count < resolution.Quorum {
+			continue
+		}
+		// a quorum for more than one digest is not a conclusive evidence.
+		if winner != nil && winner.digest != candidates[i].digest {
+			logger.Warn("more than one digest reached the quorum")
+			return nil
+		}
+		winner = &candidates[i]
+	}
+	return winner
+}
+
+// resolveBySourceTx returns the only candidate whose transaction exists in the source chain.
+// The transaction of each candidate is fetched from the chain by its own tx hash, so the
+// transaction stored for the vaa is not taken as the evidence of its own candidate.
+func (p *Processor) resolveBySourceTx(
+	vaaID string,
+	candidates []candidate,
+	observations []storage.ObservationDoc,
+	resolution *storage.DuplicateVaaResolution,
+	logger *zap.Logger,
+) *candidate {
+
+	// observations carry the native tx hash of the digest they signed.
+	nativeTxHashes := make(map[string]string)
+	for _, o := range observations {
+		if o.NativeTxHash != "" {
+			nativeTxHashes[hex.EncodeToString(o.Hash)] = o.NativeTxHash
+		}
+	}
+
+	var winner *candidate
+	var winnerTxHash string
+	for i := range candidates {
+		txHash := candidates[i].txHash
+		if txHash == "" {
+			txHash = nativeTxHashes[candidates[i].digest]
+		}
+		if txHash == "" {
+			continue
+		}
+
+		sourceTx, err := p.sourceTxFunc(vaaID, txHash, &candidates[i].timestamp)
+		if errors.Is(err, txtracker.ErrNotFound) {
+			logger.Info("source transaction of candidate not found", zap.String("digest", candidates[i].digest), zap.String("txHash", txHash))
+			continue
+		}
+		// without the answer of the chain for every candidate there is no conclusive evidence.
+		if err != nil {
+			logger.Error("error getting source transaction from tx-tracker", zap.Error(err), zap.String("txHash", txHash))
+			return nil
+		}
+
+		// candidates with different digests backed by the chain are not a conclusive evidence.
+		if winner != nil && winner.digest != candidates[i].digest {
+			logger.Warn("more than one digest backed by a source transaction")
+			return nil
+		}
+		winner = &candidates[i]
+		winnerTxHash = sourceTx.NativeTxHash
+	}
+	if winner != nil {
+		resolution.SourceTxHash = winnerTxHash
+	}
+	return winner
+}
+
+// resolveByGuardianAPI returns the candidate whose digest matches the signed vaa returned by the guardian api.
+func (p *Processor) resolveByGuardianAPI(
+	ctx context.Context,
+	vaaID string,
+	candidates []candidate,
+	logger *zap.Logger,
+) *candidate {
+
+	signedVaa := p.signedVaa(ctx, vaaID, logger)
+	if signedVaa == nil {
+		logger.Error("error getting signed vaa from guardian api")
+		return nil
+	}
+
+	guardianVAA, err := sdk.Unmarshal(signedVaa.VaaBytes)
+	if err != nil {
+		logger.Error("error unmarshalling guardian signed vaa", zap.Error(err))
+		return nil
+	}
+
+	for i := range candidates {
+		if candidates[i].digest == guardianVAA.HexDigest() {
+			return &candidates[i]
+		}
+	}
+	return nil
+}
+
+// getSignedVaaFromGuardians returns the signed vaa from the first guardian of the pool that answers.
+func (p *Processor) getSignedVaaFromGuardians(ctx context.Context, vaaID string, logger *zap.Logger) *guardian.SignedVaa {
+	guardians := p.guardianPool.GetItems()
+	for _, g := range guardians {
+		g.Wait(ctx)
+		guardianAPIClient, err := guardian.NewGuardianAPIClient(
+			guardian.DefaultTimeout,
+			g.Id,
+			logger)
+		if err != nil {
+			logger.Error("error creating guardian api client", zap.Error(err))
+			continue
+		}
+		signedVaa, err := guardianAPIClient.GetSignedVAA(vaaID)
+		if err != nil {
+			logger.Error("error getting signed vaa from guardian api", zap.Error(err))
+			continue
+		}
+		return signedVaa
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package vaa
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/guardian"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/txtracker"
+	"github.com/wormhole-foundation/wormhole-explorer/common/guardianset"
+	"github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/storage"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+const testVaaID = "2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1"
+
+type testGuardianSets map[uint32]*common.GuardianSet
+
+func (g testGuardianSets) Get(_ context.Context, index uint32) (*common.GuardianSet, error) {
+	gs, ok := g[index]
+	if !ok {
+		return nil, guardianset.ErrGuardianSetNotFound
+	}
+	return gs, nil
+}
+
+// newTestGuardianSet returns a guardian set of size guardians with the addresses 0x01, 0x02, ...
+func newTestGuardianSet(size int) testGuardianSets {
+	gs := &common.GuardianSet{}
+	for i := 0; i < size; i++ {
+		gs.Keys = append(gs.Keys, [20]byte{19: byte(i + 1)})
+	}
+	return testGuardianSets{0: gs}
+}
+
+func guardianAddr(i int) string {
+	return fmt.Sprintf("0x%040x", i)
+}
+
+// newTestVaa returns a vaa for testVaaID with the given payload and its candidate.
+func newTestVaa(t *testing.T, payload string, txHash string) ([]byte, candidate) {
+	t.Helper()
+	v := &sdk.VAA{
+		Version:          1,
+		Timestamp:        time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		EmitterChain:     sdk.ChainIDEthereum,
+		EmitterAddress:   sdk.Address{31: 1},
+		Sequence:         1,
+		ConsistencyLevel: 1,
+		Payload:          []byte(payload),
+	}
+	data, err := v.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data, candidate{digest: v.HexDigest(), txHash: txHash, timestamp: v.Timestamp}
+}
+
+func observations(c candidate, guardians ...int) []storage.ObservationDoc {
+	hash, _ := hex.DecodeString(c.digest)
+	docs := make([]storage.ObservationDoc, 0, len(guardians))
+	for _, g := range guardians {
+		docs = append(docs, storage.ObservationDoc{Hash: hash, GuardianAddr: guardianAddr(g)})
+	}
+	return docs
+}
+
+// sourceTxs returns a source tx func that finds the given tx hashes, and records the requested ones.
+func sourceTxs(requested *[]string, found ...string) txtracker.SourceTxFunc {
+	return func(vaaID, txHash string, timestamp *time.Time) (*txtracker.TxHashResponse, error) {
+		*requested = append(*requested, txHash)
+		for _, f := range found {
+			if f == txHash {
+				return &txtracker.TxHashResponse{NativeTxHash: "0x" + txHash}, nil
+			}
+		}
+		return nil, txtracker.ErrNotFound
+	}
+}
+
+func signedVaa(data []byte) signedVaaFunc {
+	return func(context.Context, string, *zap.Logger) *guardian.SignedVaa {
+		if data == nil {
+			return nil
+		}
+		return &guardian.SignedVaa{VaaBytes: data}
+	}
+}
+
+func TestResolve_ObservationQuorum(t *testing.T) {
+	_, stored := newTestVaa(t, "stored", "aa")
+	_, duplicate := newTestVaa(t, "duplicate", "bb")
+	var requested []string
+	p := &Processor{
+		guardianSets: newTestGuardianSet(4),
+		sourceTxFunc: sourceTxs(&requested, "aa", "bb"),
+		signedVaa:    signedVaa(nil),
+	}
+
+	obs := append(observations(duplicate, 1, 2, 3), observations(stored, 4, 5)...)
+	var resolution storage.DuplicateVaaResolution
+	c := p.resolve(context.Background(), testVaaID, 0, []candidate{stored, duplicate}, obs, &resolution, zap.NewNop())
+
+	if c == nil || c.digest != duplicate.digest {
+		t.Fatalf("expected the duplicate to be resolved, got %v", c)
+	}
+	if resolution.Method != MethodObservationQuorum {
+		t.Errorf("expected method %s, got %s", MethodObservationQuorum, resolution.Method)
+	}
+	if resolution.Quorum != 3 {
+		t.Errorf("expected quorum 3, got %d", resolution.Quorum)
+	}
+	// the observation of guardian 5 is not from the guardian set.
+	if resolution.Signatures[duplicate.digest] != 3 || resolution.Signatures[stored.digest] != 1 {
+		t.Errorf("unexpected signatures %v", resolution.Signatures)
+	}
+	if len(requested) != 0 {
+		t.Errorf("expected no source tx requests, got %v", requested)
+	}
+}
+
+func TestResolve_SourceTx(t *testing.T) {
+	_, stored := newTestVaa(t, "stored", "aa")
+	_, duplicate := newTestVaa(t, "duplicate", "")
+	var requested []string
+	p := &Processor{
+		guardianSets: newTestGuardianSet(4),
+		sourceTxFunc: sourceTxs(&requested, "bb"),
+		signedVaa:    signedVaa(nil),
+	}
+
+	// no quorum, and the duplicate has no tx hash but its observations carry it.
+	obs := observations(duplicate, 1, 2)
+	for i := range obs {
+		obs[i].NativeTxHash = "bb"
+	}
+	var resolution storage.DuplicateVaaResolution
+	c := p.resolve(context.Background(), testVaaID, 0, []candidate{stored, duplicate}, obs, &resolution, zap.NewNop())
+
+	if c == nil || c.digest != duplicate.digest {
+		t.Fatalf("expected the duplicate to be resolved, got %v", c)
+	}
+	if resolution.Method != MethodSourceTx {
+		t.Errorf("expected method %s, got %s", MethodSourceTx, resolution.Method)
+	}
+	if resolution.SourceTxHash != "0xbb" {
+		t.Errorf("expected source tx hash 0xbb, got %s", resolution.SourceTxHash)
+	}
+	// each candidate is checked with its own tx hash.
+	if fmt.Sprint(requested) != "[aa bb]" {
+		t.Errorf("expected source tx requests [aa bb], got %v", requested)
+	}
+}
+
+func TestResolve_GuardianAPIFallback(t *testing.T) {
+	storedData, stored := newTestVaa(t, "stored", "aa")
+	_, duplicate := newTestVaa(t, "duplicate", "bb")
+
+	tcs := []struct {
+		name     string
+		sourceTx txtracker.SourceTxFunc
+	}{
+		{
+			name:     "both candidates backed by the chain",
+			sourceTx: sourceTxs(new([]string), "aa", "bb"),
+		},
+		{
+			name:     "no candidate backed by the chain",
+			sourceTx: sourceTxs(new([]string)),
+		},
+		{
+			name: "tx-tracker error",
+			sourceTx: func(vaaID, txHash string, timestamp *time.Time) (*txtracker.TxHashResponse, error) {
+				if txHash == "bb" {
+					return nil, txtracker.ErrInternalError
+				}
+				return &txtracker.TxHashResponse{NativeTxHash: "0x" + txHash}, nil
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Processor{
+				guardianSets: newTestGuardianSet(4),
+				sourceTxFunc: tc.sourceTx,
+				signedVaa:    signedVaa(storedData),
+			}
+
+			var resolution storage.DuplicateVaaResolution
+			c := p.resolve(context.Background(), testVaaID, 0, []candidate{stored, duplicate}, observations(stored, 1), &resolution, zap.NewNop())
+
+			if c == nil || c.digest != stored.digest {
+				t.Fatalf("expected the stored vaa to be resolved, got %v", c)
+			}
+			if resolution.Method != MethodGuardianAPI {
+				t.Errorf("expected method %s, got %s", MethodGuardianAPI, resolution.Method)
+			}
+			if resolution.SourceTxHash != "" {
+				t.Errorf("expected no source tx hash, got %s", resolution.SourceTxHash)
+			}
+		})
+	}
+}
+
+func TestResolve_NoConclusiveEvidence(t *testing.T) {
+	_, stored := newTestVaa(t, "stored", "aa")
+	_, duplicate := newTestVaa(t, "duplicate", "bb")
+	p := &Processor{
+		guardianSets: testGuardianSets{},
+		sourceTxFunc: func(vaaID, txHash string, timestamp *time.Time) (*txtracker.TxHashResponse, error) {
+			return nil, errors.New("unexpected")
+		},
+		signedVaa: signedVaa(nil),
+	}
+
+	var resolution storage.DuplicateVaaResolution
+	c := p.resolve(context.Background(), testVaaID, 0, []candidate{stored, duplicate}, nil, &resolution, zap.NewNop())
+
+	if c != nil {
+		t.Fatalf("expected no candidate, got %v", c)
+	}
+	if resolution.Method != "" {
+		t.Errorf("expected no method, got %s", resolution.Method)
+	}
+}
@@ -0,0 +1,37 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+
+	common_dlq "github.com/wormhole-foundation/wormhole-explorer/common/dlq"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// DecodeDlqEvent decodes a message of the dead-letter queue of a duplicate vaa or governor status queue.
+func DecodeDlqEvent(message string) (*common_dlq.Event, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(message), &header); err != nil {
+		return nil, err
+	}
+
+	switch header.Type {
+	case DeduplicateVaaEventType:
+		var event EventDuplicateVaa
+		if err := json.Unmarshal([]byte(message), &event); err != nil {
+			return nil, err
+		}
+		return &common_dlq.Event{ID: event.Data.VaaID, ChainID: sdk.ChainID(event.Data.ChainID)}, nil
+	case GovernorStatusEventType:
+		var event EventGovernorStatus
+		if err := json.Unmarshal([]byte(message), &event); err != nil {
+			return nil, err
+		}
+		// the governor status is not related to a chain.
+		return &common_dlq.Event{ID: event.Data.NodeAddress, ChainID: sdk.ChainIDUnset}, nil
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", header.Type)
+	}
+}
@@ -7,11 +7,17 @@ import (
 	"sync"
 	"time"
 
+	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	sqs_client "github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/internal/metrics"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+var tracer = telemetry.Tracer("github.com/wormhole-foundation/wormhole-explorer/fly-event-processor/queue")
+
 // SQSOption represents a VAA queue in SQS option function.
 type SQSOption[T Event] func(*SQS[T])
 
@@ -87,16 +93,22 @@ func (q *SQS[T]) Consume(ctx context.Context) <-chan ConsumerMessage[T] {
 				}
 
 				retry, _ := strconv.Atoi(msg.Attributes["ApproximateReceiveCount"])
+				// continue the trace of the producer of the message.
+				msgCtx, span := tracer.Start(sqsEvent.MessageAttributes.Extract(ctx), "fly-event-processor.event.consume",
+					trace.WithSpanKind(trace.SpanKindConsumer))
+
 				q.wg.Add(1)
 				q.ch <- &sqsConsumerMessage[T]{
 					id:        msg.ReceiptHandle,
+					msg:       msg,
 					data:      event,
 					wg:        &q.wg,
 					logger:    q.logger,
 					consumer:  q.consumer,
 					expiredAt: expiredAt,
 					retry:     uint8(retry),
-					ctx:       ctx,
+					ctx:       msgCtx,
+					span:      span,
 				}
 			}
 			q.wg.Wait()
@@ -116,10 +128,12 @@ type sqsConsumerMessage[T Event] struct {
 	consumer  *sqs_client.Consumer
 	wg        *sync.WaitGroup
 	id        *string
+	msg       aws_sqs_types.Message
 	logger    *zap.Logger
 	expiredAt time.Time
 	retry     uint8
 	ctx       context.Context
+	span      trace.Span
 }
 
 func (m *sqsConsumerMessage[T]) Done() {
@@ -130,6 +144,7 @@ func (m *sqsConsumerMessage[T]) Done() {
 			zap.Error(err),
 		)
 	}
+	m.span.End()
 	m.wg.Done()
 }
 
@@ -137,7 +152,21 @@ func (m *sqsConsumerMessage[T]) Data() T {
 	return m.data
 }
 
-func (m *sqsConsumerMessage[T]) Failed() {
+// Failed leaves the message in the queue to be received again, or moves it to the dead-letter queue with
+// the error in the LastError attribute when this is its last receive allowed by the redrive policy.
+func (m *sqsConsumerMessage[T]) Failed(err error) {
+	var lastErr string
+	if err != nil {
+		lastErr = err.Error()
+	}
+	moved, errMove := m.consumer.Failed(m.ctx, m.msg, lastErr)
+	if errMove != nil {
+		m.logger.Error("Error moving message to the dead-letter queue", zap.Error(errMove))
+	} else if moved {
+		m.logger.Warn("Message moved to the dead-letter queue", zap.Uint8("retry", m.retry), zap.Error(err))
+	}
+	m.span.SetStatus(codes.Error, "message processing failed")
+	m.span.End()
 	m.wg.Done()
 }
 
@@ -148,3 +177,7 @@ func (m *sqsConsumerMessage[T]) IsExpired() bool {
 func (m *sqsConsumerMessage[T]) Retry() uint8 {
 	return m.retry
 }
+
+func (m *sqsConsumerMessage[T]) Context() context.Context {
+	return m.ctx
+}
@@ -3,6 +3,8 @@ package queue
 import (
 	"context"
 	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 )
 
 const (
@@ -14,6 +16,8 @@ const (
 type sqsEvent struct {
 	MessageID string `json:"MessageId"`
 	Message   string `json:"Message"`
+	// MessageAttributes carries the trace context of the producer.
+	MessageAttributes telemetry.SNSMessageAttributes `json:"MessageAttributes"`
 }
 
 // Event represents a event data.
@@ -85,8 +89,11 @@ type ConsumerMessage[T any] interface {
 	Retry() uint8
 	Data() T
 	Done()
-	Failed()
+	// Failed is called when the processing of the message failed with err.
+	Failed(err error)
 	IsExpired() bool
+	// Context returns the context of the message, it carries the span of its processing.
+	Context() context.Context
 }
 
 // ConsumeFunc is a function to consume Event.
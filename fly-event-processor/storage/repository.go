@@ -16,6 +16,7 @@ type Repository struct {
 	duplicateVaas    *mongo.Collection
 	nodeGovernorVaas *mongo.Collection
 	governorVaas     *mongo.Collection
+	observations     *mongo.Collection
 }
 
 // New creates a new repository.
@@ -26,6 +27,7 @@ func NewRepository(logger *zap.Logger, db *mongo.Database) *Repository {
 		duplicateVaas:    db.Collection(commonRepo.DuplicateVaas),
 		nodeGovernorVaas: db.Collection(commonRepo.NodeGovernorVaas),
 		governorVaas:     db.Collection(commonRepo.GovernorVaas),
+		observations:     db.Collection(commonRepo.Observations),
 	}
 	return &r
 }
@@ -57,6 +59,27 @@ func (r *Repository) FindDuplicateVAAs(ctx context.Context, vaaID string) ([]Dup
 	return duplicateVaaDocs, nil
 }
 
+// FindObservationsByVaaID find the observations of a vaa by vaa id.
+func (r *Repository) FindObservationsByVaaID(ctx context.Context, vaaID string) ([]ObservationDoc, error) {
+	var observations []ObservationDoc
+	cursor, err := r.observations.Find(ctx, bson.M{"messageId": vaaID})
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(ctx, &observations); err != nil {
+		return nil, err
+	}
+	return observations, nil
+}
+
+// AddDuplicateVaaResolution appends a resolution to the audit trail of all the duplicate vaas of a vaa.
+func (r *Repository) AddDuplicateVaaResolution(ctx context.Context, vaaID string, resolution DuplicateVaaResolution) error {
+	_, err := r.duplicateVaas.UpdateMany(ctx,
+		bson.M{"vaaId": vaaID},
+		bson.M{"$push": bson.M{"resolutions": resolution}})
+	return err
+}
+
 // FixVAA fix a vaa by id.
 func (r *Repository) FixVAA(ctx context.Context, vaaID, duplicateID string) error {
 	// start mongo transaction
@@ -47,6 +47,33 @@ type DuplicateVaaDoc struct {
 	TxHash           string      `bson:"txHash,omitempty"`
 	Timestamp        *time.Time  `bson:"timestamp"`
 	UpdatedAt        *time.Time  `bson:"updatedAt"`
+	// Resolutions is the audit trail of the decisions taken to resolve the duplicate VAA.
+	Resolutions []DuplicateVaaResolution `bson:"resolutions,omitempty"`
+}
+
+// DuplicateVaaResolution represents a decision taken to resolve a duplicate VAA.
+type DuplicateVaaResolution struct {
+	TrackID string `bson:"trackId"`
+	// Method is the evidence used to take the decision, empty when there was no conclusive evidence.
+	Method string `bson:"method"`
+	// Digest is the digest of the VAA chosen as the correct one.
+	Digest string `bson:"digest,omitempty"`
+	// Signatures is the number of guardian set signatures observed for each digest.
+	Signatures   map[string]int `bson:"signatures,omitempty"`
+	Quorum       int            `bson:"quorum,omitempty"`
+	SourceTxHash string         `bson:"sourceTxHash,omitempty"`
+	// Fixed is true when the VAA stored in the vaas collection was replaced by a duplicate.
+	Fixed      bool      `bson:"fixed"`
+	ResolvedAt time.Time `bson:"resolvedAt"`
+}
+
+// ObservationDoc represents an observation document.
+type ObservationDoc struct {
+	ID           string `bson:"_id"`
+	MessageID    string `bson:"messageId"`
+	Hash         []byte `bson:"hash"`
+	NativeTxHash string `bson:"nativeTxHash"`
+	GuardianAddr string `bson:"guardianAddr"`
 }
 
 type NodeGovernorVaaDoc struct {
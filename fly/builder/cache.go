@@ -1,14 +1,16 @@
 package builder
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/eko/gocache/v3/cache"
-	"github.com/eko/gocache/v3/metrics"
+	cache_metrics "github.com/eko/gocache/v3/metrics"
 	"github.com/eko/gocache/v3/store"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/config"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/deduplicator"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/metrics"
 	"go.uber.org/zap"
 )
 
@@ -23,17 +25,34 @@ func NewCache[T any](name string, numKeys, maxCost int64) (cache.CacheInterface[
 	}
 	store := store.NewRistretto(c)
 	return cache.NewMetric[T](
-		metrics.NewPrometheus(name),
+		cache_metrics.NewPrometheus(name),
 		cache.New[T](store),
 	), nil
 }
 
-func NewDeduplicator(name string, cfg config.Cache, logger *zap.Logger) (*deduplicator.Deduplicator, error) {
+func NewDeduplicator(name string, cfg *config.Configuration, cacheCfg config.Cache, metrics metrics.Metrics, logger *zap.Logger) (*deduplicator.Deduplicator, error) {
 	// Creates a deduplicator to discard VAA messages that were processed previously
-	deduplicatorCache, err := NewCache[bool](name, cfg.NumKeys, cfg.MaxCostsInMB)
+	deduplicatorCache, err := NewCache[bool](name, cacheCfg.NumKeys, cacheCfg.MaxCostsInMB)
 	if err != nil {
 		return nil, err
 	}
-	expiration := time.Duration(cfg.ExpirationInSeconds) * time.Second
-	return deduplicator.New(deduplicatorCache, logger, deduplicator.WithExpiration(expiration)), nil
+	expiration := time.Duration(cacheCfg.ExpirationInSeconds) * time.Second
+	opts := []deduplicator.Option{deduplicator.WithExpiration(expiration)}
+
+	switch cacheCfg.Mode {
+	case "", config.DedupModeLocal:
+	case config.DedupModeRedis:
+		if cfg.Redis == nil {
+			return nil, fmt.Errorf("deduplicator %s: redis mode requires a redis configuration", name)
+		}
+		prefix := fmt.Sprintf("%s:dedup:%s", cfg.Redis.RedisPrefix, name)
+		processingTTL := time.Duration(cacheCfg.ProcessingTTLSeconds) * time.Second
+		claimer := deduplicator.NewRedisClaimer(NewRedisClient(cfg), prefix, processingTTL, expiration)
+		opts = append(opts, deduplicator.WithClaimer(claimer, func() { metrics.IncDedupClaimSuppressed(name) }))
+		logger.Info("using redis deduplicator", zap.String("name", name))
+	default:
+		return nil, fmt.Errorf("deduplicator %s: invalid mode %s", name, cacheCfg.Mode)
+	}
+
+	return deduplicator.New(deduplicatorCache, logger, opts...), nil
 }
@@ -2,6 +2,7 @@ package builder
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/certusone/wormhole/node/pkg/common"
@@ -21,6 +22,39 @@ const (
 )
 
 func NewGuardianSetSynchronizer(ctx context.Context, db *dbutil.Session, heartbeatChannel chan *gossipv1.Heartbeat, logger *zap.Logger, cfg *config.Configuration, alertClient alert.AlertClient) (*guardiansets.GuardianSetSynchronizer, error) {
+	switch cfg.GuardianSetSource {
+	case config.GuardianSetSourceBundle:
+		return newBundleGuardianSetSynchronizer(ctx, db, heartbeatChannel, logger, cfg, alertClient)
+	case config.GuardianSetSourceEthereum:
+		return newEthGuardianSetSynchronizer(ctx, db, heartbeatChannel, logger, cfg, alertClient)
+	default:
+		return nil, fmt.Errorf("unknown guardian set source %s", cfg.GuardianSetSource)
+	}
+}
+
+func newBundleGuardianSetSynchronizer(ctx context.Context, db *dbutil.Session, heartbeatChannel chan *gossipv1.Heartbeat, logger *zap.Logger, cfg *config.Configuration, alertClient alert.AlertClient) (*guardiansets.GuardianSetSynchronizer, error) {
+	guardianSetRepository := repository.NewGuardianSetRepository(db.Database, logger)
+
+	bundleGuardianSet, err := guardiansets.NewBundleGuardianSet(ctx, cfg.P2pNetwork, cfg.GuardianSetBundleFile, db.Database, guardianSetRepository, alertClient, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bundleGuardianSet.Sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gst := common.NewGuardianSetState(heartbeatChannel)
+
+	return guardiansets.NewGuardianSetSynchronizer(ctx, gst, bundleGuardianSet, logger)
+}
+
+func newEthGuardianSetSynchronizer(ctx context.Context, db *dbutil.Session, heartbeatChannel chan *gossipv1.Heartbeat, logger *zap.Logger, cfg *config.Configuration, alertClient alert.AlertClient) (*guardiansets.GuardianSetSynchronizer, error) {
+	if cfg.EthereumUrl == "" {
+		return nil, errors.New("ETHEREUM_URL is required for the ethereum guardian set source")
+	}
+
 	var ethContract string
 	switch cfg.P2pNetwork {
 	case domain.P2pMainNet:
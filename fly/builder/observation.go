@@ -4,11 +4,14 @@ import (
 	"context"
 	"time"
 
+	"github.com/dgraph-io/ristretto"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/config"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/processor"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/queue"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/storage"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/txhash"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
@@ -44,7 +47,7 @@ func NewObservationConsumePublish(ctx context.Context, config *config.Configurat
 
 func NewTxHashStore(ctx context.Context, config *config.Configuration, metrics metrics.Metrics, db *mongo.Database, logger *zap.Logger) (txhash.TxHashStore, error) {
 	// Creates a txHashDedup to discard txHash from observations that were processed previously
-	txHashDedup, err := NewDeduplicator("observations-dedup", config.ObservationsDedup, logger)
+	txHashDedup, err := NewDeduplicator("observations-dedup", config, config.ObservationsDedup, metrics, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -61,3 +64,18 @@ func NewTxHashStore(ctx context.Context, config *config.Configuration, metrics m
 	dedupTxHashStore := txhash.NewDedupTxHashStore(txHashStore, txHashDedup, logger)
 	return dedupTxHashStore, nil
 }
+
+func NewObservationConflictDetector(config *config.Configuration, repository *storage.Repository, alertClient alert.AlertClient, metrics metrics.Metrics, logger *zap.Logger) (*processor.ObservationConflictDetector, error) {
+	// Creates a cache with the first hash observed by message to detect guardians signing different hashes
+	// the detector uses ristretto directly to wait for the writes to be applied before the next lookup.
+	conflictCache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: config.ObservationsConflict.NumKeys,
+		MaxCost:     config.ObservationsConflict.MaxCostsInMB * (1 << 20),
+		BufferItems: 64,
+	})
+	if err != nil {
+		return nil, err
+	}
+	expiration := time.Duration(config.ObservationsConflict.ExpirationInSeconds) * time.Second
+	return processor.NewObservationConflictDetector(conflictCache, expiration, repository, alertClient, metrics, logger), nil
+}
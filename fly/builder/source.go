@@ -0,0 +1,39 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/config"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/source"
+	"go.uber.org/zap"
+)
+
+// NewSource creates the source that feeds the gossip channels, selected by the INGESTION_SOURCE configuration.
+func NewSource(cfg *config.Configuration, p2pNetworkConfig *config.P2pNetworkConfig, nodeKeyPath string,
+	gst *common.GuardianSetState, cancel context.CancelFunc, logger *zap.Logger) (source.Source, error) {
+	sourceType, err := source.ParseType(cfg.IngestionSource)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("using ingestion source", zap.String("source", string(sourceType)))
+	switch sourceType {
+	case source.Spy:
+		if cfg.SpyAddr == "" {
+			return nil, errors.New("SPY_ADDR is required for the spy ingestion source")
+		}
+		retry := time.Duration(cfg.SpyRetrySeconds) * time.Second
+		return source.NewSpySource(cfg.SpyAddr, retry, logger), nil
+	case source.Replay:
+		if cfg.ReplayFile == "" {
+			return nil, errors.New("REPLAY_FILE is required for the replay ingestion source")
+		}
+		interval := time.Duration(cfg.ReplayIntervalMs) * time.Millisecond
+		return source.NewReplaySource(cfg.ReplayFile, interval, logger), nil
+	default:
+		return source.NewP2PSource(p2pNetworkConfig, cfg.P2pPort, nodeKeyPath, gst, cancel, logger), nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
 	"github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/config"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/notifier"
@@ -28,6 +29,19 @@ func NewVAARedisProducerFunc(cfg *config.Configuration, logger *zap.Logger) (pro
 	return producer.NewRedisProducer(client, channel).Push, nil
 }
 
+// NewVAARedisStreamProducerFunc creates a callback to publish VAA notifications to a redis stream.
+func NewVAARedisStreamProducerFunc(cfg *config.Configuration, logger *zap.Logger) (producer.PushFunc, error) {
+	if cfg.IsLocal || cfg.Redis.RedisVaaStream == "" {
+		return func(context.Context, *producer.Notification) error {
+			return nil
+		}, nil
+	}
+	client := NewRedisClient(cfg)
+	stream := fmt.Sprintf("%s:%s", cfg.Redis.RedisPrefix, cfg.Redis.RedisVaaStream)
+	logger.Info("using redis stream producer", zap.String("stream", stream))
+	return producer.NewRedisStreamProducer(redisstream.NewProducer(client, stream, cfg.Redis.RedisStreamMaxLen)).Push, nil
+}
+
 // Creates two callbacks depending on whether the execution is local (memory queue) or not (SQS queue)
 // callback to obtain queue messages from a queue
 // callback to publish vaa non pyth messages to a sink
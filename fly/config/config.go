@@ -35,41 +35,56 @@ type P2pNetworkConfig struct {
 }
 
 type Configuration struct {
-	P2pNetwork                string `env:"P2P_NETWORK,required"`
-	Environment               string `env:"ENVIRONMENT,required"`
-	LogLevel                  string `env:"LOG_LEVEL,default=warn"`
-	MongoUri                  string `env:"MONGODB_URI,required"`
-	MongoDatabase             string `env:"MONGODB_DATABASE,required"`
-	MongoEnableQueryLog       bool   `env:"MONGODB_ENABLE_QUERY_LOG"`
-	ObservationsChannelSize   int    `env:"OBSERVATIONS_CHANNEL_SIZE,required"`
-	VaasChannelSize           int    `env:"VAAS_CHANNEL_SIZE,required"`
-	HeartbeatsChannelSize     int    `env:"HEARTBEATS_CHANNEL_SIZE,required"`
-	GovernorConfigChannelSize int    `env:"GOVERNOR_CONFIG_CHANNEL_SIZE,required"`
-	GovernorStatusChannelSize int    `env:"GOVERNOR_STATUS_CHANNEL_SIZE,required"`
-	VaasWorkersSize           int    `env:"VAAS_WORKERS_SIZE,default=5"`
-	ObservationsWorkersSize   int    `env:"OBSERVATIONS_WORKERS_SIZE,default=10"`
-	AlertEnabled              bool   `env:"ALERT_ENABLED"`
-	AlertApiKey               string `env:"ALERT_API_KEY"`
-	MetricsEnabled            bool   `env:"METRICS_ENABLED"`
-	ApiPort                   uint   `env:"API_PORT,required"`
-	P2pPort                   uint   `env:"P2P_PORT,required"`
-	PprofEnabled              bool   `env:"PPROF_ENABLED"`
-	MaxHealthTimeSeconds      int64  `env:"MAX_HEALTH_TIME_SECONDS,default=60"`
+	P2pNetwork                string  `env:"P2P_NETWORK,required"`
+	Environment               string  `env:"ENVIRONMENT,required"`
+	LogLevel                  string  `env:"LOG_LEVEL,default=warn"`
+	MongoUri                  string  `env:"MONGODB_URI,required"`
+	MongoDatabase             string  `env:"MONGODB_DATABASE,required"`
+	MongoEnableQueryLog       bool    `env:"MONGODB_ENABLE_QUERY_LOG"`
+	ObservationsChannelSize   int     `env:"OBSERVATIONS_CHANNEL_SIZE,required"`
+	VaasChannelSize           int     `env:"VAAS_CHANNEL_SIZE,required"`
+	HeartbeatsChannelSize     int     `env:"HEARTBEATS_CHANNEL_SIZE,required"`
+	GovernorConfigChannelSize int     `env:"GOVERNOR_CONFIG_CHANNEL_SIZE,required"`
+	GovernorStatusChannelSize int     `env:"GOVERNOR_STATUS_CHANNEL_SIZE,required"`
+	VaasWorkersSize           int     `env:"VAAS_WORKERS_SIZE,default=5"`
+	ObservationsWorkersSize   int     `env:"OBSERVATIONS_WORKERS_SIZE,default=10"`
+	AlertEnabled              bool    `env:"ALERT_ENABLED"`
+	AlertApiKey               string  `env:"ALERT_API_KEY"`
+	MetricsEnabled            bool    `env:"METRICS_ENABLED"`
+	ApiPort                   uint    `env:"API_PORT,required"`
+	P2pPort                   uint    `env:"P2P_PORT,required"`
+	PprofEnabled              bool    `env:"PPROF_ENABLED"`
+	MaxHealthTimeSeconds      int64   `env:"MAX_HEALTH_TIME_SECONDS,default=60"`
+	TracingOtlpEndpoint       string  `env:"TRACING_OTLP_ENDPOINT"`
+	TracingOtlpInsecure       bool    `env:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio        float64 `env:"TRACING_SAMPLE_RATIO,default=1"`
+	IngestionSource           string  `env:"INGESTION_SOURCE,default=p2p"`
+	SpyAddr                   string  `env:"SPY_ADDR"`
+	SpyRetrySeconds           int     `env:"SPY_RETRY_SECONDS,default=5"`
+	ReplayFile                string  `env:"REPLAY_FILE"`
+	ReplayIntervalMs          int     `env:"REPLAY_INTERVAL_MS,default=0"`
 	IsLocal                   bool
 	Redis                     *RedisConfiguration
 	Aws                       *AwsConfiguration
 	ObservationsDedup         Cache `env:", prefix=OBSERVATIONS_DEDUP_,required"`
 	ObservationsTxHash        Cache `env:", prefix=OBSERVATIONS_TX_HASH_,required"`
+	ObservationsConflict      Cache `env:", prefix=OBSERVATIONS_CONFLICT_,required"`
 	VaasDedup                 Cache `env:", prefix=VAAS_DEDUP_,required"`
 	VaasPythDedup             Cache `env:", prefix=VAAS_PYTH_DEDUP_,required"`
 
-	EthereumUrl string `env:"ETHEREUM_URL,required"`
+	GuardianSetSource     string `env:"GUARDIAN_SET_SOURCE,default=ethereum"`
+	GuardianSetBundleFile string `env:"GUARDIAN_SET_BUNDLE_FILE"`
+	EthereumUrl           string `env:"ETHEREUM_URL"`
 }
 
 type RedisConfiguration struct {
 	RedisUri        string `env:"REDIS_URI,required"`
 	RedisPrefix     string `env:"REDIS_PREFIX,required"`
 	RedisVaaChannel string `env:"REDIS_VAA_CHANNEL,required"`
+	// RedisVaaStream is the stream to push the VAA notifications to, in addition to the channel.
+	// The stream is disabled when it is empty.
+	RedisVaaStream    string `env:"REDIS_VAA_STREAM"`
+	RedisStreamMaxLen int64  `env:"REDIS_STREAM_MAX_LEN,default=100000"`
 }
 
 type AwsConfiguration struct {
@@ -82,10 +97,22 @@ type AwsConfiguration struct {
 	EventsSnsUrl       string `env:"EVENTS_SNS_URL,required"`
 }
 
+// DedupMode defines how the deduplication is shared between workers.
+type DedupMode string
+
+const (
+	// DedupModeLocal deduplicates messages only within the process.
+	DedupModeLocal DedupMode = "local"
+	// DedupModeRedis claims messages in redis so that they are processed once across replicas.
+	DedupModeRedis DedupMode = "redis"
+)
+
 type Cache struct {
-	ExpirationInSeconds int64 `env:"CACHE_EXPIRATION_SECONDS,required"`
-	NumKeys             int64 `env:"CACHE_NUM_KEYS,required"`
-	MaxCostsInMB        int64 `env:"CACHE_MAX_COSTS_MB,required"`
+	ExpirationInSeconds  int64     `env:"CACHE_EXPIRATION_SECONDS,required"`
+	NumKeys              int64     `env:"CACHE_NUM_KEYS,required"`
+	MaxCostsInMB         int64     `env:"CACHE_MAX_COSTS_MB,required"`
+	Mode                 DedupMode `env:"MODE,default=local"`
+	ProcessingTTLSeconds int64     `env:"PROCESSING_TTL_SECONDS,default=30"`
 }
 
 // New creates a configuration with the values from .env file and environment variables.
@@ -116,6 +143,13 @@ func New(ctx context.Context, isLocal *bool) (*Configuration, error) {
 	return &configuration, nil
 }
 
+const (
+	// GuardianSetSourceEthereum reads guardian sets from the core contract through an Ethereum RPC.
+	GuardianSetSourceEthereum = "ethereum"
+	// GuardianSetSourceBundle verifies guardian sets from signed upgrade VAAs in a bundle file and the vaas collection.
+	GuardianSetSourceBundle = "bundle"
+)
+
 // GetP2pNetwork get p2p network config.
 func (c *Configuration) GetP2pNetwork() (*P2pNetworkConfig, error) {
 
@@ -0,0 +1,70 @@
+package deduplicator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const committedValue = "done"
+
+// Claimer represents a distributed lock used to process a message only once across replicas.
+type Claimer interface {
+	// Claim tries to take the key for processing. It returns false if the key is already
+	// being processed or was already processed by another worker.
+	Claim(ctx context.Context, key string) (bool, error)
+	// Commit marks the key as processed.
+	Commit(ctx context.Context, key string) error
+	// Release frees a claimed key so that it can be processed again.
+	Release(ctx context.Context, key string) error
+}
+
+// releaseScript deletes the key only if it is still claimed by the caller.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisClaimer is a Claimer backed by redis SET NX.
+type RedisClaimer struct {
+	client        *redis.Client
+	prefix        string
+	owner         string
+	processingTTL time.Duration
+	expiration    time.Duration
+}
+
+// NewRedisClaimer creates a redis claimer. A claim expires after processingTTL if it is neither
+// committed nor released, a committed key expires after expiration.
+func NewRedisClaimer(client *redis.Client, prefix string, processingTTL, expiration time.Duration) *RedisClaimer {
+	return &RedisClaimer{
+		client:        client,
+		prefix:        prefix,
+		owner:         uuid.NewString(),
+		processingTTL: processingTTL,
+		expiration:    expiration,
+	}
+}
+
+// Claim takes the key for processing.
+func (c *RedisClaimer) Claim(ctx context.Context, key string) (bool, error) {
+	return c.client.SetNX(ctx, c.key(key), c.owner, c.processingTTL).Result()
+}
+
+// Commit marks the key as processed.
+func (c *RedisClaimer) Commit(ctx context.Context, key string) error {
+	return c.client.Set(ctx, c.key(key), committedValue, c.expiration).Err()
+}
+
+// Release deletes the claim of the key if it is still owned by this claimer.
+func (c *RedisClaimer) Release(ctx context.Context, key string) error {
+	return releaseScript.Run(ctx, c.client, []string{c.key(key)}, c.owner).Err()
+}
+
+func (c *RedisClaimer) key(key string) string {
+	return fmt.Sprintf("%s:%s", c.prefix, key)
+}
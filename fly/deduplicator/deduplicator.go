@@ -17,6 +17,8 @@ type Deduplicator struct {
 	cache      cache.CacheInterface[bool]
 	logger     *zap.Logger
 	expiration time.Duration
+	claimer    Claimer
+	suppressed func()
 }
 
 // New creates a deduplicator instance
@@ -38,16 +40,61 @@ func WithExpiration(expiration time.Duration) Option {
 	}
 }
 
+// WithClaimer allows to share the deduplication between replicas. Before executing the fn function
+// the key is claimed, the claim is committed when fn succeeds and released when it fails.
+// The suppressed function is called every time a message is discarded because it was claimed
+// by another worker.
+func WithClaimer(claimer Claimer, suppressed func()) Option {
+	return func(d *Deduplicator) {
+		d.claimer = claimer
+		d.suppressed = suppressed
+	}
+}
+
 // Apply executes the fn function in case the message has not been received previously
 func (d *Deduplicator) Apply(ctx context.Context, key string, fn func() error) error {
 	if v, _ := d.cache.Get(ctx, key); v {
 		return nil
 	}
 
+	if d.claimer != nil {
+		return d.applyWithClaim(ctx, key, fn)
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	_ = d.cache.Set(ctx, key, true, store.WithCost(16), store.WithExpiration(d.expiration))
+
+	return nil
+}
+
+// applyWithClaim executes the fn function only if the key can be claimed.
+// If the claimer is not available the message is processed anyway, duplicates are preferred to message loss.
+func (d *Deduplicator) applyWithClaim(ctx context.Context, key string, fn func() error) error {
+	claimed, err := d.claimer.Claim(ctx, key)
+	if err != nil {
+		d.logger.Warn("Error claiming dedup key", zap.String("key", key), zap.Error(err))
+		claimed = true
+	}
+	if !claimed {
+		if d.suppressed != nil {
+			d.suppressed()
+		}
+		return nil
+	}
+
 	if err := fn(); err != nil {
+		if errRelease := d.claimer.Release(ctx, key); errRelease != nil {
+			d.logger.Warn("Error releasing dedup key", zap.String("key", key), zap.Error(errRelease))
+		}
 		return err
 	}
 
+	if err := d.claimer.Commit(ctx, key); err != nil {
+		d.logger.Warn("Error committing dedup key", zap.String("key", key), zap.Error(err))
+	}
 	_ = d.cache.Set(ctx, key, true, store.WithCost(16), store.WithExpiration(d.expiration))
 
 	return nil
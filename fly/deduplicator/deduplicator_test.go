@@ -3,6 +3,7 @@ package deduplicator
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -92,3 +93,101 @@ func TestDeduplicator_Apply_Error(t *testing.T) {
 		assert.Equal(t, 4, numberCalls)
 	})
 }
+
+// memoryClaimer is a claimer shared between deduplicators to simulate replicas.
+type memoryClaimer struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (c *memoryClaimer) Claim(_ context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.keys[key]; ok {
+		return false, nil
+	}
+	c.keys[key] = false
+	return true, nil
+}
+
+func (c *memoryClaimer) Commit(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys[key] = true
+	return nil
+}
+
+func (c *memoryClaimer) Release(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.keys, key)
+	return nil
+}
+
+func TestDeduplicator_Apply_Claimer(t *testing.T) {
+	ctx := context.TODO()
+	logger := zaptest.NewLogger(t)
+
+	t.Run("replicas process key once", func(t *testing.T) {
+		claimer := &memoryClaimer{keys: map[string]bool{}}
+		suppressed := 0
+		onSuppressed := func() { suppressed++ }
+		d1 := New(newCache(), logger, WithClaimer(claimer, onSuppressed))
+		d2 := New(newCache(), logger, WithClaimer(claimer, onSuppressed))
+
+		numberCalls := 0
+		fnc := func() error {
+			numberCalls++
+			return nil
+		}
+		assert.Nil(t, d1.Apply(ctx, "key-1", fnc))
+		assert.Nil(t, d2.Apply(ctx, "key-1", fnc))
+		assert.Nil(t, d2.Apply(ctx, "key-2", fnc))
+		assert.Nil(t, d1.Apply(ctx, "key-2", fnc))
+		assert.Equal(t, 2, numberCalls)
+		assert.Equal(t, 2, suppressed)
+		assert.True(t, claimer.keys["key-1"])
+		assert.True(t, claimer.keys["key-2"])
+	})
+
+	t.Run("key in process is suppressed", func(t *testing.T) {
+		claimer := &memoryClaimer{keys: map[string]bool{}}
+		suppressed := 0
+		d1 := New(newCache(), logger, WithClaimer(claimer, func() { suppressed++ }))
+		d2 := New(newCache(), logger, WithClaimer(claimer, func() { suppressed++ }))
+
+		numberCalls := 0
+		err := d1.Apply(ctx, "key-1", func() error {
+			numberCalls++
+			return d2.Apply(ctx, "key-1", func() error {
+				numberCalls++
+				return nil
+			})
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, numberCalls)
+		assert.Equal(t, 1, suppressed)
+	})
+
+	t.Run("failed processing releases claim", func(t *testing.T) {
+		claimer := &memoryClaimer{keys: map[string]bool{}}
+		d1 := New(newCache(), logger, WithClaimer(claimer, nil))
+		d2 := New(newCache(), logger, WithClaimer(claimer, nil))
+
+		numberCalls := 0
+		err := d1.Apply(ctx, "key-1", func() error {
+			numberCalls++
+			return fmt.Errorf("failed")
+		})
+		assert.NotNil(t, err)
+		_, ok := claimer.keys["key-1"]
+		assert.False(t, ok)
+
+		err = d2.Apply(ctx, "key-1", func() error {
+			numberCalls++
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, numberCalls)
+	})
+}
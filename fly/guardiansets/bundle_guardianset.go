@@ -0,0 +1,136 @@
+package guardiansets
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/guardianset"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// bundleGuardianSet serves guardian sets verified from signed guardian set upgrade VAAs, read from a
+// bundle file and the vaas collection, so no Ethereum RPC is needed.
+type bundleGuardianSet struct {
+	history     *guardianset.History
+	db          *mongo.Database
+	repository  *repository.GuardianSetRepository
+	alertClient alert.AlertClient
+	logger      *zap.Logger
+}
+
+var _ GuardianSetProvider = &bundleGuardianSet{}
+
+func NewBundleGuardianSet(ctx context.Context, p2pNetwork, bundlePath string, db *mongo.Database,
+	repository *repository.GuardianSetRepository, alertClient alert.AlertClient, logger *zap.Logger) (*bundleGuardianSet, error) {
+	history, err := guardianset.Load(ctx, p2pNetwork, bundlePath, db, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &bundleGuardianSet{
+		history:     history,
+		db:          db,
+		repository:  repository,
+		alertClient: alertClient,
+		logger:      logger,
+	}, nil
+}
+
+// GetCurrentGuardianSetIndex applies the new guardian set upgrades stored in the vaas collection and returns the current index.
+func (b *bundleGuardianSet) GetCurrentGuardianSetIndex(ctx context.Context) (uint32, error) {
+	vaas, err := guardianset.LoadFromDatabase(ctx, b.db, b.logger)
+	if err != nil {
+		return 0, err
+	}
+
+	var upgrades []*guardianset.Upgrade
+	for _, v := range vaas {
+		u, err := guardianset.ParseUpgrade(v)
+		if err != nil {
+			b.logger.Warn("invalid guardian set upgrade", zap.String("id", v.MessageID()), zap.Error(err))
+			continue
+		}
+		upgrades = append(upgrades, u)
+	}
+	sort.Slice(upgrades, func(i, j int) bool {
+		return upgrades[i].NewIndex < upgrades[j].NewIndex
+	})
+
+	applied := false
+	for _, u := range upgrades {
+		if u.NewIndex != b.history.GetLatest().Index+1 {
+			continue
+		}
+		if err := b.history.Apply(u); err != nil {
+			b.logger.Error("failed to apply guardian set upgrade", zap.Uint32("index", u.NewIndex), zap.Error(err))
+			continue
+		}
+		applied = true
+		b.logger.Info("guardian set upgrade applied", zap.Uint32("index", u.NewIndex))
+	}
+
+	// the expiration time of the replaced guardian set changes too, so store the whole history.
+	if applied {
+		if err := b.Sync(ctx); err != nil {
+			return 0, err
+		}
+	}
+	return b.history.GetLatest().Index, nil
+}
+
+func (b *bundleGuardianSet) GetGuardianSet(ctx context.Context, index uint32) (*common.GuardianSet, *time.Time, error) {
+	gs, expiration, err := b.history.Get(index)
+	if err != nil {
+		return nil, nil, err
+	}
+	if expiration.IsZero() {
+		return gs, nil, nil
+	}
+	return gs, &expiration, nil
+}
+
+func (b *bundleGuardianSet) GetGuardianSetHistory(ctx context.Context) (*GuardianSetHistory, error) {
+	guardianSetsByIndex, expirationTimesByIndex := b.history.All()
+	return &GuardianSetHistory{
+		guardianSetsByIndex:    guardianSetsByIndex,
+		expirationTimesByIndex: expirationTimesByIndex,
+		alertClient:            b.alertClient,
+	}, nil
+}
+
+// Sync stores every verified guardian set in the guardian sets collection.
+func (b *bundleGuardianSet) Sync(ctx context.Context) error {
+	guardianSetsByIndex, expirationTimesByIndex := b.history.All()
+	for i := range guardianSetsByIndex {
+		if err := b.AddGuardianSet(ctx, &guardianSetsByIndex[i], expirationTimesByIndex[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddGuardianSet implements GuardianSetProvider.
+func (b *bundleGuardianSet) AddGuardianSet(ctx context.Context, gs *common.GuardianSet, et time.Time) error {
+	var keys []repository.GuardianSetKeyDoc
+	for index, v := range gs.Keys {
+		keys = append(keys, repository.GuardianSetKeyDoc{
+			Index:   uint32(index),
+			Address: v.Bytes(),
+		})
+	}
+	var expiration *time.Time
+	if !et.IsZero() {
+		expiration = &et
+	}
+	doc := &repository.GuardianSetDoc{
+		GuardianSetIndex: gs.Index,
+		Keys:             keys,
+		ExpirationTime:   expiration,
+		UpdatedAt:        time.Now(),
+	}
+	return b.repository.Upsert(ctx, doc)
+}
@@ -16,6 +16,7 @@ const (
 	ErrorSaveGovernorStatus = "ERROR_SAVE_GOVERNOR_STATUS"
 	ErrorSaveGovernorConfig = "ERROR_SAVE_GOVERNOR_CONFIG"
 	ErrorGuardianNoActivity = "ERROR_GUARDIAN_NO_ACTIVITY"
+	ObservationConflict     = "OBSERVATION_CONFLICT"
 
 	// warning alerts
 	GuardianSetUnknown       = "GUARDIAN_SET_UNKNOWN"
@@ -101,5 +102,15 @@ func LoadAlerts(cfg alert.AlertConfig) map[string]alert.Alert {
 		Entity:      "fly",
 		Priority:    alert.INFORMATIONAL,
 	}
+	// Alert guardians signed different hashes for the same message.
+	alerts[ObservationConflict] = alert.Alert{
+		Alias:       ObservationConflict,
+		Message:     fmt.Sprintf("[%s] %s", cfg.Environment, "Guardians signed different hashes for the same message"),
+		Description: "Observations from guardians for the same chain/emitter/sequence carry a hash different from the digest of the signed VAA.",
+		Actions:     []string{"check conflictingObservations collection", "check guardian nodes of the signers"},
+		Tags:        []string{cfg.Environment, "fly", "observation", "equivocation"},
+		Entity:      "fly",
+		Priority:    alert.CRITICAL,
+	}
 	return alerts
 }
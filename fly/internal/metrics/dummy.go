@@ -42,6 +42,9 @@ func (d *DummyMetrics) IncObservationInserted(chain sdk.ChainID) {}
 // IncObservationWithoutTxHash increases the number of observation without tx hash.
 func (d *DummyMetrics) IncObservationWithoutTxHash(chain sdk.ChainID) {}
 
+// IncObservationConflict increases the number of messages with conflicting observations.
+func (d *DummyMetrics) IncObservationConflict(chain sdk.ChainID, confirmed bool) {}
+
 // IncVaaSendNotification increases the number of vaa send notifcations to pipeline.
 func (d *DummyMetrics) IncVaaSendNotification(chain sdk.ChainID) {}
 
@@ -89,4 +92,6 @@ func (m *DummyMetrics) IncConsistencyLevelByChainID(chainID sdk.ChainID, consist
 
 func (m *DummyMetrics) IncDuplicateVaaByChainID(chain sdk.ChainID) {}
 
+func (m *DummyMetrics) IncDedupClaimSuppressed(name string) {}
+
 func (m *DummyMetrics) VaaProcessingDuration(chain sdk.ChainID, start *time.Time) {}
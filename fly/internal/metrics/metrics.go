@@ -22,6 +22,7 @@ type Metrics interface {
 	IncObservationUnfiltered(chain sdk.ChainID)
 	IncObservationInserted(chain sdk.ChainID)
 	IncObservationWithoutTxHash(chain sdk.ChainID)
+	IncObservationConflict(chain sdk.ChainID, confirmed bool)
 	IncObservationTotal()
 	IncBatchObservationTotal(batchSize uint)
 	IncObservationInvalidGuardian(address string)
@@ -53,6 +54,9 @@ type Metrics interface {
 	// duplicate vaa metrics
 	IncDuplicateVaaByChainID(chain sdk.ChainID)
 
+	// dedup metrics
+	IncDedupClaimSuppressed(name string)
+
 	// vaas processing duration
 	VaaProcessingDuration(chain sdk.ChainID, start *time.Time)
 }
@@ -15,6 +15,7 @@ type PrometheusMetrics struct {
 	vaaTotal                      prometheus.Counter
 	observationReceivedCount      *prometheus.CounterVec
 	observationTotal              prometheus.Counter
+	observationConflictCount      *prometheus.CounterVec
 	batchObservationTotal         prometheus.Counter
 	batchSizeObservations         prometheus.Gauge
 	observationReceivedByGuardian *prometheus.CounterVec
@@ -25,6 +26,7 @@ type PrometheusMetrics struct {
 	txHashSearchCount             *prometheus.CounterVec
 	consistenceLevelChainCount    *prometheus.CounterVec
 	duplicateVaaByChainCount      *prometheus.CounterVec
+	dedupClaimSuppressedCount     *prometheus.CounterVec
 	vaaProcessingDuration         *prometheus.HistogramVec
 }
 
@@ -55,6 +57,13 @@ func NewPrometheusMetrics(environment string) *PrometheusMetrics {
 			ConstLabels: constLabels,
 		}, []string{"chain", "type"})
 
+	observationConflictCount := promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "observation_conflict_count_by_chain",
+			Help:        "Total number of messages with conflicting observations by chain",
+			ConstLabels: constLabels,
+		}, []string{"chain", "status"})
+
 	observationTotal := promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name:        "observation_total",
@@ -127,6 +136,12 @@ func NewPrometheusMetrics(environment string) *PrometheusMetrics {
 			Help:        "Total number of duplicate vaa by chain",
 			ConstLabels: constLabels,
 		}, []string{"chain"})
+	dedupClaimSuppressedCount := promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "dedup_claim_suppressed_count",
+			Help:        "Total number of messages discarded because they were claimed by another worker",
+			ConstLabels: constLabels,
+		}, []string{"dedup"})
 	vaaProcessingDuration := promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:        "vaa_processing_duration_seconds",
@@ -141,6 +156,7 @@ func NewPrometheusMetrics(environment string) *PrometheusMetrics {
 		vaaTotal:                      vaaTotal,
 		observationReceivedCount:      observationReceivedCount,
 		observationTotal:              observationTotal,
+		observationConflictCount:      observationConflictCount,
 		batchObservationTotal:         batchObservationTotal,
 		batchSizeObservations:         batchSizeObservations,
 		heartbeatReceivedCount:        heartbeatReceivedCount,
@@ -151,6 +167,7 @@ func NewPrometheusMetrics(environment string) *PrometheusMetrics {
 		observationReceivedByGuardian: observationReceivedByGuardian,
 		consistenceLevelChainCount:    consistenceLevelChainCount,
 		duplicateVaaByChainCount:      duplicateVaaByChainCount,
+		dedupClaimSuppressedCount:     dedupClaimSuppressedCount,
 		vaaProcessingDuration:         vaaProcessingDuration,
 	}
 }
@@ -205,6 +222,15 @@ func (m *PrometheusMetrics) IncObservationWithoutTxHash(chain sdk.ChainID) {
 	m.observationReceivedCount.WithLabelValues(chain.String(), "without_txhash").Inc()
 }
 
+// IncObservationConflict increases the number of messages with conflicting observations.
+func (m *PrometheusMetrics) IncObservationConflict(chain sdk.ChainID, confirmed bool) {
+	status := "unconfirmed"
+	if confirmed {
+		status = "confirmed"
+	}
+	m.observationConflictCount.WithLabelValues(chain.String(), status).Inc()
+}
+
 // IncObservationTotal increases the number of observation received from Gossip network.
 func (m *PrometheusMetrics) IncObservationTotal() {
 	m.observationTotal.Inc()
@@ -284,6 +310,11 @@ func (m *PrometheusMetrics) IncDuplicateVaaByChainID(chain sdk.ChainID) {
 	m.duplicateVaaByChainCount.WithLabelValues(chain.String()).Inc()
 }
 
+// IncDedupClaimSuppressed increases the number of messages discarded because they were claimed by another worker.
+func (m *PrometheusMetrics) IncDedupClaimSuppressed(name string) {
+	m.dedupClaimSuppressedCount.WithLabelValues(name).Inc()
+}
+
 // VaaProcessingDuration increases the duration of vaa processing.
 func (m *PrometheusMetrics) VaaProcessingDuration(chain sdk.ChainID, start *time.Time) {
 	if start == nil {
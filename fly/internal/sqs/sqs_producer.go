@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_sqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 )
 
 type Producer struct {
@@ -29,6 +30,7 @@ func (p *Producer) SendMessage(ctx context.Context, groupID, deduplicationID, bo
 			MessageDeduplicationId: aws.String(deduplicationID),
 			MessageBody:            aws.String(body),
 			QueueUrl:               aws.String(p.url),
+			MessageAttributes:      telemetry.InjectSQSAttributes(ctx, nil),
 		})
 	return err
 }
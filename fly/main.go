@@ -11,6 +11,7 @@ import (
 
 	healthcheck "github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/builder"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/config"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/gossip"
@@ -22,9 +23,6 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/fly/storage"
 
 	"github.com/certusone/wormhole/node/pkg/common"
-	"github.com/certusone/wormhole/node/pkg/p2p"
-	"github.com/certusone/wormhole/node/pkg/supervisor"
-	"github.com/libp2p/go-libp2p/core/crypto"
 	"go.uber.org/zap"
 )
 
@@ -69,6 +67,18 @@ func main() {
 		logger.Fatal("could not create alert client", zap.Error(err))
 	}
 
+	// Set up tracing
+	shutdownTracing, err := telemetry.Setup(rootCtx, telemetry.Config{
+		ServiceName:  "wormhole-fly",
+		Environment:  cfg.Environment,
+		OtlpEndpoint: cfg.TracingOtlpEndpoint,
+		OtlpInsecure: cfg.TracingOtlpInsecure,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("could not set up tracing", zap.Error(err))
+	}
+
 	// New metrics client
 	metrics := builder.NewMetrics(cfg)
 
@@ -90,8 +100,14 @@ func main() {
 		logger.Fatal("could not create vaa redis producer", zap.Error(err))
 	}
 
-	// Creates a composite callback to publish VAA messages to a redis pubsub
-	producerFunc := producer.NewComposite(vaaRedisProducerFunc)
+	// Creates a callback to publish VAA messages to a redis stream
+	vaaRedisStreamProducerFunc, err := builder.NewVAARedisStreamProducerFunc(cfg, logger)
+	if err != nil {
+		logger.Fatal("could not create vaa redis stream producer", zap.Error(err))
+	}
+
+	// Creates a composite callback to publish VAA messages to a redis pubsub and stream
+	producerFunc := producer.NewComposite(vaaRedisProducerFunc, vaaRedisStreamProducerFunc)
 
 	txHashStore, err := builder.NewTxHashStore(rootCtx, cfg, metrics, db.Database, logger)
 	if err != nil {
@@ -101,12 +117,12 @@ func main() {
 
 	repository := storage.NewRepository(alertClient, metrics, db.Database, producerFunc, txHashStore, eventDispatcher, logger)
 
-	vaaNonPythDedup, err := builder.NewDeduplicator("vaas-dedup", cfg.VaasDedup, logger)
+	vaaNonPythDedup, err := builder.NewDeduplicator("vaas-dedup", cfg, cfg.VaasDedup, metrics, logger)
 	if err != nil {
 		logger.Fatal("could not create vaa deduplicator", zap.Error(err))
 	}
 
-	vaaPythDedup, err := builder.NewDeduplicator("vaas-pyth-dedup", cfg.VaasPythDedup, logger)
+	vaaPythDedup, err := builder.NewDeduplicator("vaas-pyth-dedup", cfg, cfg.VaasPythDedup, metrics, logger)
 	if err != nil {
 		logger.Fatal("could not create vaa deduplicator", zap.Error(err))
 	}
@@ -128,9 +144,15 @@ func main() {
 	discardMessages(rootCtx, channels.ObsvReqChannel)
 	guardianCheck := health.NewGuardianCheck(cfg.MaxHealthTimeSeconds)
 
+	observationConflictDetector, err := builder.NewObservationConflictDetector(cfg, repository, alertClient, metrics, logger)
+	if err != nil {
+		logger.Fatal("could not create observation conflict detector", zap.Error(err))
+	}
+	observationConflictDetector.Start(rootCtx)
+
 	healthObservations, observationQueueConsume, observationPublish := builder.NewObservationConsumePublish(rootCtx, cfg, logger)
 	observationGossipConsumer := processor.NewObservationGossipConsumer(observationPublish, gst, p2pNetworkConfig.Enviroment,
-		cfg.ObservationsChannelSize, cfg.ObservationsWorkersSize, metrics, txHashStore, repository, logger)
+		cfg.ObservationsChannelSize, cfg.ObservationsWorkersSize, metrics, txHashStore, repository, observationConflictDetector, logger)
 	observationQueueConsumer := processor.NewObservationQueueConsumer(observationQueueConsume, repository, metrics, logger)
 	observationGossipConsumer.Start(rootCtx)
 	observationQueueConsumer.Start(rootCtx)
@@ -179,55 +201,14 @@ func main() {
 	governorStatusHandler := gossip.NewGovernorStatusHandler(channels.GovStatusChannel, repository, guardianCheck, metrics, logger)
 	governorStatusHandler.Start(rootCtx)
 
-	// Load p2p private key
-	var priv crypto.PrivKey
-	priv, err = common.GetOrCreateNodeKey(logger, nodeKeyPath)
+	// Start the ingestion source that feeds the gossip channels
+	ingestionSource, err := builder.NewSource(cfg, p2pNetworkConfig, nodeKeyPath, gst, rootCtxCancel, logger)
 	if err != nil {
-		logger.Fatal("Failed to load node key", zap.Error(err))
-	}
-
-	components := p2p.DefaultComponents()
-	components.Port = cfg.P2pPort
-	components.WarnChannelOverflow = true
-
-	runParams, errRunParams := p2p.NewRunParams(
-		p2pNetworkConfig.P2pBootstrap,
-		p2pNetworkConfig.P2pNetworkID,
-		priv,
-		gst,
-		rootCtxCancel,
-		p2p.WithSignedObservationListener(channels.ObsvChannel),
-		p2p.WithSignedObservationBatchListener(channels.BatchObsvC),
-		p2p.WithSignedVAAListener(channels.SignedInChannel),
-		p2p.WithObservationRequestListener(channels.ObsvReqChannel),
-		p2p.WithChainGovernorConfigListener(channels.GovConfigChannel),
-		p2p.WithChainGovernorStatusListener(channels.GovStatusChannel),
-		p2p.WithComponents(components),
-	)
-	if errRunParams != nil {
-		logger.Fatal("failed to create run params", zap.Error(errRunParams))
-	}
-
-	// Run supervisor.
-	supervisor.New(rootCtx, logger, func(ctx context.Context) error {
-		components := p2p.DefaultComponents()
-		components.Port = cfg.P2pPort
-		components.WarnChannelOverflow = true
-		if err := supervisor.Run(ctx, "p2p",
-			p2p.Run(
-				runParams,
-			)); err != nil {
-			return err
-		}
-
-		logger.Info("Started internal services")
-
-		<-ctx.Done()
-		return nil
-	},
-		// It's safer to crash and restart the process in case we encounter a panic,
-		// rather than attempting to reschedule the runnable.
-		supervisor.WithPropagatePanic)
+		logger.Fatal("could not create ingestion source", zap.Error(err))
+	}
+	if err := ingestionSource.Start(rootCtx, channels); err != nil {
+		logger.Fatal("could not start ingestion source", zap.Error(err))
+	}
 
 	<-rootCtx.Done()
 
@@ -238,6 +219,13 @@ func main() {
 
 	logger.Info("Closing MongoDB connection...")
 	db.DisconnectWithTimeout(10 * time.Second)
+
+	logger.Info("Flushing traces...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", zap.Error(err))
+	}
 }
 
 func discardMessages[T any](ctx context.Context, obsvReqC chan T) {
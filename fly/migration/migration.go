@@ -72,6 +72,36 @@ func Run(db *mongo.Database) error {
 		return err
 	}
 
+	// Create conflictingObservations collection.
+	err = db.CreateCollection(context.TODO(), repository.ConflictingObservations)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// Create stuckMessages collection.
+	err = db.CreateCollection(context.TODO(), repository.StuckMessages)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// Create governanceActions collection.
+	err = db.CreateCollection(context.TODO(), repository.GovernanceActions)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// Create emitters collection.
+	err = db.CreateCollection(context.TODO(), repository.Emitters)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// Create txHashQueue collection.
+	err = db.CreateCollection(context.TODO(), repository.TxHashQueue)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
 	// create index in vaas collection by vaa key (emitterchain, emitterAddr, sequence)
 	indexVaaByKey := mongo.IndexModel{
 		Keys: bson.D{
@@ -105,6 +135,17 @@ func Run(db *mongo.Database) error {
 		return err
 	}
 
+	// create index in vaas collection by indexedAt, used to read the vaas incrementally.
+	indexVaaByIndexedAtId := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "indexedAt", Value: 1},
+			{Key: "_id", Value: 1},
+		}}
+	_, err = db.Collection("vaas").Indexes().CreateOne(context.TODO(), indexVaaByIndexedAtId)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
 	indexVaaByTxHash := mongo.IndexModel{
 		Keys: bson.D{
 			{Key: "txHash", Value: 1},
@@ -181,6 +222,14 @@ func Run(db *mongo.Database) error {
 		return err
 	}
 
+	// create index in globalTransactions collection by chain and timestamp for the reorg checker.
+	indexGlobalTransactionsByOriginChainIdAndTimestamp := mongo.IndexModel{
+		Keys: bson.D{{Key: "originTx.chainId", Value: 1}, {Key: "originTx.timestamp", Value: 1}}}
+	_, err = db.Collection("globalTransactions").Indexes().CreateOne(context.TODO(), indexGlobalTransactionsByOriginChainIdAndTimestamp)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
 	// create index in globalTransactions collection by destination txHash.
 	indexGlobalTransactionsByDestinationTxHash := mongo.IndexModel{
 		Keys: bson.D{{Key: "destinationTx.txHash", Value: 1}}}
@@ -197,6 +246,22 @@ func Run(db *mongo.Database) error {
 		return err
 	}
 
+	// create index in globalTransactions collection by destinationTx updatedAt.
+	indexGlobalTransactionsByDestinationUpdatedAt := mongo.IndexModel{
+		Keys: bson.D{{Key: "destinationTx.updatedAt", Value: 1}}}
+	_, err = db.Collection("globalTransactions").Indexes().CreateOne(context.TODO(), indexGlobalTransactionsByDestinationUpdatedAt)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create index in globalTransactions collection by originTx updatedAt.
+	indexGlobalTransactionsByOriginUpdatedAt := mongo.IndexModel{
+		Keys: bson.D{{Key: "originTx.updatedAt", Value: 1}}}
+	_, err = db.Collection("globalTransactions").Indexes().CreateOne(context.TODO(), indexGlobalTransactionsByOriginUpdatedAt)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
 	// create index in parsedVaa collection by standardizedProperties toAddress.
 	indexParsedVaaByStandardizedPropertiesToAddress := mongo.IndexModel{
 		Keys: bson.D{{Key: "standardizedProperties.toAddress", Value: 1}}}
@@ -290,6 +355,71 @@ func Run(db *mongo.Database) error {
 		return err
 	}
 
+	// create index in conflictingObservations collection by indexedAt.
+	indexConflictingObservationsByIndexedAt := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "indexedAt", Value: -1},
+			{Key: "_id", Value: -1},
+		}}
+	_, err = db.Collection(repository.ConflictingObservations).Indexes().CreateOne(context.TODO(), indexConflictingObservationsByIndexedAt)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create index in stuckMessages collection by firstObservedAt.
+	indexStuckMessagesByFirstObservedAt := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "firstObservedAt", Value: 1},
+		}}
+	_, err = db.Collection(repository.StuckMessages).Indexes().CreateOne(context.TODO(), indexStuckMessagesByFirstObservedAt)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create index in governanceActions collection by type, targetChain and timestamp.
+	indexGovernanceActionsByTypeTargetChain := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "type", Value: 1},
+			{Key: "targetChain", Value: 1},
+			{Key: "timestamp", Value: -1},
+		}}
+	_, err = db.Collection(repository.GovernanceActions).Indexes().CreateOne(context.TODO(), indexGovernanceActionsByTypeTargetChain)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create index in governanceActions collection by timestamp.
+	indexGovernanceActionsByTimestamp := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "timestamp", Value: -1},
+			{Key: "_id", Value: -1},
+		}}
+	_, err = db.Collection(repository.GovernanceActions).Indexes().CreateOne(context.TODO(), indexGovernanceActionsByTimestamp)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create index in emitters collection by appId and emitterChain.
+	indexEmittersByAppIdChain := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "appId", Value: 1},
+			{Key: "emitterChain", Value: 1},
+		}}
+	_, err = db.Collection(repository.Emitters).Indexes().CreateOne(context.TODO(), indexEmittersByAppIdChain)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create index in txHashQueue collection by nextAttempt.
+	indexTxHashQueueByNextAttempt := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "nextAttempt", Value: 1},
+		}}
+	_, err = db.Collection(repository.TxHashQueue).Indexes().CreateOne(context.TODO(), indexTxHashQueueByNextAttempt)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
 	// create index in nodeGovernorVaas collection by vaaId.
 	indexNodeGovernorVaasByVaaId := mongo.IndexModel{
 		Keys: bson.D{{Key: "vaaId", Value: 1}}}
@@ -0,0 +1,267 @@
+package processor
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	gossipv1 "github.com/certusone/wormhole/node/pkg/proto/gossip/v1"
+	"github.com/dgraph-io/ristretto"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
+	flyAlert "github.com/wormhole-foundation/wormhole-explorer/fly/internal/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/metrics"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/storage"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// ObservedHash is the first hash observed for a message.
+type ObservedHash struct {
+	Hash         string
+	GuardianAddr string
+	// Conflict is true once a different hash was observed for the message.
+	Conflict bool
+	// Confirmed is true once a hash differs from the digest of the signed VAA of the message.
+	Confirmed bool
+}
+
+const (
+	// conflictConfirmationInterval is the time between two confirmations of the unconfirmed conflicts.
+	conflictConfirmationInterval = time.Minute
+	// conflictConfirmationWindow is how long an unconfirmed conflict waits for the signed VAA of its message.
+	conflictConfirmationWindow = 24 * time.Hour
+	// conflictConfirmationLimit is the maximum number of unconfirmed conflicts read at once.
+	conflictConfirmationLimit = 1000
+)
+
+// conflictRepository decouples the conflict detector from the repository.
+type conflictRepository interface {
+	FindObservationSigners(ctx context.Context, chainID sdk.ChainID, emitter, sequence string) ([]storage.ConflictingSigner, error)
+	UpsertConflictingObservation(ctx context.Context, c *storage.ConflictingObservationUpdate, signers []storage.ConflictingSigner) (bool, error)
+	FindUnconfirmedConflictingObservations(ctx context.Context, from time.Time, limit int64) ([]storage.ConflictingObservation, error)
+	FindVaaDigest(ctx context.Context, id string) (string, error)
+	ConfirmConflictingObservation(ctx context.Context, id string, digest string) (bool, error)
+}
+
+// ObservationConflictDetector flags messages for which guardians sign different hashes.
+//
+// The hash of an observation is not bound to its message id, so a conflict is recorded as unconfirmed
+// until the signed VAA of the message is stored: it is confirmed, and a critical alert is sent, when a
+// guardian signed a hash different from the digest of the VAA. The VAA is usually stored after the
+// conflicting observations, so the unconfirmed conflicts are also confirmed periodically.
+type ObservationConflictDetector struct {
+	// mu makes the lookup and the update of the first hash of a message atomic across the workers.
+	mu         sync.Mutex
+	cache      *ristretto.Cache
+	expiration time.Duration
+	repository conflictRepository
+	alert      alert.AlertClient
+	metrics    metrics.Metrics
+	logger     *zap.Logger
+}
+
+// NewObservationConflictDetector creates a new observation conflict detector.
+func NewObservationConflictDetector(cache *ristretto.Cache, expiration time.Duration, repository conflictRepository,
+	alert alert.AlertClient, metrics metrics.Metrics, logger *zap.Logger) *ObservationConflictDetector {
+	return &ObservationConflictDetector{
+		cache:      cache,
+		expiration: expiration,
+		repository: repository,
+		alert:      alert,
+		metrics:    metrics,
+		logger:     logger,
+	}
+}
+
+// Start confirms periodically the unconfirmed conflicts against the signed VAA of their message
+// until the context is cancelled.
+func (d *ObservationConflictDetector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(conflictConfirmationInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.confirmPending(ctx)
+			}
+		}
+	}()
+}
+
+// Check compares the hash of the observation with the first hash observed for its message.
+// When they differ the signers and hashes of the message are recorded as evidence, and the
+// conflict is confirmed against the signed VAA of the message if it is already stored.
+func (d *ObservationConflictDetector) Check(ctx context.Context, chainID sdk.ChainID, o *gossipv1.SignedObservation) {
+	if chainID == sdk.ChainIDPythNet {
+		return
+	}
+	signer := storage.ConflictingSigner{
+		GuardianAddr: eth_common.BytesToAddress(o.GetAddr()).String(),
+		Hash:         hex.EncodeToString(o.GetHash()),
+	}
+
+	seen, first, ok := d.observe(o.MessageId, signer)
+	if !ok {
+		return
+	}
+
+	vaaID := strings.Split(o.MessageId, "/")
+	if len(vaaID) != 3 {
+		return
+	}
+	emitter, sequence := vaaID[1], vaaID[2]
+
+	firstSigner := storage.ConflictingSigner{GuardianAddr: seen.GuardianAddr, Hash: seen.Hash}
+	signers := []storage.ConflictingSigner{signer}
+	if first {
+		// first conflicting observation, collect the evidence already stored.
+		signers = append(signers, firstSigner)
+		stored, err := d.repository.FindObservationSigners(ctx, chainID, emitter, sequence)
+		if err != nil {
+			d.logger.Error("Error finding observation signers", zap.String("id", o.MessageId), zap.Error(err))
+		}
+		signers = append(signers, stored...)
+	}
+
+	now := time.Now()
+	conflict := &storage.ConflictingObservationUpdate{
+		ID:           o.MessageId,
+		EmitterChain: chainID,
+		EmitterAddr:  emitter,
+		Sequence:     sequence,
+		UpdatedAt:    &now,
+	}
+	isNew, err := d.repository.UpsertConflictingObservation(ctx, conflict, signers)
+	if err != nil {
+		return
+	}
+	if isNew {
+		d.metrics.IncObservationConflict(chainID, false)
+		d.logger.Info("Guardians signed different hashes for the same message, waiting for the signed vaa",
+			zap.String("id", o.MessageId),
+			zap.String("hash", signer.Hash),
+			zap.String("firstHash", seen.Hash))
+	}
+	if seen.Confirmed {
+		return
+	}
+	d.confirmConflict(ctx, conflict, []storage.ConflictingSigner{signer, firstSigner})
+}
+
+// confirmPending confirms the unconfirmed conflicts whose signed VAA was stored after their observations.
+func (d *ObservationConflictDetector) confirmPending(ctx context.Context) {
+	from := time.Now().Add(-conflictConfirmationWindow)
+	conflicts, err := d.repository.FindUnconfirmedConflictingObservations(ctx, from, conflictConfirmationLimit)
+	if err != nil {
+		d.logger.Error("Error finding unconfirmed conflicting observations", zap.Error(err))
+		return
+	}
+	for _, c := range conflicts {
+		if ctx.Err() != nil {
+			return
+		}
+		conflict := &storage.ConflictingObservationUpdate{
+			ID:           c.ID,
+			EmitterChain: c.EmitterChain,
+			EmitterAddr:  c.EmitterAddr,
+			Sequence:     c.Sequence,
+		}
+		d.confirmConflict(ctx, conflict, c.Signers)
+	}
+}
+
+// confirmConflict confirms the conflict of a message when one of the signed hashes is not the digest of
+// its signed VAA, and sends a critical alert the first time. It does nothing while the VAA is not stored.
+func (d *ObservationConflictDetector) confirmConflict(ctx context.Context, conflict *storage.ConflictingObservationUpdate,
+	signers []storage.ConflictingSigner) {
+	digest, err := d.repository.FindVaaDigest(ctx, conflict.ID)
+	if err != nil {
+		d.logger.Error("Error finding vaa digest", zap.String("id", conflict.ID), zap.Error(err))
+		return
+	}
+	digest = utils.NormalizeHex(digest)
+	if digest == "" {
+		return
+	}
+	var signer *storage.ConflictingSigner
+	for i := range signers {
+		if utils.NormalizeHex(signers[i].Hash) != digest {
+			signer = &signers[i]
+			break
+		}
+	}
+	if signer == nil {
+		return
+	}
+
+	confirmed, err := d.repository.ConfirmConflictingObservation(ctx, conflict.ID, digest)
+	if err != nil {
+		return
+	}
+	d.confirm(conflict.ID)
+	if !confirmed {
+		return
+	}
+
+	d.metrics.IncObservationConflict(conflict.EmitterChain, true)
+	d.logger.Warn("Guardians signed a hash different from the signed vaa digest",
+		zap.String("id", conflict.ID),
+		zap.String("hash", signer.Hash),
+		zap.String("guardianAddr", signer.GuardianAddr),
+		zap.String("vaaDigest", digest))
+	alertContext := alert.AlertContext{
+		Details: conflict.ToMap(),
+	}
+	alertContext.Details["hash"] = signer.Hash
+	alertContext.Details["guardianAddr"] = signer.GuardianAddr
+	alertContext.Details["vaaDigest"] = digest
+	d.alert.CreateAndSend(ctx, flyAlert.ObservationConflict, alertContext)
+}
+
+// observe records the hash of an observation. It returns the first hash observed for the message,
+// whether this is the first conflicting observation, and false if the observation matches the first
+// hash of a message without conflict.
+func (d *ObservationConflictDetector) observe(messageID string, signer storage.ConflictingSigner) (ObservedHash, bool, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	value, found := d.cache.Get(messageID)
+	if !found {
+		d.set(messageID, ObservedHash{Hash: signer.Hash, GuardianAddr: signer.GuardianAddr})
+		return ObservedHash{}, false, false
+	}
+	seen := value.(ObservedHash)
+	if seen.Conflict {
+		return seen, false, true
+	}
+	if seen.Hash == signer.Hash {
+		return seen, false, false
+	}
+	seen.Conflict = true
+	d.set(messageID, seen)
+	return seen, true, true
+}
+
+// confirm records that the conflict of a message was confirmed.
+func (d *ObservationConflictDetector) confirm(messageID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if value, found := d.cache.Get(messageID); found {
+		seen := value.(ObservedHash)
+		seen.Confirmed = true
+		d.set(messageID, seen)
+	}
+}
+
+// set stores the hash of a message, waiting for the write to be visible to the next lookups.
+func (d *ObservationConflictDetector) set(messageID string, h ObservedHash) {
+	d.cache.SetWithTTL(messageID, h, 128, d.expiration)
+	d.cache.Wait()
+}
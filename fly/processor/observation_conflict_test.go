@@ -0,0 +1,320 @@
+package processor
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"testing"
+	"time"
+
+	gossipv1 "github.com/certusone/wormhole/node/pkg/proto/gossip/v1"
+	"github.com/dgraph-io/ristretto"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/metrics"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/storage"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+const testMessageID = "2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1"
+
+var (
+	hashA = hex.EncodeToString(eth_common.HexToHash("0xaa").Bytes())
+	hashB = hex.EncodeToString(eth_common.HexToHash("0xbb").Bytes())
+	hashC = hex.EncodeToString(eth_common.HexToHash("0xcc").Bytes())
+
+	guardian1 = eth_common.HexToAddress("0x01")
+	guardian2 = eth_common.HexToAddress("0x02")
+	guardian3 = eth_common.HexToAddress("0x03")
+)
+
+// fakeConflictRepository keeps the conflicting messages in memory.
+type fakeConflictRepository struct {
+	mu            sync.Mutex
+	digests       map[string]string
+	conflicts     map[string]*storage.ConflictingObservation
+	confirmed     map[string]string
+	digestLookups int
+}
+
+func newFakeConflictRepository() *fakeConflictRepository {
+	return &fakeConflictRepository{
+		digests:   make(map[string]string),
+		conflicts: make(map[string]*storage.ConflictingObservation),
+		confirmed: make(map[string]string),
+	}
+}
+
+func (r *fakeConflictRepository) FindObservationSigners(_ context.Context, _ sdk.ChainID, _, _ string) ([]storage.ConflictingSigner, error) {
+	return nil, nil
+}
+
+func (r *fakeConflictRepository) UpsertConflictingObservation(_ context.Context, c *storage.ConflictingObservationUpdate, signers []storage.ConflictingSigner) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conflict, found := r.conflicts[c.ID]
+	if !found {
+		conflict = &storage.ConflictingObservation{ID: c.ID, EmitterChain: c.EmitterChain, EmitterAddr: c.EmitterAddr, Sequence: c.Sequence}
+		r.conflicts[c.ID] = conflict
+	}
+	conflict.Signers = append(conflict.Signers, signers...)
+	return !found, nil
+}
+
+func (r *fakeConflictRepository) FindUnconfirmedConflictingObservations(_ context.Context, _ time.Time, _ int64) ([]storage.ConflictingObservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var conflicts []storage.ConflictingObservation
+	for id, c := range r.conflicts {
+		if _, ok := r.confirmed[id]; !ok {
+			conflicts = append(conflicts, *c)
+		}
+	}
+	return conflicts, nil
+}
+
+func (r *fakeConflictRepository) FindVaaDigest(_ context.Context, id string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.digestLookups++
+	return r.digests[id], nil
+}
+
+func (r *fakeConflictRepository) ConfirmConflictingObservation(_ context.Context, id string, digest string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.confirmed[id]; ok {
+		return false, nil
+	}
+	r.confirmed[id] = digest
+	return true, nil
+}
+
+// fakeAlertClient records the alerts sent.
+type fakeAlertClient struct {
+	alert.DummyClient
+	alerts []alert.AlertContext
+}
+
+func (c *fakeAlertClient) CreateAndSend(_ context.Context, _ string, alertCtx alert.AlertContext) error {
+	c.alerts = append(c.alerts, alertCtx)
+	return nil
+}
+
+func newTestConflictDetector(t *testing.T, repository conflictRepository, alertClient alert.AlertClient) *ObservationConflictDetector {
+	cache, err := ristretto.NewCache(&ristretto.Config{NumCounters: 1000, MaxCost: 1 << 20, BufferItems: 64})
+	require.NoError(t, err)
+	t.Cleanup(cache.Close)
+	return NewObservationConflictDetector(cache, time.Hour, repository, alertClient, metrics.NewDummyMetrics(), zap.NewNop())
+}
+
+func newTestObservation(guardian eth_common.Address, hash string) *gossipv1.SignedObservation {
+	h, _ := hex.DecodeString(hash)
+	return &gossipv1.SignedObservation{Addr: guardian.Bytes(), Hash: h, MessageId: testMessageID}
+}
+
+func TestObservationConflictDetector_Observe(t *testing.T) {
+	first := storage.ConflictingSigner{GuardianAddr: guardian1.String(), Hash: hashA}
+
+	tcs := []struct {
+		name          string
+		cached        *ObservedHash
+		signer        storage.ConflictingSigner
+		expectedSeen  ObservedHash
+		expectedFirst bool
+		expectedOk    bool
+		expectedCache ObservedHash
+	}{
+		{
+			name:          "first hash",
+			signer:        first,
+			expectedCache: ObservedHash{Hash: hashA, GuardianAddr: guardian1.String()},
+		},
+		{
+			name:          "same hash",
+			cached:        &ObservedHash{Hash: hashA, GuardianAddr: guardian1.String()},
+			signer:        storage.ConflictingSigner{GuardianAddr: guardian2.String(), Hash: hashA},
+			expectedSeen:  ObservedHash{Hash: hashA, GuardianAddr: guardian1.String()},
+			expectedCache: ObservedHash{Hash: hashA, GuardianAddr: guardian1.String()},
+		},
+		{
+			name:          "conflict",
+			cached:        &ObservedHash{Hash: hashA, GuardianAddr: guardian1.String()},
+			signer:        storage.ConflictingSigner{GuardianAddr: guardian2.String(), Hash: hashB},
+			expectedSeen:  ObservedHash{Hash: hashA, GuardianAddr: guardian1.String(), Conflict: true},
+			expectedFirst: true,
+			expectedOk:    true,
+			expectedCache: ObservedHash{Hash: hashA, GuardianAddr: guardian1.String(), Conflict: true},
+		},
+		{
+			name:          "already in conflict",
+			cached:        &ObservedHash{Hash: hashA, GuardianAddr: guardian1.String(), Conflict: true},
+			signer:        storage.ConflictingSigner{GuardianAddr: guardian3.String(), Hash: hashA},
+			expectedSeen:  ObservedHash{Hash: hashA, GuardianAddr: guardian1.String(), Conflict: true},
+			expectedOk:    true,
+			expectedCache: ObservedHash{Hash: hashA, GuardianAddr: guardian1.String(), Conflict: true},
+		},
+		{
+			name:          "already confirmed",
+			cached:        &ObservedHash{Hash: hashA, GuardianAddr: guardian1.String(), Conflict: true, Confirmed: true},
+			signer:        storage.ConflictingSigner{GuardianAddr: guardian3.String(), Hash: hashC},
+			expectedSeen:  ObservedHash{Hash: hashA, GuardianAddr: guardian1.String(), Conflict: true, Confirmed: true},
+			expectedOk:    true,
+			expectedCache: ObservedHash{Hash: hashA, GuardianAddr: guardian1.String(), Conflict: true, Confirmed: true},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			d := newTestConflictDetector(t, newFakeConflictRepository(), alert.NewDummyClient())
+			if tc.cached != nil {
+				d.set(testMessageID, *tc.cached)
+			}
+
+			seen, first, ok := d.observe(testMessageID, tc.signer)
+			assert.Equal(t, tc.expectedSeen, seen)
+			assert.Equal(t, tc.expectedFirst, first)
+			assert.Equal(t, tc.expectedOk, ok)
+
+			cached, found := d.cache.Get(testMessageID)
+			require.True(t, found)
+			assert.Equal(t, tc.expectedCache, cached)
+		})
+	}
+}
+
+func TestObservationConflictDetector_Check(t *testing.T) {
+	tcs := []struct {
+		name string
+		// digest is the digest of the stored vaa, empty if the vaa is not stored.
+		digest               string
+		observations         []*gossipv1.SignedObservation
+		expectedConflict     bool
+		expectedConfirmed    bool
+		expectedGuardianAddr string
+		expectedHash         string
+	}{
+		{
+			name:         "same hash",
+			digest:       hashA,
+			observations: []*gossipv1.SignedObservation{newTestObservation(guardian1, hashA), newTestObservation(guardian2, hashA)},
+		},
+		{
+			name:             "conflict without vaa",
+			observations:     []*gossipv1.SignedObservation{newTestObservation(guardian1, hashA), newTestObservation(guardian2, hashB)},
+			expectedConflict: true,
+		},
+		{
+			name:                 "last hash differs from the digest",
+			digest:               hashA,
+			observations:         []*gossipv1.SignedObservation{newTestObservation(guardian1, hashA), newTestObservation(guardian2, hashB)},
+			expectedConflict:     true,
+			expectedConfirmed:    true,
+			expectedGuardianAddr: guardian2.String(),
+			expectedHash:         hashB,
+		},
+		{
+			name:                 "first hash differs from the digest",
+			digest:               "0x" + hashB,
+			observations:         []*gossipv1.SignedObservation{newTestObservation(guardian1, hashA), newTestObservation(guardian2, hashB)},
+			expectedConflict:     true,
+			expectedConfirmed:    true,
+			expectedGuardianAddr: guardian1.String(),
+			expectedHash:         hashA,
+		},
+		{
+			name:                 "both hashes differ from the digest",
+			digest:               hashC,
+			observations:         []*gossipv1.SignedObservation{newTestObservation(guardian1, hashA), newTestObservation(guardian2, hashB)},
+			expectedConflict:     true,
+			expectedConfirmed:    true,
+			expectedGuardianAddr: guardian2.String(),
+			expectedHash:         hashB,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := newFakeConflictRepository()
+			if tc.digest != "" {
+				repository.digests[testMessageID] = tc.digest
+			}
+			alertClient := &fakeAlertClient{}
+			d := newTestConflictDetector(t, repository, alertClient)
+
+			for _, o := range tc.observations {
+				d.Check(context.Background(), sdk.ChainIDEthereum, o)
+			}
+
+			_, conflict := repository.conflicts[testMessageID]
+			assert.Equal(t, tc.expectedConflict, conflict)
+			_, confirmed := repository.confirmed[testMessageID]
+			assert.Equal(t, tc.expectedConfirmed, confirmed)
+			if !tc.expectedConfirmed {
+				assert.Empty(t, alertClient.alerts)
+				return
+			}
+			require.Len(t, alertClient.alerts, 1)
+			assert.Equal(t, tc.expectedGuardianAddr, alertClient.alerts[0].Details["guardianAddr"])
+			assert.Equal(t, tc.expectedHash, alertClient.alerts[0].Details["hash"])
+			assert.Equal(t, testMessageID, alertClient.alerts[0].Details["messageId"])
+		})
+	}
+}
+
+func TestObservationConflictDetector_CheckConfirmed(t *testing.T) {
+	repository := newFakeConflictRepository()
+	repository.digests[testMessageID] = hashA
+	alertClient := &fakeAlertClient{}
+	d := newTestConflictDetector(t, repository, alertClient)
+
+	d.Check(context.Background(), sdk.ChainIDEthereum, newTestObservation(guardian1, hashA))
+	d.Check(context.Background(), sdk.ChainIDEthereum, newTestObservation(guardian2, hashB))
+	require.Len(t, alertClient.alerts, 1)
+	lookups := repository.digestLookups
+
+	// the digest is not looked up again once the conflict is confirmed.
+	d.Check(context.Background(), sdk.ChainIDEthereum, newTestObservation(guardian3, hashC))
+	assert.Equal(t, lookups, repository.digestLookups)
+	assert.Len(t, alertClient.alerts, 1)
+}
+
+func TestObservationConflictDetector_CheckPythNet(t *testing.T) {
+	repository := newFakeConflictRepository()
+	d := newTestConflictDetector(t, repository, alert.NewDummyClient())
+
+	d.Check(context.Background(), sdk.ChainIDPythNet, newTestObservation(guardian1, hashA))
+	d.Check(context.Background(), sdk.ChainIDPythNet, newTestObservation(guardian2, hashB))
+	assert.Empty(t, repository.conflicts)
+}
+
+func TestObservationConflictDetector_ConfirmPending(t *testing.T) {
+	ctx := context.Background()
+	repository := newFakeConflictRepository()
+	alertClient := &fakeAlertClient{}
+	d := newTestConflictDetector(t, repository, alertClient)
+
+	// the conflict is recorded before the vaa is stored.
+	d.Check(ctx, sdk.ChainIDEthereum, newTestObservation(guardian1, hashA))
+	d.Check(ctx, sdk.ChainIDEthereum, newTestObservation(guardian2, hashB))
+	d.confirmPending(ctx)
+	assert.Empty(t, repository.confirmed)
+	assert.Empty(t, alertClient.alerts)
+
+	// the conflict is confirmed once the vaa is stored, without a new observation.
+	repository.digests[testMessageID] = hashB
+	d.confirmPending(ctx)
+	assert.Equal(t, hashB, repository.confirmed[testMessageID])
+	require.Len(t, alertClient.alerts, 1)
+	assert.Equal(t, guardian1.String(), alertClient.alerts[0].Details["guardianAddr"])
+	assert.Equal(t, hashA, alertClient.alerts[0].Details["hash"])
+	assert.Equal(t, hashB, alertClient.alerts[0].Details["vaaDigest"])
+
+	// the confirmed conflict is not alerted again.
+	d.confirmPending(ctx)
+	d.Check(ctx, sdk.ChainIDEthereum, newTestObservation(guardian3, hashC))
+	assert.Len(t, alertClient.alerts, 1)
+}
@@ -28,6 +28,7 @@ type observationGossipConsumer struct {
 	wgBlock            sync.WaitGroup
 	txHashStore        txhash.TxHashStore
 	repository         *storage.Repository
+	conflictDetector   *ObservationConflictDetector
 	logger             *zap.Logger
 }
 
@@ -41,6 +42,7 @@ func NewObservationGossipConsumer(
 	metrics metrics.Metrics,
 	txHashStore txhash.TxHashStore,
 	repository *storage.Repository,
+	conflictDetector *ObservationConflictDetector,
 	logger *zap.Logger,
 ) *observationGossipConsumer {
 	return &observationGossipConsumer{
@@ -51,6 +53,7 @@ func NewObservationGossipConsumer(
 		metrics:            metrics,
 		txHashStore:        txHashStore,
 		repository:         repository,
+		conflictDetector:   conflictDetector,
 		logger:             logger,
 		signedObsCh:        make(chan *gossipv1.SignedObservation, channelSize),
 	}
@@ -124,6 +127,9 @@ func (c *observationGossipConsumer) process(ctx context.Context, o *gossipv1.Sig
 
 	c.metrics.IncObservationUnfiltered(chainID)
 
+	// the conflicts are checked by the worker, so that the observations of a message are compared in order.
+	c.conflictDetector.Check(ctx, chainID, o)
+
 	go func(consumer *observationGossipConsumer, ctx context.Context, obs *gossipv1.SignedObservation) {
 		err = consumer.txHashStore.SetObservation(ctx, obs)
 		if err != nil {
@@ -5,15 +5,19 @@ import (
 	"fmt"
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/deduplicator"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/guardiansets"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/storage"
 
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+var tracer = telemetry.Tracer("github.com/wormhole-foundation/wormhole-explorer/fly/processor")
+
 type vaaGossipConsumer struct {
 	guardianSetHistory *guardiansets.GuardianSetHistory
 	nonPythProcess     VAAPushFunc
@@ -66,9 +70,14 @@ func (p *vaaGossipConsumer) Push(ctx context.Context, v *vaa.VAA, serializedVaa
 			return p.pythProcess(ctx, v, serializedVaa)
 		})
 	} else {
-		err = p.nonPythDedup.Apply(ctx, key, func() error {
+		err = p.nonPythDedup.Apply(ctx, key, func() (pErr error) {
+			// the trace of a vaa starts with its first appearance in the gossip network.
+			// pyth vaas are not traced because of their volume.
+			ctx, span := tracer.Start(ctx, "fly.gossip.vaa", trace.WithAttributes(telemetry.VaaIDKey.String(v.MessageID())))
+			defer func() { telemetry.End(span, pErr) }()
+
 			p.metrics.IncVaaUnfiltered(v.EmitterChain)
-			pErr := p.nonPythProcess(ctx, v, serializedVaa)
+			pErr = p.nonPythProcess(ctx, v, serializedVaa)
 			if pErr != nil {
 				p.logger.Error("Error processing vaa", zap.String("id", uniqueVaaID), zap.Error(err))
 				// This is the fallback to store the vaa in the repository.
@@ -2,11 +2,15 @@ package processor
 
 import (
 	"context"
+	"errors"
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/metrics"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/queue"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/storage"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.opentelemetry.io/otel/trace"
 
 	"go.uber.org/zap"
 )
@@ -40,90 +44,108 @@ func NewVAAQueueConsumer(
 func (c *VAAQueueConsumer) Start(ctx context.Context) {
 	go func() {
 		for msg := range c.consume(ctx) {
-			v, err := sdk.Unmarshal(msg.Data())
-			if err != nil {
-				c.logger.Error("Error unmarshalling vaa", zap.Error(err))
-				msg.Failed()
-				continue
-			}
+			c.process(msg)
+		}
+	}()
+}
 
-			if msg.IsExpired() {
-				c.logger.Warn("Message with vaa expired", zap.String("id", v.MessageID()))
-				msg.Failed()
-				continue
-			}
+// process stores a VAA and notifies it. The span of the message continues the trace started in the gossip consumer.
+func (c *VAAQueueConsumer) process(msg queue.Message[[]byte]) {
+	v, err := sdk.Unmarshal(msg.Data())
+	if err != nil {
+		c.logger.Error("Error unmarshalling vaa", zap.Error(err))
+		msg.Failed()
+		return
+	}
 
-			c.metrics.IncVaaConsumedFromQueue(v.EmitterChain)
+	ctx, span := tracer.Start(msg.Context(), "fly.vaa.process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(telemetry.VaaIDKey.String(v.MessageID())))
+	var pErr error
+	defer func() { telemetry.End(span, pErr) }()
 
-			c.metrics.IncConsistencyLevelByChainID(v.EmitterChain, v.ConsistencyLevel)
+	if msg.IsExpired() {
+		c.logger.Warn("Message with vaa expired", zap.String("id", v.MessageID()))
+		pErr = errors.New("message expired")
+		msg.Failed()
+		return
+	}
 
-			if v.EmitterChain != sdk.ChainIDPythNet && domain.ConsistencyLevelIsImmediately(v) {
-				dbVaa, err := c.repository.FindVaaByID(ctx, v.MessageID())
-				if err != nil {
-					c.logger.Error("Error finding vaa in repository",
-						zap.String("id", v.MessageID()),
-						zap.Error(err))
-					msg.Failed()
-					continue
-				}
-				if dbVaa == nil {
-					err = c.repository.UpsertVaa(ctx, v, msg.Data())
-					if err != nil {
-						c.logger.Error("Error inserting vaa in repository",
-							zap.String("id", v.MessageID()),
-							zap.Error(err))
-						msg.Failed()
-						continue
-					}
-				} else {
-					existingVaa, err := sdk.Unmarshal(dbVaa.Vaa)
-					if err != nil {
-						c.logger.Error("Error unmarshalling found vaa", zap.Error(err), zap.String("id", v.MessageID()))
-						msg.Failed()
-						continue
-					}
-					currentHash := v.SigningDigest()
-					savedHash := existingVaa.SigningDigest()
-					// if the hash is the same, we can skip the vaa
-					if currentHash.Hex() == savedHash.Hex() {
-						msg.Done(ctx)
-						continue
-					}
-					//put as dirty the vaa and save it in duplicatedVaas
-					err = c.repository.UpsertDuplicateVaa(ctx, v, msg.Data())
-					if err != nil {
-						c.logger.Error("Error inserting duplicate vaa in repository",
-							zap.String("id", v.MessageID()),
-							zap.Error(err))
-						msg.Failed()
-						continue
-					}
-					c.metrics.IncDuplicateVaaByChainID(v.EmitterChain)
-				}
+	c.metrics.IncVaaConsumedFromQueue(v.EmitterChain)
 
-			} else {
-				err = c.repository.UpsertVaa(ctx, v, msg.Data())
-				if err != nil {
-					c.logger.Error("Error inserting vaa in repository",
-						zap.String("id", v.MessageID()),
-						zap.Error(err))
-					msg.Failed()
-					continue
-				}
-			}
+	c.metrics.IncConsistencyLevelByChainID(v.EmitterChain, v.ConsistencyLevel)
 
-			err = c.notifyFunc(ctx, v, msg.Data())
+	if v.EmitterChain != sdk.ChainIDPythNet && domain.ConsistencyLevelIsImmediately(v) {
+		dbVaa, err := c.repository.FindVaaByID(ctx, v.MessageID())
+		if err != nil {
+			c.logger.Error("Error finding vaa in repository",
+				zap.String("id", v.MessageID()),
+				zap.Error(err))
+			pErr = err
+			msg.Failed()
+			return
+		}
+		if dbVaa == nil {
+			err = c.repository.UpsertVaa(ctx, v, msg.Data())
 			if err != nil {
-				c.metrics.IncMaxSequenceCacheError(v.EmitterChain)
-				c.logger.Error("Error notifying vaa",
+				c.logger.Error("Error inserting vaa in repository",
 					zap.String("id", v.MessageID()),
 					zap.Error(err))
+				pErr = err
 				msg.Failed()
-				continue
+				return
 			}
-			c.metrics.VaaProcessingDuration(v.EmitterChain, msg.SentTimestamp())
-			msg.Done(ctx)
-			c.logger.Info("Vaa saved in repository", zap.String("id", v.MessageID()))
+		} else {
+			existingVaa, err := sdk.Unmarshal(dbVaa.Vaa)
+			if err != nil {
+				c.logger.Error("Error unmarshalling found vaa", zap.Error(err), zap.String("id", v.MessageID()))
+				pErr = err
+				msg.Failed()
+				return
+			}
+			currentHash := v.SigningDigest()
+			savedHash := existingVaa.SigningDigest()
+			// if the hash is the same, we can skip the vaa
+			if currentHash.Hex() == savedHash.Hex() {
+				msg.Done(ctx)
+				return
+			}
+			//put as dirty the vaa and save it in duplicatedVaas
+			err = c.repository.UpsertDuplicateVaa(ctx, v, msg.Data())
+			if err != nil {
+				c.logger.Error("Error inserting duplicate vaa in repository",
+					zap.String("id", v.MessageID()),
+					zap.Error(err))
+				pErr = err
+				msg.Failed()
+				return
+			}
+			c.metrics.IncDuplicateVaaByChainID(v.EmitterChain)
 		}
-	}()
+
+	} else {
+		err = c.repository.UpsertVaa(ctx, v, msg.Data())
+		if err != nil {
+			c.logger.Error("Error inserting vaa in repository",
+				zap.String("id", v.MessageID()),
+				zap.Error(err))
+			pErr = err
+			msg.Failed()
+			return
+		}
+	}
+
+	err = c.notifyFunc(ctx, v, msg.Data())
+	if err != nil {
+		c.metrics.IncMaxSequenceCacheError(v.EmitterChain)
+		c.logger.Error("Error notifying vaa",
+			zap.String("id", v.MessageID()),
+			zap.Error(err))
+		pErr = err
+		msg.Failed()
+		return
+	}
+	c.metrics.VaaProcessingDuration(v.EmitterChain, msg.SentTimestamp())
+	msg.Done(ctx)
+	c.logger.Info("Vaa saved in repository", zap.String("id", v.MessageID()))
 }
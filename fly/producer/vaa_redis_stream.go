@@ -0,0 +1,29 @@
+package producer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
+)
+
+// RedisStreamProducer represents a redis stream producer.
+type RedisStreamProducer struct {
+	producer *redisstream.Producer
+}
+
+// NewRedisStreamProducer returns a producer that pushes NotificationEvent to a redis stream.
+func NewRedisStreamProducer(producer *redisstream.Producer) *RedisStreamProducer {
+	return &RedisStreamProducer{
+		producer: producer,
+	}
+}
+
+// Push pushes a NotificationEvent to a redis stream.
+func (p *RedisStreamProducer) Push(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n.Event)
+	if err != nil {
+		return err
+	}
+	return p.producer.SendMessage(ctx, string(body))
+}
@@ -43,6 +43,10 @@ func (m *sqsConsumerMessage[T]) SentTimestamp() *time.Time {
 	return m.sentTimestamp
 }
 
+func (m *sqsConsumerMessage[T]) Context() context.Context {
+	return m.ctx
+}
+
 type memoryConsumerMessageQueue[T any] struct {
 	data T
 }
@@ -62,3 +66,7 @@ func (m *memoryConsumerMessageQueue[T]) IsExpired() bool {
 func (m *memoryConsumerMessageQueue[T]) SentTimestamp() *time.Time {
 	return nil
 }
+
+func (m *memoryConsumerMessageQueue[T]) Context() context.Context {
+	return context.Background()
+}
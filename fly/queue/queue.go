@@ -12,6 +12,8 @@ type Message[T any] interface {
 	Failed()
 	IsExpired() bool
 	SentTimestamp() *time.Time
+	// Context returns the context of the message, it carries the trace context of the publisher.
+	Context() context.Context
 }
 
 // Observation represents a signed observation.
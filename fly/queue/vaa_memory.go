@@ -34,9 +34,10 @@ func WithSize(v int) VAAInMemoryOption {
 }
 
 // Publish sends the message to a channel.
-func (i *VAAInMemory) Publish(_ context.Context, v *vaa.VAA, data []byte) error {
+func (i *VAAInMemory) Publish(ctx context.Context, v *vaa.VAA, data []byte) error {
 	i.ch <- &memoryConsumerMessage{
 		data: data,
+		ctx:  context.WithoutCancel(ctx),
 	}
 	return nil
 }
@@ -48,6 +49,7 @@ func (i *VAAInMemory) Consume(_ context.Context) <-chan Message[[]byte] {
 
 type memoryConsumerMessage struct {
 	data []byte
+	ctx  context.Context
 }
 
 func (m *memoryConsumerMessage) Data() []byte {
@@ -65,3 +67,7 @@ func (m *memoryConsumerMessage) IsExpired() bool {
 func (m *memoryConsumerMessage) SentTimestamp() *time.Time {
 	return nil
 }
+
+func (m *memoryConsumerMessage) Context() context.Context {
+	return m.ctx
+}
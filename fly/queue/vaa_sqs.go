@@ -8,6 +8,7 @@ import (
 
 	common_sqs "github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/sqs"
 
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -81,7 +82,7 @@ func (q *VAASqs) Consume(ctx context.Context) <-chan Message[[]byte] {
 					logger:        q.logger,
 					consumer:      q.consumer,
 					expiredAt:     expiredAt,
-					ctx:           ctx,
+					ctx:           telemetry.ExtractSQSAttributes(ctx, msg.MessageAttributes),
 					sentTimestamp: common_sqs.GetSentTimestamp(msg),
 				}
 			}
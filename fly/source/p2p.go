@@ -0,0 +1,82 @@
+package source
+
+import (
+	"context"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	"github.com/certusone/wormhole/node/pkg/p2p"
+	"github.com/certusone/wormhole/node/pkg/supervisor"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/config"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/gossip"
+	"go.uber.org/zap"
+)
+
+// P2PSource joins the libp2p gossip network with its own p2p key.
+type P2PSource struct {
+	networkConfig *config.P2pNetworkConfig
+	port          uint
+	nodeKeyPath   string
+	gst           *common.GuardianSetState
+	cancel        context.CancelFunc
+	logger        *zap.Logger
+}
+
+// NewP2PSource creates a new p2p source. The cancel function is called when the p2p node fails.
+func NewP2PSource(networkConfig *config.P2pNetworkConfig, port uint, nodeKeyPath string, gst *common.GuardianSetState,
+	cancel context.CancelFunc, logger *zap.Logger) *P2PSource {
+	return &P2PSource{
+		networkConfig: networkConfig,
+		port:          port,
+		nodeKeyPath:   nodeKeyPath,
+		gst:           gst,
+		cancel:        cancel,
+		logger:        logger,
+	}
+}
+
+// Start runs the p2p node under a supervisor.
+func (s *P2PSource) Start(ctx context.Context, channels *gossip.GossipChannels) error {
+	// Load p2p private key
+	priv, err := common.GetOrCreateNodeKey(s.logger, s.nodeKeyPath)
+	if err != nil {
+		return err
+	}
+
+	components := p2p.DefaultComponents()
+	components.Port = s.port
+	components.WarnChannelOverflow = true
+
+	runParams, err := p2p.NewRunParams(
+		s.networkConfig.P2pBootstrap,
+		s.networkConfig.P2pNetworkID,
+		priv,
+		s.gst,
+		s.cancel,
+		p2p.WithSignedObservationListener(channels.ObsvChannel),
+		p2p.WithSignedObservationBatchListener(channels.BatchObsvC),
+		p2p.WithSignedVAAListener(channels.SignedInChannel),
+		p2p.WithObservationRequestListener(channels.ObsvReqChannel),
+		p2p.WithChainGovernorConfigListener(channels.GovConfigChannel),
+		p2p.WithChainGovernorStatusListener(channels.GovStatusChannel),
+		p2p.WithComponents(components),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Run supervisor.
+	supervisor.New(ctx, s.logger, func(ctx context.Context) error {
+		if err := supervisor.Run(ctx, "p2p", p2p.Run(runParams)); err != nil {
+			return err
+		}
+
+		s.logger.Info("Started internal services")
+
+		<-ctx.Done()
+		return nil
+	},
+		// It's safer to crash and restart the process in case we encounter a panic,
+		// rather than attempting to reschedule the runnable.
+		supervisor.WithPropagatePanic)
+	return nil
+}
@@ -0,0 +1,155 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	gossipv1 "github.com/certusone/wormhole/node/pkg/proto/gossip/v1"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/gossip"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxReplayLineSize is the maximum size of a line of a replay file.
+const maxReplayLineSize = 4 * 1024 * 1024
+
+// replay message types.
+const (
+	replayTypeVaa              = "vaa"
+	replayTypeObservation      = "observation"
+	replayTypeObservationBatch = "observation_batch"
+	replayTypeHeartbeat        = "heartbeat"
+	replayTypeGovernorConfig   = "governor_config"
+	replayTypeGovernorStatus   = "governor_status"
+)
+
+// replayLine is a line of a replay file. Message is the protojson encoding of the gossip message.
+type replayLine struct {
+	Type    string          `json:"type"`
+	Message json.RawMessage `json:"message"`
+}
+
+// ReplaySource reads the gossip messages from a JSON lines file.
+//
+// Each line has the message type (vaa, observation, observation_batch, heartbeat, governor_config
+// or governor_status) and the message encoded with protojson, for example:
+//
+//	{"type":"vaa","message":{"vaa":"AQAAAAMN..."}}
+type ReplaySource struct {
+	path     string
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// NewReplaySource creates a new replay source. The interval is the time to wait between messages.
+func NewReplaySource(path string, interval time.Duration, logger *zap.Logger) *ReplaySource {
+	return &ReplaySource{
+		path:     path,
+		interval: interval,
+		logger:   logger.With(zap.String("source", string(Replay)), zap.String("path", path)),
+	}
+}
+
+// Start reads the file and pushes its messages into the channels.
+func (s *ReplaySource) Start(ctx context.Context, channels *gossip.GossipChannels) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer f.Close()
+		count, err := s.replay(ctx, f, channels)
+		if err != nil {
+			s.logger.Error("Error replaying messages", zap.Int("count", count), zap.Error(err))
+			return
+		}
+		s.logger.Info("Finished replaying messages", zap.Int("count", count))
+	}()
+	return nil
+}
+
+func (s *ReplaySource) replay(ctx context.Context, f *os.File, channels *gossip.GossipChannels) (int, error) {
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxReplayLineSize)
+
+	var count, lineNumber int
+	for scanner.Scan() {
+		lineNumber++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := push(ctx, scanner.Bytes(), channels); err != nil {
+			if ctx.Err() != nil {
+				return count, ctx.Err()
+			}
+			s.logger.Warn("Skipping invalid replay line", zap.Int("line", lineNumber), zap.Error(err))
+			continue
+		}
+		count++
+
+		if s.interval > 0 {
+			select {
+			case <-ctx.Done():
+				return count, ctx.Err()
+			case <-time.After(s.interval):
+			}
+		}
+	}
+	return count, scanner.Err()
+}
+
+// push decodes a replay line and sends the message to its channel.
+func push(ctx context.Context, data []byte, channels *gossip.GossipChannels) error {
+	var line replayLine
+	if err := json.Unmarshal(data, &line); err != nil {
+		return err
+	}
+
+	switch line.Type {
+	case replayTypeVaa:
+		return decodeAndSend(ctx, line.Message, channels.SignedInChannel, &gossipv1.SignedVAAWithQuorum{})
+	case replayTypeObservation:
+		var o gossipv1.SignedObservation
+		if err := protojson.Unmarshal(line.Message, &o); err != nil {
+			return err
+		}
+		return send(ctx, channels.ObsvChannel, common.CreateMsgWithTimestamp(&o))
+	case replayTypeObservationBatch:
+		var b gossipv1.SignedObservationBatch
+		if err := protojson.Unmarshal(line.Message, &b); err != nil {
+			return err
+		}
+		return send(ctx, channels.BatchObsvC, common.CreateMsgWithTimestamp(&b))
+	case replayTypeHeartbeat:
+		return decodeAndSend(ctx, line.Message, channels.HeartbeatChannel, &gossipv1.Heartbeat{})
+	case replayTypeGovernorConfig:
+		return decodeAndSend(ctx, line.Message, channels.GovConfigChannel, &gossipv1.SignedChainGovernorConfig{})
+	case replayTypeGovernorStatus:
+		return decodeAndSend(ctx, line.Message, channels.GovStatusChannel, &gossipv1.SignedChainGovernorStatus{})
+	default:
+		return fmt.Errorf("unknown message type %s", line.Type)
+	}
+}
+
+func decodeAndSend[T proto.Message](ctx context.Context, data []byte, c chan T, m T) error {
+	if err := protojson.Unmarshal(data, m); err != nil {
+		return err
+	}
+	return send(ctx, c, m)
+}
+
+func send[T any](ctx context.Context, c chan T, m T) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case c <- m:
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	gossipv1 "github.com/certusone/wormhole/node/pkg/proto/gossip/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/gossip"
+	"go.uber.org/zap"
+)
+
+func newTestChannels() *gossip.GossipChannels {
+	return &gossip.GossipChannels{
+		ObsvChannel:      make(chan *common.MsgWithTimeStamp[gossipv1.SignedObservation], 10),
+		BatchObsvC:       make(chan *common.MsgWithTimeStamp[gossipv1.SignedObservationBatch], 10),
+		ObsvReqChannel:   make(chan *gossipv1.ObservationRequest, 10),
+		SignedInChannel:  make(chan *gossipv1.SignedVAAWithQuorum, 10),
+		HeartbeatChannel: make(chan *gossipv1.Heartbeat, 10),
+		GovConfigChannel: make(chan *gossipv1.SignedChainGovernorConfig, 10),
+		GovStatusChannel: make(chan *gossipv1.SignedChainGovernorStatus, 10),
+	}
+}
+
+func TestReplaySource(t *testing.T) {
+	lines := `{"type":"vaa","message":{"vaa":"AQID"}}
+{"type":"observation","message":{"messageId":"2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1"}}
+{"type":"unknown","message":{}}
+not json
+
+{"type":"heartbeat","message":{"nodeName":"guardian-0"}}
+`
+	path := filepath.Join(t.TempDir(), "replay.jsonl")
+	assert.NoError(t, os.WriteFile(path, []byte(lines), 0o600))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	channels := newTestChannels()
+	source := NewReplaySource(path, 0, zap.NewNop())
+	assert.NoError(t, source.Start(ctx, channels))
+
+	select {
+	case v := <-channels.SignedInChannel:
+		assert.Equal(t, []byte{1, 2, 3}, v.Vaa)
+	case <-ctx.Done():
+		t.Fatal("vaa not replayed")
+	}
+	select {
+	case o := <-channels.ObsvChannel:
+		assert.Equal(t, "2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1", o.Msg.MessageId)
+	case <-ctx.Done():
+		t.Fatal("observation not replayed")
+	}
+	select {
+	case hb := <-channels.HeartbeatChannel:
+		assert.Equal(t, "guardian-0", hb.NodeName)
+	case <-ctx.Done():
+		t.Fatal("heartbeat not replayed")
+	}
+}
+
+func TestReplaySource_FileNotFound(t *testing.T) {
+	source := NewReplaySource(filepath.Join(t.TempDir(), "missing.jsonl"), 0, zap.NewNop())
+	assert.Error(t, source.Start(context.Background(), newTestChannels()))
+}
+
+func TestParseType(t *testing.T) {
+	typ, err := ParseType("spy")
+	assert.NoError(t, err)
+	assert.Equal(t, Spy, typ)
+	_, err = ParseType("udp")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+// Package source defines where fly ingests the messages of the wormhole network from.
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wormhole-foundation/wormhole-explorer/fly/gossip"
+)
+
+// Type is the kind of ingestion source.
+type Type string
+
+const (
+	// P2P joins the libp2p gossip network.
+	P2P Type = "p2p"
+	// Spy subscribes to the signed VAAs of a guardian spy.
+	Spy Type = "spy"
+	// Replay reads the messages from a file.
+	Replay Type = "replay"
+)
+
+// ParseType parses an ingestion source type.
+func ParseType(s string) (Type, error) {
+	switch t := Type(s); t {
+	case P2P, Spy, Replay:
+		return t, nil
+	default:
+		return "", fmt.Errorf("invalid ingestion source %s", s)
+	}
+}
+
+// Source feeds the gossip channels that are read by the fly handlers.
+type Source interface {
+	// Start starts pushing messages into the channels, it does not block.
+	Start(ctx context.Context, channels *gossip.GossipChannels) error
+}
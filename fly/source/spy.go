@@ -0,0 +1,76 @@
+package source
+
+import (
+	"context"
+	"time"
+
+	gossipv1 "github.com/certusone/wormhole/node/pkg/proto/gossip/v1"
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"github.com/wormhole-foundation/wormhole-explorer/fly/gossip"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// SpySource subscribes to the signed VAAs stream of a guardian spy.
+// A spy only streams signed VAAs, observations and heartbeats are not available with this source.
+type SpySource struct {
+	addr         string
+	retryBackoff time.Duration
+	logger       *zap.Logger
+}
+
+// NewSpySource creates a new spy source for the spy gRPC address.
+func NewSpySource(addr string, retryBackoff time.Duration, logger *zap.Logger) *SpySource {
+	return &SpySource{
+		addr:         addr,
+		retryBackoff: retryBackoff,
+		logger:       logger.With(zap.String("source", string(Spy)), zap.String("addr", addr)),
+	}
+}
+
+// Start connects to the spy and subscribes to the signed VAAs, the subscription is
+// restarted when the stream fails.
+func (s *SpySource) Start(ctx context.Context, channels *gossip.GossipChannels) error {
+	conn, err := grpc.DialContext(ctx, s.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	client := spyv1.NewSpyRPCServiceClient(conn)
+
+	go func() {
+		defer conn.Close()
+		for {
+			err := s.subscribe(ctx, client, channels.SignedInChannel)
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Warn("spy subscription finished, retrying", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.retryBackoff):
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *SpySource) subscribe(ctx context.Context, client spyv1.SpyRPCServiceClient, signedInC chan<- *gossipv1.SignedVAAWithQuorum) error {
+	stream, err := client.SubscribeSignedVAA(ctx, &spyv1.SubscribeSignedVAARequest{})
+	if err != nil {
+		return err
+	}
+	s.logger.Info("subscribed to spy signed vaas")
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case signedInC <- &gossipv1.SignedVAAWithQuorum{Vaa: resp.VaaBytes}:
+		}
+	}
+}
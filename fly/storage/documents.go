@@ -100,12 +100,64 @@ func (v *ObservationUpdate) ToMap() map[string]string {
 	}
 }
 
+// ConflictingSigner is a guardian that signed a hash of a message.
+type ConflictingSigner struct {
+	GuardianAddr string `bson:"guardianAddr"`
+	Hash         string `bson:"hash"`
+}
+
+// ConflictingObservationUpdate is a message whose observations carry different hashes.
+type ConflictingObservationUpdate struct {
+	ID           string      `bson:"_id"`
+	EmitterChain vaa.ChainID `bson:"emitterChain"`
+	EmitterAddr  string      `bson:"emitterAddr"`
+	Sequence     string      `bson:"sequence"`
+	UpdatedAt    *time.Time  `bson:"updatedAt"`
+}
+
+// ConflictingObservation is a conflicting message with the guardians and hashes that signed it.
+type ConflictingObservation struct {
+	ID           string              `bson:"_id"`
+	EmitterChain vaa.ChainID         `bson:"emitterChain"`
+	EmitterAddr  string              `bson:"emitterAddr"`
+	Sequence     string              `bson:"sequence"`
+	Signers      []ConflictingSigner `bson:"signers"`
+}
+
+// ConflictStatus tells whether the hashes of a conflicting message were compared with its signed VAA.
+type ConflictStatus string
+
+const (
+	// ConflictUnconfirmed is a message observed with different hashes that has no signed VAA yet,
+	// the hashes can not be bound to the message.
+	ConflictUnconfirmed ConflictStatus = "unconfirmed"
+	// ConflictConfirmed is a message whose signed VAA digest differs from a hash signed by a guardian.
+	ConflictConfirmed ConflictStatus = "confirmed"
+)
+
+// ToMap returns a map representation of the ConflictingObservationUpdate.
+func (c *ConflictingObservationUpdate) ToMap() map[string]string {
+	return map[string]string{
+		"messageId":    c.ID,
+		"emitterChain": c.EmitterChain.String(),
+		"emitterAddr":  c.EmitterAddr,
+		"sequence":     c.Sequence,
+	}
+}
+
 func indexedAt(t time.Time) IndexingTimestamps {
 	return IndexingTimestamps{
 		IndexedAt: t,
 	}
 }
 
+// VaaInsertFields are the fields of a vaa document that are set only when it is inserted.
+type VaaInsertFields struct {
+	IndexedAt time.Time `bson:"indexedAt"`
+	// TraceContext is the trace context of the insertion, the pipeline continues the trace from it.
+	TraceContext map[string]string `bson:"traceContext,omitempty"`
+}
+
 // MongoStatus represent a mongo server status.
 type MongoStatus struct {
 	Ok          int32             `bson:"ok"`
@@ -14,6 +14,7 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	"github.com/wormhole-foundation/wormhole-explorer/common/events"
 	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/event"
 	flyAlert "github.com/wormhole-foundation/wormhole-explorer/fly/internal/alert"
@@ -25,6 +26,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 )
@@ -47,6 +49,7 @@ type Repository struct {
 		vaasPythnet    *mongo.Collection
 		vaaCounts      *mongo.Collection
 		duplicateVaas  *mongo.Collection
+		conflicts      *mongo.Collection
 	}
 }
 
@@ -66,6 +69,7 @@ func NewRepository(alertService alert.AlertClient, metrics metrics.Metrics,
 		vaasPythnet    *mongo.Collection
 		vaaCounts      *mongo.Collection
 		duplicateVaas  *mongo.Collection
+		conflicts      *mongo.Collection
 	}{
 		vaas:           db.Collection(repository.Vaas),
 		heartbeats:     db.Collection("heartbeats"),
@@ -74,7 +78,8 @@ func NewRepository(alertService alert.AlertClient, metrics metrics.Metrics,
 		governorStatus: db.Collection("governorStatus"),
 		vaasPythnet:    db.Collection("vaasPythnet"),
 		vaaCounts:      db.Collection("vaaCounts"),
-		duplicateVaas:  db.Collection(repository.DuplicateVaas)}}
+		duplicateVaas:  db.Collection(repository.DuplicateVaas),
+		conflicts:      db.Collection(repository.ConflictingObservations)}}
 }
 
 func (s *Repository) UpsertVaa(ctx context.Context, v *vaa.VAA, serializedVaa []byte) error {
@@ -95,7 +100,7 @@ func (s *Repository) UpsertVaa(ctx context.Context, v *vaa.VAA, serializedVaa []
 
 	update := bson.M{
 		"$set":         vaaDoc,
-		"$setOnInsert": indexedAt(now),
+		"$setOnInsert": VaaInsertFields{IndexedAt: now, TraceContext: telemetry.TraceContext(ctx)},
 		"$inc":         bson.D{{Key: "revision", Value: 1}},
 	}
 
@@ -136,8 +141,10 @@ func (s *Repository) UpsertVaa(ctx context.Context, v *vaa.VAA, serializedVaa []
 		s.updateVAACount(v.EmitterChain)
 
 		// send signedvaa event to topic.
+		trackID := track.GetTrackID(v.MessageID())
+		trace.SpanFromContext(ctx).SetAttributes(telemetry.TrackIDKey.String(trackID))
 		event, newErr := events.NewNotificationEvent[events.SignedVaa](
-			track.GetTrackID(v.MessageID()), "fly", events.SignedVaaType,
+			trackID, "fly", events.SignedVaaType,
 			events.SignedVaa{
 				ID:               v.MessageID(),
 				EmitterChain:     uint16(v.EmitterChain),
@@ -372,6 +379,104 @@ func (s *Repository) updateVAACount(chainID vaa.ChainID) {
 	_, _ = s.collections.vaaCounts.UpdateByID(context.TODO(), chainID, update, opts)
 }
 
+// FindObservationSigners returns the guardians and hashes of the stored observations of a message.
+func (s *Repository) FindObservationSigners(ctx context.Context, chainID vaa.ChainID, emitter, sequence string) ([]ConflictingSigner, error) {
+	filter := bson.D{
+		{Key: "emitterChain", Value: chainID},
+		{Key: "emitterAddr", Value: emitter},
+		{Key: "sequence", Value: sequence},
+	}
+	opts := options.Find().SetProjection(bson.D{{Key: "guardianAddr", Value: 1}, {Key: "hash", Value: 1}})
+	cur, err := s.collections.observations.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		GuardianAddr string `bson:"guardianAddr"`
+		Hash         []byte `bson:"hash"`
+	}
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	signers := make([]ConflictingSigner, 0, len(docs))
+	for _, d := range docs {
+		signers = append(signers, ConflictingSigner{GuardianAddr: d.GuardianAddr, Hash: hex.EncodeToString(d.Hash)})
+	}
+	return signers, nil
+}
+
+// FindVaaDigest returns the digest of the signed VAA of a message, empty if the VAA is not stored.
+func (s *Repository) FindVaaDigest(ctx context.Context, id string) (string, error) {
+	var doc struct {
+		Digest string `bson:"digest"`
+	}
+	opts := options.FindOne().SetProjection(bson.D{{Key: "digest", Value: 1}})
+	err := s.collections.vaas.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return doc.Digest, nil
+}
+
+// ConfirmConflictingObservation marks a conflicting message as confirmed with the digest of its signed VAA.
+// It returns true if the conflict was not confirmed before.
+func (s *Repository) ConfirmConflictingObservation(ctx context.Context, id string, digest string) (bool, error) {
+	filter := bson.M{"_id": id, "status": bson.M{"$ne": ConflictConfirmed}}
+	update := bson.M{"$set": bson.M{"status": ConflictConfirmed, "vaaDigest": digest}}
+	result, err := s.collections.conflicts.UpdateOne(ctx, filter, update)
+	if err != nil {
+		s.log.Error("Error confirming conflicting observation", zap.String("id", id), zap.Error(err))
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// FindUnconfirmedConflictingObservations returns the unconfirmed conflicting messages indexed after the given time,
+// the most recent first.
+func (s *Repository) FindUnconfirmedConflictingObservations(ctx context.Context, from time.Time, limit int64) ([]ConflictingObservation, error) {
+	filter := bson.M{"indexedAt": bson.M{"$gte": from}, "status": ConflictUnconfirmed}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "indexedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+	cur, err := s.collections.conflicts.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []ConflictingObservation
+	if err := cur.All(ctx, &conflicts); err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// UpsertConflictingObservation adds the signers to the evidence of a conflicting message.
+// A new conflict is recorded as unconfirmed.
+// It returns true if the conflict was not recorded before.
+func (s *Repository) UpsertConflictingObservation(ctx context.Context, c *ConflictingObservationUpdate, signers []ConflictingSigner) (bool, error) {
+	hashes := make([]string, 0, len(signers))
+	for _, signer := range signers {
+		hashes = append(hashes, signer.Hash)
+	}
+	update := bson.M{
+		"$set":         c,
+		"$setOnInsert": bson.M{"indexedAt": time.Now(), "status": ConflictUnconfirmed},
+		"$addToSet": bson.M{
+			"signers": bson.M{"$each": signers},
+			"hashes":  bson.M{"$each": hashes},
+		},
+	}
+	opts := options.Update().SetUpsert(true)
+	result, err := s.collections.conflicts.UpdateByID(ctx, c.ID, update, opts)
+	if err != nil {
+		s.log.Error("Error inserting conflicting observation", zap.String("id", c.ID), zap.Error(err))
+		return false, err
+	}
+	return s.isNewRecord(result), nil
+}
+
 func (s *Repository) isNewRecord(result *mongo.UpdateResult) bool {
 	return result.MatchedCount == 0 && result.ModifiedCount == 0 && result.UpsertedCount == 1
 }
@@ -566,8 +671,10 @@ func (s *Repository) UpsertDuplicateVaa(ctx context.Context, v *vaa.VAA, seriali
 func (s *Repository) notifyNewVaa(ctx context.Context, v *vaa.VAA, serializedVaa []byte, txHash string) error {
 	s.metrics.IncVaaInserted(v.EmitterChain)
 	s.updateVAACount(v.EmitterChain)
+	trackID := track.GetTrackID(v.MessageID())
+	trace.SpanFromContext(ctx).SetAttributes(telemetry.TrackIDKey.String(trackID))
 	event, newErr := events.NewNotificationEvent[events.SignedVaa](
-		track.GetTrackID(v.MessageID()), "fly", events.SignedVaaType,
+		trackID, "fly", events.SignedVaaType,
 		events.SignedVaa{
 			ID:               v.MessageID(),
 			EmitterChain:     uint16(v.EmitterChain),
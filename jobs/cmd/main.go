@@ -3,13 +3,26 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbconsts"
+	"github.com/wormhole-foundation/wormhole-explorer/common/fees"
+	"github.com/wormhole-foundation/wormhole-explorer/common/governance"
+	"github.com/wormhole-foundation/wormhole-explorer/common/latency"
+	"github.com/wormhole-foundation/wormhole-explorer/common/observation"
+	"github.com/wormhole-foundation/wormhole-explorer/common/timeseries"
+	jobsAlert "github.com/wormhole-foundation/wormhole-explorer/jobs/internal/alert"
+	feesJob "github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/fees"
+	governanceJob "github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/governance"
+	latencyJob "github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/latency"
+	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/observations"
 	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/protocols"
 	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/protocols/repository"
 	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/stats"
@@ -24,6 +37,7 @@ import (
 	common "github.com/wormhole-foundation/wormhole-explorer/common/coingecko"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbutil"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/export"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
 	filePrices "github.com/wormhole-foundation/wormhole-explorer/common/prices"
 	"github.com/wormhole-foundation/wormhole-explorer/jobs/config"
@@ -37,6 +51,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// protocolsHTTPTimeout bounds every request made to the protocols activity and stats endpoints.
+const protocolsHTTPTimeout = 30 * time.Second
+
 type exitCode int
 
 func main() {
@@ -106,6 +123,18 @@ func main() {
 	case jobs.JobIDNTTMedianStats:
 		job := initNTTMedianStatsJob(ctx, logger)
 		err = job.Run(ctx)
+	case jobs.JobIDStuckObservations:
+		job := initStuckObservationsJob(ctx, logger)
+		err = job.Run(ctx)
+	case jobs.JobIDGovernanceActions:
+		job := initGovernanceActionsJob(ctx, logger)
+		err = job.Run(ctx)
+	case jobs.JobIDTransferLatency:
+		job := initTransferLatencyJob(ctx, logger)
+		err = job.Run(ctx)
+	case jobs.JobIDTransactionFees:
+		job := initTransactionFeesJob(ctx, logger)
+		err = job.Run(ctx)
 	default:
 		logger.Error("Invalid job id", zap.String("job_id", cfg.JobID))
 	}
@@ -153,9 +182,19 @@ func initTransferReportJob(ctx context.Context, cfg *config.TransferReportConfig
 		logger.Fatal("Invalid prices type", zap.String("prices_type", cfg.PricesType))
 	}
 
+	formats := make([]export.Format, 0, len(cfg.Formats))
+	for _, f := range cfg.Formats {
+		format, err := export.ParseFormat(strings.TrimSpace(f))
+		if err != nil {
+			logger.Fatal("Invalid report format", zap.String("format", f), zap.Error(err))
+		}
+		formats = append(formats, format)
+	}
+
 	// init token provider.
 	tokenProvider := domain.NewTokenProvider(cfg.P2pNetwork)
-	return report.NewTransferReportJob(db.Database, cfg.PageSize, getPriceByTime, cfg.OutputPath, tokenProvider, logger)
+	settleDelay := time.Duration(cfg.SettleDelayMinutes) * time.Minute
+	return report.NewTransferReportJob(db.Database, cfg.PageSize, getPriceByTime, cfg.OutputPath, formats, cfg.AppIDs, settleDelay, tokenProvider, logger)
 }
 
 func initHistoricalPricesJob(ctx context.Context, cfg *config.HistoricalPricesConfiguration, logger *zap.Logger) *notional.HistoryNotionalJob {
@@ -202,15 +241,7 @@ func initProtocolStatsHourlyJob(ctx context.Context, logger *zap.Logger) *protoc
 	dbClient := influxdb2.NewClient(cfgJob.InfluxUrl, cfgJob.InfluxToken)
 	dbWriter := dbClient.WriteAPIBlocking(cfgJob.InfluxOrganization, cfgJob.InfluxBucket30Days)
 
-	protocolRepos := make([]repository.ProtocolRepository, 0, len(cfgJob.Protocols))
-	for _, c := range cfgJob.Protocols {
-		builder, ok := repository.ProtocolsRepositoryFactory[c.Name]
-		if !ok {
-			log.Fatal("error creating protocol stats client. Unknown protocol:", c.Name, errCfg)
-		}
-		cs := builder(c.Url, logger.With(zap.String("protocol", c.Name), zap.String("url", c.Url)))
-		protocolRepos = append(protocolRepos, cs)
-	}
+	protocolRepos := newProtocolRepositories(cfgJob.Protocols, logger)
 	to := time.Now().UTC().Truncate(1 * time.Hour)
 	from := to.Add(-1 * time.Hour)
 	return protocols.NewStatsJob(dbWriter,
@@ -234,15 +265,7 @@ func initProtocolStatsDailyJob(ctx context.Context, logger *zap.Logger) *protoco
 	dbClient := influxdb2.NewClient(cfgJob.InfluxUrl, cfgJob.InfluxToken)
 	dbWriter := dbClient.WriteAPIBlocking(cfgJob.InfluxOrganization, cfgJob.InfluxBucketInfinite)
 
-	protocolRepos := make([]repository.ProtocolRepository, 0, len(cfgJob.Protocols))
-	for _, c := range cfgJob.Protocols {
-		builder, ok := repository.ProtocolsRepositoryFactory[c.Name]
-		if !ok {
-			log.Fatal("error creating protocol stats client. Unknown protocol:", c.Name, errCfg)
-		}
-		cs := builder(c.Url, logger.With(zap.String("protocol", c.Name), zap.String("url", c.Url)))
-		protocolRepos = append(protocolRepos, cs)
-	}
+	protocolRepos := newProtocolRepositories(cfgJob.Protocols, logger)
 	to := time.Now().UTC().Truncate(24 * time.Hour)
 	from := to.Add(-24 * time.Hour)
 	return protocols.NewStatsJob(dbWriter,
@@ -254,6 +277,27 @@ func initProtocolStatsDailyJob(ctx context.Context, logger *zap.Logger) *protoco
 		logger)
 }
 
+// newProtocolRepositories creates the client of each protocol, using the adapter definition if present.
+func newProtocolRepositories(protocols []config.Protocol, logger *zap.Logger) []repository.ProtocolRepository {
+	protocolRepos := make([]repository.ProtocolRepository, 0, len(protocols))
+	for _, c := range protocols {
+		protocolLogger := logger.With(zap.String("protocol", c.Name), zap.String("url", c.Url))
+		if c.Adapter != nil {
+			if err := c.Adapter.Validate(); err != nil {
+				log.Fatal("error creating protocol stats client. Invalid adapter for protocol:", c.Name, err)
+			}
+			protocolRepos = append(protocolRepos, repository.NewAdapterRestClient(c.Name, c.Url, *c.Adapter, protocolLogger, &http.Client{Timeout: protocolsHTTPTimeout}))
+			continue
+		}
+		builder, ok := repository.ProtocolsRepositoryFactory[c.Name]
+		if !ok {
+			log.Fatal("error creating protocol stats client. Unknown protocol:", c.Name)
+		}
+		protocolRepos = append(protocolRepos, builder(c.Url, protocolLogger))
+	}
+	return protocolRepos
+}
+
 func initMigrateNativeTxHashJob(ctx context.Context, logger *zap.Logger) *migration.MigrateNativeTxHash {
 	cfgJob, errCfg := configuration.LoadFromEnv[config.MigrateNativeTxHashConfiguration](ctx)
 	if errCfg != nil {
@@ -335,6 +379,131 @@ func initNTTMedianStatsJob(ctx context.Context, logger *zap.Logger) *stats.NTTMe
 	return stats.NewNTTMedian(influxClient, cfgJob.InfluxOrganization, cfgJob.InfluxBucketInfinite, cache, logger)
 }
 
+func initStuckObservationsJob(ctx context.Context, logger *zap.Logger) *observations.StuckObservationsJob {
+	cfgJob, errCfg := configuration.LoadFromEnv[config.StuckObservationsConfiguration](ctx)
+	if errCfg != nil {
+		log.Fatal("error creating config", errCfg)
+	}
+	db, err := dbutil.Connect(ctx, logger, cfgJob.MongoURI, cfgJob.MongoDatabase, false)
+	if err != nil {
+		logger.Fatal("Failed to connect MongoDB", zap.Error(err))
+	}
+
+	// init alert client.
+	var alertClient alert.AlertClient = alert.NewDummyClient()
+	if cfgJob.AlertEnabled {
+		alertConfig := alert.AlertConfig{
+			Environment: cfgJob.Environment,
+			Enabled:     cfgJob.AlertEnabled,
+			ApiKey:      cfgJob.AlertApiKey,
+		}
+		alertClient, err = alert.NewAlertService(alertConfig, jobsAlert.LoadAlerts)
+		if err != nil {
+			logger.Fatal("Failed to create alert client", zap.Error(err))
+		}
+	}
+
+	detector := observation.NewDetector(db.Database, logger)
+	lookback := time.Duration(cfgJob.LookbackMinutes) * time.Minute
+	threshold := time.Duration(cfgJob.ThresholdMinutes) * time.Minute
+	repo := observation.NewRepository(db.Database, logger)
+	return observations.NewStuckObservationsJob(detector, repo, alertClient, lookback, threshold, logger)
+}
+
+func initGovernanceActionsJob(ctx context.Context, logger *zap.Logger) *governanceJob.GovernanceActionsJob {
+	cfgJob, errCfg := configuration.LoadFromEnv[config.GovernanceActionsConfiguration](ctx)
+	if errCfg != nil {
+		log.Fatal("error creating config", errCfg)
+	}
+	db, err := dbutil.Connect(ctx, logger, cfgJob.MongoURI, cfgJob.MongoDatabase, false)
+	if err != nil {
+		logger.Fatal("Failed to connect MongoDB", zap.Error(err))
+	}
+	indexer := governance.NewIndexer(governance.NewRepository(db.Database, logger), logger)
+	return governanceJob.NewGovernanceActionsJob(indexer, cfgJob.FullScan, logger)
+}
+
+func initTransferLatencyJob(ctx context.Context, logger *zap.Logger) *latencyJob.TransferLatencyJob {
+	cfgJob, errCfg := configuration.LoadFromEnv[config.TransferLatencyConfiguration](ctx)
+	if errCfg != nil {
+		log.Fatal("error creating config", errCfg)
+	}
+	db, err := dbutil.Connect(ctx, logger, cfgJob.MongoURI, cfgJob.MongoDatabase, false)
+	if err != nil {
+		logger.Fatal("Failed to connect MongoDB", zap.Error(err))
+	}
+
+	store, err := newTimeSeriesWriter(ctx, &cfgJob.TimeSeriesConfiguration,
+		map[timeseries.Bucket]string{timeseries.Bucket30Days: cfgJob.InfluxBucket30Days})
+	if err != nil {
+		logger.Fatal("Failed to create time-series store", zap.Error(err))
+	}
+
+	collector := latency.NewCollector(latency.NewRepository(db.Database, logger), store, logger)
+	lookback := time.Duration(cfgJob.LookbackMinutes) * time.Minute
+	return latencyJob.NewTransferLatencyJob(collector, lookback, logger)
+}
+
+func initTransactionFeesJob(ctx context.Context, logger *zap.Logger) *feesJob.TransactionFeesJob {
+	cfgJob, errCfg := configuration.LoadFromEnv[config.TransactionFeesConfiguration](ctx)
+	if errCfg != nil {
+		log.Fatal("error creating config", errCfg)
+	}
+
+	// the range is the lookback window unless a range to backfill is configured.
+	to := time.Now().UTC()
+	from := to.Add(-time.Duration(cfgJob.LookbackMinutes) * time.Minute)
+	if cfgJob.FromDate != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, cfgJob.FromDate); err != nil {
+			logger.Fatal("Invalid FROM_DATE", zap.Error(err))
+		}
+		if cfgJob.ToDate != "" {
+			if to, err = time.Parse(time.RFC3339, cfgJob.ToDate); err != nil {
+				logger.Fatal("Invalid TO_DATE", zap.Error(err))
+			}
+		}
+	}
+
+	db, err := dbutil.Connect(ctx, logger, cfgJob.MongoURI, cfgJob.MongoDatabase, false)
+	if err != nil {
+		logger.Fatal("Failed to connect MongoDB", zap.Error(err))
+	}
+
+	store, err := newTimeSeriesWriter(ctx, &cfgJob.TimeSeriesConfiguration,
+		map[timeseries.Bucket]string{timeseries.BucketInfinite: cfgJob.InfluxBucketInfinite})
+	if err != nil {
+		logger.Fatal("Failed to create time-series store", zap.Error(err))
+	}
+
+	relayers := fees.NewKnownRelayers(cfgJob.KnownRelayers)
+	collector := fees.NewCollector(fees.NewRepository(db.Database, logger), store, relayers, logger)
+	return feesJob.NewTransactionFeesJob(collector, from, to, logger)
+}
+
+// newTimeSeriesWriter creates the writer of the configured time-series backend. The influx buckets
+// are only used by the influx backend.
+func newTimeSeriesWriter(ctx context.Context, cfg *config.TimeSeriesConfiguration, buckets map[timeseries.Bucket]string) (timeseries.Writer, error) {
+	switch cfg.TimeSeriesBackend {
+	case timeseries.BackendInflux:
+		influxClient := influxdb2.NewClient(cfg.InfluxUrl, cfg.InfluxToken)
+		return timeseries.NewInfluxStore(influxClient, cfg.InfluxOrganization, buckets), nil
+	case timeseries.BackendClickHouse:
+		store := timeseries.NewClickHouseStore(timeseries.ClickHouseConfig{
+			URL:      cfg.ClickHouseURL,
+			Database: cfg.ClickHouseDatabase,
+			User:     cfg.ClickHouseUser,
+			Password: cfg.ClickHousePassword,
+		})
+		if err := store.EnsureSchema(ctx); err != nil {
+			return nil, fmt.Errorf("failed to create clickhouse schema: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unsupported time-series backend: %s", cfg.TimeSeriesBackend)
+	}
+}
+
 func handleExit() {
 	if r := recover(); r != nil {
 		if e, ok := r.(exitCode); ok {
@@ -0,0 +1,99 @@
+// Command protocol-dry-run validates a protocol adapter definition against recorded responses
+// of the protocol api, and prints the activity and stats that the protocols stats job would store.
+//
+// Usage:
+//
+//	go run ./cmd/protocol-dry-run -protocol acme.json -activity page1.json,page2.json -stats stats.json
+//
+// The protocol file has the same format as an entry of PROTOCOLS_JSON.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wormhole-foundation/wormhole-explorer/jobs/config"
+	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/protocols/repository"
+)
+
+func main() {
+	protocolPath := flag.String("protocol", "", "path to the protocol definition")
+	activityPaths := flag.String("activity", "", "comma separated paths to the recorded activity pages, in order")
+	statsPath := flag.String("stats", "", "path to the recorded stats response")
+	strict := flag.Bool("strict", false, "fail if a mapped path is missing in the recorded responses")
+	flag.Parse()
+
+	if *protocolPath == "" || *activityPaths == "" || *statsPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*protocolPath, strings.Split(*activityPaths, ","), *statsPath, *strict); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(protocolPath string, activityPaths []string, statsPath string, strict bool) error {
+	data, err := os.ReadFile(protocolPath)
+	if err != nil {
+		return err
+	}
+	var protocol config.Protocol
+	if err := json.Unmarshal(data, &protocol); err != nil {
+		return fmt.Errorf("failed unmarshalling protocol definition: %w", err)
+	}
+	if protocol.Name == "" {
+		return fmt.Errorf("protocol name is required")
+	}
+	if protocol.Adapter == nil {
+		return fmt.Errorf("protocol %s has no adapter definition", protocol.Name)
+	}
+	if err := protocol.Adapter.Validate(); err != nil {
+		return fmt.Errorf("invalid adapter definition: %w", err)
+	}
+
+	pages := make([][]byte, 0, len(activityPaths))
+	for _, p := range activityPaths {
+		page, err := os.ReadFile(strings.TrimSpace(p))
+		if err != nil {
+			return err
+		}
+		pages = append(pages, page)
+	}
+	stats, err := os.ReadFile(statsPath)
+	if err != nil {
+		return err
+	}
+
+	missing, err := protocol.Adapter.MissingPaths(pages[0], stats)
+	if err != nil {
+		return err
+	}
+	for _, p := range missing {
+		fmt.Fprintln(os.Stderr, "warning: path not found in the recorded response:", p)
+	}
+	if strict && len(missing) > 0 {
+		return fmt.Errorf("%d mapped paths not found", len(missing))
+	}
+
+	activity, err := protocol.Adapter.ParseActivity(pages...)
+	if err != nil {
+		return fmt.Errorf("failed mapping activity: %w", err)
+	}
+	protocolStats, err := protocol.Adapter.ParseStats(stats)
+	if err != nil {
+		return fmt.Errorf("failed mapping stats: %w", err)
+	}
+
+	out := struct {
+		Protocol string                      `json:"protocol"`
+		Activity repository.ProtocolActivity `json:"activity"`
+		Stats    repository.Stats            `json:"stats"`
+	}{protocol.Name, activity, protocolStats}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
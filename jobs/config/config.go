@@ -2,6 +2,8 @@
 // It define a type [Configuration] that represent the aplication configuration
 package config
 
+import "github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/protocols/repository"
+
 // Configuration is the configuration for the job
 type Configuration struct {
 	JobID    string `env:"JOB_ID,required"`
@@ -22,13 +24,16 @@ type NotionalConfiguration struct {
 }
 
 type TransferReportConfiguration struct {
-	MongoURI      string `env:"MONGODB_URI,required"`
-	MongoDatabase string `env:"MONGODB_DATABASE,required"`
-	PageSize      int64  `env:"PAGE_SIZE,default=100"`
-	PricesType    string `env:"PRICES_TYPE,required"`
-	PricesUri     string `env:"PRICES_URI,required"`
-	OutputPath    string `env:"OUTPUT_PATH,required"`
-	P2pNetwork    string `env:"P2P_NETWORK,required"`
+	MongoURI           string   `env:"MONGODB_URI,required"`
+	MongoDatabase      string   `env:"MONGODB_DATABASE,required"`
+	PageSize           int64    `env:"PAGE_SIZE,default=100"`
+	PricesType         string   `env:"PRICES_TYPE,required"`
+	PricesUri          string   `env:"PRICES_URI,required"`
+	OutputPath         string   `env:"OUTPUT_PATH,required"`
+	P2pNetwork         string   `env:"P2P_NETWORK,required"`
+	Formats            []string `env:"FORMATS,default=csv"`
+	AppIDs             []string `env:"APP_IDS"`
+	SettleDelayMinutes int      `env:"SETTLE_DELAY_MINUTES,default=60"`
 }
 
 type HistoricalPricesConfiguration struct {
@@ -67,6 +72,8 @@ type ProtocolsStatsConfiguration struct {
 type Protocol struct {
 	Name string `json:"name"`
 	Url  string `json:"url"`
+	// Adapter describes the api of a protocol without a dedicated client.
+	Adapter *repository.AdapterDefinition `json:"adapter,omitempty"`
 }
 
 type ProtocolsActivityConfiguration struct {
@@ -106,3 +113,53 @@ type NTTMedianStatsConfiguration struct {
 	CacheUrl             string `env:"CACHE_URL,required"`
 	CachePrefix          string `env:"CACHE_PREFIX,required"`
 }
+
+type StuckObservationsConfiguration struct {
+	Environment      string `env:"ENVIRONMENT,required"`
+	MongoURI         string `env:"MONGODB_URI,required"`
+	MongoDatabase    string `env:"MONGODB_DATABASE,required"`
+	AlertEnabled     bool   `env:"ALERT_ENABLED"`
+	AlertApiKey      string `env:"ALERT_API_KEY"`
+	LookbackMinutes  int    `env:"LOOKBACK_MINUTES,default=180"`
+	ThresholdMinutes int    `env:"THRESHOLD_MINUTES,default=30"`
+}
+
+type GovernanceActionsConfiguration struct {
+	MongoURI      string `env:"MONGODB_URI,required"`
+	MongoDatabase string `env:"MONGODB_DATABASE,required"`
+	FullScan      bool   `env:"FULL_SCAN,default=false"`
+}
+
+// TimeSeriesConfiguration selects the time-series backend the jobs write to. It must match the
+// backend the api reads the measurements from.
+type TimeSeriesConfiguration struct {
+	TimeSeriesBackend  string `env:"TIMESERIES_BACKEND,default=influx"`
+	InfluxUrl          string `env:"INFLUX_URL"`
+	InfluxToken        string `env:"INFLUX_TOKEN"`
+	InfluxOrganization string `env:"INFLUX_ORGANIZATION"`
+	ClickHouseURL      string `env:"CLICKHOUSE_URL"`
+	ClickHouseDatabase string `env:"CLICKHOUSE_DATABASE"`
+	ClickHouseUser     string `env:"CLICKHOUSE_USER"`
+	ClickHousePassword string `env:"CLICKHOUSE_PASSWORD"`
+}
+
+type TransferLatencyConfiguration struct {
+	TimeSeriesConfiguration
+	MongoURI           string `env:"MONGODB_URI,required"`
+	MongoDatabase      string `env:"MONGODB_DATABASE,required"`
+	InfluxBucket30Days string `env:"INFLUX_BUCKET_30_DAYS"`
+	LookbackMinutes    int    `env:"LOOKBACK_MINUTES,default=60"`
+}
+
+type TransactionFeesConfiguration struct {
+	TimeSeriesConfiguration
+	MongoURI             string `env:"MONGODB_URI,required"`
+	MongoDatabase        string `env:"MONGODB_DATABASE,required"`
+	InfluxBucketInfinite string `env:"INFLUX_BUCKET_INFINITE"`
+	LookbackMinutes      int    `env:"LOOKBACK_MINUTES,default=60"`
+	// KnownRelayers are the relayer addresses tagged in the measurement, the other senders are tagged as other.
+	KnownRelayers []string `env:"KNOWN_RELAYERS"`
+	// FromDate and ToDate replace the lookback window to backfill a range, in RFC3339 format.
+	FromDate string `env:"FROM_DATE"`
+	ToDate   string `env:"TO_DATE"`
+}
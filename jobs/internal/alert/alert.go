@@ -0,0 +1,41 @@
+package alert
+
+import (
+	"fmt"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+)
+
+// alert key constants definition.
+const (
+	StuckMessage   = "STUCK_MESSAGE"
+	PartialMessage = "PARTIAL_MESSAGE"
+)
+
+func LoadAlerts(cfg alert.AlertConfig) map[string]alert.Alert {
+	alerts := make(map[string]alert.Alert)
+
+	// Alert for messages that reached the quorum but have no signed VAA.
+	alerts[StuckMessage] = alert.Alert{
+		Alias:       StuckMessage,
+		Message:     fmt.Sprintf("[%s] %s", cfg.Environment, "Message reached quorum but has no signed VAA"),
+		Description: "A message collected enough observations to reach the guardian set quorum but the signed VAA was not found in the vaas collection.",
+		Actions:     []string{"check fly gossip vaa consumer", "check vaas collection"},
+		Tags:        []string{cfg.Environment, "jobs", "observations", "vaa"},
+		Entity:      "jobs",
+		Priority:    alert.HIGH,
+	}
+
+	// Alert for messages that did not reach the quorum.
+	alerts[PartialMessage] = alert.Alert{
+		Alias:       PartialMessage,
+		Message:     fmt.Sprintf("[%s] %s", cfg.Environment, "Message observed without reaching quorum"),
+		Description: "A message was observed by some guardians but did not reach the guardian set quorum. It may be held by the governor, reorged or the guardians may disagree.",
+		Actions:     []string{"check governor status", "check observations by hash"},
+		Tags:        []string{cfg.Environment, "jobs", "observations"},
+		Entity:      "jobs",
+		Priority:    alert.MODERATE,
+	}
+
+	return alerts
+}
@@ -0,0 +1,51 @@
+// Package fees implements the job that records the fees of the source and destination transactions.
+package fees
+
+import (
+	"context"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/fees"
+	"go.uber.org/zap"
+)
+
+// step is the size of the ranges in which the collection is split, so that a long range does not load
+// every transaction at once.
+const step = 24 * time.Hour
+
+// TransactionFeesJob writes the fees of the transactions updated in a time range.
+type TransactionFeesJob struct {
+	collector *fees.Collector
+	from      time.Time
+	to        time.Time
+	logger    *zap.Logger
+}
+
+// NewTransactionFeesJob creates a new transaction fees job for the range [from, to).
+func NewTransactionFeesJob(collector *fees.Collector, from, to time.Time, logger *zap.Logger) *TransactionFeesJob {
+	return &TransactionFeesJob{
+		collector: collector,
+		from:      from,
+		to:        to,
+		logger:    logger.With(zap.String("module", "TransactionFeesJob")),
+	}
+}
+
+// Run runs the job.
+func (j *TransactionFeesJob) Run(ctx context.Context) error {
+	var total int
+	for start := j.from; start.Before(j.to); start = start.Add(step) {
+		end := start.Add(step)
+		if end.After(j.to) {
+			end = j.to
+		}
+		count, err := j.collector.Collect(ctx, start, end)
+		if err != nil {
+			return err
+		}
+		j.logger.Debug("transaction fees collected", zap.Time("from", start), zap.Time("to", end), zap.Int("points", count))
+		total += count
+	}
+	j.logger.Info("transaction fees job finished", zap.Int("points", total))
+	return nil
+}
@@ -0,0 +1,35 @@
+// Package governance implements the job that indexes governance VAAs into governance actions.
+package governance
+
+import (
+	"context"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/governance"
+	"go.uber.org/zap"
+)
+
+// GovernanceActionsJob decodes the governance VAAs into the governanceActions collection.
+type GovernanceActionsJob struct {
+	indexer  *governance.Indexer
+	fullScan bool
+	logger   *zap.Logger
+}
+
+// NewGovernanceActionsJob creates a new governance actions job.
+func NewGovernanceActionsJob(indexer *governance.Indexer, fullScan bool, logger *zap.Logger) *GovernanceActionsJob {
+	return &GovernanceActionsJob{
+		indexer:  indexer,
+		fullScan: fullScan,
+		logger:   logger.With(zap.String("module", "GovernanceActionsJob")),
+	}
+}
+
+// Run runs the job.
+func (j *GovernanceActionsJob) Run(ctx context.Context) error {
+	indexed, err := j.indexer.Index(ctx, j.fullScan)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("governance actions job finished", zap.Int("actions", indexed), zap.Bool("fullScan", j.fullScan))
+	return nil
+}
@@ -15,6 +15,10 @@ const (
 	JobIDNTTTopHolderStats     = "JOB_NTT_TOP_HOLDER_STATS"
 	JobIDNTTMedianStats        = "JOB_NTT_MEDIAN_STATS"
 	JobIDMigrationNativeTxHash = "JOB_MIGRATE_NATIVE_TX_HASH"
+	JobIDStuckObservations     = "JOB_STUCK_OBSERVATIONS"
+	JobIDGovernanceActions     = "JOB_GOVERNANCE_ACTIONS"
+	JobIDTransferLatency       = "JOB_TRANSFER_LATENCY"
+	JobIDTransactionFees       = "JOB_TRANSACTION_FEES"
 )
 
 // Job is the interface for jobs.
@@ -0,0 +1,38 @@
+// Package latency implements the job that records the phase durations of the completed transfers.
+package latency
+
+import (
+	"context"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/latency"
+	"go.uber.org/zap"
+)
+
+// TransferLatencyJob writes the phase durations of the transfers completed in the lookback window.
+type TransferLatencyJob struct {
+	collector *latency.Collector
+	lookback  time.Duration
+	logger    *zap.Logger
+}
+
+// NewTransferLatencyJob creates a new transfer latency job.
+func NewTransferLatencyJob(collector *latency.Collector, lookback time.Duration, logger *zap.Logger) *TransferLatencyJob {
+	return &TransferLatencyJob{
+		collector: collector,
+		lookback:  lookback,
+		logger:    logger.With(zap.String("module", "TransferLatencyJob")),
+	}
+}
+
+// Run runs the job. The window of consecutive runs may overlap, the points of a transfer are
+// written with the same tags and time so the last write wins.
+func (j *TransferLatencyJob) Run(ctx context.Context) error {
+	to := time.Now().UTC()
+	count, err := j.collector.Collect(ctx, to.Add(-j.lookback), to)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("transfer latency job finished", zap.Int("transfers", count))
+	return nil
+}
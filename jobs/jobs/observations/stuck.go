@@ -0,0 +1,96 @@
+// Package observations implements the job that detects messages that never became a signed VAA.
+package observations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/observation"
+	jobsAlert "github.com/wormhole-foundation/wormhole-explorer/jobs/internal/alert"
+	"go.uber.org/zap"
+)
+
+// StuckObservationsJob stores the messages first observed in the lookback window that did not become a signed VAA
+// in time, so that the API serves them, and sends an alert for every stuck or partial message.
+type StuckObservationsJob struct {
+	detector  *observation.Detector
+	repo      *observation.Repository
+	alert     alert.AlertClient
+	lookback  time.Duration
+	threshold time.Duration
+	logger    *zap.Logger
+}
+
+// NewStuckObservationsJob creates a new stuck observations job.
+func NewStuckObservationsJob(detector *observation.Detector, repo *observation.Repository, alert alert.AlertClient,
+	lookback, threshold time.Duration, logger *zap.Logger) *StuckObservationsJob {
+	return &StuckObservationsJob{
+		detector:  detector,
+		repo:      repo,
+		alert:     alert,
+		lookback:  lookback,
+		threshold: threshold,
+		logger:    logger.With(zap.String("module", "StuckObservationsJob")),
+	}
+}
+
+// Run runs the job.
+func (j *StuckObservationsJob) Run(ctx context.Context) error {
+	to := time.Now().UTC()
+	messages, err := j.detector.Find(ctx, observation.Query{
+		From:      to.Add(-j.lookback),
+		To:        to,
+		Threshold: j.threshold,
+	})
+	if err != nil {
+		return err
+	}
+	if err := j.repo.Upsert(ctx, messages); err != nil {
+		return err
+	}
+
+	var alerts int
+	for _, m := range messages {
+		if m.Status == observation.StatusLate {
+			continue
+		}
+		alerts++
+		j.logger.Warn("message without signed vaa",
+			zap.String("messageId", m.MessageID),
+			zap.String("status", string(m.Status)),
+			zap.Int("signers", m.Signers),
+			zap.Int("quorum", m.Quorum))
+		if err := j.sendAlert(ctx, m); err != nil {
+			j.logger.Error("failed to send alert", zap.String("messageId", m.MessageID), zap.Error(err))
+		}
+	}
+	j.logger.Info("stuck observations job finished", zap.Int("messages", len(messages)), zap.Int("alerts", alerts))
+	return nil
+}
+
+func (j *StuckObservationsJob) sendAlert(ctx context.Context, m observation.Message) error {
+	key := jobsAlert.PartialMessage
+	if m.Status == observation.StatusStuck {
+		key = jobsAlert.StuckMessage
+	}
+	a, err := j.alert.CreateAlert(key, alert.AlertContext{
+		Details: map[string]string{
+			"messageId":        m.MessageID,
+			"status":           string(m.Status),
+			"signers":          strconv.Itoa(m.Signers),
+			"quorum":           strconv.Itoa(m.Quorum),
+			"hashes":           strconv.Itoa(m.Hashes),
+			"guardianSetIndex": strconv.FormatUint(uint64(m.GuardianSetIndex), 10),
+			"firstObservedAt":  m.FirstObservedAt.Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	// the alias is unique by message so consecutive runs do not open a new alert for the same message.
+	a.Alias = fmt.Sprintf("%s:%s", a.Alias, m.MessageID)
+	return j.alert.Send(ctx, a)
+}
@@ -0,0 +1,500 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/protocols/internal/commons"
+	"go.uber.org/zap"
+)
+
+// Pagination types supported by the adapter.
+const (
+	// PaginationPage increments a page number until a page returns fewer items than the page size.
+	PaginationPage = "page"
+	// PaginationCursor sends the cursor found in the previous response until it is empty.
+	PaginationCursor = "cursor"
+)
+
+// defaultMaxPages is the maximum number of pages requested when the definition does not set it.
+const defaultMaxPages = 100
+
+// ErrMaxPagesReached is returned when the activity has more pages than the maximum, since the
+// partial activity would be stored as if it were complete.
+var ErrMaxPagesReached = errors.New("reached the maximum number of activity pages")
+
+// AdapterDefinition describes how to get the activity and stats of a protocol from its HTTP API,
+// so a new protocol can be added with configuration instead of a new client.
+//
+// The field mappings are JSONPath expressions (`$.a.b[0]['c']`) evaluated against the response.
+// An empty mapping leaves the field in zero. Numbers can be returned as JSON numbers or strings.
+type AdapterDefinition struct {
+	// Headers are sent in every request. Values can reference environment variables
+	// with ${NAME}, so credentials are not stored in the definition.
+	Headers  map[string]string `json:"headers"`
+	Activity ActivityEndpoint  `json:"activity"`
+	Stats    StatsEndpoint     `json:"stats"`
+}
+
+// Endpoint is a path relative to the protocol url and its query parameters.
+// The query values of the activity endpoint can contain the {{from}} and {{to}} placeholders
+// in RFC3339 format, or {{from_unix}} and {{to_unix}} in seconds.
+type Endpoint struct {
+	Path  string            `json:"path"`
+	Query map[string]string `json:"query"`
+}
+
+// Pagination describes how to request the pages of the activity endpoint.
+type Pagination struct {
+	Type string `json:"type"`
+	// Param is the query parameter with the page number or the cursor.
+	Param string `json:"param"`
+	// Start is the number of the first page.
+	Start int `json:"start"`
+	// SizeParam is the optional query parameter with the page size.
+	SizeParam string `json:"size_param"`
+	Size      int    `json:"size"`
+	// CursorPath is the path of the cursor of the next page in the response.
+	CursorPath string `json:"cursor_path"`
+	// MaxPages is the maximum number of pages requested, GetActivity fails if there are more pages.
+	MaxPages int `json:"max_pages"`
+}
+
+// ActivityEndpoint maps the response of the activity endpoint to a ProtocolActivity.
+// The totals are read from the first page and the activities are accumulated from every page.
+type ActivityEndpoint struct {
+	Endpoint
+	Pagination            *Pagination `json:"pagination"`
+	TotalValueSecure      string      `json:"total_value_secure"`
+	TotalValueTransferred string      `json:"total_value_transferred"`
+	Volume                string      `json:"volume"`
+	TotalMessages         string      `json:"total_messages"`
+	// Items is the path of the list of activities. The paths below are relative to each item.
+	Items              string `json:"items"`
+	EmitterChainID     string `json:"emitter_chain_id"`
+	DestinationChainID string `json:"destination_chain_id"`
+	Txs                string `json:"txs"`
+	TotalUSD           string `json:"total_usd"`
+}
+
+// StatsEndpoint maps the response of the stats endpoint to Stats.
+type StatsEndpoint struct {
+	Endpoint
+	TotalValueLocked string `json:"total_value_locked"`
+	TotalMessages    string `json:"total_messages"`
+	Volume           string `json:"volume"`
+}
+
+// Validate checks the definition, including the syntax of every path.
+func (d *AdapterDefinition) Validate() error {
+	if d.Activity.Path == "" {
+		return errors.New("activity path is required")
+	}
+	if d.Stats.Path == "" {
+		return errors.New("stats path is required")
+	}
+	if p := d.Activity.Pagination; p != nil {
+		switch p.Type {
+		case PaginationPage:
+			if p.Size <= 0 {
+				return errors.New("page pagination requires a size")
+			}
+		case PaginationCursor:
+			if p.CursorPath == "" {
+				return errors.New("cursor pagination requires a cursor_path")
+			}
+		default:
+			return errors.Errorf("invalid pagination type %q", p.Type)
+		}
+		if p.Param == "" {
+			return errors.New("pagination param is required")
+		}
+		if d.Activity.Items == "" {
+			return errors.New("pagination requires the activity items path")
+		}
+	}
+	itemPaths := []string{d.Activity.EmitterChainID, d.Activity.DestinationChainID, d.Activity.Txs, d.Activity.TotalUSD}
+	if d.Activity.Items == "" {
+		for _, p := range itemPaths {
+			if p != "" {
+				return errors.New("activity item fields require the activity items path")
+			}
+		}
+	}
+
+	paths := append(itemPaths,
+		d.Activity.TotalValueSecure, d.Activity.TotalValueTransferred, d.Activity.Volume, d.Activity.TotalMessages,
+		d.Activity.Items, d.Stats.TotalValueLocked, d.Stats.TotalMessages, d.Stats.Volume)
+	if d.Activity.Pagination != nil {
+		paths = append(paths, d.Activity.Pagination.CursorPath)
+	}
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		if _, err := compileJSONPath(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseActivity maps the pages of a recorded activity response.
+func (d *AdapterDefinition) ParseActivity(pages ...[]byte) (ProtocolActivity, error) {
+	var result ProtocolActivity
+	for i, page := range pages {
+		doc, err := decodeJSON(page)
+		if err != nil {
+			return result, err
+		}
+		if i == 0 {
+			m := mapper{doc: doc}
+			m.float(d.Activity.TotalValueSecure, &result.TotalValueSecure)
+			m.float(d.Activity.TotalValueTransferred, &result.TotalValueTransferred)
+			m.float(d.Activity.Volume, &result.Volume)
+			m.uint(d.Activity.TotalMessages, &result.TotalMessages)
+			if m.err != nil {
+				return result, m.err
+			}
+		}
+		activities, err := d.parseActivities(doc)
+		if err != nil {
+			return result, err
+		}
+		result.Activities = append(result.Activities, activities...)
+	}
+	return result, nil
+}
+
+func (d *AdapterDefinition) parseActivities(doc any) ([]Activity, error) {
+	items, err := d.items(doc)
+	if err != nil {
+		return nil, err
+	}
+	activities := make([]Activity, 0, len(items))
+	for i, item := range items {
+		var a Activity
+		m := mapper{doc: item}
+		m.uint(d.Activity.EmitterChainID, &a.EmitterChainID)
+		m.uint(d.Activity.DestinationChainID, &a.DestinationChainID)
+		m.uint(d.Activity.Txs, &a.Txs)
+		m.float(d.Activity.TotalUSD, &a.TotalUSD)
+		if m.err != nil {
+			return nil, errors.Wrapf(m.err, "activity item %d", i)
+		}
+		activities = append(activities, a)
+	}
+	return activities, nil
+}
+
+func (d *AdapterDefinition) items(doc any) ([]any, error) {
+	if d.Activity.Items == "" {
+		return nil, nil
+	}
+	path, err := compileJSONPath(d.Activity.Items)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := path.lookup(doc)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, errors.Errorf("%s is not a list", d.Activity.Items)
+	}
+	return items, nil
+}
+
+// ParseStats maps a recorded stats response.
+func (d *AdapterDefinition) ParseStats(body []byte) (Stats, error) {
+	var result Stats
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return result, err
+	}
+	m := mapper{doc: doc}
+	m.float(d.Stats.TotalValueLocked, &result.TotalValueLocked)
+	m.uint(d.Stats.TotalMessages, &result.TotalMessages)
+	m.float(d.Stats.Volume, &result.Volume)
+	return result, m.err
+}
+
+// MissingPaths returns the mapped paths that are not present in a recorded activity page and stats response.
+// The item paths are checked against the first activity item.
+func (d *AdapterDefinition) MissingPaths(activityPage, stats []byte) ([]string, error) {
+	var missing []string
+	check := func(doc any, paths ...string) {
+		for _, p := range paths {
+			if p == "" {
+				continue
+			}
+			path, err := compileJSONPath(p)
+			if err != nil {
+				missing = append(missing, p)
+				continue
+			}
+			if v, ok := path.lookup(doc); !ok || v == nil {
+				missing = append(missing, p)
+			}
+		}
+	}
+
+	activityDoc, err := decodeJSON(activityPage)
+	if err != nil {
+		return nil, err
+	}
+	check(activityDoc, d.Activity.TotalValueSecure, d.Activity.TotalValueTransferred, d.Activity.Volume,
+		d.Activity.TotalMessages, d.Activity.Items)
+	items, err := d.items(activityDoc)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) > 0 {
+		check(items[0], d.Activity.EmitterChainID, d.Activity.DestinationChainID, d.Activity.Txs, d.Activity.TotalUSD)
+	}
+
+	statsDoc, err := decodeJSON(stats)
+	if err != nil {
+		return nil, err
+	}
+	check(statsDoc, d.Stats.TotalValueLocked, d.Stats.TotalMessages, d.Stats.Volume)
+	return missing, nil
+}
+
+func decodeJSON(body []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "failed decoding json")
+	}
+	return doc, nil
+}
+
+// mapper reads numeric values from a decoded document, keeping the first error.
+type mapper struct {
+	doc any
+	err error
+}
+
+func (m *mapper) value(path string) (string, bool) {
+	if m.err != nil || path == "" {
+		return "", false
+	}
+	p, err := compileJSONPath(path)
+	if err != nil {
+		m.err = err
+		return "", false
+	}
+	v, ok := p.lookup(m.doc)
+	if !ok || v == nil {
+		return "", false
+	}
+	switch t := v.(type) {
+	case json.Number:
+		return t.String(), true
+	case string:
+		return t, t != ""
+	default:
+		m.err = errors.Errorf("%s is not a number: %v", path, v)
+		return "", false
+	}
+}
+
+func (m *mapper) float(path string, target *float64) {
+	s, ok := m.value(path)
+	if !ok {
+		return
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		m.err = errors.Errorf("failed parsing %s value %q to float64", path, s)
+		return
+	}
+	*target = f
+}
+
+func (m *mapper) uint(path string, target *uint64) {
+	s, ok := m.value(path)
+	if !ok {
+		return
+	}
+	u, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		// some apis return integers as floats, e.g. 12.0
+		f, errFloat := strconv.ParseFloat(s, 64)
+		if errFloat != nil || f < 0 || f != math.Trunc(f) {
+			m.err = errors.Errorf("failed parsing %s value %q to uint64", path, s)
+			return
+		}
+		u = uint64(f)
+	}
+	*target = u
+}
+
+// NewAdapterRestClient creates a client for a protocol described by an AdapterDefinition.
+func NewAdapterRestClient(name, baseURL string, definition AdapterDefinition, logger *zap.Logger, httpClient commons.HttpDo) *AdapterRestClient {
+	return &AdapterRestClient{
+		name:       name,
+		baseURL:    baseURL,
+		definition: definition,
+		logger:     logger,
+		client:     httpClient,
+	}
+}
+
+type AdapterRestClient struct {
+	name       string
+	baseURL    string
+	definition AdapterDefinition
+	client     commons.HttpDo
+	logger     *zap.Logger
+}
+
+func (d *AdapterRestClient) ProtocolName() string {
+	return d.name
+}
+
+func (d *AdapterRestClient) GetActivity(ctx context.Context, from, to time.Time) (ProtocolActivity, error) {
+	p := d.definition.Activity.Pagination
+	if p == nil {
+		body, err := d.get(ctx, "activities", d.definition.Activity.Endpoint, from, to, nil)
+		if err != nil {
+			return ProtocolActivity{}, err
+		}
+		return d.definition.ParseActivity(body)
+	}
+
+	maxPages := p.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	pageParams := map[string]string{}
+	if p.SizeParam != "" {
+		pageParams[p.SizeParam] = strconv.Itoa(p.Size)
+	}
+	if p.Type == PaginationPage {
+		pageParams[p.Param] = strconv.Itoa(p.Start)
+	}
+
+	var pages [][]byte
+	complete := false
+	for i := 0; i < maxPages && !complete; i++ {
+		body, err := d.get(ctx, "activities", d.definition.Activity.Endpoint, from, to, pageParams)
+		if err != nil {
+			return ProtocolActivity{}, err
+		}
+		pages = append(pages, body)
+
+		doc, err := decodeJSON(body)
+		if err != nil {
+			return ProtocolActivity{}, errors.Wrapf(err, "failed unmarshalling response body from protocol activities. url:%s", d.baseURL+d.definition.Activity.Path)
+		}
+		if p.Type == PaginationPage {
+			items, err := d.definition.items(doc)
+			if err != nil {
+				return ProtocolActivity{}, err
+			}
+			complete = len(items) < p.Size
+			pageParams[p.Param] = strconv.Itoa(p.Start + i + 1)
+			continue
+		}
+		m := mapper{doc: doc}
+		cursor, ok := m.value(p.CursorPath)
+		if m.err != nil {
+			return ProtocolActivity{}, m.err
+		}
+		complete = !ok
+		pageParams[p.Param] = cursor
+	}
+	if !complete {
+		return ProtocolActivity{}, errors.Wrapf(ErrMaxPagesReached, "max_pages:%d url:%s", maxPages, d.baseURL+d.definition.Activity.Path)
+	}
+	return d.definition.ParseActivity(pages...)
+}
+
+func (d *AdapterRestClient) GetStats(ctx context.Context) (Stats, error) {
+	body, err := d.get(ctx, "stats", d.definition.Stats.Endpoint, time.Time{}, time.Time{}, nil)
+	if err != nil {
+		return Stats{}, err
+	}
+	return d.definition.ParseStats(body)
+}
+
+// get requests an endpoint and returns the body of the response.
+func (d *AdapterRestClient) get(ctx context.Context, resource string, e Endpoint, from, to time.Time, extraParams map[string]string) ([]byte, error) {
+	decoratedLogger := d.logger
+
+	endpoint := d.baseURL + e.Path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		decoratedLogger.Error("failed creating http request for retrieving protocol "+resource, zap.Error(err))
+		return nil, errors.WithStack(err)
+	}
+
+	replacer := strings.NewReplacer(
+		"{{from}}", from.Format(time.RFC3339),
+		"{{to}}", to.Format(time.RFC3339),
+		"{{from_unix}}", strconv.FormatInt(from.Unix(), 10),
+		"{{to_unix}}", strconv.FormatInt(to.Unix(), 10),
+	)
+	q := req.URL.Query()
+	for k, v := range e.Query {
+		q.Set(k, replacer.Replace(v))
+	}
+	for k, v := range extraParams {
+		q.Set(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	for k, v := range d.definition.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	reqId := uuid.New().String()
+	req.Header.Set("X-Request-ID", reqId)
+	decoratedLogger = decoratedLogger.With(zap.String("requestID", reqId))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		decoratedLogger.Error("failed retrieving protocol "+resource, zap.Error(err))
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	decoratedLogger = decoratedLogger.
+		With(zap.String("status_code", http.StatusText(resp.StatusCode))).
+		With(zap.String("response_headers", commons.ToJson(resp.Header)))
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		decoratedLogger.Error("error retrieving protocol "+resource+": got an invalid response status code",
+			zap.String("response_body", string(body)), zap.Int("status_code", resp.StatusCode))
+		return nil, errors.Errorf("failed retrieving protocol %s from url:%s - status_code:%d - response_body:%s", resource, redactQuery(req.URL), resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		decoratedLogger.Error("failed reading response body", zap.Error(err))
+		return nil, errors.Wrapf(errors.WithStack(err), "failed reading response body from protocol %s. url:%s - status_code:%d", resource, endpoint, resp.StatusCode)
+	}
+	return body, nil
+}
+
+// redactQuery returns the url without the query, which may contain credentials.
+func redactQuery(u *url.URL) string {
+	c := *u
+	c.RawQuery = ""
+	return c.String()
+}
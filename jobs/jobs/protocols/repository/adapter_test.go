@@ -0,0 +1,179 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/protocols/internal/commons/mocks"
+	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/protocols/repository"
+	"go.uber.org/zap"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", "adapter", name))
+	require.NoError(t, err)
+	return data
+}
+
+func loadDefinition(t *testing.T) repository.AdapterDefinition {
+	var protocol struct {
+		Adapter repository.AdapterDefinition `json:"adapter"`
+	}
+	require.NoError(t, json.Unmarshal(readTestdata(t, "protocol.json"), &protocol))
+	require.NoError(t, protocol.Adapter.Validate())
+	return protocol.Adapter
+}
+
+var expectedActivity = repository.ProtocolActivity{
+	TotalValueSecure:      1500.5,
+	TotalValueTransferred: 98000,
+	Activities: []repository.Activity{
+		{EmitterChainID: 2, DestinationChainID: 1, Txs: 10, TotalUSD: 1200.25},
+		{EmitterChainID: 1, DestinationChainID: 2, Txs: 3, TotalUSD: 300},
+		{EmitterChainID: 30, DestinationChainID: 2, Txs: 1, TotalUSD: 50},
+	},
+}
+
+func Test_Adapter_ParseRecordedResponses(t *testing.T) {
+	def := loadDefinition(t)
+
+	activity, err := def.ParseActivity(readTestdata(t, "activity_page1.json"), readTestdata(t, "activity_page2.json"))
+	require.NoError(t, err)
+	assert.Equal(t, expectedActivity, activity)
+
+	stats, err := def.ParseStats(readTestdata(t, "stats.json"))
+	require.NoError(t, err)
+	assert.Equal(t, repository.Stats{TotalValueLocked: 2500000.75, TotalMessages: 123456, Volume: 9876543.21}, stats)
+
+	missing, err := def.MissingPaths(readTestdata(t, "activity_page1.json"), readTestdata(t, "stats.json"))
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	missing, err = def.MissingPaths([]byte(`{"data":[{"count":1}]}`), []byte(`{"tvl":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"$.totals.tvs", "$.totals['tvt']", "$.route.source", "$.route.target", "$.usd", "$.messages", "$.volume[0].usd"}, missing)
+}
+
+func Test_Adapter_ParseInvalidValue(t *testing.T) {
+	def := loadDefinition(t)
+	_, err := def.ParseStats([]byte(`{"tvl": "not a number"}`))
+	assert.EqualError(t, err, `failed parsing $.tvl value "not a number" to float64`)
+
+	_, err = def.ParseActivity([]byte(`{"data": [{"count": 1.5}]}`))
+	assert.EqualError(t, err, `activity item 0: failed parsing $.count value "1.5" to uint64`)
+}
+
+func Test_Adapter_Validate(t *testing.T) {
+	valid := loadDefinition(t)
+
+	cases := map[string]func(d *repository.AdapterDefinition){
+		"activity path is required":                          func(d *repository.AdapterDefinition) { d.Activity.Path = "" },
+		"stats path is required":                             func(d *repository.AdapterDefinition) { d.Stats.Path = "" },
+		`invalid pagination type "offset"`:                   func(d *repository.AdapterDefinition) { d.Activity.Pagination.Type = "offset" },
+		"cursor pagination requires a cursor_path":           func(d *repository.AdapterDefinition) { d.Activity.Pagination.CursorPath = "" },
+		"pagination requires the activity items path":        func(d *repository.AdapterDefinition) { d.Activity.Items = "" },
+		`invalid json path "tvl": it must start with $`:      func(d *repository.AdapterDefinition) { d.Stats.TotalValueLocked = "tvl" },
+		`invalid json path "$.volume[x]": invalid index "x"`: func(d *repository.AdapterDefinition) { d.Stats.Volume = "$.volume[x]" },
+	}
+	for expected, modify := range cases {
+		def := valid
+		pagination := *valid.Activity.Pagination
+		def.Activity.Pagination = &pagination
+		modify(&def)
+		assert.EqualError(t, def.Validate(), expected)
+	}
+}
+
+func Test_AdapterRestClient_GetActivityWithCursor(t *testing.T) {
+	t.Setenv("ACME_API_KEY", "secret")
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	var requests []*http.Request
+	pages := map[string][]byte{
+		"":    readTestdata(t, "activity_page1.json"),
+		"abc": readTestdata(t, "activity_page2.json"),
+	}
+	client := repository.NewAdapterRestClient("acme", "https://api.acme.example", loadDefinition(t), zap.NewNop(),
+		mocks.MockHttpClient(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBuffer(pages[req.URL.Query().Get("cursor")])),
+			}, nil
+		}))
+
+	activity, err := client.GetActivity(context.Background(), from, to)
+	require.NoError(t, err)
+	assert.Equal(t, expectedActivity, activity)
+	assert.Equal(t, "acme", client.ProtocolName())
+
+	require.Len(t, requests, 2)
+	for _, req := range requests {
+		assert.Equal(t, "/wormhole/activity", req.URL.Path)
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		assert.Equal(t, "2024-05-01T00:00:00Z", req.URL.Query().Get("start"))
+		assert.Equal(t, "1714525200", req.URL.Query().Get("end"))
+		assert.Equal(t, "2", req.URL.Query().Get("limit"))
+	}
+	assert.Equal(t, "abc", requests[1].URL.Query().Get("cursor"))
+}
+
+func Test_AdapterRestClient_GetActivityWithPages(t *testing.T) {
+	def := loadDefinition(t)
+	def.Activity.Pagination = &repository.Pagination{Type: repository.PaginationPage, Param: "page", Start: 1, Size: 2}
+
+	var requestedPages []string
+	client := repository.NewAdapterRestClient("acme", "https://api.acme.example", def, zap.NewNop(),
+		mocks.MockHttpClient(func(req *http.Request) (*http.Response, error) {
+			page := req.URL.Query().Get("page")
+			requestedPages = append(requestedPages, page)
+			body := readTestdata(t, "activity_page2.json")
+			if page == "1" {
+				body = readTestdata(t, "activity_page1.json")
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBuffer(body))}, nil
+		}))
+
+	activity, err := client.GetActivity(context.Background(), time.Now(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, expectedActivity, activity)
+	assert.Equal(t, []string{"1", "2"}, requestedPages)
+}
+
+func Test_AdapterRestClient_GetStatsStatus500(t *testing.T) {
+	client := repository.NewAdapterRestClient("acme", "https://api.acme.example", loadDefinition(t), zap.NewNop(),
+		mocks.MockHttpClient(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(bytes.NewBufferString("response_body_test")),
+			}, nil
+		}))
+
+	_, err := client.GetStats(context.Background())
+	assert.EqualError(t, err, "failed retrieving protocol stats from url:https://api.acme.example/wormhole/stats - status_code:500 - response_body:response_body_test")
+}
+
+func Test_AdapterRestClient_GetActivityMaxPages(t *testing.T) {
+	def := loadDefinition(t)
+	def.Activity.Pagination = &repository.Pagination{Type: repository.PaginationPage, Param: "page", Start: 1, Size: 2, MaxPages: 1}
+
+	client := repository.NewAdapterRestClient("acme", "https://api.acme.example", def, zap.NewNop(),
+		mocks.MockHttpClient(func(req *http.Request) (*http.Response, error) {
+			// the first page is full, so there are more pages to request.
+			body := readTestdata(t, "activity_page1.json")
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBuffer(body))}, nil
+		}))
+
+	_, err := client.GetActivity(context.Background(), time.Now(), time.Now())
+	assert.ErrorIs(t, err, repository.ErrMaxPagesReached)
+}
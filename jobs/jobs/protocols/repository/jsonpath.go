@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// jsonPath is a compiled subset of JSONPath: the root `$` followed by any number of
+// `.key`, `['key']` and `[index]` selectors, e.g. `$.data.activity[0]['total_usd']`.
+type jsonPath []pathSelector
+
+type pathSelector struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func compileJSONPath(path string) (jsonPath, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, errors.Errorf("invalid json path %q: it must start with $", path)
+	}
+	var result jsonPath
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, errors.Errorf("invalid json path %q: empty key", path)
+			}
+			result = append(result, pathSelector{key: key})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, errors.Errorf("invalid json path %q: missing ]", path)
+			}
+			selector := rest[1:end]
+			if len(selector) >= 2 && selector[0] == '\'' && selector[len(selector)-1] == '\'' {
+				result = append(result, pathSelector{key: selector[1 : len(selector)-1]})
+			} else {
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, errors.Errorf("invalid json path %q: invalid index %q", path, selector)
+				}
+				result = append(result, pathSelector{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, errors.Errorf("invalid json path %q: unexpected character %q", path, rest[0])
+		}
+	}
+	return result, nil
+}
+
+// lookup returns the value of the path in a document decoded with encoding/json.
+// The second value is false if the path does not exist.
+func (p jsonPath) lookup(doc any) (any, bool) {
+	current := doc
+	for _, s := range p {
+		if s.isIndex {
+			arr, ok := current.([]any)
+			if !ok || s.index >= len(arr) {
+				return nil, false
+			}
+			current = arr[s.index]
+			continue
+		}
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[s.key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,202 @@
+package report
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/export"
+)
+
+const (
+	// tmpSuffix is the suffix of the files that are being written.
+	tmpSuffix = ".tmp"
+	// partFilePrefix is the prefix of the files written by a run.
+	partFilePrefix = "transfers-"
+	// maxOpenPartitions is the maximum number of partitions with open files.
+	maxOpenPartitions = 64
+	// noAppID is the partition of the transfers without app ids.
+	noAppID = "NONE"
+)
+
+// partitionDir returns the directory of the partition of a transfer, relative to the output path.
+// The directories follow the hive naming convention (key=value), so the output can be read as a
+// partitioned dataset.
+func partitionDir(timestamp time.Time, appID string) string {
+	if appID == "" {
+		appID = noAppID
+	}
+	return filepath.Join("date="+timestamp.UTC().Format(time.DateOnly), "app="+appID)
+}
+
+// partitionWriter writes the records of a run into partitioned files, one file per
+// partition and format.
+//
+// Files are written with a temporary name and renamed on commit, so a failed run does
+// not leave partial files. When too many partitions are open the least recently used
+// is closed; if it receives more records later, a new file is created for it.
+type partitionWriter struct {
+	outputPath string
+	runID      string
+	formats    []export.Format
+	columns    []export.Column
+	maxOpen    int
+	open       map[string]*partition
+	seq        map[string]int
+	files      []string
+	clock      int64
+}
+
+type partition struct {
+	files    []*os.File
+	writers  []export.Writer
+	lastUsed int64
+}
+
+func newPartitionWriter(outputPath, runID string, formats []export.Format, columns []export.Column) *partitionWriter {
+	return &partitionWriter{
+		outputPath: outputPath,
+		runID:      runID,
+		formats:    formats,
+		columns:    columns,
+		maxOpen:    maxOpenPartitions,
+		open:       make(map[string]*partition),
+		seq:        make(map[string]int),
+	}
+}
+
+// Write writes a record into the partition dir.
+func (pw *partitionWriter) Write(dir string, record []any) error {
+	p, err := pw.partition(dir)
+	if err != nil {
+		return err
+	}
+	pw.clock++
+	p.lastUsed = pw.clock
+	for _, w := range p.writers {
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit closes the open files and renames the files written by the run to their final names.
+func (pw *partitionWriter) Commit() error {
+	for dir := range pw.open {
+		if err := pw.close(dir); err != nil {
+			return err
+		}
+	}
+	for _, tmp := range pw.files {
+		if err := os.Rename(tmp, strings.TrimSuffix(tmp, tmpSuffix)); err != nil {
+			return err
+		}
+	}
+	pw.files = nil
+	return nil
+}
+
+// Abort closes the open files and removes the files written by the run.
+func (pw *partitionWriter) Abort() {
+	for dir := range pw.open {
+		_ = pw.close(dir)
+	}
+	for _, tmp := range pw.files {
+		_ = os.Remove(tmp)
+	}
+	pw.files = nil
+}
+
+func (pw *partitionWriter) partition(dir string) (*partition, error) {
+	if p, ok := pw.open[dir]; ok {
+		return p, nil
+	}
+	if len(pw.open) >= pw.maxOpen {
+		if err := pw.evict(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(pw.outputPath, dir), 0o755); err != nil {
+		return nil, err
+	}
+
+	seq := pw.seq[dir]
+	pw.seq[dir] = seq + 1
+
+	p := &partition{}
+	for _, format := range pw.formats {
+		name := fmt.Sprintf("%s%s-%03d.%s%s", partFilePrefix, pw.runID, seq, format.Extension(), tmpSuffix)
+		path := filepath.Join(pw.outputPath, dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		pw.files = append(pw.files, path)
+		w, err := export.NewWriter(format, f, pw.columns)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		p.files = append(p.files, f)
+		p.writers = append(p.writers, w)
+	}
+	pw.open[dir] = p
+	return p, nil
+}
+
+// evict closes the least recently used partition.
+func (pw *partitionWriter) evict() error {
+	var lru string
+	var lastUsed int64
+	for dir, p := range pw.open {
+		if lru == "" || p.lastUsed < lastUsed {
+			lru, lastUsed = dir, p.lastUsed
+		}
+	}
+	return pw.close(lru)
+}
+
+func (pw *partitionWriter) close(dir string) error {
+	p := pw.open[dir]
+	delete(pw.open, dir)
+	var result error
+	for i, w := range p.writers {
+		if err := w.Close(); err != nil && result == nil {
+			result = err
+		}
+		if err := p.files[i].Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// removeUncommittedFiles removes the files of the runs that did not commit their state:
+// temporary files and files of runs after the last committed run.
+func removeUncommittedFiles(outputPath, committedRunID string) error {
+	return filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if strings.HasSuffix(name, tmpSuffix) {
+			return os.Remove(path)
+		}
+		if !strings.HasPrefix(name, partFilePrefix) {
+			return nil
+		}
+		runID, _, ok := strings.Cut(strings.TrimPrefix(name, partFilePrefix), "-")
+		if ok && runID > committedRunID {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
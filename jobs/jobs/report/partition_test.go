@@ -0,0 +1,93 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/export"
+)
+
+var testColumns = []export.Column{{Name: "id", Type: export.ColumnString}}
+
+func listFiles(t *testing.T, root string) []string {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	assert.NoError(t, err)
+	sort.Strings(files)
+	return files
+}
+
+func TestPartitionWriter_Commit(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	w := newPartitionWriter(dir, "20240502T000000Z", []export.Format{export.FormatCSV, export.FormatJSONL}, testColumns)
+	w.maxOpen = 1
+	assert.NoError(t, w.Write(partitionDir(day, "PORTAL_TOKEN_BRIDGE"), []any{"a"}))
+	assert.NoError(t, w.Write(partitionDir(day, ""), []any{"b"}))
+	// the first partition was evicted, so a new file is created.
+	assert.NoError(t, w.Write(partitionDir(day, "PORTAL_TOKEN_BRIDGE"), []any{"c"}))
+	assert.NoError(t, w.Commit())
+
+	assert.Equal(t, []string{
+		"date=2024-05-01/app=NONE/transfers-20240502T000000Z-000.csv",
+		"date=2024-05-01/app=NONE/transfers-20240502T000000Z-000.jsonl",
+		"date=2024-05-01/app=PORTAL_TOKEN_BRIDGE/transfers-20240502T000000Z-000.csv",
+		"date=2024-05-01/app=PORTAL_TOKEN_BRIDGE/transfers-20240502T000000Z-000.jsonl",
+		"date=2024-05-01/app=PORTAL_TOKEN_BRIDGE/transfers-20240502T000000Z-001.csv",
+		"date=2024-05-01/app=PORTAL_TOKEN_BRIDGE/transfers-20240502T000000Z-001.jsonl",
+	}, listFiles(t, dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, "date=2024-05-01/app=PORTAL_TOKEN_BRIDGE/transfers-20240502T000000Z-001.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "id\nc\n", string(data))
+}
+
+func TestPartitionWriter_Abort(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	w := newPartitionWriter(dir, "20240502T000000Z", []export.Format{export.FormatParquet}, testColumns)
+	assert.NoError(t, w.Write(partitionDir(day, "CCTP_WORMHOLE_INTEGRATION"), []any{"a"}))
+	w.Abort()
+
+	assert.Empty(t, listFiles(t, dir))
+}
+
+func TestRemoveUncommittedFiles(t *testing.T) {
+	dir := t.TempDir()
+	partition := filepath.Join(dir, "date=2024-05-01", "app=NONE")
+	assert.NoError(t, os.MkdirAll(partition, 0o755))
+	for _, name := range []string{
+		"transfers-20240501T000000Z-000.csv",
+		"transfers-20240502T000000Z-000.csv",
+		"transfers-20240503T000000Z-000.csv",
+		"transfers-20240503T000000Z-001.csv.tmp",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(partition, name), nil, 0o644))
+	}
+	assert.NoError(t, saveState(dir, &reportState{RunID: "20240502T000000Z"}))
+
+	assert.NoError(t, removeUncommittedFiles(dir, "20240502T000000Z"))
+
+	assert.Equal(t, []string{
+		stateFilename,
+		"date=2024-05-01/app=NONE/transfers-20240501T000000Z-000.csv",
+		"date=2024-05-01/app=NONE/transfers-20240502T000000Z-000.csv",
+	}, listFiles(t, dir))
+
+	state, err := loadState(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "20240502T000000Z", state.RunID)
+}
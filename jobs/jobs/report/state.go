@@ -0,0 +1,80 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stateFilename is the name of the file that stores the progress of the report.
+// It starts with an underscore so that dataset readers (e.g.: Spark, DuckDB) ignore it.
+const stateFilename = "_watermark.json"
+
+// runIDLayout is the layout of the run ids. Run ids sort in chronological order.
+const runIDLayout = "20060102T150405Z"
+
+// watermark is the position of the last VAA included in the report.
+//
+// VAAs are processed in (indexedAt, _id) order. indexedAt is set once when the VAA
+// is inserted, so VAAs indexed after the watermark were never reported.
+type watermark struct {
+	IndexedAt time.Time `json:"indexedAt"`
+	ID        string    `json:"id"`
+}
+
+// includes returns true when the VAA is at or before the watermark.
+func (wm watermark) includes(indexedAt time.Time, id string) bool {
+	return indexedAt.Before(wm.IndexedAt) || (indexedAt.Equal(wm.IndexedAt) && id <= wm.ID)
+}
+
+// updatesWatermark is the position of the last redemption re-exported by the report.
+//
+// Redemptions are processed in (destinationTx.updatedAt, _id) order, the transfers
+// reported before their redemption was indexed are written again with the destination
+// transaction.
+type updatesWatermark struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	ID        string    `json:"id"`
+}
+
+// reportState is the progress of the report, stored next to the output files.
+type reportState struct {
+	Watermark watermark        `json:"watermark"`
+	Updates   updatesWatermark `json:"updates"`
+	// RunID is the id of the last run whose files were committed.
+	RunID     string    `json:"runId"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// loadState reads the state of the report. A missing state means that the report
+// starts from scratch.
+func loadState(outputPath string) (*reportState, error) {
+	data, err := os.ReadFile(filepath.Join(outputPath, stateFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return &reportState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state reportState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// saveState writes the state of the report atomically.
+func saveState(outputPath string, state *reportState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(outputPath, stateFilename)
+	tmp := path + tmpSuffix
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
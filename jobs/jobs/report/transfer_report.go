@@ -2,16 +2,14 @@ package report
 
 import (
 	"context"
-	"encoding/csv"
-	"fmt"
 	"math/big"
 	"os"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/export"
 	"github.com/wormhole-foundation/wormhole-explorer/common/prices"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.mongodb.org/mongo-driver/bson"
@@ -19,12 +17,44 @@ import (
 	"go.uber.org/zap"
 )
 
+// reconciliationColumns are appended to the transfer columns to reconcile the fees
+// paid on each side of a transfer and the time it took to be redeemed.
+var reconciliationColumns = []export.Column{
+	{Name: "sourceFee", Type: export.ColumnString},
+	{Name: "sourceFeeUSD", Type: export.ColumnFloat64, Optional: true},
+	{Name: "destinationFee", Type: export.ColumnString},
+	{Name: "destinationFeeUSD", Type: export.ColumnFloat64, Optional: true},
+	{Name: "redemptionDelaySeconds", Type: export.ColumnInt64, Optional: true},
+}
+
+// reportColumns are the columns of the transfer report.
+var reportColumns = append(append([]export.Column{}, export.TransferColumns...), reconciliationColumns...)
+
+var alphanumericRegexp = regexp.MustCompile(`^[A-Za-z0-9]*$`)
+
+// priceRetryWindow is the time during which a transfer whose price could not be fetched is
+// retried by the next runs. Older transfers are written without the USD amount.
+const priceRetryWindow = 24 * time.Hour
+
+// TransferReportJob writes the transfers into a dataset partitioned by day and app id.
+//
+// Each run appends the transfers indexed since the previous run: the progress is stored
+// in a watermark file in the output path, and every run writes new files to the partitions
+// it touches. VAAs indexed in the last settleDelay are left for the next run, so that the
+// parser and the tx-tracker have time to process them.
+//
+// The transfers redeemed after they were reported are written again, with the destination
+// transaction, to the partitions of the run that indexed the redemption: readers keep the
+// record of the latest run of each vaaId.
 type TransferReportJob struct {
 	database       *mongo.Database
 	pageSize       int64
 	logger         *zap.Logger
 	getPriceByTime GetPriceByTimeFn
 	outputPath     string
+	formats        []export.Format
+	appIDs         map[string]bool
+	settleDelay    time.Duration
 	tokenProvider  *domain.TokenProvider
 }
 
@@ -48,184 +78,385 @@ type transactionResult struct {
 	AppIds                     []string    `bson:"appIds" json:"appIds"`
 	PortalPayloadType          int         `bson:"portalPayloadType" json:"portalPayloadType"`
 	SemanticDestinationAddress string      `bson:"semanticDestinationAddress" json:"semanticDestinationAddress"`
+	IndexedAt                  time.Time   `bson:"indexedAt" json:"indexedAt"`
+	SourceFee                  string      `bson:"sourceFee" json:"sourceFee"`
+	SourceFeeUSD               string      `bson:"sourceFeeUSD" json:"sourceFeeUSD"`
+	DestinationFee             string      `bson:"destinationFee" json:"destinationFee"`
+	DestinationFeeUSD          string      `bson:"destinationFeeUSD" json:"destinationFeeUSD"`
+	DestinationTimestamp       *time.Time  `bson:"destinationTimestamp" json:"destinationTimestamp"`
+	DestinationUpdatedAt       *time.Time  `bson:"destinationUpdatedAt" json:"destinationUpdatedAt"`
 }
 
 type GetPriceByTimeFn func(ctx context.Context, coingeckoID string, day time.Time) (decimal.Decimal, error)
 
 // NewTransferReportJob creates a new transfer report job.
-func NewTransferReportJob(database *mongo.Database, pageSize int64, getPriceByTime GetPriceByTimeFn, outputPath string, tokenProvider *domain.TokenProvider, logger *zap.Logger) *TransferReportJob {
-	return &TransferReportJob{database: database, pageSize: pageSize, getPriceByTime: getPriceByTime, outputPath: outputPath, tokenProvider: tokenProvider, logger: logger}
+//
+// When appIDs is not empty, only the transfers of those apps are reported.
+func NewTransferReportJob(database *mongo.Database, pageSize int64, getPriceByTime GetPriceByTimeFn, outputPath string,
+	formats []export.Format, appIDs []string, settleDelay time.Duration, tokenProvider *domain.TokenProvider, logger *zap.Logger) *TransferReportJob {
+	scope := make(map[string]bool, len(appIDs))
+	for _, appID := range appIDs {
+		scope[appID] = true
+	}
+	return &TransferReportJob{
+		database:       database,
+		pageSize:       pageSize,
+		getPriceByTime: getPriceByTime,
+		outputPath:     outputPath,
+		formats:        formats,
+		appIDs:         scope,
+		settleDelay:    settleDelay,
+		tokenProvider:  tokenProvider,
+		logger:         logger,
+	}
 }
 
 // Run runs the transfer report job.
 func (j *TransferReportJob) Run(ctx context.Context) error {
 
-	file, err := os.Create(j.outputPath)
+	if err := os.MkdirAll(j.outputPath, 0o755); err != nil {
+		return err
+	}
+	state, err := loadState(j.outputPath)
 	if err != nil {
 		return err
 	}
+	if err := removeUncommittedFiles(j.outputPath, state.RunID); err != nil {
+		return err
+	}
 
-	writer := csv.NewWriter(file)
+	now := time.Now().UTC()
+	until := now.Add(-j.settleDelay)
+	runID := now.Format(runIDLayout)
+
+	updates := state.Updates
+	if updates.UpdatedAt.IsZero() {
+		// re-export the redemptions indexed since the end of the last run, a new report
+		// already includes every redemption indexed until now.
+		updates.UpdatedAt = until
+		if !state.UpdatedAt.IsZero() {
+			updates.UpdatedAt = state.UpdatedAt.Add(-j.settleDelay)
+		}
+	}
 
-	_ = j.writeHeader(writer)
+	j.logger.Info("Starting transfer report",
+		zap.String("runId", runID),
+		zap.Time("watermark", state.Watermark.IndexedAt),
+		zap.Time("updatesWatermark", updates.UpdatedAt),
+		zap.Time("until", until))
 
-	defer file.Close()
+	writer := newPartitionWriter(j.outputPath, runID, j.formats, reportColumns)
+	wm, count, err := j.exportIndexed(ctx, writer, state.Watermark, until)
+	if err != nil {
+		writer.Abort()
+		return err
+	}
+	updates, updated, err := j.exportRedeemed(ctx, writer, updates, state.Watermark, until)
+	if err != nil {
+		writer.Abort()
+		return err
+	}
 
-	//start backfilling
-	page := int64(0)
-	for {
-		j.logger.Info("Processing page", zap.Int64("page", page))
+	if err := writer.Commit(); err != nil {
+		writer.Abort()
+		return err
+	}
+	state.Watermark = wm
+	state.Updates = updates
+	state.RunID = runID
+	state.UpdatedAt = now
+	if err := saveState(j.outputPath, state); err != nil {
+		return err
+	}
+
+	j.logger.Info("Transfer report completed",
+		zap.String("runId", runID),
+		zap.Int64("records", count),
+		zap.Int64("updatedRecords", updated))
+	return nil
+}
 
-		trxs, err := j.findTransactionsByPage(ctx, page, j.pageSize)
+// exportIndexed writes the transfers indexed after the watermark and before until.
+//
+// It stops at the first transfer whose price could not be fetched, so that the next run
+// retries it, and returns the watermark of the last transfer written.
+func (j *TransferReportJob) exportIndexed(ctx context.Context, writer *partitionWriter, wm watermark, until time.Time) (watermark, int64, error) {
+	var count int64
+	for {
+		trxs, err := j.findTransactions(ctx, wm, until, j.pageSize)
 		if err != nil {
 			j.logger.Error("Failed to get transactions", zap.Error(err))
-			break
+			return wm, count, err
 		}
-
 		if len(trxs) == 0 {
-			j.logger.Info("Empty page", zap.Int64("page", page))
-			break
+			return wm, count, nil
 		}
-		for _, t := range trxs {
 
-			log := j.logger.With(zap.String("id", t.ID))
+		for _, t := range trxs {
+			record, err := j.toRecord(ctx, &t)
+			if err != nil {
+				j.logger.Warn("Stopping at a transfer without price, it is retried by the next run",
+					zap.String("id", t.ID), zap.Error(err))
+				return wm, count, nil
+			}
+			n, err := j.write(writer, &t, record)
+			if err != nil {
+				return wm, count, err
+			}
+			count += n
+			wm = watermark{IndexedAt: t.IndexedAt, ID: t.ID}
+		}
+		j.logger.Info("Processed page", zap.Int("size", len(trxs)), zap.Time("watermark", wm.IndexedAt))
+	}
+}
 
-			log.Debug("Processing transaction")
+// exportRedeemed writes again the transfers reported before the given watermark whose
+// destination transaction was updated after the updates watermark and before until.
+//
+// The transfers not reported yet are skipped, they are written with their redemption when
+// they are indexed. Like exportIndexed, it stops at the first transfer without price.
+func (j *TransferReportJob) exportRedeemed(ctx context.Context, writer *partitionWriter, updates updatesWatermark,
+	reported watermark, until time.Time) (updatesWatermark, int64, error) {
+	var count int64
+	for {
+		trxs, err := j.findRedeemedTransactions(ctx, updates, until, j.pageSize)
+		if err != nil {
+			j.logger.Error("Failed to get redeemed transactions", zap.Error(err))
+			return updates, count, err
+		}
+		if len(trxs) == 0 {
+			return updates, count, nil
+		}
 
-			if t.TokenAddressHexa == "" {
-				j.writeRecord(t, t.Amount, nil, nil, writer)
+		for _, t := range trxs {
+			next := updatesWatermark{ID: t.ID}
+			if t.DestinationUpdatedAt != nil {
+				next.UpdatedAt = *t.DestinationUpdatedAt
+			}
+			// the transactions without VAA have nothing to report.
+			if t.IndexedAt.IsZero() || !reported.includes(t.IndexedAt, t.ID) {
+				updates = next
 				continue
 			}
-
-			tokenAddress, err := sdk.StringToAddress(t.TokenAddressHexa)
+			record, err := j.toRecord(ctx, &t)
 			if err != nil {
-				log.Error("Failed to get transactions",
-					zap.String("tokenAddressHexa", t.TokenAddressHexa),
-					zap.Error(err))
-				continue
+				j.logger.Warn("Stopping at a redeemed transfer without price, it is retried by the next run",
+					zap.String("id", t.ID), zap.Error(err))
+				return updates, count, nil
 			}
-
-			m, ok := j.tokenProvider.GetTokenByAddress(sdk.ChainID(t.TokenChain), tokenAddress.String())
-			if ok {
-				tokenPrice, err := j.getPriceByTime(ctx, m.CoingeckoID, t.Timestamp)
-				if err != nil {
-					log.Error("Failed to get token price",
-						zap.String("coingeckoId", m.CoingeckoID),
-						zap.String("timestamp", t.Timestamp.UTC().Format(time.RFC3339)),
-						zap.Error(err))
-					continue
-				}
-				if t.Amount == "" {
-					j.writeRecord(t, t.Amount, nil, nil, writer)
-					continue
-				}
-				amount := new(big.Int)
-				amount, ok := amount.SetString(t.Amount, 10)
-				if !ok {
-					log.Error("amount is not a number",
-						zap.String("amount", t.Amount),
-					)
-					j.writeRecord(t, "", nil, nil, writer)
-					continue
-				}
-
-				priceUSD := prices.CalculatePriceUSD(tokenPrice, amount, m.Decimals)
-
-				j.writeRecord(t, t.Amount, m, &priceUSD, writer)
-			} else {
-				j.writeRecord(t, t.Amount, nil, nil, writer)
+			n, err := j.write(writer, &t, record)
+			if err != nil {
+				return updates, count, err
 			}
+			count += n
+			updates = next
+		}
+		j.logger.Info("Processed page of redeemed transfers", zap.Int("size", len(trxs)), zap.Time("watermark", updates.UpdatedAt))
+	}
+}
 
+// write writes the record of a transfer to the partition of each of its apps.
+func (j *TransferReportJob) write(writer *partitionWriter, trx *transactionResult, record []any) (int64, error) {
+	var count int64
+	for _, appID := range j.partitionAppIDs(trx.AppIds) {
+		if err := writer.Write(partitionDir(trx.Timestamp, appID), record); err != nil {
+			return count, err
 		}
-		writer.Flush()
-		page++
+		count++
 	}
-	return nil
+	return count, nil
 }
 
-func (j *TransferReportJob) writeRecord(trx transactionResult, fAmount string, m *domain.TokenMetadata, priceUSD *decimal.Decimal, file *csv.Writer) error {
-	var notionalUSD, decimals, symbol, coingeckoID, tokenAddress string
-	if m != nil {
-		decimals = fmt.Sprintf("%d", m.Decimals)
-		symbol = m.Symbol.String()
-		coingeckoID = m.CoingeckoID
+// partitionAppIDs returns the app partitions of a transfer. A transfer with several app ids
+// is written to the partition of each app, so every partition holds all the transfers of its app.
+func (j *TransferReportJob) partitionAppIDs(appIDs []string) []string {
+	if len(j.appIDs) == 0 {
+		if len(appIDs) == 0 {
+			return []string{noAppID}
+		}
+		return appIDs
+	}
+	var result []string
+	for _, appID := range appIDs {
+		if j.appIDs[appID] {
+			result = append(result, appID)
+		}
+	}
+	return result
+}
+
+// toRecord converts a transaction into a report record.
+//
+// It returns an error when the price of the token could not be fetched and the transfer was
+// indexed less than priceRetryWindow ago, older transfers are written without the USD amount.
+func (j *TransferReportJob) toRecord(ctx context.Context, trx *transactionResult) ([]any, error) {
+
+	t := export.Transfer{
+		VaaID:               trx.ID,
+		VaaHash:             trx.VaaHash,
+		SourceChain:         trx.SourceChain,
+		EmitterAddress:      trx.EmitterAddress,
+		Sequence:            trx.Sequence,
+		Timestamp:           trx.Timestamp,
+		SourceTxHash:        trx.SourceTxHash,
+		SourceSenderAddress: trx.SourceSenderAddress,
+		DestinationChain:    trx.DestinationChain,
+		DestinationAddress:  trx.DestinationAddress,
+		DestinationTxHash:   trx.DestinationTxHash,
+		PortalPayloadType:   trx.PortalPayloadType,
+		AppIDs:              trx.AppIds,
+		TokenChain:          trx.TokenChain,
+		TokenAddress:        trx.TokenAddress,
+		Amount:              trx.Amount,
+		Fee:                 trx.Fee,
+	}
+	if !alphanumericRegexp.MatchString(t.TokenAddress) {
+		t.TokenAddress, _ = domain.TranslateEmitterAddress(trx.TokenChain, trx.TokenAddressHexa)
 	}
-	if priceUSD != nil {
-		notionalUSD = priceUSD.Truncate(10).String()
+	if err := j.setTokenDetails(ctx, trx, &t); err != nil && time.Since(trx.IndexedAt) < priceRetryWindow {
+		return nil, err
 	}
 
-	tokenAddress = trx.TokenAddress
-	if !regexp.MustCompile(`^[A-Za-z0-9]*$`).MatchString(tokenAddress) {
-		tokenAddress, _ = domain.TranslateEmitterAddress(trx.TokenChain, trx.TokenAddressHexa)
+	var redemptionDelay any
+	if trx.DestinationTimestamp != nil {
+		redemptionDelay = int64(trx.DestinationTimestamp.Sub(trx.Timestamp).Seconds())
 	}
 
-	var record []string
-	record = append(record, trx.ID)
-	record = append(record, trx.VaaHash)
-	record = append(record, chainIDToCsv(trx.SourceChain))
-	record = append(record, trx.EmitterAddress)
-	record = append(record, trx.Sequence)
-	record = append(record, trx.Timestamp.Format(time.RFC3339))
-	record = append(record, trx.SourceTxHash)
-	record = append(record, trx.SourceSenderAddress)
-	record = append(record, chainIDToCsv(trx.DestinationChain))
-	record = append(record, trx.DestinationAddress)
-	record = append(record, trx.DestinationTxHash)
-	record = append(record, portalPayloadTypeToCsv(trx.PortalPayloadType))
-	record = append(record, appIdsToCsv(trx.AppIds))
-	record = append(record, chainIDToCsv(trx.TokenChain))
-	record = append(record, tokenAddress)
-	record = append(record, fAmount)
-	record = append(record, decimals)
-	record = append(record, notionalUSD)
-	record = append(record, trx.Fee)
-	record = append(record, coingeckoID)
-	record = append(record, symbol)
-	return file.Write(record)
+	return append(t.Record(),
+		trx.SourceFee,
+		decimalValue(trx.SourceFeeUSD),
+		trx.DestinationFee,
+		decimalValue(trx.DestinationFeeUSD),
+		redemptionDelay,
+	), nil
 }
 
-func (*TransferReportJob) writeHeader(writer *csv.Writer) error {
-	var record []string
-	record = append(record, "vaaId")
-	record = append(record, "vaaHash")
-	record = append(record, "sourceChain")
-	record = append(record, "emitterAddress")
-	record = append(record, "sequence")
-	record = append(record, "timestamp")
-	record = append(record, "sourceTxHash")
-	record = append(record, "sourceSenderAddress")
-	record = append(record, "destinationChain")
-	record = append(record, "destinationAddress")
-	record = append(record, "destinationTxHash")
-	record = append(record, "portalPayloadType")
-	record = append(record, "appIds")
-	record = append(record, "tokenChain")
-	record = append(record, "tokenAddress")
-	record = append(record, "amount")
-	record = append(record, "decimals")
-	record = append(record, "notionalUSD")
-	record = append(record, "fee")
-	record = append(record, "coinGeckoId")
-	record = append(record, "symbol")
-	return writer.Write(record)
+// setTokenDetails sets the token metadata and the USD amount of a transfer.
+// It returns an error when the price of the token could not be fetched.
+func (j *TransferReportJob) setTokenDetails(ctx context.Context, trx *transactionResult, t *export.Transfer) error {
+
+	if trx.TokenAddressHexa == "" {
+		return nil
+	}
+
+	log := j.logger.With(zap.String("id", trx.ID))
+
+	tokenAddress, err := sdk.StringToAddress(trx.TokenAddressHexa)
+	if err != nil {
+		log.Error("Failed to parse token address",
+			zap.String("tokenAddressHexa", trx.TokenAddressHexa),
+			zap.Error(err))
+		return nil
+	}
+
+	m, ok := j.tokenProvider.GetTokenByAddress(trx.TokenChain, tokenAddress.String())
+	if !ok {
+		return nil
+	}
+	decimals := m.Decimals
+	t.Decimals = &decimals
+	t.Symbol = m.Symbol.String()
+	t.CoingeckoID = m.CoingeckoID
+
+	if trx.Amount == "" {
+		return nil
+	}
+	amount, ok := new(big.Int).SetString(trx.Amount, 10)
+	if !ok {
+		log.Error("amount is not a number", zap.String("amount", trx.Amount))
+		t.Amount = ""
+		return nil
+	}
+
+	tokenPrice, err := j.getPriceByTime(ctx, m.CoingeckoID, trx.Timestamp)
+	if err != nil {
+		log.Error("Failed to get token price",
+			zap.String("coingeckoId", m.CoingeckoID),
+			zap.String("timestamp", trx.Timestamp.UTC().Format(time.RFC3339)),
+			zap.Error(err))
+		return err
+	}
+
+	notionalUSD, _ := prices.CalculatePriceUSD(tokenPrice, amount, m.Decimals).Truncate(10).Float64()
+	t.NotionalUSD = &notionalUSD
+	return nil
 }
 
-func (j *TransferReportJob) findTransactionsByPage(ctx context.Context, page, pageSize int64) ([]transactionResult, error) {
+// findTransactions returns the next page of transactions indexed after the watermark and before until.
+func (j *TransferReportJob) findTransactions(ctx context.Context, wm watermark, until time.Time, pageSize int64) ([]transactionResult, error) {
 
 	vaas := j.database.Collection("vaas")
 
-	skip := page * pageSize
-
 	// Build the aggregation pipeline
 	var pipeline mongo.Pipeline
 
+	pipeline = append(pipeline, bson.D{
+		{Key: "$match", Value: bson.D{
+			{Key: "indexedAt", Value: bson.M{"$lt": until}},
+			{Key: "$or", Value: bson.A{
+				bson.M{"indexedAt": bson.M{"$gt": wm.IndexedAt}},
+				bson.M{"indexedAt": wm.IndexedAt, "_id": bson.M{"$gt": wm.ID}},
+			}},
+		}},
+	})
+
 	pipeline = append(pipeline, bson.D{
 		{Key: "$sort", Value: bson.D{
-			bson.E{Key: "timestamp", Value: -1},
-			bson.E{Key: "_id", Value: -1},
+			bson.E{Key: "indexedAt", Value: 1},
+			bson.E{Key: "_id", Value: 1},
 		}},
 	})
 
+	// Limit size of results
+	pipeline = append(pipeline, bson.D{
+		{Key: "$limit", Value: pageSize},
+	})
+
+	return j.aggregate(ctx, vaas, append(pipeline, transferStages()...))
+}
+
+// findRedeemedTransactions returns the next page of transactions whose destination transaction
+// was updated after the updates watermark and before until.
+func (j *TransferReportJob) findRedeemedTransactions(ctx context.Context, wm updatesWatermark, until time.Time, pageSize int64) ([]transactionResult, error) {
+
+	globalTransactions := j.database.Collection("globalTransactions")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "destinationTx.updatedAt", Value: bson.M{"$lt": until}},
+			{Key: "$or", Value: bson.A{
+				bson.M{"destinationTx.updatedAt": bson.M{"$gt": wm.UpdatedAt}},
+				bson.M{"destinationTx.updatedAt": wm.UpdatedAt, "_id": bson.M{"$gt": wm.ID}},
+			}},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			bson.E{Key: "destinationTx.updatedAt", Value: 1},
+			bson.E{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$limit", Value: pageSize}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "vaas"},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "vaas"},
+		}}},
+		// keep the transactions without VAA, so that the watermark moves past them.
+		{{Key: "$replaceRoot", Value: bson.M{
+			"newRoot": bson.M{"$mergeObjects": bson.A{
+				bson.M{"_id": "$_id"},
+				bson.M{"$arrayElemAt": bson.A{"$vaas", 0}},
+			}},
+		}}},
+	}
+
+	return j.aggregate(ctx, globalTransactions, append(pipeline, transferStages()...))
+}
+
+// transferStages returns the stages that join a VAA with its parsed VAA and its transaction
+// and project the fields of the report.
+func transferStages() mongo.Pipeline {
+	var pipeline mongo.Pipeline
+
 	pipeline = append(pipeline, bson.D{
 		{Key: "$lookup", Value: bson.D{
 			{Key: "from", Value: "parsedVaa"},
@@ -244,16 +475,6 @@ func (j *TransferReportJob) findTransactionsByPage(ctx context.Context, page, pa
 		}},
 	})
 
-	// Skip initial results
-	pipeline = append(pipeline, bson.D{
-		{Key: "$skip", Value: skip},
-	})
-
-	// Limit size of results
-	pipeline = append(pipeline, bson.D{
-		{Key: "$limit", Value: pageSize},
-	})
-
 	// add nested fields
 	pipeline = append(pipeline, bson.D{
 		{Key: "$addFields", Value: bson.D{
@@ -283,10 +504,23 @@ func (j *TransferReportJob) findTransactionsByPage(ctx context.Context, page, pa
 			{Key: "timestamp", Value: "$timestamp"},
 			{Key: "tokenAddressHexa", Value: "$parsedPayload.tokenAddress"},
 			{Key: "portalPayloadType", Value: "$parsedPayload.payloadType"},
+			{Key: "indexedAt", Value: "$indexedAt"},
+			{Key: "sourceFee", Value: "$globalTransactions.originTx.feeDetail.fee"},
+			{Key: "sourceFeeUSD", Value: "$globalTransactions.originTx.feeDetail.feeUSD"},
+			{Key: "destinationFee", Value: "$globalTransactions.destinationTx.feeDetail.fee"},
+			{Key: "destinationFeeUSD", Value: "$globalTransactions.destinationTx.feeDetail.feeUSD"},
+			{Key: "destinationTimestamp", Value: "$globalTransactions.destinationTx.timestamp"},
+			{Key: "destinationUpdatedAt", Value: "$globalTransactions.destinationTx.updatedAt"},
 		}}})
 
+	return pipeline
+}
+
+// aggregate executes the pipeline on the collection and decodes the transactions.
+func (j *TransferReportJob) aggregate(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline) ([]transactionResult, error) {
+
 	// Execute the aggregation pipeline
-	cur, err := vaas.Aggregate(ctx, pipeline)
+	cur, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		j.logger.Error("failed execute aggregation pipeline", zap.Error(err))
 		return nil, err
@@ -303,24 +537,12 @@ func (j *TransferReportJob) findTransactionsByPage(ctx context.Context, page, pa
 	return documents, nil
 }
 
-func chainIDToCsv(chainID sdk.ChainID) string {
-
-	if chainID.String() == sdk.ChainIDUnset.String() {
-		return ""
-	}
-	return fmt.Sprintf("%d", int16(chainID))
-}
-
-func appIdsToCsv(appIds []string) string {
-	if len(appIds) == 0 {
-		return ""
-	}
-	return strings.Join(appIds, "|")
-}
-
-func portalPayloadTypeToCsv(portalPayloadType int) string {
-	if portalPayloadType == 0 {
-		return ""
+// decimalValue converts a decimal string into a report value, nil when it is empty or invalid.
+func decimalValue(s string) any {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return nil
 	}
-	return fmt.Sprintf("%d", portalPayloadType)
+	f, _ := d.Float64()
+	return f
 }
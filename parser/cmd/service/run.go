@@ -11,19 +11,22 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/go-redis/redis/v8"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbutil"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	"github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/config"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/consumer"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/http/infrastructure"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/http/vaa"
 	parserAlert "github.com/wormhole-foundation/wormhole-explorer/parser/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
-	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/sqs"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/migration"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/processor"
@@ -57,6 +60,18 @@ func Run() {
 
 	logger.Info("Starting wormhole-explorer-parser ...")
 
+	// set up tracing
+	shutdownTracing, err := telemetry.Setup(rootCtx, telemetry.Config{
+		ServiceName:  "wormhole-explorer-parser",
+		Environment:  config.Environment,
+		OtlpEndpoint: config.TracingOtlpEndpoint,
+		OtlpInsecure: config.TracingOtlpInsecure,
+		SampleRatio:  config.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+
 	// setup DB connection
 	db, err := dbutil.Connect(rootCtx, logger, config.MongoURI, config.MongoDatabase, false)
 	if err != nil {
@@ -137,6 +152,13 @@ func Run() {
 	logger.Info("closing MongoDB connection...")
 	db.DisconnectWithTimeout(10 * time.Second)
 
+	logger.Info("flushing traces...")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", zap.Error(err))
+	}
+	shutdownCancel()
+
 	logger.Info("Closing Http server ...")
 	server.Stop()
 
@@ -171,6 +193,10 @@ func newAwsConfig(appCtx context.Context, cfg *config.ServiceConfiguration) (aws
 }
 
 func newVAAConsume(appCtx context.Context, config *config.ServiceConfiguration, metrics metrics.Metrics, logger *zap.Logger) queue.ConsumeFunc {
+	if config.ConsumerQueue == "redis-stream" {
+		return newRedisStreamConsume(appCtx, config, config.PipelineRedisStream, queue.NewVaaConverter(logger), metrics, logger)
+	}
+
 	sqsConsumer, err := newSQSConsumer(appCtx, config, config.PipelineSQSUrl)
 	if err != nil {
 		logger.Fatal("failed to create sqs consumer", zap.Error(err))
@@ -182,6 +208,10 @@ func newVAAConsume(appCtx context.Context, config *config.ServiceConfiguration,
 }
 
 func newNotificationConsume(appCtx context.Context, config *config.ServiceConfiguration, metrics metrics.Metrics, logger *zap.Logger) queue.ConsumeFunc {
+	if config.ConsumerQueue == "redis-stream" {
+		return newRedisStreamConsume(appCtx, config, config.NotificationsRedisStream, queue.NewNotificationEvent(logger), metrics, logger)
+	}
+
 	sqsConsumer, err := newSQSConsumer(appCtx, config, config.NotificationsSQSUrl)
 	if err != nil {
 		logger.Fatal("failed to create sqs consumer", zap.Error(err))
@@ -192,6 +222,22 @@ func newNotificationConsume(appCtx context.Context, config *config.ServiceConfig
 	return vaaQueue.Consume
 }
 
+func newRedisStreamConsume(appCtx context.Context, config *config.ServiceConfiguration, stream string, converter queue.ConverterFunc, metrics metrics.Metrics, logger *zap.Logger) queue.ConsumeFunc {
+	client := redis.NewClient(&redis.Options{Addr: config.RedisStreamUri})
+	consumer, err := redisstream.NewConsumer(appCtx, client, stream, config.RedisStreamGroup,
+		redisstream.WithMaxMessages(10),
+		redisstream.WithVisibilityTimeout(120*time.Second),
+		redisstream.WithMaxDeliveries(config.RedisStreamMaxDeliveries),
+		redisstream.WithLogger(logger))
+	if err != nil {
+		logger.Fatal("failed to create redis stream consumer", zap.Error(err))
+	}
+
+	filterConsumeFunc := newFilterFunc(config)
+	vaaQueue := queue.NewEventRedisStream(consumer, converter, filterConsumeFunc, metrics, logger)
+	return vaaQueue.Consume
+}
+
 // Create a new SQS consumer.
 func newSQSConsumer(appCtx context.Context, config *config.ServiceConfiguration, sqsUrl string) (*sqs.Consumer, error) {
 	awsconfig, err := newAwsConfig(appCtx, config)
@@ -240,6 +286,11 @@ func newHealthChecks(
 	db *mongo.Database,
 ) ([]health.Check, error) {
 
+	if config.ConsumerQueue == "redis-stream" {
+		client := redis.NewClient(&redis.Options{Addr: config.RedisStreamUri})
+		return []health.Check{health.Redis(client), health.Mongo(db)}, nil
+	}
+
 	awsConfig, err := newAwsConfig(ctx, config)
 	if err != nil {
 		return nil, err
@@ -17,25 +17,34 @@ const (
 
 // ServiceConfiguration represents the application configuration when running as service with default values.
 type ServiceConfiguration struct {
-	Environment             string `env:"ENVIRONMENT,required"`
-	LogLevel                string `env:"LOG_LEVEL,default=INFO"`
-	Port                    string `env:"PORT,default=8000"`
-	ConsumerMode            string `env:"CONSUMER_MODE,default=QUEUE"`
-	MongoURI                string `env:"MONGODB_URI,required"`
-	MongoDatabase           string `env:"MONGODB_DATABASE,required"`
-	AwsEndpoint             string `env:"AWS_ENDPOINT"`
-	AwsAccessKeyID          string `env:"AWS_ACCESS_KEY_ID"`
-	AwsSecretAccessKey      string `env:"AWS_SECRET_ACCESS_KEY"`
-	AwsRegion               string `env:"AWS_REGION"`
-	PipelineSQSUrl          string `env:"PIPELINE_SQS_URL"`
-	NotificationsSQSUrl     string `env:"NOTIFICATIONS_SQS_URL"`
-	VaaPayloadParserURL     string `env:"VAA_PAYLOAD_PARSER_URL, required"`
-	VaaPayloadParserTimeout int64  `env:"VAA_PAYLOAD_PARSER_TIMEOUT, required"`
-	PprofEnabled            bool   `env:"PPROF_ENABLED,default=false"`
-	P2pNetwork              string `env:"P2P_NETWORK,required"`
-	AlertEnabled            bool   `env:"ALERT_ENABLED,default=false"`
-	AlertApiKey             string `env:"ALERT_API_KEY"`
-	MetricsEnabled          bool   `env:"METRICS_ENABLED,default=false"`
+	Environment              string  `env:"ENVIRONMENT,required"`
+	LogLevel                 string  `env:"LOG_LEVEL,default=INFO"`
+	TracingOtlpEndpoint      string  `env:"TRACING_OTLP_ENDPOINT"`
+	TracingOtlpInsecure      bool    `env:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio       float64 `env:"TRACING_SAMPLE_RATIO,default=1"`
+	Port                     string  `env:"PORT,default=8000"`
+	ConsumerMode             string  `env:"CONSUMER_MODE,default=QUEUE"`
+	ConsumerQueue            string  `env:"CONSUMER_QUEUE,default=sqs"`
+	MongoURI                 string  `env:"MONGODB_URI,required"`
+	MongoDatabase            string  `env:"MONGODB_DATABASE,required"`
+	AwsEndpoint              string  `env:"AWS_ENDPOINT"`
+	AwsAccessKeyID           string  `env:"AWS_ACCESS_KEY_ID"`
+	AwsSecretAccessKey       string  `env:"AWS_SECRET_ACCESS_KEY"`
+	AwsRegion                string  `env:"AWS_REGION"`
+	PipelineSQSUrl           string  `env:"PIPELINE_SQS_URL"`
+	NotificationsSQSUrl      string  `env:"NOTIFICATIONS_SQS_URL"`
+	RedisStreamUri           string  `env:"REDIS_STREAM_URI"`
+	PipelineRedisStream      string  `env:"PIPELINE_REDIS_STREAM"`
+	NotificationsRedisStream string  `env:"NOTIFICATIONS_REDIS_STREAM"`
+	RedisStreamGroup         string  `env:"REDIS_STREAM_GROUP,default=parser"`
+	RedisStreamMaxDeliveries int64   `env:"REDIS_STREAM_MAX_DELIVERIES,default=10"`
+	VaaPayloadParserURL      string  `env:"VAA_PAYLOAD_PARSER_URL, required"`
+	VaaPayloadParserTimeout  int64   `env:"VAA_PAYLOAD_PARSER_TIMEOUT, required"`
+	PprofEnabled             bool    `env:"PPROF_ENABLED,default=false"`
+	P2pNetwork               string  `env:"P2P_NETWORK,required"`
+	AlertEnabled             bool    `env:"ALERT_ENABLED,default=false"`
+	AlertApiKey              string  `env:"ALERT_API_KEY"`
+	MetricsEnabled           bool    `env:"METRICS_ENABLED,default=false"`
 }
 
 // BackfillerConfiguration represents the application configuration when running as backfiller with default values.
@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"context"
+	"errors"
 
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/processor"
@@ -10,6 +11,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// errEventExpired is the error of the events whose visibility timeout expired before being processed.
+var errEventExpired = errors.New("event expired")
+
 // Consumer consumer struct definition.
 type Consumer struct {
 	consume queue.ConsumeFunc
@@ -35,7 +39,7 @@ func (c *Consumer) Start(ctx context.Context) {
 			if msg.IsExpired() {
 				c.metrics.IncExpiredMessage(emitterChainID, event.Source)
 				c.logger.Warn("Event expired", zap.String("id", event.ID))
-				msg.Failed()
+				msg.Failed(errEventExpired)
 				continue
 			}
 
@@ -43,14 +47,14 @@ func (c *Consumer) Start(ctx context.Context) {
 				TrackID: event.TrackID,
 				Vaa:     event.Vaa,
 			}
-			_, err := c.process(ctx, params)
+			_, err := c.process(msg.Context(), params)
 			if err != nil {
 				c.metrics.IncUnprocessedMessage(emitterChainID, event.Source)
 				c.logger.Error("Error processing event",
 					zap.String("trackId", event.TrackID),
 					zap.String("id", event.ID),
 					zap.Error(err))
-				msg.Failed()
+				msg.Failed(err)
 				continue
 			} else {
 				c.metrics.IncProcessedMessage(emitterChainID, event.Source)
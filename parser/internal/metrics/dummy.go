@@ -49,3 +49,6 @@ func (p *DummyMetrics) IncProcessedMessage(chain, source string) {}
 
 // VaaProcessingDuration increments the duration of VAA processing.
 func (m *DummyMetrics) VaaProcessingDuration(chain string, start *time.Time) {}
+
+// SetStreamLag sets the lag and the dead letters of the consumer group of a redis stream.
+func (m *DummyMetrics) SetStreamLag(stream string, lag time.Duration, deadLetters int64) {}
@@ -22,4 +22,5 @@ type Metrics interface {
 	IncProcessedMessage(chain, source string)
 
 	VaaProcessingDuration(chain string, start *time.Time)
+	SetStreamLag(stream string, lag time.Duration, deadLetters int64)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -24,6 +25,22 @@ func Run(db *mongo.Database) error {
 		return err
 	}
 
+	// create index in addressLinks collection by representations.
+	indexRepresentations := mongo.IndexModel{Keys: bson.D{{Key: "representations", Value: 1}}}
+	_, err = db.Collection(repository.AddressLinks).Indexes().CreateOne(context.TODO(), indexRepresentations)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create indexes in addressGraph collection by sender and recipient.
+	for _, key := range []string{"from", "to"} {
+		index := mongo.IndexModel{Keys: bson.D{{Key: key, Value: 1}}}
+		_, err = db.Collection(repository.AddressGraph).Indexes().CreateOne(context.TODO(), index)
+		if err != nil && isNotAlreadyExistsError(err) {
+			return err
+		}
+	}
+
 	return nil
 }
 
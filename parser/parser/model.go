@@ -20,3 +20,22 @@ type ParsedVaaUpdate struct {
 	UpdatedAt                 *time.Time                              `bson:"updatedAt" json:"updatedAt"`
 	Timestamp                 time.Time                               `bson:"timestamp" json:"timestamp"`
 }
+
+// AddressLink contains every known representation of a universal address.
+type AddressLink struct {
+	ID              string        `bson:"_id" json:"id"`
+	Representations []string      `bson:"representations" json:"representations"`
+	Chains          []sdk.ChainID `bson:"chains" json:"chains"`
+	UpdatedAt       time.Time     `bson:"updatedAt" json:"updatedAt"`
+}
+
+// AddressEdge is a sender to recipient relationship seen in a transfer.
+type AddressEdge struct {
+	ID        string      `bson:"_id" json:"id"`
+	From      string      `bson:"from" json:"from"`
+	To        string      `bson:"to" json:"to"`
+	FromChain sdk.ChainID `bson:"fromChain" json:"fromChain"`
+	ToChain   sdk.ChainID `bson:"toChain" json:"toChain"`
+	VaaID     string      `bson:"vaaId" json:"vaaId"`
+	Timestamp time.Time   `bson:"timestamp" json:"timestamp"`
+}
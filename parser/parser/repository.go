@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -21,16 +22,22 @@ type Repository struct {
 	db          *mongo.Database
 	log         *zap.Logger
 	collections struct {
-		parsedVaa *mongo.Collection
+		parsedVaa    *mongo.Collection
+		addressLinks *mongo.Collection
+		addressGraph *mongo.Collection
 	}
 }
 
 // NewRepository create a new respository instance.
 func NewRepository(db *mongo.Database, log *zap.Logger) *Repository {
 	return &Repository{db, log, struct {
-		parsedVaa *mongo.Collection
+		parsedVaa    *mongo.Collection
+		addressLinks *mongo.Collection
+		addressGraph *mongo.Collection
 	}{
-		parsedVaa: db.Collection(ParsedVAACollection),
+		parsedVaa:    db.Collection(ParsedVAACollection),
+		addressLinks: db.Collection(repository.AddressLinks),
+		addressGraph: db.Collection(repository.AddressGraph),
 	}}
 }
 
@@ -48,6 +55,43 @@ func (s *Repository) UpsertParsedVaa(ctx context.Context, parsedVAA ParsedVaaUpd
 	return err
 }
 
+// UpsertAddressLinks adds the representations and chains of the addresses to their link documents
+// in a single bulk write.
+func (s *Repository) UpsertAddressLinks(ctx context.Context, links []AddressLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(links))
+	for _, link := range links {
+		update := bson.M{
+			"$addToSet": bson.M{
+				"representations": bson.M{"$each": link.Representations},
+				"chains":          bson.M{"$each": link.Chains},
+			},
+			"$set": bson.M{"updatedAt": link.UpdatedAt},
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: link.ID}}).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+
+	_, err := s.collections.addressLinks.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// UpsertAddressEdge saves a sender to recipient relationship.
+//
+// Edges are keyed by the vaa id, so reprocessing a VAA does not duplicate them.
+func (s *Repository) UpsertAddressEdge(ctx context.Context, edge AddressEdge) error {
+	update := bson.M{"$set": edge}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := s.collections.addressGraph.UpdateByID(ctx, edge.ID, update, opts)
+	return err
+}
+
 func indexedAt(t time.Time) IndexingTimestamps {
 	return IndexingTimestamps{
 		IndexedAt: t,
@@ -51,7 +51,7 @@ func (p *Processor) Process(ctx context.Context, params *Params) (*parser.Parsed
 	sequence := fmt.Sprintf("%d", vaa.Sequence)
 
 	p.metrics.IncVaaPayloadParserRequestCount(chainID)
-	vaaParseResponse, err := p.parser.ParseVaaWithStandarizedProperties(vaa)
+	vaaParseResponse, err := p.parser.ParseVaaWithStandarizedProperties(ctx, vaa)
 	if err != nil {
 		// split metrics error not found and others errors.
 		if errors.Is(err, vaaPayloadParser.ErrNotFound) {
@@ -124,10 +124,79 @@ func (p *Processor) Process(ctx context.Context, params *Params) (*parser.Parsed
 	}
 	p.metrics.IncVaaParsedInserted(chainID)
 
+	// link the sender and recipient addresses, failures are not critical for the parsed VAA.
+	p.linkAddresses(ctx, params.TrackID, &vaaParsed)
+
 	p.logger.Info("parsed VAA was successfully persisted", zap.String("trackId", params.TrackID), zap.String("id", vaaParsed.ID))
 	return &vaaParsed, nil
 }
 
+// linkAddresses stores every known representation of the sender and recipient of a VAA
+// and the relationship between them.
+func (p *Processor) linkAddresses(ctx context.Context, trackID string, vaaParsed *parser.ParsedVaaUpdate) {
+	sp := vaaParsed.StandardizedProperties
+	from := p.addressLink(trackID, vaaParsed.ID, sp.FromChain, sp.FromAddress)
+	to := p.addressLink(trackID, vaaParsed.ID, sp.ToChain, sp.ToAddress)
+
+	var links []parser.AddressLink
+	for _, link := range []*parser.AddressLink{from, to} {
+		if link != nil {
+			links = append(links, *link)
+		}
+	}
+	if err := p.repository.UpsertAddressLinks(ctx, links); err != nil {
+		p.logger.Error("Error inserting address links",
+			zap.String("trackId", trackID),
+			zap.String("vaaId", vaaParsed.ID),
+			zap.Error(err))
+	}
+	if from == nil || to == nil {
+		return
+	}
+
+	edge := parser.AddressEdge{
+		ID:        vaaParsed.ID,
+		From:      from.ID,
+		To:        to.ID,
+		FromChain: sp.FromChain,
+		ToChain:   sp.ToChain,
+		VaaID:     vaaParsed.ID,
+		Timestamp: vaaParsed.Timestamp,
+	}
+	if err := p.repository.UpsertAddressEdge(ctx, edge); err != nil {
+		p.logger.Error("Error inserting address edge",
+			zap.String("trackId", trackID),
+			zap.String("vaaId", vaaParsed.ID),
+			zap.Error(err))
+	}
+}
+
+// addressLink returns the link of a native address with its universal address, or nil when
+// the address cannot be converted.
+func (p *Processor) addressLink(trackID, vaaID string, chainID sdk.ChainID, address string) *parser.AddressLink {
+	if chainID == sdk.ChainIDUnset || address == "" {
+		return nil
+	}
+
+	universal, err := domain.ToUniversalAddress(chainID, address)
+	if err != nil {
+		p.logger.Debug("Address cannot be converted to universal address",
+			zap.String("trackId", trackID),
+			zap.String("vaaId", vaaID),
+			zap.String("address", address),
+			zap.Uint16("chain", uint16(chainID)),
+			zap.Error(err))
+		return nil
+	}
+
+	return &parser.AddressLink{
+		ID:              universal,
+		Representations: append(domain.AddressRepresentations(address), domain.AddressRepresentations(universal)...),
+		Chains:          []sdk.ChainID{chainID},
+		UpdatedAt:       time.Now(),
+	}
+}
+
 // transformStandarizedProperties transform amount and fee amount.
 func (p *Processor) transformStandarizedProperties(trackID, vaaID string, sp vaaPayloadParser.StandardizedProperties) vaaPayloadParser.StandardizedProperties {
 	// transform amount.
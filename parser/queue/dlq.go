@@ -0,0 +1,17 @@
+package queue
+
+import (
+	common_dlq "github.com/wormhole-foundation/wormhole-explorer/common/dlq"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// NewDlqDecoder creates a decoder of the messages of a dead-letter queue that uses the converter of the queue.
+func NewDlqDecoder(converter ConverterFunc) common_dlq.DecodeFunc {
+	return func(message string) (*common_dlq.Event, error) {
+		event, err := converter(message)
+		if err != nil || event == nil {
+			return nil, err
+		}
+		return &common_dlq.Event{ID: event.ID, ChainID: sdk.ChainID(event.ChainID)}, nil
+	}
+}
@@ -6,12 +6,17 @@ import (
 	"sync"
 	"time"
 
-	common_sqs "github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
+	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
-	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/sqs"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+var tracer = telemetry.Tracer("github.com/wormhole-foundation/wormhole-explorer/parser/queue")
+
 // SQSOption represents a VAA queue in SQS option function.
 type SQSOption func(*SQS)
 
@@ -110,16 +115,23 @@ func (q *SQS) Consume(ctx context.Context) <-chan ConsumerMessage {
 				}
 				q.metrics.IncVaaUnfiltered(event.ChainID)
 
+				// continue the trace of the producer of the message.
+				msgCtx, span := tracer.Start(sqsEvent.MessageAttributes.Extract(ctx), "parser.vaa.consume",
+					trace.WithSpanKind(trace.SpanKindConsumer),
+					trace.WithAttributes(telemetry.TrackIDKey.String(event.TrackID), telemetry.VaaIDKey.String(event.ID)))
+
 				q.wg.Add(1)
 				q.ch <- &sqsConsumerMessage{
 					id:            msg.ReceiptHandle,
+					msg:           msg,
 					data:          event,
 					wg:            &q.wg,
 					logger:        q.logger,
 					consumer:      q.consumer,
 					expiredAt:     expiredAt,
-					sentTimestamp: common_sqs.GetSentTimestamp(msg),
-					ctx:           ctx,
+					sentTimestamp: sqs.GetSentTimestamp(msg),
+					ctx:           msgCtx,
+					span:          span,
 				}
 			}
 			q.wg.Wait()
@@ -139,10 +151,12 @@ type sqsConsumerMessage struct {
 	consumer      *sqs.Consumer
 	wg            *sync.WaitGroup
 	id            *string
+	msg           aws_sqs_types.Message
 	logger        *zap.Logger
 	expiredAt     time.Time
 	sentTimestamp *time.Time
 	ctx           context.Context
+	span          trace.Span
 }
 
 func (m *sqsConsumerMessage) Data() *Event {
@@ -153,10 +167,25 @@ func (m *sqsConsumerMessage) Done() {
 	if err := m.consumer.DeleteMessage(m.ctx, m.id); err != nil {
 		m.logger.Error("Error deleting message from SQS", zap.Error(err))
 	}
+	m.span.End()
 	m.wg.Done()
 }
 
-func (m *sqsConsumerMessage) Failed() {
+// Failed leaves the message in the queue to be received again, or moves it to the dead-letter queue with
+// the error in the LastError attribute when this is its last receive allowed by the redrive policy.
+func (m *sqsConsumerMessage) Failed(err error) {
+	var lastErr string
+	if err != nil {
+		lastErr = err.Error()
+	}
+	moved, errMove := m.consumer.Failed(m.ctx, m.msg, lastErr)
+	if errMove != nil {
+		m.logger.Error("Error moving message to the dead-letter queue", zap.String("vaaId", m.data.ID), zap.Error(errMove))
+	} else if moved {
+		m.logger.Warn("Message moved to the dead-letter queue", zap.String("vaaId", m.data.ID), zap.Error(err))
+	}
+	m.span.SetStatus(codes.Error, "message processing failed")
+	m.span.End()
 	m.wg.Done()
 }
 
@@ -167,3 +196,7 @@ func (m *sqsConsumerMessage) IsExpired() bool {
 func (m *sqsConsumerMessage) SentTimestamp() *time.Time {
 	return m.sentTimestamp
 }
+
+func (m *sqsConsumerMessage) Context() context.Context {
+	return m.ctx
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -11,9 +12,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/go-redis/redis/v8"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbutil"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/config"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/healthcheck"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/http/infrastructure"
@@ -52,6 +56,18 @@ func Run() {
 
 	logger.Info("Starting wormhole-explorer-pipeline ...")
 
+	// set up tracing
+	shutdownTracing, err := telemetry.Setup(rootCtx, telemetry.Config{
+		ServiceName:  "wormhole-explorer-pipeline",
+		Environment:  config.Environment,
+		OtlpEndpoint: config.TracingOtlpEndpoint,
+		OtlpInsecure: config.TracingOtlpInsecure,
+		SampleRatio:  config.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+
 	//setup DB connection
 	db, err := dbutil.Connect(rootCtx, logger, config.MongoURI, config.MongoDatabase, false)
 	if err != nil {
@@ -68,13 +84,13 @@ func Run() {
 	metrics := newMetrics(config)
 
 	// get publish function.
-	pushFunc, err := newTopicProducer(rootCtx, config, alertClient, metrics, logger)
+	pushFunc, topicHealthCheck, err := newTopicProducer(rootCtx, config, alertClient, metrics, logger)
 	if err != nil {
 		logger.Fatal("failed to create publish function", zap.Error(err))
 	}
 
 	// get health check functions.
-	healthChecks, err := newHealthChecks(rootCtx, config, db.Database)
+	healthChecks := []healthcheck.Check{healthcheck.Mongo(db.Database), topicHealthCheck}
 	if err != nil {
 		logger.Fatal("failed to create health checks", zap.Error(err))
 	}
@@ -83,8 +99,18 @@ func Run() {
 	repository := pipeline.NewRepository(db.Database, logger)
 
 	// create and start a new tx hash handler.
+	txHashQueue, err := newTxHashQueue(config, db.Database)
+	if err != nil {
+		logger.Fatal("failed to create txhash queue", zap.Error(err))
+	}
+	txHashRetryConfig := pipeline.TxHashRetryConfig{
+		PollInterval:   config.TxHashRetryPollInterval,
+		InitialBackoff: config.TxHashRetryInitialBackoff,
+		MaxBackoff:     config.TxHashRetryMaxBackoff,
+		MaxAttempts:    config.TxHashRetryMaxAttempts,
+	}
 	quit := make(chan bool)
-	txHashHandler := pipeline.NewTxHashHandler(repository, pushFunc, alertClient, metrics, logger, quit)
+	txHashHandler := pipeline.NewTxHashHandler(repository, txHashQueue, pushFunc, alertClient, metrics, txHashRetryConfig, logger, quit)
 	go txHashHandler.Run(rootCtx)
 
 	// create a new publisher.
@@ -95,7 +121,7 @@ func Run() {
 		logger.Fatal("failed to watch MongoDB", zap.Error(err))
 	}
 
-	server := infrastructure.NewServer(logger, config.Port, config.PprofEnabled, healthChecks...)
+	server := infrastructure.NewServer(logger, config.Port, config.PprofEnabled, txHashHandler, healthChecks...)
 	server.Start()
 
 	logger.Info("Started wormhole-explorer-pipeline")
@@ -119,6 +145,13 @@ func Run() {
 	logger.Info("closing MongoDB connection...")
 	db.DisconnectWithTimeout(10 * time.Second)
 
+	logger.Info("flushing traces...")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", zap.Error(err))
+	}
+	shutdownCancel()
+
 	logger.Info("Closing Http server ...")
 	server.Stop()
 
@@ -154,26 +187,41 @@ func newAwsConfig(appCtx context.Context, cfg *config.Configuration) (aws.Config
 	return awsconfig.LoadDefaultConfig(appCtx, awsconfig.WithRegion(region))
 }
 
-func newTopicProducer(appCtx context.Context, config *config.Configuration, alertClient alert.AlertClient, metrics metrics.Metrics, logger *zap.Logger) (topic.PushFunc, error) {
-	awsConfig, err := newAwsConfig(appCtx, config)
-	if err != nil {
-		return nil, err
-	}
+func newTopicProducer(appCtx context.Context, config *config.Configuration, alertClient alert.AlertClient, metrics metrics.Metrics, logger *zap.Logger) (topic.PushFunc, healthcheck.Check, error) {
+	switch config.Topic {
+	case "sns":
+		awsConfig, err := newAwsConfig(appCtx, config)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	snsProducer, err := sns.NewProducer(awsConfig, config.SNSUrl)
-	if err != nil {
-		return nil, err
-	}
+		snsProducer, err := sns.NewProducer(awsConfig, config.SNSUrl)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	return topic.NewVAASNS(snsProducer, alertClient, metrics, logger).Publish, nil
+		return topic.NewVAASNS(snsProducer, alertClient, metrics, logger).Publish, healthcheck.SNS(awsConfig, config.SNSUrl), nil
+	case "redis-stream":
+		if config.RedisUri == "" || config.RedisStream == "" {
+			return nil, nil, fmt.Errorf("redis uri and stream are required for the redis-stream topic")
+		}
+		client := redis.NewClient(&redis.Options{Addr: config.RedisUri})
+		producer := redisstream.NewProducer(client, config.RedisStream, config.RedisStreamMaxLen)
+		return topic.NewVAARedisStream(producer, metrics, logger).Publish, healthcheck.Redis(client), nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported topic: %s", config.Topic)
+	}
 }
 
-func newHealthChecks(ctx context.Context, config *config.Configuration, db *mongo.Database) ([]healthcheck.Check, error) {
-	awsConfig, err := newAwsConfig(ctx, config)
-	if err != nil {
-		return nil, err
+func newTxHashQueue(cfg *config.Configuration, db *mongo.Database) (pipeline.TxHashQueue, error) {
+	switch cfg.TxHashQueue {
+	case "mongo":
+		return pipeline.NewMongoTxHashQueue(db, cfg.TxHashQueueLease), nil
+	case "memory":
+		return pipeline.NewMemoryTxHashQueue(), nil
+	default:
+		return nil, fmt.Errorf("unsupported txhash queue: %s", cfg.TxHashQueue)
 	}
-	return []healthcheck.Check{healthcheck.Mongo(db), healthcheck.SNS(awsConfig, config.SNSUrl)}, nil
 }
 
 func newMetrics(cfg *config.Configuration) metrics.Metrics {
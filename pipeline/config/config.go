@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sethvargo/go-envconfig"
@@ -9,21 +10,36 @@ import (
 
 // Configuration represents the application configuration with the default values.
 type Configuration struct {
-	Environment        string `env:"ENVIRONMENT,required"`
-	LogLevel           string `env:"LOG_LEVEL,default=INFO"`
-	Port               string `env:"PORT,default=8000"`
-	P2pNetwork         string `env:"P2P_NETWORK,required"`
-	MongoURI           string `env:"MONGODB_URI,required"`
-	MongoDatabase      string `env:"MONGODB_DATABASE,required"`
-	AwsEndpoint        string `env:"AWS_ENDPOINT"`
-	AwsAccessKeyID     string `env:"AWS_ACCESS_KEY_ID"`
-	AwsSecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY"`
-	AwsRegion          string `env:"AWS_REGION"`
-	SNSUrl             string `env:"SNS_URL"`
-	PprofEnabled       bool   `env:"PPROF_ENABLED,default=false"`
-	AlertEnabled       bool   `env:"ALERT_ENABLED,default=false"`
-	AlertApiKey        string `env:"ALERT_API_KEY"`
-	MetricsEnabled     bool   `env:"METRICS_ENABLED,default=false"`
+	Environment         string  `env:"ENVIRONMENT,required"`
+	LogLevel            string  `env:"LOG_LEVEL,default=INFO"`
+	TracingOtlpEndpoint string  `env:"TRACING_OTLP_ENDPOINT"`
+	TracingOtlpInsecure bool    `env:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio  float64 `env:"TRACING_SAMPLE_RATIO,default=1"`
+	Port                string  `env:"PORT,default=8000"`
+	P2pNetwork          string  `env:"P2P_NETWORK,required"`
+	MongoURI            string  `env:"MONGODB_URI,required"`
+	MongoDatabase       string  `env:"MONGODB_DATABASE,required"`
+	AwsEndpoint         string  `env:"AWS_ENDPOINT"`
+	AwsAccessKeyID      string  `env:"AWS_ACCESS_KEY_ID"`
+	AwsSecretAccessKey  string  `env:"AWS_SECRET_ACCESS_KEY"`
+	AwsRegion           string  `env:"AWS_REGION"`
+	SNSUrl              string  `env:"SNS_URL"`
+	PprofEnabled        bool    `env:"PPROF_ENABLED,default=false"`
+	AlertEnabled        bool    `env:"ALERT_ENABLED,default=false"`
+	AlertApiKey         string  `env:"ALERT_API_KEY"`
+	MetricsEnabled      bool    `env:"METRICS_ENABLED,default=false"`
+	// Topic is where the VAAs are published: sns (default) or redis-stream.
+	Topic             string `env:"TOPIC,default=sns"`
+	RedisUri          string `env:"REDIS_URI"`
+	RedisStream       string `env:"REDIS_STREAM"`
+	RedisStreamMaxLen int64  `env:"REDIS_STREAM_MAX_LEN,default=100000"`
+	// TxHashQueue is where the VAAs without txhash wait for it: mongo (default) or memory.
+	TxHashQueue               string        `env:"TXHASH_QUEUE,default=mongo"`
+	TxHashQueueLease          time.Duration `env:"TXHASH_QUEUE_LEASE,default=1m"`
+	TxHashRetryPollInterval   time.Duration `env:"TXHASH_RETRY_POLL_INTERVAL,default=2s"`
+	TxHashRetryInitialBackoff time.Duration `env:"TXHASH_RETRY_INITIAL_BACKOFF,default=2s"`
+	TxHashRetryMaxBackoff     time.Duration `env:"TXHASH_RETRY_MAX_BACKOFF,default=10m"`
+	TxHashRetryMaxAttempts    int           `env:"TXHASH_RETRY_MAX_ATTEMPTS,default=10"`
 }
 
 type Backfiller struct {
@@ -5,6 +5,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/healthcheck"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/http/txhash"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/pipeline"
 	"go.uber.org/zap"
 )
 
@@ -14,7 +16,7 @@ type Server struct {
 	logger *zap.Logger
 }
 
-func NewServer(logger *zap.Logger, port string, pprofEnabled bool, checks ...healthcheck.Check) *Server {
+func NewServer(logger *zap.Logger, port string, pprofEnabled bool, txHashHandler *pipeline.TxHashHandler, checks ...healthcheck.Check) *Server {
 	app := fiber.New(fiber.Config{DisableStartupMessage: true})
 
 	// config use of middlware.
@@ -31,6 +33,12 @@ func NewServer(logger *zap.Logger, port string, pprofEnabled bool, checks ...hea
 	api.Get("/health", ctrl.HealthCheck)
 	api.Get("/ready", ctrl.ReadyCheck)
 
+	// admin endpoints of the queue of vaas waiting for their txhash.
+	txHashCtrl := txhash.NewController(txHashHandler, logger)
+	api.Get("/txhash-queue", txHashCtrl.ListPending)
+	api.Post("/txhash-queue/retry", txHashCtrl.RetryAll)
+	api.Post("/txhash-queue/:chain/:emitter/:sequence/retry", txHashCtrl.Retry)
+
 	return &Server{
 		app:    app,
 		port:   port,
@@ -0,0 +1,80 @@
+package txhash
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/pipeline"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// Controller exposes the queue of VAAs waiting for their txhash.
+type Controller struct {
+	handler *pipeline.TxHashHandler
+	logger  *zap.Logger
+}
+
+// NewController creates a Controller instance.
+func NewController(handler *pipeline.TxHashHandler, logger *zap.Logger) *Controller {
+	return &Controller{handler: handler, logger: logger}
+}
+
+// ListPending handler for the endpoint GET /api/txhash-queue.
+func (c *Controller) ListPending(ctx *fiber.Ctx) error {
+	page := ctx.QueryInt("page", 0)
+	pageSize := ctx.QueryInt("pageSize", defaultPageSize)
+	if page < 0 || pageSize <= 0 || pageSize > maxPageSize {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("page must be positive and pageSize between 1 and %d", maxPageSize),
+		})
+	}
+
+	items, total, err := c.handler.Pending(ctx.Context(), int64(page*pageSize), int64(pageSize))
+	if err != nil {
+		c.logger.Error("failed to list txhash queue", zap.Error(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return ctx.JSON(struct {
+		Total int64                       `json:"total"`
+		Items []*pipeline.TxHashQueueItem `json:"items"`
+	}{Total: total, Items: items})
+}
+
+// Retry handler for the endpoint POST /api/txhash-queue/:id/retry. It makes an attempt to fix the
+// txhash of the item now. The id is the VAA id (chain/emitter/sequence).
+func (c *Controller) Retry(ctx *fiber.Ctx) error {
+	id := ctx.Params("chain") + "/" + ctx.Params("emitter") + "/" + ctx.Params("sequence")
+	result, err := c.handler.Retry(ctx.Context(), id)
+	if errors.Is(err, pipeline.ErrTxHashQueueItemNotFound) {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, pipeline.ErrTxHashQueueItemLeased) {
+		return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		c.logger.Error("failed to retry txhash queue item", zap.String("vaaID", id), zap.Error(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return ctx.JSON(struct {
+		ID     string               `json:"id"`
+		Result pipeline.RetryResult `json:"result"`
+	}{ID: id, Result: result})
+}
+
+// RetryAll handler for the endpoint POST /api/txhash-queue/retry. It makes every item due on the next poll.
+func (c *Controller) RetryAll(ctx *fiber.Ctx) error {
+	count, err := c.handler.RetryAll(ctx.Context())
+	if err != nil {
+		c.logger.Error("failed to retry txhash queue", zap.Error(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return ctx.JSON(struct {
+		Scheduled int64 `json:"scheduled"`
+	}{Scheduled: count})
+}
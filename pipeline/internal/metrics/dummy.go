@@ -20,3 +20,6 @@ func (m *DummyMetrics) IncVaaWithoutTxHash(chainID uint16) {}
 
 // IncVaaWithTxHashFixed increments the vaa received count with tx hash fixed.
 func (m *DummyMetrics) IncVaaWithTxHashFixed(chainID uint16) {}
+
+// IncVaaPublishedWithoutTxHash increments the vaa count published without tx hash after the retries ran out.
+func (m *DummyMetrics) IncVaaPublishedWithoutTxHash(chainID uint16) {}
@@ -9,4 +9,5 @@ type Metrics interface {
 
 	IncVaaWithoutTxHash(chainID uint16)
 	IncVaaWithTxHashFixed(chainID uint16)
+	IncVaaPublishedWithoutTxHash(chainID uint16)
 }
@@ -63,3 +63,9 @@ func (m *PrometheusMetrics) IncVaaWithTxHashFixed(chainID uint16) {
 	chain := vaa.ChainID(chainID).String()
 	m.vaaTxHashCount.WithLabelValues(chain, "vaa-with-txhash-fixed").Inc()
 }
+
+// IncVaaPublishedWithoutTxHash increments the vaa count published without tx hash after the retries ran out.
+func (m *PrometheusMetrics) IncVaaPublishedWithoutTxHash(chainID uint16) {
+	chain := vaa.ChainID(chainID).String()
+	m.vaaTxHashCount.WithLabelValues(chain, "vaa-published-without-txhash").Inc()
+}
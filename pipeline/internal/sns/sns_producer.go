@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_sns "github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 )
 
 // Producer represents SNS producer.
@@ -30,6 +31,7 @@ func (p *Producer) SendMessage(ctx context.Context, chainId uint16, groupID, ded
 			StringValue: aws.String(fmt.Sprintf("%d", chainId)),
 		},
 	}
+	attrs = telemetry.InjectSNSAttributes(ctx, attrs)
 	_, err := p.api.Publish(ctx,
 		&aws_sns.PublishInput{
 			MessageGroupId:         aws.String(groupID),
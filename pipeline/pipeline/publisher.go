@@ -3,13 +3,17 @@ package pipeline
 import (
 	"context"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/topic"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/watcher"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+var tracer = telemetry.Tracer("github.com/wormhole-foundation/wormhole-explorer/pipeline/pipeline")
+
 // Publisher definition.
 type Publisher struct {
 	logger        *zap.Logger
@@ -35,6 +39,11 @@ func NewPublisher(pushFunc topic.PushFunc, metrics metrics.Metrics, repository *
 // Publish sends a Event for the vaa that has parse configuration defined.
 func (p *Publisher) Publish(ctx context.Context, e *watcher.Event) {
 
+	// continue the trace started by the fly component.
+	ctx, span := tracer.Start(telemetry.ContextWithTraceContext(ctx, e.TraceContext), "pipeline.vaa.publish",
+		trace.WithAttributes(telemetry.VaaIDKey.String(e.ID)))
+	defer span.End()
+
 	// create a Event.
 	event := topic.Event{
 		ID:               e.ID,
@@ -51,6 +60,7 @@ func (p *Publisher) Publish(ctx context.Context, e *watcher.Event) {
 		Revision:         e.Revision,
 		Digest:           e.Digest,
 		Overwrite:        e.DuplicatedFixed,
+		TraceContext:     telemetry.TraceContext(ctx),
 	}
 
 	// In some scenarios the fly component that inserts the VAA documents does not have the txhash field available,
@@ -66,7 +76,7 @@ func (p *Publisher) Publish(ctx context.Context, e *watcher.Event) {
 			// add the event to the txhash handler.
 			// the handler will try to get the txhash for the vaa
 			// and publish the event with the txhash.
-			p.txHashHandler.AddVaaFixItem(event)
+			p.txHashHandler.AddVaaFixItem(ctx, event)
 			return
 		}
 	}
@@ -2,7 +2,9 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,14 +12,71 @@ import (
 	"github.com/test-go/testify/assert"
 	"github.com/test-go/testify/require"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/pipeline"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/pipeline/mocks"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/topic"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 )
 
+var testRetryConfig = pipeline.TxHashRetryConfig{
+	PollInterval:   10 * time.Millisecond,
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     50 * time.Millisecond,
+	MaxAttempts:    3,
+}
+
+// newTestEvent returns an event with a marshalled vaa and the unique id of the vaa.
+func newTestEvent(t *testing.T) (topic.Event, string) {
+	v := &sdk.VAA{
+		Version:          1,
+		GuardianSetIndex: 0,
+		Timestamp:        time.Unix(1700000000, 0),
+		Nonce:            1,
+		Sequence:         42,
+		EmitterChain:     sdk.ChainIDEthereum,
+		EmitterAddress:   sdk.Address{1},
+		Payload:          []byte{1, 2, 3},
+	}
+	b, err := v.Marshal()
+	require.NoError(t, err)
+	return topic.Event{ID: v.MessageID(), ChainID: uint16(v.EmitterChain), Vaa: b}, domain.CreateUniqueVaaID(v)
+}
+
+// publishedEvents records the events pushed by the handler.
+type publishedEvents struct {
+	mu     sync.Mutex
+	events []topic.Event
+}
+
+func (p *publishedEvents) push(_ context.Context, e *topic.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, *e)
+	return nil
+}
+
+func (p *publishedEvents) all() []topic.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]topic.Event(nil), p.events...)
+}
+
+// waitFor polls cond until it is true, failing the test once timeout expires.
+func waitFor(t *testing.T, cond func() bool, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before the timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 func TestNewTxHashHandler(t *testing.T) {
 
 	mock := gomock.NewController(t)
@@ -25,40 +84,124 @@ func TestNewTxHashHandler(t *testing.T) {
 
 	repo := mocks.NewMockIRepository(mock)
 
-	//	log, _ := zap.NewDevelopment()
 	observedZapCore, observedLogs := observer.New(zap.InfoLevel)
 	observedLogger := zap.New(observedZapCore)
 
 	quit := make(chan bool)
+	published := &publishedEvents{}
+	event, uniqueID := newTestEvent(t)
 
-	var f = topic.PushFunc(func(context.Context, *topic.Event) error {
-		return nil
-	})
+	ctx := context.Background()
 
-	txHashHandler := pipeline.NewTxHashHandler(repo, f, alert.NewDummyClient(), metrics.NewDummyMetrics(), observedLogger, quit)
-	txHashHandler.AddVaaFixItem(topic.Event{
-		ID: "vaa1",
-	},
+	gomock.InOrder(
+		repo.EXPECT().GetVaaIdTxHash(ctx, uniqueID).Return(nil, fmt.Errorf("error")),
+		repo.EXPECT().GetVaaIdTxHash(ctx, uniqueID).Return(&pipeline.VaaIdTxHash{
+			ChainID: 1,
+			TxHash:  "0xbabla",
+		}, nil),
 	)
+	repo.EXPECT().UpdateVaaDocTxHash(ctx, uniqueID, "0xbabla").Return(nil)
 
-	ctx := context.Background()
-
-	repo.EXPECT().GetVaaIdTxHash(ctx, "vaa1").Return(nil, fmt.Errorf("error"))
-	repo.EXPECT().GetVaaIdTxHash(ctx, "vaa1").Return(&pipeline.VaaIdTxHash{
-		ChainID: 1,
-		TxHash:  "0xbabla",
-	}, nil)
-	repo.EXPECT().UpdateVaaDocTxHash(ctx, "vaa1", "0xbabla").Return(nil)
+	queue := pipeline.NewMemoryTxHashQueue()
+	txHashHandler := pipeline.NewTxHashHandler(repo, queue, published.push, alert.NewDummyClient(), metrics.NewDummyMetrics(), testRetryConfig, observedLogger, quit)
+	txHashHandler.AddVaaFixItem(ctx, event)
 
-	go txHashHandler.Run(ctx)
-	time.Sleep(6 * time.Second)
+	stopped := make(chan struct{})
+	go func() {
+		txHashHandler.Run(ctx)
+		close(stopped)
+	}()
+	waitFor(t, func() bool { return len(published.all()) == 1 }, 2*time.Second)
 	close(quit)
+	<-stopped
 
-	require.Equal(t, 3, observedLogs.Len())
+	require.Equal(t, 4, observedLogs.Len())
 	allLogs := observedLogs.All()
+	assert.Equal(t, "TxHashHandler started", allLogs[0].Message)
 	// first attempt to get txhash should fail
 	assert.Equal(t, "Error while trying to fix vaa txhash", allLogs[1].Message)
 	// second attempt to get txhash should succeed
 	assert.Equal(t, "Vaa txhash fixed", allLogs[2].Message)
+	assert.Equal(t, "stopping txhash handler", allLogs[3].Message)
+
+	// the failed attempt is logged once, with its attempt number.
+	retryLogs := observedLogs.FilterMessage("Error while trying to fix vaa txhash").All()
+	require.Len(t, retryLogs, 1)
+	assert.Equal(t, int64(1), retryLogs[0].ContextMap()["attempts"])
+	assert.Equal(t, event.ID, retryLogs[0].ContextMap()["vaaID"])
+
+	assert.Equal(t, "0xbabla", published.all()[0].TxHash)
+	count, err := queue.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestTxHashHandlerPublishesWithoutTxHash(t *testing.T) {
+
+	mock := gomock.NewController(t)
+	defer mock.Finish()
+
+	repo := mocks.NewMockIRepository(mock)
+	published := &publishedEvents{}
+	event, uniqueID := newTestEvent(t)
+	ctx := context.Background()
+
+	repo.EXPECT().GetVaaIdTxHash(ctx, uniqueID).Return(nil, fmt.Errorf("error")).Times(testRetryConfig.MaxAttempts)
+
+	queue := pipeline.NewMemoryTxHashQueue()
+	txHashHandler := pipeline.NewTxHashHandler(repo, queue, published.push, alert.NewDummyClient(), metrics.NewDummyMetrics(), testRetryConfig, zap.NewNop(), make(chan bool))
+	txHashHandler.AddVaaFixItem(ctx, event)
+
+	// the forced retries do not wait for the backoff.
+	for i := 1; i < testRetryConfig.MaxAttempts; i++ {
+		result, err := txHashHandler.Retry(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, pipeline.RetryRescheduled, result)
+
+		item, err := queue.FindOne(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, i, item.Attempts)
+		assert.Equal(t, "error", item.LastError)
+	}
+
+	result, err := txHashHandler.Retry(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, pipeline.RetryPublishedWithoutTxHash, result)
+
+	require.Len(t, published.all(), 1)
+	assert.Empty(t, published.all()[0].TxHash)
+
+	_, err = txHashHandler.Retry(ctx, event.ID)
+	assert.True(t, errors.Is(err, pipeline.ErrTxHashQueueItemNotFound))
+}
+
+func TestTxHashHandlerPending(t *testing.T) {
+
+	mock := gomock.NewController(t)
+	defer mock.Finish()
+
+	repo := mocks.NewMockIRepository(mock)
+	published := &publishedEvents{}
+	ctx := context.Background()
+
+	queue := pipeline.NewMemoryTxHashQueue()
+	txHashHandler := pipeline.NewTxHashHandler(repo, queue, published.push, alert.NewDummyClient(), metrics.NewDummyMetrics(), testRetryConfig, zap.NewNop(), make(chan bool))
+	for i := 0; i < 3; i++ {
+		txHashHandler.AddVaaFixItem(ctx, topic.Event{ID: fmt.Sprintf("2/0001/%d", i)})
+	}
+
+	items, total, err := txHashHandler.Pending(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, items, 2)
+
+	// an item that can not be unmarshalled is discarded.
+	result, err := txHashHandler.Retry(ctx, "2/0001/0")
+	require.NoError(t, err)
+	assert.Equal(t, pipeline.RetryDiscarded, result)
+	assert.Empty(t, published.all())
 
+	scheduled, err := txHashHandler.RetryAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), scheduled)
 }
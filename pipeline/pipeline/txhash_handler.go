@@ -3,10 +3,12 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	pipelineAlert "github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/topic"
@@ -14,92 +16,223 @@ import (
 	"go.uber.org/zap"
 )
 
-type ItemTuple struct {
-	Retries int
-	Event   topic.Event
+// txHashBatchSize is the maximum number of due items processed on each poll.
+const txHashBatchSize = 100
+
+// RetryResult is the outcome of an attempt to fix the txhash of a VAA.
+type RetryResult string
+
+const (
+	// RetryFixed means the txhash was found and the event was published with it.
+	RetryFixed RetryResult = "fixed"
+	// RetryRescheduled means the attempt failed and the item waits for the next one.
+	RetryRescheduled RetryResult = "rescheduled"
+	// RetryPublishedWithoutTxHash means the attempts ran out and the event was published without txhash.
+	RetryPublishedWithoutTxHash RetryResult = "published-without-txhash"
+	// RetryDiscarded means the item could not be processed and was removed from the queue.
+	RetryDiscarded RetryResult = "discarded"
+)
+
+// TxHashRetryConfig is the retry policy of the TxHashHandler.
+type TxHashRetryConfig struct {
+	// PollInterval is the time between checks for due items.
+	PollInterval time.Duration
+	// InitialBackoff is the wait after the first failed attempt, it doubles after each failed attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum wait between attempts.
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of attempts after which the event is published without txhash.
+	MaxAttempts int
+}
+
+// DefaultTxHashRetryConfig returns the default retry policy.
+func DefaultTxHashRetryConfig() TxHashRetryConfig {
+	return TxHashRetryConfig{
+		PollInterval:   2 * time.Second,
+		InitialBackoff: 2 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+		MaxAttempts:    10,
+	}
 }
 
+// backoff returns the wait after the given number of failed attempts.
+func (c TxHashRetryConfig) backoff(attempts int) time.Duration {
+	d := c.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= c.MaxBackoff {
+			return c.MaxBackoff
+		}
+	}
+	return min(d, c.MaxBackoff)
+}
+
+// TxHashHandler completes the txhash of the VAAs that arrive without it. The pending VAAs are kept
+// in a TxHashQueue and retried with exponential backoff; when the attempts run out the event is
+// published without txhash.
 type TxHashHandler struct {
-	logger         *zap.Logger
-	repository     IRepository
-	fixItems       map[string]ItemTuple
-	inputQueue     chan topic.Event
-	quit           chan bool
-	sleepTime      time.Duration
-	pushFunc       topic.PushFunc
-	alertClient    alert.AlertClient
-	metrics        metrics.Metrics
-	defaultRetries int
+	logger      *zap.Logger
+	repository  IRepository
+	queue       TxHashQueue
+	quit        chan bool
+	pushFunc    topic.PushFunc
+	alertClient alert.AlertClient
+	metrics     metrics.Metrics
+	cfg         TxHashRetryConfig
+	// mu serializes the attempts of the polling loop and of the forced retries.
+	mu sync.Mutex
 }
 
 // NewTxHashHandler creates a new TxHashHandler.
-func NewTxHashHandler(repository IRepository, pushFunc topic.PushFunc, alertClient alert.AlertClient, metrics metrics.Metrics, logger *zap.Logger, quit chan bool) *TxHashHandler {
+func NewTxHashHandler(repository IRepository, queue TxHashQueue, pushFunc topic.PushFunc, alertClient alert.AlertClient, metrics metrics.Metrics, cfg TxHashRetryConfig, logger *zap.Logger, quit chan bool) *TxHashHandler {
 	return &TxHashHandler{
-		logger:         logger,
-		repository:     repository,
-		fixItems:       map[string]ItemTuple{},
-		inputQueue:     make(chan topic.Event, 100),
-		sleepTime:      2 * time.Second,
-		pushFunc:       pushFunc,
-		alertClient:    alertClient,
-		metrics:        metrics,
-		defaultRetries: 3,
+		logger:      logger,
+		repository:  repository,
+		queue:       queue,
+		quit:        quit,
+		pushFunc:    pushFunc,
+		alertClient: alertClient,
+		metrics:     metrics,
+		cfg:         cfg,
 	}
 }
 
-// Add a new element to the fixItems array
-func (t *TxHashHandler) AddVaaFixItem(event topic.Event) {
-	t.inputQueue <- event
+// AddVaaFixItem adds an event to the queue. If the event can not be queued it is published without txhash.
+func (t *TxHashHandler) AddVaaFixItem(ctx context.Context, event topic.Event) {
+	if err := t.queue.Enqueue(ctx, event); err != nil {
+		t.logger.Error("Error adding vaa to txhash queue, publishing without txhash", zap.String("vaaID", event.ID), zap.Error(err))
+		t.publishWithoutTxHash(ctx, &event)
+	}
 }
 
 func (t *TxHashHandler) Run(ctx context.Context) {
 	t.logger.Info("TxHashHandler started")
+	ticker := time.NewTicker(t.cfg.PollInterval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-t.quit:
 			t.logger.Info("stopping txhash handler")
 			return
-		case event := <-t.inputQueue:
-			t.fixItems[event.ID] = ItemTuple{
-				Retries: 5,
-				Event:   event,
-			}
-		default:
-			// no lock needed. the map is never updated while iterating.
-			for vaaID, item := range t.fixItems {
-				if item.Retries > 0 {
-					vaa, err := sdk.Unmarshal(item.Event.Vaa)
-					if err != nil {
-						t.logger.Error("Error unmarshalling vaa", zap.Error(err), zap.String("vaaId", vaaID))
-						delete(t.fixItems, vaaID)
-						continue
-					}
-					uniqueVaaID := domain.CreateUniqueVaaID(vaa)
-					txHash, err := t.handleEmptyVaaTxHash(ctx, uniqueVaaID)
-					if err != nil {
-						t.logger.Error("Error while trying to fix vaa txhash", zap.Int("retries_count", item.Retries), zap.Error(err))
-						item.Retries = item.Retries - 1
-						t.fixItems[vaaID] = item
-					} else {
-						t.logger.Info("Vaa txhash fixed", zap.String("vaaID", vaaID), zap.String("txHash", txHash))
-						item.Event.TxHash = txHash
-						t.pushFunc(ctx, &item.Event)
-						delete(t.fixItems, vaaID)
-						// increment metrics vaa with txhash fixed
-						t.metrics.IncVaaWithTxHashFixed(item.Event.ChainID)
-					}
-				} else {
-					t.logger.Error("Vaa txhash fix failed", zap.String("vaaID", vaaID))
-					// publish the event to the topic anyway
-					t.pushFunc(ctx, &item.Event)
-					delete(t.fixItems, vaaID)
-				}
-			}
+		case <-ctx.Done():
+			t.logger.Info("stopping txhash handler")
+			return
+		case <-ticker.C:
+			t.processDue(ctx)
 		}
-		time.Sleep(t.sleepTime)
 	}
 }
 
+// processDue attempts to fix the items whose next attempt time has passed and that this instance claims.
+func (t *TxHashHandler) processDue(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	items, err := t.queue.Claim(ctx, time.Now(), txHashBatchSize)
+	if err != nil {
+		t.logger.Error("Error claiming due items from txhash queue", zap.Error(err))
+		return
+	}
+	for _, item := range items {
+		t.process(ctx, item)
+	}
+}
+
+// Pending returns a page of the items waiting for their txhash and the total number of items.
+func (t *TxHashHandler) Pending(ctx context.Context, skip, limit int64) ([]*TxHashQueueItem, int64, error) {
+	items, err := t.queue.Find(ctx, skip, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := t.queue.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// Retry attempts to fix the txhash of a pending item now.
+func (t *TxHashHandler) Retry(ctx context.Context, id string) (RetryResult, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	item, err := t.queue.ClaimOne(ctx, id, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return t.process(ctx, item), nil
+}
+
+// RetryAll makes every pending item due on the next poll and returns the number of items.
+func (t *TxHashHandler) RetryAll(ctx context.Context) (int64, error) {
+	return t.queue.RetryAll(ctx, time.Now())
+}
+
+// process makes an attempt to fix the txhash of an item.
+func (t *TxHashHandler) process(ctx context.Context, item *TxHashQueueItem) RetryResult {
+	vaaID := item.ID
+	vaa, err := sdk.Unmarshal(item.Event.Vaa)
+	if err != nil {
+		t.logger.Error("Error unmarshalling vaa", zap.Error(err), zap.String("vaaId", vaaID))
+		t.remove(ctx, vaaID)
+		return RetryDiscarded
+	}
+
+	uniqueVaaID := domain.CreateUniqueVaaID(vaa)
+	txHash, err := t.handleEmptyVaaTxHash(ctx, uniqueVaaID)
+	if err == nil {
+		t.logger.Info("Vaa txhash fixed", zap.String("vaaID", vaaID), zap.String("txHash", txHash))
+		event := item.Event
+		event.TxHash = txHash
+		if err := t.pushFunc(telemetry.ContextWithTraceContext(ctx, event.TraceContext), &event); err != nil {
+			t.logger.Error("Error publishing vaa with txhash fixed", zap.String("vaaID", vaaID), zap.Error(err))
+			return t.reschedule(ctx, item, err)
+		}
+		t.remove(ctx, vaaID)
+		// increment metrics vaa with txhash fixed
+		t.metrics.IncVaaWithTxHashFixed(event.ChainID)
+		return RetryFixed
+	}
+
+	t.logger.Error("Error while trying to fix vaa txhash", zap.String("vaaID", vaaID), zap.Int("attempts", item.Attempts+1), zap.Error(err))
+	if item.Attempts+1 < t.cfg.MaxAttempts {
+		return t.reschedule(ctx, item, err)
+	}
+
+	t.logger.Error("Vaa txhash fix failed", zap.String("vaaID", vaaID))
+	// publish the event to the topic anyway
+	if err := t.publishWithoutTxHash(ctx, &item.Event); err != nil {
+		return t.reschedule(ctx, item, err)
+	}
+	t.remove(ctx, vaaID)
+	return RetryPublishedWithoutTxHash
+}
+
+func (t *TxHashHandler) reschedule(ctx context.Context, item *TxHashQueueItem, cause error) RetryResult {
+	attempts := item.Attempts + 1
+	next := time.Now().Add(t.cfg.backoff(attempts))
+	if err := t.queue.Reschedule(ctx, item.ID, attempts, next, cause.Error()); err != nil {
+		t.logger.Error("Error rescheduling txhash queue item", zap.String("vaaID", item.ID), zap.Error(err))
+	}
+	return RetryRescheduled
+}
+
+func (t *TxHashHandler) remove(ctx context.Context, id string) {
+	if err := t.queue.Remove(ctx, id); err != nil {
+		t.logger.Error("Error removing txhash queue item", zap.String("vaaID", id), zap.Error(err))
+	}
+}
+
+func (t *TxHashHandler) publishWithoutTxHash(ctx context.Context, event *topic.Event) error {
+	err := t.pushFunc(telemetry.ContextWithTraceContext(ctx, event.TraceContext), event)
+	if err != nil {
+		t.logger.Error("Error publishing vaa without txhash", zap.String("vaaID", event.ID), zap.Error(err))
+		return err
+	}
+	t.metrics.IncVaaPublishedWithoutTxHash(event.ChainID)
+	return nil
+}
+
 // handleEmptyVaaTxHash tries to get the txhash for the vaa with the given id.
 func (p *TxHashHandler) handleEmptyVaaTxHash(ctx context.Context, id string) (string, error) {
 	vaaIdTxHash, err := p.repository.GetVaaIdTxHash(ctx, id)
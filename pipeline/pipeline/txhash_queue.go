@@ -0,0 +1,362 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/topic"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTxHashQueueItemNotFound is returned when an item is not in the txhash queue.
+var ErrTxHashQueueItemNotFound = errors.New("txhash queue item not found")
+
+// ErrTxHashQueueItemLeased is returned when an item is being processed by another owner.
+var ErrTxHashQueueItemLeased = errors.New("txhash queue item leased by another owner")
+
+// TxHashQueueItem is a VAA event waiting for its txhash.
+type TxHashQueueItem struct {
+	ID          string      `bson:"_id" json:"id"`
+	Event       topic.Event `bson:"event" json:"event"`
+	Attempts    int         `bson:"attempts" json:"attempts"`
+	NextAttempt time.Time   `bson:"nextAttempt" json:"nextAttempt"`
+	LastError   string      `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	CreatedAt   time.Time   `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time   `bson:"updatedAt" json:"updatedAt"`
+	// Owner is the instance processing the item until LeaseExpiry.
+	Owner       string     `bson:"owner,omitempty" json:"owner,omitempty"`
+	LeaseExpiry *time.Time `bson:"leaseExpiry,omitempty" json:"leaseExpiry,omitempty"`
+}
+
+// TxHashQueue stores the VAA events waiting for their txhash, ordered by their next attempt time.
+type TxHashQueue interface {
+	// Enqueue adds an event that is due immediately. An event already in the queue keeps its attempts.
+	Enqueue(ctx context.Context, event topic.Event) error
+	// Claim leases up to limit items whose next attempt is before now and that are not leased by
+	// another owner, so that only one instance processes each item.
+	Claim(ctx context.Context, now time.Time, limit int64) ([]*TxHashQueueItem, error)
+	// ClaimOne leases an item regardless of its next attempt time. It returns ErrTxHashQueueItemNotFound,
+	// or ErrTxHashQueueItemLeased when another owner holds the lease.
+	ClaimOne(ctx context.Context, id string, now time.Time) (*TxHashQueueItem, error)
+	// Reschedule updates the attempts and the next attempt time of a claimed item and releases it.
+	Reschedule(ctx context.Context, id string, attempts int, next time.Time, lastError string) error
+	// Remove deletes a claimed item.
+	Remove(ctx context.Context, id string) error
+	// Find returns a page of items ordered by their next attempt time.
+	Find(ctx context.Context, skip, limit int64) ([]*TxHashQueueItem, error)
+	// FindOne returns an item, or ErrTxHashQueueItemNotFound.
+	FindOne(ctx context.Context, id string) (*TxHashQueueItem, error)
+	// Count returns the number of items.
+	Count(ctx context.Context) (int64, error)
+	// RetryAll makes every item due at now and returns the number of items.
+	RetryAll(ctx context.Context, now time.Time) (int64, error)
+}
+
+// MongoTxHashQueue is a TxHashQueue stored in a MongoDB collection, so that the pending
+// events survive a restart of the pipeline. The items are claimed with a lease, so several
+// replicas can share the queue and the items of a crashed replica are retried once its lease expires.
+type MongoTxHashQueue struct {
+	collection *mongo.Collection
+	owner      string
+	lease      time.Duration
+}
+
+// NewMongoTxHashQueue creates a new MongoTxHashQueue whose claims last for the given lease.
+func NewMongoTxHashQueue(db *mongo.Database, lease time.Duration) *MongoTxHashQueue {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = "pipeline"
+	}
+	return &MongoTxHashQueue{
+		collection: db.Collection(repository.TxHashQueue),
+		owner:      fmt.Sprintf("%s-%d", name, os.Getpid()),
+		lease:      lease,
+	}
+}
+
+// Enqueue adds an event that is due immediately.
+func (q *MongoTxHashQueue) Enqueue(ctx context.Context, event topic.Event) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"event":       event,
+			"nextAttempt": now,
+			"updatedAt":   now,
+		},
+		"$setOnInsert": bson.M{
+			"attempts":  0,
+			"createdAt": now,
+		},
+	}
+	_, err := q.collection.UpdateByID(ctx, event.ID, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Claim leases up to limit items whose next attempt is before now and that are not leased by another owner.
+func (q *MongoTxHashQueue) Claim(ctx context.Context, now time.Time, limit int64) ([]*TxHashQueueItem, error) {
+	filter := bson.M{
+		"nextAttempt": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"leaseExpiry": nil},
+			bson.M{"leaseExpiry": bson.M{"$lte": now}},
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttempt", Value: 1}}).
+		SetReturnDocument(options.After)
+	items := []*TxHashQueueItem{}
+	for int64(len(items)) < limit {
+		var item TxHashQueueItem
+		err := q.collection.FindOneAndUpdate(ctx, filter, q.leaseUpdate(now), opts).Decode(&item)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, nil
+}
+
+// ClaimOne leases an item regardless of its next attempt time.
+func (q *MongoTxHashQueue) ClaimOne(ctx context.Context, id string, now time.Time) (*TxHashQueueItem, error) {
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"leaseExpiry": nil},
+			bson.M{"leaseExpiry": bson.M{"$lte": now}},
+			bson.M{"owner": q.owner},
+		},
+	}
+	var item TxHashQueueItem
+	err := q.collection.FindOneAndUpdate(ctx, filter, q.leaseUpdate(now), options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&item)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// the item is either missing or leased by another owner.
+		if _, err := q.FindOne(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrTxHashQueueItemLeased
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (q *MongoTxHashQueue) leaseUpdate(now time.Time) bson.M {
+	return bson.M{
+		"$set": bson.M{
+			"owner":       q.owner,
+			"leaseExpiry": now.Add(q.lease),
+		},
+	}
+}
+
+// Reschedule updates the attempts and the next attempt time of a claimed item and releases it.
+// It does nothing if the lease was lost to another owner.
+func (q *MongoTxHashQueue) Reschedule(ctx context.Context, id string, attempts int, next time.Time, lastError string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"attempts":    attempts,
+			"nextAttempt": next,
+			"lastError":   lastError,
+			"updatedAt":   time.Now(),
+		},
+		"$unset": bson.M{
+			"owner":       "",
+			"leaseExpiry": "",
+		},
+	}
+	_, err := q.collection.UpdateOne(ctx, bson.M{"_id": id, "owner": q.owner}, update)
+	return err
+}
+
+// Remove deletes a claimed item. It does nothing if the lease was lost to another owner.
+func (q *MongoTxHashQueue) Remove(ctx context.Context, id string) error {
+	_, err := q.collection.DeleteOne(ctx, bson.M{"_id": id, "owner": q.owner})
+	return err
+}
+
+// Find returns a page of items ordered by their next attempt time.
+func (q *MongoTxHashQueue) Find(ctx context.Context, skip, limit int64) ([]*TxHashQueueItem, error) {
+	return q.find(ctx, bson.M{}, skip, limit)
+}
+
+// FindOne returns an item, or ErrTxHashQueueItemNotFound.
+func (q *MongoTxHashQueue) FindOne(ctx context.Context, id string) (*TxHashQueueItem, error) {
+	var item TxHashQueueItem
+	err := q.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&item)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrTxHashQueueItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Count returns the number of items.
+func (q *MongoTxHashQueue) Count(ctx context.Context) (int64, error) {
+	return q.collection.CountDocuments(ctx, bson.M{})
+}
+
+// RetryAll makes every item due at now.
+func (q *MongoTxHashQueue) RetryAll(ctx context.Context, now time.Time) (int64, error) {
+	res, err := q.collection.UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"nextAttempt": now}})
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+func (q *MongoTxHashQueue) find(ctx context.Context, filter bson.M, skip, limit int64) ([]*TxHashQueueItem, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "nextAttempt", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+	cur, err := q.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	items := []*TxHashQueueItem{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MemoryTxHashQueue is a TxHashQueue kept in memory. The pending events are lost on restart and
+// the queue is not shared, so the items are not leased.
+type MemoryTxHashQueue struct {
+	mu    sync.Mutex
+	items map[string]*TxHashQueueItem
+}
+
+// NewMemoryTxHashQueue creates a new MemoryTxHashQueue.
+func NewMemoryTxHashQueue() *MemoryTxHashQueue {
+	return &MemoryTxHashQueue{items: make(map[string]*TxHashQueueItem)}
+}
+
+// Enqueue adds an event that is due immediately.
+func (q *MemoryTxHashQueue) Enqueue(_ context.Context, event topic.Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	item, ok := q.items[event.ID]
+	if !ok {
+		item = &TxHashQueueItem{ID: event.ID, CreatedAt: now}
+		q.items[event.ID] = item
+	}
+	item.Event = event
+	item.NextAttempt = now
+	item.UpdatedAt = now
+	return nil
+}
+
+// Claim returns up to limit items whose next attempt is before now.
+func (q *MemoryTxHashQueue) Claim(_ context.Context, now time.Time, limit int64) ([]*TxHashQueueItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var due []*TxHashQueueItem
+	for _, item := range q.sorted() {
+		if !item.NextAttempt.After(now) {
+			due = append(due, item)
+		}
+	}
+	return page(due, 0, limit), nil
+}
+
+// ClaimOne returns an item, or ErrTxHashQueueItemNotFound.
+func (q *MemoryTxHashQueue) ClaimOne(ctx context.Context, id string, _ time.Time) (*TxHashQueueItem, error) {
+	return q.FindOne(ctx, id)
+}
+
+// Reschedule updates the attempts and the next attempt time of an item.
+func (q *MemoryTxHashQueue) Reschedule(_ context.Context, id string, attempts int, next time.Time, lastError string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if item, ok := q.items[id]; ok {
+		item.Attempts = attempts
+		item.NextAttempt = next
+		item.LastError = lastError
+		item.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// Remove deletes an item.
+func (q *MemoryTxHashQueue) Remove(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.items, id)
+	return nil
+}
+
+// Find returns a page of items ordered by their next attempt time.
+func (q *MemoryTxHashQueue) Find(_ context.Context, skip, limit int64) ([]*TxHashQueueItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return page(q.sorted(), skip, limit), nil
+}
+
+// FindOne returns an item, or ErrTxHashQueueItemNotFound.
+func (q *MemoryTxHashQueue) FindOne(_ context.Context, id string) (*TxHashQueueItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item, ok := q.items[id]
+	if !ok {
+		return nil, ErrTxHashQueueItemNotFound
+	}
+	c := *item
+	return &c, nil
+}
+
+// Count returns the number of items.
+func (q *MemoryTxHashQueue) Count(_ context.Context) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(len(q.items)), nil
+}
+
+// RetryAll makes every item due at now.
+func (q *MemoryTxHashQueue) RetryAll(_ context.Context, now time.Time) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, item := range q.items {
+		item.NextAttempt = now
+	}
+	return int64(len(q.items)), nil
+}
+
+// sorted returns copies of the items ordered by their next attempt time.
+func (q *MemoryTxHashQueue) sorted() []*TxHashQueueItem {
+	items := make([]*TxHashQueueItem, 0, len(q.items))
+	for _, item := range q.items {
+		c := *item
+		items = append(items, &c)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].NextAttempt.Before(items[j].NextAttempt)
+	})
+	return items
+}
+
+func page(items []*TxHashQueueItem, skip, limit int64) []*TxHashQueueItem {
+	if skip >= int64(len(items)) {
+		return []*TxHashQueueItem{}
+	}
+	items = items[skip:]
+	if limit > 0 && limit < int64(len(items)) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -21,6 +21,8 @@ type Event struct {
 	Revision         uint16     `json:"revision"`
 	Digest           string     `json:"digest"`
 	Overwrite        bool       `json:"overwrite"`
+	// TraceContext is the trace context of the event, it is sent in the message attributes.
+	TraceContext map[string]string `json:"-"`
 }
 
 // PushFunc is a function to push VAAEvent.
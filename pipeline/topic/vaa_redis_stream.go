@@ -0,0 +1,43 @@
+package topic
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// RedisStream represents a VAA topic in a redis stream.
+type RedisStream struct {
+	producer *redisstream.Producer
+	metrics  metrics.Metrics
+	logger   *zap.Logger
+}
+
+// NewVAARedisStream creates a VAA topic in a redis stream instances.
+func NewVAARedisStream(producer *redisstream.Producer, metrics metrics.Metrics, logger *zap.Logger) *RedisStream {
+	return &RedisStream{
+		producer: producer,
+		metrics:  metrics,
+		logger:   logger.With(zap.String("stream", producer.GetStream())),
+	}
+}
+
+// Publish appends the message to a redis stream.
+func (s *RedisStream) Publish(ctx context.Context, message *Event) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("Publishing message", zap.String("groupID", message.ID))
+	err = s.producer.SendMessage(ctx, string(body))
+	if err != nil {
+		s.logger.Error("Error publishing message to redis stream", zap.String("vaaId", message.ID), zap.Error(err))
+		return err
+	}
+	s.metrics.IncVaaSendNotification(message.ChainID)
+	return nil
+}
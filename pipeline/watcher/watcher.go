@@ -53,6 +53,8 @@ type Event struct {
 	Digest           string     `bson:"digest"`
 	IsDuplicated     bool       `bson:"isDuplicated"`
 	DuplicatedFixed  bool       `bson:"duplicatedFixed"`
+	// TraceContext is the trace context of the fly component that inserted the vaa.
+	TraceContext map[string]string `bson:"traceContext"`
 }
 
 const queryTemplate = `
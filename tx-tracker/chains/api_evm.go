@@ -17,8 +17,13 @@ import (
 const (
 	methodEthTxByHash  = "eth_getTransactionByHash"
 	methodEthTxReceipt = "eth_getTransactionReceipt"
+	methodEthBlock     = "eth_getBlockByNumber"
 )
 
+type ethGetBlockByNumberResponse struct {
+	Hash string `json:"hash"`
+}
+
 type ethGetTransactionByHashResponse struct {
 	BlockHash   string `json:"blockHash"`
 	BlockNumber string `json:"blockNumber"`
@@ -100,6 +105,59 @@ func (e *apiEvm) FetchEvmTx(
 	return txDetail, err
 }
 
+// FetchEvmBlockHash returns the hash of the canonical block at the given number.
+func (e *apiEvm) FetchEvmBlockHash(
+	ctx context.Context,
+	pool *pool.Pool,
+	blockNumber string,
+	metrics metrics.Metrics,
+	logger *zap.Logger,
+) (string, error) {
+	// get rpc sorted by score and priority.
+	rpcs := pool.GetItems()
+	if len(rpcs) == 0 {
+		return "", ErrChainNotSupported
+	}
+
+	var blockHash string
+	var err error
+	for _, rpc := range rpcs {
+		// Wait for the RPC rate limiter
+		rpc.Wait(ctx)
+		blockHash, err = e.fetchEvmBlockHash(ctx, rpc.Id, blockNumber)
+		if err != nil {
+			metrics.IncCallRpcError(uint16(e.chainId), rpc.Description)
+			logger.Debug("Failed to fetch block from evm node", zap.String("url", rpc.Id), zap.Error(err))
+			continue
+		}
+		metrics.IncCallRpcSuccess(uint16(e.chainId), rpc.Description)
+		break
+	}
+	return blockHash, err
+}
+
+func (e *apiEvm) fetchEvmBlockHash(
+	ctx context.Context,
+	baseUrl string,
+	blockNumber string,
+) (string, error) {
+	client, err := rpcDialContext(ctx, baseUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize RPC client: %w", err)
+	}
+	defer client.Close()
+
+	var blockReply ethGetBlockByNumberResponse
+	err = client.CallContext(ctx, &blockReply, methodEthBlock, blockNumber, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get block by number: %w", err)
+	}
+	if blockReply.Hash == "" {
+		return "", fmt.Errorf("block %s not found", blockNumber)
+	}
+	return strings.ToLower(blockReply.Hash), nil
+}
+
 func (e *apiEvm) fetchEvmTx(
 	ctx context.Context,
 	baseUrl string,
@@ -146,6 +204,8 @@ func (e *apiEvm) fetchEvmTxByTxHash(
 	txDetail := &TxDetail{
 		From:         strings.ToLower(txReply.From),
 		NativeTxHash: nativeTxHash,
+		BlockNumber:  txReply.BlockNumber,
+		BlockHash:    strings.ToLower(txReply.BlockHash),
 	}
 	return txDetail, nil
 }
@@ -187,13 +247,21 @@ func (e *apiEvm) fetchEvmTxReceiptByTxHash(
 		From:         strings.ToLower(txReceiptResponse.From),
 		NativeTxHash: nativeTxHash,
 		FeeDetail:    feeDetail,
+		BlockNumber:  txReceiptResponse.BlockNumber,
+		BlockHash:    strings.ToLower(txReceiptResponse.BlockHash),
 	}, nil
 }
 
 func EvmCalculateFee(chainID sdk.ChainID, gasUsed string, effectiveGasPrice string) (*decimal.Decimal, error) {
-	//ignore if the blockchain is L2
-	if chainID == sdk.ChainIDBase || chainID == sdk.ChainIDOptimism || chainID == sdk.ChainIDScroll {
-		return nil, nil
+	decimals := int32(18)
+	if chain, ok := domain.Chains.Get(chainID); ok {
+		//ignore if the blockchain is L2
+		if chain.L1DataFee {
+			return nil, nil
+		}
+		if chain.NativeToken != nil {
+			decimals = chain.NativeToken.Decimals
+		}
 	}
 
 	// get decimal gasUsed
@@ -212,8 +280,8 @@ func EvmCalculateFee(chainID sdk.ChainID, gasUsed string, effectiveGasPrice stri
 	}
 	decimalGasPrice := decimal.NewFromBigInt(gp, 0)
 
-	// calculate gasUsed * (gasPrice / 1e18)
+	// calculate gasUsed * (gasPrice / 10^decimals)
 	decimalFee := decimalGasUsed.Mul(decimalGasPrice)
-	decimalFee = decimalFee.DivRound(decimal.NewFromInt(1e18), 18)
+	decimalFee = decimalFee.Shift(-decimals).Round(decimals)
 	return &decimalFee, nil
 }
@@ -0,0 +1,140 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/pool"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// newEvmBlockServer returns a json-rpc node that serves the hashes of the given blocks by number.
+func newEvmBlockServer(t *testing.T, blocks map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []any           `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, methodEthBlock, req.Method)
+		// the transactions of the block are not requested.
+		assert.Equal(t, false, req.Params[1])
+
+		var result any
+		if hash, ok := blocks[req.Params[0].(string)]; ok {
+			result = map[string]string{"hash": hash}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newEvmPool(urls ...string) *pool.Pool {
+	var cfg []pool.Config
+	for i, url := range urls {
+		cfg = append(cfg, pool.Config{Id: url, Description: url, Priority: uint8(i), RequestsPerMinute: 60000})
+	}
+	return pool.NewPool(cfg)
+}
+
+func TestFetchEvmBlockHash(t *testing.T) {
+	server := newEvmBlockServer(t, map[string]string{"0x10": "0xABCDEF"})
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(down.Close)
+
+	tcs := []struct {
+		name        string
+		pool        *pool.Pool
+		blockNumber string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:        "block found",
+			pool:        newEvmPool(server.URL),
+			blockNumber: "0x10",
+			expected:    "0xabcdef",
+		},
+		{
+			name:        "block found by the next rpc",
+			pool:        newEvmPool(down.URL, server.URL),
+			blockNumber: "0x10",
+			expected:    "0xabcdef",
+		},
+		{
+			name:        "block not found",
+			pool:        newEvmPool(server.URL),
+			blockNumber: "0x11",
+		},
+		{
+			name:        "all rpcs fail",
+			pool:        newEvmPool(down.URL),
+			blockNumber: "0x10",
+		},
+		{
+			name:        "no rpcs",
+			pool:        newEvmPool(),
+			blockNumber: "0x10",
+			expectedErr: ErrChainNotSupported,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			api := &apiEvm{chainId: sdk.ChainIDEthereum}
+			hash, err := api.FetchEvmBlockHash(context.Background(), tc.pool, tc.blockNumber, metrics.NewDummyMetrics(), zap.NewNop())
+			if tc.expected == "" {
+				require.Error(t, err)
+				if tc.expectedErr != nil {
+					assert.ErrorIs(t, err, tc.expectedErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, hash)
+		})
+	}
+}
+
+func TestFetchBlockHash_NotSupported(t *testing.T) {
+	server := newEvmBlockServer(t, map[string]string{"0x10": "0xabcdef"})
+	rpcPool := map[sdk.ChainID]*pool.Pool{
+		sdk.ChainIDEthereum: newEvmPool(server.URL),
+		sdk.ChainIDSolana:   newEvmPool(server.URL),
+	}
+
+	tcs := []struct {
+		name        string
+		chainID     sdk.ChainID
+		blockNumber string
+	}{
+		{name: "not evm chain", chainID: sdk.ChainIDSolana, blockNumber: "0x10"},
+		{name: "evm chain without rpc pool", chainID: sdk.ChainIDPolygon, blockNumber: "0x10"},
+		{name: "unknown block number", chainID: sdk.ChainIDEthereum, blockNumber: ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := FetchBlockHash(context.Background(), rpcPool, tc.chainID, tc.blockNumber, metrics.NewDummyMetrics(), zap.NewNop())
+			assert.ErrorIs(t, err, ErrChainNotSupported)
+		})
+	}
+
+	hash, err := FetchBlockHash(context.Background(), rpcPool, sdk.ChainIDEthereum, "0x10", metrics.NewDummyMetrics(), zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "0xabcdef", hash)
+}
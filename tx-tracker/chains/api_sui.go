@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/wormhole-foundation/wormhole-explorer/common/pool"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
 	"go.uber.org/zap"
@@ -66,7 +65,7 @@ func fetchSuiTx(
 ) (*TxDetail, error) {
 
 	// Initialize RPC client
-	client, err := rpc.DialContext(ctx, baseUrl)
+	client, err := rpcDialContext(ctx, baseUrl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize RPC client: %w", err)
 	}
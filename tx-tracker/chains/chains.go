@@ -8,12 +8,18 @@ import (
 
 	notional "github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	"github.com/wormhole-foundation/wormhole-explorer/common/pool"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+var tracer = telemetry.Tracer("github.com/wormhole-foundation/wormhole-explorer/txtracker/chains")
+
 var (
 	ErrChainNotSupported   = errors.New("chain id not supported")
 	ErrTransactionNotFound = errors.New("transaction not found")
@@ -28,6 +34,10 @@ type TxDetail struct {
 	Attribute *AttributeTxDetail
 	// FeeDetail contains the fee of the transactions.
 	FeeDetail *FeeDetail
+	// BlockNumber and BlockHash identify the block that includes the transaction.
+	// They are only set for the chains whose API exposes them, and are used to detect reorgs.
+	BlockNumber string
+	BlockHash   string
 }
 
 type FeeDetail struct {
@@ -42,6 +52,25 @@ type AttributeTxDetail struct {
 	Value any
 }
 
+// FetchBlockHash returns the hash of the canonical block at the given number.
+// Only the evm chains are supported, ErrChainNotSupported is returned for the other chains.
+func FetchBlockHash(
+	ctx context.Context,
+	rpcPool map[sdk.ChainID]*pool.Pool,
+	chainId sdk.ChainID,
+	blockNumber string,
+	m metrics.Metrics,
+	logger *zap.Logger,
+) (string, error) {
+	pool, ok := rpcPool[chainId]
+	if !ok || !domain.Chains.IsEVM(chainId) || blockNumber == "" {
+		return "", ErrChainNotSupported
+	}
+
+	apiEvm := &apiEvm{chainId: chainId}
+	return apiEvm.FetchEvmBlockHash(ctx, pool, blockNumber, m, logger)
+}
+
 func FetchTx(
 	ctx context.Context,
 	rpcPool map[sdk.ChainID]*pool.Pool,
@@ -78,37 +107,6 @@ func FetchTx(
 			chainId: chainId,
 		}
 		fetchFunc = apiCosmos.FetchCosmosTx
-	case sdk.ChainIDAcala,
-		sdk.ChainIDArbitrum,
-		sdk.ChainIDArbitrumSepolia,
-		sdk.ChainIDAvalanche,
-		sdk.ChainIDBase,
-		sdk.ChainIDBaseSepolia,
-		sdk.ChainIDBSC,
-		sdk.ChainIDCelo,
-		sdk.ChainIDEthereum,
-		sdk.ChainIDSepolia,
-		sdk.ChainIDFantom,
-		sdk.ChainIDKarura,
-		sdk.ChainIDKlaytn,
-		sdk.ChainIDMoonbeam,
-		sdk.ChainIDOasis,
-		sdk.ChainIDOptimism,
-		sdk.ChainIDOptimismSepolia,
-		sdk.ChainIDPolygon,
-		sdk.ChainIDScroll,
-		sdk.ChainIDBlast,
-		sdk.ChainIDXLayer,
-		sdk.ChainIDMantle,
-		sdk.ChainIDPolygonSepolia, // polygon amoy
-		sdk.ChainIDSnaxchain,
-		sdk.ChainIDUnichain:
-		apiEvm := &apiEvm{
-			chainId:       chainId,
-			notionalCache: notionalCache,
-			p2pNetwork:    p2pNetwork,
-		}
-		fetchFunc = apiEvm.FetchEvmTx
 	case sdk.ChainIDWormchain:
 		apiWormchain := &apiWormchain{
 			p2pNetwork:    p2pNetwork,
@@ -125,7 +123,19 @@ func FetchTx(
 		}
 		fetchFunc = apiSei.FetchSeiTx
 	default:
-		return nil, ErrChainNotSupported
+		if !domain.Chains.IsEVM(chainId) {
+			return nil, ErrChainNotSupported
+		}
+		// the evm chains without a rpc pool are not supported by this deployment.
+		if _, ok := rpcPool[chainId]; !ok {
+			return nil, ErrChainNotSupported
+		}
+		apiEvm := &apiEvm{
+			chainId:       chainId,
+			notionalCache: notionalCache,
+			p2pNetwork:    p2pNetwork,
+		}
+		fetchFunc = apiEvm.FetchEvmTx
 	}
 
 	pool, ok := rpcPool[chainId]
@@ -133,7 +143,10 @@ func FetchTx(
 		return nil, fmt.Errorf("not found rpc pool for chain %s", chainId.String())
 	}
 
+	ctx, span := tracer.Start(ctx, "chains.FetchTx",
+		trace.WithAttributes(attribute.String("chain", chainId.String()), attribute.String("txHash", txHash)))
 	txDetail, err := fetchFunc(ctx, pool, txHash, m, logger)
+	telemetry.End(span, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve tx information: %w", err)
 	}
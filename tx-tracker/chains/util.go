@@ -11,11 +11,17 @@ import (
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ethereum/go-ethereum/rpc"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
+// httpClient is the client of the HTTP requests, it records a span for each one.
+var httpClient = http.Client{Transport: telemetry.NewTransport(nil)}
+
 // httpGet is a helper function that performs an HTTP request.
 func httpGet(ctx context.Context, url string) ([]byte, error) {
 
@@ -26,8 +32,7 @@ func httpGet(ctx context.Context, url string) ([]byte, error) {
 	}
 
 	// Send it
-	var client http.Client
-	response, err := client.Do(request)
+	response, err := httpClient.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query url: %w", err)
 	}
@@ -61,8 +66,7 @@ func httpPost(ctx context.Context, url string, body any) ([]byte, error) {
 	request.Header.Set("Content-Type", "application/json")
 
 	// Send it
-	var client http.Client
-	response, err := client.Do(request)
+	response, err := httpClient.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query url: %w", err)
 	}
@@ -101,7 +105,12 @@ func (c *rateLimitedRpcClient) CallContext(
 	result interface{},
 	method string,
 	args ...interface{},
-) error {
+) (err error) {
+	ctx, span := tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("rpc.system", "jsonrpc"), attribute.String("rpc.method", method)))
+	defer func() { telemetry.End(span, err) }()
+
 	return c.client.CallContext(ctx, result, method, args...)
 }
 
@@ -0,0 +1,189 @@
+package backfiller
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
+	"github.com/wormhole-foundation/wormhole-explorer/common/dbutil"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/chains"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/config"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/consumer"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/ratelimit"
+	"go.uber.org/zap"
+)
+
+// FeesBackfiller computes the fee of the source and destination transactions that were stored without it.
+type FeesBackfiller struct {
+	P2pNetwork        string
+	LogLevel          string
+	MongoURI          string
+	MongoDatabase     string
+	RequestsPerMinute int64
+	StartTime         string
+	EndTime           string
+	Side              string
+	PageSize          int64
+	RpcProvidersPath  string
+	// CurrentUSD keeps the fee in USD calculated with the current gas token price.
+	// It is disabled by default because the price at the time of the transaction is unknown.
+	CurrentUSD bool
+}
+
+func RunByFees(backfillerConfig *FeesBackfiller) {
+
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := config.NewRpcProviderSettingJson(backfillerConfig.RpcProvidersPath)
+	if err != nil {
+		log.Fatal("Failed to load config: ", err)
+	}
+
+	// create rpc pool
+	rpcPool, wormchainRpcPool, err := newRpcPool(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize rpc pool: ", zap.Error(err))
+	}
+
+	logger := logger.New("wormhole-explorer-tx-tracker", logger.WithLevel(backfillerConfig.LogLevel))
+
+	logger.Info("Starting wormhole-explorer-tx-tracker as fees backfiller ...")
+
+	startTime, err := time.Parse(time.RFC3339, backfillerConfig.StartTime)
+	if err != nil {
+		logger.Fatal("failed to parse start time", zap.Error(err))
+	}
+
+	endTime := time.Now()
+	if backfillerConfig.EndTime != "" {
+		endTime, err = time.Parse(time.RFC3339, backfillerConfig.EndTime)
+		if err != nil {
+			logger.Fatal("Failed to parse end time", zap.Error(err))
+		}
+	}
+
+	if startTime.After(endTime) {
+		logger.Fatal("Start time should be before end time",
+			zap.String("start_time", startTime.Format(time.RFC3339)),
+			zap.String("end_time", endTime.Format(time.RFC3339)))
+	}
+
+	var sides []consumer.TxSide
+	switch backfillerConfig.Side {
+	case "source":
+		sides = []consumer.TxSide{consumer.TxSideOrigin}
+	case "destination":
+		sides = []consumer.TxSide{consumer.TxSideDestination}
+	case "all":
+		sides = []consumer.TxSide{consumer.TxSideOrigin, consumer.TxSideDestination}
+	default:
+		logger.Fatal("Invalid side, expected source, destination or all", zap.String("side", backfillerConfig.Side))
+	}
+
+	// the fee is only calculated for evm and solana transactions.
+	var chainIDs []sdk.ChainID
+	for chainID := range rpcPool {
+		if chainID == sdk.ChainIDSolana || domain.Chains.IsEVM(chainID) {
+			chainIDs = append(chainIDs, chainID)
+		}
+	}
+	if len(chainIDs) == 0 {
+		logger.Fatal("No rpc providers configured for evm or solana chains")
+	}
+
+	//setup DB connection
+	db, err := dbutil.Connect(ctx, logger, backfillerConfig.MongoURI, backfillerConfig.MongoDatabase, false)
+	if err != nil {
+		logger.Fatal("failed to connect MongoDB", zap.Error(err))
+	}
+
+	// create a consumer repository.
+	globalTrxRepository := consumer.NewRepository(logger, db.Database)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: cfg.NotionalCacheURL})
+	notionalCache, errCache := notional.NewNotionalCache(ctx, redisClient, cfg.NotionalCachePrefix, cfg.NotionalCacheChannel, logger)
+	if errCache != nil {
+		logger.Fatal("Failed to create notional cache", zap.Error(errCache))
+	}
+	errCache = notionalCache.Init(ctx)
+	if errCache != nil {
+		logger.Fatal("Failed to initialize notional cache", zap.Error(errCache))
+	}
+
+	limiter := ratelimit.New(int(backfillerConfig.RequestsPerMinute), ratelimit.Per(time.Minute))
+	metrics := metrics.NewDummyMetrics()
+
+	var processed, updated, failed uint64
+	for _, side := range sides {
+		sideLogger := logger.With(zap.String("side", string(side)))
+		afterID := ""
+		for {
+			txs, err := globalTrxRepository.FindTxsWithoutFee(ctx, side, chainIDs, startTime, endTime, afterID, backfillerConfig.PageSize)
+			if err != nil {
+				sideLogger.Error("Failed to get transactions without fee", zap.Error(err))
+				break
+			}
+			if len(txs) == 0 {
+				sideLogger.Info("Empty page", zap.String("afterId", afterID))
+				break
+			}
+
+			for _, tx := range txs {
+				afterID = tx.ID
+				processed++
+				limiter.Take()
+
+				txDetail, err := chains.FetchTx(ctx, rpcPool, wormchainRpcPool, tx.ChainID, tx.TxHash, tx.Timestamp,
+					backfillerConfig.P2pNetwork, metrics, sideLogger, notionalCache)
+				if err != nil {
+					sideLogger.Error("Failed to fetch transaction",
+						zap.String("id", tx.ID),
+						zap.String("txHash", tx.TxHash),
+						zap.Error(err))
+					failed++
+					continue
+				}
+				if txDetail == nil || txDetail.FeeDetail == nil {
+					sideLogger.Debug("Transaction without fee", zap.String("id", tx.ID), zap.String("txHash", tx.TxHash))
+					continue
+				}
+
+				feeDetail := &consumer.FeeDetail{
+					Fee:    txDetail.FeeDetail.Fee,
+					RawFee: txDetail.FeeDetail.RawFee,
+				}
+				if backfillerConfig.CurrentUSD {
+					feeDetail.GasTokenNotional = txDetail.FeeDetail.GasTokenNotional
+					feeDetail.FeeUSD = txDetail.FeeDetail.FeeUSD
+				}
+
+				if err := globalTrxRepository.UpdateFeeDetail(ctx, tx.ID, side, feeDetail); err != nil {
+					sideLogger.Error("Failed to update fee detail", zap.String("id", tx.ID), zap.Error(err))
+					failed++
+					continue
+				}
+				updated++
+			}
+			sideLogger.Info("Processed page",
+				zap.String("afterId", afterID),
+				zap.Uint64("processed", processed),
+				zap.Uint64("updated", updated),
+				zap.Uint64("failed", failed))
+		}
+	}
+
+	logger.Info("closing MongoDB connection...")
+	db.DisconnectWithTimeout(10 * time.Second)
+
+	logger.Info("Finish wormhole-explorer-tx-tracker as fees backfiller",
+		zap.Uint64("processed", processed),
+		zap.Uint64("updated", updated),
+		zap.Uint64("failed", failed))
+}
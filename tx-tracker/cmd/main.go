@@ -2,7 +2,9 @@ package main
 
 import (
 	"github.com/spf13/cobra"
+	common_dlq "github.com/wormhole-foundation/wormhole-explorer/common/dlq"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/cmd/backfiller"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/cmd/dlq"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/cmd/service"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
@@ -23,6 +25,7 @@ func execute() error {
 
 	addServiceCommand(root)
 	addBackfiller(root)
+	addDlqCommand(root)
 
 	return root.Execute()
 }
@@ -44,6 +47,7 @@ func addBackfiller(parent *cobra.Command) {
 	}
 
 	addBackfillerByVaas(backfiller)
+	addBackfillerByFees(backfiller)
 	parent.AddCommand(backfiller)
 }
 
@@ -106,3 +110,91 @@ func addBackfillerByVaas(parent *cobra.Command) {
 
 	parent.AddCommand(vaas)
 }
+
+func addBackfillerByFees(parent *cobra.Command) {
+	var mongoUri, mongoDb, logLevel, startTime, endTime, p2pNetwork, side, rpcProvidersPath string
+	var pageSize, requestsPerMinute int64
+	var currentUSD bool
+
+	fees := &cobra.Command{
+		Use:   "fees",
+		Short: "Run backfiller for transaction fees",
+		Run: func(_ *cobra.Command, _ []string) {
+			cfg := &backfiller.FeesBackfiller{
+				LogLevel:          logLevel,
+				P2pNetwork:        p2pNetwork,
+				MongoURI:          mongoUri,
+				MongoDatabase:     mongoDb,
+				RequestsPerMinute: requestsPerMinute,
+				StartTime:         startTime,
+				EndTime:           endTime,
+				Side:              side,
+				PageSize:          pageSize,
+				RpcProvidersPath:  rpcProvidersPath,
+				CurrentUSD:        currentUSD,
+			}
+			backfiller.RunByFees(cfg)
+		},
+	}
+
+	fees.Flags().StringVar(&logLevel, "log-level", "INFO", "log level")
+	fees.Flags().StringVar(&p2pNetwork, "p2p-network", "", "P2P network to use")
+	fees.Flags().StringVar(&mongoUri, "mongo-uri", "", "Mongo connection")
+	fees.Flags().StringVar(&mongoDb, "mongo-database", "", "Mongo database")
+	fees.Flags().StringVar(&startTime, "start-time", "1970-01-01T00:00:00Z", "minimum transaction timestamp to process")
+	fees.Flags().StringVar(&endTime, "end-time", "", "maximum transaction timestamp to process (default now)")
+	fees.Flags().StringVar(&side, "side", "all", "transactions to process: source, destination or all")
+	fees.Flags().Int64Var(&pageSize, "page-size", 100, "number of documents retrieved at a time")
+	fees.Flags().Int64Var(&requestsPerMinute, "requests-per-minute", 12, "maximum number of requests per minute to process transactions")
+	fees.Flags().BoolVar(&currentUSD, "current-usd", false, "store the fee in USD using the current gas token price")
+	fees.Flags().StringVar(&rpcProvidersPath, "rpc-providers-path", "", "path to rpc providers file")
+
+	fees.MarkFlagRequired("mongo-uri")
+	fees.MarkFlagRequired("p2p-network")
+	fees.MarkFlagRequired("mongo-database")
+	fees.MarkFlagRequired("rpc-providers-path")
+
+	parent.AddCommand(fees)
+}
+
+func addDlqCommand(root *cobra.Command) {
+	cfg := &dlq.Settings{}
+	dlqCommand := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspect and redrive the messages of a dead-letter queue",
+	}
+
+	flags := dlqCommand.PersistentFlags()
+	flags.StringVar(&cfg.LogLevel, "log-level", "INFO", "log level")
+	flags.StringVar(&cfg.AwsRegion, "aws-region", "", "AWS region")
+	flags.StringVar(&cfg.AwsEndpoint, "aws-endpoint", "", "AWS endpoint, for local environments")
+	flags.StringVar(&cfg.DlqUrl, "dlq-url", "", "dead-letter queue url")
+	flags.StringVar(&cfg.QueueUrl, "queue-url", "", "url of the queue the messages are replayed to")
+	flags.StringVar(&cfg.Source, "source", "pipeline", "queue the dead-letter queue belongs to: pipeline or notifications")
+	flags.IntVar(&cfg.MaxMessages, "max-messages", 100, "maximum number of messages to receive")
+	flags.Int32Var(&cfg.VisibilityTimeout, "visibility-timeout", 120, "seconds the received messages are hidden from other consumers of the dead-letter queue")
+	flags.Uint16Var(&cfg.ChainID, "chain", 0, "select the messages of a chain id")
+	flags.StringVar(&cfg.Error, "error", "", "select the messages whose error contains the value")
+	flags.StringSliceVar(&cfg.IDs, "id", nil, "select the messages by message id or vaa id")
+	flags.BoolVar(&cfg.DryRun, "dry-run", false, "report the messages without replaying or purging them")
+
+	dlqCommand.MarkPersistentFlagRequired("aws-region")
+	dlqCommand.MarkPersistentFlagRequired("dlq-url")
+
+	actions := map[common_dlq.Action]string{
+		common_dlq.ActionList:   "List the messages grouped by error and chain",
+		common_dlq.ActionReplay: "Send the messages to the queue and delete them from the dead-letter queue",
+		common_dlq.ActionPurge:  "Delete the messages from the dead-letter queue",
+	}
+	for _, action := range []common_dlq.Action{common_dlq.ActionList, common_dlq.ActionReplay, common_dlq.ActionPurge} {
+		action := action
+		dlqCommand.AddCommand(&cobra.Command{
+			Use:   string(action),
+			Short: actions[action],
+			Run: func(_ *cobra.Command, _ []string) {
+				dlq.Run(action, cfg)
+			},
+		})
+	}
+	root.AddCommand(dlqCommand)
+}
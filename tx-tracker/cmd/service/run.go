@@ -16,12 +16,15 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/redisstream"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
 	"github.com/wormhole-foundation/wormhole-explorer/common/configuration"
 	"github.com/wormhole-foundation/wormhole-explorer/common/dbutil"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	"github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
 	"github.com/wormhole-foundation/wormhole-explorer/common/pool"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/config"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/consumer"
@@ -50,6 +53,23 @@ func Run() {
 
 	logger.Info("Starting wormhole-explorer-tx-tracker ...")
 
+	// set up tracing
+	shutdownTracing, err := telemetry.Setup(rootCtx, telemetry.Config{
+		ServiceName:  "wormhole-explorer-tx-tracker",
+		Environment:  cfg.Environment,
+		OtlpEndpoint: cfg.TracingOtlpEndpoint,
+		OtlpInsecure: cfg.TracingOtlpInsecure,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+
+	// apply the chain registry overrides
+	if err := domain.Chains.LoadOverrides(cfg.ChainsOverridesFile); err != nil {
+		logger.Fatal("failed to load chain overrides", zap.Error(err))
+	}
+
 	// create rpc pool
 	rpcPool, wormchainRpcPool, err := newRpcPool(cfg)
 	if err != nil {
@@ -97,6 +117,19 @@ func Run() {
 	notificationConsumer := consumer.New(notificationConsumeFunc, rpcPool, wormchainRpcPool, logger, repository, metrics, cfg.P2pNetwork, cfg.ConsumerWorkersSize, notionalCache)
 	notificationConsumer.Start(rootCtx)
 
+	// create and start the reorg checker of the source transactions.
+	if cfg.ReorgCheckerEnabled {
+		reorgCheckerCfg := consumer.ReorgCheckerConfig{
+			Interval:          cfg.ReorgCheckerInterval,
+			PageSize:          cfg.ReorgCheckerPageSize,
+			NotFoundThreshold: cfg.ReorgCheckerNotFoundThreshold,
+			MaxRetries:        cfg.ReorgCheckerMaxRetries,
+		}
+		reorgChecker := consumer.NewReorgChecker(rpcPool, wormchainRpcPool, repository, metrics, cfg.P2pNetwork,
+			notionalCache, reorgCheckerCfg, logger)
+		reorgChecker.Start(rootCtx)
+	}
+
 	logger.Info("Started wormhole-explorer-tx-tracker")
 
 	// Waiting for signal
@@ -119,6 +152,13 @@ func Run() {
 	logger.Info("Closing MongoDB connection...")
 	db.DisconnectWithTimeout(10 * time.Second)
 
+	logger.Info("flushing traces...")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", zap.Error(err))
+	}
+	shutdownCancel()
+
 	logger.Info("Terminated wormhole-explorer-tx-tracker")
 }
 
@@ -129,6 +169,10 @@ func newVAAConsumeFunc(
 	logger *zap.Logger,
 ) queue.ConsumeFunc {
 
+	if cfg.ConsumerQueue == "redis-stream" {
+		return newRedisStreamConsumeFunc(ctx, cfg, cfg.PipelineRedisStream, queue.NewVaaConverter(logger), metrics, logger)
+	}
+
 	sqsConsumer, err := newSqsConsumer(ctx, cfg, cfg.PipelineSqsUrl)
 	if err != nil {
 		logger.Fatal("failed to create sqs consumer", zap.Error(err))
@@ -145,6 +189,10 @@ func newNotificationConsumeFunc(
 	logger *zap.Logger,
 ) queue.ConsumeFunc {
 
+	if cfg.ConsumerQueue == "redis-stream" {
+		return newRedisStreamConsumeFunc(ctx, cfg, cfg.NotificationsRedisStream, queue.NewNotificationEvent(logger), metrics, logger)
+	}
+
 	sqsConsumer, err := newSqsConsumer(ctx, cfg, cfg.NotificationsSqsUrl)
 	if err != nil {
 		logger.Fatal("failed to create sqs consumer", zap.Error(err))
@@ -154,6 +202,30 @@ func newNotificationConsumeFunc(
 	return vaaQueue.Consume
 }
 
+func newRedisStreamConsumeFunc(
+	ctx context.Context,
+	cfg *config.ServiceSettings,
+	stream string,
+	converter queue.ConverterFunc,
+	metrics metrics.Metrics,
+	logger *zap.Logger,
+) queue.ConsumeFunc {
+
+	client := redis.NewClient(&redis.Options{Addr: cfg.RedisStreamUri})
+	consumer, err := redisstream.NewConsumer(ctx, client, stream, cfg.RedisStreamGroup,
+		redisstream.WithMaxMessages(10),
+		redisstream.WithVisibilityTimeout(60*time.Second),
+		redisstream.WithMaxDeliveries(cfg.RedisStreamMaxDeliveries),
+		redisstream.WithLogger(logger),
+	)
+	if err != nil {
+		logger.Fatal("failed to create redis stream consumer", zap.Error(err))
+	}
+
+	vaaQueue := queue.NewEventRedisStream(consumer, converter, metrics, logger)
+	return vaaQueue.Consume
+}
+
 func newSqsConsumer(ctx context.Context, cfg *config.ServiceSettings, sqsUrl string) (*sqs.Consumer, error) {
 
 	awsconfig, err := newAwsConfig(ctx, cfg)
@@ -207,6 +279,11 @@ func makeHealthChecks(
 	db *mongo.Database,
 ) ([]health.Check, error) {
 
+	if config.ConsumerQueue == "redis-stream" {
+		client := redis.NewClient(&redis.Options{Addr: config.RedisStreamUri})
+		return []health.Check{health.Redis(client), health.Mongo(db)}, nil
+	}
+
 	awsConfig, err := newAwsConfig(ctx, config)
 	if err != nil {
 		return nil, err
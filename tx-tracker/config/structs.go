@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -16,18 +17,34 @@ import (
 
 type ServiceSettings struct {
 	// MonitoringPort defines the TCP port for the /health and /ready endpoints.
-	MonitoringPort       string `split_words:"true" default:"8000"`
-	Environment          string `split_words:"true" required:"true"`
-	LogLevel             string `split_words:"true" default:"INFO"`
-	PprofEnabled         bool   `split_words:"true" default:"false"`
-	MetricsEnabled       bool   `split_words:"true" default:"false"`
-	P2pNetwork           string `split_words:"true" required:"true"`
-	RpcProviderPath      string `split_words:"true" required:"false"`
-	ConsumerWorkersSize  int    `split_words:"true" default:"10"`
-	NotionalCacheURL     string `split_words:"true" required:"true"`
-	NotionalCachePrefix  string `split_words:"true" required:"true"`
-	NotionalCacheChannel string `split_words:"true" required:"true"`
+	MonitoringPort       string  `split_words:"true" default:"8000"`
+	Environment          string  `split_words:"true" required:"true"`
+	LogLevel             string  `split_words:"true" default:"INFO"`
+	PprofEnabled         bool    `split_words:"true" default:"false"`
+	MetricsEnabled       bool    `split_words:"true" default:"false"`
+	P2pNetwork           string  `split_words:"true" required:"true"`
+	ChainsOverridesFile  string  `split_words:"true" required:"false"`
+	RpcProviderPath      string  `split_words:"true" required:"false"`
+	ConsumerWorkersSize  int     `split_words:"true" default:"10"`
+	NotionalCacheURL     string  `split_words:"true" required:"true"`
+	NotionalCachePrefix  string  `split_words:"true" required:"true"`
+	NotionalCacheChannel string  `split_words:"true" required:"true"`
+	TracingOtlpEndpoint  string  `split_words:"true" required:"false"`
+	TracingOtlpInsecure  bool    `split_words:"true" default:"false"`
+	TracingSampleRatio   float64 `split_words:"true" default:"1"`
+	// ReorgCheckerEnabled re-verifies the block of the source transactions within the finality window of their chain.
+	ReorgCheckerEnabled  bool          `split_words:"true" default:"false"`
+	ReorgCheckerInterval time.Duration `split_words:"true" default:"1m"`
+	ReorgCheckerPageSize int64         `split_words:"true" default:"100"`
+	// ReorgCheckerNotFoundThreshold is the number of consecutive checks that must not find a transaction
+	// to drop it when the block at its number can not be compared.
+	ReorgCheckerNotFoundThreshold int `split_words:"true" default:"3"`
+	// ReorgCheckerMaxRetries is the number of checks that look up a dropped transaction again.
+	ReorgCheckerMaxRetries int `split_words:"true" default:"10"`
+	// ConsumerQueue is where the events are consumed from: sqs (default) or redis-stream.
+	ConsumerQueue string `split_words:"true" default:"sqs"`
 	AwsSettings
+	RedisStreamSettings
 	MongodbSettings
 	*RpcProviderSettings        `required:"false"`
 	*WormchainProviderSettings  `required:"false"`
@@ -59,9 +76,19 @@ type AwsSettings struct {
 	AwsEndpoint         string `split_words:"true" required:"false"`
 	AwsAccessKeyID      string `split_words:"true" required:"false"`
 	AwsSecretAccessKey  string `split_words:"true" required:"false"`
-	AwsRegion           string `split_words:"true" required:"true"`
-	PipelineSqsUrl      string `split_words:"true" required:"true"`
-	NotificationsSqsUrl string `split_words:"true" required:"true"`
+	AwsRegion           string `split_words:"true" required:"false"`
+	PipelineSqsUrl      string `split_words:"true" required:"false"`
+	NotificationsSqsUrl string `split_words:"true" required:"false"`
+}
+
+// RedisStreamSettings defines the redis streams to consume the events from, instead of SQS.
+// Each stream is consumed with the same consumer group by all the replicas.
+type RedisStreamSettings struct {
+	RedisStreamUri           string `split_words:"true" required:"false"`
+	PipelineRedisStream      string `split_words:"true" required:"false"`
+	NotificationsRedisStream string `split_words:"true" required:"false"`
+	RedisStreamGroup         string `split_words:"true" default:"tx-tracker"`
+	RedisStreamMaxDeliveries int64  `split_words:"true" default:"10"`
 }
 
 type MongodbSettings struct {
@@ -259,6 +286,19 @@ func New() (*ServiceSettings, error) {
 		return nil, fmt.Errorf("failed to read config from environment: %w", err)
 	}
 
+	switch settings.ConsumerQueue {
+	case "sqs":
+		if settings.AwsRegion == "" || settings.PipelineSqsUrl == "" || settings.NotificationsSqsUrl == "" {
+			return nil, errors.New("aws region, pipeline and notifications sqs urls are required for the sqs consumer queue")
+		}
+	case "redis-stream":
+		if settings.RedisStreamUri == "" || settings.PipelineRedisStream == "" || settings.NotificationsRedisStream == "" {
+			return nil, errors.New("redis uri, pipeline and notifications streams are required for the redis-stream consumer queue")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported consumer queue: %s", settings.ConsumerQueue)
+	}
+
 	if settings.RpcProviderPath != "" {
 		rpcJsonFile, err := os.ReadFile(settings.RpcProviderPath)
 		if err != nil {
@@ -15,6 +15,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// errMissingTargetAttributes is the error of the target chain events without attributes.
+var errMissingTargetAttributes = errors.New("missing target chain attributes")
+
 // Consumer consumer struct definition.
 type Consumer struct {
 	consumeFunc      queue.ConsumeFunc
@@ -73,9 +76,9 @@ func (c *Consumer) producerLoop(ctx context.Context, ch <-chan queue.ConsumerMes
 			c.logger.Debug("Received message", zap.String("vaaId", msg.Data().ID), zap.String("trackId", msg.Data().TrackID))
 			switch msg.Data().Type {
 			case queue.SourceChainEvent:
-				c.processSourceTx(ctx, msg)
+				c.processSourceTx(msg.Context(), msg)
 			case queue.TargetChainEvent:
-				c.processTargetTx(ctx, msg)
+				c.processTargetTx(msg.Context(), msg)
 			default:
 				c.logger.Error("Unknown message type", zap.String("trackId", msg.Data().TrackID), zap.Any("type", msg.Data().Type))
 			}
@@ -142,7 +145,7 @@ func (c *Consumer) processSourceTx(ctx context.Context, msg queue.ConsumerMessag
 			elapsedLog,
 		)
 	} else if err != nil {
-		msg.Failed()
+		msg.Failed(err)
 		c.logger.Error("Failed to process originTx",
 			zap.String("trackId", event.TrackID),
 			zap.String("vaaId", event.ID),
@@ -166,7 +169,7 @@ func (c *Consumer) processTargetTx(ctx context.Context, msg queue.ConsumerMessag
 
 	attr, ok := queue.GetAttributes[*queue.TargetChainAttributes](event)
 	if !ok || attr == nil {
-		msg.Failed()
+		msg.Failed(errMissingTargetAttributes)
 		c.logger.Error("Failed to get attributes from message", zap.String("trackId", event.TrackID), zap.String("vaaId", event.ID))
 		return
 	}
@@ -212,7 +215,7 @@ func (c *Consumer) processTargetTx(ctx context.Context, msg queue.ConsumerMessag
 
 	elapsedLog := zap.Uint64("elapsedTime", uint64(time.Since(start).Milliseconds()))
 	if err != nil {
-		msg.Failed()
+		msg.Failed(err)
 		c.logger.Error("Failed to process destinationTx",
 			zap.String("trackId", event.TrackID),
 			zap.String("vaaId", event.ID),
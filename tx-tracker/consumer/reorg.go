@@ -0,0 +1,292 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/pool"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/chains"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// reorgCheckerLock is the name of the lock that elects the replica running the reorg checker.
+const reorgCheckerLock = "tx-tracker-reorg-checker"
+
+// ReorgCheckerConfig holds the settings of the reorg checker.
+type ReorgCheckerConfig struct {
+	// Interval is the time between two checks.
+	Interval time.Duration
+	// PageSize is the number of source transactions read from the database at once.
+	PageSize int64
+	// NotFoundThreshold is the number of consecutive checks that must not find a transaction to consider it
+	// dropped, when the block at its number can not be compared.
+	NotFoundThreshold int
+	// MaxRetries is the number of checks that look up a dropped transaction again before giving up.
+	MaxRetries int
+}
+
+// reorgRepository decouples the reorg checker from the repository.
+type reorgRepository interface {
+	AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	FindRecentOriginTxs(ctx context.Context, chainID sdk.ChainID, from time.Time, afterID string, limit int64) ([]RecentOriginTx, error)
+	FindReorgedOriginTxs(ctx context.Context, chainID sdk.ChainID, maxRetries int, afterID string, limit int64) ([]RecentOriginTx, error)
+	IncOriginTxNotFound(ctx context.Context, id string) (int, error)
+	ResetOriginTxNotFound(ctx context.Context, id string) error
+	UpdateOriginTxReorg(ctx context.Context, id string, txDetail *chains.TxDetail) error
+	RestoreOriginTxReorg(ctx context.Context, id string, txDetail *chains.TxDetail) error
+	IncOriginTxReorgRetries(ctx context.Context, id string) error
+}
+
+// ReorgChecker re-verifies the block of the source transactions indexed within the finality window of their chain,
+// and corrects the documents whose transaction was moved to another block or dropped by a reorg.
+// Only the EVM chains are re-verified, since the block of a transaction is only stored for them.
+// The dropped transactions are looked up again on the next checks until they are found or MaxRetries is reached.
+// Only the replica holding the lock of the checker runs it.
+type ReorgChecker struct {
+	rpcPool          map[sdk.ChainID]*pool.Pool
+	wormchainRpcPool map[sdk.ChainID]*pool.Pool
+	repository       reorgRepository
+	metrics          metrics.Metrics
+	p2pNetwork       string
+	notionalCache    *notional.NotionalCache
+	cfg              ReorgCheckerConfig
+	owner            string
+	logger           *zap.Logger
+}
+
+// NewReorgChecker creates a new reorg checker.
+func NewReorgChecker(
+	rpcPool map[sdk.ChainID]*pool.Pool,
+	wormchainRpcPool map[sdk.ChainID]*pool.Pool,
+	repository reorgRepository,
+	metrics metrics.Metrics,
+	p2pNetwork string,
+	notionalCache *notional.NotionalCache,
+	cfg ReorgCheckerConfig,
+	logger *zap.Logger,
+) *ReorgChecker {
+	// the hostname is the pod name, unique across the replicas.
+	owner, err := os.Hostname()
+	if err != nil {
+		owner = fmt.Sprintf("tx-tracker-%d", time.Now().UnixNano())
+	}
+	return &ReorgChecker{
+		rpcPool:          rpcPool,
+		wormchainRpcPool: wormchainRpcPool,
+		repository:       repository,
+		metrics:          metrics,
+		p2pNetwork:       p2pNetwork,
+		notionalCache:    notionalCache,
+		cfg:              cfg,
+		owner:            owner,
+		logger:           logger.With(zap.String("module", "ReorgChecker")),
+	}
+}
+
+// Start runs the checker periodically until the context is cancelled.
+func (c *ReorgChecker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.check(ctx)
+			}
+		}
+	}()
+}
+
+func (c *ReorgChecker) check(ctx context.Context) {
+	// the lock outlives a few ticks, so a check that takes longer than the interval keeps it.
+	leader, err := c.repository.AcquireLock(ctx, reorgCheckerLock, c.owner, 3*c.cfg.Interval)
+	if err != nil {
+		c.logger.Error("failed to acquire reorg checker lock", zap.Error(err))
+		return
+	}
+	if !leader {
+		c.logger.Debug("reorg checker lock held by another replica")
+		return
+	}
+
+	for chainID := range c.rpcPool {
+		if !domain.Chains.IsEVM(chainID) {
+			continue
+		}
+		if err := c.checkChain(ctx, chainID); err != nil {
+			c.logger.Error("failed to check reorgs", zap.Stringer("chainId", chainID), zap.Error(err))
+		}
+		if err := c.retryChain(ctx, chainID); err != nil {
+			c.logger.Error("failed to retry reorged txs", zap.Stringer("chainId", chainID), zap.Error(err))
+		}
+	}
+}
+
+// checkChain checks the source transactions of a chain whose timestamp is within the finality window.
+func (c *ReorgChecker) checkChain(ctx context.Context, chainID sdk.ChainID) error {
+	from := time.Now().Add(-domain.Chains.FinalityTime(chainID))
+
+	var afterID string
+	for {
+		txs, err := c.repository.FindRecentOriginTxs(ctx, chainID, from, afterID, c.cfg.PageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range txs {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.checkTx(ctx, tx)
+		}
+
+		if int64(len(txs)) < c.cfg.PageSize {
+			return nil
+		}
+		afterID = txs[len(txs)-1].ID
+	}
+}
+
+// retryChain looks up again the source transactions of a chain dropped by a reorg, since a dropped
+// transaction is usually included again in a later block.
+func (c *ReorgChecker) retryChain(ctx context.Context, chainID sdk.ChainID) error {
+	var afterID string
+	for {
+		txs, err := c.repository.FindReorgedOriginTxs(ctx, chainID, c.cfg.MaxRetries, afterID, c.cfg.PageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range txs {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.retryTx(ctx, tx)
+		}
+
+		if int64(len(txs)) < c.cfg.PageSize {
+			return nil
+		}
+		afterID = txs[len(txs)-1].ID
+	}
+}
+
+func (c *ReorgChecker) checkTx(ctx context.Context, tx RecentOriginTx) {
+	logger := c.logger.With(
+		zap.String("vaaId", tx.ID),
+		zap.Stringer("chainId", tx.ChainID),
+		zap.String("txHash", tx.TxHash))
+
+	txDetail, err := chains.FetchTx(ctx, c.rpcPool, c.wormchainRpcPool, tx.ChainID, tx.TxHash, tx.Timestamp,
+		c.p2pNetwork, c.metrics, logger, c.notionalCache)
+	c.metrics.IncOriginTxReorgChecked(uint16(tx.ChainID))
+
+	switch {
+	case errors.Is(err, chains.ErrTransactionNotFound):
+		c.checkNotFoundTx(ctx, tx, logger)
+	case err != nil:
+		logger.Debug("failed to fetch origin tx", zap.Error(err))
+	case txDetail.BlockHash == "" || txDetail.BlockHash == tx.BlockHash:
+		c.resetNotFound(ctx, tx, logger)
+	default:
+		if err := c.repository.UpdateOriginTxReorg(ctx, tx.ID, txDetail); err != nil {
+			logger.Error("failed to correct reorged origin tx", zap.Error(err))
+			return
+		}
+		c.metrics.IncOriginTxReorgCorrected(uint16(tx.ChainID))
+		logger.Warn("origin tx moved to another block by a reorg",
+			zap.String("oldBlockHash", tx.BlockHash),
+			zap.String("newBlockHash", txDetail.BlockHash),
+			zap.String("blockNumber", txDetail.BlockNumber))
+	}
+}
+
+// checkNotFoundTx decides whether a source transaction that was not found was dropped by a reorg.
+// A different block at the number of the transaction confirms the reorg. When the block can not be
+// compared, the transaction is dropped once it was not found by NotFoundThreshold consecutive checks,
+// so a lagging or inconsistent node does not drop it.
+func (c *ReorgChecker) checkNotFoundTx(ctx context.Context, tx RecentOriginTx, logger *zap.Logger) {
+	blockHash, err := chains.FetchBlockHash(ctx, c.rpcPool, tx.ChainID, tx.BlockNumber, c.metrics, logger)
+	switch {
+	case err == nil && blockHash == tx.BlockHash:
+		// the block of the transaction is still canonical, the node answered with stale data.
+		logger.Debug("origin tx not found but its block is canonical", zap.String("blockHash", tx.BlockHash))
+		c.resetNotFound(ctx, tx, logger)
+		return
+	case err == nil:
+		logger.Warn("origin tx block replaced by a reorg",
+			zap.String("oldBlockHash", tx.BlockHash),
+			zap.String("newBlockHash", blockHash),
+			zap.String("blockNumber", tx.BlockNumber))
+	default:
+		if !errors.Is(err, chains.ErrChainNotSupported) {
+			logger.Debug("failed to fetch origin tx block", zap.Error(err))
+		}
+		count, err := c.repository.IncOriginTxNotFound(ctx, tx.ID)
+		if err != nil {
+			logger.Error("failed to count origin tx not found", zap.Error(err))
+			return
+		}
+		if count < c.cfg.NotFoundThreshold {
+			logger.Debug("origin tx not found", zap.Int("count", count))
+			return
+		}
+	}
+
+	// the transaction is not included in the canonical chain anymore.
+	if err := c.repository.UpdateOriginTxReorg(ctx, tx.ID, nil); err != nil {
+		logger.Error("failed to mark origin tx as reorged", zap.Error(err))
+		return
+	}
+	c.metrics.IncOriginTxReorgDropped(uint16(tx.ChainID))
+	logger.Warn("origin tx dropped by a reorg", zap.String("blockHash", tx.BlockHash))
+}
+
+func (c *ReorgChecker) resetNotFound(ctx context.Context, tx RecentOriginTx, logger *zap.Logger) {
+	if tx.NotFoundCount == 0 {
+		return
+	}
+	if err := c.repository.ResetOriginTxNotFound(ctx, tx.ID); err != nil {
+		logger.Error("failed to reset origin tx not found count", zap.Error(err))
+	}
+}
+
+// retryTx looks up again a source transaction dropped by a reorg and stores its new block when it is found.
+func (c *ReorgChecker) retryTx(ctx context.Context, tx RecentOriginTx) {
+	logger := c.logger.With(
+		zap.String("vaaId", tx.ID),
+		zap.Stringer("chainId", tx.ChainID),
+		zap.String("txHash", tx.TxHash),
+		zap.Int("retries", tx.Retries))
+
+	txDetail, err := chains.FetchTx(ctx, c.rpcPool, c.wormchainRpcPool, tx.ChainID, tx.TxHash, tx.Timestamp,
+		c.p2pNetwork, c.metrics, logger, c.notionalCache)
+	if err != nil {
+		logger.Debug("failed to fetch reorged origin tx", zap.Error(err))
+		if err := c.repository.IncOriginTxReorgRetries(ctx, tx.ID); err != nil {
+			logger.Error("failed to count reorged origin tx retry", zap.Error(err))
+		}
+		if tx.Retries+1 >= c.cfg.MaxRetries {
+			logger.Error("reorged origin tx not found after the max retries")
+		}
+		return
+	}
+
+	if err := c.repository.RestoreOriginTxReorg(ctx, tx.ID, txDetail); err != nil {
+		logger.Error("failed to restore reorged origin tx", zap.Error(err))
+		return
+	}
+	c.metrics.IncOriginTxReorgRestored(uint16(tx.ChainID))
+	logger.Info("reorged origin tx found again",
+		zap.String("blockHash", txDetail.BlockHash),
+		zap.String("blockNumber", txDetail.BlockNumber))
+}
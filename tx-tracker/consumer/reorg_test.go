@@ -0,0 +1,438 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/pool"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/chains"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+const (
+	testTxID      = "2/000000000000000000000000000000000000000000000000000000000000dead/1"
+	testTxHash    = "0x00000000000000000000000000000000000000000000000000000000000000aa"
+	testBlock     = "0x10"
+	testBlockHash = "0x000000000000000000000000000000000000000000000000000000000000b001"
+	otherBlock    = "0x11"
+	otherHash     = "0x000000000000000000000000000000000000000000000000000000000000b002"
+)
+
+// fakeReorgTx is a source transaction stored in the fake repository.
+type fakeReorgTx struct {
+	RecentOriginTx
+	reorged bool
+}
+
+// fakeReorgRepository keeps the source transactions in memory.
+type fakeReorgRepository struct {
+	mu        sync.Mutex
+	lockOwner string
+	txs       map[string]*fakeReorgTx
+	// chains are the chains whose recent transactions were requested.
+	chains []sdk.ChainID
+}
+
+func newFakeReorgRepository(txs ...RecentOriginTx) *fakeReorgRepository {
+	r := &fakeReorgRepository{txs: make(map[string]*fakeReorgTx)}
+	for _, tx := range txs {
+		r.txs[tx.ID] = &fakeReorgTx{RecentOriginTx: tx}
+	}
+	return r
+}
+
+func (r *fakeReorgRepository) AcquireLock(_ context.Context, _, owner string, _ time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lockOwner != "" && r.lockOwner != owner {
+		return false, nil
+	}
+	r.lockOwner = owner
+	return true, nil
+}
+
+func (r *fakeReorgRepository) find(chainID sdk.ChainID, afterID string, limit int64, match func(*fakeReorgTx) bool) []RecentOriginTx {
+	var txs []RecentOriginTx
+	for _, tx := range r.txs {
+		if tx.ChainID == chainID && tx.ID > afterID && match(tx) {
+			txs = append(txs, tx.RecentOriginTx)
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].ID < txs[j].ID })
+	if int64(len(txs)) > limit {
+		txs = txs[:limit]
+	}
+	return txs
+}
+
+func (r *fakeReorgRepository) FindRecentOriginTxs(_ context.Context, chainID sdk.ChainID, _ time.Time, afterID string, limit int64) ([]RecentOriginTx, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chains = append(r.chains, chainID)
+	return r.find(chainID, afterID, limit, func(tx *fakeReorgTx) bool { return !tx.reorged }), nil
+}
+
+func (r *fakeReorgRepository) FindReorgedOriginTxs(_ context.Context, chainID sdk.ChainID, maxRetries int, afterID string, limit int64) ([]RecentOriginTx, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.find(chainID, afterID, limit, func(tx *fakeReorgTx) bool { return tx.reorged && tx.Retries < maxRetries }), nil
+}
+
+func (r *fakeReorgRepository) IncOriginTxNotFound(_ context.Context, id string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txs[id].NotFoundCount++
+	return r.txs[id].NotFoundCount, nil
+}
+
+func (r *fakeReorgRepository) ResetOriginTxNotFound(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txs[id].NotFoundCount = 0
+	return nil
+}
+
+func (r *fakeReorgRepository) UpdateOriginTxReorg(_ context.Context, id string, txDetail *chains.TxDetail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx := r.txs[id]
+	tx.NotFoundCount = 0
+	if txDetail == nil {
+		tx.reorged = true
+		tx.Retries = 0
+		return nil
+	}
+	tx.BlockNumber = txDetail.BlockNumber
+	tx.BlockHash = txDetail.BlockHash
+	return nil
+}
+
+func (r *fakeReorgRepository) RestoreOriginTxReorg(_ context.Context, id string, txDetail *chains.TxDetail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx := r.txs[id]
+	tx.reorged = false
+	tx.Retries = 0
+	tx.BlockNumber = txDetail.BlockNumber
+	tx.BlockHash = txDetail.BlockHash
+	return nil
+}
+
+func (r *fakeReorgRepository) IncOriginTxReorgRetries(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txs[id].Retries++
+	return nil
+}
+
+func (r *fakeReorgRepository) get(id string) fakeReorgTx {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.txs[id]
+}
+
+// fakeEvmNode is an evm json-rpc node that serves the receipts of the transactions and the hashes of the blocks.
+type fakeEvmNode struct {
+	mu sync.Mutex
+	// txs maps the hash of a transaction to the number and the hash of its block.
+	txs map[string][2]string
+	// blocks maps the number of a block to its hash.
+	blocks map[string]string
+	calls  int
+}
+
+func newFakeEvmNode(t *testing.T) (*fakeEvmNode, *pool.Pool) {
+	node := &fakeEvmNode{txs: make(map[string][2]string), blocks: make(map[string]string)}
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	return node, pool.NewPool([]pool.Config{{Id: server.URL, Description: "fake", RequestsPerMinute: 60000}})
+}
+
+func (n *fakeEvmNode) setTx(txHash, blockNumber, blockHash string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.txs[txHash] = [2]string{blockNumber, blockHash}
+	n.blocks[blockNumber] = blockHash
+}
+
+func (n *fakeEvmNode) dropTx(txHash string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.txs, txHash)
+}
+
+func (n *fakeEvmNode) setBlock(blockNumber, blockHash string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.blocks[blockNumber] = blockHash
+}
+
+func (n *fakeEvmNode) callCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls
+}
+
+func (n *fakeEvmNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params []any           `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.Lock()
+	n.calls++
+	var result any
+	switch req.Method {
+	case "eth_getTransactionReceipt":
+		if block, ok := n.txs[req.Params[0].(string)]; ok {
+			result = map[string]string{
+				"blockNumber": block[0],
+				"blockHash":   block[1],
+				"from":        "0x00000000000000000000000000000000000000ff",
+			}
+		}
+	case "eth_getBlockByNumber":
+		if hash, ok := n.blocks[req.Params[0].(string)]; ok {
+			result = map[string]string{"hash": hash}
+		}
+	}
+	n.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+func newTestReorgChecker(repository reorgRepository, rpcPool map[sdk.ChainID]*pool.Pool) *ReorgChecker {
+	return &ReorgChecker{
+		rpcPool:    rpcPool,
+		repository: repository,
+		metrics:    metrics.NewDummyMetrics(),
+		p2pNetwork: domain.P2pTestNet,
+		cfg: ReorgCheckerConfig{
+			Interval:          time.Minute,
+			PageSize:          2,
+			NotFoundThreshold: 3,
+			MaxRetries:        2,
+		},
+		owner:  "replica-1",
+		logger: zap.NewNop(),
+	}
+}
+
+func newTestOriginTx() RecentOriginTx {
+	now := time.Now()
+	return RecentOriginTx{
+		ID:          testTxID,
+		ChainID:     sdk.ChainIDEthereum,
+		TxHash:      testTxHash,
+		BlockNumber: testBlock,
+		BlockHash:   testBlockHash,
+		Timestamp:   &now,
+	}
+}
+
+func TestReorgChecker_CheckTx(t *testing.T) {
+	tcs := []struct {
+		name string
+		// notFoundCount is the number of previous checks that did not find the transaction.
+		notFoundCount int
+		setup         func(node *fakeEvmNode)
+		expected      fakeReorgTx
+	}{
+		{
+			name:          "canonical tx",
+			notFoundCount: 2,
+			setup:         func(node *fakeEvmNode) { node.setTx(testTxHash, testBlock, testBlockHash) },
+			expected:      fakeReorgTx{RecentOriginTx: RecentOriginTx{BlockNumber: testBlock, BlockHash: testBlockHash}},
+		},
+		{
+			name:     "tx moved to another block",
+			setup:    func(node *fakeEvmNode) { node.setTx(testTxHash, otherBlock, otherHash) },
+			expected: fakeReorgTx{RecentOriginTx: RecentOriginTx{BlockNumber: otherBlock, BlockHash: otherHash}},
+		},
+		{
+			name:     "tx not found and block replaced",
+			setup:    func(node *fakeEvmNode) { node.setBlock(testBlock, otherHash) },
+			expected: fakeReorgTx{RecentOriginTx: RecentOriginTx{BlockNumber: testBlock, BlockHash: testBlockHash}, reorged: true},
+		},
+		{
+			name:          "tx not found and block canonical",
+			notFoundCount: 2,
+			setup:         func(node *fakeEvmNode) { node.setBlock(testBlock, testBlockHash) },
+			expected:      fakeReorgTx{RecentOriginTx: RecentOriginTx{BlockNumber: testBlock, BlockHash: testBlockHash}},
+		},
+		{
+			name:     "tx and block not found below the threshold",
+			setup:    func(node *fakeEvmNode) {},
+			expected: fakeReorgTx{RecentOriginTx: RecentOriginTx{BlockNumber: testBlock, BlockHash: testBlockHash, NotFoundCount: 1}},
+		},
+		{
+			name:          "tx and block not found at the threshold",
+			notFoundCount: 2,
+			setup:         func(node *fakeEvmNode) {},
+			expected:      fakeReorgTx{RecentOriginTx: RecentOriginTx{BlockNumber: testBlock, BlockHash: testBlockHash}, reorged: true},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			node, rpcPool := newFakeEvmNode(t)
+			tc.setup(node)
+			tx := newTestOriginTx()
+			tx.NotFoundCount = tc.notFoundCount
+			repository := newFakeReorgRepository(tx)
+
+			checker := newTestReorgChecker(repository, map[sdk.ChainID]*pool.Pool{sdk.ChainIDEthereum: rpcPool})
+			require.NoError(t, checker.checkChain(context.Background(), sdk.ChainIDEthereum))
+
+			actual := repository.get(testTxID)
+			assert.Equal(t, tc.expected.BlockNumber, actual.BlockNumber)
+			assert.Equal(t, tc.expected.BlockHash, actual.BlockHash)
+			assert.Equal(t, tc.expected.NotFoundCount, actual.NotFoundCount)
+			assert.Equal(t, tc.expected.reorged, actual.reorged)
+		})
+	}
+}
+
+func TestReorgChecker_NotFoundThreshold(t *testing.T) {
+	ctx := context.Background()
+	node, rpcPool := newFakeEvmNode(t)
+	repository := newFakeReorgRepository(newTestOriginTx())
+	checker := newTestReorgChecker(repository, map[sdk.ChainID]*pool.Pool{sdk.ChainIDEthereum: rpcPool})
+
+	// neither the tx nor its block are found, as when the node lags behind.
+	for i := 1; i < checker.cfg.NotFoundThreshold; i++ {
+		checker.check(ctx)
+		assert.Equal(t, i, repository.get(testTxID).NotFoundCount)
+		assert.False(t, repository.get(testTxID).reorged)
+	}
+
+	// the count is reset once the tx is found again.
+	node.setTx(testTxHash, testBlock, testBlockHash)
+	checker.check(ctx)
+	assert.Equal(t, 0, repository.get(testTxID).NotFoundCount)
+
+	// the tx is only dropped after the threshold of consecutive checks.
+	node.dropTx(testTxHash)
+	node.setBlock(testBlock, "")
+	for i := 0; i < checker.cfg.NotFoundThreshold; i++ {
+		assert.False(t, repository.get(testTxID).reorged)
+		checker.check(ctx)
+	}
+	assert.True(t, repository.get(testTxID).reorged)
+}
+
+func TestReorgChecker_RetryDroppedTx(t *testing.T) {
+	ctx := context.Background()
+	node, rpcPool := newFakeEvmNode(t)
+	node.setBlock(testBlock, otherHash)
+	repository := newFakeReorgRepository(newTestOriginTx())
+	checker := newTestReorgChecker(repository, map[sdk.ChainID]*pool.Pool{sdk.ChainIDEthereum: rpcPool})
+
+	// the block of the tx was replaced, so the tx is dropped and looked up again in the same check.
+	checker.check(ctx)
+	tx := repository.get(testTxID)
+	assert.True(t, tx.reorged)
+	assert.Equal(t, 1, tx.Retries)
+
+	// the tx is included again in a later block.
+	node.setTx(testTxHash, otherBlock, "0x000000000000000000000000000000000000000000000000000000000000B003")
+	checker.check(ctx)
+	tx = repository.get(testTxID)
+	assert.False(t, tx.reorged)
+	assert.Equal(t, otherBlock, tx.BlockNumber)
+	assert.Equal(t, "0x000000000000000000000000000000000000000000000000000000000000b003", tx.BlockHash)
+
+	// the restored tx is checked again as a recent tx.
+	checker.check(ctx)
+	tx = repository.get(testTxID)
+	assert.False(t, tx.reorged)
+	assert.Equal(t, 0, tx.NotFoundCount)
+}
+
+func TestReorgChecker_MaxRetries(t *testing.T) {
+	ctx := context.Background()
+	node, rpcPool := newFakeEvmNode(t)
+	node.setBlock(testBlock, otherHash)
+	repository := newFakeReorgRepository(newTestOriginTx())
+	checker := newTestReorgChecker(repository, map[sdk.ChainID]*pool.Pool{sdk.ChainIDEthereum: rpcPool})
+
+	for i := 0; i < checker.cfg.MaxRetries+2; i++ {
+		checker.check(ctx)
+	}
+	tx := repository.get(testTxID)
+	assert.True(t, tx.reorged)
+	assert.Equal(t, checker.cfg.MaxRetries, tx.Retries)
+
+	// the tx is not looked up after the max retries.
+	calls := node.callCount()
+	require.NoError(t, checker.retryChain(ctx, sdk.ChainIDEthereum))
+	assert.Equal(t, calls, node.callCount())
+}
+
+func TestReorgChecker_Paging(t *testing.T) {
+	node, rpcPool := newFakeEvmNode(t)
+	node.setBlock(testBlock, otherHash)
+
+	var txs []RecentOriginTx
+	for _, id := range []string{"2/emitter/1", "2/emitter/2", "2/emitter/3", "2/emitter/4", "2/emitter/5"} {
+		tx := newTestOriginTx()
+		tx.ID = id
+		txs = append(txs, tx)
+	}
+	repository := newFakeReorgRepository(txs...)
+	checker := newTestReorgChecker(repository, map[sdk.ChainID]*pool.Pool{sdk.ChainIDEthereum: rpcPool})
+
+	// all the pages are checked.
+	require.NoError(t, checker.checkChain(context.Background(), sdk.ChainIDEthereum))
+	for _, tx := range txs {
+		assert.True(t, repository.get(tx.ID).reorged, tx.ID)
+	}
+}
+
+func TestReorgChecker_Lock(t *testing.T) {
+	node, rpcPool := newFakeEvmNode(t)
+	node.setTx(testTxHash, testBlock, testBlockHash)
+	repository := newFakeReorgRepository(newTestOriginTx())
+	repository.lockOwner = "replica-2"
+	checker := newTestReorgChecker(repository, map[sdk.ChainID]*pool.Pool{sdk.ChainIDEthereum: rpcPool})
+
+	// the replica that does not hold the lock does not check the txs.
+	checker.check(context.Background())
+	assert.Empty(t, repository.chains)
+	assert.Equal(t, 0, node.callCount())
+
+	repository.lockOwner = ""
+	checker.check(context.Background())
+	assert.Equal(t, []sdk.ChainID{sdk.ChainIDEthereum}, repository.chains)
+	assert.Equal(t, 1, node.callCount())
+}
+
+func TestReorgChecker_OnlyEvmChains(t *testing.T) {
+	_, rpcPool := newFakeEvmNode(t)
+	repository := newFakeReorgRepository()
+	checker := newTestReorgChecker(repository, map[sdk.ChainID]*pool.Pool{
+		sdk.ChainIDEthereum: rpcPool,
+		sdk.ChainIDSolana:   rpcPool,
+		sdk.ChainIDAptos:    rpcPool,
+	})
+
+	checker.check(context.Background())
+	assert.Equal(t, []sdk.ChainID{sdk.ChainIDEthereum}, repository.chains)
+}
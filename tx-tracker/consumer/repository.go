@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole-explorer/common/repository"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/chains"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.mongodb.org/mongo-driver/bson"
@@ -51,6 +52,7 @@ type Repository struct {
 	globalTransactions *mongo.Collection
 	vaas               *mongo.Collection
 	vaaIdTxHash        *mongo.Collection
+	*repository.LockRepository
 }
 
 // New creates a new repository.
@@ -61,6 +63,7 @@ func NewRepository(logger *zap.Logger, db *mongo.Database) *Repository {
 		globalTransactions: db.Collection("globalTransactions"),
 		vaas:               db.Collection("vaas"),
 		vaaIdTxHash:        db.Collection("vaaIdTxHash"),
+		LockRepository:     repository.NewLockRepository(db),
 	}
 
 	return &r
@@ -111,6 +114,10 @@ func (r *Repository) UpsertOriginTx(ctx context.Context, params *UpsertOriginTxP
 		if params.TxDetail.FeeDetail != nil {
 			fields = append(fields, primitive.E{Key: "feeDetail", Value: params.TxDetail.FeeDetail})
 		}
+		if params.TxDetail.BlockHash != "" {
+			fields = append(fields, primitive.E{Key: "blockNumber", Value: params.TxDetail.BlockNumber})
+			fields = append(fields, primitive.E{Key: "blockHash", Value: params.TxDetail.BlockHash})
+		}
 	}
 
 	if params.Timestamp != nil {
@@ -222,6 +229,294 @@ func (r *Repository) GetTargetTx(ctx context.Context, vaaId string) (*TargetTxUp
 	}
 }
 
+// TxSide is the field of a globalTransactions document that holds a transaction.
+type TxSide string
+
+const (
+	TxSideOrigin      TxSide = "originTx"
+	TxSideDestination TxSide = "destinationTx"
+)
+
+// hashField returns the field that holds the hash of the transaction.
+func (s TxSide) hashField() string {
+	if s == TxSideOrigin {
+		return string(s) + ".nativeTxHash"
+	}
+	return string(s) + ".txHash"
+}
+
+// TxWithoutFee is a transaction whose fee was not calculated.
+type TxWithoutFee struct {
+	ID        string
+	ChainID   sdk.ChainID
+	TxHash    string
+	Timestamp *time.Time
+}
+
+// FindTxsWithoutFee returns the transactions of a side without feeDetail, with a timestamp in the range [from, to),
+// on one of the given chains. The transactions are sorted by id and the page starts after the given id.
+func (r *Repository) FindTxsWithoutFee(ctx context.Context, side TxSide, chainIDs []sdk.ChainID, from, to time.Time, afterID string, limit int64) ([]TxWithoutFee, error) {
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$gt", Value: afterID}}},
+		{Key: string(side) + ".chainId", Value: bson.D{{Key: "$in", Value: chainIDs}}},
+		{Key: string(side) + ".timestamp", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}},
+		{Key: string(side) + ".feeDetail", Value: nil},
+		{Key: side.hashField(), Value: bson.D{{Key: "$nin", Value: bson.A{nil, ""}}}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.D{{Key: string(side), Value: 1}})
+
+	cur, err := r.globalTransactions.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transactions without fee: %w", err)
+	}
+	type txDoc struct {
+		ChainID      sdk.ChainID `bson:"chainId"`
+		TxHash       string      `bson:"txHash"`
+		NativeTxHash string      `bson:"nativeTxHash"`
+		Timestamp    *time.Time  `bson:"timestamp"`
+	}
+	var docs []struct {
+		ID          string `bson:"_id"`
+		Origin      *txDoc `bson:"originTx"`
+		Destination *txDoc `bson:"destinationTx"`
+	}
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions without fee: %w", err)
+	}
+
+	txs := make([]TxWithoutFee, 0, len(docs))
+	for _, doc := range docs {
+		tx := doc.Destination
+		if side == TxSideOrigin {
+			tx = doc.Origin
+		}
+		if tx == nil {
+			continue
+		}
+		txHash := tx.TxHash
+		if side == TxSideOrigin {
+			txHash = tx.NativeTxHash
+		}
+		txs = append(txs, TxWithoutFee{ID: doc.ID, ChainID: tx.ChainID, TxHash: txHash, Timestamp: tx.Timestamp})
+	}
+	return txs, nil
+}
+
+// UpdateFeeDetail sets the feeDetail of the transaction of a side.
+func (r *Repository) UpdateFeeDetail(ctx context.Context, id string, side TxSide, feeDetail *FeeDetail) error {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: string(side) + ".feeDetail", Value: feeDetail},
+		{Key: string(side) + ".updatedAt", Value: time.Now()},
+	}}}
+	if _, err := r.globalTransactions.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to update fee detail: %w", err)
+	}
+	return nil
+}
+
+// RecentOriginTx is a source transaction with the block that included it when it was indexed.
+type RecentOriginTx struct {
+	ID          string
+	ChainID     sdk.ChainID
+	TxHash      string
+	BlockNumber string
+	BlockHash   string
+	Timestamp   *time.Time
+	// NotFoundCount is the number of consecutive checks that did not find the transaction.
+	NotFoundCount int
+	// Retries is the number of times a reorged transaction was looked up again.
+	Retries int
+}
+
+// FindRecentOriginTxs returns the source transactions of a chain with a known block and a timestamp after the given time.
+// The transactions are sorted by id and the page starts after the given id.
+func (r *Repository) FindRecentOriginTxs(ctx context.Context, chainID sdk.ChainID, from time.Time, afterID string, limit int64) ([]RecentOriginTx, error) {
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$gt", Value: afterID}}},
+		{Key: "originTx.chainId", Value: chainID},
+		{Key: "originTx.timestamp", Value: bson.D{{Key: "$gte", Value: from}}},
+		{Key: "originTx.blockHash", Value: bson.D{{Key: "$nin", Value: bson.A{nil, ""}}}},
+		{Key: "originTx.reorged", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+	txs, err := r.findOriginTxs(ctx, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recent origin txs: %w", err)
+	}
+	return txs, nil
+}
+
+// FindReorgedOriginTxs returns the source transactions of a chain dropped by a reorg that are not indexed again
+// and were looked up less than maxRetries times. The transactions are sorted by id and the page starts after the given id.
+func (r *Repository) FindReorgedOriginTxs(ctx context.Context, chainID sdk.ChainID, maxRetries int, afterID string, limit int64) ([]RecentOriginTx, error) {
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$gt", Value: afterID}}},
+		{Key: "originTx.chainId", Value: chainID},
+		{Key: "originTx.reorged", Value: true},
+		{Key: "originTx.processed", Value: false},
+		{Key: "originTx.reorgRetries", Value: bson.D{{Key: "$lt", Value: maxRetries}}},
+	}
+	txs, err := r.findOriginTxs(ctx, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reorged origin txs: %w", err)
+	}
+	return txs, nil
+}
+
+func (r *Repository) findOriginTxs(ctx context.Context, filter bson.D, limit int64) ([]RecentOriginTx, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.D{{Key: "originTx", Value: 1}})
+
+	cur, err := r.globalTransactions.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID       string `bson:"_id"`
+		OriginTx struct {
+			ChainID            sdk.ChainID `bson:"chainId"`
+			NativeTxHash       string      `bson:"nativeTxHash"`
+			BlockNumber        string      `bson:"blockNumber"`
+			BlockHash          string      `bson:"blockHash"`
+			Timestamp          *time.Time  `bson:"timestamp"`
+			ReorgNotFoundCount int         `bson:"reorgNotFoundCount"`
+			ReorgRetries       int         `bson:"reorgRetries"`
+		} `bson:"originTx"`
+	}
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	txs := make([]RecentOriginTx, 0, len(docs))
+	for _, doc := range docs {
+		txs = append(txs, RecentOriginTx{
+			ID:            doc.ID,
+			ChainID:       doc.OriginTx.ChainID,
+			TxHash:        doc.OriginTx.NativeTxHash,
+			BlockNumber:   doc.OriginTx.BlockNumber,
+			BlockHash:     doc.OriginTx.BlockHash,
+			Timestamp:     doc.OriginTx.Timestamp,
+			NotFoundCount: doc.OriginTx.ReorgNotFoundCount,
+			Retries:       doc.OriginTx.ReorgRetries,
+		})
+	}
+	return txs, nil
+}
+
+// IncOriginTxNotFound increments the number of consecutive checks that did not find a source transaction
+// and returns the new number.
+func (r *Repository) IncOriginTxNotFound(ctx context.Context, id string) (int, error) {
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "originTx.reorgNotFoundCount", Value: 1}}}}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.D{{Key: "originTx.reorgNotFoundCount", Value: 1}})
+
+	var doc struct {
+		OriginTx struct {
+			ReorgNotFoundCount int `bson:"reorgNotFoundCount"`
+		} `bson:"originTx"`
+	}
+	err := r.globalTransactions.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: id}}, update, opts).Decode(&doc)
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment origin tx not found count: %w", err)
+	}
+	return doc.OriginTx.ReorgNotFoundCount, nil
+}
+
+// ResetOriginTxNotFound clears the number of consecutive checks that did not find a source transaction.
+func (r *Repository) ResetOriginTxNotFound(ctx context.Context, id string) error {
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "originTx.reorgNotFoundCount", Value: ""}}}}
+	if _, err := r.globalTransactions.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to reset origin tx not found count: %w", err)
+	}
+	return nil
+}
+
+// UpdateOriginTxReorg updates a source transaction whose block changed after a reorg.
+// When the transaction is no longer found, the document is marked as reorged and unprocessed,
+// and the reorg checker looks the transaction up again with FindReorgedOriginTxs.
+func (r *Repository) UpdateOriginTxReorg(ctx context.Context, id string, txDetail *chains.TxDetail) error {
+	now := time.Now()
+
+	var fields bson.D
+	if txDetail == nil {
+		fields = bson.D{
+			{Key: "originTx.reorged", Value: true},
+			{Key: "originTx.processed", Value: false},
+			{Key: "originTx.reorgRetries", Value: 0},
+			{Key: "originTx.updatedAt", Value: now},
+		}
+	} else {
+		fields = bson.D{
+			{Key: "originTx.from", Value: txDetail.From},
+			{Key: "originTx.blockNumber", Value: txDetail.BlockNumber},
+			{Key: "originTx.blockHash", Value: txDetail.BlockHash},
+			{Key: "originTx.updatedAt", Value: now},
+		}
+		if txDetail.FeeDetail != nil {
+			fields = append(fields, primitive.E{Key: "originTx.feeDetail", Value: txDetail.FeeDetail})
+		}
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: fields},
+		{Key: "$unset", Value: bson.D{{Key: "originTx.reorgNotFoundCount", Value: ""}}},
+		{Key: "$inc", Value: bson.D{{Key: "originTx.reorgCount", Value: 1}}},
+		{Key: "$push", Value: createChangesDoc("reorg-checker", "originTxReorg", &now)},
+	}
+	if _, err := r.globalTransactions.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to update reorged origin tx: %w", err)
+	}
+	return nil
+}
+
+// RestoreOriginTxReorg stores the block of a source transaction dropped by a reorg that was found again,
+// and marks it as processed.
+func (r *Repository) RestoreOriginTxReorg(ctx context.Context, id string, txDetail *chains.TxDetail) error {
+	now := time.Now()
+
+	fields := bson.D{
+		{Key: "originTx.nativeTxHash", Value: txDetail.NativeTxHash},
+		{Key: "originTx.from", Value: txDetail.From},
+		{Key: "originTx.blockNumber", Value: txDetail.BlockNumber},
+		{Key: "originTx.blockHash", Value: txDetail.BlockHash},
+		{Key: "originTx.processed", Value: true},
+		{Key: "originTx.updatedAt", Value: now},
+	}
+	if txDetail.Attribute != nil {
+		fields = append(fields, primitive.E{Key: "originTx.attribute", Value: txDetail.Attribute})
+	}
+	if txDetail.FeeDetail != nil {
+		fields = append(fields, primitive.E{Key: "originTx.feeDetail", Value: txDetail.FeeDetail})
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: fields},
+		{Key: "$unset", Value: bson.D{
+			{Key: "originTx.reorged", Value: ""},
+			{Key: "originTx.reorgRetries", Value: ""},
+		}},
+		{Key: "$push", Value: createChangesDoc("reorg-checker", "originTxReorgRestored", &now)},
+	}
+	if _, err := r.globalTransactions.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to restore reorged origin tx: %w", err)
+	}
+	return nil
+}
+
+// IncOriginTxReorgRetries increments the number of times a reorged source transaction was looked up again.
+func (r *Repository) IncOriginTxReorgRetries(ctx context.Context, id string) error {
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "originTx.reorgRetries", Value: 1}}}}
+	if _, err := r.globalTransactions.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to increment reorged origin tx retries: %w", err)
+	}
+	return nil
+}
+
 // CountDocumentsByTimeRange returns the number of documents that match the given time range.
 func (r *Repository) CountDocumentsByVaas(
 	ctx context.Context,
@@ -0,0 +1,67 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	commonRepo "github.com/wormhole-foundation/wormhole-explorer/common/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestAcquireLock(t *testing.T) {
+	m := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tcs := []struct {
+		name        string
+		response    bson.D
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			name:     "lock acquired",
+			response: mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			expected: true,
+		},
+		{
+			name: "lock held by another owner",
+			// the filter does not match the lock of another owner, so the upsert collides with its id.
+			response: mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key error"}),
+			expected: false,
+		},
+		{
+			name:        "write error",
+			response:    mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 121, Message: "Document failed validation"}),
+			expectedErr: true,
+		},
+		{
+			name:        "command error",
+			response:    mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 13, Name: "Unauthorized", Message: "not authorized"}),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		m.Run(tc.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tc.response)
+			repository := &Repository{logger: zap.NewNop(), LockRepository: commonRepo.NewLockRepository(mt.DB)}
+
+			acquired, err := repository.AcquireLock(context.Background(), reorgCheckerLock, "replica-1", time.Minute)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, acquired)
+
+			// the lock is upserted, so the first replica creates it.
+			updates := mt.GetStartedEvent().Command.Lookup("updates").Array()
+			update, err := updates.IndexErr(0)
+			assert.NoError(t, err)
+			assert.True(t, update.Value().Document().Lookup("upsert").Boolean())
+		})
+	}
+}
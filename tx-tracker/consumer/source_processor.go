@@ -119,6 +119,10 @@ func ProcessSourceTx(
 	// Get transaction details from the emitter blockchain
 	txDetail, err = chains.FetchTx(ctx, rpcPool, wormchainRpcPool, params.ChainId, params.TxHash, params.Timestamp, p2pNetwork, params.Metrics, logger, notionalCache)
 	if err != nil {
+		// If disableDBUpsert is set to true, the unprocessed source transaction is not stored either.
+		if params.DisableDBUpsert {
+			return nil, err
+		}
 		errHandleFetchTx := handleFetchTxError(ctx, logger, repository, params, err)
 		if errHandleFetchTx == nil {
 			params.Metrics.IncStoreUnprocessedOriginTx(uint16(params.ChainId))
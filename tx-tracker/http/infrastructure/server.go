@@ -33,6 +33,7 @@ func NewServer(logger *zap.Logger, port string, pprofEnabled bool, vaaController
 
 	api.Post("/vaa/process", vaaController.Process)
 	api.Post("/vaa/tx-hash", vaaController.CreateTxHash)
+	api.Post("/vaa/source-tx", vaaController.SourceTx)
 
 	return &Server{
 		app:    app,
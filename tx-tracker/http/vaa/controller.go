@@ -2,11 +2,13 @@ package vaa
 
 import (
 	"encoding/hex"
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	"github.com/wormhole-foundation/wormhole-explorer/common/pool"
 	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
+	"github.com/wormhole-foundation/wormhole-explorer/txtracker/chains"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/consumer"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -154,3 +156,61 @@ func (c *Controller) CreateTxHash(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(TxHashResponse{NativeTxHash: result.NativeTxHash})
 }
+
+// SourceTx fetches the source transaction of a vaa from its chain by the given tx hash,
+// without reading or writing the stored vaas and source transactions.
+// It returns 404 when the transaction is not found in the chain.
+func (c *Controller) SourceTx(ctx *fiber.Ctx) error {
+
+	var payload SourceTxRequest
+
+	if err := ctx.BodyParser(&payload); err != nil {
+		return err
+	}
+
+	if payload.TxHash == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "tx hash is empty"})
+	}
+
+	vaaID := strings.Split(payload.ID, "/")
+	if len(vaaID) != 3 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid vaa id"})
+	}
+
+	chainIDStr, emitter, sequenceStr := vaaID[0], vaaID[1], vaaID[2]
+	chainIDUint, err := strconv.ParseUint(chainIDStr, 10, 16)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "chain id is not a number", "details": err.Error()})
+	}
+	chainID := sdk.ChainID(chainIDUint)
+	if !domain.ChainIdIsValid(chainID) {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid chain id"})
+	}
+
+	c.logger.Info("Fetching source tx from endpoint", zap.String("id", payload.ID), zap.String("txHash", payload.TxHash))
+
+	p := &consumer.ProcessSourceTxParams{
+		TrackID:         "controller-source-tx",
+		Source:          "controller",
+		Timestamp:       payload.Timestamp,
+		VaaId:           payload.ID,
+		ChainId:         chainID,
+		Emitter:         emitter,
+		Sequence:        sequenceStr,
+		TxHash:          payload.TxHash,
+		IsVaaSigned:     false,
+		Metrics:         c.metrics,
+		Overwrite:       true,
+		DisableDBUpsert: true,
+	}
+
+	result, err := consumer.ProcessSourceTx(ctx.Context(), c.logger, c.rpcPool, c.wormchainRpcPool, c.repository, p, c.p2pNetwork, c.notionalCache)
+	if errors.Is(err, chains.ErrTransactionNotFound) {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "transaction not found"})
+	}
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(TxHashResponse{NativeTxHash: result.NativeTxHash})
+}
@@ -1,5 +1,7 @@
 package vaa
 
+import "time"
+
 // ProcessVaaRequest request a vaa to process.
 type ProcessVaaRequest struct {
 	ID string `json:"id"`
@@ -11,6 +13,13 @@ type TxHashRequest struct {
 	TxHash string `json:"txHash"`
 }
 
+// SourceTxRequest request the source transaction of a vaa by tx hash.
+type SourceTxRequest struct {
+	ID        string     `json:"id"`
+	TxHash    string     `json:"txHash"`
+	Timestamp *time.Time `json:"timestamp"`
+}
+
 // ProcessVaaResponse response from processing a vaa.
 type TxHashResponse struct {
 	NativeTxHash string `json:"nativeTxHash"`
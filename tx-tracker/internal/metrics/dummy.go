@@ -51,3 +51,18 @@ func (d *DummyMetrics) IncWormchainUnknown(srcChannel string, dstChannel string)
 
 // VaaProcessingDuration increments the duration of VAA processing.
 func (m *DummyMetrics) VaaProcessingDuration(chain string, start *time.Time) {}
+
+// IncOriginTxReorgChecked is a dummy implementation of IncOriginTxReorgChecked.
+func (d *DummyMetrics) IncOriginTxReorgChecked(chainID uint16) {}
+
+// IncOriginTxReorgCorrected is a dummy implementation of IncOriginTxReorgCorrected.
+func (d *DummyMetrics) IncOriginTxReorgCorrected(chainID uint16) {}
+
+// IncOriginTxReorgDropped is a dummy implementation of IncOriginTxReorgDropped.
+func (d *DummyMetrics) IncOriginTxReorgDropped(chainID uint16) {}
+
+// IncOriginTxReorgRestored is a dummy implementation of IncOriginTxReorgRestored.
+func (d *DummyMetrics) IncOriginTxReorgRestored(chainID uint16) {}
+
+// SetStreamLag is a dummy implementation of SetStreamLag.
+func (d *DummyMetrics) SetStreamLag(stream string, lag time.Duration, deadLetters int64) {}
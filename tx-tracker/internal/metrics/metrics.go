@@ -19,4 +19,9 @@ type Metrics interface {
 	IncVaaFailed(chainID uint16, retry uint8)
 	IncWormchainUnknown(srcChannel string, dstChannel string)
 	VaaProcessingDuration(chain string, start *time.Time)
+	IncOriginTxReorgChecked(chainID uint16)
+	IncOriginTxReorgCorrected(chainID uint16)
+	IncOriginTxReorgDropped(chainID uint16)
+	IncOriginTxReorgRestored(chainID uint16)
+	SetStreamLag(stream string, lag time.Duration, deadLetters int64)
 }
@@ -18,6 +18,9 @@ type PrometheusMetrics struct {
 	vaaProcessed             *prometheus.CounterVec
 	wormchainUnknown         *prometheus.CounterVec
 	vaaProcessingDuration    *prometheus.HistogramVec
+	originTxReorg            *prometheus.CounterVec
+	streamLag                *prometheus.GaugeVec
+	streamDeadLetters        *prometheus.GaugeVec
 }
 
 // NewPrometheusMetrics returns a new instance of PrometheusMetrics.
@@ -71,6 +74,24 @@ func NewPrometheusMetrics(environment string) *PrometheusMetrics {
 		},
 		[]string{"chain"},
 	)
+	originTxReorg := promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "origin_tx_reorg",
+			Help:        "Total number of origin tx checked for reorgs by chain",
+			ConstLabels: constLabels,
+		}, []string{"chain", "status"})
+	streamLag := promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "redis_stream_lag_seconds",
+			Help:        "Age of the oldest message of the redis stream not acknowledged by the consumer group",
+			ConstLabels: constLabels,
+		}, []string{"stream"})
+	streamDeadLetters := promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "redis_stream_dead_letters",
+			Help:        "Number of messages of the dead letter stream of the redis stream",
+			ConstLabels: constLabels,
+		}, []string{"stream"})
 	return &PrometheusMetrics{
 		vaaTxTrackerCount:        vaaTxTrackerCount,
 		vaaProcesedDuration:      vaaProcesedDuration,
@@ -79,6 +100,9 @@ func NewPrometheusMetrics(environment string) *PrometheusMetrics {
 		vaaProcessed:             vaaProcessed,
 		wormchainUnknown:         wormchainUnknown,
 		vaaProcessingDuration:    vaaProcessingDuration,
+		originTxReorg:            originTxReorg,
+		streamLag:                streamLag,
+		streamDeadLetters:        streamDeadLetters,
 	}
 }
 
@@ -163,3 +187,33 @@ func (p *PrometheusMetrics) VaaProcessingDuration(chain string, start *time.Time
 	elapsed := float64(time.Since(*start).Nanoseconds()) / 1e9
 	p.vaaProcessingDuration.WithLabelValues(chain).Observe(elapsed)
 }
+
+// IncOriginTxReorgChecked increments the number of origin tx whose block was checked.
+func (m *PrometheusMetrics) IncOriginTxReorgChecked(chainID uint16) {
+	chain := vaa.ChainID(chainID).String()
+	m.originTxReorg.WithLabelValues(chain, "checked").Inc()
+}
+
+// IncOriginTxReorgCorrected increments the number of origin tx corrected after a reorg.
+func (m *PrometheusMetrics) IncOriginTxReorgCorrected(chainID uint16) {
+	chain := vaa.ChainID(chainID).String()
+	m.originTxReorg.WithLabelValues(chain, "corrected").Inc()
+}
+
+// IncOriginTxReorgDropped increments the number of origin tx dropped by a reorg.
+func (m *PrometheusMetrics) IncOriginTxReorgDropped(chainID uint16) {
+	chain := vaa.ChainID(chainID).String()
+	m.originTxReorg.WithLabelValues(chain, "dropped").Inc()
+}
+
+// IncOriginTxReorgRestored increments the number of origin tx dropped by a reorg and found again.
+func (m *PrometheusMetrics) IncOriginTxReorgRestored(chainID uint16) {
+	chain := vaa.ChainID(chainID).String()
+	m.originTxReorg.WithLabelValues(chain, "restored").Inc()
+}
+
+// SetStreamLag sets the lag of the consumer group of a redis stream and the number of its dead letters.
+func (m *PrometheusMetrics) SetStreamLag(stream string, lag time.Duration, deadLetters int64) {
+	m.streamLag.WithLabelValues(stream).Set(lag.Seconds())
+	m.streamDeadLetters.WithLabelValues(stream).Set(float64(deadLetters))
+}
@@ -9,10 +9,16 @@ import (
 
 	"go.uber.org/zap"
 
+	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	sqs_client "github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = telemetry.Tracer("github.com/wormhole-foundation/wormhole-explorer/txtracker/queue")
+
 // SQSOption represents a VAA queue in SQS option function.
 type SQSOption func(*SQS)
 
@@ -98,9 +104,15 @@ func (q *SQS) Consume(ctx context.Context) <-chan ConsumerMessage {
 				q.metrics.IncVaaConsumedQueue(event.ChainID.String(), event.Source)
 
 				retry, _ := strconv.Atoi(msg.Attributes["ApproximateReceiveCount"])
+				// continue the trace of the producer of the message.
+				msgCtx, span := tracer.Start(sqsEvent.MessageAttributes.Extract(ctx), "tx-tracker.vaa.consume",
+					trace.WithSpanKind(trace.SpanKindConsumer),
+					trace.WithAttributes(telemetry.TrackIDKey.String(event.TrackID), telemetry.VaaIDKey.String(event.ID)))
+
 				q.wg.Add(1)
 				q.ch <- &sqsConsumerMessage{
 					id:            msg.ReceiptHandle,
+					msg:           msg,
 					data:          event,
 					wg:            &q.wg,
 					logger:        q.logger,
@@ -109,7 +121,8 @@ func (q *SQS) Consume(ctx context.Context) <-chan ConsumerMessage {
 					sentTimestamp: sqs_client.GetSentTimestamp(msg),
 					retry:         uint8(retry),
 					metrics:       q.metrics,
-					ctx:           ctx,
+					ctx:           msgCtx,
+					span:          span,
 				}
 			}
 			q.wg.Wait()
@@ -129,12 +142,14 @@ type sqsConsumerMessage struct {
 	consumer      *sqs_client.Consumer
 	wg            *sync.WaitGroup
 	id            *string
+	msg           aws_sqs_types.Message
 	logger        *zap.Logger
 	expiredAt     time.Time
 	sentTimestamp *time.Time
 	retry         uint8
 	metrics       metrics.Metrics
 	ctx           context.Context
+	span          trace.Span
 }
 
 func (m *sqsConsumerMessage) Data() *Event {
@@ -151,11 +166,26 @@ func (m *sqsConsumerMessage) Done() {
 		)
 	}
 	m.metrics.IncVaaProcessed(uint16(m.data.ChainID), m.retry)
+	m.span.End()
 	m.wg.Done()
 }
 
-func (m *sqsConsumerMessage) Failed() {
+// Failed leaves the message in the queue to be received again, or moves it to the dead-letter queue with
+// the error in the LastError attribute when this is its last receive allowed by the redrive policy.
+func (m *sqsConsumerMessage) Failed(err error) {
+	var lastErr string
+	if err != nil {
+		lastErr = err.Error()
+	}
+	moved, errMove := m.consumer.Failed(m.ctx, m.msg, lastErr)
+	if errMove != nil {
+		m.logger.Error("Error moving message to the dead-letter queue", zap.String("vaaId", m.data.ID), zap.Error(errMove))
+	} else if moved {
+		m.logger.Warn("Message moved to the dead-letter queue", zap.String("vaaId", m.data.ID), zap.Error(err))
+	}
 	m.metrics.IncVaaFailed(uint16(m.data.ChainID), m.retry)
+	m.span.SetStatus(codes.Error, "message processing failed")
+	m.span.End()
 	m.wg.Done()
 }
 
@@ -170,3 +200,7 @@ func (m *sqsConsumerMessage) Retry() uint8 {
 func (m *sqsConsumerMessage) SentTimestamp() *time.Time {
 	return m.sentTimestamp
 }
+
+func (m *sqsConsumerMessage) Context() context.Context {
+	return m.ctx
+}